@@ -1,6 +1,9 @@
 package api
 
 import (
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	internalmcp "github.com/0xJacky/Nginx-UI/internal/mcp"
+	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/gin-gonic/gin"
 )
@@ -9,6 +12,22 @@ func CurrentUser(c *gin.Context) *model.User {
 	return c.MustGet("user").(*model.User)
 }
 
+// CurrentUserID returns the user acting in the request. Service tokens act on
+// behalf of the user who created them.
+func CurrentUserID(c *gin.Context) uint64 {
+	if value, ok := c.Get(internalmcp.ServiceTokenPrincipalKey); ok {
+		if principal, valid := value.(*internalmcp.ServiceTokenPrincipal); valid {
+			return principal.CreatorID
+		}
+	}
+	if u, ok := c.Get("user"); ok {
+		if currentUser, valid := u.(*model.User); valid {
+			return currentUser.ID
+		}
+	}
+	return 0
+}
+
 func SetSSEHeaders(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
@@ -16,3 +35,18 @@ func SetSSEHeaders(c *gin.Context) {
 	// https://stackoverflow.com/questions/27898622/server-sent-events-stopped-work-after-enabling-ssl-on-proxy/27960243#27960243
 	c.Header("X-Accel-Buffering", "no")
 }
+
+// CommitAuthor returns the author recorded in the configuration history for
+// changes made by the request. Cluster nodes replay edits made elsewhere, so
+// they are recorded as a cluster sync rather than as the init user they act as.
+func CommitAuthor(c *gin.Context) githistory.Author {
+	if _, ok := c.Get(nodeauth.GinPrincipalKey); ok {
+		return githistory.ClusterSyncAuthor
+	}
+	if u, ok := c.Get("user"); ok {
+		if currentUser, valid := u.(*model.User); valid {
+			return githistory.UserAuthor(currentUser)
+		}
+	}
+	return githistory.SystemAuthor
+}
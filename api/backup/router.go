@@ -2,14 +2,17 @@ package backup
 
 import (
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
 func InitRouter(r *gin.RouterGroup) {
 	// A backup archive carries the crypto secret and every stored credential,
 	// and a restore can rewrite app.ini wholesale. Both stay closed in demo mode.
-	r.GET("/backup", middleware.AuthRequired(), middleware.RequireSecureSession(), middleware.RejectInDemo(), CreateBackup)
-	r.POST("/restore", middleware.AuthRequired(), middleware.RequireSecureSession(), middleware.RejectInDemo(), middleware.EncryptedForm(), RestoreBackup)
+	r.GET("/backup", middleware.AuthRequired(), middleware.RequirePermission(rbac.ResourceBackup),
+		middleware.RequireSecureSession(), middleware.RejectInDemo(), CreateBackup)
+	r.POST("/restore", middleware.AuthRequired(), middleware.RequirePermission(rbac.ResourceBackup),
+		middleware.RequireSecureSession(), middleware.RejectInDemo(), middleware.EncryptedForm(), RestoreBackup)
 }
 
 func InitSetupRouter(r *gin.RouterGroup) {
@@ -0,0 +1,23 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/tlsscan"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCertInventory returns the chains served by the TLS endpoints of the
+// configuration, scanning them first when they were never scanned.
+func GetCertInventory(c *gin.Context) {
+	report := tlsscan.LastReport()
+	if report == nil {
+		report = tlsscan.Scan(c.Request.Context())
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ScanCertInventory scans the TLS endpoints of the configuration right away.
+func ScanCertInventory(c *gin.Context) {
+	c.JSON(http.StatusOK, tlsscan.Scan(c.Request.Context()))
+}
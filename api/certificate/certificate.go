@@ -288,7 +288,9 @@ func persistCertificateFingerprint(certModel *model.Cert) {
 }
 
 func cleanupSelfSignedCertFiles(certModel *model.Cert) {
-	if certModel.AutoCert != model.AutoCertSelfSigned {
+	// Revoked certificates of a private CA keep their config, but not their
+	// auto cert mode.
+	if certModel.AutoCert != model.AutoCertSelfSigned && certModel.PrivateCAConfig == nil {
 		return
 	}
 
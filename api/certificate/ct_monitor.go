@@ -0,0 +1,38 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/ctmonitor"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+)
+
+func GetCTLogEntries(c *gin.Context) {
+	cosy.Core[model.CTLogEntry](c).
+		SetFussy("domain", "common_name", "issuer_name").
+		SetEqual("known", "acknowledged", "unexpected_issuer", "cert_id").
+		PagingList()
+}
+
+// AcknowledgeCTLogEntry marks a certificate found in the CT logs as expected.
+func AcknowledgeCTLogEntry(c *gin.Context) {
+	logEntry, err := ctmonitor.Acknowledge(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, logEntry)
+}
+
+// CheckCTLogs searches the CT logs for the managed domains right away.
+func CheckCTLogs(c *gin.Context) {
+	result, err := ctmonitor.Run(c.Request.Context())
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,88 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/cert"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+)
+
+// GetCertDeployHooks returns the deploy hooks of a certificate.
+func GetCertDeployHooks(c *gin.Context) {
+	q := query.Cert
+	certModel, err := q.FirstByID(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deploy_hooks": nonNilDeployHooks(certModel.DeployHooks),
+	})
+}
+
+// SetCertDeployHooks replaces the deploy hooks of a certificate.
+func SetCertDeployHooks(c *gin.Context) {
+	var json struct {
+		DeployHooks []model.CertDeployHook `json:"deploy_hooks" binding:"dive"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+	if err := cert.ValidateDeployHooks(json.DeployHooks); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	q := query.Cert
+	certModel, err := q.FirstByID(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	_, err = q.Where(q.ID.Eq(certModel.ID)).Select(q.DeployHooks).
+		Updates(&model.Cert{DeployHooks: json.DeployHooks})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deploy_hooks": nonNilDeployHooks(json.DeployHooks),
+	})
+}
+
+func nonNilDeployHooks(hooks []model.CertDeployHook) []model.CertDeployHook {
+	if hooks == nil {
+		return []model.CertDeployHook{}
+	}
+	return hooks
+}
+
+// RunCertDeployHooks runs the post-issuance deploy hooks of a certificate
+// right away, logging into the certificate like an issuance does.
+func RunCertDeployHooks(c *gin.Context) {
+	q := query.Cert
+	certModel, err := q.FirstByID(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	log := cert.NewLogger()
+	log.SetCertModel(certModel)
+	err = cert.RunDeployHooks(c.Request.Context(), certModel, model.CertDeployHookStagePost, log)
+	log.Close()
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
@@ -0,0 +1,29 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/ocspcheck"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+)
+
+// CheckCertOCSP queries the OCSP status of a certificate and the stapling of
+// the local listeners serving it right away.
+func CheckCertOCSP(c *gin.Context) {
+	q := query.Cert
+	certModel, err := q.FirstByID(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	if err := ocspcheck.CheckCert(c.Request.Context(), certModel); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Transformer(certModel))
+}
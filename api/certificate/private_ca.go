@@ -0,0 +1,158 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/cert"
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+)
+
+// CertAuthorityRequest is the payload for creating a private CA.
+type CertAuthorityRequest struct {
+	Name            string `json:"name" binding:"required"`
+	CommonName      string `json:"common_name" binding:"omitempty"`
+	Organization    string `json:"organization" binding:"omitempty"`
+	ParentID        uint64 `json:"parent_id" binding:"omitempty"`
+	KeyType         string `json:"key_type" binding:"omitempty,auto_cert_key_type"`
+	ValidityDays    int    `json:"validity_days" binding:"omitempty,min=1,max=7300"`
+	CRLValidityDays int    `json:"crl_validity_days" binding:"omitempty,min=1,max=365"`
+}
+
+// PrivateCACertRequest is the payload for issuing a certificate with a
+// private CA.
+type PrivateCACertRequest struct {
+	Name           string                   `json:"name" binding:"required"`
+	Usage          model.PrivateCACertUsage `json:"usage" binding:"required,oneof=server client"`
+	Domains        []string                 `json:"domains" binding:"omitempty"`
+	IPAddresses    []string                 `json:"ip_addresses" binding:"omitempty,dive,ip"`
+	EmailAddresses []string                 `json:"email_addresses" binding:"omitempty,dive,email"`
+	KeyType        string                   `json:"key_type" binding:"omitempty,auto_cert_key_type"`
+	ValidityDays   int                      `json:"validity_days" binding:"omitempty,min=1,max=3650"`
+	SyncNodeIds    []uint64                 `json:"sync_node_ids" binding:"omitempty"`
+}
+
+// RevokePrivateCACertRequest is the payload for revoking a certificate issued
+// by a private CA. Reason is an RFC 5280 reason code.
+type RevokePrivateCACertRequest struct {
+	Reason int `json:"reason" binding:"omitempty,min=0,max=10"`
+}
+
+// CertAuthorityResponse is a CA along with the files it publishes.
+type CertAuthorityResponse struct {
+	*model.CertAuthority
+	Paths cert.CertAuthorityPaths `json:"paths"`
+}
+
+func GetCertAuthorityList(c *gin.Context) {
+	cosy.Core[model.CertAuthority](c).
+		SetFussy("name", "common_name").
+		SetEqual("parent_id").
+		PagingList()
+}
+
+func GetCertAuthority(c *gin.Context) {
+	ca, err := cert.GetCertAuthority(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CertAuthorityResponse{CertAuthority: ca, Paths: cert.GetCertAuthorityPaths(ca.ID)})
+}
+
+func CreateCertAuthority(c *gin.Context) {
+	var req CertAuthorityRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	ca, err := cert.CreateCertAuthority(cert.CertAuthorityOptions{
+		Name:            req.Name,
+		CommonName:      req.CommonName,
+		Organization:    req.Organization,
+		ParentID:        req.ParentID,
+		KeyType:         certcrypto.KeyType(req.KeyType),
+		ValidityDays:    req.ValidityDays,
+		CRLValidityDays: req.CRLValidityDays,
+	})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CertAuthorityResponse{CertAuthority: ca, Paths: cert.GetCertAuthorityPaths(ca.ID)})
+}
+
+func DestroyCertAuthority(c *gin.Context) {
+	if err := cert.DeleteCertAuthority(cast.ToUint64(c.Param("id"))); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func GetCertAuthorityRevocations(c *gin.Context) {
+	r := query.CertRevocation
+	revocations, err := r.Where(r.CertAuthorityID.Eq(cast.ToUint64(c.Param("id")))).
+		Order(r.RevokedAt.Desc()).Find()
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": revocations})
+}
+
+// IssuePrivateCACert issues a server or client certificate with a private CA.
+func IssuePrivateCACert(c *gin.Context) {
+	var req PrivateCACertRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	certModel, err := cert.IssuePrivateCACert(cert.PrivateCACertOptions{
+		Name:            req.Name,
+		CertAuthorityID: cast.ToUint64(c.Param("id")),
+		Usage:           req.Usage,
+		DNSNames:        normalizeStringSlice(req.Domains),
+		IPAddresses:     normalizeStringSlice(req.IPAddresses),
+		EmailAddresses:  normalizeStringSlice(req.EmailAddresses),
+		KeyType:         certcrypto.KeyType(req.KeyType),
+		ValidityDays:    req.ValidityDays,
+		SyncNodeIds:     req.SyncNodeIds,
+	})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	if err = cert.SyncToRemoteServer(certModel); err != nil {
+		notification.Error("Sync Certificate Error", err.Error(), nil)
+	}
+
+	c.JSON(http.StatusOK, Transformer(certModel))
+}
+
+// RevokePrivateCACert adds a certificate to the CRL of the private CA that
+// issued it.
+func RevokePrivateCACert(c *gin.Context) {
+	var req RevokePrivateCACertRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	certModel, err := query.Cert.FirstByID(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if err = cert.RevokePrivateCACert(certModel, req.Reason); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Transformer(certModel))
+}
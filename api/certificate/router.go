@@ -2,6 +2,7 @@ package certificate
 
 import (
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,11 +17,30 @@ func InitDNSCredentialRouter(r *gin.RouterGroup) {
 	}
 }
 
+// InitCertificateRouter expects a group that is not proxied yet, so the deploy
+// hooks can check their extra permission before a remote node is reached.
 func InitCertificateRouter(r *gin.RouterGroup) {
+	// Deploy hooks run commands and write files on the host, so they also
+	// require unscoped terminal access.
+	hooks := r.Group("",
+		middleware.RequireUnscopedSubject(),
+		middleware.RequireWritePermission(rbac.ResourceTerminal),
+		middleware.Proxy(),
+		middleware.RequireSecureSession(),
+	)
+	{
+		hooks.GET("certs/:id/deploy_hooks", GetCertDeployHooks)
+		hooks.POST("certs/:id/deploy_hooks", middleware.RejectInDemo(), SetCertDeployHooks)
+		hooks.POST("certs/:id/deploy_hooks/run", middleware.RejectInDemo(), RunCertDeployHooks)
+	}
+
+	r = r.Group("", middleware.Proxy())
 	r.GET("certs", GetCertList)
 	r.GET("certs/:id", GetCert)
 	r.GET("certificate/dns_providers", GetDNSProvidersList)
 	r.GET("certificate/dns_provider/:code", GetDNSProvider)
+	r.GET("ct_log_entries", GetCTLogEntries)
+	r.GET("cert_inventory", GetCertInventory)
 	o := r.Group("", middleware.RequireSecureSession())
 	{
 		o.POST("certs", AddCert)
@@ -31,6 +51,25 @@ func InitCertificateRouter(r *gin.RouterGroup) {
 		o.PUT("cert_sync", SyncCertificate)
 		o.POST("self_signed_cert", GenerateSelfSignedCert)
 		o.POST("self_signed_cert/:id", ModifySelfSignedCert)
+		o.POST("certs/:id/private_ca_revoke", RevokePrivateCACert)
+		o.POST("ct_log_entries/:id/acknowledge", AcknowledgeCTLogEntry)
+		o.POST("cert_inventory/scan", ScanCertInventory)
+		// Queries the configured CT log source, which is an external service.
+		o.POST("ct_log_entries/check", middleware.RejectInDemo(), CheckCTLogs)
+		// Queries the OCSP responder of the issuer.
+		o.POST("certs/:id/ocsp_check", middleware.RejectInDemo(), CheckCertOCSP)
+	}
+}
+
+func InitCertAuthorityRouter(r *gin.RouterGroup) {
+	r.GET("cert_authorities", GetCertAuthorityList)
+	r.GET("cert_authorities/:id", GetCertAuthority)
+	r.GET("cert_authorities/:id/revocations", GetCertAuthorityRevocations)
+	o := r.Group("", middleware.RequireSecureSession())
+	{
+		o.POST("cert_authorities", CreateCertAuthority)
+		o.DELETE("cert_authorities/:id", DestroyCertAuthority)
+		o.POST("cert_authorities/:id/certs", IssuePrivateCACert)
 	}
 }
 
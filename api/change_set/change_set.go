@@ -0,0 +1,139 @@
+package change_set
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/changeset"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+// scopeToAuthor limits namespace scoped users to their own change sets, since
+// the items of other change sets may belong to namespaces they cannot see.
+func scopeToAuthor(c *gin.Context) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if !middleware.CurrentSubject(c).IsNamespaceScoped() {
+			return tx
+		}
+		return tx.Where("author_id = ?", api.CurrentUser(c).ID)
+	}
+}
+
+func respondError(c *gin.Context, err error) {
+	if errors.Is(err, rbac.ErrPermissionDenied) || errors.Is(err, rbac.ErrNamespaceDenied) {
+		c.AbortWithStatusJSON(http.StatusForbidden, err)
+		return
+	}
+	cosy.ErrHandler(c, err)
+}
+
+func GetChangeSets(c *gin.Context) {
+	cosy.Core[model.ChangeSet](c).
+		SetEqual("status", "author_id").
+		SetPreloads("Author", "Reviewer").
+		GormScope(scopeToAuthor(c)).
+		PagingList()
+}
+
+func GetChangeSet(c *gin.Context) {
+	cosy.Core[model.ChangeSet](c).
+		SetPreloads("Author", "Reviewer").
+		GormScope(scopeToAuthor(c)).
+		Get()
+}
+
+func CreateChangeSet(c *gin.Context) {
+	var json struct {
+		Title       string                  `json:"title" binding:"required,max=255"`
+		Description string                  `json:"description"`
+		Items       []changeset.ItemRequest `json:"items" binding:"required,min=1,dive"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	items, err := changeset.Prepare(json.Items)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if err := changeset.Authorize(middleware.CurrentSubject(c), items); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	changeSet, err := changeset.Stage(json.Title, json.Description, api.CurrentUser(c).ID, items)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changeSet)
+}
+
+type reviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+func ApproveChangeSet(c *gin.Context) {
+	var json reviewRequest
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	id := cast.ToUint64(c.Param("id"))
+	changeSet, err := changeset.Get(id)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if err := changeset.Authorize(middleware.CurrentSubject(c), changeSet.Items); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	changeSet, err = changeset.Approve(id, api.CurrentUser(c), json.Comment)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changeSet)
+}
+
+func RejectChangeSet(c *gin.Context) {
+	var json reviewRequest
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	id := cast.ToUint64(c.Param("id"))
+	changeSet, err := changeset.Get(id)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	currentUser := api.CurrentUser(c)
+	// Authors may always withdraw their own change set.
+	if changeSet.AuthorID != currentUser.ID {
+		if err := changeset.Authorize(middleware.CurrentSubject(c), changeSet.Items); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	changeSet, err = changeset.Reject(id, currentUser.ID, json.Comment)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changeSet)
+}
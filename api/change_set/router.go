@@ -0,0 +1,18 @@
+package change_set
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func InitRouter(r *gin.RouterGroup) {
+	r.GET("change_sets", GetChangeSets)
+	r.GET("change_sets/:id", GetChangeSet)
+
+	o := r.Group("", middleware.RequireInteractiveUser(), middleware.RequireSecureSession())
+	{
+		o.POST("change_sets", CreateChangeSet)
+		o.POST("change_sets/:id/approve", ApproveChangeSet)
+		o.POST("change_sets/:id/reject", RejectChangeSet)
+	}
+}
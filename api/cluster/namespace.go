@@ -85,6 +85,11 @@ func AddNamespace(c *gin.Context) {
 			"deploy_mode":           "omitempty,oneof=" + model.DeployModeLocal + " " + model.DeployModeRemote,
 			"sync_strategy":         "omitempty,oneof=" + model.SyncStrategyManual + " " + model.SyncStrategyAuto,
 			"sync_interval_minutes": "omitempty,min=0",
+			"dns_provisioning":      "omitempty,oneof=" + model.DNSProvisioningManual + " " + model.DNSProvisioningAuto + " " + model.DNSProvisioningOff,
+			"dns_target_ipv4":       "omitempty,ipv4",
+			"dns_target_ipv6":       "omitempty,ipv6",
+			"dns_target_cname":      "omitempty,fqdn",
+			"dns_delete_with_site":  "omitempty",
 		}).
 		Create()
 }
@@ -99,6 +104,11 @@ func ModifyNamespace(c *gin.Context) {
 			"deploy_mode":           "omitempty,oneof=" + model.DeployModeLocal + " " + model.DeployModeRemote,
 			"sync_strategy":         "omitempty,oneof=" + model.SyncStrategyManual + " " + model.SyncStrategyAuto,
 			"sync_interval_minutes": "omitempty,min=0",
+			"dns_provisioning":      "omitempty,oneof=" + model.DNSProvisioningManual + " " + model.DNSProvisioningAuto + " " + model.DNSProvisioningOff,
+			"dns_target_ipv4":       "omitempty,ipv4",
+			"dns_target_ipv6":       "omitempty,ipv6",
+			"dns_target_cname":      "omitempty,fqdn",
+			"dns_delete_with_site":  "omitempty",
 		}).
 		Modify()
 }
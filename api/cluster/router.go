@@ -5,7 +5,27 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// InitRouter expects a group that is not proxied yet, so the node secrets
+// can turn away namespace scoped subjects before a remote node is reached.
 func InitRouter(r *gin.RouterGroup) {
+	// Node secrets and credentials let the holder act as a node, which reaches
+	// every namespace.
+	secrets := r.Group("",
+		middleware.RequireUnscopedSubject(),
+		middleware.Proxy(),
+		middleware.RequireInteractiveUser(),
+		middleware.RequireSecureSession(),
+	)
+	{
+		secrets.GET("nodes/:id/secret", GetNodeSecret)
+		secrets.GET("nodes/:id/credentials", GetNodeCredentials)
+		secrets.POST("nodes/:id/credentials/rotate", RotateNodeCredential)
+		secrets.GET("node/credentials", ListControllerCredentials)
+		secrets.DELETE("node/credentials/:credential_id", RevokeControllerCredential)
+	}
+
+	r = r.Group("", middleware.Proxy())
+
 	// The node secret is the only thing a relationship starts from, so an
 	// upgrade arrives already authenticated by the middleware like any other
 	// node request.
@@ -24,11 +44,6 @@ func InitRouter(r *gin.RouterGroup) {
 		admin.POST("nodes/:id", EditNode)
 		admin.DELETE("nodes/:id", DeleteNode)
 		admin.POST("nodes/load_from_settings", LoadNodeFromSettings)
-		admin.GET("nodes/:id/secret", GetNodeSecret)
-		admin.GET("nodes/:id/credentials", GetNodeCredentials)
-		admin.POST("nodes/:id/credentials/rotate", RotateNodeCredential)
-		admin.GET("node/credentials", ListControllerCredentials)
-		admin.DELETE("node/credentials/:credential_id", RevokeControllerCredential)
 	}
 
 	r.POST("node/credentials/:credential_id/rotation", BeginControllerCredentialRotation)
@@ -5,7 +5,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/model"
@@ -77,6 +79,8 @@ func AddConfig(c *gin.Context) {
 		return
 	}
 
+	githistory.Record(api.CommitAuthor(c), "Add config "+name, path)
+
 	q := query.Config
 	_, err = q.Where(q.Filepath.Eq(path)).Delete()
 	if err != nil {
@@ -2,8 +2,8 @@ package config
 
 import (
 	"net/http"
-	"os"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/config"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/gin-gonic/gin"
@@ -31,47 +31,11 @@ func DeleteConfig(c *gin.Context) {
 		return
 	}
 
-	// Check if path is under nginx config directory
-	if err := config.ValidateDeletePath(fullPath); err != nil {
+	if err := config.Delete(fullPath, decodedName, json.SyncNodeIds, api.CommitAuthor(c)); err != nil {
 		cosy.ErrHandler(c, err)
 		return
 	}
 
-	// Check if trying to delete protected paths
-	if config.IsProtectedPath(fullPath, decodedName) {
-		cosy.ErrHandler(c, config.ErrCannotDeleteProtectedPath)
-		return
-	}
-
-	// Check if file/directory exists
-	stat, err := config.CheckFileExists(fullPath)
-	if err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	// Delete the file or directory
-	err = os.RemoveAll(fullPath)
-	if err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	// Clean up database records
-	if err := config.CleanupDatabaseRecords(fullPath, stat.IsDir()); err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	// Sync deletion to remote servers if configured
-	if len(json.SyncNodeIds) > 0 {
-		err = config.SyncDeleteOnRemoteServer(fullPath, json.SyncNodeIds)
-		if err != nil {
-			cosy.ErrHandler(c, err)
-			return
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "deleted successfully",
 	})
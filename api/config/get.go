@@ -38,6 +38,10 @@ func GetConfig(c *gin.Context) {
 		cosy.ErrHandler(c, err)
 		return
 	}
+	if !canReadKeyMaterial(c) && config.IsKeyMaterial(absPath, content) {
+		cosy.ErrHandler(c, config.ErrKeyMaterialDenied)
+		return
+	}
 
 	q := query.Config
 	cfg, err := q.Where(q.Filepath.Eq(absPath)).FirstOrInit()
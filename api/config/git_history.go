@@ -0,0 +1,85 @@
+package config
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/uozi-tech/cosy"
+)
+
+const defaultGitHistoryPerPage = 20
+
+// GetGitHistoryCommits lists the commits of the configuration history, either
+// for the whole tree or for the file given by path.
+func GetGitHistoryCommits(c *gin.Context) {
+	var query struct {
+		Path    string `form:"path"`
+		Page    int    `form:"page"`
+		PerPage int    `form:"per_page"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	page := lo.If(query.Page < 1, 1).Else(query.Page)
+	perPage := lo.If(query.PerPage <= 0, defaultGitHistoryPerPage).Else(query.PerPage)
+
+	commits, err := githistory.Log(query.Path, perPage, (page-1)*perPage)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": commits,
+	})
+}
+
+// GetGitHistoryCommitDiff returns the changes made by one commit.
+func GetGitHistoryCommitDiff(c *gin.Context) {
+	diff, err := githistory.Show(c.Param("hash"), c.Query("path"))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": diff,
+	})
+}
+
+// GetGitHistoryDiff compares two revisions, or a revision with the files on
+// disk when to is omitted.
+func GetGitHistoryDiff(c *gin.Context) {
+	diff, err := githistory.Diff(c.Query("from"), c.Query("to"), c.Query("path"))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": diff,
+	})
+}
+
+// RollbackGitHistory restores the whole configuration tree to a commit.
+func RollbackGitHistory(c *gin.Context) {
+	var json struct {
+		Revision string `json:"revision" binding:"required"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	commit, err := githistory.Rollback(json.Revision, api.CommitAuthor(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, commit)
+}
@@ -0,0 +1,14 @@
+package config
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// canReadKeyMaterial reports whether the caller may read certificates and
+// private keys through the config endpoints, which takes the permission to
+// manage certificates rather than only reading configs.
+func canReadKeyMaterial(c *gin.Context) bool {
+	return middleware.CurrentSubject(c).Can(rbac.ResourceCertificates, rbac.ActionWrite)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	"github.com/spf13/cast"
 	"github.com/uozi-tech/cosy"
 )
@@ -112,6 +113,11 @@ func GetConfigs(c *gin.Context) {
 		cosy.ErrHandler(c, err)
 		return
 	}
+	if !canReadKeyMaterial(c) {
+		configs = lo.Reject(configs, func(item config.Config, _ int) bool {
+			return config.IsKeyMaterial(filepath.Join(fullDir, item.Name), nil)
+		})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": configs,
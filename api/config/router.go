@@ -13,14 +13,18 @@ func InitRouter(r *gin.RouterGroup) {
 
 	o := r.Group("", middleware.RequireSecureSession())
 	{
-		o.POST("configs", AddConfig)
-		o.POST("config", EditConfig)
+		o.POST("configs", middleware.RequireChangeSet(), AddConfig)
+		o.POST("config", middleware.RequireChangeSet(), EditConfig)
 		o.POST("config_mkdir", Mkdir)
-		o.POST("config_rename", Rename)
-		o.POST("config_delete", DeleteConfig)
-		o.POST("config_sync_batch", SyncConfigBatch)
-		o.POST("config_sync_directory", SyncConfigDirectory)
+		o.POST("config_rename", middleware.RequireChangeSet(), Rename)
+		o.POST("config_delete", middleware.RequireChangeSet(), DeleteConfig)
+		o.POST("config_sync_batch", middleware.RequireChangeSet(), SyncConfigBatch)
+		o.POST("config_sync_directory", middleware.RequireChangeSet(), SyncConfigDirectory)
+		o.POST("config_git/rollback", middleware.RequireChangeSet(), RollbackGitHistory)
 	}
 
 	r.GET("config_histories", GetConfigHistory)
+	r.GET("config_git/commits", GetGitHistoryCommits)
+	r.GET("config_git/commits/:hash/diff", GetGitHistoryCommitDiff)
+	r.GET("config_git/diff", GetGitHistoryDiff)
 }
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/clustersync"
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/gin-gonic/gin"
@@ -32,6 +34,7 @@ func SyncConfigBatch(c *gin.Context) {
 	written := 0
 	skipped := 0
 	failures := make([]gin.H, 0)
+	syncedPaths := make([]string, 0, len(json.Files))
 
 	for _, file := range json.Files {
 		relativePath := filepath.ToSlash(filepath.Join(file.BaseDir, file.Name))
@@ -63,6 +66,7 @@ func SyncConfigBatch(c *gin.Context) {
 		}
 
 		written++
+		syncedPaths = append(syncedPaths, path)
 	}
 
 	if written > 0 {
@@ -70,6 +74,7 @@ func SyncConfigBatch(c *gin.Context) {
 			res.RespError(c)
 			return
 		}
+		githistory.Record(api.CommitAuthor(c), "Sync configuration directory", syncedPaths...)
 	}
 
 	// Only a batch where nothing could be applied is an error.
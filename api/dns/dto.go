@@ -54,30 +54,42 @@ func toRecordInput(req recordRequest) dns.RecordInput {
 }
 
 type ddnsConfigRequest struct {
-	Enabled                   bool     `json:"enabled"`
-	IntervalSeconds           int      `json:"interval_seconds" binding:"required,min=60"`
-	IPVersion                 string   `json:"ip_version"`
-	CleanupConflictingRecords bool     `json:"cleanup_conflicting_records"`
-	RecordIDs                 []string `json:"record_ids"`
+	Enabled                   bool                           `json:"enabled"`
+	IntervalSeconds           int                            `json:"interval_seconds" binding:"required,min=60"`
+	IPVersion                 string                         `json:"ip_version"`
+	CleanupConflictingRecords bool                           `json:"cleanup_conflicting_records"`
+	RecordIDs                 []string                       `json:"record_ids"`
+	Source                    *model.DDNSIPSource            `json:"source"`
+	TargetSources             map[string]*model.DDNSIPSource `json:"target_sources"`
+	WebhookEnabled            bool                           `json:"webhook_enabled"`
+	WaitPropagation           bool                           `json:"wait_propagation"`
 }
 
 type ddnsRecordTarget struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	ID     string              `json:"id"`
+	Name   string              `json:"name"`
+	Type   string              `json:"type"`
+	Source *model.DDNSIPSource `json:"source,omitempty"`
 }
 
 type ddnsConfigResponse struct {
-	Enabled                   bool               `json:"enabled"`
-	IntervalSeconds           int                `json:"interval_seconds"`
-	IPVersion                 string             `json:"ip_version"`
-	CleanupConflictingRecords bool               `json:"cleanup_conflicting_records"`
-	Targets                   []ddnsRecordTarget `json:"targets"`
-	DeletedRecords            []ddnsRecordTarget `json:"deleted_records,omitempty"`
-	LastIPv4                  string             `json:"last_ipv4,omitempty"`
-	LastIPv6                  string             `json:"last_ipv6,omitempty"`
-	LastRunAt                 string             `json:"last_run_at,omitempty"`
-	LastError                 string             `json:"last_error,omitempty"`
+	Enabled                   bool                `json:"enabled"`
+	IntervalSeconds           int                 `json:"interval_seconds"`
+	IPVersion                 string              `json:"ip_version"`
+	CleanupConflictingRecords bool                `json:"cleanup_conflicting_records"`
+	Targets                   []ddnsRecordTarget  `json:"targets"`
+	DeletedRecords            []ddnsRecordTarget  `json:"deleted_records,omitempty"`
+	LastIPv4                  string              `json:"last_ipv4,omitempty"`
+	LastIPv6                  string              `json:"last_ipv6,omitempty"`
+	LastRunAt                 string              `json:"last_run_at,omitempty"`
+	LastError                 string              `json:"last_error,omitempty"`
+	Source                    *model.DDNSIPSource `json:"source,omitempty"`
+	WebhookEnabled            bool                `json:"webhook_enabled"`
+	WebhookToken              string              `json:"webhook_token,omitempty"`
+	WebhookIPv4               string              `json:"webhook_ipv4,omitempty"`
+	WebhookIPv6               string              `json:"webhook_ipv6,omitempty"`
+	WebhookAt                 string              `json:"webhook_at,omitempty"`
+	WaitPropagation           bool                `json:"wait_propagation"`
 }
 
 func toDDNSResponse(cfg *model.DDNSConfig) ddnsConfigResponse {
@@ -103,16 +115,25 @@ func toDDNSResponse(cfg *model.DDNSConfig) ddnsConfigResponse {
 	resp.LastIPv4 = cfg.LastIPv4
 	resp.LastIPv6 = cfg.LastIPv6
 	resp.LastError = cfg.LastError
+	resp.Source = cfg.Source
+	resp.WebhookEnabled = cfg.WebhookToken != ""
+	resp.WebhookIPv4 = cfg.WebhookIPv4
+	resp.WebhookIPv6 = cfg.WebhookIPv6
+	resp.WaitPropagation = cfg.WaitPropagation
 
 	if cfg.LastRunAt != nil {
 		resp.LastRunAt = cfg.LastRunAt.Format(time.RFC3339)
 	}
+	if cfg.WebhookAt != nil {
+		resp.WebhookAt = cfg.WebhookAt.Format(time.RFC3339)
+	}
 
 	for _, target := range cfg.Targets {
 		resp.Targets = append(resp.Targets, ddnsRecordTarget{
-			ID:   target.ID,
-			Name: target.Name,
-			Type: target.Type,
+			ID:     target.ID,
+			Name:   target.Name,
+			Type:   target.Type,
+			Source: target.Source,
 		})
 	}
 
@@ -126,3 +147,38 @@ type ddnsDomainItem struct {
 	CredentialProvider string             `json:"credential_provider,omitempty"`
 	Config             ddnsConfigResponse `json:"config"`
 }
+
+type zoneExportQuery struct {
+	Download bool `form:"download"`
+}
+
+type zoneImportRequest struct {
+	Content       string `json:"content" binding:"required"`
+	DryRun        bool   `json:"dry_run"`
+	DeleteMissing bool   `json:"delete_missing"`
+}
+
+type zoneCopyRequest struct {
+	TargetCredentialID uint64 `json:"target_credential_id" binding:"required"`
+	DryRun             bool   `json:"dry_run"`
+	DeleteMissing      bool   `json:"delete_missing"`
+}
+
+type propagationQuery struct {
+	Name string `form:"name"`
+	Type string `form:"type" binding:"required"`
+	// Expected values may repeat the parameter or be separated by commas.
+	Expected []string `form:"expected"`
+	Wait     bool     `form:"wait"`
+	// Timeout is the number of seconds a wait may take.
+	Timeout int `form:"timeout" binding:"min=0,max=600"`
+}
+
+type ddnsWebhookRequest struct {
+	Token string `form:"token" json:"token"`
+	IPv4  string `form:"ipv4" json:"ipv4"`
+	IPv6  string `form:"ipv6" json:"ipv6"`
+	// MyIP is the dyndns2 style parameter understood by most routers; it may
+	// hold one address of each family separated by a comma.
+	MyIP string `form:"myip" json:"myip"`
+}
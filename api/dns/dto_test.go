@@ -72,3 +72,9 @@ func TestToDDNSResponseCleanupConflictingRecordsDefault(t *testing.T) {
 		require.True(t, resp.CleanupConflictingRecords)
 	})
 }
+
+func TestToDDNSResponseOmitsWebhookToken(t *testing.T) {
+	resp := toDDNSResponse(&model.DDNSConfig{WebhookToken: "secret"})
+	require.True(t, resp.WebhookEnabled)
+	require.Empty(t, resp.WebhookToken)
+}
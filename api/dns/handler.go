@@ -1,8 +1,10 @@
 package dns
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/lo"
@@ -155,6 +157,104 @@ func DeleteRecord(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ExportZone returns the records of a domain as a BIND zone file, either as
+// JSON or, with ?download=true, as a file attachment.
+func ExportZone(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+	var params zoneExportQuery
+	_ = c.ShouldBindQuery(&params)
+
+	svc := dnsService.NewService()
+	zone, err := svc.ExportZone(c.Request.Context(), domainID)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	if params.Download {
+		c.Header("Content-Disposition", "attachment; filename="+zone.Domain+".zone")
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(zone.Content))
+		return
+	}
+
+	c.JSON(http.StatusOK, zone)
+}
+
+// ImportZone diffs a zone file against a domain and applies it unless the
+// request is a dry run.
+func ImportZone(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+	var payload zoneImportRequest
+	if !cosy.BindAndValid(c, &payload) {
+		return
+	}
+
+	svc := dnsService.NewService()
+	result, err := svc.ImportZone(c.Request.Context(), domainID, payload.Content, dnsService.ZoneSyncOptions{
+		DryRun:        payload.DryRun,
+		DeleteMissing: payload.DeleteMissing,
+	})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CopyZone copies the records of a domain to the same domain under another
+// credential, previewing the changes when the request is a dry run.
+func CopyZone(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+	var payload zoneCopyRequest
+	if !cosy.BindAndValid(c, &payload) {
+		return
+	}
+
+	svc := dnsService.NewService()
+	result, err := svc.CopyZone(c.Request.Context(), domainID, payload.TargetCredentialID, dnsService.ZoneSyncOptions{
+		DryRun:        payload.DryRun,
+		DeleteMissing: payload.DeleteMissing,
+	})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CheckPropagation reports what every authoritative nameserver of a domain
+// answers for a record, optionally waiting until it has propagated.
+func CheckPropagation(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+	var params propagationQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	var expected []string
+	for _, value := range params.Expected {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				expected = append(expected, item)
+			}
+		}
+	}
+
+	svc := dnsService.NewService()
+	report, err := svc.CheckPropagation(c.Request.Context(), domainID, params.Name, params.Type, expected,
+		params.Wait, time.Duration(params.Timeout)*time.Second)
+	if err != nil && report == nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	// A timed out wait still tells which nameservers lag behind.
+	c.JSON(http.StatusOK, report)
+}
+
 // GetDDNSConfig returns the DDNS configuration for a domain.
 func GetDDNSConfig(c *gin.Context) {
 	domainID := cast.ToUint64(c.Param("id"))
@@ -236,6 +336,10 @@ func UpdateDDNSConfig(c *gin.Context) {
 		IPVersion:                 payload.IPVersion,
 		CleanupConflictingRecords: payload.CleanupConflictingRecords,
 		RecordIDs:                 payload.RecordIDs,
+		Source:                    payload.Source,
+		TargetSources:             payload.TargetSources,
+		WebhookEnabled:            payload.WebhookEnabled,
+		WaitPropagation:           payload.WaitPropagation,
 	})
 	if err != nil {
 		cosy.ErrHandler(c, err)
@@ -256,6 +360,10 @@ func UpdateDDNSConfig(c *gin.Context) {
 	}
 
 	resp := toDDNSResponse(cfg)
+	// The token is only returned when it is issued, like on a rotation.
+	if result.WebhookTokenIssued {
+		resp.WebhookToken = cfg.WebhookToken
+	}
 	for _, deleted := range result.DeletedRecords {
 		resp.DeletedRecords = append(resp.DeletedRecords, ddnsRecordTarget{
 			ID:   deleted.ID,
@@ -285,6 +393,73 @@ func DeleteDDNSConfig(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RotateDDNSWebhookToken issues a new webhook token for a domain.
+func RotateDDNSWebhookToken(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+
+	svc := dnsService.NewService()
+	cfg, err := svc.RotateDDNSWebhookToken(c.Request.Context(), domainID)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	resp := toDDNSResponse(cfg)
+	resp.WebhookToken = cfg.WebhookToken
+	c.JSON(http.StatusOK, resp)
+}
+
+// DDNSWebhook lets a router push its address and trigger a DDNS update. The
+// token is taken from a bearer Authorization header or the token parameter;
+// without reported addresses the caller's address is used.
+func DDNSWebhook(c *gin.Context) {
+	domainID := cast.ToUint64(c.Param("id"))
+
+	var payload ddnsWebhookRequest
+	if err := c.ShouldBind(&payload); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	token := payload.Token
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	report := dnsService.DDNSWebhookReport{IPv4: payload.IPv4, IPv6: payload.IPv6}
+	addresses := strings.Split(payload.MyIP, ",")
+	if report.IPv4 == "" && report.IPv6 == "" && strings.TrimSpace(payload.MyIP) == "" {
+		addresses = []string{c.ClientIP()}
+	}
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		switch {
+		case address == "":
+		case strings.Contains(address, ":"):
+			report.IPv6 = lo.Ternary(report.IPv6 == "", address, report.IPv6)
+		default:
+			report.IPv4 = lo.Ternary(report.IPv4 == "", address, report.IPv4)
+		}
+	}
+
+	svc := dnsService.NewService()
+	if err := svc.TriggerDDNSWebhook(c.Request.Context(), domainID, strings.TrimSpace(token), report); err != nil {
+		if errors.Is(err, dnsService.ErrDDNSWebhookUnauthorized) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ipv4": report.IPv4,
+		"ipv6": report.IPv6,
+	})
+}
+
 func buildPagination(page, perPage int, total int64) model.Pagination {
 	page = lo.If(page < 1, 1).Else(page)
 	perPage = lo.If(perPage <= 0, 50).Else(perPage)
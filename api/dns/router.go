@@ -7,7 +7,13 @@ import (
 	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/alidns"
 	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/azuredns"
 	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/cloudflare"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/digitalocean"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/gandi"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/hetzner"
 	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/huaweicloud"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/powerdns"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/rfc2136"
+	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/route53"
 	_ "github.com/0xJacky/Nginx-UI/internal/dns/providers/tencentcloud"
 )
 
@@ -21,6 +27,8 @@ func InitRouter(r *gin.RouterGroup) {
 		group.GET("/domains/:id/record-lines", ListRecordLines)
 
 		group.GET("/domains/:id/ddns", GetDDNSConfig)
+		group.GET("/domains/:id/zone", ExportZone)
+		group.GET("/domains/:id/propagation", CheckPropagation)
 
 		group.GET("/ddns", ListDDNSConfig)
 
@@ -36,6 +44,16 @@ func InitRouter(r *gin.RouterGroup) {
 			o.DELETE("/domains/:id/records/:record_id", DeleteRecord)
 			o.PUT("/domains/:id/ddns", UpdateDDNSConfig)
 			o.DELETE("/domains/:id/ddns", DeleteDDNSConfig)
+			o.POST("/domains/:id/ddns/webhook-token", RotateDDNSWebhookToken)
+			o.POST("/domains/:id/zone/import", ImportZone)
+			o.POST("/domains/:id/zone/copy", CopyZone)
 		}
 	}
 }
+
+// InitPublicRouter registers the DDNS webhook, which routers call with the
+// per-domain token instead of a user session.
+func InitPublicRouter(r *gin.RouterGroup) {
+	r.GET("/dns/ddns/webhook/:id", DDNSWebhook)
+	r.POST("/dns/ddns/webhook/:id", DDNSWebhook)
+}
@@ -0,0 +1,62 @@
+package event
+
+import (
+	"errors"
+
+	"github.com/0xJacky/Nginx-UI/internal/event"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gorm"
+)
+
+// eventResources maps the events about a resource to it. Events not listed
+// carry no content, such as the processing flags.
+var eventResources = map[event.Type]rbac.Resource{
+	event.TypeAutoCertProcessing:     rbac.ResourceCertificates,
+	event.TypeNginxLogStatus:         rbac.ResourceLogs,
+	event.TypeNginxLogIndexReady:     rbac.ResourceLogs,
+	event.TypeNginxLogIndexProgress:  rbac.ResourceLogs,
+	event.TypeNginxLogIndexComplete:  rbac.ResourceLogs,
+	event.TypeNginxLogExportProgress: rbac.ResourceLogs,
+	event.TypeNotification:           rbac.ResourceNotifications,
+	event.TypeSiteDeployment:         rbac.ResourceSites,
+}
+
+// visible reports whether a message may be sent to a client of subject.
+func visible(subject *rbac.Subject, message WebSocketMessage) bool {
+	resource, ok := eventResources[event.Type(message.Event)]
+	if ok && !subject.Can(resource, rbac.ActionRead) {
+		return false
+	}
+	if !subject.IsNamespaceScoped() {
+		return true
+	}
+
+	switch data := message.Data.(type) {
+	case event.SiteDeploymentData:
+		return subject.CanAccessNamespace(siteNamespaceID(data.SiteName))
+	case *event.SiteDeploymentData:
+		return subject.CanAccessNamespace(siteNamespaceID(data.SiteName))
+	}
+	return true
+}
+
+// siteNamespaceID returns the namespace of a site, or 0 when it has none or
+// cannot be resolved, which scoped subjects never see.
+func siteNamespaceID(name string) uint64 {
+	path, err := site.ResolveAvailablePath(name)
+	if err != nil {
+		return 0
+	}
+	s := query.Site
+	record, err := s.Where(s.Path.Eq(path)).First()
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("Failed to resolve the namespace of site", name, err)
+		}
+		return 0
+	}
+	return record.NamespaceID
+}
@@ -0,0 +1,89 @@
+package event
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/event"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupFilterTest(t *testing.T) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Site{}); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	query.Use(db)
+	query.SetDefault(db)
+
+	confDir := t.TempDir()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	settings.NginxSettings.ConfigDir = confDir
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+	})
+
+	sites := []*model.Site{
+		{Path: filepath.Join(confDir, "sites-available", "a.conf"), NamespaceID: 1},
+		{Path: filepath.Join(confDir, "sites-available", "b.conf"), NamespaceID: 2},
+	}
+	if err := db.Create(sites).Error; err != nil {
+		t.Fatalf("failed to create sites: %v", err)
+	}
+}
+
+func TestVisibleFiltersByResource(t *testing.T) {
+	subject := rbac.ForScopes([]string{rbac.Permission(rbac.ResourceNotifications, rbac.ActionRead)}, nil)
+
+	cases := []struct {
+		event string
+		want  bool
+	}{
+		{string(event.TypeNotification), true},
+		{string(event.TypeProcessingStatus), true},
+		{string(event.TypeNginxLogIndexProgress), false},
+		{string(event.TypeAutoCertProcessing), false},
+		{string(event.TypeSiteDeployment), false},
+	}
+	for _, tc := range cases {
+		if got := visible(subject, WebSocketMessage{Event: tc.event}); got != tc.want {
+			t.Errorf("visible(%s) = %v, want %v", tc.event, got, tc.want)
+		}
+	}
+}
+
+func TestVisibleFiltersSiteDeploymentsByNamespace(t *testing.T) {
+	setupFilterTest(t)
+
+	scoped := rbac.ForScopes([]string{rbac.Permission(rbac.ResourceSites, rbac.ActionRead)}, []uint64{1})
+	message := func(site string) WebSocketMessage {
+		return WebSocketMessage{
+			Event: string(event.TypeSiteDeployment),
+			Data:  event.SiteDeploymentData{SiteName: site},
+		}
+	}
+
+	if !visible(scoped, message("a.conf")) {
+		t.Fatal("expected the deployment of a site in the namespace to be visible")
+	}
+	if visible(scoped, message("b.conf")) {
+		t.Fatal("expected the deployment of a site in another namespace to be hidden")
+	}
+	if visible(scoped, message("unknown.conf")) {
+		t.Fatal("expected the deployment of a site outside any namespace to be hidden")
+	}
+	if !visible(rbac.Admin(), message("b.conf")) {
+		t.Fatal("expected unscoped subjects to see every deployment")
+	}
+}
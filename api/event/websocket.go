@@ -10,6 +10,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/kernel"
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/uozi-tech/cosy/logger"
@@ -23,10 +24,11 @@ type WebSocketMessage struct {
 
 // Client represents a WebSocket client connection
 type Client struct {
-	conn   *websocket.Conn
-	send   chan WebSocketMessage
-	ctx    context.Context
-	cancel context.CancelFunc
+	conn    *websocket.Conn
+	send    chan WebSocketMessage
+	subject *rbac.Subject
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 // Hub maintains the set of active clients and broadcasts messages to them
@@ -99,6 +101,9 @@ func (h *Hub) run() {
 		case message := <-h.broadcast:
 			h.mutex.RLock()
 			for client := range h.clients {
+				if !visible(client.subject, message) {
+					continue
+				}
 				select {
 				case client.send <- message:
 				default:
@@ -151,10 +156,11 @@ func Bus(c *gin.Context) {
 	defer cancel()
 
 	client := &Client{
-		conn:   ws,
-		send:   make(chan WebSocketMessage, 1024), // Increased buffer size
-		ctx:    ctx,
-		cancel: cancel,
+		conn:    ws,
+		send:    make(chan WebSocketMessage, 1024), // Increased buffer size
+		subject: middleware.CurrentSubject(c),
+		ctx:     ctx,
+		cancel:  cancel,
 	}
 
 	hub := GetHub()
@@ -0,0 +1,145 @@
+package jails
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/jail"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"github.com/uozi-tech/cosy/map2struct"
+)
+
+var jailRules = gin.H{
+	"name":               "required",
+	"log_path":           "required",
+	"log_type":           "omitempty,oneof=access error",
+	"status_codes":       "omitempty",
+	"path_pattern":       "omitempty",
+	"user_agent_pattern": "omitempty",
+	"line_pattern":       "omitempty",
+	"max_retry":          "omitempty",
+	"find_time_seconds":  "omitempty",
+	"ban_time_seconds":   "omitempty",
+	"site_name":          "omitempty",
+	"ignore_ips":         "omitempty",
+	"enabled":            "omitempty",
+}
+
+// normalizeJail validates the jail as it will be stored. A partial update is
+// merged onto the stored jail first, and the defaults Normalize fills in are
+// persisted with the changed fields.
+func normalizeJail(ctx *cosy.Ctx[model.Jail]) {
+	j := ctx.OriginModel
+	if err := map2struct.WeakDecode(ctx.Payload, &j); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	if err := jail.Normalize(&j); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	ctx.Model = j
+	ctx.AddSelectedFields(jail.NormalizedColumns...)
+}
+
+// applyJail creates the deny list of the jail's scope, so it can be included
+// right away, and restarts the watchers with the new filter.
+func applyJail(ctx *cosy.Ctx[model.Jail]) {
+	if err := jail.Sync(ctx.Model.SiteName); err != nil {
+		logger.Errorf("Failed to sync deny list of jail %s: %v", ctx.Model.Name, err)
+	}
+	if err := jail.Restart(); err != nil {
+		ctx.AbortWithError(err)
+	}
+}
+
+func GetJails(c *gin.Context) {
+	cosy.Core[model.Jail](c).
+		SetFussy("name").
+		SetEqual("log_path", "site_name", "enabled").
+		PagingList()
+}
+
+func GetJail(c *gin.Context) {
+	cosy.Core[model.Jail](c).Get()
+}
+
+func CreateJail(c *gin.Context) {
+	cosy.Core[model.Jail](c).
+		SetValidRules(jailRules).
+		BeforeExecuteHook(normalizeJail).
+		ExecutedHook(applyJail).
+		Create()
+}
+
+func ModifyJail(c *gin.Context) {
+	rules := gin.H{}
+	for field, rule := range jailRules {
+		if rule == "required" {
+			rule = "omitempty"
+		}
+		rules[field] = rule
+	}
+	cosy.Core[model.Jail](c).
+		SetValidRules(rules).
+		BeforeExecuteHook(normalizeJail).
+		ExecutedHook(applyJail).
+		Modify()
+}
+
+// DestroyJail deletes a jail and lifts its bans.
+func DestroyJail(c *gin.Context) {
+	cosy.Core[model.Jail](c).
+		BeforeExecuteHook(func(ctx *cosy.Ctx[model.Jail]) {
+			if err := jail.RemoveJailBans(ctx.Model.ID); err != nil {
+				ctx.AbortWithError(err)
+			}
+		}).
+		ExecutedHook(func(ctx *cosy.Ctx[model.Jail]) {
+			if err := jail.Restart(); err != nil {
+				logger.Errorf("Failed to restart jails: %v", err)
+			}
+		}).
+		Destroy()
+}
+
+// GetJailInclude returns where the deny list of a jail is written and the
+// directive that applies it.
+func GetJailInclude(c *gin.Context) {
+	q := query.Jail
+	j, err := q.Where(q.ID.Eq(cast.ToUint64(c.Param("id")))).First()
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":      jail.DenyListPath(j.SiteName),
+		"directive": jail.IncludeDirective(j.SiteName),
+		// The global deny list is picked up through conf.d.
+		"automatic": j.SiteName == "",
+	})
+}
+
+// GetJailBans lists the active bans along with their evidence lines.
+func GetJailBans(c *gin.Context) {
+	cosy.Core[model.JailBan](c).
+		SetEqual("jail_id", "ip", "site_name").
+		SetPreloads("Jail").
+		GormScope(jail.ActiveBans).
+		PagingList()
+}
+
+// Unban lifts a ban and reloads nginx without it.
+func Unban(c *gin.Context) {
+	if err := jail.Unban(cast.ToUint64(c.Param("id"))); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
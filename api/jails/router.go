@@ -0,0 +1,16 @@
+package jails
+
+import "github.com/gin-gonic/gin"
+
+// InitRouter registers the jail and ban routes
+func InitRouter(r *gin.RouterGroup) {
+	r.GET("jails", GetJails)
+	r.GET("jails/:id", GetJail)
+	r.GET("jails/:id/include", GetJailInclude)
+	r.POST("jails", CreateJail)
+	r.POST("jails/:id", ModifyJail)
+	r.DELETE("jails/:id", DestroyJail)
+
+	r.GET("jail_bans", GetJailBans)
+	r.DELETE("jail_bans/:id", Unban)
+}
@@ -0,0 +1,55 @@
+package manifest
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/manifest"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+type manifestRequest struct {
+	Manifest manifest.Manifest `json:"manifest"`
+	Prune    bool              `json:"prune"`
+}
+
+func GetManifest(c *gin.Context) {
+	m, err := manifest.Export(c.Request.Context())
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
+
+func PlanManifest(c *gin.Context) {
+	var json manifestRequest
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	plan, err := manifest.BuildPlan(c.Request.Context(), &json.Manifest, json.Prune)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+func ApplyManifest(c *gin.Context) {
+	var json manifestRequest
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	plan, err := manifest.Apply(c.Request.Context(), &json.Manifest, json.Prune, api.CommitAuthor(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
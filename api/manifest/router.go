@@ -0,0 +1,16 @@
+package manifest
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func InitRouter(r *gin.RouterGroup) {
+	r.GET("manifest", GetManifest)
+	r.POST("manifest/plan", PlanManifest)
+
+	o := r.Group("", middleware.RequireSecureSession())
+	{
+		o.POST("manifest/apply", middleware.RequireChangeSet(), ApplyManifest)
+	}
+}
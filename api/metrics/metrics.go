@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// GetMetrics serves the scrape target. Prometheus authenticates with a
+// service token holding the metrics:read scope.
+func GetMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := metrics.Write(c.Request.Context(), &buf); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
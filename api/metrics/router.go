@@ -0,0 +1,7 @@
+package metrics
+
+import "github.com/gin-gonic/gin"
+
+func InitRouter(r gin.IRoutes) {
+	r.GET("/metrics", GetMetrics)
+}
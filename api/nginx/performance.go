@@ -3,6 +3,7 @@ package nginx
 import (
 	"net/http"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/performance"
 	"github.com/gin-gonic/gin"
 	"github.com/uozi-tech/cosy"
@@ -26,7 +27,7 @@ func UpdatePerformanceSettings(c *gin.Context) {
 		return
 	}
 
-	err := performance.UpdatePerfOpt(&perfOpt)
+	err := performance.UpdatePerfOpt(&perfOpt, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
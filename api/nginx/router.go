@@ -29,9 +29,10 @@ func InitRouter(r *gin.RouterGroup) {
 	{
 		o.POST("nginx/reload", Reload)
 		o.POST("nginx/restart", Restart)
-		// Enable or disable stub_status module
-		o.POST("nginx/stub_status", ToggleStubStatus)
-		o.POST("nginx/performance", UpdatePerformanceSettings)
+		// Enable or disable stub_status module. Both rewrite nginx.conf, which
+		// takes a change set while approval is enforced.
+		o.POST("nginx/stub_status", middleware.RequireChangeSet(), ToggleStubStatus)
+		o.POST("nginx/performance", middleware.RequireChangeSet(), UpdatePerformanceSettings)
 		o.POST("nginx/modules/refresh", RefreshModulesCache)
 	}
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log/analytics"
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/internal/savedsearch"
 	"github.com/gin-gonic/gin"
 	"github.com/uozi-tech/cosy"
 	"github.com/uozi-tech/cosy/logger"
@@ -39,23 +40,31 @@ type AnalyticsRequest struct {
 
 // AdvancedSearchRequest represents the request for advanced log search
 type AdvancedSearchRequest struct {
-	Query     string `json:"query" form:"query"`
-	LogPath   string `json:"log_path" form:"log_path"`
-	StartTime int64  `json:"start_time" form:"start_time"`
-	EndTime   int64  `json:"end_time" form:"end_time"`
-	IP        string `json:"ip" form:"ip"`
-	Method    string `json:"method" form:"method"`
-	Status    []int  `json:"status" form:"status"`
-	Path      string `json:"path" form:"path"`
-	UserAgent string `json:"user_agent" form:"user_agent"`
-	Referer   string `json:"referer" form:"referer"`
-	Browser   string `json:"browser" form:"browser"`
-	OS        string `json:"os" form:"os"`
-	Device    string `json:"device" form:"device"`
-	Limit     int    `json:"limit" form:"limit"`
-	Offset    int    `json:"offset" form:"offset"`
-	SortBy    string `json:"sort_by" form:"sort_by"`
-	SortOrder string `json:"sort_order" form:"sort_order"`
+	Query string `json:"query" form:"query"`
+	// Expression is a query language expression such as
+	// `status:>=500 AND NOT ip:10.0.0.0/8`
+	Expression string `json:"expression" form:"expression"`
+	LogPath    string `json:"log_path" form:"log_path"`
+	StartTime  int64  `json:"start_time" form:"start_time"`
+	EndTime    int64  `json:"end_time" form:"end_time"`
+	IP         string `json:"ip" form:"ip"`
+	Method     string `json:"method" form:"method"`
+	Status     []int  `json:"status" form:"status"`
+	Path       string `json:"path" form:"path"`
+	UserAgent  string `json:"user_agent" form:"user_agent"`
+	Referer    string `json:"referer" form:"referer"`
+	Browser    string `json:"browser" form:"browser"`
+	OS         string `json:"os" form:"os"`
+	Device     string `json:"device" form:"device"`
+	Limit      int    `json:"limit" form:"limit"`
+	Offset     int    `json:"offset" form:"offset"`
+	SortBy     string `json:"sort_by" form:"sort_by"`
+	SortOrder  string `json:"sort_order" form:"sort_order"`
+
+	// Custom log_format variables: filters by variable name, and variables
+	// whose value distribution should be returned as facets
+	Vars      map[string][]string `json:"vars"`
+	VarFacets []string            `json:"var_facets"`
 }
 
 // SummaryStats Structures to match the frontend's expectations for the search response
@@ -77,6 +86,8 @@ type AdvancedSearchResponseAPI struct {
 	Took    int64                    `json:"took"` // Milliseconds
 	Query   string                   `json:"query"`
 	Summary SummaryStats             `json:"summary"`
+	// VarFacets maps each requested variable to its value distribution
+	VarFacets map[string]*searcher.Facet `json:"var_facets,omitempty"`
 }
 
 // PreflightResponse represents the response for preflight query
@@ -190,6 +201,11 @@ func AdvancedSearchLogs(c *gin.Context) {
 		return
 	}
 
+	if err := savedsearch.ValidateExpression(req.Expression); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
 	searcherService := nginx_log.GetSearcher()
 	if searcherService == nil {
 		cosy.ErrHandler(c, nginx_log.ErrModernSearcherNotAvailable)
@@ -202,6 +218,116 @@ func AdvancedSearchLogs(c *gin.Context) {
 		return
 	}
 
+	searchReq, ok := buildSearchRequest(c, analyticsService, &req)
+	if !ok {
+		return
+	}
+	searchReq.UseCache = true
+	searchReq.Timeout = 60 * time.Second // Add timeout for large facet operations
+	searchReq.IncludeHighlighting = true
+	searchReq.IncludeFacets = true                       // Re-enable facets for accurate summary stats
+	searchReq.FacetFields = []string{"ip", "path_exact"} // For UV and Unique Pages
+	searchReq.FacetSize = 10000                          // Balanced: large enough for most cases, but not excessive
+	searchReq.IncludeStats = true                        // Traffic totals for the whole match set, not just this page
+	for _, name := range req.VarFacets {
+		searchReq.FacetFields = append(searchReq.FacetFields, searcher.VarField(name))
+	}
+
+	// Execute search with timeout
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Minute)
+	defer cancel()
+
+	result, err := searcherService.Search(ctx, searchReq)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	// --- Transform the searcher result to the API response structure ---
+
+	// 1. Extract entries from hits
+	entries := make([]map[string]interface{}, len(result.Hits))
+	for i, hit := range result.Hits {
+		entries[i] = hit.Fields
+	}
+
+	// 2. Calculate summary stats from the overall results using Counter for accuracy
+	pv := int(result.TotalHits)
+	var uv, uniquePages int
+	var facetUV, facetUniquePages int
+
+	// First get facet values as fallback
+	if result.Facets != nil {
+		if ipFacet, ok := result.Facets["ip"]; ok {
+			facetUV = ipFacet.Total // .Total on a facet gives the count of unique terms
+			uv = facetUV
+		}
+		if pathFacet, ok := result.Facets["path_exact"]; ok {
+			facetUniquePages = pathFacet.Total
+			uniquePages = facetUniquePages
+		}
+	}
+
+	// Override with Counter results for better accuracy
+	if analyticsService != nil {
+		// Get cardinality counts for UV (unique IPs)
+		if uvResult := getCardinalityCount(ctx, "ip", searchReq); uvResult > 0 {
+			uv = uvResult
+			logger.Debugf("🔢 Search endpoint - UV from Counter: %d (vs facet: %d)", uvResult, facetUV)
+		}
+
+		// Get cardinality counts for Unique Pages (unique paths)
+		if upResult := getCardinalityCount(ctx, "path_exact", searchReq); upResult > 0 {
+			uniquePages = upResult
+			logger.Debugf("🔢 Search endpoint - Unique Pages from Counter: %d (vs facet: %d)", upResult, facetUniquePages)
+		}
+	}
+
+	// Traffic totals come from the stats aggregation, which scans the whole
+	// match set (or a bounded prefix of it, in which case it says so) rather
+	// than extrapolating from the current page.
+	var totalTraffic int64
+	var avgTraffic float64
+	var trafficApproximate bool
+	if result.Stats != nil {
+		totalTraffic = result.Stats.TotalBytes
+		avgTraffic = result.Stats.AvgBytes
+		trafficApproximate = result.Stats.Approximate
+	}
+
+	summary := SummaryStats{
+		UV:                 uv,
+		PV:                 pv,
+		TotalTraffic:       totalTraffic,
+		UniquePages:        uniquePages,
+		AvgTrafficPerPV:    avgTraffic,
+		TrafficApproximate: trafficApproximate,
+	}
+
+	// 3. Assemble the final response
+	apiResponse := AdvancedSearchResponseAPI{
+		Entries: entries,
+		Total:   result.TotalHits,
+		Took:    result.Duration.Milliseconds(),
+		Query:   req.Query,
+		Summary: summary,
+	}
+	for _, name := range req.VarFacets {
+		if facet, ok := result.Facets[searcher.VarField(name)]; ok {
+			if apiResponse.VarFacets == nil {
+				apiResponse.VarFacets = make(map[string]*searcher.Facet, len(req.VarFacets))
+			}
+			apiResponse.VarFacets[name] = facet
+		}
+	}
+
+	c.JSON(http.StatusOK, apiResponse)
+}
+
+// buildSearchRequest resolves the log path of req and turns its filters into
+// a searcher request. On failure the error response is written and false is
+// returned.
+func buildSearchRequest(c *gin.Context, analyticsService analytics.Service, req *AdvancedSearchRequest) (*searcher.SearchRequest, bool) {
 	// Use default access log path if LogPath is empty
 	if req.LogPath == "" {
 		defaultLogPath := nginx.GetAccessLogPath()
@@ -215,24 +341,18 @@ func AdvancedSearchLogs(c *gin.Context) {
 	if req.LogPath != "" {
 		if err := analyticsService.ValidateLogPath(req.LogPath); err != nil {
 			cosy.ErrHandler(c, err)
-			return
+			return nil, false
 		}
 	}
 
 	// Build search request
 	searchReq := &searcher.SearchRequest{
-		Query:               req.Query,
-		Limit:               req.Limit,
-		Offset:              req.Offset,
-		SortBy:              req.SortBy,
-		SortOrder:           req.SortOrder,
-		UseCache:            true,
-		Timeout:             60 * time.Second, // Add timeout for large facet operations
-		IncludeHighlighting: true,
-		IncludeFacets:       true,                         // Re-enable facets for accurate summary stats
-		FacetFields:         []string{"ip", "path_exact"}, // For UV and Unique Pages
-		FacetSize:           10000,                        // Balanced: large enough for most cases, but not excessive
-		IncludeStats:        true,                         // Traffic totals for the whole match set, not just this page
+		Query:      req.Query,
+		Expression: req.Expression,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+		SortBy:     req.SortBy,
+		SortOrder:  req.SortOrder,
 	}
 
 	// If no sorting is specified, default to sorting by timestamp descending.
@@ -302,88 +422,11 @@ func AdvancedSearchLogs(c *gin.Context) {
 	if len(req.Status) > 0 {
 		searchReq.StatusCodes = req.Status
 	}
-
-	// Execute search with timeout
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Minute)
-	defer cancel()
-
-	result, err := searcherService.Search(ctx, searchReq)
-	if err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	// --- Transform the searcher result to the API response structure ---
-
-	// 1. Extract entries from hits
-	entries := make([]map[string]interface{}, len(result.Hits))
-	for i, hit := range result.Hits {
-		entries[i] = hit.Fields
+	if len(req.Vars) > 0 {
+		searchReq.Variables = req.Vars
 	}
 
-	// 2. Calculate summary stats from the overall results using Counter for accuracy
-	pv := int(result.TotalHits)
-	var uv, uniquePages int
-	var facetUV, facetUniquePages int
-
-	// First get facet values as fallback
-	if result.Facets != nil {
-		if ipFacet, ok := result.Facets["ip"]; ok {
-			facetUV = ipFacet.Total // .Total on a facet gives the count of unique terms
-			uv = facetUV
-		}
-		if pathFacet, ok := result.Facets["path_exact"]; ok {
-			facetUniquePages = pathFacet.Total
-			uniquePages = facetUniquePages
-		}
-	}
-
-	// Override with Counter results for better accuracy
-	if analyticsService != nil {
-		// Get cardinality counts for UV (unique IPs)
-		if uvResult := getCardinalityCount(ctx, "ip", searchReq); uvResult > 0 {
-			uv = uvResult
-			logger.Debugf("🔢 Search endpoint - UV from Counter: %d (vs facet: %d)", uvResult, facetUV)
-		}
-
-		// Get cardinality counts for Unique Pages (unique paths)
-		if upResult := getCardinalityCount(ctx, "path_exact", searchReq); upResult > 0 {
-			uniquePages = upResult
-			logger.Debugf("🔢 Search endpoint - Unique Pages from Counter: %d (vs facet: %d)", upResult, facetUniquePages)
-		}
-	}
-
-	// Traffic totals come from the stats aggregation, which scans the whole
-	// match set (or a bounded prefix of it, in which case it says so) rather
-	// than extrapolating from the current page.
-	var totalTraffic int64
-	var avgTraffic float64
-	var trafficApproximate bool
-	if result.Stats != nil {
-		totalTraffic = result.Stats.TotalBytes
-		avgTraffic = result.Stats.AvgBytes
-		trafficApproximate = result.Stats.Approximate
-	}
-
-	summary := SummaryStats{
-		UV:                 uv,
-		PV:                 pv,
-		TotalTraffic:       totalTraffic,
-		UniquePages:        uniquePages,
-		AvgTrafficPerPV:    avgTraffic,
-		TrafficApproximate: trafficApproximate,
-	}
-
-	// 3. Assemble the final response
-	apiResponse := AdvancedSearchResponseAPI{
-		Entries: entries,
-		Total:   result.TotalHits,
-		Took:    result.Duration.Milliseconds(),
-		Query:   req.Query,
-		Summary: summary,
-	}
-
-	c.JSON(http.StatusOK, apiResponse)
+	return searchReq, true
 }
 
 // GetLogEntries provides simple log entry retrieval
@@ -547,6 +590,40 @@ func GetDashboardAnalytics(c *gin.Context) {
 		return
 	}
 
+	dashboardReq, ok := buildDashboardQuery(c, analyticsService, &req)
+	if !ok {
+		return
+	}
+
+	// Get dashboard analytics with timeout
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	// Get analytics from modern analytics service
+	result, err := analyticsService.GetDashboardAnalytics(ctx, dashboardReq)
+
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	logger.Debugf("Successfully retrieved dashboard analytics")
+
+	// Debug: Log summary of results
+	if result != nil {
+		logger.Debugf("Results summary - TotalUV=%d, TotalPV=%d, HourlyStats=%d, DailyStats=%d, TopURLs=%d",
+			result.Summary.TotalUV, result.Summary.TotalPV,
+			len(result.HourlyStats), len(result.DailyStats), len(result.TopURLs))
+	} else {
+		logger.Debugf("Analytics result is nil")
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// buildDashboardQuery resolves the log path and date range of req. On failure
+// the error response is written and false is returned.
+func buildDashboardQuery(c *gin.Context, analyticsService analytics.Service, req *DashboardRequest) (*analytics.DashboardQueryRequest, bool) {
 	// Use default access log path if LogPath is empty
 	if req.LogPath == "" {
 		defaultLogPath := nginx.GetAccessLogPath()
@@ -560,7 +637,7 @@ func GetDashboardAnalytics(c *gin.Context) {
 	if req.LogPath != "" {
 		if err := analyticsService.ValidateLogPath(req.LogPath); err != nil {
 			cosy.ErrHandler(c, err)
-			return
+			return nil, false
 		}
 	}
 
@@ -572,7 +649,7 @@ func GetDashboardAnalytics(c *gin.Context) {
 		startTime, err = time.Parse("2006-01-02", req.StartDate)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid start_date format, expected YYYY-MM-DD: " + err.Error()})
-			return
+			return nil, false
 		}
 		// Convert to UTC for consistent processing
 		startTime = startTime.UTC()
@@ -582,7 +659,7 @@ func GetDashboardAnalytics(c *gin.Context) {
 		endTime, err = time.Parse("2006-01-02", req.EndDate)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid end_date format, expected YYYY-MM-DD: " + err.Error()})
-			return
+			return nil, false
 		}
 		// Set end time to end of day and convert to UTC
 		endTime = endTime.Add(23*time.Hour + 59*time.Minute + 59*time.Second).UTC()
@@ -594,10 +671,6 @@ func GetDashboardAnalytics(c *gin.Context) {
 		startTime = endTime.AddDate(0, 0, -30) // 30 days ago
 	}
 
-	// Get dashboard analytics with timeout
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-	defer cancel()
-
 	logger.Debugf("Dashboard request for log_path: %s, parsed start_time: %v, end_time: %v", req.LogPath, startTime, endTime)
 
 	// Use main_log_path field for efficient log group queries instead of expanding file paths
@@ -614,26 +687,7 @@ func GetDashboardAnalytics(c *gin.Context) {
 	logger.Debugf("Query parameters - LogPath='%s', StartTime=%v, EndTime=%v",
 		dashboardReq.LogPath, dashboardReq.StartTime, dashboardReq.EndTime)
 
-	// Get analytics from modern analytics service
-	result, err := analyticsService.GetDashboardAnalytics(ctx, dashboardReq)
-
-	if err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	logger.Debugf("Successfully retrieved dashboard analytics")
-
-	// Debug: Log summary of results
-	if result != nil {
-		logger.Debugf("Results summary - TotalUV=%d, TotalPV=%d, HourlyStats=%d, DailyStats=%d, TopURLs=%d",
-			result.Summary.TotalUV, result.Summary.TotalPV,
-			len(result.HourlyStats), len(result.DailyStats), len(result.TopURLs))
-	} else {
-		logger.Debugf("Analytics result is nil")
-	}
-
-	c.JSON(http.StatusOK, result)
+	return dashboardReq, true
 }
 
 // GetWorldMapData provides geographic data for world map visualization
@@ -0,0 +1,205 @@
+package nginx_log
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/analytics"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// ErrorLogSearchRequest represents the request for searching indexed error logs
+type ErrorLogSearchRequest struct {
+	Query           string   `json:"query"`
+	LogPath         string   `json:"log_path"`
+	StartTime       int64    `json:"start_time"`
+	EndTime         int64    `json:"end_time"`
+	Levels          []string `json:"levels"`
+	Clients         []string `json:"clients"`
+	Servers         []string `json:"servers"`
+	Upstreams       []string `json:"upstreams"`
+	Hosts           []string `json:"hosts"`
+	MessagePatterns []string `json:"message_patterns"`
+	Limit           int      `json:"limit"`
+	Offset          int      `json:"offset"`
+	SortOrder       string   `json:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// Size is the number of message clusters of a top messages request
+	Size int `json:"size"`
+}
+
+// ErrorLogSearchResponse represents the response of an error log search
+type ErrorLogSearchResponse struct {
+	Entries []map[string]interface{}   `json:"entries"`
+	Total   uint64                     `json:"total"`
+	Took    int64                      `json:"took"` // Milliseconds
+	Facets  map[string]*searcher.Facet `json:"facets"`
+}
+
+// ErrorLogTimelineRequest represents the request for the error rate timeline
+type ErrorLogTimelineRequest struct {
+	LogPath   string   `json:"log_path"`
+	StartTime int64    `json:"start_time" binding:"required"`
+	EndTime   int64    `json:"end_time" binding:"required"`
+	Interval  int64    `json:"interval"`
+	Servers   []string `json:"servers"`
+}
+
+// resolveErrorLogPath falls back to the default error log and validates the path
+func resolveErrorLogPath(c *gin.Context, analyticsService analytics.Service, logPath string) (string, bool) {
+	if logPath == "" {
+		logPath = nginx.GetErrorLogPath()
+	}
+	if logPath != "" {
+		if err := analyticsService.ValidateLogPath(logPath); err != nil {
+			cosy.ErrHandler(c, err)
+			return "", false
+		}
+	}
+	return logPath, true
+}
+
+// toErrorSearchRequest converts the API request to a searcher request
+func (req *ErrorLogSearchRequest) toErrorSearchRequest(logPath string) *searcher.ErrorSearchRequest {
+	searchReq := &searcher.ErrorSearchRequest{
+		Query:           req.Query,
+		Levels:          req.Levels,
+		Clients:         req.Clients,
+		Servers:         req.Servers,
+		Upstreams:       req.Upstreams,
+		Hosts:           req.Hosts,
+		MessagePatterns: req.MessagePatterns,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+		SortOrder:       req.SortOrder,
+	}
+	if logPath != "" {
+		searchReq.LogPaths = []string{logPath}
+	}
+	if req.StartTime > 0 {
+		searchReq.StartTime = &req.StartTime
+	}
+	if req.EndTime > 0 {
+		searchReq.EndTime = &req.EndTime
+	}
+	return searchReq
+}
+
+// SearchErrorLogs searches indexed error log lines by message, level, client,
+// server, upstream and host
+func SearchErrorLogs(c *gin.Context) {
+	var req ErrorLogSearchRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	errorSearcher := nginx_log.GetErrorSearcher()
+	if errorSearcher == nil {
+		cosy.ErrHandler(c, nginx_log.ErrErrorLogIndexNotAvailable)
+		return
+	}
+
+	logPath, ok := resolveErrorLogPath(c, analyticsService, req.LogPath)
+	if !ok {
+		return
+	}
+
+	searchReq := req.toErrorSearchRequest(logPath)
+	searchReq.FacetFields = []string{
+		searcher.ErrorFieldLevel, searcher.ErrorFieldClient,
+		searcher.ErrorFieldServer, searcher.ErrorFieldUpstream,
+	}
+
+	result, err := errorSearcher.Search(c.Request.Context(), searchReq)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	entries := make([]map[string]interface{}, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		entries = append(entries, hit.Fields)
+	}
+
+	c.JSON(http.StatusOK, ErrorLogSearchResponse{
+		Entries: entries,
+		Total:   result.TotalHits,
+		Took:    result.Duration.Milliseconds(),
+		Facets:  result.Facets,
+	})
+}
+
+// GetErrorLogTopMessages returns the most frequent error messages, grouping
+// lines that only differ in ids, addresses or numbers
+func GetErrorLogTopMessages(c *gin.Context) {
+	var req ErrorLogSearchRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	logPath, ok := resolveErrorLogPath(c, analyticsService, req.LogPath)
+	if !ok {
+		return
+	}
+
+	clusters, err := analyticsService.GetTopErrorMessages(c.Request.Context(), req.toErrorSearchRequest(logPath), req.Size)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": clusters,
+	})
+}
+
+// GetErrorLogTimeline returns the error log volume and per-minute rate over time
+func GetErrorLogTimeline(c *gin.Context) {
+	var req ErrorLogTimelineRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	logPath, ok := resolveErrorLogPath(c, analyticsService, req.LogPath)
+	if !ok {
+		return
+	}
+
+	timelineReq := &analytics.ErrorTimelineRequest{
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Interval:  req.Interval,
+		Servers:   req.Servers,
+	}
+	if logPath != "" {
+		timelineReq.LogPaths = []string{logPath}
+	}
+
+	timeline, err := analyticsService.GetErrorTimeline(c.Request.Context(), timelineReq)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
@@ -0,0 +1,255 @@
+package nginx_log
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/logexport"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/analytics"
+	"github.com/0xJacky/Nginx-UI/internal/savedsearch"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// ExportLogsRequest selects the access log entries to export
+type ExportLogsRequest struct {
+	AdvancedSearchRequest
+	Format string `json:"format" form:"format"`
+	// Background runs the export as a job instead of streaming the file
+	Background bool `json:"background" form:"background"`
+}
+
+// ExportDashboardRequest selects the dashboard aggregates to export
+type ExportDashboardRequest struct {
+	DashboardRequest
+	Format string `json:"format" form:"format"`
+	// Table exports a single table such as top_urls, all tables if empty
+	Table string `json:"table" form:"table"`
+}
+
+// ExportGeoRequest selects the geographic distribution to export
+type ExportGeoRequest struct {
+	AnalyticsRequest
+	Format string `json:"format" form:"format"`
+}
+
+// setExportHeaders marks the response as a file download named after name
+func setExportHeaders(c *gin.Context, name string, format logexport.Format) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format.Extension())
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Cache-Control", "no-store")
+}
+
+// ExportLogs streams every access log entry matching the search as CSV,
+// NDJSON or Parquet, or starts a background export job when requested
+func ExportLogs(c *gin.Context) {
+	var req ExportLogsRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	format, err := logexport.ParseFormat(req.Format)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	if err := savedsearch.ValidateExpression(req.Expression); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	searcherService := nginx_log.GetSearcher()
+	if searcherService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernSearcherNotAvailable)
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	searchReq, ok := buildSearchRequest(c, analyticsService, &req.AdvancedSearchRequest)
+	if !ok {
+		return
+	}
+
+	if req.Background {
+		job, err := logexport.StartJob(api.CurrentUserID(c), searchReq, format)
+		if err != nil {
+			cosy.ErrHandler(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, job)
+		return
+	}
+
+	setExportHeaders(c, "access-log", format)
+	written, err := logexport.WriteEntries(c.Request.Context(), searcherService, *searchReq, format, c.Writer, nil)
+	if err == nil {
+		return
+	}
+	if !c.Writer.Written() {
+		// Nothing was sent yet, so the failure can still be reported as JSON
+		c.Writer.Header().Del("Content-Disposition")
+		cosy.ErrHandler(c, err)
+		return
+	}
+	// The download is cut short, which the client sees as a truncated body
+	logger.Errorf("Log export stopped after %d entries: %v", written, err)
+}
+
+// ExportDashboardAnalytics exports the dashboard aggregates of a log
+func ExportDashboardAnalytics(c *gin.Context) {
+	var req ExportDashboardRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	format, err := logexport.ParseFormat(req.Format)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	dashboardReq, ok := buildDashboardQuery(c, analyticsService, &req.DashboardRequest)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := analyticsService.GetDashboardAnalytics(ctx, dashboardReq)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	tables, err := logexport.SelectTable(logexport.DashboardTables(result), req.Table)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	writeExportTables(c, "dashboard", format, tables)
+}
+
+// ExportGeoDistribution exports the requests per country of a log
+func ExportGeoDistribution(c *gin.Context) {
+	var req ExportGeoRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	format, err := logexport.ParseFormat(req.Format)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	analyticsService := nginx_log.GetAnalytics()
+	if analyticsService == nil {
+		cosy.ErrHandler(c, nginx_log.ErrModernAnalyticsNotAvailable)
+		return
+	}
+
+	// Use default access log path if Path is empty
+	if req.Path == "" {
+		req.Path = nginx.GetAccessLogPath()
+	}
+	if req.Path != "" {
+		if err := analyticsService.ValidateLogPath(req.Path); err != nil {
+			cosy.ErrHandler(c, err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	data, err := analyticsService.GetGeoDistribution(ctx, &analytics.GeoQueryRequest{
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		LogPath:        req.Path,
+		LogPaths:       []string{req.Path},
+		UseMainLogPath: true,
+		Limit:          req.Limit,
+	})
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	writeExportTables(c, "geo-distribution", format, logexport.GeoTables(data))
+}
+
+func writeExportTables(c *gin.Context, name string, format logexport.Format, tables []logexport.Table) {
+	if err := logexport.CheckTables(format, tables); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	setExportHeaders(c, name, format)
+	c.Status(http.StatusOK)
+	if err := logexport.WriteTables(c.Writer, format, tables); err != nil {
+		logger.Errorf("Failed to write %s export: %v", name, err)
+	}
+}
+
+// GetExportJobs lists the background export jobs of the requesting user
+func GetExportJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": logexport.ListJobs(api.CurrentUserID(c)),
+	})
+}
+
+// GetExportJob returns a background export job of the requesting user
+func GetExportJob(c *gin.Context) {
+	job, err := logexport.GetJob(c.Param("id"), api.CurrentUserID(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadExportJob sends the file of a completed export job
+func DownloadExportJob(c *gin.Context) {
+	file, job, err := logexport.OpenJobFile(c.Param("id"), api.CurrentUserID(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	defer file.Close()
+
+	setExportHeaders(c, "access-log", job.Format)
+	http.ServeContent(c.Writer, c.Request, "", *job.FinishedAt, file)
+}
+
+// DestroyExportJob cancels a running export job or deletes a finished one
+func DestroyExportJob(c *gin.Context) {
+	if err := logexport.RemoveJob(c.Param("id"), api.CurrentUserID(c)); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
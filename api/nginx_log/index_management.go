@@ -289,12 +289,8 @@ func rebuildSingleFile(modernIndexer interface{}, path string, logFileManager in
 	var minTime, maxTime *time.Time
 
 	if targetLog != nil && targetLog.Type == "error" {
-		logger.Infof("Skipping index rebuild for error log as requested: %s", path)
-		if logFileManager != nil {
-			if err := logFileManager.(indexer.MetadataManager).SaveIndexMetadata(path, 0, time.Now(), 0, nil, nil); err != nil {
-				logger.Warnf("Could not reset metadata for skipped error log %s: %v", path, err)
-			}
-		}
+		logger.Infof("Starting error log index rebuild for file: %s", path)
+		minTime, maxTime = rebuildErrorLog(path)
 	} else {
 		logger.Infof("Starting modern index rebuild for file: %s", path)
 
@@ -388,15 +384,11 @@ func rebuildAllFiles(modernIndexer interface{}, logFileManager interface{}, prog
 	// First pass: Set all access logs to queued status
 	queuePosition := 1
 	accessLogs := make([]*nginx_log.NginxLogWithIndex, 0)
+	errorLogs := make([]*nginx_log.NginxLogWithIndex, 0)
 	
 	for _, log := range allLogs {
 		if log.Type == "error" {
-			logger.Infof("Skipping indexing for error log: %s", log.Path)
-			if logFileManager != nil {
-				if err := logFileManager.(indexer.MetadataManager).SaveIndexMetadata(log.Path, 0, time.Now(), 0, nil, nil); err != nil {
-					logger.Warnf("Could not reset metadata for skipped error log %s: %v", log.Path, err)
-				}
-			}
+			errorLogs = append(errorLogs, log)
 			continue
 		}
 		
@@ -510,6 +502,17 @@ func rebuildAllFiles(modernIndexer interface{}, logFileManager interface{}, prog
 	// Wait for all log groups to complete
 	wg.Wait()
 
+	// Error logs are small and share one index, so index them one by one
+	for _, log := range errorLogs {
+		minTime, maxTime := rebuildErrorLog(log.Path)
+		if minTime != nil && (overallMinTime == nil || minTime.Before(*overallMinTime)) {
+			overallMinTime = minTime
+		}
+		if maxTime != nil && (overallMaxTime == nil || maxTime.After(*overallMaxTime)) {
+			overallMaxTime = maxTime
+		}
+	}
+
 	totalDuration := time.Since(startTime)
 	logger.Infof("Successfully completed full modern index rebuild in %s", totalDuration)
 
@@ -521,3 +524,26 @@ func rebuildAllFiles(modernIndexer interface{}, logFileManager interface{}, prog
 
 	return overallMinTime, overallMaxTime
 }
+
+// rebuildErrorLog indexes an error log group into the error log index and
+// notifies the frontend like the access log progress tracker does
+func rebuildErrorLog(path string) (*time.Time, *time.Time) {
+	startTime := time.Now()
+	minTime, maxTime, err := nginx_log.IndexErrorLogGroup(context.Background(), path)
+
+	completion := event.NginxLogIndexCompleteData{
+		LogPath:  path,
+		Success:  err == nil,
+		Duration: time.Since(startTime).Milliseconds(),
+	}
+	if err != nil {
+		logger.Errorf("Failed to index error log group %s: %v", path, err)
+		completion.Error = err.Error()
+	}
+	event.Publish(event.Event{
+		Type: event.TypeNginxLogIndexComplete,
+		Data: completion,
+	})
+
+	return minTime, maxTime
+}
@@ -0,0 +1,34 @@
+package nginx_log
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/rollup"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// LogRetentionRequest sets how long the raw documents of a log group are kept
+type LogRetentionRequest struct {
+	Path          string `json:"path" binding:"required"`
+	RetentionDays int    `json:"retention_days" binding:"min=0"`
+}
+
+// SetLogRetention sets the retention policy of an indexed log group. Raw
+// documents older than the retention are purged once rolled up, 0 days keeps
+// them forever.
+func SetLogRetention(c *gin.Context) {
+	var req LogRetentionRequest
+	if !cosy.BindAndValid(c, &req) {
+		return
+	}
+
+	if err := rollup.SetRetention(req.Path, req.RetentionDays); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
@@ -10,16 +10,46 @@ func InitRouter(r *gin.RouterGroup) {
 	r.POST("nginx_log/analytics", GetLogAnalytics)
 	r.GET("nginx_log/entries", GetLogEntries)
 	r.POST("nginx_log/search", AdvancedSearchLogs)
+	r.POST("nginx_log/export", ExportLogs)
+	r.POST("nginx_log/export/dashboard", ExportDashboardAnalytics)
+	r.POST("nginx_log/export/geo", ExportGeoDistribution)
+	r.GET("nginx_log/export/jobs", GetExportJobs)
+	r.GET("nginx_log/export/jobs/:id", GetExportJob)
+	r.GET("nginx_log/export/jobs/:id/download", DownloadExportJob)
+	r.DELETE("nginx_log/export/jobs/:id", DestroyExportJob)
 	r.GET("nginx_log/preflight", GetLogPreflight)
 	r.POST("nginx_log/dashboard", GetDashboardAnalytics)
 	r.POST("nginx_log/geo/world", GetWorldMapData)
 	r.POST("nginx_log/geo/china", GetChinaMapData)
 	r.POST("nginx_log/geo/stats", GetGeoStats)
+	r.POST("nginx_log/errors/search", SearchErrorLogs)
+	r.POST("nginx_log/errors/top_messages", GetErrorLogTopMessages)
+	r.POST("nginx_log/errors/timeline", GetErrorLogTimeline)
 	r.POST("nginx_log/index/rebuild", RebuildIndex)
+	r.POST("nginx_log/index/retention", SetLogRetention)
 	r.POST("nginx_log/settings/advanced_indexing/enable", EnableAdvancedIndexing)
 	r.POST("nginx_log/settings/advanced_indexing/disable", DisableAdvancedIndexing)
 	r.GET("nginx_log/settings/advanced_indexing/status", GetAdvancedIndexingStatus)
 	r.GET("nginx_log/default_log_dir", GetDefaultLogDir)
+
+	r.GET("nginx_log/traffic_alerts", GetTrafficAlertRules)
+	r.GET("nginx_log/traffic_alerts/:id", GetTrafficAlertRule)
+	r.GET("nginx_log/traffic_alerts/:id/state", GetTrafficAlertState)
+	r.POST("nginx_log/traffic_alerts", CreateTrafficAlertRule)
+	r.POST("nginx_log/traffic_alerts/:id", ModifyTrafficAlertRule)
+	r.DELETE("nginx_log/traffic_alerts/:id", DestroyTrafficAlertRule)
+	r.POST("nginx_log/traffic_alerts/preview", PreviewTrafficAlertRule)
+
+	r.GET("nginx_log/saved_searches", GetSavedSearches)
+	r.GET("nginx_log/saved_searches/:id", GetSavedSearch)
+	r.POST("nginx_log/saved_searches", CreateSavedSearch)
+	r.POST("nginx_log/saved_searches/:id", ModifySavedSearch)
+	r.DELETE("nginx_log/saved_searches/:id", DestroySavedSearch)
+	r.POST("nginx_log/saved_searches/:id/run", RunSavedSearch)
+	r.POST("nginx_log/saved_searches/:id/share", ShareSavedSearch)
+	r.DELETE("nginx_log/saved_searches/:id/share", UnshareSavedSearch)
+	r.GET("nginx_log/saved_searches/shared/:token", GetSharedSavedSearch)
+	r.POST("nginx_log/saved_searches/shared/:token/run", RunSharedSavedSearch)
 }
 
 func InitWebSocketRouter(r *gin.RouterGroup) {
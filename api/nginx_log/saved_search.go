@@ -0,0 +1,187 @@
+package nginx_log
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/savedsearch"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/map2struct"
+	"gorm.io/gorm"
+)
+
+var savedSearchRules = gin.H{
+	"name":        "required",
+	"description": "omitempty",
+	"log_path":    "omitempty",
+	"query":       "omitempty",
+	"expression":  "omitempty",
+	"time_range":  "omitempty,min=0",
+	"sort_by":     "omitempty",
+	"sort_order":  "omitempty,oneof=asc desc",
+}
+
+// SavedSearchRunResponse is the result of running a saved search
+type SavedSearchRunResponse struct {
+	Search  *model.NginxLogSavedSearch `json:"search"`
+	Entries []map[string]interface{}   `json:"entries"`
+	Total   uint64                     `json:"total"`
+	Took    int64                      `json:"took"` // Milliseconds
+}
+
+// scopeToOwner limits saved searches to the ones of the requesting user
+func scopeToOwner(c *gin.Context) func(tx *gorm.DB) *gorm.DB {
+	userID := api.CurrentUserID(c)
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("user_id = ?", userID)
+	}
+}
+
+// normalizeSavedSearch validates the saved search as it will be stored and
+// assigns it to the requesting user.
+func normalizeSavedSearch(c *gin.Context) func(ctx *cosy.Ctx[model.NginxLogSavedSearch]) {
+	return func(ctx *cosy.Ctx[model.NginxLogSavedSearch]) {
+		search := ctx.OriginModel
+		if err := map2struct.WeakDecode(ctx.Payload, &search); err != nil {
+			ctx.AbortWithError(err)
+			return
+		}
+		if err := savedsearch.Normalize(&search); err != nil {
+			ctx.AbortWithError(err)
+			return
+		}
+		search.UserID = api.CurrentUserID(c)
+		ctx.Model = search
+	}
+}
+
+func GetSavedSearches(c *gin.Context) {
+	cosy.Core[model.NginxLogSavedSearch](c).
+		SetFussy("name").
+		SetEqual("log_path").
+		GormScope(scopeToOwner(c)).
+		PagingList()
+}
+
+func GetSavedSearch(c *gin.Context) {
+	cosy.Core[model.NginxLogSavedSearch](c).
+		GormScope(scopeToOwner(c)).
+		Get()
+}
+
+func CreateSavedSearch(c *gin.Context) {
+	cosy.Core[model.NginxLogSavedSearch](c).
+		SetValidRules(savedSearchRules).
+		BeforeExecuteHook(normalizeSavedSearch(c)).
+		Create()
+}
+
+func ModifySavedSearch(c *gin.Context) {
+	rules := gin.H{}
+	for field, rule := range savedSearchRules {
+		if rule == "required" {
+			rule = "omitempty"
+		}
+		rules[field] = rule
+	}
+	cosy.Core[model.NginxLogSavedSearch](c).
+		SetValidRules(rules).
+		GormScope(scopeToOwner(c)).
+		BeforeExecuteHook(normalizeSavedSearch(c)).
+		Modify()
+}
+
+func DestroySavedSearch(c *gin.Context) {
+	cosy.Core[model.NginxLogSavedSearch](c).
+		GormScope(scopeToOwner(c)).
+		Destroy()
+}
+
+// ShareSavedSearch issues the share link token of a saved search
+func ShareSavedSearch(c *gin.Context) {
+	search, err := savedsearch.Get(cast.ToUint64(c.Param("id")), api.CurrentUserID(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if err := savedsearch.Share(search); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// UnshareSavedSearch revokes the share link of a saved search
+func UnshareSavedSearch(c *gin.Context) {
+	search, err := savedsearch.Get(cast.ToUint64(c.Param("id")), api.CurrentUserID(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if err := savedsearch.Unshare(search); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// GetSharedSavedSearch opens a saved search of any user by its share link
+func GetSharedSavedSearch(c *gin.Context) {
+	search, err := savedsearch.GetShared(c.Param("token"))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// RunSavedSearch runs a saved search of the requesting user
+func RunSavedSearch(c *gin.Context) {
+	search, err := savedsearch.Get(cast.ToUint64(c.Param("id")), api.CurrentUserID(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	runSavedSearch(c, search)
+}
+
+// RunSharedSavedSearch runs a saved search opened by its share link
+func RunSharedSavedSearch(c *gin.Context) {
+	search, err := savedsearch.GetShared(c.Param("token"))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	runSavedSearch(c, search)
+}
+
+func runSavedSearch(c *gin.Context, search *model.NginxLogSavedSearch) {
+	var opts savedsearch.RunOptions
+	if c.Request.ContentLength != 0 && !cosy.BindAndValid(c, &opts) {
+		return
+	}
+
+	result, err := savedsearch.Run(c.Request.Context(), search, opts)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	entries := make([]map[string]interface{}, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		entries = append(entries, hit.Fields)
+	}
+
+	c.JSON(http.StatusOK, SavedSearchRunResponse{
+		Search:  search,
+		Entries: entries,
+		Total:   result.TotalHits,
+		Took:    result.Duration.Milliseconds(),
+	})
+}
@@ -0,0 +1,133 @@
+package nginx_log
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/cron"
+	"github.com/0xJacky/Nginx-UI/internal/trafficalert"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"github.com/uozi-tech/cosy/map2struct"
+)
+
+var trafficAlertRules = gin.H{
+	"name":                "required",
+	"log_path":            "required",
+	"metric":              "required,oneof=error_ratio latency rate_spike ip_rate",
+	"threshold":           "omitempty,min=0",
+	"recovery_threshold":  "omitempty,min=0",
+	"percentile":          "omitempty",
+	"window_seconds":      "omitempty",
+	"baseline_seconds":    "omitempty",
+	"min_requests":        "omitempty",
+	"interval_seconds":    "omitempty",
+	"failure_threshold":   "omitempty",
+	"cooldown_seconds":    "omitempty",
+	"recovery_enabled":    "omitempty",
+	"external_notify_ids": "omitempty",
+	"enabled":             "omitempty",
+}
+
+// normalizeTrafficAlertRule validates the rule as it will be stored. A partial
+// update is merged onto the stored rule first, and the defaults Normalize fills
+// in are persisted with the changed fields.
+func normalizeTrafficAlertRule(ctx *cosy.Ctx[model.TrafficAlertRule]) {
+	rule := ctx.OriginModel
+	if err := map2struct.WeakDecode(ctx.Payload, &rule); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	if err := trafficalert.Normalize(&rule); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	ctx.Model = rule
+	ctx.AddSelectedFields(trafficalert.NormalizedColumns...)
+}
+
+func scheduleTrafficAlertRule(ctx *cosy.Ctx[model.TrafficAlertRule]) {
+	var err error
+	if ctx.Model.Enabled {
+		err = cron.AddOrUpdateTrafficAlertJob(ctx.Model.ID, ctx.Model.IntervalSeconds)
+	} else {
+		err = cron.RemoveTrafficAlertJob(ctx.Model.ID)
+	}
+	if err != nil {
+		ctx.AbortWithError(err)
+	}
+}
+
+func GetTrafficAlertRules(c *gin.Context) {
+	cosy.Core[model.TrafficAlertRule](c).
+		SetFussy("name").
+		SetEqual("log_path", "metric", "enabled").
+		PagingList()
+}
+
+func GetTrafficAlertRule(c *gin.Context) {
+	cosy.Core[model.TrafficAlertRule](c).Get()
+}
+
+func CreateTrafficAlertRule(c *gin.Context) {
+	cosy.Core[model.TrafficAlertRule](c).
+		SetValidRules(trafficAlertRules).
+		BeforeExecuteHook(normalizeTrafficAlertRule).
+		ExecutedHook(scheduleTrafficAlertRule).
+		Create()
+}
+
+func ModifyTrafficAlertRule(c *gin.Context) {
+	rules := gin.H{}
+	for field := range trafficAlertRules {
+		rules[field] = "omitempty"
+	}
+	cosy.Core[model.TrafficAlertRule](c).
+		SetValidRules(rules).
+		BeforeExecuteHook(normalizeTrafficAlertRule).
+		ExecutedHook(scheduleTrafficAlertRule).
+		Modify()
+}
+
+func DestroyTrafficAlertRule(c *gin.Context) {
+	cosy.Core[model.TrafficAlertRule](c).BeforeExecuteHook(func(ctx *cosy.Ctx[model.TrafficAlertRule]) {
+		if err := cron.RemoveTrafficAlertJob(ctx.Model.ID); err != nil {
+			logger.Errorf("Failed to remove traffic alert job %d: %v", ctx.Model.ID, err)
+		}
+	}).Destroy()
+}
+
+// GetTrafficAlertState returns the last evaluation of a rule.
+func GetTrafficAlertState(c *gin.Context) {
+	state, err := trafficalert.GetState(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// PreviewTrafficAlertRule measures an unsaved rule against the current index
+// without touching any alert state.
+func PreviewTrafficAlertRule(c *gin.Context) {
+	var rule model.TrafficAlertRule
+	if !cosy.BindAndValid(c, &rule) {
+		return
+	}
+	if err := trafficalert.Normalize(&rule); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	m, err := trafficalert.Measure(c.Request.Context(), &rule, time.Now())
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
@@ -10,6 +10,7 @@ import (
 	"code.pfad.fr/risefront"
 	"github.com/0xJacky/Nginx-UI/internal/cert"
 	"github.com/0xJacky/Nginx-UI/internal/cron"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/sitecheck"
 	"github.com/0xJacky/Nginx-UI/internal/system"
@@ -33,6 +34,8 @@ type saveSettingsPayload struct {
 	Server        cSettings.Server       `json:"server"`
 	Auth          settings.Auth          `json:"auth"`
 	Cert          settings.Cert          `json:"cert"`
+	ChangeSet     settings.ChangeSet     `json:"change_set"`
+	GitHistory    settings.GitHistory    `json:"git_history"`
 	Http          settings.HTTP          `json:"http"`
 	Node          settings.Node          `json:"node"`
 	Openai        settings.OpenAI        `json:"openai"`
@@ -148,6 +151,8 @@ func settingsSectionSources() map[string]any {
 		"auth":           settings.AuthSettings,
 		"casdoor":        settings.CasdoorSettings,
 		"cert":           settings.CertSettings,
+		"change_set":     settings.ChangeSetSettings,
+		"git_history":    settings.GitHistorySettings,
 		"http":           settings.HTTPSettings,
 		"logrotate":      settings.LogrotateSettings,
 		"nginx":          settings.NginxSettings,
@@ -214,6 +219,8 @@ func buildSettingsResponse() gin.H {
 		"casdoor":        cloneRedactedSettingsSection(settings.CasdoorSettings),
 		"oidc":           cloneRedactedSettingsSection(settings.OIDCSettings),
 		"cert":           cloneRedactedSettingsSection(settings.CertSettings),
+		"change_set":     settings.ChangeSetSettings,
+		"git_history":    cloneRedactedSettingsSection(settings.GitHistorySettings),
 		"http":           cloneRedactedSettingsSection(settings.HTTPSettings),
 		"logrotate":      cloneRedactedSettingsSection(settings.LogrotateSettings),
 		"nginx":          buildNginxSettingsResponse(),
@@ -288,6 +295,7 @@ func SaveSettings(c *gin.Context) {
 
 	siteCheckChanged := *settings.SiteCheckSettings != json.SiteCheck
 	upstreamCheckChanged := *settings.UpstreamCheckSettings != json.UpstreamCheck
+	gitHistoryEnabled := !settings.GitHistorySettings.Enabled && json.GitHistory.Enabled
 
 	if settings.LogrotateSettings.Enabled != json.Logrotate.Enabled ||
 		settings.LogrotateSettings.Interval != json.Logrotate.Interval {
@@ -335,6 +343,8 @@ func SaveSettings(c *gin.Context) {
 		cSettings.ProtectedFill(cSettings.ServerSettings, &json.Server)
 		cSettings.ProtectedFill(settings.AuthSettings, &json.Auth)
 		cSettings.ProtectedFill(settings.CertSettings, &json.Cert)
+		cSettings.ProtectedFill(settings.ChangeSetSettings, &json.ChangeSet)
+		cSettings.ProtectedFill(settings.GitHistorySettings, &json.GitHistory)
 		cSettings.ProtectedFill(settings.HTTPSettings, &json.Http)
 		cSettings.ProtectedFill(settings.NodeSettings, &json.Node)
 		cSettings.ProtectedFill(settings.OpenAISettings, &json.Openai)
@@ -378,4 +388,14 @@ func SaveSettings(c *gin.Context) {
 			}
 		}()
 	}
+
+	// Snapshot the tree right away so the first save after enabling the
+	// history shows up as its own commit.
+	if gitHistoryEnabled {
+		go func() {
+			if err := githistory.Init(); err != nil {
+				logger.Errorf("Failed to initialize configuration history: %v", err)
+			}
+		}()
+	}
 }
@@ -0,0 +1,38 @@
+package sites
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// SetSiteClientVerify makes the TLS servers of a site verify client
+// certificates against a private CA. A zero cert_authority_id turns the
+// verification off.
+func SetSiteClientVerify(c *gin.Context) {
+	name := helper.UnescapeURL(c.Param("name"))
+
+	var json struct {
+		CertAuthorityID uint64 `json:"cert_authority_id"`
+		Mode            string `json:"mode" binding:"omitempty,oneof=on optional optional_no_ca"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+	if json.Mode == "" {
+		json.Mode = "on"
+	}
+
+	if err := site.SetClientVerify(name, json.CertAuthorityID, json.Mode, api.CommitAuthor(c)); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
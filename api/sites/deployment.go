@@ -0,0 +1,94 @@
+package sites
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/sitedeploy"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+func scopeToSite(c *gin.Context) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("site_name = ?", helper.UnescapeURL(c.Param("name")))
+	}
+}
+
+func GetSiteDeployments(c *gin.Context) {
+	cosy.Core[model.SiteDeployment](c).
+		SetEqual("status").
+		GormScope(scopeToSite(c)).
+		PagingList()
+}
+
+func GetSiteDeployment(c *gin.Context) {
+	cosy.Core[model.SiteDeployment](c).
+		GormScope(scopeToSite(c)).
+		Get()
+}
+
+// StartSiteDeployment moves the proxy_pass of a site to a new target, ramping
+// the traffic up step by step.
+func StartSiteDeployment(c *gin.Context) {
+	var json struct {
+		Strategy            model.SiteDeploymentStrategy `json:"strategy" binding:"omitempty,oneof=canary blue_green"`
+		OldTarget           string                       `json:"old_target" binding:"required"`
+		NewTarget           string                       `json:"new_target" binding:"required"`
+		Steps               []int                        `json:"steps"`
+		StepIntervalSeconds int                          `json:"step_interval_seconds"`
+		LogPath             string                       `json:"log_path"`
+		MaxErrorRatio       float64                      `json:"max_error_ratio"`
+		MaxLatency          float64                      `json:"max_latency"`
+		MinRequests         int                          `json:"min_requests"`
+		HealthCheck         bool                         `json:"health_check"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	d := &model.SiteDeployment{
+		SiteName:            helper.UnescapeURL(c.Param("name")),
+		Strategy:            json.Strategy,
+		OldTarget:           json.OldTarget,
+		NewTarget:           json.NewTarget,
+		Steps:               json.Steps,
+		StepIntervalSeconds: json.StepIntervalSeconds,
+		LogPath:             json.LogPath,
+		MaxErrorRatio:       json.MaxErrorRatio,
+		MaxLatency:          json.MaxLatency,
+		MinRequests:         json.MinRequests,
+		HealthCheck:         json.HealthCheck,
+	}
+	if err := sitedeploy.Start(d, api.CommitAuthor(c)); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// RollbackSiteDeployment stops a running deployment and restores the site.
+func RollbackSiteDeployment(c *gin.Context) {
+	d, err := sitedeploy.GetDeployment(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	if d.SiteName != helper.UnescapeURL(c.Param("name")) {
+		cosy.ErrHandler(c, gorm.ErrRecordNotFound)
+		return
+	}
+
+	d, err = sitedeploy.Rollback(d.ID, "Rolled back manually", api.CommitAuthor(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
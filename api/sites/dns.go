@@ -0,0 +1,65 @@
+package sites
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+type siteDNSTargetRequest struct {
+	IPv4  string `json:"ipv4" form:"ipv4"`
+	IPv6  string `json:"ipv6" form:"ipv6"`
+	CNAME string `json:"cname" form:"cname"`
+}
+
+// target returns the requested target, or nil to use the one of the site's
+// namespace.
+func (r siteDNSTargetRequest) target() *dns.SiteRecordTarget {
+	if r.IPv4 == "" && r.IPv6 == "" && r.CNAME == "" {
+		return nil
+	}
+	return &dns.SiteRecordTarget{IPv4: r.IPv4, IPv6: r.IPv6, CNAME: r.CNAME}
+}
+
+// GetSiteDNSPlan proposes the DNS records missing for the server names of a
+// site without changing anything.
+func GetSiteDNSPlan(c *gin.Context) {
+	name := helper.UnescapeURL(c.Param("name"))
+
+	var query siteDNSTargetRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	plan, err := site.PlanDNSRecords(c.Request.Context(), name, query.target())
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// ProvisionSiteDNS creates the proposed DNS records of a site and links them
+// to it.
+func ProvisionSiteDNS(c *gin.Context) {
+	name := helper.UnescapeURL(c.Param("name"))
+
+	var json siteDNSTargetRequest
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	plan, err := site.ProvisionDNSRecords(c.Request.Context(), name, json.target())
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
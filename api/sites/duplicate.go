@@ -3,6 +3,7 @@ package sites
 import (
 	"net/http"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/site"
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ func DuplicateSite(c *gin.Context) {
 		return
 	}
 
-	err := site.Duplicate(src, json.Name)
+	err := site.Duplicate(src, json.Name, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
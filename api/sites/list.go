@@ -51,6 +51,6 @@ func GetSiteList(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": configs,
+		"data": filterSiteConfigs(c, configs),
 	})
 }
@@ -0,0 +1,76 @@
+package sites
+
+import (
+	"errors"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+// siteNamespaceID returns the namespace recorded for the site. Sites without
+// a database record do not belong to any namespace.
+func siteNamespaceID(name string) (uint64, error) {
+	path, err := site.ResolveAvailablePath(name)
+	if err != nil {
+		return 0, err
+	}
+
+	s := query.Site
+	siteModel, err := s.Where(s.Path.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return siteModel.NamespaceID, nil
+}
+
+// authorizeSiteNamespace responds and returns false when the site is outside
+// the namespaces of the caller.
+func authorizeSiteNamespace(c *gin.Context, name string) bool {
+	subject := middleware.CurrentSubject(c)
+	if !subject.IsNamespaceScoped() {
+		return true
+	}
+
+	namespaceID, err := siteNamespaceID(name)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		c.Abort()
+		return false
+	}
+	if !subject.CanAccessNamespace(namespaceID) {
+		middleware.AbortWithNamespaceDenied(c)
+		return false
+	}
+	return true
+}
+
+// requireSiteNamespace guards the routes addressing a single site by name.
+func requireSiteNamespace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authorizeSiteNamespace(c, helper.UnescapeURL(c.Param("name"))) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// filterSiteConfigs hides sites outside the namespaces of the caller.
+func filterSiteConfigs(c *gin.Context, configs []config.Config) []config.Config {
+	subject := middleware.CurrentSubject(c)
+	if !subject.IsNamespaceScoped() {
+		return configs
+	}
+	return lo.Filter(configs, func(item config.Config, _ int) bool {
+		return subject.CanAccessNamespace(item.NamespaceID)
+	})
+}
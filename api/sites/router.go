@@ -2,16 +2,44 @@ package sites
 
 import (
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/gin-gonic/gin"
 )
 
+// InitRouter expects a group that is not proxied yet, so the routes changing
+// DNS records can check that permission before a remote node is reached.
 func InitRouter(r *gin.RouterGroup) {
 	// Initialize WebSocket notifications for site checking
 	InitWebSocketNotifications()
 
+	// Provisioning, and deleting along with the records, change the DNS
+	// provider as well.
+	r.POST("sites/:name/dns/provision",
+		middleware.RequireWritePermission(rbac.ResourceDNS),
+		middleware.Proxy(),
+		middleware.RequireSecureSession(),
+		middleware.RejectInDemo(),
+		requireSiteNamespace(),
+		ProvisionSiteDNS,
+	)
+	// delete site
+	r.DELETE("sites/:name",
+		middleware.When(deletesDNSRecords, middleware.RequireWritePermission(rbac.ResourceDNS)),
+		middleware.Proxy(),
+		middleware.RequireSecureSession(),
+		middleware.RequireChangeSet(),
+		requireSiteNamespace(),
+		DeleteSite,
+	)
+
+	r = r.Group("", middleware.Proxy())
+
 	r.GET("sites", GetSiteList)
-	r.GET("sites/:name", GetSite)
-	r.GET("sites/:name/logs", GetSiteLogs)
+	r.GET("sites/:name", requireSiteNamespace(), GetSite)
+	r.GET("sites/:name/logs", requireSiteNamespace(), GetSiteLogs)
+	r.GET("sites/:name/deployments", requireSiteNamespace(), GetSiteDeployments)
+	r.GET("sites/:name/dns/plan", requireSiteNamespace(), GetSiteDNSPlan)
+	r.GET("sites/:name/deployments/:id", requireSiteNamespace(), GetSiteDeployment)
 
 	// site navigation endpoints
 	r.GET("site_navigation", GetSiteNavigation)
@@ -25,30 +53,38 @@ func InitRouter(r *gin.RouterGroup) {
 	o := r.Group("", middleware.RequireSecureSession())
 	{
 		o.PUT("sites", BatchUpdateSites)
-		o.POST("sites/:name/advance", DomainEditByAdvancedMode)
-		o.POST("auto_cert/:name", AddDomainToAutoCert)
-		o.DELETE("auto_cert/:name", RemoveDomainFromAutoCert)
+		o.POST("sites/:name/advance", requireSiteNamespace(), DomainEditByAdvancedMode)
+		o.POST("auto_cert/:name", requireSiteNamespace(), AddDomainToAutoCert)
+		o.DELETE("auto_cert/:name", requireSiteNamespace(), RemoveDomainFromAutoCert)
 		o.POST("site_navigation/order", UpdateSiteOrder)
 		o.POST("site_navigation/health_check/:id", UpdateHealthCheck)
 		o.PUT("site_navigation/health_check/sync", SyncHealthCheck)
 
 		// batch enable sites
-		o.POST("sites/batch/enable", BatchEnableSites)
+		o.POST("sites/batch/enable", middleware.RequireChangeSet(), BatchEnableSites)
 		// batch disable sites
-		o.POST("sites/batch/disable", BatchDisableSites)
+		o.POST("sites/batch/disable", middleware.RequireChangeSet(), BatchDisableSites)
 		// rename site
-		o.POST("sites/:name/rename", RenameSite)
+		o.POST("sites/:name/rename", middleware.RequireChangeSet(), requireSiteNamespace(), RenameSite)
 		// enable site
-		o.POST("sites/:name/enable", EnableSite)
+		o.POST("sites/:name/enable", middleware.RequireChangeSet(), requireSiteNamespace(), EnableSite)
 		// disable site
-		o.POST("sites/:name/disable", DisableSite)
+		o.POST("sites/:name/disable", middleware.RequireChangeSet(), requireSiteNamespace(), DisableSite)
 		// save site
-		o.POST("sites/:name", SaveSite)
-		// delete site
-		o.DELETE("sites/:name", DeleteSite)
+		o.POST("sites/:name", middleware.RequireChangeSet(), SaveSite)
 		// duplicate site
-		o.POST("sites/:name/duplicate", DuplicateSite)
+		o.POST("sites/:name/duplicate", middleware.RequireChangeSet(), requireSiteNamespace(), DuplicateSite)
 		// enable maintenance mode for site
-		o.POST("sites/:name/maintenance", EnableMaintenanceSite)
+		o.POST("sites/:name/maintenance", middleware.RequireChangeSet(), requireSiteNamespace(), EnableMaintenanceSite)
+		// start a canary or blue/green deployment of the site's proxy_pass
+		o.POST("sites/:name/deployments", middleware.RequireChangeSet(), requireSiteNamespace(), StartSiteDeployment)
+		// roll a running deployment back
+		o.POST("sites/:name/deployments/:id/rollback", requireSiteNamespace(), RollbackSiteDeployment)
+		// verify client certificates against a private CA
+		o.POST("sites/:name/client_verify", middleware.RequireChangeSet(), requireSiteNamespace(), SetSiteClientVerify)
 	}
 }
+
+func deletesDNSRecords(c *gin.Context) bool {
+	return c.Query("delete_dns_records") == "true"
+}
@@ -2,8 +2,11 @@ package sites
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/internal/sitecheck"
 	"github.com/gin-gonic/gin"
 )
@@ -28,3 +31,24 @@ func TestInitRouterWithoutInitializedSiteCheckService(t *testing.T) {
 		t.Fatal("expected site routes to be registered before the service starts")
 	}
 }
+
+func TestDNSRoutesRequireDNSWriteBeforeProxying(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	InitRouter(router.Group("/api", func(c *gin.Context) {
+		c.Set(middleware.SubjectKey, rbac.ForScopes([]string{rbac.Permission(rbac.ResourceSites, rbac.ActionWrite)}, nil))
+		// A remote node would answer without checking the DNS permission.
+		c.Set("ProxyNodeID", 1)
+	}))
+
+	for _, target := range []struct{ method, path string }{
+		{http.MethodPost, "/api/sites/example/dns/provision"},
+		{http.MethodDelete, "/api/sites/example?delete_dns_records=true"},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(target.method, target.path, nil))
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: expected 403, got %d", target.method, target.path, recorder.Code)
+		}
+	}
+}
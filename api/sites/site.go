@@ -7,10 +7,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/cert"
 	"github.com/0xJacky/Nginx-UI/internal/clustersync"
 	"github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/site"
 	"github.com/0xJacky/Nginx-UI/internal/upstream"
@@ -54,7 +57,8 @@ func buildProxyTargets(fileName string) []site.ProxyTarget {
 	return proxyTargets
 }
 
-// checkDNSRecordsExist verifies all linked records with a single provider request.
+// checkDNSRecordsExist verifies all linked records with a single provider
+// request per domain. Records without their own domain belong to domainID.
 func checkDNSRecordsExist(domainID int, records []model.SiteDNSRecord) []model.SiteDNSRecord {
 	checkedRecords := append([]model.SiteDNSRecord(nil), records...)
 	if domainID == 0 || len(checkedRecords) == 0 {
@@ -65,23 +69,55 @@ func checkDNSRecordsExist(domainID int, records []model.SiteDNSRecord) []model.S
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	providerRecords, err := svc.ListRecords(ctx, uint64(domainID), dns.RecordListOptions{})
-	if err != nil {
-		logger.Warn("Failed to list DNS records:", err)
-		return checkedRecords
-	}
-
-	existingRecordIDs := make(map[string]struct{}, len(providerRecords))
-	for _, record := range providerRecords {
-		existingRecordIDs[record.ID] = struct{}{}
-	}
+	existingRecordIDs := make(map[uint64]map[string]struct{})
 	for i := range checkedRecords {
-		_, checkedRecords[i].Exists = existingRecordIDs[checkedRecords[i].ID]
+		recordDomainID := checkedRecords[i].DomainID
+		if recordDomainID == 0 {
+			recordDomainID = uint64(domainID)
+		}
+
+		ids, ok := existingRecordIDs[recordDomainID]
+		if !ok {
+			providerRecords, err := svc.ListRecords(ctx, recordDomainID, dns.RecordListOptions{})
+			if err != nil {
+				logger.Warn("Failed to list DNS records:", err)
+				// Unknown state is kept rather than reported as missing.
+				ids = nil
+			} else {
+				ids = make(map[string]struct{}, len(providerRecords))
+				for _, record := range providerRecords {
+					ids[record.ID] = struct{}{}
+				}
+			}
+			existingRecordIDs[recordDomainID] = ids
+		}
+		if ids != nil {
+			_, checkedRecords[i].Exists = ids[checkedRecords[i].ID]
+		}
 	}
 
 	return checkedRecords
 }
 
+// preserveDNSRecordFlags carries the domain and managed flags of the current
+// links over to the links submitted by the editor, which does not track them.
+// Managed is never taken from the request, deleting the site removes managed
+// records from the provider.
+func preserveDNSRecordFlags(current, submitted []model.SiteDNSRecord) []model.SiteDNSRecord {
+	flags := make(map[string]model.SiteDNSRecord, len(current))
+	for _, record := range current {
+		flags[record.ID] = record
+	}
+	for i := range submitted {
+		previous, ok := flags[submitted[i].ID]
+		submitted[i].Managed = ok && previous.Managed
+		if ok && submitted[i].DomainID == 0 {
+			submitted[i].DomainID = previous.DomainID
+		}
+	}
+	return submitted
+}
+
 func normalizeDNSRecords(records []model.SiteDNSRecord) []model.SiteDNSRecord {
 	normalizedRecords := make([]model.SiteDNSRecord, 0, len(records))
 	seenRecordIDs := make(map[string]struct{}, len(records))
@@ -263,7 +299,15 @@ func SaveSite(c *gin.Context) {
 		namespaceID = clustersync.ResolveNamespaceIDByName(json.Namespace)
 	}
 
-	err := site.Save(name, json.Content, json.Overwrite, namespaceID, json.SyncNodeIDs, json.PostAction)
+	if !authorizeSiteNamespace(c, name) {
+		return
+	}
+	if !middleware.CurrentSubject(c).CanAccessNamespace(namespaceID) {
+		middleware.AbortWithNamespaceDenied(c)
+		return
+	}
+
+	err := site.Save(name, json.Content, json.Overwrite, namespaceID, json.SyncNodeIDs, json.PostAction, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -295,6 +339,7 @@ func SaveSite(c *gin.Context) {
 			linkedRecords = []model.SiteDNSRecord{legacyRecord}
 		}
 
+		linkedRecords = preserveDNSRecordFlags(getSiteDNSRecords(siteModel), linkedRecords)
 		if json.DNSDomainID != nil {
 			linkedRecords = checkDNSRecordsExist(*json.DNSDomainID, linkedRecords)
 		}
@@ -305,6 +350,8 @@ func SaveSite(c *gin.Context) {
 		}
 	}
 
+	site.AutoProvisionDNSRecords(c.Request.Context(), name)
+
 	GetSite(c)
 }
 
@@ -317,7 +364,7 @@ func RenameSite(c *gin.Context) {
 		return
 	}
 
-	err := site.Rename(oldName, json.NewName)
+	err := site.Rename(oldName, json.NewName, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -328,44 +375,26 @@ func RenameSite(c *gin.Context) {
 	})
 }
 
-func disableMaintenanceIfExists(name string) error {
-	// Check if the site is in maintenance mode, if yes, disable maintenance mode first
-	maintenanceConfigPath, err := site.ResolveEnabledPath(name + site.MaintenanceSuffix)
-	if err != nil {
-		return err
-	}
-
-	if _, err := os.Stat(maintenanceConfigPath); err == nil {
-		// Site is in maintenance mode, disable it first
-		err := site.DisableMaintenance(name)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func enableSiteByName(name string) error {
-	if err := disableMaintenanceIfExists(name); err != nil {
+func enableSiteByName(name string, author githistory.Author) error {
+	if err := site.ExitMaintenance(name); err != nil {
 		return err
 	}
 
-	return site.Enable(name)
+	return site.Enable(name, author)
 }
 
-func disableSiteByName(name string) error {
-	if err := disableMaintenanceIfExists(name); err != nil {
+func disableSiteByName(name string, author githistory.Author) error {
+	if err := site.ExitMaintenance(name); err != nil {
 		return err
 	}
 
-	return site.Disable(name)
+	return site.Disable(name, author)
 }
 
 func EnableSite(c *gin.Context) {
 	name := helper.UnescapeURL(c.Param("name"))
 
-	err := enableSiteByName(name)
+	err := enableSiteByName(name, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -379,7 +408,7 @@ func EnableSite(c *gin.Context) {
 func DisableSite(c *gin.Context) {
 	name := helper.UnescapeURL(c.Param("name"))
 
-	err := disableSiteByName(name)
+	err := disableSiteByName(name, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -401,7 +430,13 @@ func BatchEnableSites(c *gin.Context) {
 	}
 
 	for _, name := range json.Names {
-		if err := enableSiteByName(name); err != nil {
+		if !authorizeSiteNamespace(c, name) {
+			return
+		}
+	}
+
+	for _, name := range json.Names {
+		if err := enableSiteByName(name, api.CommitAuthor(c)); err != nil {
 			cosy.ErrHandler(c, err)
 			return
 		}
@@ -419,7 +454,13 @@ func BatchDisableSites(c *gin.Context) {
 	}
 
 	for _, name := range json.Names {
-		if err := disableSiteByName(name); err != nil {
+		if !authorizeSiteNamespace(c, name) {
+			return
+		}
+	}
+
+	for _, name := range json.Names {
+		if err := disableSiteByName(name, api.CommitAuthor(c)); err != nil {
 			cosy.ErrHandler(c, err)
 			return
 		}
@@ -431,7 +472,14 @@ func BatchDisableSites(c *gin.Context) {
 }
 
 func DeleteSite(c *gin.Context) {
-	err := site.Delete(helper.UnescapeURL(c.Param("name")))
+	name := helper.UnescapeURL(c.Param("name"))
+
+	var err error
+	if c.Query("delete_dns_records") == "true" {
+		err = site.DeleteWithDNSRecords(name, api.CommitAuthor(c))
+	} else {
+		err = site.Delete(name, api.CommitAuthor(c))
+	}
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -447,6 +495,18 @@ func BatchUpdateSites(c *gin.Context) {
 		"namespace_id": "required",
 	}).SetItemKey("path").
 		BeforeExecuteHook(func(ctx *cosy.Ctx[model.Site]) {
+			if !middleware.CurrentSubject(ctx.Context).CanAccessNamespace(ctx.Model.NamespaceID) {
+				middleware.AbortWithNamespaceDenied(ctx.Context)
+				ctx.Abort()
+				return
+			}
+			for _, name := range ctx.BatchEffectedIDs {
+				if !authorizeSiteNamespace(ctx.Context, name) {
+					ctx.Abort()
+					return
+				}
+			}
+
 			effectedPath := make([]string, len(ctx.BatchEffectedIDs))
 			var sites []*model.Site
 			for i, name := range ctx.BatchEffectedIDs {
@@ -487,25 +547,7 @@ func EnableMaintenanceSite(c *gin.Context) {
 		return
 	}
 
-	// If site is already enabled, disable the normal site first
-	enabledConfigPath, err := site.ResolveEnabledPath(name)
-	if err != nil {
-		cosy.ErrHandler(c, err)
-		return
-	}
-
-	if _, err := os.Stat(enabledConfigPath); err == nil {
-		// Site is already enabled, disable normal site first
-		err := site.Disable(name)
-		if err != nil {
-			cosy.ErrHandler(c, err)
-			return
-		}
-	}
-
-	// Then enable maintenance mode
-	err = site.EnableMaintenance(name)
-	if err != nil {
+	if err := site.EnterMaintenance(name, api.CommitAuthor(c)); err != nil {
 		cosy.ErrHandler(c, err)
 		return
 	}
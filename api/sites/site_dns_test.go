@@ -71,3 +71,21 @@ func TestSetSiteDNSRecordsMirrorsFirstRecordAndClearsLinks(t *testing.T) {
 	assert.Nil(t, siteModel.DNSRecordType)
 	assert.Nil(t, siteModel.DNSRecordExists)
 }
+
+func TestPreserveDNSRecordFlags(t *testing.T) {
+	current := []model.SiteDNSRecord{
+		{ID: "1", Name: "www", Type: "A", DomainID: 2, Managed: true},
+		{ID: "2", Name: "api", Type: "A"},
+	}
+	submitted := []model.SiteDNSRecord{
+		{ID: "1", Name: "www", Type: "A"},
+		{ID: "2", Name: "api", Type: "A", Managed: true},
+		{ID: "3", Name: "blog", Type: "CNAME", Managed: true},
+	}
+
+	require.Equal(t, []model.SiteDNSRecord{
+		{ID: "1", Name: "www", Type: "A", DomainID: 2, Managed: true},
+		{ID: "2", Name: "api", Type: "A"},
+		{ID: "3", Name: "blog", Type: "CNAME"},
+	}, preserveDNSRecordFlags(current, submitted))
+}
@@ -3,6 +3,7 @@ package streams
 import (
 	"net/http"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/stream"
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ func Duplicate(c *gin.Context) {
 		return
 	}
 
-	err := stream.Duplicate(name, json.Name)
+	err := stream.Duplicate(name, json.Name, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -0,0 +1,76 @@
+package streams
+
+import (
+	"errors"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+// streamNamespaceID returns the namespace recorded for the stream. Streams without
+// a database record do not belong to any namespace.
+func streamNamespaceID(name string) (uint64, error) {
+	path, err := stream.ResolveAvailablePath(name)
+	if err != nil {
+		return 0, err
+	}
+
+	s := query.Stream
+	streamModel, err := s.Where(s.Path.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return streamModel.NamespaceID, nil
+}
+
+// authorizeStreamNamespace responds and returns false when the stream is outside
+// the namespaces of the caller.
+func authorizeStreamNamespace(c *gin.Context, name string) bool {
+	subject := middleware.CurrentSubject(c)
+	if !subject.IsNamespaceScoped() {
+		return true
+	}
+
+	namespaceID, err := streamNamespaceID(name)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		c.Abort()
+		return false
+	}
+	if !subject.CanAccessNamespace(namespaceID) {
+		middleware.AbortWithNamespaceDenied(c)
+		return false
+	}
+	return true
+}
+
+// requireStreamNamespace guards the routes addressing a single stream by name.
+func requireStreamNamespace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authorizeStreamNamespace(c, helper.UnescapeURL(c.Param("name"))) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// filterStreamConfigs hides streams outside the namespaces of the caller.
+func filterStreamConfigs(c *gin.Context, configs []config.Config) []config.Config {
+	subject := middleware.CurrentSubject(c)
+	if !subject.IsNamespaceScoped() {
+		return configs
+	}
+	return lo.Filter(configs, func(item config.Config, _ int) bool {
+		return subject.CanAccessNamespace(item.NamespaceID)
+	})
+}
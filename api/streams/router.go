@@ -7,16 +7,16 @@ import (
 
 func InitRouter(r *gin.RouterGroup) {
 	r.GET("streams", GetStreams)
-	r.GET("streams/:name", GetStream)
+	r.GET("streams/:name", requireStreamNamespace(), GetStream)
 	o := r.Group("", middleware.RequireSecureSession())
 	{
 		o.PUT("streams", BatchUpdateStreams)
-		o.POST("streams/:name", SaveStream)
-		o.POST("streams/:name/rename", RenameStream)
-		o.POST("streams/:name/enable", EnableStream)
-		o.POST("streams/:name/disable", DisableStream)
-		o.DELETE("streams/:name", DeleteStream)
-		o.POST("streams/:name/duplicate", Duplicate)
-		o.POST("streams/:name/advance", AdvancedEdit)
+		o.POST("streams/:name", middleware.RequireChangeSet(), SaveStream)
+		o.POST("streams/:name/rename", middleware.RequireChangeSet(), requireStreamNamespace(), RenameStream)
+		o.POST("streams/:name/enable", middleware.RequireChangeSet(), requireStreamNamespace(), EnableStream)
+		o.POST("streams/:name/disable", middleware.RequireChangeSet(), requireStreamNamespace(), DisableStream)
+		o.DELETE("streams/:name", middleware.RequireChangeSet(), requireStreamNamespace(), DeleteStream)
+		o.POST("streams/:name/duplicate", middleware.RequireChangeSet(), requireStreamNamespace(), Duplicate)
+		o.POST("streams/:name/advance", requireStreamNamespace(), AdvancedEdit)
 	}
 }
@@ -4,9 +4,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/0xJacky/Nginx-UI/api"
 	"github.com/0xJacky/Nginx-UI/internal/clustersync"
 	"github.com/0xJacky/Nginx-UI/internal/config"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/stream"
 	"github.com/0xJacky/Nginx-UI/internal/upstream"
@@ -132,7 +134,7 @@ func GetStreams(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": configs,
+		"data": filterStreamConfigs(c, configs),
 	})
 }
 
@@ -194,7 +196,15 @@ func SaveStream(c *gin.Context) {
 		namespaceID = clustersync.ResolveNamespaceIDByName(json.Namespace)
 	}
 
-	err := stream.SaveStreamConfig(name, json.Content, namespaceID, json.SyncNodeIDs, json.Overwrite, json.PostAction)
+	if !authorizeStreamNamespace(c, name) {
+		return
+	}
+	if !middleware.CurrentSubject(c).CanAccessNamespace(namespaceID) {
+		middleware.AbortWithNamespaceDenied(c)
+		return
+	}
+
+	err := stream.SaveStreamConfig(name, json.Content, namespaceID, json.SyncNodeIDs, json.Overwrite, json.PostAction, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -206,7 +216,7 @@ func SaveStream(c *gin.Context) {
 
 func EnableStream(c *gin.Context) {
 	// Enable the stream by creating a symlink in streams-enabled directory
-	err := stream.Enable(helper.UnescapeURL(c.Param("name")))
+	err := stream.Enable(helper.UnescapeURL(c.Param("name")), api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -219,7 +229,7 @@ func EnableStream(c *gin.Context) {
 
 func DisableStream(c *gin.Context) {
 	// Disable the stream by removing the symlink from streams-enabled directory
-	err := stream.Disable(helper.UnescapeURL(c.Param("name")))
+	err := stream.Disable(helper.UnescapeURL(c.Param("name")), api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -232,7 +242,7 @@ func DisableStream(c *gin.Context) {
 
 func DeleteStream(c *gin.Context) {
 	// Delete the stream configuration file and its symbolic link if exists
-	err := stream.Delete(helper.UnescapeURL(c.Param("name")))
+	err := stream.Delete(helper.UnescapeURL(c.Param("name")), api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -254,7 +264,7 @@ func RenameStream(c *gin.Context) {
 	}
 
 	// Rename the stream configuration file
-	err := stream.Rename(oldName, json.NewName)
+	err := stream.Rename(oldName, json.NewName, api.CommitAuthor(c))
 	if err != nil {
 		cosy.ErrHandler(c, err)
 		return
@@ -270,6 +280,18 @@ func BatchUpdateStreams(c *gin.Context) {
 		"namespace_id": "required",
 	}).SetItemKey("path").
 		BeforeExecuteHook(func(ctx *cosy.Ctx[model.Stream]) {
+			if !middleware.CurrentSubject(ctx.Context).CanAccessNamespace(ctx.Model.NamespaceID) {
+				middleware.AbortWithNamespaceDenied(ctx.Context)
+				ctx.Abort()
+				return
+			}
+			for _, name := range ctx.BatchEffectedIDs {
+				if !authorizeStreamNamespace(ctx.Context, name) {
+					ctx.Abort()
+					return
+				}
+			}
+
 			effectedPath := make([]string, len(ctx.BatchEffectedIDs))
 			var streams []*model.Stream
 			for i, name := range ctx.BatchEffectedIDs {
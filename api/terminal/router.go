@@ -5,6 +5,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// InitRouter expects a group that is not proxied yet, so namespace scoped
+// subjects are turned away before a remote node is reached.
 func InitRouter(r *gin.RouterGroup) {
-	r.GET("pty", middleware.RequireSecureSession(), Pty)
+	// A shell reaches every namespace.
+	o := r.Group("",
+		middleware.RequireUnscopedSubject(),
+		middleware.ProxyWs(),
+		middleware.RequireInteractiveUser(),
+		middleware.RequireSecureSession(),
+	)
+	{
+		o.GET("pty", Pty)
+	}
 }
@@ -0,0 +1,81 @@
+package upstream
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/api"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/0xJacky/Nginx-UI/internal/upstreamedit"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// GetBlock returns the structured form of an upstream block
+func GetBlock(c *gin.Context) {
+	result, err := upstreamedit.Get(helper.UnescapeURL(c.Param("name")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// SaveBlock rewrites an upstream block, then tests and reloads nginx
+func SaveBlock(c *gin.Context) {
+	var block upstream.Block
+	if !cosy.BindAndValid(c, &block) {
+		return
+	}
+
+	result, err := upstreamedit.Save(helper.UnescapeURL(c.Param("name")), &block, api.CommitAuthor(c))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetDrains returns the drains of the servers of an upstream
+func GetDrains(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": upstreamedit.GetDrains(helper.UnescapeURL(c.Param("name"))),
+	})
+}
+
+// DrainServer marks a server down and waits for its connections to finish
+func DrainServer(c *gin.Context) {
+	var json struct {
+		Address        string `json:"address" binding:"required"`
+		TimeoutSeconds int    `json:"timeout_seconds" binding:"min=0"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	status, err := upstreamedit.Drain(helper.UnescapeURL(c.Param("name")), json.Address,
+		time.Duration(json.TimeoutSeconds)*time.Second)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// UndrainServer brings a drained server back
+func UndrainServer(c *gin.Context) {
+	var json struct {
+		Address string `json:"address" binding:"required"`
+	}
+	if !cosy.BindAndValid(c, &json) {
+		return
+	}
+
+	result, err := upstreamedit.Undrain(helper.UnescapeURL(c.Param("name")), json.Address)
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
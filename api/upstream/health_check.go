@@ -0,0 +1,119 @@
+package upstream
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/cron"
+	"github.com/0xJacky/Nginx-UI/internal/upstreamhealth"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"github.com/uozi-tech/cosy/map2struct"
+)
+
+var healthCheckRules = gin.H{
+	"upstream":            "required",
+	"scheme":              "omitempty,oneof=http https",
+	"path":                "omitempty",
+	"host":                "omitempty",
+	"expected_status":     "omitempty",
+	"body_pattern":        "omitempty",
+	"verify_ssl":          "omitempty",
+	"interval_seconds":    "omitempty",
+	"timeout_seconds":     "omitempty",
+	"rise":                "omitempty",
+	"fall":                "omitempty",
+	"external_notify_ids": "omitempty",
+	"enabled":             "omitempty",
+}
+
+// normalizeHealthCheck validates the check as it will be stored. A partial
+// update is merged onto the stored check first.
+func normalizeHealthCheck(ctx *cosy.Ctx[model.UpstreamHealthCheck]) {
+	check := ctx.OriginModel
+	if err := map2struct.WeakDecode(ctx.Payload, &check); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	if err := upstreamhealth.Normalize(&check); err != nil {
+		ctx.AbortWithError(err)
+		return
+	}
+	ctx.Model = check
+	ctx.AddSelectedFields(upstreamhealth.NormalizedColumns...)
+}
+
+func scheduleHealthCheck(ctx *cosy.Ctx[model.UpstreamHealthCheck]) {
+	var err error
+	if ctx.Model.Enabled {
+		err = cron.AddOrUpdateUpstreamHealthJob(ctx.Model.ID, ctx.Model.IntervalSeconds)
+	} else {
+		err = cron.RemoveUpstreamHealthJob(ctx.Model.ID)
+	}
+	if err != nil {
+		ctx.AbortWithError(err)
+	}
+}
+
+func GetUpstreamHealthChecks(c *gin.Context) {
+	cosy.Core[model.UpstreamHealthCheck](c).
+		SetFussy("upstream").
+		SetEqual("enabled").
+		PagingList()
+}
+
+func GetUpstreamHealthCheck(c *gin.Context) {
+	cosy.Core[model.UpstreamHealthCheck](c).Get()
+}
+
+func CreateUpstreamHealthCheck(c *gin.Context) {
+	cosy.Core[model.UpstreamHealthCheck](c).
+		SetValidRules(healthCheckRules).
+		BeforeExecuteHook(normalizeHealthCheck).
+		ExecutedHook(scheduleHealthCheck).
+		Create()
+}
+
+func ModifyUpstreamHealthCheck(c *gin.Context) {
+	rules := gin.H{}
+	for field := range healthCheckRules {
+		rules[field] = "omitempty"
+	}
+	cosy.Core[model.UpstreamHealthCheck](c).
+		SetValidRules(rules).
+		BeforeExecuteHook(normalizeHealthCheck).
+		ExecutedHook(scheduleHealthCheck).
+		Modify()
+}
+
+// DestroyUpstreamHealthCheck deletes a check for good, so the upstream can be
+// checked again, and brings back the servers it marked down.
+func DestroyUpstreamHealthCheck(c *gin.Context) {
+	cosy.Core[model.UpstreamHealthCheck](c).BeforeExecuteHook(func(ctx *cosy.Ctx[model.UpstreamHealthCheck]) {
+		if err := cron.RemoveUpstreamHealthJob(ctx.Model.ID); err != nil {
+			logger.Errorf("Failed to remove upstream health job %d: %v", ctx.Model.ID, err)
+		}
+		if err := upstreamhealth.RemoveCheckState(&ctx.OriginModel); err != nil {
+			ctx.AbortWithError(err)
+		}
+	}).PermanentlyDelete()
+}
+
+// GetUpstreamServerHealth returns the health of the servers probed by a check
+func GetUpstreamServerHealth(c *gin.Context) {
+	states, err := upstreamhealth.GetServerHealth(cast.ToUint64(c.Param("id")))
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": states})
+}
+
+// GetUpstreamHealthEvents returns the recorded health changes of upstream servers
+func GetUpstreamHealthEvents(c *gin.Context) {
+	cosy.Core[model.UpstreamHealthEvent](c).
+		SetEqual("check_id", "upstream", "healthy").
+		PagingList()
+}
@@ -0,0 +1,34 @@
+package upstream
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/upstreamedit"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// requireUpstreamNamespace guards the routes addressing an upstream block by
+// name. A namespace scoped subject may only reach the blocks defined by the
+// sites and streams of its namespaces.
+func requireUpstreamNamespace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := middleware.CurrentSubject(c)
+		if !subject.IsNamespaceScoped() {
+			c.Next()
+			return
+		}
+
+		namespaceID, ok, err := upstreamedit.Namespace(helper.UnescapeURL(c.Param("name")))
+		if err != nil {
+			cosy.ErrHandler(c, err)
+			c.Abort()
+			return
+		}
+		if !ok || !subject.CanAccessNamespace(namespaceID) {
+			middleware.AbortWithNamespaceDenied(c)
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,12 +1,27 @@
 package upstream
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
 
 func InitHTTPRouter(r *gin.RouterGroup) {
 	r.GET("/upstream/availability", GetAvailability)
 	r.GET("/upstream/sockets", GetSocketList)
 	r.GET("/upstream/health_check/status", GetHealthCheckStatus)
-	r.PUT("/upstream/socket/:socket", UpdateSocketConfig)
+	r.PUT("/upstream/socket/:socket", middleware.RequireChangeSet(), UpdateSocketConfig)
+	r.GET("/upstream/blocks/:name", requireUpstreamNamespace(), GetBlock)
+	r.PUT("/upstream/blocks/:name", middleware.RequireChangeSet(), requireUpstreamNamespace(), SaveBlock)
+	r.GET("/upstream/blocks/:name/drains", requireUpstreamNamespace(), GetDrains)
+	r.POST("/upstream/blocks/:name/drain", middleware.RequireChangeSet(), requireUpstreamNamespace(), DrainServer)
+	r.POST("/upstream/blocks/:name/undrain", middleware.RequireChangeSet(), requireUpstreamNamespace(), UndrainServer)
+	r.GET("/upstream/health_checks", GetUpstreamHealthChecks)
+	r.GET("/upstream/health_checks/:id", GetUpstreamHealthCheck)
+	r.POST("/upstream/health_checks", middleware.RequireChangeSet(), CreateUpstreamHealthCheck)
+	r.POST("/upstream/health_checks/:id", middleware.RequireChangeSet(), ModifyUpstreamHealthCheck)
+	r.DELETE("/upstream/health_checks/:id", middleware.RequireChangeSet(), DestroyUpstreamHealthCheck)
+	r.GET("/upstream/health_checks/:id/servers", GetUpstreamServerHealth)
+	r.GET("/upstream/health_events", GetUpstreamHealthEvents)
 }
 
 func InitWebSocketRouter(r *gin.RouterGroup) {
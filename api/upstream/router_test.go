@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/0xJacky/Nginx-UI/settings"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
@@ -72,3 +73,28 @@ func TestRouteRegistrationSeparatesHTTPAndWebSocketEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestWritesRequireChangeSetWhileApprovalIsEnforced(t *testing.T) {
+	original := settings.ChangeSetSettings.RequireApproval
+	settings.ChangeSetSettings.RequireApproval = true
+	t.Cleanup(func() { settings.ChangeSetSettings.RequireApproval = original })
+
+	router := gin.New()
+	InitHTTPRouter(router.Group("/"))
+
+	for _, route := range []struct{ method, target string }{
+		{http.MethodPut, "/upstream/socket/127.0.0.1%3A8080"},
+		{http.MethodPut, "/upstream/blocks/backend"},
+		{http.MethodPost, "/upstream/blocks/backend/drain"},
+		{http.MethodPost, "/upstream/blocks/backend/undrain"},
+		{http.MethodPost, "/upstream/health_checks"},
+		{http.MethodPost, "/upstream/health_checks/1"},
+		{http.MethodDelete, "/upstream/health_checks/1"},
+	} {
+		req := httptest.NewRequest(route.method, route.target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Contains(t, w.Body.String(), `"code":40301`, route.method+" "+route.target)
+	}
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+var roleValidRules = gin.H{
+	"name":        "required,max=32",
+	"description": "omitempty",
+	"permissions": "required",
+}
+
+func GetRoles(c *gin.Context) {
+	roles, err := rbac.ListRoles()
+	if err != nil {
+		cosy.ErrHandler(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":      roles,
+		"resources": rbac.Resources,
+	})
+}
+
+func validateRole(ctx *cosy.Ctx[model.Role]) {
+	if err := rbac.ValidateRole(&ctx.Model, middleware.CurrentSubject(ctx.Context)); err != nil {
+		ctx.AbortWithError(err)
+	}
+}
+
+// authorizeRoleTarget keeps users from editing roles that hold more than they
+// do, since that changes the access of everyone assigned to the role.
+func authorizeRoleTarget(ctx *cosy.Ctx[model.Role]) {
+	if !middleware.CurrentSubject(ctx.Context).Covers(ctx.OriginModel.Permissions) {
+		ctx.AbortWithError(rbac.ErrPermissionEscalation)
+	}
+}
+
+func CreateRole(c *gin.Context) {
+	cosy.Core[model.Role](c).
+		SetValidRules(roleValidRules).
+		BeforeExecuteHook(validateRole).
+		Create()
+}
+
+func ModifyRole(c *gin.Context) {
+	cosy.Core[model.Role](c).
+		SetValidRules(roleValidRules).
+		BeforeDecodeHook(authorizeRoleTarget).
+		BeforeExecuteHook(validateRole, func(ctx *cosy.Ctx[model.Role]) {
+			if ctx.Model.Name == ctx.OriginModel.Name {
+				return
+			}
+			if err := rbac.EnsureRoleUnused(ctx.OriginModel.Name); err != nil {
+				ctx.AbortWithError(err)
+			}
+		}).
+		Modify()
+}
+
+func DestroyRole(c *gin.Context) {
+	cosy.Core[model.Role](c).
+		BeforeExecuteHook(authorizeRoleTarget, func(ctx *cosy.Ctx[model.Role]) {
+			if err := rbac.EnsureRoleUnused(ctx.OriginModel.Name); err != nil {
+				ctx.AbortWithError(err)
+			}
+		}).
+		Destroy()
+}
+
+// GetPermissions returns the role, permissions and namespace scope of the
+// caller so the frontend can hide what it cannot use.
+func GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, middleware.CurrentSubject(c))
+}
+
+func InitRoleRouter(r *gin.RouterGroup) {
+	r.GET("/roles", GetRoles)
+
+	o := r.Group("", middleware.RequireInteractiveUser(), middleware.RequireSecureSession())
+	{
+		o.POST("/roles", CreateRole)
+		o.POST("/roles/:id", ModifyRole)
+		o.DELETE("/roles/:id", DestroyRole)
+	}
+}
+
+func InitPermissionRouter(r *gin.RouterGroup) {
+	r.GET("/permissions", GetPermissions)
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/internal/user"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/settings"
@@ -29,11 +30,30 @@ func encryptPassword(ctx *cosy.Ctx[model.User]) {
 	}
 }
 
+// authorizeAssignment keeps users from handing out a role or namespace scope
+// beyond their own.
+func authorizeAssignment(ctx *cosy.Ctx[model.User], role string, namespaceIDs []uint64) {
+	if err := rbac.ValidateAssignment(ctx.ID, role, namespaceIDs, middleware.CurrentSubject(ctx.Context)); err != nil {
+		ctx.AbortWithError(err)
+	}
+}
+
+// authorizeTarget keeps users from editing accounts that hold more than they do.
+func authorizeTarget(ctx *cosy.Ctx[model.User]) {
+	authorizeAssignment(ctx, ctx.OriginModel.Role, ctx.OriginModel.NamespaceIDs)
+}
+
 func InitManageUserRouter(g *gin.RouterGroup) {
 	c := cosy.Api[model.User]("users")
 
 	c.CreateHook(func(c *cosy.Ctx[model.User]) {
 		c.BeforeDecodeHook(encryptPassword)
+		c.BeforeExecuteHook(func(ctx *cosy.Ctx[model.User]) {
+			if ctx.Model.Role == "" {
+				ctx.Model.Role = model.RoleAdmin
+			}
+			authorizeAssignment(ctx, ctx.Model.Role, ctx.Model.NamespaceIDs)
+		})
 	})
 
 	c.ModifyHook(func(c *cosy.Ctx[model.User]) {
@@ -42,7 +62,19 @@ func InitManageUserRouter(g *gin.RouterGroup) {
 				ctx.AbortWithError(user.ErrChangeInitUserPwdInDemo)
 			}
 		})
+		c.BeforeDecodeHook(authorizeTarget)
 		c.BeforeDecodeHook(encryptPassword)
+		c.BeforeExecuteHook(func(ctx *cosy.Ctx[model.User]) {
+			role := ctx.OriginModel.Role
+			if _, ok := ctx.Payload["role"]; ok {
+				role = ctx.Model.Role
+			}
+			namespaceIDs := ctx.OriginModel.NamespaceIDs
+			if _, ok := ctx.Payload["namespace_ids"]; ok {
+				namespaceIDs = ctx.Model.NamespaceIDs
+			}
+			authorizeAssignment(ctx, role, namespaceIDs)
+		})
 		c.ExecutedHook(func(ctx *cosy.Ctx[model.User]) {
 			user.InvalidateUserCache(ctx.ID)
 
@@ -63,6 +95,7 @@ func InitManageUserRouter(g *gin.RouterGroup) {
 				ctx.AbortWithError(user.ErrCannotRemoveInitUser)
 			}
 		})
+		c.BeforeExecuteHook(authorizeTarget)
 	})
 
 	c.BeforeCreate(middleware.RequireSecureSession())
@@ -46,6 +46,26 @@ export interface Cert extends ModelBase {
   last_error: string
   last_attempt_at: string | null
   self_signed_config?: SelfSignedCertConfig
+  ct_unexpected_issuers?: string[]
+  ocsp_status: '' | 'good' | 'revoked' | 'unknown'
+  ocsp_revoked_at?: string
+  ocsp_stapling: '' | 'ok' | 'missing'
+  ocsp_error?: string
+  ocsp_checked_at?: string
+}
+
+export interface CertDeployHook {
+  type: 'script' | 'copy' | 'bundle' | 'docker'
+  stage?: 'pre' | 'post'
+  command?: string
+  timeout?: number
+  destination?: string
+  owner?: string
+  mode?: string
+  key_mode?: string
+  format?: 'pem' | 'pkcs12'
+  password?: string
+  container?: string
 }
 
 export interface ImportExistingCertPayload {
@@ -129,6 +149,18 @@ const cert = extendCurdApi(useCurdApi<Cert>('/certs'), {
   modify_self_signed(id: number, payload: SelfSignedCertPayload): Promise<Cert> {
     return http.post(`/self_signed_cert/${id}`, payload)
   },
+  ocsp_check(id: number): Promise<Cert> {
+    return http.post(`/certs/${id}/ocsp_check`)
+  },
+  get_deploy_hooks(id: number): Promise<{ deploy_hooks: CertDeployHook[] }> {
+    return http.get(`/certs/${id}/deploy_hooks`)
+  },
+  set_deploy_hooks(id: number, deployHooks: CertDeployHook[]): Promise<{ deploy_hooks: CertDeployHook[] }> {
+    return http.post(`/certs/${id}/deploy_hooks`, { deploy_hooks: deployHooks })
+  },
+  run_deploy_hooks(id: number) {
+    return http.post(`/certs/${id}/deploy_hooks/run`)
+  },
 })
 
 export default cert
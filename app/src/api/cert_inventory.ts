@@ -0,0 +1,43 @@
+import { http } from '@uozi-admin/request'
+
+export type CertInventoryIssueKind = 'handshake_failed' | 'expired' | 'intermediate_expired'
+  | 'certificate_mismatch' | 'weak_key' | 'name_mismatch'
+
+export interface ServedCertificate {
+  subject: string
+  issuer: string
+  serial_number: string
+  dns_names: string[]
+  not_before: string
+  not_after: string
+  key_type: string
+  fingerprint: string
+}
+
+export interface CertInventoryEndpoint {
+  type: 'site' | 'stream'
+  config: string
+  address: string
+  server_name: string
+  certificates: string[]
+  chain: ServedCertificate[]
+  issues: { kind: CertInventoryIssueKind, message: string }[]
+}
+
+export interface CertInventoryReport {
+  scanned_at: string
+  summary: {
+    endpoints: number
+    healthy: number
+    issues: Partial<Record<CertInventoryIssueKind, number>>
+  }
+  certificates: (ServedCertificate & { endpoints: number })[]
+  endpoints: CertInventoryEndpoint[]
+}
+
+const certInventory = {
+  get: () => http.get<CertInventoryReport>('/cert_inventory'),
+  scan: () => http.post<CertInventoryReport>('/cert_inventory/scan'),
+}
+
+export default certInventory
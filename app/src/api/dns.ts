@@ -34,10 +34,28 @@ export interface DNSRecordLine {
   father_code?: string
 }
 
+export type DDNSIPSourceType
+  = | 'public'
+    | 'interface'
+    | 'command'
+    | 'url'
+    | 'webhook'
+
+export interface DDNSIPSource {
+  type: DDNSIPSourceType
+  interface?: string
+  ipv6_scope?: 'gua' | 'ula'
+  prefix?: string
+  command?: string
+  url?: string
+  regex?: string
+}
+
 export interface DDNSRecordTarget {
   id: string
   name: string
   type: string
+  source?: DDNSIPSource
 }
 
 export type DDNSIPVersion
@@ -57,6 +75,13 @@ export interface DDNSConfig {
   last_ipv6?: string
   last_run_at?: string
   last_error?: string
+  source?: DDNSIPSource
+  webhook_enabled: boolean
+  webhook_token?: string
+  webhook_ipv4?: string
+  webhook_ipv6?: string
+  webhook_at?: string
+  wait_propagation: boolean
 }
 
 export interface DDNSDomainItem {
@@ -73,6 +98,10 @@ export interface UpdateDDNSPayload {
   ip_version: DDNSIPVersion
   cleanup_conflicting_records: boolean
   record_ids: string[]
+  source?: DDNSIPSource
+  target_sources?: Record<string, DDNSIPSource>
+  webhook_enabled?: boolean
+  wait_propagation?: boolean
 }
 
 export interface DomainListParams {
@@ -101,6 +130,88 @@ export interface RecordPayload {
   comment?: string
 }
 
+export interface DNSZoneFile {
+  domain: string
+  content: string
+}
+
+export type DNSZoneChangeAction = 'add' | 'update' | 'delete'
+
+export interface DNSZoneChange {
+  action: DNSZoneChangeAction
+  name: string
+  type: string
+  record_id?: string
+  before?: DNSRecord
+  after?: RecordPayload
+  error?: string
+}
+
+export interface DNSZoneSyncResult {
+  changes: DNSZoneChange[]
+  unchanged: number
+  skipped: string[]
+  domain_id: number
+  dry_run: boolean
+  applied: number
+  failed: number
+}
+
+export interface ZoneSyncOptions {
+  dry_run?: boolean
+  delete_missing?: boolean
+}
+
+export interface ZoneImportPayload extends ZoneSyncOptions {
+  content: string
+}
+
+export interface ZoneCopyPayload extends ZoneSyncOptions {
+  target_credential_id: number
+}
+
+export interface PropagationParams {
+  name: string
+  type: string
+  expected?: string[]
+  wait?: boolean
+  timeout?: number
+}
+
+export interface NameserverAnswer {
+  nameserver: string
+  address?: string
+  values: string[]
+  ttl: number
+  serial: number
+  rcode?: string
+  authoritative: boolean
+  propagated: boolean
+  error?: string
+}
+
+export interface DNSSECStatus {
+  signed: boolean
+  valid: boolean
+  ds_matched: boolean
+  keys_signed: boolean
+  answer_signed: boolean
+  key_tags?: number[]
+  errors?: string[]
+}
+
+export interface PropagationReport {
+  name: string
+  type: string
+  zone: string
+  expected?: string[]
+  nameservers: NameserverAnswer[]
+  propagated: boolean
+  consistent: boolean
+  dnssec: DNSSECStatus
+  checked_at: string
+}
+
 const baseDomainUrl = '/dns/domains'
 
 const domainApi = useCurdApi<DNSDomain>(baseDomainUrl)
@@ -134,6 +245,23 @@ export const dnsApi = {
   deleteDDNSConfig(domainId: number) {
     return http.delete(`${baseDomainUrl}/${domainId}/ddns`)
   },
+  rotateDDNSWebhookToken(domainId: number) {
+    return http.post<DDNSConfig>(`${baseDomainUrl}/${domainId}/ddns/webhook-token`)
+  },
+  exportZone(domainId: number) {
+    return http.get<DNSZoneFile>(`${baseDomainUrl}/${domainId}/zone`)
+  },
+  importZone(domainId: number, payload: ZoneImportPayload) {
+    return http.post<DNSZoneSyncResult>(`${baseDomainUrl}/${domainId}/zone/import`, payload)
+  },
+  copyZone(domainId: number, payload: ZoneCopyPayload) {
+    return http.post<DNSZoneSyncResult>(`${baseDomainUrl}/${domainId}/zone/copy`, payload)
+  },
+  checkPropagation(domainId: number, params: PropagationParams) {
+    return http.get<PropagationReport>(`${baseDomainUrl}/${domainId}/propagation`, {
+      params: { ...params, expected: params.expected?.join(',') },
+    })
+  },
 }
 
 export type { DnsCredential }
@@ -27,6 +27,13 @@ export const SyncStrategy = {
   Auto: 'auto',
 } as const
 
+// DNS record provisioning policies for site server names
+export const DNSProvisioning = {
+  Manual: 'manual',
+  Auto: 'auto',
+  Off: 'off',
+} as const
+
 export interface Namespace extends ModelBase {
   name: string
   sync_node_ids: number[]
@@ -35,6 +42,11 @@ export interface Namespace extends ModelBase {
   deploy_mode?: string
   sync_strategy?: string
   sync_interval_minutes?: number
+  dns_provisioning?: string
+  dns_target_ipv4?: string
+  dns_target_ipv6?: string
+  dns_target_cname?: string
+  dns_delete_with_site?: boolean
 }
 
 const baseUrl = '/namespaces'
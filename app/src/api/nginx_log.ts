@@ -21,6 +21,9 @@ export interface NginxLogData {
   retry_count?: number
   queue_position?: number
   partial_offset?: number
+  // Retention of raw documents, 0 keeps them forever
+  retention_days?: number
+  purged_before?: number
 }
 
 export interface AnalyticsRequest {
@@ -51,6 +54,8 @@ export interface AccessLogEntry {
   request_time?: number
   upstream_time?: number
   raw: string
+  // Custom log_format variables, e.g. "vars.upstream_addr"
+  [key: `vars.${string}`]: string | undefined
 }
 
 export interface LogStats {
@@ -117,6 +122,7 @@ export interface LogAnalyticsResponse {
 
 export interface SearchFilters {
   query: string
+  expression: string
   ip: string
   method: string
   status: string[]
@@ -132,6 +138,8 @@ export interface AdvancedSearchRequest {
   start_time?: number
   end_time?: number
   query?: string
+  // Query language expression, e.g. `status:>=500 AND NOT ip:10.0.0.0/8`
+  expression?: string
   ip?: string
   method?: string
   status?: number[]
@@ -146,6 +154,21 @@ export interface AdvancedSearchRequest {
   sort_by?: string
   sort_order?: string
   log_path?: string
+  vars?: Record<string, string[]>
+  var_facets?: string[]
+}
+
+export interface FacetTerm {
+  term: string
+  count: number
+}
+
+export interface Facet {
+  field: string
+  total: number
+  missing: number
+  other: number
+  terms: FacetTerm[]
 }
 
 export interface SummaryStats {
@@ -163,6 +186,7 @@ export interface AdvancedSearchResponse {
   took: number
   query: string
   summary: SummaryStats
+  var_facets?: Record<string, Facet>
 }
 
 export interface PreflightResponse {
@@ -255,6 +279,7 @@ export interface DashboardSummary {
   peak_hour_traffic: number
   avg_qps: number // Requests per second across the queried range
   peak_qps: number // Busiest minute of the range, expressed per second
+  rollup_before?: number // Figures before this time come from daily rollups
 }
 
 export interface DashboardAnalytics {
@@ -309,6 +334,127 @@ export interface GeoStats {
   percent: number
 }
 
+// Error log analytics types
+export type ErrorLogLevel = 'debug' | 'info' | 'notice' | 'warn' | 'error' | 'crit' | 'alert' | 'emerg'
+
+export interface ErrorLogEntry {
+  timestamp: number
+  level: ErrorLogLevel
+  pid: number
+  tid: number
+  connection_id: number
+  message: string
+  message_pattern: string
+  client: string
+  server: string
+  request: string
+  method: string
+  path: string
+  upstream: string
+  host: string
+  referrer: string
+  raw: string
+  file_path: string
+  main_log_path: string
+}
+
+export interface ErrorLogSearchRequest {
+  query?: string
+  log_path?: string
+  start_time?: number
+  end_time?: number
+  levels?: ErrorLogLevel[]
+  clients?: string[]
+  servers?: string[]
+  upstreams?: string[]
+  hosts?: string[]
+  message_patterns?: string[]
+  limit?: number
+  offset?: number
+  sort_order?: 'asc' | 'desc'
+  size?: number // Number of message clusters, top messages only
+}
+
+export interface ErrorLogSearchResponse {
+  entries: ErrorLogEntry[]
+  total: number
+  took: number
+  facets?: Record<string, Facet>
+}
+
+export interface ErrorMessageCluster {
+  pattern: string
+  count: number
+  sample: string
+  sample_raw: string
+  level: ErrorLogLevel
+  last_seen: number
+}
+
+export interface ErrorTimelineRequest {
+  log_path?: string
+  start_time: number
+  end_time: number
+  interval?: number // Bucket size in seconds, omitted to pick one from the range
+  servers?: string[]
+}
+
+export interface ErrorTimelinePoint {
+  timestamp: number
+  total: number
+  severe: number
+  rate: number // Lines per minute
+}
+
+export interface ErrorTimeline {
+  interval: number
+  total: number
+  severe: number // Lines at level error or above
+  levels: Record<string, number>
+  peak_rate: number
+  points: ErrorTimelinePoint[]
+}
+// Export types
+export type ExportFormat = 'csv' | 'ndjson' | 'parquet'
+
+export interface ExportLogsRequest extends AdvancedSearchRequest {
+  format?: ExportFormat
+  background?: boolean // Run as a job reporting nginx_log_export_progress events
+}
+
+export interface ExportDashboardRequest extends DashboardRequest {
+  format?: ExportFormat
+  table?: string // e.g. top_urls, all tables if omitted, required for Parquet
+}
+
+export interface ExportGeoRequest extends AnalyticsRequest {
+  format?: ExportFormat
+}
+
+export type ExportJobStatus = 'running' | 'completed' | 'failed' | 'cancelled'
+
+export interface ExportJob {
+  id: string
+  user_id: number
+  format: ExportFormat
+  status: ExportJobStatus
+  written: number
+  total: number
+  size: number // Bytes of the export file
+  error?: string
+  created_at: string
+  finished_at?: string
+}
+
+export interface ExportProgressData {
+  id: string
+  status: ExportJobStatus
+  written: number
+  total: number
+  progress: number // 0-100 percentage
+  error?: string
+}
+
 const nginx_log = extendCurdApi(useCurdApi('/nginx_logs'), {
   page(page = 0, data: NginxLogData | undefined = undefined) {
     return http.post(`/nginx_log/page?page=${page}`, data)
@@ -322,6 +468,51 @@ const nginx_log = extendCurdApi(useCurdApi('/nginx_logs'), {
     return http.post('/nginx_log/search', data)
   },
 
+  // Export APIs, downloads resolve to the full response to read the file name
+  exportLogs(data: ExportLogsRequest) {
+    return http.post('/nginx_log/export', data, {
+      responseType: 'blob',
+      returnFullResponse: true,
+    })
+  },
+
+  startExportJob(data: Omit<ExportLogsRequest, 'background'>): Promise<ExportJob> {
+    return http.post('/nginx_log/export', { ...data, background: true })
+  },
+
+  exportDashboard(data: ExportDashboardRequest) {
+    return http.post('/nginx_log/export/dashboard', data, {
+      responseType: 'blob',
+      returnFullResponse: true,
+    })
+  },
+
+  exportGeo(data: ExportGeoRequest) {
+    return http.post('/nginx_log/export/geo', data, {
+      responseType: 'blob',
+      returnFullResponse: true,
+    })
+  },
+
+  getExportJobs(): Promise<{ data: ExportJob[] }> {
+    return http.get('/nginx_log/export/jobs')
+  },
+
+  getExportJob(id: string): Promise<ExportJob> {
+    return http.get(`/nginx_log/export/jobs/${id}`)
+  },
+
+  downloadExportJob(id: string) {
+    return http.get(`/nginx_log/export/jobs/${id}/download`, {
+      responseType: 'blob',
+      returnFullResponse: true,
+    })
+  },
+
+  deleteExportJob(id: string) {
+    return http.delete(`/nginx_log/export/jobs/${id}`)
+  },
+
   getPreflight(logPath?: string): Promise<PreflightResponse> {
     const params = logPath ? { log_path: logPath } : {}
     return http.get('/nginx_log/preflight', { params })
@@ -336,6 +527,10 @@ const nginx_log = extendCurdApi(useCurdApi('/nginx_logs'), {
     return http.post('/nginx_log/index/rebuild', { path })
   },
 
+  setRetention(path: string, retentionDays: number): Promise<{ message: string }> {
+    return http.post('/nginx_log/index/retention', { path, retention_days: retentionDays })
+  },
+
   // Dashboard analytics API
   getDashboardAnalytics(data: DashboardRequest): Promise<DashboardAnalytics> {
     return http.post('/nginx_log/dashboard', data)
@@ -354,6 +549,19 @@ const nginx_log = extendCurdApi(useCurdApi('/nginx_logs'), {
     return http.post('/nginx_log/geo/stats', data)
   },
 
+  // Error log analytics APIs
+  searchErrorLogs(data: ErrorLogSearchRequest): Promise<ErrorLogSearchResponse> {
+    return http.post('/nginx_log/errors/search', data)
+  },
+
+  getTopErrorMessages(data: ErrorLogSearchRequest): Promise<{ messages: ErrorMessageCluster[] }> {
+    return http.post('/nginx_log/errors/top_messages', data)
+  },
+
+  getErrorTimeline(data: ErrorTimelineRequest): Promise<ErrorTimeline> {
+    return http.post('/nginx_log/errors/timeline', data)
+  },
+
   // Advanced indexing settings APIs
   enableAdvancedIndexing(): Promise<{ message: string }> {
     return http.post('/nginx_log/settings/advanced_indexing/enable')
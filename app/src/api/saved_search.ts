@@ -0,0 +1,55 @@
+import type { AccessLogEntry } from '@/api/nginx_log'
+import type { ModelBase } from './curd'
+import { extendCurdApi, http, useCurdApi } from '@uozi-admin/request'
+
+export interface SavedSearch extends ModelBase {
+  user_id: number
+  name: string
+  description: string
+  log_path: string
+  query: string
+  expression: string
+  time_range: number // Look-back window in seconds, 0 searches all indexed logs
+  sort_by: string
+  sort_order: 'asc' | 'desc' | ''
+  share_token?: string
+}
+
+// An explicit start or end time overrides the time range of the saved search
+export interface SavedSearchRunOptions {
+  start_time?: number
+  end_time?: number
+  limit?: number
+  offset?: number
+}
+
+export interface SavedSearchRunResponse {
+  search: SavedSearch
+  entries: AccessLogEntry[]
+  total: number
+  took: number
+}
+
+const savedSearch = extendCurdApi(useCurdApi<SavedSearch>('/nginx_log/saved_searches'), {
+  run(id: number, options: SavedSearchRunOptions = {}): Promise<SavedSearchRunResponse> {
+    return http.post(`/nginx_log/saved_searches/${id}/run`, options)
+  },
+
+  share(id: number): Promise<SavedSearch> {
+    return http.post(`/nginx_log/saved_searches/${id}/share`)
+  },
+
+  unshare(id: number): Promise<SavedSearch> {
+    return http.delete(`/nginx_log/saved_searches/${id}/share`)
+  },
+
+  getShared(token: string): Promise<SavedSearch> {
+    return http.get(`/nginx_log/saved_searches/shared/${encodeURIComponent(token)}`)
+  },
+
+  runShared(token: string, options: SavedSearchRunOptions = {}): Promise<SavedSearchRunResponse> {
+    return http.post(`/nginx_log/saved_searches/shared/${encodeURIComponent(token)}/run`, options)
+  },
+})
+
+export default savedSearch
@@ -47,6 +47,9 @@ export interface CertSettings {
   renewal_interval: number
   recursive_nameservers: string[]
   http_challenge_port: string
+  ct_log_source: string
+  ct_monitor_interval: number
+  dns_propagation_check: boolean
 }
 
 export interface HTTPSettings {
@@ -137,6 +140,16 @@ export interface OIDCSettings {
   identifier: string
 }
 
+export interface ChangeSetSettings {
+  require_approval: boolean
+  expire_hours: number
+}
+
+export interface GitHistorySettings {
+  enabled: boolean
+  repository_path: string
+}
+
 export interface Settings {
   app: AppSettings
   server: ServerSettings
@@ -145,6 +158,8 @@ export interface Settings {
   casdoor: CasdoorSettings
   oidc: OIDCSettings
   cert: CertSettings
+  change_set: ChangeSetSettings
+  git_history: GitHistorySettings
   http: HTTPSettings
   logrotate: LogrotateSettings
   nginx: NginxSettings
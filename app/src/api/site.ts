@@ -18,6 +18,32 @@ export interface SiteDNSRecord {
   name: string
   type: string
   exists: boolean
+  domain_id?: number
+  managed?: boolean
+}
+
+export interface SiteDNSTarget {
+  ipv4?: string
+  ipv6?: string
+  cname?: string
+}
+
+export interface SiteDNSChange {
+  action: 'create' | 'rename' | 'exists' | 'conflict'
+  host: string
+  domain_id: number
+  domain: string
+  name: string
+  type: string
+  content: string
+  record_id?: string
+  previous?: string
+  error?: string
+}
+
+export interface SiteDNSPlan {
+  changes: SiteDNSChange[]
+  unmatched: string[]
 }
 
 export interface Site extends ModelBase {
@@ -80,6 +106,8 @@ const site = extendCurdApi(useCurdApi<Site>(baseUrl), {
   advance_mode: (name: string, data: { advanced: boolean }) => http.post(`${baseUrl}/${encodeURIComponent(name)}/advance`, data),
   enableMaintenance: (name: string) => http.post(`${baseUrl}/${encodeURIComponent(name)}/maintenance`),
   getLogs: (name: string) => http.get<{ logs: SiteLog[] }>(`${baseUrl}/${encodeURIComponent(name)}/logs`),
+  getDNSPlan: (name: string, target?: SiteDNSTarget) => http.get<SiteDNSPlan>(`${baseUrl}/${encodeURIComponent(name)}/dns/plan`, { params: target }),
+  provisionDNS: (name: string, target?: SiteDNSTarget) => http.post<SiteDNSPlan>(`${baseUrl}/${encodeURIComponent(name)}/dns/provision`, target ?? {}),
 })
 
 export default site
@@ -2,7 +2,20 @@ import type { DNSProvider } from '@/api/auto_cert'
 
 // Providers with backend DNS record management implementations.
 // This list does not limit ACME DNS-01 credential providers.
-export const ALLOWED_DNS_PROVIDER_CODES = ['alidns', 'tencentcloud', 'cloudflare', 'azuredns', 'huaweicloud'] as const
+export const ALLOWED_DNS_PROVIDER_CODES = [
+  'alidns',
+  'tencentcloud',
+  'cloudflare',
+  'azuredns',
+  'huaweicloud',
+  'route53',
+  'digitalocean',
+  'hetzner',
+  'gandiv5',
+  'pdns',
+  'dnsupdate',
+  'rfc2136',
+] as const
 
 type DNSProviderIdentifier = Pick<DNSProvider, 'code' | 'provider' | 'name'> | null
 
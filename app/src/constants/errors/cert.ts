@@ -47,4 +47,14 @@ export default {
   50053: () => $gettext('IP address certificates require the HTTP-01 challenge'),
   50054: () => $gettext('Certificate profile is not available from the selected ACME server: {0}'),
   50055: () => $gettext('Wildcard domains and IP addresses cannot be requested in the same certificate'),
+  50056: () => $gettext('Create certificate authority error: {0}'),
+  50057: () => $gettext('Certificate authority {0} is invalid: {1}'),
+  50058: () => $gettext('Certificate authority {0} still has intermediates or issued certificates'),
+  50059: () => $gettext('Certificate authority {0} has expired'),
+  50060: () => $gettext('Invalid certificate usage: {0}'),
+  50061: () => $gettext('Certificate is not issued by a private certificate authority'),
+  50062: () => $gettext('Sign CRL error: {0}'),
+  50063: () => $gettext('Invalid email address: {0}'),
+  50064: () => $gettext('Invalid deploy hook {0}: {1}'),
+  50065: () => $gettext('Deploy hook {0} failed: {1}'),
 }
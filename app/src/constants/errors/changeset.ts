@@ -0,0 +1,16 @@
+export default {
+  40001: () => $gettext('Change set has no items'),
+  40002: () => $gettext('Unknown change set item kind: {0}'),
+  40003: () => $gettext('File is changed more than once: {0}'),
+  40004: () => $gettext('Change set is not pending'),
+  40005: () => $gettext('Change set has expired'),
+  40006: () => $gettext('{0} items cannot {1}'),
+  40007: () => $gettext('A new name is required to {0} {1}'),
+  40301: () => $gettext('A change set must be approved by another user'),
+  40901: () => $gettext('File changed since the change set was created: {0}'),
+  50001: () => $gettext('Nginx test failed: {0}'),
+  50002: () => $gettext('Failed to apply change set: {0}'),
+  50003: () => $gettext('Nginx rejected the change set: {0}'),
+  50004: () => $gettext('Failed to reload nginx: {0}'),
+  50005: () => $gettext('Failed to {0} {1}: {2}'),
+}
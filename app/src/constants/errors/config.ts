@@ -3,6 +3,7 @@ export default {
   40015: () => $gettext('File content must be valid UTF-8 text'),
   40016: () => $gettext('File content contains invalid control characters'),
   40017: () => $gettext('File content contains restricted nginx directive: {0}'),
+  40301: () => $gettext('Reading certificates and keys requires write access to certificates'),
   50006: () => $gettext('Path: {0} is not under the nginx conf dir: {1}'),
   50007: () => $gettext('Destination file: {0} already exists'),
   50008: () => $gettext('Nginx test failed: {0}'),
@@ -0,0 +1,6 @@
+export default {
+  40001: () => $gettext('Invalid CT log source: {0}'),
+  50001: () => $gettext('Query CT log source error: {0}'),
+  50002: () => $gettext('CT log source responded with status {0}'),
+  50003: () => $gettext('Decode CT log source response error: {0}'),
+}
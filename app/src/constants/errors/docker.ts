@@ -14,4 +14,5 @@ export default {
   500013: () => $gettext('Failed to start temp container: {0}'),
   500014: () => $gettext('Could not find old container name'),
   500015: () => $gettext('Could not find temp container'),
+  500016: () => $gettext('Failed to copy files to container: {0}'),
 }
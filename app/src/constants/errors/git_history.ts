@@ -0,0 +1,9 @@
+export default {
+  40001: () => $gettext('Git history is disabled'),
+  40002: () => $gettext('Invalid revision: {0}'),
+  40003: () => $gettext('Path is not under the nginx configuration directory: {0}'),
+  50001: () => $gettext('Git executable not found'),
+  50002: () => $gettext('Git {0} failed: {1}'),
+  50003: () => $gettext('Nginx test failed, the rollback was reverted: {0}'),
+  50004: () => $gettext('Nginx reload failed, the rollback was reverted: {0}'),
+}
@@ -0,0 +1,12 @@
+export default {
+  40001: () => $gettext('A jail needs at least one filter condition'),
+  40002: () => $gettext('Invalid {0}: {1}'),
+  40003: () => $gettext('Log path is not under the whitelist: {0}'),
+  40004: () => $gettext('Unknown log type: {0}'),
+  40005: () => $gettext('{0} can only be matched in access logs'),
+  40006: () => $gettext('Invalid site name: {0}'),
+  40007: () => $gettext('Invalid ignore IP: {0}'),
+  40008: () => $gettext('{0} must be positive'),
+  50001: () => $gettext('Nginx test failed, the deny list was restored: {0}'),
+  50002: () => $gettext('Nginx reload failed: {0}'),
+}
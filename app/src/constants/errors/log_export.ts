@@ -0,0 +1,8 @@
+export default {
+  40001: () => $gettext('Unsupported export format: {0}'),
+  40002: () => $gettext('Unknown export table: {0}'),
+  40003: () => $gettext('The export job has not finished'),
+  40004: () => $gettext('Too many export jobs are running, try again later'),
+  40401: () => $gettext('Export job not found'),
+  50001: () => $gettext('The log searcher is not available'),
+}
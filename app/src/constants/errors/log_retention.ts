@@ -0,0 +1,4 @@
+export default {
+  40001: () => $gettext('Retention days must not be negative'),
+  40401: () => $gettext('The log is not indexed: {0}'),
+}
@@ -0,0 +1,10 @@
+export default {
+  40001: () => $gettext('Invalid manifest: {0}'),
+  40002: () => $gettext('Unsupported manifest version: {0}'),
+  40003: () => $gettext('{0} entry is missing a name'),
+  40004: () => $gettext('{0} is declared more than once: {1}'),
+  40005: () => $gettext('{0} {1} references undeclared namespace {2}'),
+  40006: () => $gettext('{0} {1} has an invalid {2}'),
+  50001: () => $gettext('Nginx test failed, manifest was rolled back: {0}'),
+  50002: () => $gettext('Nginx reload failed, manifest was rolled back: {0}'),
+}
@@ -3,4 +3,5 @@ export default {
   40001: () => $gettext('Decryption failed'),
   40002: () => $gettext('Form parse failed'),
   40300: () => $gettext('This action is disabled in demo mode'),
+  40301: () => $gettext('Direct edits are disabled, submit a change set for approval instead'),
 }
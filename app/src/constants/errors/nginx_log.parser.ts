@@ -3,4 +3,7 @@ export default {
   50102: () => $gettext('Log line exceeds maximum length'),
   50103: () => $gettext('Unsupported log format'),
   50104: () => $gettext('Invalid timestamp format'),
+  50105: () => $gettext('Invalid log format {0}: {1}'),
+  50106: () => $gettext('Log line does not match log format'),
+  50107: () => $gettext('Invalid error log line'),
 }
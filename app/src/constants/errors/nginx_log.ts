@@ -24,4 +24,5 @@ export default {
   50026: () => $gettext('Modern searcher service not available'),
   50027: () => $gettext('Modern analytics service not available'),
   50028: () => $gettext('Modern indexer service not available'),
+  50029: () => $gettext('Error log index not available'),
 }
@@ -0,0 +1,8 @@
+export default {
+  40001: () => $gettext('Certificate has no OCSP responder'),
+  40002: () => $gettext('Issuer certificate of {0} not found'),
+  50001: () => $gettext('Read certificate error: {0}'),
+  50002: () => $gettext('Query OCSP responder error: {0}'),
+  50003: () => $gettext('OCSP responder responded with status {0}'),
+  50004: () => $gettext('Parse OCSP response error: {0}'),
+}
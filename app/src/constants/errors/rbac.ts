@@ -0,0 +1,10 @@
+export default {
+  40301: () => $gettext('Permission denied'),
+  40302: () => $gettext('Namespace access denied'),
+  40001: () => $gettext('Invalid permission: {0}'),
+  40401: () => $gettext('Role not found: {0}'),
+  40002: () => $gettext('Role name is reserved by a built-in role'),
+  40003: () => $gettext('Role is assigned to users'),
+  40004: () => $gettext('The initial user must keep the admin role'),
+  40303: () => $gettext('Cannot grant permissions you do not hold'),
+}
@@ -0,0 +1,7 @@
+export default {
+  40001: () => $gettext('Invalid query expression: {0}'),
+  40002: () => $gettext('Log path is not under the whitelist: {0}'),
+  40003: () => $gettext('Time range must not be negative'),
+  40004: () => $gettext('Sort order must be asc or desc'),
+  50001: () => $gettext('The log searcher is not available'),
+}
@@ -0,0 +1,15 @@
+export default {
+  40001: () => $gettext('Site {0} already has a running deployment'),
+  40002: () => $gettext('No proxy_pass to {0} without a URI was found in the site'),
+  40003: () => $gettext('Invalid target: {0}, use an upstream name or an address with port'),
+  40004: () => $gettext('The new target must differ from the old one'),
+  40005: () => $gettext('Steps must be increasing percentages ending at 100'),
+  40006: () => $gettext('{0} must not be negative'),
+  40007: () => $gettext('Log path is not under the whitelist: {0}'),
+  40008: () => $gettext('Site {0} is deployed to remote nodes only'),
+  40009: () => $gettext('Site {0} is not enabled'),
+  40010: () => $gettext('The deployment is not running'),
+  40011: () => $gettext('Unknown deployment strategy: {0}'),
+  50001: () => $gettext('Nginx test failed, the configuration was restored: {0}'),
+  50002: () => $gettext('Nginx reload failed, the configuration was restored: {0}'),
+}
@@ -0,0 +1,8 @@
+export default {
+  40001: () => $gettext('Unknown traffic alert metric: {0}'),
+  40002: () => $gettext('Log path is not under the whitelist: {0}'),
+  40003: () => $gettext('The recovery threshold must not exceed the threshold'),
+  40004: () => $gettext('Percentile must be between 0 and 100'),
+  40005: () => $gettext('{0} must not be negative'),
+  50001: () => $gettext('The log searcher is not available'),
+}
@@ -0,0 +1,11 @@
+export default {
+  40001: () => $gettext('Invalid upstream block: {0}'),
+  40002: () => $gettext('The upstream cannot be renamed'),
+  40003: () => $gettext('The upstream is not defined under the nginx configuration directory'),
+  40004: () => $gettext('Connections to {0} cannot be counted'),
+  40401: () => $gettext('Upstream {0} not found'),
+  40402: () => $gettext('Server {0} not found in the upstream'),
+  50001: () => $gettext('Failed to parse {0}: {1}'),
+  50002: () => $gettext('Nginx test failed, the configuration was restored: {0}'),
+  50003: () => $gettext('Nginx reload failed, the configuration was restored: {0}'),
+}
@@ -0,0 +1,8 @@
+export default {
+  40001: () => $gettext('Unsupported scheme: {0}, use http or https'),
+  40002: () => $gettext('The path must start with /'),
+  40003: () => $gettext('Invalid body pattern: {0}'),
+  40004: () => $gettext('Invalid status code: {0}'),
+  40005: () => $gettext('{0} must not be negative'),
+  40006: () => $gettext('Invalid upstream name: {0}'),
+}
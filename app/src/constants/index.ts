@@ -55,6 +55,12 @@ export const SyncStrategyMask = {
   auto: () => $gettext('Automatic'),
 } as const
 
+export const DNSProvisioningMask = {
+  manual: () => $gettext('Ask'),
+  auto: () => $gettext('Automatic'),
+  off: () => $gettext('Off'),
+} as const
+
 export const PrivateKeyTypeMask = {
   2048: 'RSA2048',
   3072: 'RSA3072',
@@ -23,7 +23,7 @@ const columns: StdTableColumn[] = [{
 }, {
   title: () => $gettext('Type'),
   dataIndex: 'auto_cert',
-  customRender: ({ text }: CustomRenderArgs) => {
+  customRender: ({ text, record }: CustomRenderArgs) => {
     const template: JSXElements = []
     const sync = $gettext('Sync Certificate')
     const managed = $gettext('Managed Certificate')
@@ -57,6 +57,29 @@ const columns: StdTableColumn[] = [{
         </Tag>,
       )
     }
+    if (record.ct_unexpected_issuers?.length) {
+      template.push(
+        <Tooltip title={record.ct_unexpected_issuers.join('\n')}>
+          <Tag bordered={false} color="warning">
+            {$gettext('Unexpected Issuer')}
+          </Tag>
+        </Tooltip>,
+      )
+    }
+    if (record.ocsp_status === 'revoked') {
+      template.push(
+        <Tag bordered={false} color="error">
+          {$gettext('Revoked')}
+        </Tag>,
+      )
+    }
+    if (record.must_staple && record.ocsp_stapling === 'missing') {
+      template.push(
+        <Tag bordered={false} color="warning">
+          {$gettext('OCSP Stapling Missing')}
+        </Tag>,
+      )
+    }
     return h('div', template)
   },
   sorter: true,
@@ -1,7 +1,7 @@
 import type { StdTableColumn } from '@uozi-admin/curd'
 import { datetimeRender, maskRender } from '@uozi-admin/curd'
-import { DeployMode, PostSyncAction, SyncStrategy, UpstreamTestType } from '@/api/namespace'
-import { DeployModeMask, PostSyncActionMask, SyncStrategyMask, UpstreamTestTypeMask } from '@/constants'
+import { DeployMode, DNSProvisioning, PostSyncAction, SyncStrategy, UpstreamTestType } from '@/api/namespace'
+import { DeployModeMask, DNSProvisioningMask, PostSyncActionMask, SyncStrategyMask, UpstreamTestTypeMask } from '@/constants'
 import { useNodeAvailabilityStore } from '@/pinia/moudule/nodeAvailability'
 
 const columns: StdTableColumn[] = [{
@@ -106,6 +106,51 @@ const columns: StdTableColumn[] = [{
   },
   hiddenInTable: true,
   pure: true,
+}, {
+  title: () => $gettext('DNS Provisioning'),
+  dataIndex: 'dns_provisioning',
+  customRender: maskRender(DNSProvisioningMask),
+  edit: {
+    type: 'select',
+    select: {
+      mask: DNSProvisioningMask,
+      defaultValue: DNSProvisioning.Manual,
+    },
+  },
+  pure: true,
+  width: 120,
+}, {
+  title: () => $gettext('DNS Target IPv4'),
+  dataIndex: 'dns_target_ipv4',
+  edit: {
+    type: 'input',
+  },
+  hiddenInTable: true,
+  pure: true,
+}, {
+  title: () => $gettext('DNS Target IPv6'),
+  dataIndex: 'dns_target_ipv6',
+  edit: {
+    type: 'input',
+  },
+  hiddenInTable: true,
+  pure: true,
+}, {
+  title: () => $gettext('DNS Target CNAME'),
+  dataIndex: 'dns_target_cname',
+  edit: {
+    type: 'input',
+  },
+  hiddenInTable: true,
+  pure: true,
+}, {
+  title: () => $gettext('Delete DNS Records With Site'),
+  dataIndex: 'dns_delete_with_site',
+  edit: {
+    type: 'switch',
+  },
+  hiddenInTable: true,
+  pure: true,
 }, {
   title: () => $gettext('Created at'),
   dataIndex: 'created_at',
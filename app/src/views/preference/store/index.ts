@@ -53,6 +53,17 @@ const useSystemSettingsStore = defineStore('systemSettings', () => {
       renewal_interval: 30,
       recursive_nameservers: [],
       http_challenge_port: '9180',
+      ct_log_source: 'https://crt.sh',
+      ct_monitor_interval: 6,
+      dns_propagation_check: false,
+    },
+    change_set: {
+      require_approval: false,
+      expire_hours: 72,
+    },
+    git_history: {
+      enabled: false,
+      repository_path: '',
     },
     http: {
       github_proxy: '',
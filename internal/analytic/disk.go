@@ -36,6 +36,38 @@ func GetDiskStat() (DiskStat, error) {
 	return buildDiskStat(partitions, disk.Usage, getFilesystemKey), nil
 }
 
+// GetPartitionUsage returns the usage of the same partitions as GetDiskStat in
+// bytes, for consumers that need numbers rather than humanized sizes.
+func GetPartitionUsage() ([]PartitionUsage, error) {
+	partitions, err := getVisiblePartitions()
+	if err != nil {
+		return nil, errors.Wrap(err, "error analytic GetPartitionUsage - getting partitions")
+	}
+
+	result := make([]PartitionUsage, 0, len(partitions))
+	for _, partition := range partitions {
+		if isVirtualFilesystem(partition.Fstype) || shouldSkipPath(partition.Mountpoint, partition.Device) {
+			continue
+		}
+
+		usage, err := disk.Usage(partition.Mountpoint)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, PartitionUsage{
+			Mountpoint: partition.Mountpoint,
+			Device:     partition.Device,
+			Fstype:     partition.Fstype,
+			Total:      usage.Total,
+			Used:       usage.Used,
+			Free:       usage.Free,
+		})
+	}
+
+	return result, nil
+}
+
 type diskUsageFunc func(path string) (*disk.UsageStat, error)
 type filesystemKeyFunc func(partition disk.PartitionStat, usage *disk.UsageStat) (string, error)
 
@@ -30,3 +30,13 @@ type DiskStat struct {
 	Reads      Usage[uint64]   `json:"reads"`
 	Partitions []PartitionStat `json:"partitions"`
 }
+
+// PartitionUsage is the raw byte usage of a partition listed by GetDiskStat.
+type PartitionUsage struct {
+	Mountpoint string
+	Device     string
+	Fstype     string
+	Total      uint64
+	Used       uint64
+	Free       uint64
+}
@@ -0,0 +1,146 @@
+package cert
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// RenewPrivateCACerts signs the CRLs of the private CAs again before they
+// expire and renews every certificate issued by them that is close to expiry.
+// It is invoked by a dedicated cron job.
+func RenewPrivateCACerts() {
+	defer func() {
+		if err := recover(); err != nil {
+			buf := make([]byte, 1024)
+			runtime.Stack(buf, false)
+			logger.Errorf("%s\n%s", err, buf)
+		}
+	}()
+	logger.Info("RenewPrivateCACerts Worker Started")
+
+	if model.UseDB() == nil {
+		return
+	}
+
+	now := time.Now()
+	reload := false
+	authorities, _ := query.CertAuthority.Find()
+	for _, ca := range authorities {
+		if !crlDue(ca, now) {
+			continue
+		}
+		if err := UpdateCRL(ca, now); err != nil {
+			logger.Errorf("update CRL of %s error: %v", ca.Name, err)
+			notification.Error("Update CRL Error", "Update CRL of %{name} failed: %{error}",
+				map[string]any{"name": ca.Name, "error": err.Error()})
+			continue
+		}
+		reload = true
+	}
+	if reload {
+		nginx.Reload()
+	}
+
+	c := query.Cert
+	certs, _ := c.Where(c.AutoCert.Eq(model.AutoCertPrivateCA)).Find()
+
+	renewalThresholdDays := settings.CertSettings.GetCertRenewalInterval()
+	for _, certModel := range certs {
+		renewPrivateCACert(certModel, now, renewalThresholdDays)
+	}
+	logger.Info("RenewPrivateCACerts Worker End")
+}
+
+// renewPrivateCACert renews a single certificate issued by a private CA when
+// it is due. The certificate keeps its key, so nothing that pinned it breaks.
+func renewPrivateCACert(certModel *model.Cert, now time.Time, renewalThresholdDays int) {
+	targetName := getAutoRenewTargetName(certModel)
+
+	if shouldSkipAutoRenew(certModel, now) {
+		logger.Infof("Skip auto renew for %s until %s after previous failure", targetName,
+			certModel.LastAutoRenewAt.Add(autoRenewFailureRetryCooldown).Format(time.DateTime))
+		return
+	}
+
+	due, err := privateCARenewalDue(certModel, now, renewalThresholdDays)
+	if err == nil && !due {
+		return
+	}
+
+	log := NewLogger()
+	log.SetCertModel(certModel)
+	defer log.Close()
+
+	if err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+
+	ca, err := GetCertAuthority(certModel.PrivateCAConfig.CertAuthorityID)
+	if err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+	signer, err := loadSelfSignedKey(certModel.SSLCertificateKeyPath)
+	if err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+	certPEM, keyPEM, err := signPrivateCACert(ca, certModel, signer, now)
+	if err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+	if err = recordIssuance(ca, certModel, certPEM); err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+
+	content := &Content{
+		SSLCertificatePath:    certModel.SSLCertificatePath,
+		SSLCertificateKeyPath: certModel.SSLCertificateKeyPath,
+		SSLCertificate:        string(certPEM),
+		SSLCertificateKey:     string(keyPEM),
+	}
+	if err = content.WriteFile(); err != nil {
+		handleAutoRenewFailure(certModel, log, targetName, err)
+		return
+	}
+
+	nginx.Reload()
+
+	updateAutoRenewStatus(certModel, now, "")
+	notification.Success("Renew Certificate Success",
+		"Certificate %{name} renewed successfully", map[string]any{"name": targetName})
+
+	if err = SyncToRemoteServer(certModel); err != nil {
+		notification.Error("Sync Certificate Error", err.Error(), nil)
+	}
+}
+
+// privateCARenewalDue reports whether a certificate issued by a private CA is
+// due for renewal, using the same schedule as every other certificate.
+func privateCARenewalDue(certModel *model.Cert, now time.Time, renewalThresholdDays int) (bool, error) {
+	if certModel.PrivateCAConfig == nil {
+		return false, pkgerrors.New("private CA certificate config is empty")
+	}
+
+	if certModel.SSLCertificatePath == "" {
+		return false, pkgerrors.New("ssl certificate path is empty for private CA certificate")
+	}
+
+	info, err := GetCertInfo(certModel.SSLCertificatePath)
+	if err != nil {
+		return false, pkgerrors.Wrap(err, "get private CA certificate info error")
+	}
+
+	return shouldRenewCertificate(info, now, renewalThresholdDays), nil
+}
@@ -26,6 +26,12 @@ func GetCertInfo(sslCertificatePath string) (info *Info, err error) {
 	return certificateInfo(cert), nil
 }
 
+// GetCertificate parses the certificate at a path under the nginx
+// configuration directory.
+func GetCertificate(sslCertificatePath string) (*x509.Certificate, error) {
+	return getCertificate(sslCertificatePath)
+}
+
 func getCertificate(sslCertificatePath string) (*x509.Certificate, error) {
 	if !helper.IsUnderDirectory(sslCertificatePath, nginx.GetConfPath()) {
 		return nil, ErrCertPathIsNotUnderTheNginxConfDir
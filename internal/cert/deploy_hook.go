@@ -0,0 +1,376 @@
+package cert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/docker"
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/internal/translation"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/uozi-tech/cosy"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	deployHookDefaultTimeout = 60 * time.Second
+	// deployHookOutputLimit bounds the script output kept in the log.
+	deployHookOutputLimit = 4096
+)
+
+// ValidateDeployHooks checks that every hook has the fields its type needs.
+func ValidateDeployHooks(hooks []model.CertDeployHook) error {
+	for i, hook := range hooks {
+		if err := validateDeployHook(hook); err != nil {
+			return cosy.WrapErrorWithParams(ErrInvalidDeployHook, deployHookName(i, hook), err.Error())
+		}
+	}
+	return nil
+}
+
+func validateDeployHook(hook model.CertDeployHook) error {
+	if hook.Stage == model.CertDeployHookStagePre && hook.Type != model.CertDeployHookScript {
+		return errors.New("only script hooks can run before the issuance")
+	}
+	switch hook.Type {
+	case model.CertDeployHookScript:
+		if strings.TrimSpace(hook.Command) == "" {
+			return errors.New("command is required")
+		}
+	case model.CertDeployHookCopy, model.CertDeployHookBundle:
+		if !filepath.IsAbs(hook.Destination) {
+			return errors.New("destination must be an absolute path")
+		}
+		if hook.Type == model.CertDeployHookBundle && hook.Format == "" {
+			return errors.New("format is required")
+		}
+	case model.CertDeployHookDocker:
+		if !strings.HasPrefix(hook.Destination, "/") {
+			return errors.New("destination must be an absolute path")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", hook.Type)
+	}
+	if _, err := parseDeployHookMode(hook.Mode, 0); err != nil {
+		return err
+	}
+	if _, err := parseDeployHookMode(hook.KeyMode, 0); err != nil {
+		return err
+	}
+	if hook.Type == model.CertDeployHookDocker {
+		// Names can not be resolved inside the container.
+		_, _, err := numericOwner(hook.Owner)
+		return err
+	}
+	return nil
+}
+
+// RunDeployHooks runs the hooks of a certificate for a stage in order and
+// logs each result. A failed hook fires a notification without stopping the
+// following ones, and the first failure is returned.
+func RunDeployHooks(ctx context.Context, certModel *model.Cert, stage string, certLogger *Logger) error {
+	var firstErr error
+	for i, hook := range certModel.DeployHooks {
+		if deployHookStage(hook) != stage {
+			continue
+		}
+		name := deployHookName(i, hook)
+		certLogger.Info(translation.C("[Nginx UI] Running deploy hook %{name}", map[string]any{
+			"name": name,
+		}))
+
+		output, err := runDeployHook(ctx, certModel, hook, stage)
+		if output != "" {
+			certLogger.Info(translation.C("[Nginx UI] Deploy hook %{name} output: %{output}", map[string]any{
+				"name":   name,
+				"output": output,
+			}))
+		}
+		if err != nil {
+			err = cosy.WrapErrorWithParams(ErrDeployHookFailed, name, err.Error())
+			certLogger.Error(err)
+			notification.Error("Certificate Deploy Hook Failed",
+				"Deploy hook %{hook} of certificate %{name} failed: %{error}", map[string]any{
+					"hook":  name,
+					"name":  certModel.Name,
+					"error": err.Error(),
+				})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		certLogger.Info(translation.C("[Nginx UI] Deploy hook %{name} succeeded", map[string]any{
+			"name": name,
+		}))
+	}
+	return firstErr
+}
+
+func runDeployHook(ctx context.Context, certModel *model.Cert, hook model.CertDeployHook, stage string) (string, error) {
+	if hook.Type == model.CertDeployHookScript {
+		return runDeployScript(ctx, certModel, hook, stage)
+	}
+
+	certificate, err := os.ReadFile(certModel.SSLCertificatePath)
+	if err != nil {
+		return "", err
+	}
+	key, err := os.ReadFile(certModel.SSLCertificateKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	switch hook.Type {
+	case model.CertDeployHookCopy:
+		return "", copyDeployFiles(certModel, hook, certificate, key)
+	case model.CertDeployHookBundle:
+		return "", writeDeployBundle(hook, certificate, key)
+	case model.CertDeployHookDocker:
+		return "", copyDeployFilesToContainer(ctx, certModel, hook, certificate, key)
+	}
+	return "", fmt.Errorf("unknown type %q", hook.Type)
+}
+
+// runDeployScript runs a script with the certificate paths in its
+// environment and returns its combined output.
+func runDeployScript(ctx context.Context, certModel *model.Cert, hook model.CertDeployHook, stage string) (string, error) {
+	timeout := deployHookDefaultTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", hook.Command)
+	}
+	cmd.Env = append(os.Environ(),
+		"NGINX_UI_HOOK_STAGE="+stage,
+		"NGINX_UI_CERT_ID="+strconv.FormatUint(certModel.ID, 10),
+		"NGINX_UI_CERT_NAME="+certModel.Name,
+		"NGINX_UI_CERT_DOMAINS="+strings.Join(certModel.Domains, " "),
+		"NGINX_UI_CERT_PATH="+certModel.SSLCertificatePath,
+		"NGINX_UI_CERT_KEY_PATH="+certModel.SSLCertificateKeyPath,
+	)
+
+	output, err := cmd.CombinedOutput()
+	result := strings.TrimSpace(string(output))
+	if len(result) > deployHookOutputLimit {
+		result = result[:deployHookOutputLimit] + "..."
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("timed out after %s", timeout)
+	}
+	return result, err
+}
+
+// copyDeployFiles copies the certificate and its key into the destination
+// directory under their own names. The key keeps 0600 unless KeyMode is set.
+func copyDeployFiles(certModel *model.Cert, hook model.CertDeployHook, certificate, key []byte) error {
+	uid, gid, err := lookupOwner(hook.Owner)
+	if err != nil {
+		return err
+	}
+	certMode, err := parseDeployHookMode(hook.Mode, 0644)
+	if err != nil {
+		return err
+	}
+	keyMode, err := parseDeployHookMode(hook.KeyMode, 0600)
+	if err != nil {
+		return err
+	}
+	if err := writeDeployFile(filepath.Join(hook.Destination, filepath.Base(certModel.SSLCertificatePath)),
+		certificate, certMode, uid, gid); err != nil {
+		return err
+	}
+	return writeDeployFile(filepath.Join(hook.Destination, filepath.Base(certModel.SSLCertificateKeyPath)),
+		key, keyMode, uid, gid)
+}
+
+// writeDeployBundle writes the key and the chain into one file, either
+// concatenated as PEM or as a PKCS#12 archive.
+func writeDeployBundle(hook model.CertDeployHook, certificate, key []byte) error {
+	var content []byte
+	switch hook.Format {
+	case model.CertBundleFormatPEM:
+		content = append(append([]byte{}, key...), certificate...)
+	case model.CertBundleFormatPKCS12:
+		privateKey, err := certcrypto.ParsePEMPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		chain, err := certcrypto.ParsePEMBundle(certificate)
+		if err != nil {
+			return err
+		}
+		content, err = pkcs12.Modern.Encode(privateKey, chain[0], chain[1:], hook.Password)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown bundle format %q", hook.Format)
+	}
+
+	uid, gid, err := lookupOwner(hook.Owner)
+	if err != nil {
+		return err
+	}
+	mode, err := parseDeployHookMode(hook.Mode, 0600)
+	if err != nil {
+		return err
+	}
+	return writeDeployFile(hook.Destination, content, mode, uid, gid)
+}
+
+// copyDeployFilesToContainer copies the certificate and its key into a
+// directory of a container, by default the nginx one. Like copyDeployFiles, the
+// key keeps 0600 unless KeyMode is set.
+func copyDeployFilesToContainer(ctx context.Context, certModel *model.Cert, hook model.CertDeployHook, certificate, key []byte) error {
+	uid, gid, err := numericOwner(hook.Owner)
+	if err != nil {
+		return err
+	}
+	certMode, err := parseDeployHookMode(hook.Mode, 0644)
+	if err != nil {
+		return err
+	}
+	keyMode, err := parseDeployHookMode(hook.KeyMode, 0600)
+	if err != nil {
+		return err
+	}
+	return docker.CopyToContainer(ctx, hook.Container, hook.Destination, []docker.File{
+		{Name: filepath.Base(certModel.SSLCertificatePath), Content: certificate, Mode: int64(certMode), UID: uid, GID: gid},
+		{Name: filepath.Base(certModel.SSLCertificateKeyPath), Content: key, Mode: int64(keyMode), UID: uid, GID: gid},
+	})
+}
+
+// writeDeployFile replaces a file atomically with the given mode and, when
+// uid or gid is not -1, ownership.
+func writeDeployFile(path string, content []byte, mode os.FileMode, uid, gid int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := ensureWritableFileTarget(path); err != nil {
+		return err
+	}
+	tmpPath, err := writeTempFileNextTo(path, content, mode)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+	if uid != -1 || gid != -1 {
+		if err := os.Chown(tmpPath, uid, gid); err != nil {
+			return err
+		}
+	}
+	return replaceFile(tmpPath, path)
+}
+
+func parseDeployHookMode(mode string, fallback os.FileMode) (os.FileMode, error) {
+	if mode == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q", mode)
+	}
+	return os.FileMode(parsed), nil
+}
+
+// lookupOwner resolves an owner given as user[:group], by name or id. An
+// empty part keeps the ownership and yields -1.
+func lookupOwner(owner string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner == "" {
+		return
+	}
+	userPart, groupPart, _ := strings.Cut(owner, ":")
+	if userPart != "" {
+		if uid, err = strconv.Atoi(userPart); err != nil {
+			u, err := user.Lookup(userPart)
+			if err != nil {
+				return -1, -1, err
+			}
+			if uid, err = strconv.Atoi(u.Uid); err != nil {
+				return -1, -1, err
+			}
+		}
+	}
+	if groupPart != "" {
+		if gid, err = strconv.Atoi(groupPart); err != nil {
+			g, err := user.LookupGroup(groupPart)
+			if err != nil {
+				return -1, -1, err
+			}
+			if gid, err = strconv.Atoi(g.Gid); err != nil {
+				return -1, -1, err
+			}
+		}
+	}
+	return uid, gid, nil
+}
+
+// numericOwner parses an owner given as uid[:gid]. Files copied into a
+// container belong to root unless set otherwise.
+func numericOwner(owner string) (uid, gid int, err error) {
+	if owner == "" {
+		return 0, 0, nil
+	}
+	userPart, groupPart, _ := strings.Cut(owner, ":")
+	if uid, err = strconv.Atoi(userPart); err != nil {
+		return 0, 0, fmt.Errorf("owner %q must be a numeric uid[:gid]", owner)
+	}
+	if groupPart == "" {
+		return uid, 0, nil
+	}
+	if gid, err = strconv.Atoi(groupPart); err != nil {
+		return 0, 0, fmt.Errorf("owner %q must be a numeric uid[:gid]", owner)
+	}
+	return uid, gid, nil
+}
+
+func deployHookStage(hook model.CertDeployHook) string {
+	if hook.Stage == "" {
+		return model.CertDeployHookStagePost
+	}
+	return hook.Stage
+}
+
+func deployHookName(i int, hook model.CertDeployHook) string {
+	return fmt.Sprintf("#%d (%s)", i+1, hook.Type)
+}
+
+// deployHookCert returns the certificate being issued with the paths of the
+// payload, or nil when it has no hooks.
+func deployHookCert(payload *ConfigPayload) *model.Cert {
+	if payload.CertID == 0 || model.UseDB() == nil {
+		return nil
+	}
+	c := query.Cert
+	certModel, err := c.Where(c.ID.Eq(payload.CertID)).First()
+	if err != nil || len(certModel.DeployHooks) == 0 {
+		return nil
+	}
+	certModel.SSLCertificatePath = payload.GetCertificatePath()
+	certModel.SSLCertificateKeyPath = payload.GetCertificateKeyPath()
+	return certModel
+}
@@ -0,0 +1,116 @@
+package cert
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestValidateDeployHooks(t *testing.T) {
+	require.NoError(t, ValidateDeployHooks([]model.CertDeployHook{
+		{Type: model.CertDeployHookScript, Stage: model.CertDeployHookStagePre, Command: "systemctl stop haproxy"},
+		{Type: model.CertDeployHookCopy, Destination: "/etc/haproxy/certs", Mode: "0640"},
+		{Type: model.CertDeployHookDocker, Destination: "/certs", Owner: "101:101"},
+	}))
+
+	for _, hook := range []model.CertDeployHook{
+		{Type: model.CertDeployHookScript},
+		{Type: model.CertDeployHookCopy, Stage: model.CertDeployHookStagePre, Destination: "/etc/haproxy/certs"},
+		{Type: model.CertDeployHookCopy, Destination: "certs"},
+		{Type: model.CertDeployHookCopy, Destination: "/etc/haproxy/certs", Mode: "0999"},
+		{Type: model.CertDeployHookCopy, Destination: "/etc/haproxy/certs", KeyMode: "rw"},
+		{Type: model.CertDeployHookBundle, Destination: "/etc/haproxy/site.pem"},
+		{Type: model.CertDeployHookDocker, Destination: "/certs", Owner: "nginx"},
+	} {
+		requireErrorCode(t, ValidateDeployHooks([]model.CertDeployHook{hook}), ErrInvalidDeployHook)
+	}
+}
+
+func TestRunDeployHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("deploy scripts run with /bin/sh")
+	}
+	db := setupPrivateCATest(t)
+
+	certPEM, keyPEM, err := GenerateSelfSigned(SelfSignedOptions{
+		CommonName:   "example.com",
+		DNSNames:     []string{"example.com"},
+		KeyType:      certcrypto.EC256,
+		ValidityDays: 30,
+	})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "fullchain.cer")
+	keyPath := filepath.Join(dir, "private.key")
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0644))
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0600))
+
+	out := t.TempDir()
+	certModel := &model.Cert{
+		Name:                  "example.com",
+		Domains:               []string{"example.com"},
+		SSLCertificatePath:    certPath,
+		SSLCertificateKeyPath: keyPath,
+		DeployHooks: []model.CertDeployHook{
+			{Type: model.CertDeployHookScript, Stage: model.CertDeployHookStagePre, Command: "touch " + filepath.Join(out, "pre")},
+			{Type: model.CertDeployHookScript, Command: `echo "$NGINX_UI_HOOK_STAGE $NGINX_UI_CERT_DOMAINS $NGINX_UI_CERT_PATH"`},
+			{Type: model.CertDeployHookScript, Command: "echo broken >&2; exit 3"},
+			{Type: model.CertDeployHookCopy, Destination: filepath.Join(out, "copy"), Mode: "0640"},
+			{Type: model.CertDeployHookCopy, Destination: filepath.Join(out, "shared"), KeyMode: "0640"},
+			{Type: model.CertDeployHookBundle, Destination: filepath.Join(out, "site.pem"), Format: model.CertBundleFormatPEM},
+			{Type: model.CertDeployHookBundle, Destination: filepath.Join(out, "site.p12"), Format: model.CertBundleFormatPKCS12, Password: "secret"},
+		},
+	}
+	require.NoError(t, db.Create(certModel).Error)
+
+	log := NewLogger()
+	err = RunDeployHooks(t.Context(), certModel, model.CertDeployHookStagePost, log)
+	log.Close()
+	requireErrorCode(t, err, ErrDeployHookFailed)
+	assert.Contains(t, err.Error(), "#3 (script)")
+
+	assert.NoFileExists(t, filepath.Join(out, "pre"), "pre hooks only run before the issuance")
+	assert.Contains(t, log.ToString(), "post example.com "+certPath)
+	assert.Contains(t, log.ToString(), "broken")
+
+	var notifications int64
+	require.NoError(t, db.Model(&model.Notification{}).Count(&notifications).Error)
+	assert.Equal(t, int64(1), notifications)
+
+	copied, err := os.ReadFile(filepath.Join(out, "copy", "fullchain.cer"))
+	require.NoError(t, err)
+	assert.Equal(t, certPEM, copied)
+	info, err := os.Stat(filepath.Join(out, "copy", "fullchain.cer"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(out, "copy", "private.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the mode does not loosen the key")
+	info, err = os.Stat(filepath.Join(out, "shared", "private.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	bundle, err := os.ReadFile(filepath.Join(out, "site.pem"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bundle), string(keyPEM)))
+	assert.True(t, strings.HasSuffix(string(bundle), string(certPEM)))
+
+	p12, err := os.ReadFile(filepath.Join(out, "site.p12"))
+	require.NoError(t, err)
+	_, leaf, _, err := pkcs12.DecodeChain(p12, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", leaf.Subject.CommonName)
+
+	log = NewLogger()
+	defer log.Close()
+	require.NoError(t, RunDeployHooks(t.Context(), certModel, model.CertDeployHookStagePre, log))
+	assert.FileExists(t, filepath.Join(out, "pre"))
+}
@@ -0,0 +1,39 @@
+package cert
+
+import (
+	"context"
+
+	dnsService "github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/internal/translation"
+	"github.com/go-acme/lego/v5/challenge/dns01"
+)
+
+// propagationPreCheck replaces the lego propagation check with one that asks
+// every authoritative nameserver of the zone and logs those lagging behind.
+// Failed lookups are retried until the propagation timeout of the provider.
+func propagationPreCheck(l *Logger) dns01.WrapPreCheckFunc {
+	checker := dnsService.NewPropagationChecker()
+	return func(ctx context.Context, domain, fqdn, value string, _ dns01.PreCheckFunc) (bool, error) {
+		report, err := checker.Check(ctx, dnsService.PropagationQuery{
+			Name:     fqdn,
+			Type:     "TXT",
+			Expected: []string{value},
+		})
+		if err != nil {
+			l.Info(translation.C("[Nginx UI] DNS propagation check failed: %{error}", map[string]any{
+				"error": err.Error(),
+			}))
+			return false, nil
+		}
+
+		for _, answer := range report.Nameservers {
+			if !answer.Propagated {
+				l.Info(translation.C("[Nginx UI] Waiting for %{nameserver} to serve %{fqdn}", map[string]any{
+					"nameserver": answer.Nameserver,
+					"fqdn":       fqdn,
+				}))
+			}
+		}
+		return report.Propagated, nil
+	}
+}
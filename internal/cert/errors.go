@@ -52,6 +52,16 @@ var (
 	ErrIPCertificateRequiresHTTP01       = e.New(50053, "IP address certificates require the HTTP-01 challenge")
 	ErrCertificateProfileUnavailable     = e.New(50054, "certificate profile is not available from the selected ACME server: {0}")
 	ErrWildcardIPCertificateConflict     = e.New(50055, "wildcard domains and IP addresses cannot be requested in the same certificate")
+	ErrCertAuthorityCreate               = e.New(50056, "create certificate authority error: {0}")
+	ErrCertAuthorityParse                = e.New(50057, "certificate authority {0} is invalid: {1}")
+	ErrCertAuthorityInUse                = e.New(50058, "certificate authority {0} still has intermediates or issued certificates")
+	ErrCertAuthorityExpired              = e.New(50059, "certificate authority {0} has expired")
+	ErrInvalidCertUsage                  = e.New(50060, "invalid certificate usage: {0}")
+	ErrCertIsNotPrivateCA                = e.New(50061, "certificate is not issued by a private certificate authority")
+	ErrSignCRL                           = e.New(50062, "sign CRL error: {0}")
+	ErrInvalidEmail                      = e.New(50063, "invalid email address: {0}")
+	ErrInvalidDeployHook                 = e.New(50064, "invalid deploy hook {0}: {1}")
+	ErrDeployHookFailed                  = e.New(50065, "deploy hook {0} failed: {1}")
 )
 
 func NewInvalidKeyTypeError(keyType string) error {
@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"runtime"
@@ -153,6 +154,9 @@ func IssueCert(payload *ConfigPayload, certLogger *Logger) error {
 			// authoritative nameservers. Fixes #1711, #1719.
 			err = client.Challenge.SetDNS01Provider(provider,
 				dns01.DisableRecursiveNSsPropagationRequirement(),
+				dns01.CondOptions(settings.CertSettings.DNSPropagationCheck,
+					dns01.WrapPreCheck(propagationPreCheck(certLogger)),
+				),
 			)
 		} else {
 			return ErrEnvironmentConfigurationIsEmpty
@@ -186,6 +190,10 @@ func IssueCert(payload *ConfigPayload, certLogger *Logger) error {
 		}
 	}
 
+	if hookCert := deployHookCert(payload); hookCert != nil {
+		_ = RunDeployHooks(context.Background(), hookCert, model.CertDeployHookStagePre, certLogger)
+	}
+
 	if canUseLegoRenew(payload) &&
 		time.Since(payload.NotBefore).Hours()/24 <= 21 &&
 		payload.Resource != nil && payload.Resource.Certificate != nil {
@@ -204,6 +212,11 @@ func IssueCert(payload *ConfigPayload, certLogger *Logger) error {
 
 	nginx.Reload()
 
+	// A failed hook is logged and notified, the certificate itself was issued.
+	if hookCert := deployHookCert(payload); hookCert != nil {
+		_ = RunDeployHooks(context.Background(), hookCert, model.CertDeployHookStagePost, certLogger)
+	}
+
 	certLogger.Info(translation.C("[Nginx UI] Finished"))
 
 	if payload.GetCertificatePath() == cSettings.ServerSettings.SSLCert &&
@@ -0,0 +1,277 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/uozi-tech/cosy"
+)
+
+const (
+	// PrivateCARootDefaultValidityDays is the validity of a root CA when none
+	// is given.
+	PrivateCARootDefaultValidityDays = 3650
+	// PrivateCAIntermediateDefaultValidityDays is the validity of an
+	// intermediate CA when none is given.
+	PrivateCAIntermediateDefaultValidityDays = 1825
+	// PrivateCAMaxValidityDays caps the validity of a CA.
+	PrivateCAMaxValidityDays = 7300
+	// privateCADefaultCRLValidityDays is how long a CRL stays valid.
+	privateCADefaultCRLValidityDays = 7
+)
+
+// CertAuthorityOptions describes a root or intermediate CA to create. A CA
+// with a ParentID is signed by that CA, all others are roots.
+type CertAuthorityOptions struct {
+	Name            string
+	CommonName      string
+	Organization    string
+	ParentID        uint64
+	KeyType         certcrypto.KeyType
+	ValidityDays    int
+	CRLValidityDays int
+}
+
+// CertAuthorityPaths are the files a CA publishes under the nginx
+// configuration directory. Chain holds the CA and every CA above it, which is
+// what ssl_client_certificate needs, and CRL holds their revocation lists for
+// ssl_crl.
+type CertAuthorityPaths struct {
+	Certificate string `json:"certificate"`
+	Chain       string `json:"chain"`
+	CRL         string `json:"crl"`
+}
+
+// GetCertAuthorityPaths returns the files published by a CA.
+func GetCertAuthorityPaths(id uint64) CertAuthorityPaths {
+	dir := nginx.GetConfPath("ssl", "private_ca", strconv.FormatUint(id, 10))
+	return CertAuthorityPaths{
+		Certificate: filepath.Join(dir, "ca.crt"),
+		Chain:       filepath.Join(dir, "chain.crt"),
+		CRL:         filepath.Join(dir, "crl.pem"),
+	}
+}
+
+// GetCertAuthority loads a CA by id.
+func GetCertAuthority(id uint64) (*model.CertAuthority, error) {
+	a := query.CertAuthority
+	return a.Where(a.ID.Eq(id)).First()
+}
+
+// CreateCertAuthority generates the key and certificate of a CA, stores them
+// and publishes the certificate, its chain and an empty CRL.
+func CreateCertAuthority(opts CertAuthorityOptions) (*model.CertAuthority, error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return nil, ErrCertificateNameRequired
+	}
+	if opts.CommonName == "" {
+		opts.CommonName = opts.Name
+	}
+	if opts.CRLValidityDays <= 0 {
+		opts.CRLValidityDays = privateCADefaultCRLValidityDays
+	}
+	validityDays := opts.ValidityDays
+	if validityDays <= 0 {
+		validityDays = PrivateCARootDefaultValidityDays
+		if opts.ParentID != 0 {
+			validityDays = PrivateCAIntermediateDefaultValidityDays
+		}
+	}
+	validityDays = min(validityDays, PrivateCAMaxValidityDays)
+
+	keyType := helper.GetKeyType(opts.KeyType)
+	signer, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrCertAuthorityCreate, err.Error())
+	}
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrCertAuthorityCreate, err.Error())
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: opts.CommonName},
+		NotBefore:             now.Add(-selfSignedClockSkewBackdate),
+		NotAfter:              now.AddDate(0, 0, validityDays),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if opts.Organization != "" {
+		template.Subject.Organization = []string{opts.Organization}
+	}
+
+	// A root signs itself, an intermediate is signed by its parent and may
+	// only sign leaf certificates.
+	issuer, issuerSigner := template, signer
+	if opts.ParentID != 0 {
+		parent, err := GetCertAuthority(opts.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		issuer, issuerSigner, err = parseCertAuthority(parent)
+		if err != nil {
+			return nil, err
+		}
+		if !now.Before(issuer.NotAfter) {
+			return nil, cosy.WrapErrorWithParams(ErrCertAuthorityExpired, parent.Name)
+		}
+		if template.NotAfter.After(issuer.NotAfter) {
+			template.NotAfter = issuer.NotAfter
+		}
+		template.MaxPathLenZero = true
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, signer.Public(), issuerSigner)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrCertAuthorityCreate, err.Error())
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrCertAuthorityCreate, err.Error())
+	}
+
+	ca := &model.CertAuthority{
+		Name:            opts.Name,
+		ParentID:        opts.ParentID,
+		CommonName:      opts.CommonName,
+		Organization:    opts.Organization,
+		KeyType:         keyType,
+		ValidityDays:    validityDays,
+		Certificate:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKey:      string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		SerialNumber:    serialNumber.Text(16),
+		NotBefore:       template.NotBefore,
+		NotAfter:        template.NotAfter,
+		CRLValidityDays: opts.CRLValidityDays,
+	}
+	a := query.CertAuthority
+	if err := a.Create(ca); err != nil {
+		return nil, err
+	}
+
+	if err := UpdateCRL(ca, now); err != nil {
+		if _, rollbackErr := a.Delete(ca); rollbackErr != nil {
+			return nil, rollbackErr
+		}
+		_ = os.RemoveAll(filepath.Dir(GetCertAuthorityPaths(ca.ID).Certificate))
+		return nil, err
+	}
+	return ca, nil
+}
+
+// DeleteCertAuthority removes a CA along with its published files. A CA that
+// signed an intermediate or a certificate that is still renewed is kept.
+func DeleteCertAuthority(id uint64) error {
+	ca, err := GetCertAuthority(id)
+	if err != nil {
+		return err
+	}
+
+	a := query.CertAuthority
+	children, err := a.Where(a.ParentID.Eq(id)).Count()
+	if err != nil {
+		return err
+	}
+	issued, err := getPrivateCACerts(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 || len(issued) > 0 {
+		return cosy.WrapErrorWithParams(ErrCertAuthorityInUse, ca.Name)
+	}
+
+	r := query.CertRevocation
+	if _, err := r.Where(r.CertAuthorityID.Eq(id)).Delete(); err != nil {
+		return err
+	}
+	if _, err := a.Delete(ca); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Dir(GetCertAuthorityPaths(id).Certificate))
+}
+
+// parseCertAuthority decodes the certificate and private key of a CA.
+func parseCertAuthority(ca *model.CertAuthority) (*x509.Certificate, crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(ca.Certificate))
+	if block == nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrCertAuthorityParse, ca.Name, "no certificate")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrCertAuthorityParse, ca.Name, err.Error())
+	}
+	signer, err := certcrypto.ParsePEMPrivateKey([]byte(ca.PrivateKey))
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrCertAuthorityParse, ca.Name, err.Error())
+	}
+	return certificate, signer, nil
+}
+
+// CertAuthorityChain returns the CA followed by every CA above it, up to the
+// root.
+func CertAuthorityChain(ca *model.CertAuthority) ([]*model.CertAuthority, error) {
+	chain := []*model.CertAuthority{ca}
+	for current := ca; current.ParentID != 0; {
+		parent, err := GetCertAuthority(current.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		// A broken database could link a CA to itself.
+		if len(chain) > 16 {
+			return nil, cosy.WrapErrorWithParams(ErrCertAuthorityParse, ca.Name, "chain is too long")
+		}
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain, nil
+}
+
+// publishCertAuthority writes the certificate, chain and CRL files of a CA.
+func publishCertAuthority(ca *model.CertAuthority) error {
+	chain, err := CertAuthorityChain(ca)
+	if err != nil {
+		return err
+	}
+
+	var certificates, crls strings.Builder
+	for _, current := range chain {
+		certificates.WriteString(current.Certificate)
+		crls.WriteString(current.CRL)
+	}
+
+	paths := GetCertAuthorityPaths(ca.ID)
+	if err := os.MkdirAll(filepath.Dir(paths.Certificate), 0755); err != nil {
+		return cosy.WrapErrorWithParams(ErrMakeCertificateDir, err.Error())
+	}
+	for path, content := range map[string]string{
+		paths.Certificate: ca.Certificate,
+		paths.Chain:       certificates.String(),
+		paths.CRL:         crls.String(),
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
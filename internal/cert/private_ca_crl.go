@@ -0,0 +1,177 @@
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+// UpdateCRL signs a new CRL for a CA listing every certificate it revoked,
+// then publishes it for the CA and every CA below it, whose CRL files include
+// it.
+func UpdateCRL(ca *model.CertAuthority, now time.Time) error {
+	certificate, signer, err := parseCertAuthority(ca)
+	if err != nil {
+		return err
+	}
+
+	r := query.CertRevocation
+	revocations, err := r.Where(r.CertAuthorityID.Eq(ca.ID)).Find()
+	if err != nil {
+		return err
+	}
+	entries := make([]x509.RevocationListEntry, 0, len(revocations))
+	for _, revocation := range revocations {
+		serialNumber, ok := new(big.Int).SetString(revocation.SerialNumber, 16)
+		if !ok {
+			continue
+		}
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serialNumber,
+			RevocationTime: revocation.RevokedAt,
+			ReasonCode:     revocation.Reason,
+		})
+	}
+
+	validityDays := ca.CRLValidityDays
+	if validityDays <= 0 {
+		validityDays = privateCADefaultCRLValidityDays
+	}
+	nextUpdate := now.AddDate(0, 0, validityDays)
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(ca.CRLNumber + 1),
+		ThisUpdate:                now,
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, certificate, signer)
+	if err != nil {
+		return cosy.WrapErrorWithParams(ErrSignCRL, err.Error())
+	}
+
+	ca.CRL = string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+	ca.CRLNumber++
+	ca.CRLUpdatedAt = &now
+	ca.CRLNextUpdate = &nextUpdate
+	a := query.CertAuthority
+	if _, err := a.Where(a.ID.Eq(ca.ID)).Select(a.CRL, a.CRLNumber, a.CRLUpdatedAt, a.CRLNextUpdate).Updates(ca); err != nil {
+		return err
+	}
+
+	return publishCertAuthorityTree(ca)
+}
+
+// publishCertAuthorityTree publishes a CA and every CA below it.
+func publishCertAuthorityTree(ca *model.CertAuthority) error {
+	if err := publishCertAuthority(ca); err != nil {
+		return err
+	}
+	a := query.CertAuthority
+	children, err := a.Where(a.ParentID.Eq(ca.ID), a.ID.Neq(ca.ID)).Find()
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := publishCertAuthorityTree(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crlDue reports whether the CRL of a CA should be signed again, which
+// happens once half of its validity has passed.
+func crlDue(ca *model.CertAuthority, now time.Time) bool {
+	if ca.CRLUpdatedAt == nil || ca.CRLNextUpdate == nil {
+		return true
+	}
+	half := ca.CRLNextUpdate.Sub(*ca.CRLUpdatedAt) / 2
+	return !now.Before(ca.CRLUpdatedAt.Add(half))
+}
+
+// RevokePrivateCACert adds a certificate issued by a private CA to the CRL of
+// the CA and stops renewing it. Every unexpired version signed for it is
+// revoked, not only the current one. nginx is reloaded to load the new CRL.
+func RevokePrivateCACert(certModel *model.Cert, reason int) error {
+	if certModel.AutoCert != model.AutoCertPrivateCA || certModel.PrivateCAConfig == nil {
+		return ErrCertIsNotPrivateCA
+	}
+	ca, err := GetCertAuthority(certModel.PrivateCAConfig.CertAuthorityID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	serials, err := unrevokedSerials(ca, certModel, now)
+	if err != nil {
+		return err
+	}
+	revocations := make([]*model.CertRevocation, 0, len(serials))
+	for _, serial := range serials {
+		revocations = append(revocations, &model.CertRevocation{
+			CertAuthorityID: ca.ID,
+			CertID:          certModel.ID,
+			SerialNumber:    serial,
+			Reason:          reason,
+			RevokedAt:       now,
+		})
+	}
+	if len(revocations) > 0 {
+		if err := query.CertRevocation.Create(revocations...); err != nil {
+			return err
+		}
+	}
+	c := query.Cert
+	if _, err := c.Where(c.ID.Eq(certModel.ID)).Update(c.AutoCert, model.AutoCertDisabled); err != nil {
+		return err
+	}
+	certModel.AutoCert = model.AutoCertDisabled
+
+	if err := UpdateCRL(ca, now); err != nil {
+		return err
+	}
+	nginx.Reload()
+	return nil
+}
+
+// unrevokedSerials returns the serial numbers of the unexpired certificates
+// the CA signed for certModel that are not revoked yet. The current file is
+// included for the certificates issued before their serials were recorded.
+func unrevokedSerials(ca *model.CertAuthority, certModel *model.Cert, now time.Time) ([]string, error) {
+	certificate, err := getCertificate(certModel.SSLCertificatePath)
+	if err != nil {
+		return nil, err
+	}
+	i := query.CertIssuance
+	issuances, err := i.Where(i.CertAuthorityID.Eq(ca.ID), i.CertID.Eq(certModel.ID), i.NotAfter.Gt(now)).Find()
+	if err != nil {
+		return nil, err
+	}
+	r := query.CertRevocation
+	revoked, err := r.Where(r.CertAuthorityID.Eq(ca.ID)).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(revoked))
+	for _, revocation := range revoked {
+		seen[revocation.SerialNumber] = true
+	}
+	var serials []string
+	candidates := []string{certificate.SerialNumber.Text(16)}
+	for _, issuance := range issuances {
+		candidates = append(candidates, issuance.SerialNumber)
+	}
+	for _, serial := range candidates {
+		if !seen[serial] {
+			seen[serial] = true
+			serials = append(serials, serial)
+		}
+	}
+	return serials, nil
+}
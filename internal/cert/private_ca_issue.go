@@ -0,0 +1,257 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// PrivateCALeafDefaultValidityDays is the validity of a certificate issued by
+// a private CA when none is given.
+const PrivateCALeafDefaultValidityDays = 365
+
+// PrivateCACertOptions describes a server or client certificate to issue with
+// a private CA.
+type PrivateCACertOptions struct {
+	Name            string
+	CertAuthorityID uint64
+	Usage           model.PrivateCACertUsage
+	DNSNames        []string
+	IPAddresses     []string
+	EmailAddresses  []string
+	KeyType         certcrypto.KeyType
+	ValidityDays    int
+	SyncNodeIds     []uint64
+}
+
+// IssuePrivateCACert issues a certificate with a private CA and stores it as
+// a Cert, so it is listed, synced and renewed like every other certificate.
+func IssuePrivateCACert(opts PrivateCACertOptions) (*model.Cert, error) {
+	if strings.TrimSpace(opts.Name) == "" {
+		return nil, ErrCertificateNameRequired
+	}
+	ca, err := GetCertAuthority(opts.CertAuthorityID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType := helper.GetKeyType(opts.KeyType)
+	certModel := &model.Cert{
+		Name:     opts.Name,
+		Domains:  opts.DNSNames,
+		AutoCert: model.AutoCertPrivateCA,
+		KeyType:  keyType,
+		PrivateCAConfig: &model.PrivateCACertConfig{
+			CertAuthorityID: ca.ID,
+			Usage:           opts.Usage,
+			IPAddresses:     opts.IPAddresses,
+			EmailAddresses:  opts.EmailAddresses,
+			ValidityDays:    opts.ValidityDays,
+		},
+		SyncNodeIds: opts.SyncNodeIds,
+	}
+	if certModel.PrivateCAConfig.ValidityDays <= 0 {
+		certModel.PrivateCAConfig.ValidityDays = PrivateCALeafDefaultValidityDays
+	}
+
+	signer, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSelfSignedGenerateKey, err.Error())
+	}
+	certPEM, keyPEM, err := signPrivateCACert(ca, certModel, signer, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Cert.Create(certModel); err != nil {
+		return nil, err
+	}
+	if err := recordIssuance(ca, certModel, certPEM); err != nil {
+		if _, rollbackErr := query.Cert.Delete(certModel); rollbackErr != nil {
+			logger.Errorf("private CA cert rollback failed for id %d: %v", certModel.ID, rollbackErr)
+		}
+		return nil, err
+	}
+
+	dir := filepath.Join(filepath.Dir(GetCertAuthorityPaths(ca.ID).Certificate), "issued",
+		strconv.FormatUint(certModel.ID, 10))
+	content := &Content{
+		SSLCertificatePath:    filepath.Join(dir, "fullchain.cer"),
+		SSLCertificateKeyPath: filepath.Join(dir, "private.key"),
+		SSLCertificate:        string(certPEM),
+		SSLCertificateKey:     string(keyPEM),
+	}
+	if err := content.WriteFile(); err != nil {
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			logger.Errorf("private CA cert directory cleanup failed for id %d at %s: %v", certModel.ID, dir, rmErr)
+		}
+		if _, rollbackErr := query.Cert.Delete(certModel); rollbackErr != nil {
+			logger.Errorf("private CA cert rollback failed for id %d: %v", certModel.ID, rollbackErr)
+		}
+		return nil, err
+	}
+
+	certModel.SSLCertificatePath = content.SSLCertificatePath
+	certModel.SSLCertificateKeyPath = content.SSLCertificateKeyPath
+	if fingerprint, fpErr := CertificateFingerprintFromPath(content.SSLCertificatePath); fpErr == nil {
+		certModel.Fingerprint = fingerprint
+	}
+	c := query.Cert
+	if _, err := c.Where(c.ID.Eq(certModel.ID)).
+		Select(c.SSLCertificatePath, c.SSLCertificateKeyPath, c.Fingerprint).
+		Updates(certModel); err != nil {
+		return nil, err
+	}
+	return certModel, nil
+}
+
+// signPrivateCACert signs the key of a certificate with its CA and returns
+// the certificate followed by the intermediates, along with the key.
+func signPrivateCACert(ca *model.CertAuthority, certModel *model.Cert, signer crypto.Signer, now time.Time) (certPEM, keyPEM []byte, err error) {
+	cfg := certModel.PrivateCAConfig
+
+	var extKeyUsage x509.ExtKeyUsage
+	switch cfg.Usage {
+	case model.PrivateCACertUsageServer:
+		extKeyUsage = x509.ExtKeyUsageServerAuth
+	case model.PrivateCACertUsageClient:
+		extKeyUsage = x509.ExtKeyUsageClientAuth
+	default:
+		return nil, nil, cosy.WrapErrorWithParams(ErrInvalidCertUsage, string(cfg.Usage))
+	}
+
+	ipAddresses := make([]net.IP, 0, len(cfg.IPAddresses))
+	for _, raw := range cfg.IPAddresses {
+		ip := net.ParseIP(raw)
+		if ip == nil {
+			return nil, nil, cosy.WrapErrorWithParams(ErrSelfSignedInvalidIP, raw)
+		}
+		ipAddresses = append(ipAddresses, ip)
+	}
+	for _, address := range cfg.EmailAddresses {
+		if parsed, err := mail.ParseAddress(address); err != nil || parsed.Address != address {
+			return nil, nil, cosy.WrapErrorWithParams(ErrInvalidEmail, address)
+		}
+	}
+	// Clients may be identified by their subject alone, servers need a name
+	// to be matched against.
+	if cfg.Usage == model.PrivateCACertUsageServer && len(certModel.Domains) == 0 && len(ipAddresses) == 0 {
+		return nil, nil, ErrSelfSignedNoSAN
+	}
+
+	caCertificate, caSigner, err := parseCertAuthority(ca)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !now.Before(caCertificate.NotAfter) {
+		return nil, nil, cosy.WrapErrorWithParams(ErrCertAuthorityExpired, ca.Name)
+	}
+	chain, err := CertAuthorityChain(ca)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrSelfSignedCreateCert, err.Error())
+	}
+	validityDays := cfg.ValidityDays
+	if validityDays <= 0 {
+		validityDays = PrivateCALeafDefaultValidityDays
+	}
+	notAfter := now.AddDate(0, 0, validityDays)
+	if notAfter.After(caCertificate.NotAfter) {
+		notAfter = caCertificate.NotAfter
+	}
+
+	keyUsage := x509.KeyUsageDigitalSignature
+	if _, isRSA := signer.Public().(*rsa.PublicKey); isRSA {
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	commonName := deriveSelfSignedCommonName(certModel.Domains, cfg.IPAddresses)
+	if commonName == "" && len(cfg.EmailAddresses) > 0 {
+		commonName = cfg.EmailAddresses[0]
+	}
+	if commonName == "" {
+		commonName = certModel.Name
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              certModel.Domains,
+		IPAddresses:           ipAddresses,
+		EmailAddresses:        cfg.EmailAddresses,
+		NotBefore:             now.Add(-selfSignedClockSkewBackdate),
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           []x509.ExtKeyUsage{extKeyUsage},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCertificate, signer.Public(), caSigner)
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrSelfSignedCreateCert, err.Error())
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrSelfSignedCreateCert, err.Error())
+	}
+
+	// The root is left out, peers have to trust it on their own.
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	for _, intermediate := range chain {
+		if intermediate.ParentID != 0 {
+			certPEM = append(certPEM, intermediate.Certificate...)
+		}
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// recordIssuance stores the serial number of a certificate signed for
+// certModel, so it is revoked along with the Cert even once renewed.
+func recordIssuance(ca *model.CertAuthority, certModel *model.Cert, certPEM []byte) error {
+	certificate, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return err
+	}
+	return query.CertIssuance.Create(&model.CertIssuance{
+		CertAuthorityID: ca.ID,
+		CertID:          certModel.ID,
+		SerialNumber:    certificate.SerialNumber.Text(16),
+		NotAfter:        certificate.NotAfter,
+	})
+}
+
+// getPrivateCACerts returns the certificates a CA keeps renewing.
+func getPrivateCACerts(caID uint64) ([]*model.Cert, error) {
+	c := query.Cert
+	certs, err := c.Where(c.AutoCert.Eq(model.AutoCertPrivateCA)).Find()
+	if err != nil {
+		return nil, err
+	}
+	issued := certs[:0]
+	for _, certModel := range certs {
+		if certModel.PrivateCAConfig != nil && certModel.PrivateCAConfig.CertAuthorityID == caID {
+			issued = append(issued, certModel)
+		}
+	}
+	return issued, nil
+}
@@ -0,0 +1,209 @@
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPrivateCAIssuesCertificatesThatVerifyAgainstItsChain(t *testing.T) {
+	setupPrivateCATest(t)
+
+	root, err := CreateCertAuthority(CertAuthorityOptions{Name: "Root", Organization: "Example"})
+	require.NoError(t, err)
+	intermediate, err := CreateCertAuthority(CertAuthorityOptions{Name: "Clients", ParentID: root.ID, ValidityDays: PrivateCAMaxValidityDays})
+	require.NoError(t, err)
+	assert.False(t, intermediate.NotAfter.After(root.NotAfter), "an intermediate never outlives its parent")
+
+	paths := GetCertAuthorityPaths(intermediate.ID)
+	chain := readPEMCertificates(t, paths.Chain)
+	require.Len(t, chain, 2)
+	assert.Equal(t, "Clients", chain[0].Subject.CommonName)
+	assert.True(t, chain[0].MaxPathLenZero)
+	assert.FileExists(t, paths.CRL)
+
+	client, err := IssuePrivateCACert(PrivateCACertOptions{
+		Name:            "alice",
+		CertAuthorityID: intermediate.ID,
+		Usage:           model.PrivateCACertUsageClient,
+		EmailAddresses:  []string{"alice@example.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.AutoCertPrivateCA, client.AutoCert)
+	assert.NotEmpty(t, client.Fingerprint)
+
+	issued := readPEMCertificates(t, client.SSLCertificatePath)
+	require.Len(t, issued, 2, "the leaf is followed by the intermediate")
+	assert.Equal(t, "alice@example.com", issued[0].Subject.CommonName)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(chain[1])
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(issued[1])
+	_, err = issued[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+	_, err = issued[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	assert.Error(t, err, "a client certificate is not valid for servers")
+
+	_, err = IssuePrivateCACert(PrivateCACertOptions{Name: "api", CertAuthorityID: root.ID, Usage: model.PrivateCACertUsageServer})
+	requireErrorCode(t, err, ErrSelfSignedNoSAN)
+	_, err = IssuePrivateCACert(PrivateCACertOptions{Name: "api", CertAuthorityID: root.ID, Usage: "peer", DNSNames: []string{"api.internal"}})
+	requireErrorCode(t, err, ErrInvalidCertUsage)
+
+	requireErrorCode(t, DeleteCertAuthority(root.ID), ErrCertAuthorityInUse)
+}
+
+func TestRevokedCertificatesAreListedInTheCRL(t *testing.T) {
+	db := setupPrivateCATest(t)
+
+	root, err := CreateCertAuthority(CertAuthorityOptions{Name: "Root", CRLValidityDays: 2})
+	require.NoError(t, err)
+	server, err := IssuePrivateCACert(PrivateCACertOptions{
+		Name:            "api",
+		CertAuthorityID: root.ID,
+		Usage:           model.PrivateCACertUsageServer,
+		DNSNames:        []string{"api.internal"},
+		IPAddresses:     []string{"10.0.0.1"},
+		ValidityDays:    30,
+	})
+	require.NoError(t, err)
+	original := readPEMCertificates(t, server.SSLCertificatePath)[0]
+	renewPrivateCACert(server, time.Now().AddDate(0, 0, 24), 7)
+	renewed := readPEMCertificates(t, server.SSLCertificatePath)[0]
+	require.NotEqual(t, original.SerialNumber, renewed.SerialNumber)
+
+	require.NoError(t, RevokePrivateCACert(server, 1))
+	assert.Equal(t, model.AutoCertDisabled, server.AutoCert, "a revoked certificate is not renewed")
+	requireErrorCode(t, RevokePrivateCACert(&model.Cert{AutoCert: model.AutoCertSelfSigned}, 0), ErrCertIsNotPrivateCA)
+
+	content, err := os.ReadFile(GetCertAuthorityPaths(root.ID).CRL)
+	require.NoError(t, err)
+	block, _ := pem.Decode(content)
+	require.NotNil(t, block)
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	require.NoError(t, err)
+	require.Len(t, crl.RevokedCertificateEntries, 2, "the version replaced by the renewal is still valid")
+	var revoked []string
+	for _, entry := range crl.RevokedCertificateEntries {
+		revoked = append(revoked, entry.SerialNumber.String())
+		assert.Equal(t, 1, entry.ReasonCode)
+	}
+	assert.ElementsMatch(t, []string{original.SerialNumber.String(), renewed.SerialNumber.String()}, revoked)
+	assert.Equal(t, int64(2), crl.Number.Int64())
+
+	require.NoError(t, db.First(root, root.ID).Error)
+	assert.False(t, crlDue(root, root.CRLUpdatedAt.Add(23*time.Hour)))
+	assert.True(t, crlDue(root, root.CRLUpdatedAt.Add(24*time.Hour)), "a CRL is signed again at half of its validity")
+}
+
+func TestPrivateCARenewalKeepsTheKey(t *testing.T) {
+	setupPrivateCATest(t)
+
+	root, err := CreateCertAuthority(CertAuthorityOptions{Name: "Root"})
+	require.NoError(t, err)
+	server, err := IssuePrivateCACert(PrivateCACertOptions{
+		Name:            "api",
+		CertAuthorityID: root.ID,
+		Usage:           model.PrivateCACertUsageServer,
+		DNSNames:        []string{"api.internal"},
+		ValidityDays:    30,
+	})
+	require.NoError(t, err)
+	before := readPEMCertificates(t, server.SSLCertificatePath)[0]
+
+	now := time.Now()
+	due, err := privateCARenewalDue(server, now, 7)
+	require.NoError(t, err)
+	assert.False(t, due)
+	due, err = privateCARenewalDue(server, now.AddDate(0, 0, 24), 7)
+	require.NoError(t, err)
+	assert.True(t, due)
+
+	renewPrivateCACert(server, now.AddDate(0, 0, 24), 7)
+	after := readPEMCertificates(t, server.SSLCertificatePath)[0]
+	assert.NotEqual(t, before.SerialNumber, after.SerialNumber)
+	assert.Equal(t, before.PublicKey, after.PublicKey)
+	assert.True(t, after.NotAfter.After(before.NotAfter))
+}
+
+func setupPrivateCATest(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	settings.NginxSettings.ConfigDir = t.TempDir()
+	settings.NginxSettings.ReloadCmd = "true"
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+	})
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Cert{}, &model.CertAuthority{}, &model.CertIssuance{}, &model.CertRevocation{},
+		&model.Notification{}, &model.ExternalNotify{}))
+	model.Use(db)
+	originalCert := query.Cert
+	originalCertAuthority := query.CertAuthority
+	originalCertIssuance := query.CertIssuance
+	originalCertRevocation := query.CertRevocation
+	originalNotification := query.Notification
+	originalExternalNotify := query.ExternalNotify
+	testQuery := query.Use(db)
+	query.Cert = &testQuery.Cert
+	query.CertAuthority = &testQuery.CertAuthority
+	query.CertIssuance = &testQuery.CertIssuance
+	query.CertRevocation = &testQuery.CertRevocation
+	query.Notification = &testQuery.Notification
+	query.ExternalNotify = &testQuery.ExternalNotify
+	t.Cleanup(func() {
+		model.Use(nil)
+		query.Cert = originalCert
+		query.CertAuthority = originalCertAuthority
+		query.CertIssuance = originalCertIssuance
+		query.CertRevocation = originalCertRevocation
+		query.Notification = originalNotification
+		query.ExternalNotify = originalExternalNotify
+	})
+	return db
+}
+
+func readPEMCertificates(t *testing.T, path string) []*x509.Certificate {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(content); block != nil; block, rest = pem.Decode(rest) {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certificates = append(certificates, certificate)
+	}
+	return certificates
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
@@ -0,0 +1,194 @@
+package changeset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+type actionFunc func(item model.ChangeSetItem, author githistory.Author) error
+
+// actions runs the items that do not write content through the same functions
+// as the matching direct operations.
+var actions = map[model.ChangeSetItemKind]map[model.ChangeSetItemAction]actionFunc{
+	model.ChangeSetItemSite: {
+		model.ChangeSetActionRename: func(item model.ChangeSetItem, author githistory.Author) error {
+			return site.Rename(item.Name, item.NewName, author)
+		},
+		model.ChangeSetActionEnable: func(item model.ChangeSetItem, author githistory.Author) error {
+			if err := site.ExitMaintenance(item.Name); err != nil {
+				return err
+			}
+			return site.Enable(item.Name, author)
+		},
+		model.ChangeSetActionDisable: func(item model.ChangeSetItem, author githistory.Author) error {
+			if err := site.ExitMaintenance(item.Name); err != nil {
+				return err
+			}
+			return site.Disable(item.Name, author)
+		},
+		model.ChangeSetActionDelete: func(item model.ChangeSetItem, author githistory.Author) error {
+			return site.Delete(item.Name, author)
+		},
+		model.ChangeSetActionDuplicate: func(item model.ChangeSetItem, author githistory.Author) error {
+			return site.Duplicate(item.Name, item.NewName, author)
+		},
+		model.ChangeSetActionMaintenance: func(item model.ChangeSetItem, author githistory.Author) error {
+			return site.EnterMaintenance(item.Name, author)
+		},
+		model.ChangeSetActionClientVerify: func(item model.ChangeSetItem, author githistory.Author) error {
+			return site.SetClientVerify(item.Name, item.CertAuthorityID, item.VerifyMode, author)
+		},
+	},
+	model.ChangeSetItemStream: {
+		model.ChangeSetActionRename: func(item model.ChangeSetItem, author githistory.Author) error {
+			return stream.Rename(item.Name, item.NewName, author)
+		},
+		model.ChangeSetActionEnable: func(item model.ChangeSetItem, author githistory.Author) error {
+			return stream.Enable(item.Name, author)
+		},
+		model.ChangeSetActionDisable: func(item model.ChangeSetItem, author githistory.Author) error {
+			return stream.Disable(item.Name, author)
+		},
+		model.ChangeSetActionDelete: func(item model.ChangeSetItem, author githistory.Author) error {
+			return stream.Delete(item.Name, author)
+		},
+		model.ChangeSetActionDuplicate: func(item model.ChangeSetItem, author githistory.Author) error {
+			return stream.Duplicate(item.Name, item.NewName, author)
+		},
+	},
+	model.ChangeSetItemConfig: {
+		model.ChangeSetActionRename: func(item model.ChangeSetItem, author githistory.Author) error {
+			return config.Rename(item.Path, configRenamePath(item), nil, author)
+		},
+		model.ChangeSetActionDelete: func(item model.ChangeSetItem, author githistory.Author) error {
+			syncNodeIDs, err := configSyncNodeIDs(item.Path)
+			if err != nil {
+				return err
+			}
+			return config.Delete(item.Path, filepath.Base(item.Path), syncNodeIDs, author)
+		},
+	},
+}
+
+// prepareAction resolves an item that does not write content. The file it
+// acts on must exist; Diff summarizes the action for the reviewers.
+func prepareAction(request ItemRequest) (item model.ChangeSetItem, err error) {
+	item = model.ChangeSetItem{
+		Kind:            request.Kind,
+		Action:          request.Action,
+		Name:            request.Name,
+		NewName:         request.NewName,
+		CertAuthorityID: request.CertAuthorityID,
+		VerifyMode:      request.VerifyMode,
+	}
+	if _, ok := actions[request.Kind][request.Action]; !ok {
+		return item, cosy.WrapErrorWithParams(ErrUnsupportedAction, string(request.Kind), string(request.Action))
+	}
+	if request.Action == model.ChangeSetActionClientVerify && item.VerifyMode == "" {
+		item.VerifyMode = "on"
+	}
+	renames := request.Action == model.ChangeSetActionRename || request.Action == model.ChangeSetActionDuplicate
+	if renames && request.NewName == "" {
+		return item, cosy.WrapErrorWithParams(ErrNewNameRequired, string(request.Action), request.Name)
+	}
+
+	switch request.Kind {
+	case model.ChangeSetItemSite:
+		item.Path, err = site.ResolveAvailablePath(request.Name)
+		if err == nil && renames {
+			_, err = site.ResolveAvailablePath(request.NewName)
+		}
+		if err == nil {
+			item.NamespaceID, err = siteNamespaceID(item.Path)
+		}
+	case model.ChangeSetItemStream:
+		item.Path, err = stream.ResolveAvailablePath(request.Name)
+		if err == nil && renames {
+			_, err = stream.ResolveAvailablePath(request.NewName)
+		}
+		if err == nil {
+			item.NamespaceID, err = streamNamespaceID(item.Path)
+		}
+	case model.ChangeSetItemConfig:
+		item.Path, err = config.ResolveAbsoluteOrRelativeConfPath(request.Name)
+		if err == nil {
+			item.Name, err = filepath.Rel(nginx.GetConfPath(), item.Path)
+		}
+		if err == nil && renames {
+			_, err = config.ResolveConfPath(filepath.Dir(item.Name), request.NewName)
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	if _, err = os.Stat(item.Path); err != nil {
+		return
+	}
+
+	item.Diff = summarizeAction(item)
+	return
+}
+
+func summarizeAction(item model.ChangeSetItem) string {
+	switch item.Action {
+	case model.ChangeSetActionRename:
+		return fmt.Sprintf("rename %s %s to %s", item.Kind, item.Name, item.NewName)
+	case model.ChangeSetActionDuplicate:
+		return fmt.Sprintf("duplicate %s %s as %s", item.Kind, item.Name, item.NewName)
+	case model.ChangeSetActionMaintenance:
+		return fmt.Sprintf("put %s %s in maintenance", item.Kind, item.Name)
+	case model.ChangeSetActionClientVerify:
+		if item.CertAuthorityID == 0 {
+			return fmt.Sprintf("stop verifying client certificates of %s %s", item.Kind, item.Name)
+		}
+		return fmt.Sprintf("verify client certificates of %s %s against certificate authority #%d (%s)",
+			item.Kind, item.Name, item.CertAuthorityID, item.VerifyMode)
+	default:
+		return fmt.Sprintf("%s %s %s", item.Action, item.Kind, item.Name)
+	}
+}
+
+// runAction applies an item that does not write content.
+func runAction(item model.ChangeSetItem, author githistory.Author) error {
+	run, ok := actions[item.Kind][item.Action]
+	if !ok {
+		return cosy.WrapErrorWithParams(ErrUnsupportedAction, string(item.Kind), string(item.Action))
+	}
+	if err := run(item, author); err != nil {
+		return cosy.WrapErrorWithParams(ErrActionFailed, string(item.Action), item.Name, err.Error())
+	}
+	return nil
+}
+
+// configRenamePath returns the path a config is renamed to, next to the
+// current one.
+func configRenamePath(item model.ChangeSetItem) string {
+	return filepath.Join(filepath.Dir(item.Path), item.NewName)
+}
+
+// configSyncNodeIDs returns the nodes a config file is synced to. Directories
+// have no record and are not synced.
+func configSyncNodeIDs(path string) ([]uint64, error) {
+	c := query.Config
+	cfg, err := c.Where(c.Filepath.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cfg.SyncNodeIds, nil
+}
@@ -0,0 +1,179 @@
+package changeset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gen/field"
+)
+
+// applyItems applies a change set. The writes go first and as a whole: the
+// records are changed in a single database transaction, and every file is
+// written, then nginx is tested and reloaded once before it commits. Any
+// failure restores all files and rolls the records back. Once applied, they
+// are recorded as a single commit by author and synced to the cluster like a
+// direct edit.
+//
+// The other actions then run in order through the same functions as the
+// matching direct operations, which test, reload, record and sync on their
+// own. A failing action stops the change set and leaves the items before it
+// applied.
+func applyItems(changeSet *model.ChangeSet, author githistory.Author) error {
+	var writes, others []model.ChangeSetItem
+	for _, item := range changeSet.Items {
+		if item.Action == model.ChangeSetActionWrite {
+			writes = append(writes, item)
+		} else {
+			others = append(others, item)
+		}
+	}
+
+	if len(writes) > 0 {
+		a := &applier{items: writes}
+		err := query.Q.Transaction(func(tx *query.Query) error {
+			return a.run(tx)
+		})
+		if err != nil {
+			a.restore()
+			return err
+		}
+
+		paths := make([]string, 0, len(writes))
+		for _, item := range writes {
+			paths = append(paths, item.Path)
+		}
+		githistory.Record(author, fmt.Sprintf("Apply change set #%d: %s", changeSet.ID, changeSet.Title), paths...)
+
+		a.sync()
+	}
+
+	for _, item := range others {
+		if err := runAction(item, author); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type applier struct {
+	items    []model.ChangeSetItem
+	configs  []*model.Config
+	written  int
+	reloaded bool
+}
+
+func (a *applier) run(tx *query.Query) error {
+	for _, item := range a.items {
+		if err := a.saveRecord(tx, item); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range a.items {
+		if err := os.WriteFile(item.Path, []byte(item.Content), 0644); err != nil {
+			return err
+		}
+		a.written++
+	}
+
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		return cosy.WrapErrorWithParams(ErrTestFailed, result.GetOutput())
+	}
+	a.reloaded = true
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		return cosy.WrapErrorWithParams(ErrReloadFailed, result.GetOutput())
+	}
+	return nil
+}
+
+// saveRecord places new sites and streams in their namespace and makes sure a
+// config has a record to read its sync targets from.
+func (a *applier) saveRecord(tx *query.Query, item model.ChangeSetItem) error {
+	switch item.Kind {
+	case model.ChangeSetItemSite:
+		if !item.NewFile || item.NamespaceID == 0 {
+			return nil
+		}
+		s := tx.Site
+		if _, err := s.Where(s.Path.Eq(item.Path)).FirstOrCreate(); err != nil {
+			return err
+		}
+		_, err := s.Where(s.Path.Eq(item.Path)).Update(s.NamespaceID, item.NamespaceID)
+		return err
+	case model.ChangeSetItemStream:
+		if !item.NewFile || item.NamespaceID == 0 {
+			return nil
+		}
+		s := tx.Stream
+		if _, err := s.Where(s.Path.Eq(item.Path)).FirstOrCreate(); err != nil {
+			return err
+		}
+		_, err := s.Where(s.Path.Eq(item.Path)).Update(s.NamespaceID, item.NamespaceID)
+		return err
+	case model.ChangeSetItemConfig:
+		c := tx.Config
+		cfg, err := c.Assign(field.Attrs(&model.Config{
+			Filepath: item.Path,
+			Name:     filepath.Base(item.Path),
+		})).Where(c.Filepath.Eq(item.Path)).FirstOrCreate()
+		if err != nil {
+			return err
+		}
+		a.configs = append(a.configs, cfg)
+		return nil
+	default:
+		return cosy.WrapErrorWithParams(ErrUnknownItemKind, string(item.Kind))
+	}
+}
+
+// restore puts every written file back in reverse order and reloads nginx
+// when a reload was already attempted with the new files.
+func (a *applier) restore() {
+	for i := a.written - 1; i >= 0; i-- {
+		if err := restoreItem(a.items[i]); err != nil {
+			logger.Errorf("failed to restore %s after a failed change set: %v", a.items[i].Path, err)
+		}
+	}
+	if a.reloaded {
+		nginx.Control(nginx.Reload)
+	}
+}
+
+func restoreItem(item model.ChangeSetItem) error {
+	if item.NewFile {
+		err := os.Remove(item.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(item.Path, []byte(item.OriginalContent), 0644)
+}
+
+// sync pushes the applied items to the nodes they are synced to.
+func (a *applier) sync() {
+	for _, item := range a.items {
+		switch item.Kind {
+		case model.ChangeSetItemSite:
+			go site.SyncSave(item.Name, item.Content)
+		case model.ChangeSetItemStream:
+			go stream.SyncSave(item.Name, item.Content)
+		}
+	}
+	for _, cfg := range a.configs {
+		if err := config.SyncToRemoteServer(cfg); err != nil {
+			logger.Errorf("failed to sync %s after applying a change set: %v", cfg.Filepath, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package changeset
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+var itemResources = map[model.ChangeSetItemKind]rbac.Resource{
+	model.ChangeSetItemSite:   rbac.ResourceSites,
+	model.ChangeSetItemStream: rbac.ResourceStreams,
+	model.ChangeSetItemConfig: rbac.ResourceConfigs,
+}
+
+// Authorize checks that the subject could have made every edit of the change
+// set directly, so staging or approving one never widens what a user may
+// change.
+func Authorize(subject *rbac.Subject, items []model.ChangeSetItem) error {
+	for _, item := range items {
+		resource, ok := itemResources[item.Kind]
+		if !ok {
+			return ErrUnknownItemKind
+		}
+		if !subject.Can(resource, rbac.ActionWrite) {
+			return rbac.ErrPermissionDenied
+		}
+		if !subject.CanAccessNamespace(item.NamespaceID) {
+			return rbac.ErrNamespaceDenied
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package changeset
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// reviewMutex serializes approvals so two reviewers can never apply change
+// sets touching the same file at the same time.
+var reviewMutex sync.Mutex
+
+// ItemRequest is a single edit submitted for review.
+type ItemRequest struct {
+	Kind model.ChangeSetItemKind `json:"kind" binding:"required,oneof=site stream config"`
+	// Action is what the item does to the file, it writes Content unless
+	// another action is given.
+	Action model.ChangeSetItemAction `json:"action" binding:"omitempty,oneof=rename enable disable delete duplicate maintenance client_verify"`
+	// Name is the site or stream name, or a config path that is either
+	// absolute or relative to the nginx configuration directory.
+	Name    string `json:"name" binding:"required"`
+	Content string `json:"content" binding:"required_without=Action"`
+	// NamespaceID places a new site or stream in a namespace. Existing files
+	// keep their namespace.
+	NamespaceID *uint64 `json:"namespace_id"`
+	// NewName is the name a rename or duplicate leads to.
+	NewName         string `json:"new_name"`
+	CertAuthorityID uint64 `json:"cert_authority_id"`
+	VerifyMode      string `json:"verify_mode" binding:"omitempty,oneof=on optional optional_no_ca"`
+}
+
+// Prepare resolves the edits and diffs them against the files on disk.
+func Prepare(requests []ItemRequest) ([]model.ChangeSetItem, error) {
+	if len(requests) == 0 {
+		return nil, ErrEmptyChangeSet
+	}
+
+	items := make([]model.ChangeSetItem, 0, len(requests))
+	seen := make(map[string]struct{}, len(requests))
+	for _, request := range requests {
+		var (
+			item model.ChangeSetItem
+			err  error
+		)
+		if request.Action == model.ChangeSetActionWrite {
+			item, err = prepareItem(request)
+		} else {
+			item, err = prepareAction(request)
+		}
+		if err != nil {
+			return nil, err
+		}
+		key := string(item.Action) + " " + item.Path
+		if _, exists := seen[key]; exists {
+			return nil, cosy.WrapErrorWithParams(ErrDuplicateItem, item.Name)
+		}
+		seen[key] = struct{}{}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Stage tests the prepared items in a sandbox and stores them as a pending
+// change set. It is stored even when the test fails so reviewers can see why.
+func Stage(title, description string, authorID uint64, items []model.ChangeSetItem) (*model.ChangeSet, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyChangeSet
+	}
+
+	result := testItems(items)
+	expiresAt := time.Now().Add(settings.ChangeSetSettings.GetExpiry())
+	changeSet := &model.ChangeSet{
+		Title:       title,
+		Description: description,
+		Status:      model.ChangeSetStatusPending,
+		Items:       items,
+		AuthorID:    authorID,
+		ExpiresAt:   &expiresAt,
+		TestPassed:  !result.IsError(),
+		TestOutput:  result.Message,
+	}
+	if err := query.ChangeSet.Create(changeSet); err != nil {
+		return nil, err
+	}
+	return changeSet, nil
+}
+
+// Get loads a change set by id.
+func Get(id uint64) (*model.ChangeSet, error) {
+	c := query.ChangeSet
+	return c.Where(c.ID.Eq(id)).First()
+}
+
+// Approve applies a pending change set on behalf of reviewer. The staged
+// content is tested again first, since other edits may have landed since it
+// was staged.
+func Approve(id uint64, reviewer *model.User, comment string) (*model.ChangeSet, error) {
+	reviewMutex.Lock()
+	defer reviewMutex.Unlock()
+
+	changeSet, err := loadPending(id)
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.AuthorID == reviewer.ID {
+		return nil, ErrSelfApproval
+	}
+	for _, item := range changeSet.Items {
+		if !unchangedOnDisk(item) {
+			return nil, cosy.WrapErrorWithParams(ErrConflict, item.Name)
+		}
+	}
+
+	now := time.Now()
+	changeSet.ReviewerID = reviewer.ID
+	changeSet.ReviewComment = comment
+	changeSet.ReviewedAt = &now
+
+	result := testItems(changeSet.Items)
+	changeSet.TestPassed = !result.IsError()
+	changeSet.TestOutput = result.Message
+	if !changeSet.TestPassed {
+		changeSet.Status = model.ChangeSetStatusFailed
+		if err := save(changeSet); err != nil {
+			return nil, err
+		}
+		return changeSet, cosy.WrapErrorWithParams(ErrSandboxTestFailed, result.Message)
+	}
+
+	if err := applyItems(changeSet, githistory.UserAuthor(reviewer)); err != nil {
+		changeSet.Status = model.ChangeSetStatusFailed
+		changeSet.ApplyError = err.Error()
+		if saveErr := save(changeSet); saveErr != nil {
+			return nil, saveErr
+		}
+		return changeSet, cosy.WrapErrorWithParams(ErrApplyFailed, err.Error())
+	}
+
+	changeSet.Status = model.ChangeSetStatusApplied
+	if err := save(changeSet); err != nil {
+		return nil, err
+	}
+	return changeSet, nil
+}
+
+// Reject closes a pending change set without touching the disk. The author
+// may reject their own change set to withdraw it.
+func Reject(id uint64, reviewerID uint64, comment string) (*model.ChangeSet, error) {
+	reviewMutex.Lock()
+	defer reviewMutex.Unlock()
+
+	changeSet, err := loadPending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	changeSet.Status = model.ChangeSetStatusRejected
+	changeSet.ReviewerID = reviewerID
+	changeSet.ReviewComment = comment
+	changeSet.ReviewedAt = &now
+	if err := save(changeSet); err != nil {
+		return nil, err
+	}
+	return changeSet, nil
+}
+
+// ExpireStale marks pending change sets whose review window has passed as
+// expired and returns how many were changed.
+func ExpireStale() (int64, error) {
+	c := query.ChangeSet
+	result, err := c.Where(c.Status.Eq(string(model.ChangeSetStatusPending)), c.ExpiresAt.Lt(time.Now())).
+		Update(c.Status, model.ChangeSetStatusExpired)
+	return result.RowsAffected, err
+}
+
+func loadPending(id uint64) (*model.ChangeSet, error) {
+	changeSet, err := Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.Status != model.ChangeSetStatusPending {
+		return nil, ErrNotPending
+	}
+	if changeSet.ExpiresAt != nil && changeSet.ExpiresAt.Before(time.Now()) {
+		changeSet.Status = model.ChangeSetStatusExpired
+		if err := save(changeSet); err != nil {
+			return nil, err
+		}
+		return nil, ErrExpired
+	}
+	return changeSet, nil
+}
+
+func save(changeSet *model.ChangeSet) error {
+	return query.ChangeSet.Omit(field.AssociationFields).Save(changeSet)
+}
+
+func prepareItem(request ItemRequest) (item model.ChangeSetItem, err error) {
+	item = model.ChangeSetItem{
+		Kind:    request.Kind,
+		Name:    request.Name,
+		Content: request.Content,
+	}
+
+	switch request.Kind {
+	case model.ChangeSetItemSite:
+		item.Path, err = site.ResolveAvailablePath(request.Name)
+		if err == nil {
+			item.NamespaceID, err = siteNamespaceID(item.Path)
+		}
+	case model.ChangeSetItemStream:
+		item.Path, err = stream.ResolveAvailablePath(request.Name)
+		if err == nil {
+			item.NamespaceID, err = streamNamespaceID(item.Path)
+		}
+	case model.ChangeSetItemConfig:
+		item.Path, err = config.ResolveAbsoluteOrRelativeConfPath(request.Name)
+		if err == nil {
+			item.Name, err = filepath.Rel(nginx.GetConfPath(), item.Path)
+		}
+	default:
+		return item, cosy.WrapErrorWithParams(ErrUnknownItemKind, string(request.Kind))
+	}
+	if err != nil {
+		return
+	}
+
+	if err = config.ValidateConfigFile(item.Path, item.Content); err != nil {
+		return
+	}
+
+	original, err := os.ReadFile(item.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		item.NewFile = true
+		if request.NamespaceID != nil && request.Kind != model.ChangeSetItemConfig {
+			item.NamespaceID = *request.NamespaceID
+		}
+	case err != nil:
+		return
+	default:
+		item.OriginalContent = string(original)
+	}
+
+	item.Diff, err = helper.UnifiedDiff(item.Name, item.OriginalContent, item.Content)
+	return
+}
+
+func siteNamespaceID(path string) (uint64, error) {
+	s := query.Site
+	siteModel, err := s.Where(s.Path.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return siteModel.NamespaceID, nil
+}
+
+func streamNamespaceID(path string) (uint64, error) {
+	s := query.Stream
+	streamModel, err := s.Where(s.Path.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return streamModel.NamespaceID, nil
+}
+
+// unchangedOnDisk reports whether the file still matches the content the
+// item was diffed against, or still exists for the other actions.
+func unchangedOnDisk(item model.ChangeSetItem) bool {
+	if item.Action != model.ChangeSetActionWrite {
+		_, err := os.Stat(item.Path)
+		return err == nil
+	}
+	current, err := os.ReadFile(item.Path)
+	if item.NewFile {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	return err == nil && string(current) == item.OriginalContent
+}
+
+// testItems runs nginx -t in a sandbox holding every local site and stream
+// with the staged content in place of the files on disk. The other actions
+// test the configuration themselves when they are applied.
+func testItems(items []model.ChangeSetItem) nginx.TestConfigResult {
+	overrides := make(map[string]string, len(items))
+	for _, item := range items {
+		if item.Action == model.ChangeSetActionWrite {
+			overrides[filepath.Clean(item.Path)] = item.Content
+		}
+	}
+
+	return nginx.SandboxTestConfigWithOverrides(
+		&nginx.NamespaceInfo{Name: "change-set"},
+		availablePaths("sites-available"),
+		availablePaths("streams-available"),
+		overrides,
+	)
+}
+
+func availablePaths(dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(nginx.GetConfPath(dir), "*"))
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	return paths
+}
@@ -0,0 +1,271 @@
+package changeset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChangeSetDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dbName := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.ChangeSet{}))
+	model.Use(db)
+	query.SetDefault(db)
+	return db
+}
+
+func createPending(t *testing.T, db *gorm.DB, expiresAt time.Time, items ...model.ChangeSetItem) *model.ChangeSet {
+	t.Helper()
+
+	changeSet := &model.ChangeSet{
+		Title:     "test",
+		Status:    model.ChangeSetStatusPending,
+		Items:     items,
+		AuthorID:  1,
+		ExpiresAt: &expiresAt,
+	}
+	require.NoError(t, db.Create(changeSet).Error)
+	return changeSet
+}
+
+func TestApproveRejectsSelfApproval(t *testing.T) {
+	db := setupChangeSetDB(t)
+	changeSet := createPending(t, db, time.Now().Add(time.Hour))
+
+	_, err := Approve(changeSet.ID, &model.User{Model: model.Model{ID: changeSet.AuthorID}}, "")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+}
+
+func TestApproveDetectsConflict(t *testing.T) {
+	db := setupChangeSetDB(t)
+
+	path := filepath.Join(t.TempDir(), "example.conf")
+	require.NoError(t, os.WriteFile(path, []byte("edited elsewhere\n"), 0644))
+	changeSet := createPending(t, db, time.Now().Add(time.Hour), model.ChangeSetItem{
+		Kind:            model.ChangeSetItemConfig,
+		Name:            "example.conf",
+		Path:            path,
+		Content:         "staged\n",
+		OriginalContent: "original\n",
+	})
+
+	_, err := Approve(changeSet.ID, &model.User{Model: model.Model{ID: 2}}, "")
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, ErrConflict.(*cosy.Error).Code, cErr.Code)
+
+	stored, err := Get(changeSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusPending, stored.Status)
+}
+
+func TestReviewExpiredChangeSet(t *testing.T) {
+	db := setupChangeSetDB(t)
+	changeSet := createPending(t, db, time.Now().Add(-time.Minute))
+
+	_, err := Reject(changeSet.ID, 2, "too late")
+	assert.ErrorIs(t, err, ErrExpired)
+
+	stored, err := Get(changeSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusExpired, stored.Status)
+
+	_, err = Reject(changeSet.ID, 2, "")
+	assert.ErrorIs(t, err, ErrNotPending)
+}
+
+func TestRejectKeepsReviewer(t *testing.T) {
+	db := setupChangeSetDB(t)
+	changeSet := createPending(t, db, time.Now().Add(time.Hour))
+
+	rejected, err := Reject(changeSet.ID, 2, "not now")
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusRejected, rejected.Status)
+
+	stored, err := Get(changeSet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusRejected, stored.Status)
+	assert.Equal(t, uint64(2), stored.ReviewerID)
+	assert.Equal(t, "not now", stored.ReviewComment)
+	assert.NotNil(t, stored.ReviewedAt)
+}
+
+func TestExpireStale(t *testing.T) {
+	db := setupChangeSetDB(t)
+	stale := createPending(t, db, time.Now().Add(-time.Minute))
+	fresh := createPending(t, db, time.Now().Add(time.Hour))
+
+	count, err := ExpireStale()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	stored, err := Get(stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusExpired, stored.Status)
+
+	stored, err = Get(fresh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeSetStatusPending, stored.Status)
+}
+
+func TestAuthorize(t *testing.T) {
+	site := model.ChangeSetItem{Kind: model.ChangeSetItemSite, NamespaceID: 3}
+	conf := model.ChangeSetItem{Kind: model.ChangeSetItemConfig}
+
+	assert.NoError(t, Authorize(rbac.Admin(), []model.ChangeSetItem{site, conf}))
+
+	scoped := rbac.ForScopes([]string{model.APITokenScopeWrite}, []uint64{3})
+	assert.NoError(t, Authorize(scoped, []model.ChangeSetItem{site}))
+	assert.ErrorIs(t, Authorize(scoped, []model.ChangeSetItem{conf}), rbac.ErrNamespaceDenied)
+
+	readOnly := rbac.ForScopes([]string{rbac.Permission(rbac.ResourceSites, rbac.ActionRead)}, nil)
+	assert.ErrorIs(t, Authorize(readOnly, []model.ChangeSetItem{site}), rbac.ErrPermissionDenied)
+}
+
+func TestPrepareRejectsEmptyChangeSet(t *testing.T) {
+	_, err := Prepare(nil)
+	assert.ErrorIs(t, err, ErrEmptyChangeSet)
+}
+
+func setupApplyTest(t *testing.T, testConfigCmd string) (confDir, reloads string) {
+	t.Helper()
+
+	db := setupChangeSetDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Config{}, &model.Site{}, &model.Stream{}))
+
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+	})
+
+	confDir = t.TempDir()
+	reloads = filepath.Join(t.TempDir(), "reloads")
+	settings.NginxSettings.ConfigDir = confDir
+	// Without a running nginx a reload falls back to a restart.
+	settings.NginxSettings.ReloadCmd = fmt.Sprintf("echo reload >> %q", reloads)
+	settings.NginxSettings.RestartCmd = settings.NginxSettings.ReloadCmd
+	settings.NginxSettings.TestConfigCmd = testConfigCmd
+	return
+}
+
+func TestApplyItemsReloadsOnce(t *testing.T) {
+	confDir, reloads := setupApplyTest(t, "true")
+	existing := filepath.Join(confDir, "existing.conf")
+	require.NoError(t, os.WriteFile(existing, []byte("original\n"), 0644))
+	created := filepath.Join(confDir, "created.conf")
+
+	changeSet := &model.ChangeSet{Title: "test", Items: []model.ChangeSetItem{
+		{Kind: model.ChangeSetItemConfig, Name: "existing.conf", Path: existing, Content: "staged\n", OriginalContent: "original\n"},
+		{Kind: model.ChangeSetItemConfig, Name: "created.conf", Path: created, Content: "created\n", NewFile: true},
+	}}
+	require.NoError(t, applyItems(changeSet, githistory.SystemAuthor))
+
+	assert.Equal(t, "staged\n", readFile(t, existing))
+	assert.Equal(t, "created\n", readFile(t, created))
+	assert.Equal(t, "reload\n", readFile(t, reloads))
+
+	count, err := query.Config.Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestApplyItemsRestoresEveryItemWhenNginxRejectsThem(t *testing.T) {
+	confDir, reloads := setupApplyTest(t, "false")
+	existing := filepath.Join(confDir, "existing.conf")
+	require.NoError(t, os.WriteFile(existing, []byte("original\n"), 0644))
+	created := filepath.Join(confDir, "created.conf")
+
+	changeSet := &model.ChangeSet{Title: "test", Items: []model.ChangeSetItem{
+		{Kind: model.ChangeSetItemConfig, Name: "existing.conf", Path: existing, Content: "staged\n", OriginalContent: "original\n"},
+		{Kind: model.ChangeSetItemConfig, Name: "created.conf", Path: created, Content: "created\n", NewFile: true},
+	}}
+	err := applyItems(changeSet, githistory.SystemAuthor)
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, ErrTestFailed.(*cosy.Error).Code, cErr.Code)
+
+	assert.Equal(t, "original\n", readFile(t, existing))
+	assert.NoFileExists(t, created)
+	assert.NoFileExists(t, reloads)
+
+	count, err := query.Config.Count()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestPrepareActions(t *testing.T) {
+	confDir, _ := setupApplyTest(t, "true")
+	require.NoError(t, os.WriteFile(filepath.Join(confDir, "old.conf"), []byte("old\n"), 0644))
+
+	_, err := Prepare([]ItemRequest{{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionEnable, Name: "old.conf"}})
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, ErrUnsupportedAction.(*cosy.Error).Code, cErr.Code)
+
+	_, err = Prepare([]ItemRequest{{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionRename, Name: "old.conf"}})
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, ErrNewNameRequired.(*cosy.Error).Code, cErr.Code)
+
+	_, err = Prepare([]ItemRequest{{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionDelete, Name: "missing.conf"}})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	items, err := Prepare([]ItemRequest{
+		{Kind: model.ChangeSetItemConfig, Name: "old.conf", Content: "edited\n"},
+		{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionRename, Name: "old.conf", NewName: "new.conf"},
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, filepath.Join(confDir, "old.conf"), items[1].Path)
+	assert.Equal(t, "rename config old.conf to new.conf", items[1].Diff)
+}
+
+func TestApplyItemsRunsActionsAfterWrites(t *testing.T) {
+	confDir, _ := setupApplyTest(t, "true")
+	require.NoError(t, model.UseDB().AutoMigrate(&model.LLMSession{}, &model.ConfigBackup{}))
+	old := filepath.Join(confDir, "old.conf")
+	require.NoError(t, os.WriteFile(old, []byte("original\n"), 0644))
+	unused := filepath.Join(confDir, "unused.conf")
+	require.NoError(t, os.WriteFile(unused, []byte("unused\n"), 0644))
+
+	changeSet := &model.ChangeSet{Title: "test", Items: []model.ChangeSetItem{
+		{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionRename, Name: "old.conf", Path: old, NewName: "new.conf"},
+		{Kind: model.ChangeSetItemConfig, Name: "old.conf", Path: old, Content: "staged\n", OriginalContent: "original\n"},
+		{Kind: model.ChangeSetItemConfig, Action: model.ChangeSetActionDelete, Name: "unused.conf", Path: unused},
+	}}
+	require.NoError(t, applyItems(changeSet, githistory.SystemAuthor))
+
+	assert.NoFileExists(t, old)
+	assert.Equal(t, "staged\n", readFile(t, filepath.Join(confDir, "new.conf")))
+	assert.NoFileExists(t, unused)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
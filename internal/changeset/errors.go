@@ -0,0 +1,21 @@
+package changeset
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                    = cosy.NewErrorScope("changeset")
+	ErrEmptyChangeSet    = e.New(40001, "change set has no items")
+	ErrUnknownItemKind   = e.New(40002, "unknown change set item kind: {0}")
+	ErrDuplicateItem     = e.New(40003, "file is changed more than once: {0}")
+	ErrNotPending        = e.New(40004, "change set is not pending")
+	ErrExpired           = e.New(40005, "change set has expired")
+	ErrUnsupportedAction = e.New(40006, "{0} items cannot {1}")
+	ErrNewNameRequired   = e.New(40007, "a new name is required to {0} {1}")
+	ErrSelfApproval      = e.New(40301, "a change set must be approved by another user")
+	ErrConflict          = e.New(40901, "file changed since the change set was created: {0}")
+	ErrSandboxTestFailed = e.New(50001, "nginx test failed: {0}")
+	ErrApplyFailed       = e.New(50002, "failed to apply change set: {0}")
+	ErrTestFailed        = e.New(50003, "nginx rejected the change set: {0}")
+	ErrReloadFailed      = e.New(50004, "failed to reload nginx: {0}")
+	ErrActionFailed      = e.New(50005, "failed to {0} {1}: {2}")
+)
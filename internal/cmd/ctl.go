@@ -18,6 +18,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/0xJacky/Nginx-UI/internal/manifest"
 	"github.com/urfave/cli/v3"
 )
 
@@ -37,9 +38,14 @@ var CtlCommand = &cli.Command{
 	Commands: []*cli.Command{
 		ctlAPICommand(),
 		ctlUsersCommand(),
+		ctlRolesCommand(),
+		ctlWhoamiCommand(),
 		ctlCertificatesCommand(),
 		ctlNginxCommand(),
 		ctlTokensCommand(),
+		ctlApplyCommand(),
+		ctlExportCommand(),
+		ctlLogsCommand(),
 	},
 }
 
@@ -371,17 +377,72 @@ func ctlUsersCommand() *cli.Command {
 					&cli.StringFlag{Name: "name", Required: true, Usage: "user name"},
 					&cli.BoolFlag{Name: "password-stdin", Usage: "read the password from standard input"},
 					&cli.StringFlag{Name: "password-file", Usage: "read the password from a file"},
+					&cli.StringFlag{Name: "role", Usage: "built-in or custom role (default admin)"},
+					&cli.Uint64SliceFlag{Name: "namespace", Usage: "restrict the user to a namespace ID (repeat for multiple)"},
 				},
 				Action: func(ctx context.Context, command *cli.Command) error {
 					password, err := ctlPassword(command)
 					if err != nil {
 						return err
 					}
-					return executeCtlRequest(ctx, command, http.MethodPost, "users", map[string]any{
+					payload := map[string]any{
 						"name": command.String("name"), "password": password, "status": true,
+					}
+					if role := command.String("role"); role != "" {
+						payload["role"] = role
+					}
+					if namespaceIDs := command.Uint64Slice("namespace"); len(namespaceIDs) > 0 {
+						payload["namespace_ids"] = namespaceIDs
+					}
+					return executeCtlRequest(ctx, command, http.MethodPost, "users", payload)
+				},
+			},
+		},
+	}
+}
+
+func ctlRolesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "roles",
+		Usage: "Manage roles",
+		Commands: []*cli.Command{
+			{Name: "list", Usage: "List built-in and custom roles", Action: func(ctx context.Context, command *cli.Command) error {
+				return executeCtlRequest(ctx, command, http.MethodGet, "roles", nil)
+			}},
+			{
+				Name: "create", Usage: "Create a custom role",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "name", Required: true, Usage: "role name"},
+					&cli.StringFlag{Name: "description", Usage: "role description"},
+					&cli.StringSliceFlag{Name: "permission", Required: true, Usage: "permission such as sites:write (repeat for multiple)"},
+				},
+				Action: func(ctx context.Context, command *cli.Command) error {
+					return executeCtlRequest(ctx, command, http.MethodPost, "roles", map[string]any{
+						"name":        command.String("name"),
+						"description": command.String("description"),
+						"permissions": command.StringSlice("permission"),
 					})
 				},
 			},
+			{
+				Name: "delete", Usage: "Delete a custom role",
+				Action: func(ctx context.Context, command *cli.Command) error {
+					id := command.Args().First()
+					if id == "" {
+						return errors.New("role ID is required")
+					}
+					return executeCtlRequest(ctx, command, http.MethodDelete, "roles/"+url.PathEscape(id), nil)
+				},
+			},
+		},
+	}
+}
+
+func ctlWhoamiCommand() *cli.Command {
+	return &cli.Command{
+		Name: "whoami", Usage: "Show the role, permissions and namespaces of the current token",
+		Action: func(ctx context.Context, command *cli.Command) error {
+			return executeCtlRequest(ctx, command, http.MethodGet, "permissions", nil)
 		},
 	}
 }
@@ -536,6 +597,209 @@ func ctlTokensCommand() *cli.Command {
 	}
 }
 
+func ctlApplyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "apply",
+		Usage:     "Converge the instance to a YAML or JSON manifest",
+		UsageText: "nginx-ui ctl apply -f manifest.yaml [--prune] [--dry-run]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "file", Aliases: []string{"f"}, Required: true, Usage: "manifest file"},
+			&cli.BoolFlag{Name: "prune", Usage: "delete entries missing from the manifest"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "only print the plan"},
+		},
+		Action: func(ctx context.Context, command *cli.Command) error {
+			data, err := readLimitedFile(command.String("file"))
+			if err != nil {
+				return err
+			}
+			desired, err := manifest.Parse(data)
+			if err != nil {
+				return err
+			}
+
+			apiPath := "manifest/apply"
+			if command.Bool("dry-run") {
+				apiPath = "manifest/plan"
+			}
+			client, err := newCtlClient(command)
+			if err != nil {
+				return err
+			}
+			body, err := client.do(ctx, http.MethodPost, apiPath, map[string]any{
+				"manifest": desired,
+				"prune":    command.Bool("prune"),
+			})
+			if err != nil {
+				return err
+			}
+			return writeCtlPlan(client.stdout, body, !command.Bool("dry-run"))
+		},
+	}
+}
+
+func writeCtlPlan(writer io.Writer, body []byte, applied bool) error {
+	var plan manifest.Plan
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return errors.New("Nginx UI API returned an unexpected non-JSON response")
+	}
+	if _, err := io.WriteString(writer, plan.String()); err != nil {
+		return err
+	}
+	if applied && !plan.Empty() {
+		_, err := fmt.Fprintln(writer, "Manifest applied.")
+		return err
+	}
+	return nil
+}
+
+func ctlExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export the instance as a manifest accepted by apply",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Value: "yaml", Usage: "output format, yaml or json"},
+			&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "write the manifest to a file instead of standard output"},
+		},
+		Action: func(ctx context.Context, command *cli.Command) error {
+			format := command.String("format")
+			if format != "yaml" && format != "json" {
+				return fmt.Errorf("unsupported format %q", format)
+			}
+			client, err := newCtlClient(command)
+			if err != nil {
+				return err
+			}
+			body, err := client.do(ctx, http.MethodGet, "manifest", nil)
+			if err != nil {
+				return err
+			}
+			var current manifest.Manifest
+			if err := json.Unmarshal(body, &current); err != nil {
+				return errors.New("Nginx UI API returned an unexpected non-JSON response")
+			}
+			out, err := current.Marshal(format)
+			if err != nil {
+				return err
+			}
+			if output := command.String("output"); output != "" {
+				return os.WriteFile(output, out, 0600)
+			}
+			_, err = client.stdout.Write(out)
+			return err
+		},
+	}
+}
+
+func ctlLogsCommand() *cli.Command {
+	windowFlags := []cli.Flag{
+		&cli.DurationFlag{Name: "since", Usage: "only search entries newer than this duration, such as 1h"},
+		&cli.IntFlag{Name: "limit", Usage: "maximum number of entries to return"},
+	}
+	return &cli.Command{
+		Name:  "logs",
+		Usage: "Search indexed nginx access logs",
+		Commands: []*cli.Command{
+			{
+				Name: "search", Usage: "Search access logs with a query expression such as 'status:>=500 AND NOT ip:10.0.0.0/8'",
+				ArgsUsage: "EXPRESSION",
+				Flags:     append([]cli.Flag{&cli.StringFlag{Name: "log-path", Usage: "access log to search (default log if empty)"}}, windowFlags...),
+				Action: func(ctx context.Context, command *cli.Command) error {
+					payload := ctlLogWindow(command, time.Now())
+					payload["expression"] = strings.Join(command.Args().Slice(), " ")
+					if logPath := command.String("log-path"); logPath != "" {
+						payload["log_path"] = logPath
+					}
+					return executeCtlRequest(ctx, command, http.MethodPost, "nginx_log/search", payload)
+				},
+			},
+			{
+				Name: "saved", Usage: "Manage saved log searches",
+				Commands: []*cli.Command{
+					{Name: "list", Usage: "List your saved searches", Action: func(ctx context.Context, command *cli.Command) error {
+						return executeCtlRequest(ctx, command, http.MethodGet, "nginx_log/saved_searches", nil)
+					}},
+					{
+						Name: "create", Usage: "Save a search",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "name", Required: true, Usage: "saved search name"},
+							&cli.StringFlag{Name: "expression", Required: true, Usage: "query expression"},
+							&cli.StringFlag{Name: "description", Usage: "saved search description"},
+							&cli.StringFlag{Name: "log-path", Usage: "access log to search (default log if empty)"},
+							&cli.DurationFlag{Name: "time-range", Usage: "search the entries of this duration before each run, such as 24h"},
+						},
+						Action: func(ctx context.Context, command *cli.Command) error {
+							return executeCtlRequest(ctx, command, http.MethodPost, "nginx_log/saved_searches", map[string]any{
+								"name":        command.String("name"),
+								"expression":  command.String("expression"),
+								"description": command.String("description"),
+								"log_path":    command.String("log-path"),
+								"time_range":  int64(command.Duration("time-range").Seconds()),
+							})
+						},
+					},
+					{
+						Name: "run", Usage: "Run a saved search by ID, or a shared one with --shared",
+						ArgsUsage: "ID",
+						Flags:     append([]cli.Flag{&cli.StringFlag{Name: "shared", Usage: "share link token of a search saved by another user"}}, windowFlags...),
+						Action: func(ctx context.Context, command *cli.Command) error {
+							apiPath, err := ctlSavedSearchPath(command)
+							if err != nil {
+								return err
+							}
+							return executeCtlRequest(ctx, command, http.MethodPost, apiPath+"/run", ctlLogWindow(command, time.Now()))
+						},
+					},
+					ctlSavedSearchCommand("share", "Create a share link for a saved search", http.MethodPost, "/share"),
+					ctlSavedSearchCommand("unshare", "Revoke the share link of a saved search", http.MethodDelete, "/share"),
+					ctlSavedSearchCommand("delete", "Delete a saved search", http.MethodDelete, ""),
+				},
+			},
+		},
+	}
+}
+
+func ctlSavedSearchCommand(name, usage, method, suffix string) *cli.Command {
+	return &cli.Command{
+		Name: name, Usage: usage, ArgsUsage: "ID",
+		Action: func(ctx context.Context, command *cli.Command) error {
+			id := command.Args().First()
+			if id == "" {
+				return errors.New("saved search ID is required")
+			}
+			return executeCtlRequest(ctx, command, method, "nginx_log/saved_searches/"+url.PathEscape(id)+suffix, nil)
+		},
+	}
+}
+
+// ctlSavedSearchPath resolves the API path of the saved search addressed by
+// either an ID argument or a --shared token.
+func ctlSavedSearchPath(command *cli.Command) (string, error) {
+	id, token := command.Args().First(), command.String("shared")
+	switch {
+	case id != "" && token != "":
+		return "", errors.New("use only one of a saved search ID and --shared")
+	case token != "":
+		return "nginx_log/saved_searches/shared/" + url.PathEscape(token), nil
+	case id != "":
+		return "nginx_log/saved_searches/" + url.PathEscape(id), nil
+	default:
+		return "", errors.New("saved search ID or --shared is required")
+	}
+}
+
+// ctlLogWindow converts the --since and --limit flags to a search payload.
+func ctlLogWindow(command *cli.Command, now time.Time) map[string]any {
+	payload := map[string]any{}
+	if since := command.Duration("since"); since > 0 {
+		payload["start_time"] = now.Add(-since).Unix()
+		payload["end_time"] = now.Unix()
+	}
+	if limit := command.Int("limit"); limit > 0 {
+		payload["limit"] = limit
+	}
+	return payload
+}
+
 func parseNodeID(value string) (uint64, error) {
 	if value == "" {
 		return 0, nil
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -142,3 +143,57 @@ func TestCtlPasswordLimitCountsUnicodeCharacters(t *testing.T) {
 	_, err = normalizeCtlPassword([]byte(strings.Repeat("密", 21)))
 	require.ErrorContains(t, err, "20 characters")
 }
+
+func TestWriteCtlPlanRendersChanges(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, writeCtlPlan(&output, []byte(`{"changes":[
+		{"kind":"site","name":"example.com","action":"create","diff":"+enabled: true\n"},
+		{"kind":"upstream","name":"127.0.0.1:8080","action":"delete"}
+	]}`), true))
+	assert.Contains(t, output.String(), "+ site example.com\n    +enabled: true\n")
+	assert.Contains(t, output.String(), "- upstream 127.0.0.1:8080\n")
+	assert.Contains(t, output.String(), "Plan: 1 to create, 0 to update, 1 to delete.")
+	assert.Contains(t, output.String(), "Manifest applied.")
+
+	output.Reset()
+	require.NoError(t, writeCtlPlan(&output, []byte(`{"changes":[]}`), true))
+	assert.Equal(t, "No changes. The instance matches the manifest.\n", output.String())
+}
+
+func TestCtlLogsResolvesSavedSearchAndWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	run := func(args ...string) (string, map[string]any, error) {
+		var apiPath string
+		var window map[string]any
+		command := &cli.Command{
+			Name: "run",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "shared"},
+				&cli.DurationFlag{Name: "since"},
+				&cli.IntFlag{Name: "limit"},
+			},
+			Action: func(_ context.Context, command *cli.Command) error {
+				path, err := ctlSavedSearchPath(command)
+				apiPath, window = path, ctlLogWindow(command, now)
+				return err
+			},
+		}
+		err := command.Run(context.Background(), append([]string{"run"}, args...))
+		return apiPath, window, err
+	}
+
+	apiPath, window, err := run("--since", "1h", "--limit", "20", "7")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx_log/saved_searches/7", apiPath)
+	assert.Equal(t, map[string]any{"start_time": int64(1_700_000_000 - 3600), "end_time": int64(1_700_000_000), "limit": 20}, window)
+
+	apiPath, window, err = run("--shared", "abc/def")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx_log/saved_searches/shared/abc%2Fdef", apiPath)
+	assert.Empty(t, window)
+
+	_, _, err = run("--shared", "abc", "7")
+	require.ErrorContains(t, err, "only one")
+	_, _, err = run()
+	require.ErrorContains(t, err, "is required")
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/query"
@@ -112,3 +113,34 @@ func CheckFileExists(fullPath string) (os.FileInfo, error) {
 	}
 	return stat, nil
 }
+
+// Delete removes a config file or directory along with its records, then
+// deletes it on syncNodeIDs. name is the name the caller addressed it by,
+// which is checked against the protected directories.
+func Delete(fullPath, name string, syncNodeIDs []uint64, author githistory.Author) error {
+	if err := ValidateDeletePath(fullPath); err != nil {
+		return err
+	}
+	if IsProtectedPath(fullPath, name) {
+		return ErrCannotDeleteProtectedPath
+	}
+
+	stat, err := CheckFileExists(fullPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(fullPath); err != nil {
+		return err
+	}
+	if err := CleanupDatabaseRecords(fullPath, stat.IsDir()); err != nil {
+		return err
+	}
+
+	githistory.Record(author, "Delete config "+filepath.Base(fullPath), fullPath)
+
+	if len(syncNodeIDs) > 0 {
+		return SyncDeleteOnRemoteServer(fullPath, syncNodeIDs)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+)
+
+// IsKeyMaterial reports whether a config file holds certificates or their
+// keys: everything below ssl/, any *.key file and any file carrying a PEM
+// private key. Pass nil content when only the path is known.
+func IsKeyMaterial(absPath string, content []byte) bool {
+	if filepath.Ext(absPath) == ".key" {
+		return true
+	}
+	rel, err := filepath.Rel(nginx.GetConfPath(), absPath)
+	if err == nil {
+		rel = filepath.ToSlash(rel)
+		if rel == "ssl" || strings.HasPrefix(rel, "ssl/") {
+			return true
+		}
+	}
+	return bytes.Contains(content, []byte("PRIVATE KEY-----"))
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+// Rename moves a config file or directory and carries its records along, then
+// renames it on the nodes it is synced to. A file follows its own sync policy,
+// syncNodeIDs only applies to directories.
+func Rename(origFullPath, newFullPath string, syncNodeIDs []uint64, author githistory.Author) error {
+	stat, err := os.Stat(origFullPath)
+	if err != nil {
+		return err
+	}
+	if !stat.IsDir() {
+		if err := ValidateConfigFilename(newFullPath); err != nil {
+			return err
+		}
+	}
+	if helper.FileExists(newFullPath) {
+		return cosy.WrapErrorWithParams(ErrDstFileExists, newFullPath)
+	}
+
+	if err := os.Rename(origFullPath, newFullPath); err != nil {
+		return err
+	}
+
+	// update LLM records
+	g := query.LLMSession
+	q := query.Config
+	cfg, err := q.Where(q.Filepath.Eq(origFullPath)).FirstOrInit()
+	if err != nil {
+		return err
+	}
+	if !stat.IsDir() {
+		_, _ = g.Where(g.Path.Eq(newFullPath)).Delete()
+		_, _ = g.Where(g.Path.Eq(origFullPath)).Update(g.Path, newFullPath)
+		syncNodeIDs = cfg.SyncNodeIds
+	} else {
+		// is directory, update all records under the directory
+		_, _ = g.Where(g.Path.Like(origFullPath+"%")).Update(g.Path, g.Path.Replace(origFullPath, newFullPath))
+	}
+
+	newName := filepath.Base(newFullPath)
+	_, err = q.Where(q.Filepath.Eq(origFullPath)).Updates(&model.Config{
+		Filepath: newFullPath,
+		Name:     newName,
+	})
+	if err != nil {
+		return err
+	}
+
+	b := query.ConfigBackup
+	_, _ = b.Where(b.FilePath.Eq(origFullPath)).Updates(map[string]interface{}{
+		"filepath": newFullPath,
+		"name":     newName,
+	})
+
+	githistory.Record(author, "Rename config "+filepath.Base(origFullPath)+" to "+newName, origFullPath, newFullPath)
+
+	if len(syncNodeIDs) > 0 {
+		return SyncRenameOnRemoteServer(origFullPath, newFullPath, syncNodeIDs)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/model"
@@ -12,7 +13,9 @@ import (
 	"gorm.io/gen/field"
 )
 
-func Save(absPath string, content string, cfg *model.Config) (err error) {
+// Save writes a configuration file, reloads nginx and records the file in the
+// git history as author.
+func Save(absPath string, content string, cfg *model.Config, author githistory.Author) (err error) {
 	q := query.Config
 	if cfg == nil {
 		cfg, err = q.Assign(field.Attrs(&model.Config{
@@ -48,6 +51,8 @@ func Save(absPath string, content string, cfg *model.Config) (err error) {
 		return res.GetError()
 	}
 
+	githistory.Record(author, "Save config "+filepath.Base(absPath), absPath)
+
 	err = SyncToRemoteServer(cfg)
 	if err != nil {
 		return
@@ -34,6 +34,20 @@ func setupCertExpiredJob(scheduler gocron.Scheduler) (gocron.Job, error) {
 	return job, nil
 }
 
+// setupPrivateCACertRenewalJob initializes the renewal job of the private CA
+// certificates and CRLs
+func setupPrivateCACertRenewalJob(scheduler gocron.Scheduler) (gocron.Job, error) {
+	job, err := scheduler.NewJob(gocron.DurationJob(30*time.Minute),
+		gocron.NewTask(cert.RenewPrivateCACerts),
+		gocron.WithSingletonMode(gocron.LimitModeWait),
+		gocron.JobOption(gocron.WithStartImmediately()))
+	if err != nil {
+		logger.Errorf("PrivateCACertRenewal Job: Err: %v\n", err)
+		return nil, err
+	}
+	return job, nil
+}
+
 // setupSelfSignedCertRenewalJob initializes the self-signed certificate renewal job
 func setupSelfSignedCertRenewalJob(scheduler gocron.Scheduler) (gocron.Job, error) {
 	job, err := scheduler.NewJob(gocron.DurationJob(30*time.Minute),
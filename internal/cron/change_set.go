@@ -0,0 +1,31 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/changeset"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// setupChangeSetExpiryJob initializes the job to expire change sets that were
+// not reviewed in time
+func setupChangeSetExpiryJob(scheduler gocron.Scheduler) (gocron.Job, error) {
+	job, err := scheduler.NewJob(
+		gocron.DurationJob(5*time.Minute),
+		gocron.NewTask(func() {
+			logger.Debug("expire stale change sets")
+			if _, err := changeset.ExpireStale(); err != nil {
+				logger.Error(err)
+			}
+		}),
+		gocron.WithSingletonMode(gocron.LimitModeWait),
+		gocron.JobOption(gocron.WithStartImmediately()))
+
+	if err != nil {
+		logger.Errorf("ChangeSetExpiry Err: %v\n", err)
+		return nil, err
+	}
+
+	return job, nil
+}
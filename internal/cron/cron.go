@@ -45,6 +45,24 @@ func InitCronJobs(ctx context.Context) {
 		logger.Fatalf("SelfSignedCertRenewal Err: %v\n", err)
 	}
 
+	// Initialize private CA certificate and CRL renewal job
+	_, err = setupPrivateCACertRenewalJob(s)
+	if err != nil {
+		logger.Fatalf("PrivateCACertRenewal Err: %v\n", err)
+	}
+
+	// Initialize Certificate Transparency monitor job
+	_, err = setupCTMonitorJob(s)
+	if err != nil {
+		logger.Fatalf("CTMonitor Err: %v\n", err)
+	}
+
+	// Initialize OCSP status and stapling check job
+	_, err = setupOCSPCheckJob(s)
+	if err != nil {
+		logger.Fatalf("OCSPCheck Err: %v\n", err)
+	}
+
 	// Start logrotate job
 	setupLogrotateJob(s)
 
@@ -60,6 +78,12 @@ func InitCronJobs(ctx context.Context) {
 		logger.Fatalf("NodeCredentialMaintenance Err: %v\n", err)
 	}
 
+	// Initialize change set expiry job
+	_, err = setupChangeSetExpiryJob(s)
+	if err != nil {
+		logger.Fatalf("ChangeSetExpiry Err: %v\n", err)
+	}
+
 	// Initialize auto backup jobs
 	err = setupAutoBackupJobs(s)
 	if err != nil {
@@ -71,6 +95,22 @@ func InitCronJobs(ctx context.Context) {
 		logger.Fatalf("DDNS Err: %v\n", err)
 	}
 
+	// Initialize traffic alert jobs
+	if err := setupTrafficAlertJobs(s); err != nil {
+		logger.Fatalf("TrafficAlert Err: %v\n", err)
+	}
+
+	// Initialize upstream health check jobs
+	if err := setupUpstreamHealthJobs(s); err != nil {
+		logger.Fatalf("UpstreamHealth Err: %v\n", err)
+	}
+
+	// Initialize jail ban expiry job
+	_, err = setupJailBanExpiryJob(s)
+	if err != nil {
+		logger.Fatalf("JailBanExpiry Err: %v\n", err)
+	}
+
 	// Initialize upstream availability testing job
 	_, err = setupUpstreamAvailabilityJob(s)
 	if err != nil {
@@ -83,6 +123,12 @@ func InitCronJobs(ctx context.Context) {
 		logger.Fatalf("IncrementalIndexing Err: %v\n", err)
 	}
 
+	// Initialize log rollup and retention job
+	_, err = setupLogRetentionJob(s)
+	if err != nil {
+		logger.Fatalf("LogRetention Err: %v\n", err)
+	}
+
 	// Initialize automatic namespace replication job
 	_, err = setupNamespaceSyncJob(s)
 	if err != nil {
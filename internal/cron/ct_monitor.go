@@ -0,0 +1,23 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/ctmonitor"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// setupCTMonitorJob initializes the Certificate Transparency monitor, which
+// checks the logs every CTMonitorInterval hours
+func setupCTMonitorJob(scheduler gocron.Scheduler) (gocron.Job, error) {
+	job, err := scheduler.NewJob(gocron.DurationJob(time.Hour),
+		gocron.NewTask(ctmonitor.Check),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		gocron.JobOption(gocron.WithStartImmediately()))
+	if err != nil {
+		logger.Errorf("CTMonitor Job: Err: %v\n", err)
+		return nil, err
+	}
+	return job, nil
+}
@@ -83,8 +83,7 @@ func performIncrementalIndexing() {
 
 	// Get all log groups to check for changes
 	allLogs := nginx_log.GetAllLogsWithIndexGrouped(func(log *nginx_log.NginxLogWithIndex) bool {
-		// Only process access logs (skip error logs as they are not indexed)
-		return log.Type == "access"
+		return log.Type == "access" || log.Type == "error"
 	})
 
 	// Process files sequentially to avoid overwhelming the system
@@ -93,6 +92,15 @@ func performIncrementalIndexing() {
 	for _, log := range allLogs {
 		// Check if file needs incremental indexing
 		if needsIncrementalIndexing(log, persistence) {
+			if log.Type == "error" {
+				// Error log groups are small and re-indexed as a whole; the
+				// status is tracked by IndexErrorLogGroup itself.
+				if _, _, err := nginx_log.IndexErrorLogGroup(context.Background(), log.Path); err != nil {
+					logger.Errorf("Failed to index error log %s: %v", log.Path, err)
+				}
+				continue
+			}
+
 			logger.Debugf("Starting incremental indexing for file: %s", log.Path)
 
 			// Set status to indexing
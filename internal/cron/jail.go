@@ -0,0 +1,31 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/jail"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// setupJailBanExpiryJob initializes the job to lift jail bans whose ban time
+// is over
+func setupJailBanExpiryJob(scheduler gocron.Scheduler) (gocron.Job, error) {
+	job, err := scheduler.NewJob(
+		gocron.DurationJob(time.Minute),
+		gocron.NewTask(func() {
+			logger.Debug("expire jail bans")
+			if _, err := jail.ExpireBans(); err != nil {
+				logger.Error(err)
+			}
+		}),
+		gocron.WithSingletonMode(gocron.LimitModeWait),
+		gocron.JobOption(gocron.WithStartImmediately()))
+
+	if err != nil {
+		logger.Errorf("JailBanExpiry Err: %v\n", err)
+		return nil, err
+	}
+
+	return job, nil
+}
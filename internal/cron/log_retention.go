@@ -0,0 +1,45 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/rollup"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// setupLogRetentionJob sets up the hourly job rolling up indexed access logs
+// and purging the raw documents older than their retention
+func setupLogRetentionJob(s gocron.Scheduler) (gocron.Job, error) {
+	job, err := s.NewJob(
+		gocron.DurationJob(time.Hour),
+		gocron.NewTask(performLogRetention),
+		gocron.WithName("log_retention"),
+		gocron.WithSingletonMode(gocron.LimitModeWait),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Log rollup and retention job scheduled to run every hour")
+	return job, nil
+}
+
+func performLogRetention() {
+	if !shouldRunIncrementalIndexing() {
+		return
+	}
+
+	searcherService := nginx_log.GetSearcher()
+	modernIndexer := nginx_log.GetIndexer()
+	if searcherService == nil || modernIndexer == nil || !searcherService.IsHealthy() || !modernIndexer.IsHealthy() {
+		logger.Debug("Log searcher or indexer not available, skipping rollup and retention")
+		return
+	}
+
+	if err := rollup.Run(context.Background(), searcherService, modernIndexer); err != nil {
+		logger.Errorf("Log rollup and retention failed: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/ocspcheck"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// setupOCSPCheckJob initializes the job checking the OCSP status and the
+// stapling of the deployed certificates every 6 hours
+func setupOCSPCheckJob(scheduler gocron.Scheduler) (gocron.Job, error) {
+	job, err := scheduler.NewJob(gocron.DurationJob(6*time.Hour),
+		gocron.NewTask(ocspcheck.Check),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule))
+	if err != nil {
+		logger.Errorf("OCSPCheck Job: Err: %v\n", err)
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/trafficalert"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var (
+	trafficAlertJobs = make(map[uint64]gocron.Job)
+	trafficAlertMu   sync.RWMutex
+)
+
+func setupTrafficAlertJobs(s gocron.Scheduler) error {
+	rules, err := trafficalert.GetEnabledRules()
+	if err != nil {
+		return fmt.Errorf("load traffic alert rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if err := addTrafficAlertJob(s, rule.ID, rule.IntervalSeconds); err != nil {
+			logger.Errorf("Add traffic alert job %d failed: %v", rule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func addTrafficAlertJob(s gocron.Scheduler, ruleID uint64, intervalSeconds int) error {
+	if intervalSeconds <= 0 {
+		return fmt.Errorf("invalid traffic alert interval for rule %d", ruleID)
+	}
+
+	trafficAlertMu.Lock()
+	defer trafficAlertMu.Unlock()
+
+	if job, exists := trafficAlertJobs[ruleID]; exists {
+		if err := s.RemoveJob(job.ID()); err != nil {
+			logger.Warnf("Remove existing traffic alert job %d failed: %v", ruleID, err)
+		}
+		delete(trafficAlertJobs, ruleID)
+	}
+
+	job, err := s.NewJob(
+		gocron.DurationJob(time.Duration(intervalSeconds)*time.Second),
+		gocron.NewTask(executeTrafficAlertJob, ruleID),
+		gocron.WithName(fmt.Sprintf("traffic_alert_%d", ruleID)),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return fmt.Errorf("create traffic alert job: %w", err)
+	}
+
+	trafficAlertJobs[ruleID] = job
+	logger.Infof("Added traffic alert job %d with interval %ds", ruleID, intervalSeconds)
+	return nil
+}
+
+func removeTrafficAlertJob(s gocron.Scheduler, ruleID uint64) error {
+	trafficAlertMu.Lock()
+	defer trafficAlertMu.Unlock()
+
+	if job, exists := trafficAlertJobs[ruleID]; exists {
+		if err := s.RemoveJob(job.ID()); err != nil {
+			return fmt.Errorf("remove traffic alert job: %w", err)
+		}
+		delete(trafficAlertJobs, ruleID)
+		logger.Infof("Removed traffic alert job %d", ruleID)
+	}
+	return nil
+}
+
+// AddOrUpdateTrafficAlertJob adds or replaces a traffic alert job using the
+// global scheduler.
+func AddOrUpdateTrafficAlertJob(ruleID uint64, intervalSeconds int) error {
+	return addTrafficAlertJob(s, ruleID, intervalSeconds)
+}
+
+// RemoveTrafficAlertJob removes a traffic alert job from the global scheduler.
+func RemoveTrafficAlertJob(ruleID uint64) error {
+	return removeTrafficAlertJob(s, ruleID)
+}
+
+func executeTrafficAlertJob(ruleID uint64) {
+	if err := trafficalert.EvaluateByID(context.Background(), ruleID); err != nil {
+		logger.Errorf("Traffic alert job %d failed: %v", ruleID, err)
+	}
+}
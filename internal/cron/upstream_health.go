@@ -0,0 +1,94 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/upstreamhealth"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var (
+	upstreamHealthJobs = make(map[uint64]gocron.Job)
+	upstreamHealthMu   sync.Mutex
+)
+
+func setupUpstreamHealthJobs(s gocron.Scheduler) error {
+	checks, err := upstreamhealth.GetEnabledChecks()
+	if err != nil {
+		return fmt.Errorf("load upstream health checks: %w", err)
+	}
+
+	for _, check := range checks {
+		if err := addUpstreamHealthJob(s, check.ID, check.IntervalSeconds); err != nil {
+			logger.Errorf("Add upstream health job %d failed: %v", check.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func addUpstreamHealthJob(s gocron.Scheduler, checkID uint64, intervalSeconds int) error {
+	if intervalSeconds <= 0 {
+		return fmt.Errorf("invalid upstream health interval for check %d", checkID)
+	}
+
+	upstreamHealthMu.Lock()
+	defer upstreamHealthMu.Unlock()
+
+	if job, exists := upstreamHealthJobs[checkID]; exists {
+		if err := s.RemoveJob(job.ID()); err != nil {
+			logger.Warnf("Remove existing upstream health job %d failed: %v", checkID, err)
+		}
+		delete(upstreamHealthJobs, checkID)
+	}
+
+	job, err := s.NewJob(
+		gocron.DurationJob(time.Duration(intervalSeconds)*time.Second),
+		gocron.NewTask(executeUpstreamHealthJob, checkID),
+		gocron.WithName(fmt.Sprintf("upstream_health_%d", checkID)),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return fmt.Errorf("create upstream health job: %w", err)
+	}
+
+	upstreamHealthJobs[checkID] = job
+	logger.Infof("Added upstream health job %d with interval %ds", checkID, intervalSeconds)
+	return nil
+}
+
+func removeUpstreamHealthJob(s gocron.Scheduler, checkID uint64) error {
+	upstreamHealthMu.Lock()
+	defer upstreamHealthMu.Unlock()
+
+	if job, exists := upstreamHealthJobs[checkID]; exists {
+		if err := s.RemoveJob(job.ID()); err != nil {
+			return fmt.Errorf("remove upstream health job: %w", err)
+		}
+		delete(upstreamHealthJobs, checkID)
+		logger.Infof("Removed upstream health job %d", checkID)
+	}
+	return nil
+}
+
+// AddOrUpdateUpstreamHealthJob adds or replaces an upstream health check job
+// using the global scheduler.
+func AddOrUpdateUpstreamHealthJob(checkID uint64, intervalSeconds int) error {
+	return addUpstreamHealthJob(s, checkID, intervalSeconds)
+}
+
+// RemoveUpstreamHealthJob removes an upstream health check job from the global
+// scheduler.
+func RemoveUpstreamHealthJob(checkID uint64) error {
+	return removeUpstreamHealthJob(s, checkID)
+}
+
+func executeUpstreamHealthJob(checkID uint64) {
+	if err := upstreamhealth.RunByID(context.Background(), checkID); err != nil {
+		logger.Errorf("Upstream health job %d failed: %v", checkID, err)
+	}
+}
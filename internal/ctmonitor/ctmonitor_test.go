@@ -0,0 +1,288 @@
+package ctmonitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var testNow = time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+func TestNewSource(t *testing.T) {
+	source, err := NewSource("")
+	require.NoError(t, err)
+	assert.Equal(t, &crtshSource{baseURL: DefaultSource}, source)
+
+	_, err = NewSource("file:///var/lib/ct.json")
+	requireErrorCode(t, err, ErrInvalidSource)
+
+	_, err = NewSource("ftp://ct.example.com")
+	requireErrorCode(t, err, ErrInvalidSource)
+}
+
+func TestIssuerOrganization(t *testing.T) {
+	assert.Equal(t, "let's encrypt", issuerOrganization("C=US, O=Let's Encrypt, CN=R10"))
+	assert.Equal(t, "digicert, inc.", issuerOrganization(`C=US, O="DigiCert, Inc.", CN=DigiCert Global G2 TLS RSA SHA256 2020 CA1`))
+	assert.Equal(t, "digicert, inc.", issuerOrganization(`CN=DigiCert TLS,O=DigiCert\, Inc.,C=US`))
+	assert.Equal(t, "internal ca", issuerOrganization("CN=Internal CA, OU=Ops"))
+}
+
+func TestTargetsSkipsNamesCoveredByDNSDomains(t *testing.T) {
+	db := setupMonitorTest(t)
+	require.NoError(t, db.Create(&model.DnsDomain{Domain: "Example.com", DnsCredentialID: 1}).Error)
+	require.NoError(t, db.Create(&model.Cert{Name: "a", Domains: []string{"www.example.com", "*.example.org", "10.0.0.1"}}).Error)
+	require.NoError(t, db.Create(&model.Cert{Name: "b", Domains: []string{"internal.test"}, AutoCert: model.AutoCertSelfSigned}).Error)
+
+	targets, err := Targets()
+	require.NoError(t, err)
+	assert.Equal(t, []Target{
+		{Domain: "example.com", IncludeSubdomains: true},
+		{Domain: "example.org"},
+	}, targets)
+}
+
+func TestRunFlagsCertificatesIssuedElsewhere(t *testing.T) {
+	db := setupMonitorTest(t)
+	confDir := settings.NginxSettings.ConfigDir
+
+	certPath := writeCertificate(t, filepath.Join(confDir, "ssl", "example.com", "fullchain.cer"), 0x1001, "Let's Encrypt")
+	managed := &model.Cert{Name: "example.com", Domains: []string{"example.com", "www.example.com"}, SSLCertificatePath: certPath}
+	require.NoError(t, db.Create(managed).Error)
+
+	fixture := filepath.Join(t.TempDir(), "ct.json")
+	newSource = func(string) (Source, error) { return &fileSource{path: fixture}, nil }
+	t.Cleanup(func() { newSource = NewSource })
+	writeFixture(t, fixture, []*Entry{
+		entry(1, "C=US, O=Let's Encrypt, CN=R10", "example.com\nwww.example.com", "1001"),
+		entry(2, "C=US, O=Let's Encrypt, CN=R11", "example.com", "0a"),
+	})
+
+	// The first check only records what is already logged.
+	result, err := Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Targets: 2, New: 2, Unknown: 1}, result)
+	assertNotificationCount(t, db, 0)
+
+	writeFixture(t, fixture, []*Entry{
+		entry(1, "C=US, O=Let's Encrypt, CN=R10", "example.com\nwww.example.com", "1001"),
+		entry(2, "C=US, O=Let's Encrypt, CN=R11", "example.com", "0a"),
+		entry(3, `C=US, O="Rogue, Inc.", CN=Rogue CA`, "example.com\n*.example.com", "0b"),
+		{ID: 4, IssuerName: "O=Rogue", NameValue: "example.com", SerialNumber: "0c", NotBefore: "2025-01-01T00:00:00", NotAfter: "2025-04-01T00:00:00"},
+	})
+	result, err = Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Targets: 2, New: 1, Unknown: 1}, result, "expired certificates are ignored")
+	assertNotificationCount(t, db, 1)
+
+	var known model.CTLogEntry
+	require.NoError(t, db.Where("serial_number = ?", "1001").First(&known).Error)
+	assert.True(t, known.Known)
+	assert.Equal(t, managed.ID, known.CertID)
+
+	var renewed model.CTLogEntry
+	require.NoError(t, db.Where("serial_number = ?", "a").First(&renewed).Error)
+	assert.False(t, renewed.Known)
+	assert.False(t, renewed.UnexpectedIssuer, "the issuer already issued a deployed certificate")
+
+	require.NoError(t, db.First(managed, managed.ID).Error)
+	assert.Equal(t, []string{`C=US, O="Rogue, Inc.", CN=Rogue CA`}, managed.CTUnexpectedIssuers)
+
+	var rogue model.CTLogEntry
+	require.NoError(t, db.Where("serial_number = ?", "b").First(&rogue).Error)
+	_, err = Acknowledge(rogue.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.First(managed, managed.ID).Error)
+	assert.Empty(t, managed.CTUnexpectedIssuers)
+
+	// A certificate deployed after it was logged is no longer unknown.
+	writeCertificate(t, certPath, 0x0a, "Let's Encrypt")
+	_, err = Run(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, db.First(&renewed, renewed.ID).Error)
+	assert.True(t, renewed.Known)
+}
+
+// fileSource answers searches from a JSON file in the format of crt.sh.
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Search(_ context.Context, domain string, includeSubdomains bool) ([]*Entry, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceRequest, err.Error())
+	}
+	var all []*Entry
+	if err := json.Unmarshal(content, &all); err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceDecode, err.Error())
+	}
+
+	var entries []*Entry
+	for _, entry := range all {
+		for _, name := range entry.Names() {
+			if name == domain || includeSubdomains && strings.HasSuffix(name, "."+domain) {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+// failingSource fails the searches for the domains in failing and answers
+// the others with the entries naming them.
+type failingSource struct {
+	entries []*Entry
+	failing map[string]bool
+}
+
+func (s *failingSource) Search(_ context.Context, domain string, _ bool) ([]*Entry, error) {
+	if s.failing[domain] {
+		return nil, cosy.WrapErrorWithParams(ErrSourceStatus, "502")
+	}
+	var entries []*Entry
+	for _, entry := range s.entries {
+		if slices.Contains(entry.Names(), domain) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestRunBaselinesOnlyTheFirstSuccessfulCheck(t *testing.T) {
+	db := setupMonitorTest(t)
+	require.NoError(t, db.Create(&model.Cert{Name: "a", Domains: []string{"example.com"}}).Error)
+	require.NoError(t, db.Create(&model.Cert{Name: "b", Domains: []string{"example.org"}}).Error)
+
+	source := &failingSource{failing: map[string]bool{"example.org": true}}
+	newSource = func(string) (Source, error) { return source, nil }
+	t.Cleanup(func() { newSource = NewSource })
+
+	// A failed search is reported without stopping the other targets.
+	result, err := Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Targets: 2, Failed: 1}, result)
+	assertNotificationCount(t, db, 1)
+
+	// example.com was checked before, even though nothing was logged, while
+	// this is the first check that reaches example.org.
+	source.failing = nil
+	source.entries = []*Entry{
+		entry(1, "O=Rogue", "example.com", "0b"),
+		entry(2, "O=Rogue", "example.org", "0c"),
+	}
+	result, err = Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Targets: 2, New: 2, Unknown: 2}, result)
+	assertNotificationCount(t, db, 2)
+
+	source.failing = map[string]bool{"example.com": true, "example.org": true}
+	_, err = Run(t.Context())
+	requireErrorCode(t, err, ErrSourceStatus)
+}
+
+func entry(id int64, issuer, names, serial string) *Entry {
+	return &Entry{
+		ID:           id,
+		IssuerName:   issuer,
+		CommonName:   names,
+		NameValue:    names,
+		SerialNumber: serial,
+		NotBefore:    "2026-09-01T00:00:00",
+		NotAfter:     "2026-11-30T00:00:00",
+	}
+}
+
+func writeFixture(t *testing.T, path string, entries []*Entry) {
+	t.Helper()
+	content, err := json.Marshal(entries)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0644))
+}
+
+// writeCertificate writes a certificate with the given serial number, issued
+// by a CA of the given organization.
+func writeCertificate(t *testing.T, path string, serial int64, organization string) string {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "R10", Organization: []string{organization}},
+		NotBefore:             testNow.AddDate(0, -1, 0),
+		NotAfter:              testNow.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    testNow.AddDate(0, -1, 0),
+		NotAfter:     testNow.AddDate(0, 2, 0),
+	}, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	return path
+}
+
+func setupMonitorTest(t *testing.T) *gorm.DB {
+	t.Helper()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Cert{}, &model.DnsDomain{}, &model.CTLogEntry{}, &model.CertSerial{},
+		&model.CTMonitorTarget{}, &model.Notification{}, &model.ExternalNotify{}))
+	model.Use(db)
+	query.SetDefault(db)
+	settings.NginxSettings.ConfigDir = t.TempDir()
+	ctNow = func() time.Time { return testNow }
+
+	t.Cleanup(func() {
+		model.Use(nil)
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		ctNow = time.Now
+	})
+	return db
+}
+
+func assertNotificationCount(t *testing.T, db *gorm.DB, want int64) {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&model.Notification{}).Count(&count).Error)
+	assert.Equal(t, want, count)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
@@ -0,0 +1,11 @@
+package ctmonitor
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                = cosy.NewErrorScope("ct_monitor")
+	ErrInvalidSource = e.New(40001, "invalid CT log source: {0}")
+	ErrSourceRequest = e.New(50001, "query CT log source error: {0}")
+	ErrSourceStatus  = e.New(50002, "CT log source responded with status {0}")
+	ErrSourceDecode  = e.New(50003, "decode CT log source response error: {0}")
+)
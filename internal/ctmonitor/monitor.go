@@ -0,0 +1,438 @@
+package ctmonitor
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/cert"
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var (
+	mutex     sync.Mutex
+	ctNow     = time.Now
+	newSource = NewSource
+	lastCheck time.Time
+	// issuerOrganizationRe extracts the organization of a distinguished name,
+	// which may be quoted or contain escaped commas.
+	issuerOrganizationRe = regexp.MustCompile(`(?:^|,)\s*O=("(?:[^"\\]|\\.)*"|(?:[^,\\]|\\.)*)`)
+)
+
+// Target is a name watched in the CT logs.
+type Target struct {
+	Domain            string `json:"domain"`
+	IncludeSubdomains bool   `json:"include_subdomains"`
+}
+
+// Result summarizes a check.
+type Result struct {
+	Targets int `json:"targets"`
+	New     int `json:"new"`
+	Unknown int `json:"unknown"`
+	// Failed counts the targets whose search failed, they are searched
+	// again on the next check.
+	Failed int `json:"failed"`
+}
+
+// Check runs a check from the background job once the configured interval
+// has passed, so a changed interval applies without a restart.
+func Check() {
+	defer func() {
+		if err := recover(); err != nil {
+			buf := make([]byte, 1024)
+			runtime.Stack(buf, false)
+			logger.Errorf("%s\n%s", err, buf)
+		}
+	}()
+	interval := time.Duration(settings.CertSettings.CTMonitorInterval) * time.Hour
+	now := ctNow()
+	if interval <= 0 || now.Sub(lastCheck) < interval {
+		return
+	}
+	lastCheck = now
+	if _, err := Run(context.Background()); err != nil {
+		logger.Error("CT monitor:", err)
+	}
+}
+
+// Run searches the CT logs for the certificates of the managed domains and
+// records them. A certificate whose serial number was never deployed by Nginx
+// UI raises a notification, except on the first successful check of a domain,
+// which only records what is already logged. A failed search is reported and
+// skipped; Run only fails when every search did.
+func Run(ctx context.Context) (*Result, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	source, err := newSource(settings.CertSettings.CTLogSource)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := Targets()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Targets: len(targets)}
+	found := make(map[Target][]*Entry, len(targets))
+	var searchErr error
+	for _, target := range targets {
+		entries, err := source.Search(ctx, target.Domain, target.IncludeSubdomains)
+		if err != nil {
+			logger.Errorf("CT monitor: search %s: %v", target.Domain, err)
+			notifySearchFailed(target.Domain, err)
+			result.Failed++
+			searchErr = err
+			continue
+		}
+		found[target] = entries
+	}
+	if len(targets) > 0 && result.Failed == len(targets) {
+		return nil, searchErr
+	}
+
+	// The deployed certificates are recorded after the search, so one issued
+	// in the meantime can not show up as unknown.
+	serials, issuers, err := recordDeployedSerials()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reconcileKnownEntries(serials); err != nil {
+		return nil, err
+	}
+
+	l := query.CTLogEntry
+	now := ctNow()
+	for _, target := range targets {
+		if _, ok := found[target]; !ok {
+			continue
+		}
+		m := query.CTMonitorTarget
+		checked, err := m.Where(m.Domain.Eq(target.Domain)).Count()
+		if err != nil {
+			return nil, err
+		}
+		baseline := checked == 0
+
+		for _, entry := range found[target] {
+			logEntry, err := newLogEntry(target.Domain, entry)
+			if err != nil {
+				logger.Warnf("CT monitor: skip entry %d of %s: %v", entry.ID, target.Domain, err)
+				continue
+			}
+			if !logEntry.NotAfter.After(now) {
+				continue
+			}
+
+			count, err := l.Where(l.IssuerName.Eq(logEntry.IssuerName), l.SerialNumber.Eq(logEntry.SerialNumber)).Count()
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				continue
+			}
+
+			logEntry.CertID, logEntry.Known = serials[logEntry.SerialNumber]
+			logEntry.UnexpectedIssuer = !logEntry.Known && !issuers[issuerOrganization(logEntry.IssuerName)]
+			if err := l.Create(logEntry); err != nil {
+				return nil, err
+			}
+			result.New++
+			if logEntry.Known {
+				continue
+			}
+			result.Unknown++
+			if !baseline {
+				notifyUnknown(logEntry)
+			}
+		}
+		if err := markChecked(target.Domain, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := updateCertFlags(now); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Acknowledge marks an unknown certificate as expected, which clears the flag
+// on the managed certificates it covers.
+func Acknowledge(id uint64) (*model.CTLogEntry, error) {
+	l := query.CTLogEntry
+	logEntry, err := l.Where(l.ID.Eq(id)).First()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := l.Where(l.ID.Eq(id)).Update(l.Acknowledged, true); err != nil {
+		return nil, err
+	}
+	logEntry.Acknowledged = true
+	if err := updateCertFlags(ctNow()); err != nil {
+		return nil, err
+	}
+	return logEntry, nil
+}
+
+// Targets returns the names to watch: the domains of every publicly issued
+// certificate and every DNS domain, whose subdomains are watched as well.
+func Targets() ([]Target, error) {
+	dnsDomains, err := query.DnsDomain.Find()
+	if err != nil {
+		return nil, err
+	}
+	certs, err := managedCerts()
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+	seen := map[string]bool{}
+	var targets []Target
+	for _, dnsDomain := range dnsDomains {
+		zone := normalizeDomain(dnsDomain.Domain)
+		if zone == "" || seen[zone] {
+			continue
+		}
+		seen[zone] = true
+		zones = append(zones, zone)
+		targets = append(targets, Target{Domain: zone, IncludeSubdomains: true})
+	}
+	for _, certModel := range certs {
+		for _, domain := range certModel.Domains {
+			domain = normalizeDomain(domain)
+			if domain == "" || seen[domain] || slices.ContainsFunc(zones, func(zone string) bool {
+				return strings.HasSuffix(domain, "."+zone)
+			}) {
+				continue
+			}
+			seen[domain] = true
+			targets = append(targets, Target{Domain: domain})
+		}
+	}
+	return targets, nil
+}
+
+// recordDeployedSerials stores the serial numbers of the deployed managed
+// certificates and returns every serial number ever deployed along with the
+// organizations that issued them.
+func recordDeployedSerials() (map[string]uint64, map[string]bool, error) {
+	c := query.Cert
+	certs, err := c.Where(c.SSLCertificatePath.Neq("")).Find()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, certModel := range certs {
+		certificate, err := cert.GetCertificate(certModel.SSLCertificatePath)
+		if err != nil {
+			continue
+		}
+		organization := certificate.Issuer.CommonName
+		if len(certificate.Issuer.Organization) > 0 {
+			organization = certificate.Issuer.Organization[0]
+		}
+		s := query.CertSerial
+		_, err = s.Where(s.SerialNumber.Eq(certificate.SerialNumber.Text(16))).
+			Attrs(s.CertID.Value(certModel.ID), s.IssuerName.Value(organization)).
+			FirstOrCreate()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	recorded, err := query.CertSerial.Find()
+	if err != nil {
+		return nil, nil, err
+	}
+	serials := make(map[string]uint64, len(recorded))
+	issuers := map[string]bool{}
+	for _, serial := range recorded {
+		serials[serial.SerialNumber] = serial.CertID
+		issuers[strings.ToLower(serial.IssuerName)] = true
+	}
+	return serials, issuers, nil
+}
+
+// reconcileKnownEntries marks the unknown entries whose certificate has been
+// deployed since they were recorded.
+func reconcileKnownEntries(serials map[string]uint64) error {
+	l := query.CTLogEntry
+	unknown, err := l.Where(l.Known.Is(false)).Find()
+	if err != nil {
+		return err
+	}
+	for _, logEntry := range unknown {
+		certID, ok := serials[logEntry.SerialNumber]
+		if !ok {
+			continue
+		}
+		_, err := l.Where(l.ID.Eq(logEntry.ID)).
+			UpdateSimple(l.Known.Value(true), l.CertID.Value(certID), l.UnexpectedIssuer.Value(false))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateCertFlags stores on every managed certificate the issuers of the
+// valid, unacknowledged certificates for its names that come from an issuer
+// Nginx UI never deployed a certificate of.
+func updateCertFlags(now time.Time) error {
+	l := query.CTLogEntry
+	unexpected, err := l.Where(l.Known.Is(false), l.Acknowledged.Is(false), l.UnexpectedIssuer.Is(true),
+		l.NotAfter.Gt(now)).Find()
+	if err != nil {
+		return err
+	}
+	certs, err := managedCerts()
+	if err != nil {
+		return err
+	}
+
+	for _, certModel := range certs {
+		var issuers []string
+		for _, logEntry := range unexpected {
+			if coversAny(logEntry.Names, certModel.Domains) && !slices.Contains(issuers, logEntry.IssuerName) {
+				issuers = append(issuers, logEntry.IssuerName)
+			}
+		}
+		slices.Sort(issuers)
+		if slices.Equal(issuers, certModel.CTUnexpectedIssuers) {
+			continue
+		}
+		c := query.Cert
+		_, err := c.Where(c.ID.Eq(certModel.ID)).Select(c.CTUnexpectedIssuers).
+			Updates(&model.Cert{CTUnexpectedIssuers: issuers})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// managedCerts returns the certificates issued by a public CA.
+func managedCerts() ([]*model.Cert, error) {
+	c := query.Cert
+	return c.Where(c.AutoCert.NotIn(model.AutoCertSelfSigned, model.AutoCertPrivateCA)).Find()
+}
+
+func newLogEntry(domain string, entry *Entry) (*model.CTLogEntry, error) {
+	serial, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(entry.SerialNumber), "0x"), 16)
+	if !ok {
+		return nil, cosy.WrapErrorWithParams(ErrSourceDecode, "serial number "+entry.SerialNumber)
+	}
+	notBefore, err := parseTime(entry.NotBefore)
+	if err != nil {
+		return nil, err
+	}
+	notAfter, err := parseTime(entry.NotAfter)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CTLogEntry{
+		Domain:       domain,
+		SourceID:     entry.ID,
+		IssuerName:   entry.IssuerName,
+		CommonName:   entry.CommonName,
+		Names:        entry.Names(),
+		SerialNumber: serial.Text(16),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}, nil
+}
+
+// markChecked records that the domain has been searched, which ends its
+// baseline.
+func markChecked(domain string, now time.Time) error {
+	m := query.CTMonitorTarget
+	_, err := m.Where(m.Domain.Eq(domain)).
+		Assign(m.CheckedAt.Value(now)).
+		FirstOrCreate()
+	return err
+}
+
+func notifySearchFailed(domain string, err error) {
+	notification.Error("CT Log Search Failed",
+		"Searching the Certificate Transparency logs for %{domain} failed: %{error}", map[string]any{
+			"domain": domain,
+			"error":  err.Error(),
+		})
+}
+
+func notifyUnknown(logEntry *model.CTLogEntry) {
+	details := map[string]any{
+		"names":  strings.Join(logEntry.Names, ", "),
+		"issuer": logEntry.IssuerName,
+	}
+	if logEntry.UnexpectedIssuer {
+		notification.Warning("Certificate From Unexpected Issuer",
+			"A certificate for %{names} issued by %{issuer}, which never issued a certificate deployed by Nginx UI, was found in the Certificate Transparency logs", details)
+		return
+	}
+	notification.Warning("Unknown Certificate",
+		"A certificate for %{names} issued by %{issuer} that is not managed by Nginx UI was found in the Certificate Transparency logs", details)
+}
+
+// issuerOrganization returns the lower-cased organization of an issuer
+// distinguished name, or its common name when it has none.
+func issuerOrganization(dn string) string {
+	value := ""
+	if match := issuerOrganizationRe.FindStringSubmatch(dn); match != nil {
+		value = match[1]
+	} else if i := strings.Index(dn, "CN="); i >= 0 {
+		value, _, _ = strings.Cut(dn[i+3:], ",")
+	}
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+	value = strings.ReplaceAll(value, `\`, "")
+	return strings.ToLower(value)
+}
+
+// normalizeDomain turns a certificate name into a watched domain. IP
+// addresses are never logged and are dropped.
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	domain = strings.TrimPrefix(domain, "*.")
+	if domain == "" || net.ParseIP(domain) != nil || !strings.Contains(domain, ".") {
+		return ""
+	}
+	return domain
+}
+
+// coversAny reports whether any of the certificate names covers one of the
+// domains, taking wildcards on either side into account.
+func coversAny(names, domains []string) bool {
+	for _, name := range names {
+		for _, domain := range domains {
+			domain = strings.ToLower(domain)
+			if name == domain || matchesWildcard(name, domain) || matchesWildcard(domain, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesWildcard(pattern, name string) bool {
+	suffix, ok := strings.CutPrefix(pattern, "*.")
+	if !ok {
+		return false
+	}
+	label, rest, found := strings.Cut(name, ".")
+	return found && label != "" && rest == suffix
+}
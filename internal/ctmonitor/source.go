@@ -0,0 +1,137 @@
+package ctmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/transport"
+	"github.com/uozi-tech/cosy"
+)
+
+// DefaultSource is the CT log source used when none is configured.
+const DefaultSource = "https://crt.sh"
+
+const (
+	sourceTimeout = 60 * time.Second
+	// maxResponseSize caps a source response, popular domains have a lot of
+	// certificates.
+	maxResponseSize = 64 << 20
+)
+
+// Entry is a certificate as returned by the crt.sh JSON API. NameValue holds
+// the names of the certificate separated by newlines.
+type Entry struct {
+	ID           int64  `json:"id"`
+	IssuerName   string `json:"issuer_name"`
+	CommonName   string `json:"common_name"`
+	NameValue    string `json:"name_value"`
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+	NotAfter     string `json:"not_after"`
+}
+
+// Names returns the lower-cased names of the certificate.
+func (e *Entry) Names() []string {
+	var names []string
+	for _, name := range strings.Split(e.NameValue, "\n") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Source looks up the certificates logged for a domain.
+type Source interface {
+	Search(ctx context.Context, domain string, includeSubdomains bool) ([]*Entry, error)
+}
+
+// NewSource returns the source for a configured location, the http(s) URL of
+// a crt.sh-compatible API.
+func NewSource(location string) (Source, error) {
+	if location == "" {
+		location = DefaultSource
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidSource, location)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &crtshSource{baseURL: strings.TrimRight(location, "/")}, nil
+	default:
+		return nil, cosy.WrapErrorWithParams(ErrInvalidSource, location)
+	}
+}
+
+type crtshSource struct {
+	baseURL string
+}
+
+func (s *crtshSource) Search(ctx context.Context, domain string, includeSubdomains bool) ([]*Entry, error) {
+	queries := []string{domain}
+	if includeSubdomains {
+		queries = append(queries, "%."+domain)
+	}
+
+	t, err := transport.NewTransport()
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceRequest, err.Error())
+	}
+	client := &http.Client{Transport: t, Timeout: sourceTimeout}
+
+	seen := map[int64]bool{}
+	var entries []*Entry
+	for _, query := range queries {
+		result, err := s.query(ctx, client, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range result {
+			if !seen[entry.ID] {
+				seen[entry.ID] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (s *crtshSource) query(ctx context.Context, client *http.Client, query string) ([]*Entry, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("output", "json")
+	params.Set("exclude", "expired")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/?"+params.Encode(), nil)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceRequest, err.Error())
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceRequest, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, cosy.WrapErrorWithParams(ErrSourceStatus, strconv.Itoa(resp.StatusCode))
+	}
+
+	var entries []*Entry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&entries); err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrSourceDecode, err.Error())
+	}
+	return entries, nil
+}
+
+// parseTime parses the timestamps of crt.sh, which are in UTC without a zone.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05", value)
+}
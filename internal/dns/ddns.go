@@ -14,6 +14,7 @@ import (
 
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/samber/lo"
 	"github.com/uozi-tech/cosy"
 )
 
@@ -34,6 +35,10 @@ const (
 // a var (not const) so tests can override via SetDDNSFamilyFailureGraceForTest.
 var ddnsFamilyFailureGrace = 1 * time.Hour
 
+// ddnsPropagationTimeout bounds how long a run waits for updated records to
+// reach every authoritative nameserver.
+var ddnsPropagationTimeout = DefaultPropagationTimeout
+
 var (
 	ipv4Endpoints = []string{
 		"https://api.ipify.org",
@@ -72,6 +77,14 @@ type DDNSUpdateInput struct {
 	IPVersion                 string
 	CleanupConflictingRecords bool
 	RecordIDs                 []string
+	// Source is the IP source of every target without its own; nil means the
+	// public IP echo services.
+	Source *model.DDNSIPSource
+	// TargetSources overrides Source for the targets selected by an entry of
+	// RecordIDs, keyed by that entry.
+	TargetSources   map[string]*model.DDNSIPSource
+	WebhookEnabled  bool
+	WaitPropagation bool
 }
 
 // UpdateDDNSResult bundles the persisted config and any provider records that
@@ -79,6 +92,9 @@ type DDNSUpdateInput struct {
 type UpdateDDNSResult struct {
 	Config         *model.DDNSConfig
 	DeletedRecords []model.DDNSRecordTarget
+	// WebhookTokenIssued is set when enabling the webhook generated a token,
+	// the only time besides a rotation that it is handed out.
+	WebhookTokenIssued bool
 }
 
 // DDNSSchedule describes an enabled DDNS task.
@@ -135,12 +151,22 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 		return nil, err
 	}
 
+	if err := validateDDNSSource(input.Source, input.WebhookEnabled); err != nil {
+		return nil, err
+	}
+	for _, source := range input.TargetSources {
+		if err := validateDDNSSource(source, input.WebhookEnabled); err != nil {
+			return nil, err
+		}
+	}
+
 	domain, provider, err := s.prepareProvider(ctx, domainID)
 	if err != nil {
 		return nil, err
 	}
 
 	existing := domain.DDNSConfig
+	resolver := newDDNSSourceResolver(version, existing)
 
 	targets := []model.DDNSRecordTarget{}
 	var createdRecords []model.DDNSRecordTarget
@@ -181,9 +207,10 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 				}
 				if _, ok := seenTargetIDs[record.ID]; !ok {
 					targets = append(targets, model.DDNSRecordTarget{
-						ID:   record.ID,
-						Name: record.Name,
-						Type: recordType,
+						ID:     record.ID,
+						Name:   record.Name,
+						Type:   recordType,
+						Source: input.TargetSources[trimmed],
 					})
 					seenTargetIDs[record.ID] = struct{}{}
 				}
@@ -196,25 +223,36 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 				if err != nil {
 					return nil, err
 				}
+				for i := range createdTargets {
+					createdTargets[i].Source = input.TargetSources[trimmed]
+				}
 				targets = append(targets, createdTargets...)
 				seen[trimmed] = struct{}{}
 				continue
 			}
 
 			// If record does not exist, create new A/AAAA records using detected IPs.
-			if ipSnapshot == nil {
-				snapshot, ipErr := resolvePublicIPs(ctx, version)
-				if ipErr != nil {
-					return nil, ipErr
-				}
+			targetSource := input.TargetSources[trimmed]
+			if targetSource == nil {
+				targetSource = input.Source
+			}
+			snapshot, _, ipErr := resolver.resolve(ctx, targetSource)
+			if ipErr != nil {
+				rollbackCreatedDDNSRecords(ctx, provider, domain.Domain, createdRecords)
+				return nil, ipErr
+			}
+			if targetSource == input.Source {
 				ipSnapshot = &snapshot
 			}
 
-			createdTargets, err := createDDNSRecordsForMissingName(ctx, provider, domain.Domain, trimmed, version, *ipSnapshot)
+			createdTargets, err := createDDNSRecordsForMissingName(ctx, provider, domain.Domain, trimmed, version, snapshot)
 			if err != nil {
 				rollbackCreatedDDNSRecords(ctx, provider, domain.Domain, createdRecords)
 				return nil, err
 			}
+			for i := range createdTargets {
+				createdTargets[i].Source = input.TargetSources[trimmed]
+			}
 
 			createdRecords = append(createdRecords, createdTargets...)
 			targets = append(targets, createdTargets...)
@@ -228,7 +266,7 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 		// §6.2-6.3 — Completion phase: dual-stack mode with cleanup on only.
 		if isDualStackMode(version) && input.CleanupConflictingRecords {
 			if ipSnapshot == nil {
-				snap, _ := resolvePublicIPs(ctx, version)
+				snap, _, _ := resolver.resolve(ctx, input.Source)
 				ipSnapshot = &snap
 			}
 			// If neither family is detected, refuse to run §6.3 — running it would
@@ -309,6 +347,8 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 		IPVersion:                 version,
 		CleanupConflictingRecords: input.CleanupConflictingRecords,
 		Targets:                   targets,
+		Source:                    input.Source,
+		WaitPropagation:           input.WaitPropagation,
 	}
 
 	if existing != nil {
@@ -318,13 +358,30 @@ func (s *Service) UpdateDDNSConfigWithDetails(ctx context.Context, domainID uint
 		cfg.LastError = existing.LastError
 		cfg.IPv4FailedSince = existing.IPv4FailedSince
 		cfg.IPv6FailedSince = existing.IPv6FailedSince
+		cfg.WebhookToken = existing.WebhookToken
+		cfg.WebhookIPv4 = existing.WebhookIPv4
+		cfg.WebhookIPv6 = existing.WebhookIPv6
+		cfg.WebhookAt = existing.WebhookAt
+	}
+
+	tokenIssued := false
+	if !input.WebhookEnabled {
+		cfg.WebhookToken = ""
+	} else if cfg.WebhookToken == "" {
+		token, err := newDDNSWebhookToken()
+		if err != nil {
+			rollbackCreatedDDNSRecords(ctx, provider, domain.Domain, createdRecords)
+			return nil, err
+		}
+		cfg.WebhookToken = token
+		tokenIssued = true
 	}
 
 	if err := saveDDNSConfig(ctx, domainID, cfg); err != nil {
 		return nil, err
 	}
 
-	return &UpdateDDNSResult{Config: cfg, DeletedRecords: deletedRecords}, nil
+	return &UpdateDDNSResult{Config: cfg, DeletedRecords: deletedRecords, WebhookTokenIssued: tokenIssued}, nil
 }
 
 // DeleteDDNSConfig removes DDNS configuration for the given domain.
@@ -389,20 +446,27 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 	version := NormalizeDDNSIPVersion(cfg.IPVersion)
 	policy := getDDNSIPVersionPolicy(version)
 
-	ipSnapshot, ipErr := resolvePublicIPs(ctx, version)
-
+	resolver := newDDNSSourceResolver(version, cfg)
 	now := time.Now()
-	if ipSnapshot.IPv4 != "" {
-		cfg.IPv4FailedSince = nil
-	} else if cfg.IPv4FailedSince == nil {
-		t := now
-		cfg.IPv4FailedSince = &t
-	}
-	if ipSnapshot.IPv6 != "" {
-		cfg.IPv6FailedSince = nil
-	} else if cfg.IPv6FailedSince == nil {
-		t := now
-		cfg.IPv6FailedSince = &t
+
+	// The domain source is only consulted when some target relies on it.
+	var ipSnapshot ipSnapshot
+	var ipErr error
+	if lo.ContainsBy(cfg.Targets, func(target model.DDNSRecordTarget) bool { return target.Source == nil }) {
+		ipSnapshot, _, ipErr = resolver.resolve(ctx, cfg.Source)
+
+		if ipSnapshot.IPv4 != "" {
+			cfg.IPv4FailedSince = nil
+		} else if cfg.IPv4FailedSince == nil {
+			t := now
+			cfg.IPv4FailedSince = &t
+		}
+		if ipSnapshot.IPv6 != "" {
+			cfg.IPv6FailedSince = nil
+		} else if cfg.IPv6FailedSince == nil {
+			t := now
+			cfg.IPv6FailedSince = &t
+		}
 	}
 
 	records, err := fetchProviderRecords(ctx, provider, domain.Domain)
@@ -428,7 +492,9 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 		kept := make([]model.DDNSRecordTarget, 0, len(cfg.Targets))
 		for _, target := range cfg.Targets {
 			typ := strings.ToUpper(target.Type)
-			if (typ == "A" && evictA) || (typ == "AAAA" && evictAAAA) {
+			// Failure tracking follows the domain source, so targets with their
+			// own source are never evicted.
+			if target.Source == nil && ((typ == "A" && evictA) || (typ == "AAAA" && evictAAAA)) {
 				ctxTimeout, cancel := context.WithTimeout(ctx, providerTimeout)
 				err := provider.DeleteRecord(ctxTimeout, domain.Domain, target.ID)
 				cancel()
@@ -444,6 +510,7 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 		cfg.Targets = kept
 	}
 
+	var updated []PropagationQuery
 	for _, target := range cfg.Targets {
 		record, ok := recordMap[target.ID]
 		if !ok {
@@ -457,16 +524,31 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 			continue
 		}
 
+		snapshot := ipSnapshot
+		if target.Source != nil {
+			targetSnapshot, first, err := resolver.resolve(ctx, target.Source)
+			if err != nil {
+				if first {
+					updateErrs = append(updateErrs, err.Error())
+				}
+				continue
+			}
+			if first {
+				updateErrs = append(updateErrs, targetSnapshot.Warnings...)
+			}
+			snapshot = targetSnapshot
+		}
+
 		var nextIP string
 		switch recordType {
 		case "A":
-			nextIP = ipSnapshot.IPv4
-			if nextIP != "" {
+			nextIP = snapshot.IPv4
+			if nextIP != "" && target.Source == nil {
 				cfg.LastIPv4 = nextIP
 			}
 		case "AAAA":
-			nextIP = ipSnapshot.IPv6
-			if nextIP != "" {
+			nextIP = snapshot.IPv6
+			if nextIP != "" && target.Source == nil {
 				cfg.LastIPv6 = nextIP
 			}
 		default:
@@ -499,7 +581,18 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 
 		if err != nil {
 			updateErrs = append(updateErrs, fmt.Sprintf("%s: %v", record.ID, err))
+			continue
 		}
+		updated = append(updated, PropagationQuery{
+			Name:     recordFQDN(record.Name, domain.Domain),
+			Type:     recordType,
+			Expected: []string{nextIP},
+			Zone:     domain.Domain,
+		})
+	}
+
+	if cfg.WaitPropagation && len(updated) > 0 {
+		updateErrs = append(updateErrs, waitDDNSPropagation(ctx, updated)...)
 	}
 
 	cfg.LastRunAt = &now
@@ -512,6 +605,22 @@ func (s *Service) runDDNSUpdate(ctx context.Context, domainID uint64) error {
 	return saveDDNSConfig(ctx, domainID, cfg)
 }
 
+// waitDDNSPropagation waits for the updated records under one shared deadline
+// and describes those that did not propagate in time.
+func waitDDNSPropagation(ctx context.Context, updated []PropagationQuery) []string {
+	waitCtx, cancel := context.WithTimeout(ctx, ddnsPropagationTimeout)
+	defer cancel()
+
+	checker := NewPropagationChecker()
+	var errs []string
+	for _, q := range updated {
+		if _, err := checker.Wait(waitCtx, q, 0); err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", q.Name, q.Type, err))
+		}
+	}
+	return errs
+}
+
 type ipSnapshot struct {
 	IPv4     string
 	IPv6     string
@@ -552,30 +661,45 @@ func resolvePublicIPs(ctx context.Context, ipVersion string) (ipSnapshot, error)
 	}
 	wg.Wait()
 
+	return combineFamilyResults(version, map[ipFamily]familyResult{
+		ipFamilyV4: {ip: ipv4Res, err: ipv4Err},
+		ipFamilyV6: {ip: ipv6Res, err: ipv6Err},
+	}, "public ip resolve errors")
+}
+
+type familyResult struct {
+	ip  string
+	err error
+}
+
+// combineFamilyResults builds the snapshot of the families in version. It
+// fails only when no family resolved; otherwise failures become warnings.
+func combineFamilyResults(version string, results map[ipFamily]familyResult, label string) (ipSnapshot, error) {
 	var snapshot ipSnapshot
 	var errs []string
 	var successCount int
-	for _, family := range policy.families {
+	for _, family := range getDDNSIPVersionPolicy(version).families {
+		result := results[family]
 		switch family {
 		case ipFamilyV4:
-			if ipv4Err == nil {
-				snapshot.IPv4 = ipv4Res
+			if result.err == nil {
+				snapshot.IPv4 = result.ip
 				successCount++
 			} else {
-				errs = append(errs, fmt.Sprintf("ipv4: %v", ipv4Err))
+				errs = append(errs, fmt.Sprintf("ipv4: %v", result.err))
 			}
 		case ipFamilyV6:
-			if ipv6Err == nil {
-				snapshot.IPv6 = ipv6Res
+			if result.err == nil {
+				snapshot.IPv6 = result.ip
 				successCount++
 			} else {
-				errs = append(errs, fmt.Sprintf("ipv6: %v", ipv6Err))
+				errs = append(errs, fmt.Sprintf("ipv6: %v", result.err))
 			}
 		}
 	}
 
 	if successCount == 0 && len(errs) > 0 {
-		return snapshot, fmt.Errorf("public ip resolve errors: %s", strings.Join(errs, "; "))
+		return snapshot, fmt.Errorf("%s: %s", label, strings.Join(errs, "; "))
 	}
 	if len(errs) > 0 {
 		snapshot.Warnings = errs
@@ -0,0 +1,476 @@
+package dns
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/samber/lo"
+	"github.com/uozi-tech/cosy"
+)
+
+// DDNS IP source types persisted in DDNSIPSource.Type and accepted by the API.
+const (
+	DDNSSourcePublic    = "public"
+	DDNSSourceInterface = "interface"
+	DDNSSourceCommand   = "command"
+	DDNSSourceURL       = "url"
+	DDNSSourceWebhook   = "webhook"
+
+	// IPv6 scopes accepted by interface sources. An empty scope accepts both
+	// and prefers global addresses.
+	DDNSIPv6ScopeGUA = "gua"
+	DDNSIPv6ScopeULA = "ula"
+)
+
+// ddnsSourceOutputLimit bounds how much of a command output or URL body is
+// searched for an address.
+const ddnsSourceOutputLimit = 64 << 10
+
+var ulaPrefix = netip.MustParsePrefix("fc00::/7")
+
+// interfaceAddrs lists the addresses of a local interface. Tests replace it
+// to avoid depending on the interfaces of the host.
+var interfaceAddrs = func(name string) ([]net.Addr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return iface.Addrs()
+}
+
+// DDNSWebhookReport carries the addresses reported by a webhook call.
+type DDNSWebhookReport struct {
+	IPv4 string
+	IPv6 string
+}
+
+// TriggerDDNSWebhook authenticates a webhook call, stores the addresses it
+// reported for targets using the webhook source and runs a DDNS update.
+func (s *Service) TriggerDDNSWebhook(ctx context.Context, domainID uint64, token string, report DDNSWebhookReport) error {
+	domain, err := loadDomain(ctx, domainID)
+	if err != nil {
+		if errors.Is(err, ErrDomainNotFound) {
+			return ErrDDNSWebhookUnauthorized
+		}
+		return err
+	}
+
+	cfg := domain.DDNSConfig
+	if cfg == nil || !cfg.Enabled || cfg.WebhookToken == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(cfg.WebhookToken)) != 1 {
+		return ErrDDNSWebhookUnauthorized
+	}
+
+	ipv4, ipv6 := strings.TrimSpace(report.IPv4), strings.TrimSpace(report.IPv6)
+	if ipv4 != "" && parseExpectedIP(ipv4, ipFamilyV4) == nil {
+		return cosy.WrapErrorWithParams(ErrInvalidDDNSSource, "invalid IPv4 address "+ipv4)
+	}
+	if ipv6 != "" && parseExpectedIP(ipv6, ipFamilyV6) == nil {
+		return cosy.WrapErrorWithParams(ErrInvalidDDNSSource, "invalid IPv6 address "+ipv6)
+	}
+
+	if ipv4 != "" || ipv6 != "" {
+		now := time.Now()
+		if ipv4 != "" {
+			cfg.WebhookIPv4 = parseExpectedIP(ipv4, ipFamilyV4).String()
+		}
+		if ipv6 != "" {
+			cfg.WebhookIPv6 = parseExpectedIP(ipv6, ipFamilyV6).String()
+		}
+		cfg.WebhookAt = &now
+		if err := saveDDNSConfig(ctx, domainID, cfg); err != nil {
+			return err
+		}
+	}
+
+	return s.runDDNSUpdate(ctx, domainID)
+}
+
+// RotateDDNSWebhookToken replaces the webhook token of a domain, invalidating
+// the URL configured on the caller.
+func (s *Service) RotateDDNSWebhookToken(ctx context.Context, domainID uint64) (*model.DDNSConfig, error) {
+	domain, err := loadDomain(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := domain.DDNSConfig
+	if cfg == nil || cfg.WebhookToken == "" {
+		return nil, ErrDDNSWebhookDisabled
+	}
+
+	token, err := newDDNSWebhookToken()
+	if err != nil {
+		return nil, err
+	}
+	cfg.WebhookToken = token
+
+	if err := saveDDNSConfig(ctx, domainID, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func newDDNSWebhookToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// validateDDNSSource checks that a source has the fields its type needs.
+func validateDDNSSource(source *model.DDNSIPSource, webhookEnabled bool) error {
+	if source == nil {
+		return nil
+	}
+
+	var err error
+	switch ddnsSourceType(source) {
+	case DDNSSourcePublic:
+	case DDNSSourceInterface:
+		err = validateInterfaceSource(source)
+	case DDNSSourceCommand:
+		if strings.TrimSpace(source.Command) == "" {
+			err = errors.New("command is required")
+		} else {
+			err = checkSourceAllowed(source)
+		}
+	case DDNSSourceURL:
+		parsed, parseErr := url.Parse(strings.TrimSpace(source.URL))
+		if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			err = errors.New("url must be an absolute http or https URL")
+		} else {
+			err = checkSourceAllowed(source)
+		}
+	case DDNSSourceWebhook:
+		if !webhookEnabled {
+			err = errors.New("the webhook source requires the webhook to be enabled")
+		}
+	default:
+		err = fmt.Errorf("unknown type %q", source.Type)
+	}
+	if err == nil && source.Regex != "" {
+		if _, regexErr := regexp.Compile(source.Regex); regexErr != nil {
+			err = fmt.Errorf("invalid regex: %v", regexErr)
+		}
+	}
+
+	if err != nil {
+		return cosy.WrapErrorWithParams(ErrInvalidDDNSSource, err.Error())
+	}
+	return nil
+}
+
+// checkSourceAllowed rejects command and URL sources that are not listed in
+// the ddns section of the settings file. They run as the Nginx UI process, so
+// dns:write alone must not be enough to pick them.
+func checkSourceAllowed(source *model.DDNSIPSource) error {
+	switch ddnsSourceType(source) {
+	case DDNSSourceCommand:
+		if !slices.Contains(settings.DDNSSettings.AllowedCommands, strings.TrimSpace(source.Command)) {
+			return errors.New("command is not listed in ddns.AllowedCommands of the settings file")
+		}
+	case DDNSSourceURL:
+		if !slices.Contains(settings.DDNSSettings.AllowedURLs, strings.TrimSpace(source.URL)) {
+			return errors.New("url is not listed in ddns.AllowedURLs of the settings file")
+		}
+	}
+	return nil
+}
+
+func validateInterfaceSource(source *model.DDNSIPSource) error {
+	if strings.TrimSpace(source.Interface) == "" {
+		return errors.New("interface is required")
+	}
+	switch strings.ToLower(strings.TrimSpace(source.IPv6Scope)) {
+	case "", DDNSIPv6ScopeGUA, DDNSIPv6ScopeULA:
+	default:
+		return fmt.Errorf("unknown IPv6 scope %q", source.IPv6Scope)
+	}
+	if prefix := strings.TrimSpace(source.Prefix); prefix != "" {
+		if _, err := netip.ParsePrefix(prefix); err != nil {
+			return fmt.Errorf("invalid prefix: %v", err)
+		}
+	}
+	return nil
+}
+
+func ddnsSourceType(source *model.DDNSIPSource) string {
+	if source == nil {
+		return DDNSSourcePublic
+	}
+	sourceType := strings.ToLower(strings.TrimSpace(source.Type))
+	if sourceType == "" {
+		return DDNSSourcePublic
+	}
+	return sourceType
+}
+
+// resolveSourceIPs resolves the addresses of the families in version from
+// source. cfg provides the addresses last reported through the webhook.
+func resolveSourceIPs(ctx context.Context, source *model.DDNSIPSource, version string, cfg *model.DDNSConfig) (ipSnapshot, error) {
+	sourceType := ddnsSourceType(source)
+	if sourceType == DDNSSourcePublic {
+		return resolvePublicIPs(ctx, version)
+	}
+
+	version, err := sanitizeDDNSIPVersion(version)
+	if err != nil {
+		return ipSnapshot{}, err
+	}
+
+	ipCtx, cancel := context.WithTimeout(ctx, ipDetectTimeout)
+	defer cancel()
+
+	var lookup func(family ipFamily) (string, error)
+	switch sourceType {
+	case DDNSSourceInterface:
+		lookup = func(family ipFamily) (string, error) {
+			addrs, err := interfaceAddrs(strings.TrimSpace(source.Interface))
+			if err != nil {
+				return "", err
+			}
+			return selectInterfaceIP(addrs, source, family)
+		}
+	case DDNSSourceCommand, DDNSSourceURL:
+		var (
+			output string
+			err    error
+		)
+		// The allowlist may have shrunk since the source was saved.
+		if err = checkSourceAllowed(source); err == nil {
+			if sourceType == DDNSSourceCommand {
+				output, err = runSourceCommand(ipCtx, source.Command)
+			} else {
+				output, err = fetchSourceURL(ipCtx, source.URL)
+			}
+		}
+		lookup = func(family ipFamily) (string, error) {
+			if err != nil {
+				return "", err
+			}
+			return extractSourceIP(output, source.Regex, family)
+		}
+	case DDNSSourceWebhook:
+		lookup = func(family ipFamily) (string, error) {
+			var ip string
+			if cfg != nil {
+				ip = lo.Ternary(family == ipFamilyV4, cfg.WebhookIPv4, cfg.WebhookIPv6)
+			}
+			if ip == "" {
+				return "", errors.New("no address reported through the webhook yet")
+			}
+			return ip, nil
+		}
+	default:
+		return ipSnapshot{}, cosy.WrapErrorWithParams(ErrInvalidDDNSSource, fmt.Sprintf("unknown type %q", source.Type))
+	}
+
+	results := map[ipFamily]familyResult{}
+	for _, family := range getDDNSIPVersionPolicy(version).families {
+		ip, err := lookup(family)
+		results[family] = familyResult{ip: ip, err: err}
+	}
+	return combineFamilyResults(version, results, sourceType+" ip source errors")
+}
+
+// selectInterfaceIP picks the address of family among the addresses of an
+// interface. Loopback and link-local addresses are never used, and with no
+// IPv6 scope configured a global address is preferred over a ULA.
+func selectInterfaceIP(addrs []net.Addr, source *model.DDNSIPSource, family ipFamily) (string, error) {
+	var prefix netip.Prefix
+	if value := strings.TrimSpace(source.Prefix); value != "" {
+		parsed, err := netip.ParsePrefix(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid prefix: %v", err)
+		}
+		prefix = parsed.Masked()
+	}
+	scope := strings.ToLower(strings.TrimSpace(source.IPv6Scope))
+
+	var fallback string
+	for _, addr := range addrs {
+		var ip net.IP
+		switch value := addr.(type) {
+		case *net.IPNet:
+			ip = value.IP
+		case *net.IPAddr:
+			ip = value.IP
+		default:
+			continue
+		}
+		parsed, ok := netip.AddrFromSlice(ip)
+		if !ok {
+			continue
+		}
+		parsed = parsed.Unmap()
+
+		if (family == ipFamilyV4 && !parsed.Is4()) || (family == ipFamilyV6 && !parsed.Is6()) {
+			continue
+		}
+		if parsed.IsLoopback() || parsed.IsLinkLocalUnicast() || parsed.IsUnspecified() || parsed.IsMulticast() {
+			continue
+		}
+		// The prefix only narrows down addresses of its own family.
+		if prefix.IsValid() && prefix.Addr().Is4() == parsed.Is4() && !prefix.Contains(parsed) {
+			continue
+		}
+
+		if parsed.Is6() {
+			isULA := ulaPrefix.Contains(parsed)
+			switch scope {
+			case DDNSIPv6ScopeGUA:
+				if isULA {
+					continue
+				}
+			case DDNSIPv6ScopeULA:
+				if !isULA {
+					continue
+				}
+			default:
+				if isULA {
+					if fallback == "" {
+						fallback = parsed.String()
+					}
+					continue
+				}
+			}
+		}
+
+		return parsed.String(), nil
+	}
+
+	if fallback != "" {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("no matching address on interface %s", source.Interface)
+}
+
+// extractSourceIP finds an address of family in the output of a command or
+// URL source, restricted to the matches of pattern when it is set.
+func extractSourceIP(output, pattern string, family ipFamily) (string, error) {
+	if pattern == "" {
+		return parseIPString(output, family)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex: %v", err)
+	}
+	for _, match := range re.FindAllStringSubmatch(output, -1) {
+		candidate := match[0]
+		if len(match) > 1 {
+			candidate = match[1]
+		}
+		if ip := parseExpectedIP(strings.TrimSpace(candidate), family); ip != nil {
+			return ip.String(), nil
+		}
+	}
+	return "", errors.New("regex matched no valid ip")
+}
+
+func runSourceCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+
+	output, err := cmd.Output()
+	if len(output) > ddnsSourceOutputLimit {
+		output = output[:ddnsSourceOutputLimit]
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("command timed out after %s", ipDetectTimeout)
+		}
+		return "", fmt.Errorf("command failed: %v", err)
+	}
+	return string(output), nil
+}
+
+// sourceURLClient does not follow redirects, which could lead an allowed URL
+// anywhere.
+var sourceURLClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func fetchSourceURL(ctx context.Context, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(endpoint), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sourceURLClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ddnsSourceOutputLimit))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// ddnsSourceResolver resolves each distinct source once per DDNS run.
+type ddnsSourceResolver struct {
+	version string
+	cfg     *model.DDNSConfig
+	cache   map[model.DDNSIPSource]ddnsSourceResult
+}
+
+type ddnsSourceResult struct {
+	snapshot ipSnapshot
+	err      error
+}
+
+func newDDNSSourceResolver(version string, cfg *model.DDNSConfig) *ddnsSourceResolver {
+	return &ddnsSourceResolver{
+		version: version,
+		cfg:     cfg,
+		cache:   map[model.DDNSIPSource]ddnsSourceResult{},
+	}
+}
+
+// resolve returns the snapshot of source and whether this call resolved it,
+// so callers report warnings only once.
+func (r *ddnsSourceResolver) resolve(ctx context.Context, source *model.DDNSIPSource) (ipSnapshot, bool, error) {
+	key := model.DDNSIPSource{}
+	if source != nil {
+		key = *source
+	}
+	if result, ok := r.cache[key]; ok {
+		return result.snapshot, false, result.err
+	}
+
+	snapshot, err := resolveSourceIPs(ctx, source, r.version, r.cfg)
+	r.cache[key] = ddnsSourceResult{snapshot: snapshot, err: err}
+	return snapshot, true, err
+}
@@ -2,10 +2,13 @@ package dns
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/settings"
 	"github.com/stretchr/testify/require"
 )
 
@@ -165,3 +168,134 @@ func TestIsDualStackMode(t *testing.T) {
 	require.False(t, isDualStackMode(""))
 	require.False(t, isDualStackMode("invalid"))
 }
+
+func TestSelectInterfaceIP(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+		&net.IPNet{IP: net.ParseIP("192.168.1.10"), Mask: net.CIDRMask(24, 32)},
+		&net.IPNet{IP: net.ParseIP("203.0.113.5"), Mask: net.CIDRMask(24, 32)},
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("fd00::10"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("2001:db8::10"), Mask: net.CIDRMask(64, 128)},
+	}
+
+	ip, err := selectInterfaceIP(addrs, &model.DDNSIPSource{}, ipFamilyV4)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.10", ip)
+
+	ip, err = selectInterfaceIP(addrs, &model.DDNSIPSource{Prefix: "203.0.113.0/24"}, ipFamilyV4)
+	require.NoError(t, err)
+	require.Equal(t, "203.0.113.5", ip)
+
+	ip, err = selectInterfaceIP(addrs, &model.DDNSIPSource{}, ipFamilyV6)
+	require.NoError(t, err)
+	require.Equal(t, "2001:db8::10", ip, "global address is preferred")
+
+	ip, err = selectInterfaceIP(addrs, &model.DDNSIPSource{IPv6Scope: DDNSIPv6ScopeULA}, ipFamilyV6)
+	require.NoError(t, err)
+	require.Equal(t, "fd00::10", ip)
+
+	ip, err = selectInterfaceIP(addrs[:5], &model.DDNSIPSource{}, ipFamilyV6)
+	require.NoError(t, err)
+	require.Equal(t, "fd00::10", ip, "ULA is used when no global address exists")
+
+	_, err = selectInterfaceIP(addrs[:5], &model.DDNSIPSource{IPv6Scope: DDNSIPv6ScopeGUA}, ipFamilyV6)
+	require.Error(t, err)
+
+	ip, err = selectInterfaceIP(addrs, &model.DDNSIPSource{Prefix: "2001:db8::/32"}, ipFamilyV4)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.10", ip, "an IPv6 prefix leaves IPv4 untouched")
+}
+
+func TestExtractSourceIP(t *testing.T) {
+	output := "wan 10.0.0.2 up\npublic=198.51.100.7\nv6=2001:db8::7"
+
+	ip, err := extractSourceIP(output, `public=(\S+)`, ipFamilyV4)
+	require.NoError(t, err)
+	require.Equal(t, "198.51.100.7", ip)
+
+	ip, err = extractSourceIP(output, `v6=\S+`, ipFamilyV6)
+	require.Error(t, err, "whole match is not an address")
+	require.Empty(t, ip)
+
+	ip, err = extractSourceIP(output, "", ipFamilyV6)
+	require.NoError(t, err)
+	require.Equal(t, "2001:db8::7", ip)
+}
+
+func TestValidateDDNSSource(t *testing.T) {
+	require.NoError(t, validateDDNSSource(nil, false))
+	require.NoError(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceInterface, Interface: "eth0", IPv6Scope: "gua", Prefix: "2001:db8::/32"}, false))
+	require.NoError(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceWebhook}, true))
+
+	for _, source := range []*model.DDNSIPSource{
+		{Type: "carrier-pigeon"},
+		{Type: DDNSSourceInterface},
+		{Type: DDNSSourceInterface, Interface: "eth0", IPv6Scope: "site"},
+		{Type: DDNSSourceInterface, Interface: "eth0", Prefix: "2001:db8::"},
+		{Type: DDNSSourceCommand},
+		{Type: DDNSSourceURL, URL: "ftp://example.com/ip"},
+		{Type: DDNSSourceURL, URL: "https://example.com/ip", Regex: "("},
+		{Type: DDNSSourceWebhook},
+		{Type: DDNSSourceCommand, Command: "curl -s https://example.com/ip"},
+		{Type: DDNSSourceURL, URL: "http://169.254.169.254/latest/meta-data/"},
+	} {
+		require.ErrorContains(t, validateDDNSSource(source, false), "Invalid DDNS IP source", "%+v", source)
+	}
+}
+
+func TestValidateDDNSSourceUsesAllowlist(t *testing.T) {
+	allowDDNSSources(t, []string{"/usr/local/bin/wan-ip"}, []string{"https://example.com/ip"})
+
+	require.NoError(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceCommand, Command: "/usr/local/bin/wan-ip"}, false))
+	require.NoError(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceURL, URL: " https://example.com/ip"}, false))
+	require.ErrorContains(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceCommand, Command: "/usr/local/bin/wan-ip; id"}, false), "AllowedCommands")
+	require.ErrorContains(t, validateDDNSSource(&model.DDNSIPSource{Type: DDNSSourceURL, URL: "https://example.com/ip?x"}, false), "AllowedURLs")
+}
+
+func allowDDNSSources(t *testing.T, commands, urls []string) {
+	t.Helper()
+	previous := *settings.DDNSSettings
+	settings.DDNSSettings.AllowedCommands = commands
+	settings.DDNSSettings.AllowedURLs = urls
+	t.Cleanup(func() { *settings.DDNSSettings = previous })
+}
+
+func TestResolveSourceIPsFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"wan":"198.51.100.30","lan":"192.168.1.1"}`))
+	}))
+	defer server.Close()
+
+	source := &model.DDNSIPSource{Type: DDNSSourceURL, URL: server.URL, Regex: `"wan":"([^"]+)"`}
+
+	_, err := resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv4, nil)
+	require.ErrorContains(t, err, "AllowedURLs", "sources are checked again when they run")
+
+	allowDDNSSources(t, nil, []string{server.URL})
+
+	snapshot, err := resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv4, nil)
+	require.NoError(t, err)
+	require.Equal(t, "198.51.100.30", snapshot.IPv4)
+
+	// Dual stack tolerates the missing family and reports it as a warning.
+	snapshot, err = resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv4IPv6, nil)
+	require.NoError(t, err)
+	require.Equal(t, "198.51.100.30", snapshot.IPv4)
+	require.Empty(t, snapshot.IPv6)
+	require.NotEmpty(t, snapshot.Warnings)
+
+	_, err = resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv6, nil)
+	require.Error(t, err)
+}
+
+func TestResolveSourceIPsFromWebhook(t *testing.T) {
+	source := &model.DDNSIPSource{Type: DDNSSourceWebhook}
+
+	_, err := resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv4, &model.DDNSConfig{})
+	require.Error(t, err)
+
+	snapshot, err := resolveSourceIPs(context.Background(), source, DDNSIPVersionIPv4, &model.DDNSConfig{WebhookIPv4: "198.51.100.40"})
+	require.NoError(t, err)
+	require.Equal(t, "198.51.100.40", snapshot.IPv4)
+}
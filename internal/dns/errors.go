@@ -19,4 +19,18 @@ var (
 	ErrDDNSIPVersionRecordMismatch = cosy.NewError(40014, "DDNS record type does not match the selected IP version")
 	ErrDDNSIPUnavailable           = cosy.NewError(50005, "DDNS cannot detect a public IP to create records")
 	ErrDDNSRecordDeleteFailed      = cosy.NewError(50006, "Failed to delete DNS record")
+	ErrInvalidZoneFile             = cosy.NewError(40015, "Invalid zone file: {0}")
+	ErrZoneRecordOutOfZone         = cosy.NewError(40016, "Zone file record {0} is outside of {1}")
+	ErrSameZoneCredential          = cosy.NewError(40017, "Source and target credentials must differ")
+	ErrInvalidDDNSSource           = cosy.NewError(40018, "Invalid DDNS IP source: {0}")
+	ErrDDNSWebhookUnauthorized     = cosy.NewError(40019, "Invalid DDNS webhook token")
+	ErrDDNSWebhookDisabled         = cosy.NewError(40020, "DDNS webhook is not enabled")
+	ErrSiteRecordTargetRequired    = cosy.NewError(40021, "An IPv4, IPv6 or CNAME target is required to provision DNS records")
+	ErrInvalidSiteRecordTarget     = cosy.NewError(40022, "Invalid DNS record target: {0}")
+	ErrRecordNotFound              = cosy.NewError(40403, "DNS record {0} not found")
+	ErrInvalidPropagationQuery     = cosy.NewError(40023, "Invalid propagation query: {0}")
+	ErrPropagationZoneNotFound     = cosy.NewError(40404, "No nameservers found for {0}")
+	ErrPropagationResolver         = cosy.NewError(50007, "Resolver {0} answered {1}")
+	ErrNameserverUnresolved        = cosy.NewError(50008, "Cannot resolve nameserver {0}")
+	ErrPropagationTimeout          = cosy.NewError(50009, "{0} did not propagate to all nameservers in time")
 )
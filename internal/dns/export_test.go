@@ -22,3 +22,14 @@ func SetDDNSFamilyFailureGraceForTest(d time.Duration) func() {
 	ddnsFamilyFailureGrace = d
 	return func() { ddnsFamilyFailureGrace = original }
 }
+
+// OverridePropagationForTest points propagation checks at in-process
+// nameservers listening on port and shortens the DDNS wait. Returns a restore
+// func.
+func OverridePropagationForTest(resolvers []string, port string, timeout time.Duration) func() {
+	originalResolvers, originalPort, originalTimeout := propagationResolvers, nameserverPort, ddnsPropagationTimeout
+	propagationResolvers, nameserverPort, ddnsPropagationTimeout = resolvers, port, timeout
+	return func() {
+		propagationResolvers, nameserverPort, ddnsPropagationTimeout = originalResolvers, originalPort, originalTimeout
+	}
+}
@@ -0,0 +1,570 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/settings"
+	mdns "github.com/miekg/dns"
+	"github.com/uozi-tech/cosy"
+)
+
+const (
+	propagationQueryTimeout    = 5 * time.Second
+	defaultPropagationInterval = 5 * time.Second
+	// DefaultPropagationTimeout bounds waiting for a record to propagate when
+	// the caller does not pick a timeout.
+	DefaultPropagationTimeout = 2 * time.Minute
+	maxPropagationTimeout     = 10 * time.Minute
+)
+
+var (
+	// propagationResolvers overrides the recursive resolvers when set, which
+	// tests use to point the checker at an in-process server.
+	propagationResolvers []string
+	// nameserverPort is the port authoritative nameservers are queried on.
+	nameserverPort = "53"
+
+	fallbackResolvers = []string{"1.1.1.1:53", "8.8.8.8:53"}
+)
+
+// PropagationQuery names the record to check. Expected lists the values every
+// nameserver must answer with; without it any answer counts as propagated.
+// Zone skips looking up the zone when it is already known.
+type PropagationQuery struct {
+	Name     string
+	Type     string
+	Expected []string
+	Zone     string
+}
+
+// NameserverAnswer is what one authoritative nameserver of the zone answers.
+type NameserverAnswer struct {
+	Nameserver    string   `json:"nameserver"`
+	Address       string   `json:"address,omitempty"`
+	Values        []string `json:"values"`
+	TTL           uint32   `json:"ttl"`
+	Serial        uint32   `json:"serial"`
+	Rcode         string   `json:"rcode,omitempty"`
+	Authoritative bool     `json:"authoritative"`
+	Propagated    bool     `json:"propagated"`
+	Error         string   `json:"error,omitempty"`
+
+	rrs  []mdns.RR
+	sigs []*mdns.RRSIG
+}
+
+// DNSSECStatus is the outcome of validating the chain from the DS records at
+// the parent through the DNSKEY set of the zone to the checked answer.
+type DNSSECStatus struct {
+	Signed       bool     `json:"signed"`
+	Valid        bool     `json:"valid"`
+	DSMatched    bool     `json:"ds_matched"`
+	KeysSigned   bool     `json:"keys_signed"`
+	AnswerSigned bool     `json:"answer_signed"`
+	KeyTags      []uint16 `json:"key_tags,omitempty"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// PropagationReport collects the answers of all authoritative nameservers.
+// Consistent reports whether they agree on the values and the zone serial.
+type PropagationReport struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Zone        string             `json:"zone"`
+	Expected    []string           `json:"expected,omitempty"`
+	Nameservers []NameserverAnswer `json:"nameservers"`
+	Propagated  bool               `json:"propagated"`
+	Consistent  bool               `json:"consistent"`
+	DNSSEC      DNSSECStatus       `json:"dnssec"`
+	CheckedAt   time.Time          `json:"checked_at"`
+}
+
+// PropagationChecker queries the authoritative nameservers of a zone
+// directly. Resolvers are only used to find the zone, its nameservers and
+// the DS records at the parent.
+type PropagationChecker struct {
+	Resolvers []string
+	Port      string
+	Timeout   time.Duration
+}
+
+// NewPropagationChecker builds a checker using the recursive nameservers of
+// the certificate settings, falling back to the system resolvers.
+func NewPropagationChecker() *PropagationChecker {
+	return &PropagationChecker{
+		Resolvers: defaultPropagationResolvers(),
+		Port:      nameserverPort,
+		Timeout:   propagationQueryTimeout,
+	}
+}
+
+// Check queries every authoritative nameserver once.
+func (c *PropagationChecker) Check(ctx context.Context, q PropagationQuery) (*PropagationReport, error) {
+	name, qtype, err := parsePropagationQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	zone, nameservers, err := c.findZone(ctx, name, q.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PropagationReport{
+		Name:        name,
+		Type:        mdns.TypeToString[qtype],
+		Zone:        zone,
+		Expected:    q.Expected,
+		Nameservers: make([]NameserverAnswer, 0, len(nameservers)),
+		CheckedAt:   time.Now(),
+	}
+	for _, nameserver := range nameservers {
+		answer := c.queryNameserver(ctx, nameserver, zone, name, qtype)
+		answer.Propagated = answer.Error == "" && answer.Rcode == mdns.RcodeToString[mdns.RcodeSuccess] &&
+			answerMatches(answer.Values, q.Expected)
+		report.Nameservers = append(report.Nameservers, answer)
+	}
+
+	report.Propagated = len(report.Nameservers) > 0
+	for _, answer := range report.Nameservers {
+		report.Propagated = report.Propagated && answer.Propagated
+	}
+	report.Consistent = answersConsistent(report.Nameservers)
+	report.DNSSEC = c.checkDNSSEC(ctx, zone, report.Nameservers)
+
+	return report, nil
+}
+
+// Wait checks q every interval until all nameservers answer as expected or
+// ctx ends, and returns the last complete report either way.
+func (c *PropagationChecker) Wait(ctx context.Context, q PropagationQuery, interval time.Duration) (*PropagationReport, error) {
+	if _, _, err := parsePropagationQuery(q); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = defaultPropagationInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *PropagationReport
+	for {
+		report, err := c.Check(ctx, q)
+		if err == nil {
+			if report.Propagated {
+				return report, nil
+			}
+			last = report
+		}
+
+		select {
+		case <-ctx.Done():
+			// Without any report the check itself kept failing, which says
+			// more than running out of time.
+			if last == nil && err != nil {
+				return nil, err
+			}
+			return last, cosy.WrapErrorWithParams(ErrPropagationTimeout, q.Name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckPropagation checks a record of a managed domain. Name is relative to
+// the domain or fully qualified within it. With wait set it polls until the
+// record propagated or timeout elapsed.
+func (s *Service) CheckPropagation(ctx context.Context, domainID uint64, name, recordType string, expected []string, wait bool, timeout time.Duration) (*PropagationReport, error) {
+	domain, err := loadDomain(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := PropagationQuery{
+		Name:     recordFQDN(name, domain.Domain),
+		Type:     recordType,
+		Expected: expected,
+		Zone:     domain.Domain,
+	}
+	checker := NewPropagationChecker()
+	if !wait {
+		return checker.Check(ctx, q)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultPropagationTimeout
+	}
+	timeout = min(timeout, maxPropagationTimeout)
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return checker.Wait(waitCtx, q, 0)
+}
+
+func parsePropagationQuery(q PropagationQuery) (string, uint16, error) {
+	qtype, ok := mdns.StringToType[strings.ToUpper(strings.TrimSpace(q.Type))]
+	if !ok || qtype == mdns.TypeNone || qtype == mdns.TypeANY {
+		return "", 0, cosy.WrapErrorWithParams(ErrInvalidPropagationQuery, q.Type)
+	}
+	name := mdns.Fqdn(strings.ToLower(strings.TrimSpace(q.Name)))
+	if _, ok := mdns.IsDomainName(name); !ok || name == "." {
+		return "", 0, cosy.WrapErrorWithParams(ErrInvalidPropagationQuery, q.Name)
+	}
+	return name, qtype, nil
+}
+
+// recordFQDN qualifies name with domain unless it already lies within it.
+func recordFQDN(name, domain string) string {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if name == "" || name == "@" || name == domain {
+		return domain
+	}
+	if strings.HasSuffix(name, "."+domain) {
+		return name
+	}
+	return name + "." + domain
+}
+
+// findZone returns the closest enclosing zone of name that has nameservers,
+// or only tries zone when it is given.
+func (c *PropagationChecker) findZone(ctx context.Context, name, zone string) (string, []string, error) {
+	var candidates []string
+	if zone != "" {
+		candidates = []string{mdns.Fqdn(strings.ToLower(zone))}
+	} else {
+		for offset, end := 0, false; !end; offset, end = mdns.NextLabel(name, offset) {
+			candidates = append(candidates, name[offset:])
+		}
+	}
+
+	for _, candidate := range candidates {
+		resp, err := c.resolve(ctx, candidate, mdns.TypeNS, false)
+		if err != nil {
+			return "", nil, err
+		}
+		var nameservers []string
+		for _, rr := range resp.Answer {
+			if ns, ok := rr.(*mdns.NS); ok && strings.EqualFold(ns.Hdr.Name, candidate) {
+				nameservers = append(nameservers, strings.ToLower(ns.Ns))
+			}
+		}
+		if len(nameservers) > 0 {
+			slices.Sort(nameservers)
+			return candidate, slices.Compact(nameservers), nil
+		}
+	}
+
+	return "", nil, cosy.WrapErrorWithParams(ErrPropagationZoneNotFound, name)
+}
+
+func (c *PropagationChecker) queryNameserver(ctx context.Context, nameserver, zone, name string, qtype uint16) NameserverAnswer {
+	answer := NameserverAnswer{Nameserver: strings.TrimSuffix(nameserver, "."), Values: []string{}}
+
+	addresses, err := c.lookupAddresses(ctx, nameserver)
+	if err != nil {
+		answer.Error = err.Error()
+		return answer
+	}
+
+	var resp *mdns.Msg
+	for _, address := range addresses {
+		resp, err = c.exchange(ctx, newQuery(name, qtype, false, true), net.JoinHostPort(address, c.port()))
+		if err == nil {
+			answer.Address = address
+			break
+		}
+	}
+	if resp == nil {
+		answer.Error = err.Error()
+		return answer
+	}
+
+	answer.Rcode = mdns.RcodeToString[resp.Rcode]
+	answer.Authoritative = resp.Authoritative
+	if !resp.Authoritative {
+		answer.Error = "not authoritative for " + strings.TrimSuffix(zone, ".")
+	}
+	for _, rr := range resp.Answer {
+		header := rr.Header()
+		if !strings.EqualFold(header.Name, name) {
+			continue
+		}
+		if sig, ok := rr.(*mdns.RRSIG); ok && sig.TypeCovered == qtype {
+			answer.sigs = append(answer.sigs, sig)
+			continue
+		}
+		if header.Rrtype != qtype {
+			continue
+		}
+		if len(answer.rrs) == 0 || header.Ttl < answer.TTL {
+			answer.TTL = header.Ttl
+		}
+		answer.rrs = append(answer.rrs, rr)
+		answer.Values = append(answer.Values, recordInputValue(rr))
+	}
+	slices.Sort(answer.Values)
+
+	if soa, err := c.exchange(ctx, newQuery(zone, mdns.TypeSOA, false, false), net.JoinHostPort(answer.Address, c.port())); err == nil {
+		for _, rr := range soa.Answer {
+			if record, ok := rr.(*mdns.SOA); ok {
+				answer.Serial = record.Serial
+			}
+		}
+	}
+
+	return answer
+}
+
+func (c *PropagationChecker) checkDNSSEC(ctx context.Context, zone string, answers []NameserverAnswer) DNSSECStatus {
+	var status DNSSECStatus
+
+	index := slices.IndexFunc(answers, func(answer NameserverAnswer) bool { return answer.Address != "" })
+	if index < 0 {
+		return status
+	}
+	answer := answers[index]
+
+	resp, err := c.exchange(ctx, newQuery(zone, mdns.TypeDNSKEY, false, true), net.JoinHostPort(answer.Address, c.port()))
+	if err != nil {
+		status.Errors = append(status.Errors, "DNSKEY: "+err.Error())
+		return status
+	}
+
+	var (
+		keys    []*mdns.DNSKEY
+		keySet  []mdns.RR
+		keySigs []*mdns.RRSIG
+	)
+	for _, rr := range resp.Answer {
+		switch record := rr.(type) {
+		case *mdns.DNSKEY:
+			keys = append(keys, record)
+			keySet = append(keySet, record)
+			status.KeyTags = append(status.KeyTags, record.KeyTag())
+		case *mdns.RRSIG:
+			if record.TypeCovered == mdns.TypeDNSKEY {
+				keySigs = append(keySigs, record)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return status
+	}
+	status.Signed = true
+
+	// The DS set at the parent vouches for the keys signing the DNSKEY set.
+	anchors := keys
+	ds, err := c.resolve(ctx, zone, mdns.TypeDS, true)
+	if err != nil {
+		status.Errors = append(status.Errors, "DS: "+err.Error())
+	} else {
+		anchors = matchDS(keys, ds.Answer)
+		status.DSMatched = len(anchors) > 0
+		if !status.DSMatched {
+			status.Errors = append(status.Errors, "no DS record at the parent matches a DNSKEY of "+strings.TrimSuffix(zone, "."))
+			anchors = keys
+		}
+	}
+
+	now := time.Now()
+	if err := verifyRRSet(keySigs, anchors, keySet, now); err != nil {
+		status.Errors = append(status.Errors, "DNSKEY: "+err.Error())
+	} else {
+		status.KeysSigned = true
+	}
+
+	if len(answer.rrs) > 0 {
+		if err := verifyRRSet(answer.sigs, keys, answer.rrs, now); err != nil {
+			status.Errors = append(status.Errors, "answer: "+err.Error())
+		} else {
+			status.AnswerSigned = true
+		}
+	}
+
+	status.Valid = status.DSMatched && status.KeysSigned && (status.AnswerSigned || len(answer.rrs) == 0)
+	return status
+}
+
+// matchDS returns the keys a DS record of the parent refers to.
+func matchDS(keys []*mdns.DNSKEY, records []mdns.RR) []*mdns.DNSKEY {
+	var matched []*mdns.DNSKEY
+	for _, key := range keys {
+		for _, rr := range records {
+			ds, ok := rr.(*mdns.DS)
+			if !ok || ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+				continue
+			}
+			if digest := key.ToDS(ds.DigestType); digest != nil && strings.EqualFold(digest.Digest, ds.Digest) {
+				matched = append(matched, key)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// verifyRRSet succeeds when one of sigs is currently valid and made by one of
+// keys.
+func verifyRRSet(sigs []*mdns.RRSIG, keys []*mdns.DNSKEY, rrset []mdns.RR, now time.Time) error {
+	if len(sigs) == 0 {
+		return mdns.ErrNoSig
+	}
+
+	err := mdns.ErrKey
+	for _, sig := range sigs {
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if verifyErr := sig.Verify(key, rrset); verifyErr != nil {
+				err = verifyErr
+				continue
+			}
+			if !sig.ValidityPeriod(now) {
+				err = mdns.ErrTime
+				continue
+			}
+			return nil
+		}
+	}
+	return err
+}
+
+func (c *PropagationChecker) lookupAddresses(ctx context.Context, host string) ([]string, error) {
+	if ip := net.ParseIP(strings.TrimSuffix(host, ".")); ip != nil {
+		return []string{ip.String()}, nil
+	}
+
+	var addresses []string
+	var lastErr error
+	for _, qtype := range []uint16{mdns.TypeA, mdns.TypeAAAA} {
+		resp, err := c.resolve(ctx, host, qtype, false)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, rr := range resp.Answer {
+			switch record := rr.(type) {
+			case *mdns.A:
+				addresses = append(addresses, record.A.String())
+			case *mdns.AAAA:
+				addresses = append(addresses, record.AAAA.String())
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, cosy.WrapErrorWithParams(ErrNameserverUnresolved, strings.TrimSuffix(host, "."))
+	}
+	return addresses, nil
+}
+
+// resolve asks the recursive resolvers in turn until one answers.
+func (c *PropagationChecker) resolve(ctx context.Context, name string, qtype uint16, dnssec bool) (*mdns.Msg, error) {
+	resolvers := c.Resolvers
+	if len(resolvers) == 0 {
+		resolvers = fallbackResolvers
+	}
+
+	var lastErr error
+	for _, resolver := range resolvers {
+		resp, err := c.exchange(ctx, newQuery(name, qtype, true, dnssec), resolver)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Rcode == mdns.RcodeServerFailure || resp.Rcode == mdns.RcodeRefused {
+			lastErr = cosy.WrapErrorWithParams(ErrPropagationResolver, resolver, mdns.RcodeToString[resp.Rcode])
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// exchange sends msg over UDP and retries over TCP when the answer is
+// truncated.
+func (c *PropagationChecker) exchange(ctx context.Context, msg *mdns.Msg, address string) (*mdns.Msg, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = propagationQueryTimeout
+	}
+
+	client := &mdns.Client{Net: "udp", Timeout: timeout}
+	resp, _, err := client.ExchangeContext(ctx, msg, address)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, address)
+	}
+	return resp, err
+}
+
+func (c *PropagationChecker) port() string {
+	if c.Port == "" {
+		return nameserverPort
+	}
+	return c.Port
+}
+
+func newQuery(name string, qtype uint16, recursive, dnssec bool) *mdns.Msg {
+	msg := new(mdns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.RecursionDesired = recursive
+	msg.SetEdns0(mdns.DefaultMsgSize, dnssec)
+	return msg
+}
+
+func answerMatches(values, expected []string) bool {
+	if len(expected) == 0 {
+		return len(values) > 0
+	}
+	for _, value := range expected {
+		if !siteRecordContains(strings.Join(values, "\n"), value) {
+			return false
+		}
+	}
+	return true
+}
+
+func answersConsistent(answers []NameserverAnswer) bool {
+	var first *NameserverAnswer
+	for i := range answers {
+		answer := &answers[i]
+		if answer.Error != "" {
+			return false
+		}
+		if first == nil {
+			first = answer
+			continue
+		}
+		if answer.Serial != first.Serial || !slices.EqualFunc(answer.Values, first.Values, strings.EqualFold) {
+			return false
+		}
+	}
+	return first != nil
+}
+
+func defaultPropagationResolvers() []string {
+	if len(propagationResolvers) > 0 {
+		return propagationResolvers
+	}
+	if len(settings.CertSettings.RecursiveNameservers) > 0 {
+		return settings.CertSettings.RecursiveNameservers
+	}
+
+	config, err := mdns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(config.Servers) == 0 {
+		return fallbackResolvers
+	}
+	resolvers := make([]string, 0, len(config.Servers))
+	for _, server := range config.Servers {
+		resolvers = append(resolvers, net.JoinHostPort(server, config.Port))
+	}
+	return resolvers
+}
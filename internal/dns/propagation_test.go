@@ -0,0 +1,303 @@
+package dns_test
+
+import (
+	"context"
+	"crypto"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dnsSvc "github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/model"
+	mdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// authoritativeServer answers every query from its own records, acting as
+// both the resolver and an authoritative nameserver of example.com.
+type authoritativeServer struct {
+	mu      sync.Mutex
+	records []mdns.RR
+	server  *mdns.Server
+}
+
+func (s *authoritativeServer) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
+	reply := new(mdns.Msg)
+	reply.SetReply(r)
+	reply.Authoritative = true
+
+	question := r.Question[0]
+	s.mu.Lock()
+	for _, rr := range s.records {
+		header := rr.Header()
+		if !strings.EqualFold(header.Name, question.Name) {
+			continue
+		}
+		if sig, ok := rr.(*mdns.RRSIG); ok && sig.TypeCovered == question.Qtype {
+			reply.Answer = append(reply.Answer, rr)
+		} else if header.Rrtype == question.Qtype {
+			reply.Answer = append(reply.Answer, rr)
+		}
+	}
+	s.mu.Unlock()
+
+	_ = w.WriteMsg(reply)
+}
+
+func (s *authoritativeServer) set(records []mdns.RR) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = records
+}
+
+// startNameservers starts ns1 on 127.0.0.1 and ns2 on 127.0.0.2 sharing one
+// port, and returns a checker pointed at them.
+func startNameservers(t *testing.T) (*authoritativeServer, *authoritativeServer, *dnsSvc.PropagationChecker) {
+	t.Helper()
+
+	first := &authoritativeServer{}
+	firstConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(firstConn.LocalAddr().String())
+	require.NoError(t, err)
+
+	second := &authoritativeServer{}
+	secondConn, err := net.ListenPacket("udp", net.JoinHostPort("127.0.0.2", port))
+	if err != nil {
+		_ = firstConn.Close()
+		t.Skipf("cannot listen on 127.0.0.2: %v", err)
+	}
+
+	for _, server := range []struct {
+		ns   *authoritativeServer
+		conn net.PacketConn
+	}{{first, firstConn}, {second, secondConn}} {
+		started := make(chan struct{})
+		server.ns.server = &mdns.Server{PacketConn: server.conn, Handler: server.ns, NotifyStartedFunc: func() { close(started) }}
+		go func() { _ = server.ns.server.ActivateAndServe() }()
+		<-started
+		t.Cleanup(func() { _ = server.ns.server.Shutdown() })
+	}
+
+	checker := &dnsSvc.PropagationChecker{
+		Resolvers: []string{net.JoinHostPort("127.0.0.1", port)},
+		Port:      port,
+		Timeout:   time.Second,
+	}
+	return first, second, checker
+}
+
+func zoneRecords(t *testing.T, serial uint32, records ...string) []mdns.RR {
+	t.Helper()
+
+	base := []string{
+		"example.com. 3600 IN NS ns1.example.com.",
+		"example.com. 3600 IN NS ns2.example.com.",
+		"ns1.example.com. 3600 IN A 127.0.0.1",
+		"ns2.example.com. 3600 IN A 127.0.0.2",
+	}
+	rrs := []mdns.RR{&mdns.SOA{
+		Hdr:     mdns.RR_Header{Name: "example.com.", Rrtype: mdns.TypeSOA, Class: mdns.ClassINET, Ttl: 3600},
+		Ns:      "ns1.example.com.",
+		Mbox:    "hostmaster.example.com.",
+		Serial:  serial,
+		Refresh: 7200, Retry: 3600, Expire: 1209600, Minttl: 300,
+	}}
+	for _, record := range append(base, records...) {
+		rr, err := mdns.NewRR(record)
+		require.NoError(t, err)
+		rrs = append(rrs, rr)
+	}
+	return rrs
+}
+
+func TestPropagationCheckerReportsEveryNameserver(t *testing.T) {
+	first, second, checker := startNameservers(t)
+	first.set(zoneRecords(t, 2, "www.example.com. 300 IN A 192.0.2.1"))
+	second.set(zoneRecords(t, 1, "www.example.com. 600 IN A 192.0.2.9"))
+
+	_, err := checker.Check(context.Background(), dnsSvc.PropagationQuery{Name: "www.example.com", Type: "BOGUS"})
+	require.ErrorContains(t, err, "Invalid propagation query")
+
+	report, err := checker.Check(context.Background(), dnsSvc.PropagationQuery{
+		Name:     "WWW.example.com",
+		Type:     "a",
+		Expected: []string{"192.0.2.1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com.", report.Name)
+	require.Equal(t, "A", report.Type)
+	require.Equal(t, "example.com.", report.Zone)
+	require.False(t, report.Propagated)
+	require.False(t, report.Consistent)
+	require.False(t, report.DNSSEC.Signed)
+
+	require.Len(t, report.Nameservers, 2)
+	ns1, ns2 := report.Nameservers[0], report.Nameservers[1]
+	require.Equal(t, "ns1.example.com", ns1.Nameserver)
+	require.Equal(t, "127.0.0.1", ns1.Address)
+	require.Equal(t, []string{"192.0.2.1"}, ns1.Values)
+	require.EqualValues(t, 300, ns1.TTL)
+	require.EqualValues(t, 2, ns1.Serial)
+	require.True(t, ns1.Authoritative)
+	require.True(t, ns1.Propagated)
+
+	require.Equal(t, "127.0.0.2", ns2.Address)
+	require.Equal(t, []string{"192.0.2.9"}, ns2.Values)
+	require.EqualValues(t, 1, ns2.Serial)
+	require.False(t, ns2.Propagated)
+}
+
+func TestPropagationCheckerWait(t *testing.T) {
+	first, second, checker := startNameservers(t)
+	first.set(zoneRecords(t, 2, `_acme-challenge.example.com. 60 IN TXT "token"`))
+	second.set(zoneRecords(t, 1))
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		second.set(zoneRecords(t, 2, `_acme-challenge.example.com. 60 IN TXT "token"`))
+	}()
+
+	q := dnsSvc.PropagationQuery{Name: "_acme-challenge.example.com", Type: "TXT", Expected: []string{"token"}, Zone: "example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := checker.Wait(ctx, q, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, report.Propagated)
+	require.True(t, report.Consistent)
+
+	q.Expected = []string{"other"}
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	report, err = checker.Wait(ctx, q, 50*time.Millisecond)
+	require.ErrorContains(t, err, "did not propagate")
+	require.NotNil(t, report)
+	require.False(t, report.Propagated)
+}
+
+func TestPropagationCheckerValidatesDNSSEC(t *testing.T) {
+	first, second, checker := startNameservers(t)
+
+	key := &mdns.DNSKEY{
+		Hdr:       mdns.RR_Header{Name: "example.com.", Rrtype: mdns.TypeDNSKEY, Class: mdns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: mdns.ECDSAP256SHA256,
+	}
+	privateKey, err := key.Generate(256)
+	require.NoError(t, err)
+	signer := privateKey.(crypto.Signer)
+
+	sign := func(rrset []mdns.RR) mdns.RR {
+		sig := &mdns.RRSIG{
+			Hdr:        mdns.RR_Header{Name: rrset[0].Header().Name, Rrtype: mdns.TypeRRSIG, Class: mdns.ClassINET, Ttl: 3600},
+			KeyTag:     key.KeyTag(),
+			SignerName: "example.com.",
+			Algorithm:  key.Algorithm,
+			Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+			Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+		}
+		require.NoError(t, sig.Sign(signer, rrset))
+		return sig
+	}
+
+	answer, err := mdns.NewRR("www.example.com. 300 IN A 192.0.2.1")
+	require.NoError(t, err)
+	records := append(zoneRecords(t, 1), answer, sign([]mdns.RR{answer}), key, sign([]mdns.RR{key}))
+	ds := key.ToDS(mdns.SHA256)
+	first.set(append(records, ds))
+	second.set(records)
+
+	q := dnsSvc.PropagationQuery{Name: "www.example.com", Type: "A"}
+	report, err := checker.Check(context.Background(), q)
+	require.NoError(t, err)
+	require.True(t, report.Propagated)
+	require.True(t, report.DNSSEC.Signed)
+	require.True(t, report.DNSSEC.Valid, report.DNSSEC.Errors)
+	require.Equal(t, []uint16{key.KeyTag()}, report.DNSSEC.KeyTags)
+
+	// A DS at the parent that no key matches breaks the chain.
+	ds.Digest = strings.Repeat("0", len(ds.Digest))
+	first.set(append(records, ds))
+	report, err = checker.Check(context.Background(), q)
+	require.NoError(t, err)
+	require.True(t, report.DNSSEC.Signed)
+	require.False(t, report.DNSSEC.DSMatched)
+	require.True(t, report.DNSSEC.KeysSigned)
+	require.True(t, report.DNSSEC.AnswerSigned)
+	require.False(t, report.DNSSEC.Valid)
+	require.NotEmpty(t, report.DNSSEC.Errors)
+}
+
+func TestCheckPropagationForManagedDomain(t *testing.T) {
+	first, second, checker := startNameservers(t)
+	first.set(zoneRecords(t, 1, "example.com. 300 IN A 192.0.2.1"))
+	second.set(zoneRecords(t, 1, "example.com. 300 IN A 192.0.2.1"))
+	restore := dnsSvc.OverridePropagationForTest(checker.Resolvers, checker.Port, time.Second)
+	defer restore()
+
+	registerMockProvider()
+	q := setupTestQuery(t)
+	t.Cleanup(func() { resetDomains(t, q) })
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createCredential(t, q).ID)
+
+	report, err := service.CheckPropagation(context.Background(), domain.ID, "@", "A", []string{"192.0.2.1"}, false, 0)
+	require.NoError(t, err)
+	require.Equal(t, "example.com.", report.Name)
+	require.True(t, report.Propagated)
+	require.True(t, report.Consistent)
+
+	report, err = service.CheckPropagation(context.Background(), domain.ID, "@", "A", []string{"192.0.2.2"}, true, 300*time.Millisecond)
+	require.ErrorContains(t, err, "did not propagate")
+	require.False(t, report.Propagated)
+}
+
+func TestRunDDNSUpdateWaitsForPropagation(t *testing.T) {
+	first, second, checker := startNameservers(t)
+	first.set(zoneRecords(t, 2, "home.example.com. 600 IN A 198.51.100.12"))
+	second.set(zoneRecords(t, 1, "home.example.com. 600 IN A 198.51.100.10"))
+	restore := dnsSvc.OverridePropagationForTest(checker.Resolvers, checker.Port, 300*time.Millisecond)
+	defer restore()
+
+	registerMockProvider()
+	setMockRecords([]dnsSvc.Record{
+		{ID: "home-a", Type: "A", Name: "home", Content: "198.51.100.10", TTL: 600},
+	})
+
+	ipv4Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("198.51.100.12"))
+	}))
+	defer ipv4Server.Close()
+	restoreEndpoints := dnsSvc.OverrideIPEndpointsForTest([]string{ipv4Server.URL}, []string{"http://127.0.0.1:1"})
+	defer restoreEndpoints()
+
+	q := setupTestQuery(t)
+	t.Cleanup(func() { resetDomains(t, q) })
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createCredential(t, q).ID)
+
+	_, err := service.UpdateDDNSConfig(ctx, domain.ID, dnsSvc.DDNSUpdateInput{
+		Enabled:         true,
+		IntervalSeconds: dnsSvc.DefaultDDNSInterval(),
+		IPVersion:       "ipv4",
+		RecordIDs:       []string{"home-a"},
+		WaitPropagation: true,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, dnsSvc.RunDDNSUpdate(ctx, domain.ID))
+	require.Equal(t, []string{"home-a"}, getMockUpdatedRecordIDs())
+
+	// ns2 still serves the previous address.
+	var persisted model.DnsDomain
+	require.NoError(t, model.UseDB().WithContext(ctx).First(&persisted, domain.ID).Error)
+	require.True(t, persisted.DDNSConfig.WaitPropagation)
+	require.Contains(t, persisted.DDNSConfig.LastError, "home.example.com A")
+	require.Contains(t, persisted.DDNSConfig.LastError, "did not propagate")
+}
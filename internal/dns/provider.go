@@ -72,6 +72,13 @@ type RecordLineProvider interface {
 	ListRecordLines(ctx context.Context, domain string) ([]RecordLine, error)
 }
 
+// RecordSetProvider is implemented by providers that expose each record set as
+// a single Record listing its values one per line in Content, rather than one
+// Record per value.
+type RecordSetProvider interface {
+	ManagesRecordSets() bool
+}
+
 // Factory constructs a provider using the given credential.
 type Factory func(*Credential) (Provider, error)
 
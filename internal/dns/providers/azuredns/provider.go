@@ -34,6 +34,11 @@ type provider struct {
 	zoneName       string
 }
 
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
 func init() {
 	dns.RegisterProvider(providerCode, newProvider)
 }
@@ -252,6 +257,11 @@ func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID str
 	return nil
 }
 
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
 // recordSetsClient builds a record sets client bound to the configured subscription.
 func (p *provider) recordSetsClient() (*armdns.RecordSetsClient, error) {
 	client, err := armdns.NewRecordSetsClient(p.subscriptionID, p.credential, p.armOptions())
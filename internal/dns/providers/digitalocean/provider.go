@@ -0,0 +1,273 @@
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	defaultBaseURL = "https://api.digitalocean.com"
+	defaultTimeout = 10 * time.Second
+	defaultTTL     = 1800
+	pageSize       = 200
+)
+
+type provider struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+type domainRecord struct {
+	ID       int64  `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Data     string `json:"data"`
+	Priority *int   `json:"priority"`
+	Port     *int   `json:"port"`
+	TTL      int    `json:"ttl"`
+	Weight   *int   `json:"weight"`
+	Flags    *int   `json:"flags,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+var _ dns.Provider = (*provider)(nil)
+
+func init() {
+	dns.RegisterProvider("digitalocean", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	return newProviderWithHTTPClient(cred, &http.Client{Timeout: defaultTimeout})
+}
+
+func newProviderWithHTTPClient(cred *dns.Credential, httpClient *http.Client) (dns.Provider, error) {
+	token := strings.TrimSpace(cred.Values["DO_AUTH_TOKEN"])
+	if token == "" {
+		return nil, fmt.Errorf("digitalocean: missing auth token")
+	}
+
+	baseURL := strings.TrimSpace(cred.Additional["DO_API_URL"])
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &provider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	zone := normalizeDomain(domain)
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(pageSize))
+	if recordType := strings.ToUpper(strings.TrimSpace(filter.Type)); recordType != "" {
+		query.Set("type", recordType)
+	}
+	if name := strings.TrimSpace(filter.Name); name != "" {
+		// The API filters by fully qualified name, but only together with a type.
+		if query.Get("type") != "" {
+			query.Set("name", strings.TrimSuffix(dns.RecordSetFQDN(zone, name), "."))
+		}
+	}
+
+	result := make([]dns.Record, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var response struct {
+			DomainRecords []domainRecord `json:"domain_records"`
+			Links         struct {
+				Pages struct {
+					Next string `json:"next"`
+				} `json:"pages"`
+			} `json:"links"`
+		}
+		if err := p.do(ctx, http.MethodGet, recordsPath(zone)+"?"+query.Encode(), nil, &response); err != nil {
+			return nil, fmt.Errorf("digitalocean: list records: %w", err)
+		}
+
+		for _, record := range response.DomainRecords {
+			if name := strings.TrimSpace(filter.Name); name != "" &&
+				dns.RecordSetRelativeName(name, zone) != dns.RecordSetRelativeName(record.Name, zone) {
+				continue
+			}
+			result = append(result, toRecord(record, zone))
+		}
+
+		if response.Links.Pages.Next == "" || len(response.DomainRecords) == 0 {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	zone := normalizeDomain(domain)
+	payload, err := toPayload(zone, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("digitalocean: create record: %w", err)
+	}
+
+	var response struct {
+		DomainRecord domainRecord `json:"domain_record"`
+	}
+	if err := p.do(ctx, http.MethodPost, recordsPath(zone), payload, &response); err != nil {
+		return dns.Record{}, fmt.Errorf("digitalocean: create record: %w", err)
+	}
+
+	return toRecord(response.DomainRecord, zone), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	zone := normalizeDomain(domain)
+	payload, err := toPayload(zone, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("digitalocean: update record: %w", err)
+	}
+
+	var response struct {
+		DomainRecord domainRecord `json:"domain_record"`
+	}
+	path := recordsPath(zone) + "/" + url.PathEscape(recordID)
+	if err := p.do(ctx, http.MethodPut, path, payload, &response); err != nil {
+		return dns.Record{}, fmt.Errorf("digitalocean: update record: %w", err)
+	}
+
+	return toRecord(response.DomainRecord, zone), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	path := recordsPath(normalizeDomain(domain)) + "/" + url.PathEscape(recordID)
+	if err := p.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("digitalocean: delete record: %w", err)
+	}
+	return nil
+}
+
+func (p *provider) do(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s (%s, status %d)", apiErr.Message, apiErr.ID, resp.StatusCode)
+		}
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func toPayload(zone string, input dns.RecordInput) (domainRecord, error) {
+	recordType := strings.ToUpper(strings.TrimSpace(input.Type))
+	record := domainRecord{
+		Type:     recordType,
+		Name:     dns.RecordSetRelativeName(input.Name, zone),
+		Data:     strings.TrimSpace(input.Content),
+		TTL:      input.TTL,
+		Priority: input.Priority,
+		Weight:   input.Weight,
+	}
+	if record.TTL <= 0 {
+		record.TTL = defaultTTL
+	}
+	if record.Data == "" {
+		return domainRecord{}, fmt.Errorf("record value is required")
+	}
+
+	switch recordType {
+	case "MX":
+		if record.Priority == nil {
+			return domainRecord{}, fmt.Errorf("MX records require a priority")
+		}
+	case "SRV":
+		// SRV content is "<port> <target>", as stored by the other providers.
+		fields := strings.Fields(record.Data)
+		if len(fields) != 2 || record.Priority == nil || record.Weight == nil {
+			return domainRecord{}, fmt.Errorf("SRV records require priority, weight and a \"<port> <target>\" value")
+		}
+		port, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return domainRecord{}, fmt.Errorf("SRV record value must start with a numeric port")
+		}
+		record.Port = &port
+		record.Data = fields[1]
+	}
+
+	return record, nil
+}
+
+func toRecord(record domainRecord, zone string) dns.Record {
+	content := record.Data
+	if strings.EqualFold(record.Type, "SRV") && record.Port != nil {
+		content = fmt.Sprintf("%d %s", *record.Port, record.Data)
+	}
+
+	result := dns.Record{
+		ID:      strconv.FormatInt(record.ID, 10),
+		Type:    strings.ToUpper(record.Type),
+		Name:    dns.RecordSetRelativeName(record.Name, zone),
+		Content: content,
+		TTL:     record.TTL,
+	}
+	switch result.Type {
+	case "MX":
+		result.Priority = record.Priority
+	case "SRV":
+		result.Priority = record.Priority
+		result.Weight = record.Weight
+	}
+	return result
+}
+
+func recordsPath(zone string) string {
+	return "/v2/domains/" + url.PathEscape(zone) + "/records"
+}
+
+func normalizeDomain(domain string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,150 @@
+package digitalocean
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string]domainRecord{
+		"1": {ID: 1, Type: "A", Name: "@", Data: "192.0.2.1", TTL: 1800},
+	}
+	nextID := int64(2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization")) {
+			http.Error(w, "missing authorization", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		const base = "/v2/domains/example.com/records"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == base:
+			assert.Equal(t, "200", r.URL.Query().Get("per_page"))
+			list := make([]domainRecord, 0)
+			next := ""
+			if r.URL.Query().Get("page") == "1" {
+				for _, record := range stored {
+					if recordType := r.URL.Query().Get("type"); recordType != "" && record.Type != recordType {
+						continue
+					}
+					list = append(list, record)
+				}
+				next = "page-2"
+			}
+			writeJSON(t, w, http.StatusOK, map[string]any{
+				"domain_records": list,
+				"links":          map[string]any{"pages": map[string]any{"next": next}},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == base:
+			var payload domainRecord
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload)) {
+				return
+			}
+			payload.ID = nextID
+			nextID++
+			stored[strconv.FormatInt(payload.ID, 10)] = payload
+			writeJSON(t, w, http.StatusCreated, map[string]any{"domain_record": payload})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, base+"/"):
+			id := strings.TrimPrefix(r.URL.Path, base+"/")
+			existing, ok := stored[id]
+			if !ok {
+				writeJSON(t, w, http.StatusNotFound, map[string]any{"id": "not_found", "message": "record not found"})
+				return
+			}
+			var payload domainRecord
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload)) {
+				return
+			}
+			payload.ID = existing.ID
+			stored[id] = payload
+			writeJSON(t, w, http.StatusOK, map[string]any{"domain_record": payload})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, base+"/"):
+			delete(stored, strings.TrimPrefix(r.URL.Path, base+"/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(&dns.Credential{
+		Values:     map[string]string{"DO_AUTH_TOKEN": "test-token"},
+		Additional: map[string]string{"DO_API_URL": server.URL},
+	})
+	require.NoError(t, err)
+
+	priority := 10
+	weight := 20
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:     "srv",
+		Name:     "_sip._tcp",
+		Content:  "5060 sip.example.com.",
+		TTL:      300,
+		Priority: &priority,
+		Weight:   &weight,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2", created.ID)
+	require.Equal(t, "_sip._tcp", created.Name)
+	require.Equal(t, "5060 sip.example.com.", created.Content)
+	require.Equal(t, 10, *created.Priority)
+	require.Equal(t, 20, *created.Weight)
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "SRV"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{created}, records)
+
+	all, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "@"})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, "192.0.2.1", all[0].Content)
+
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", "1", dns.RecordInput{
+		Type:    "A",
+		Name:    "@",
+		Content: "192.0.2.2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1", updated.ID)
+	require.Equal(t, "192.0.2.2", updated.Content)
+	require.Equal(t, defaultTTL, updated.TTL)
+
+	_, err = provider.UpdateRecord(t.Context(), "example.com", "404", dns.RecordInput{Type: "A", Content: "192.0.2.3"})
+	require.ErrorContains(t, err, "record not found")
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", "2"))
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "SRV"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestNewProviderRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := newProvider(&dns.Credential{Values: map[string]string{}})
+	require.Error(t, err)
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, body any) {
+	t.Helper()
+	w.WriteHeader(status)
+	assert.NoError(t, json.NewEncoder(w).Encode(body))
+}
@@ -0,0 +1,212 @@
+package gandi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	defaultBaseURL = "https://api.gandi.net/v5/livedns"
+	defaultTimeout = 10 * time.Second
+	minTTL         = 300
+)
+
+// provider manages records through the Gandi LiveDNS v5 API. LiveDNS works on
+// record sets, which are exposed as one record each.
+type provider struct {
+	baseURL       string
+	authorization string
+	httpClient    *http.Client
+}
+
+type rrset struct {
+	Name   string   `json:"rrset_name,omitempty"`
+	Type   string   `json:"rrset_type,omitempty"`
+	TTL    int      `json:"rrset_ttl"`
+	Values []string `json:"rrset_values"`
+}
+
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
+func init() {
+	dns.RegisterProvider("gandiv5", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	return newProviderWithHTTPClient(cred, &http.Client{Timeout: defaultTimeout})
+}
+
+func newProviderWithHTTPClient(cred *dns.Credential, httpClient *http.Client) (dns.Provider, error) {
+	var authorization string
+	if token := strings.TrimSpace(cred.Values["GANDIV5_PERSONAL_ACCESS_TOKEN"]); token != "" {
+		authorization = "Bearer " + token
+	} else if apiKey := strings.TrimSpace(cred.Values["GANDIV5_API_KEY"]); apiKey != "" {
+		// API keys are deprecated by Gandi in favour of personal access tokens.
+		authorization = "Apikey " + apiKey
+	} else {
+		return nil, fmt.Errorf("gandiv5: missing personal access token or API key")
+	}
+
+	baseURL := strings.TrimSpace(cred.Additional["GANDIV5_API_URL"])
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &provider{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		authorization: authorization,
+		httpClient:    httpClient,
+	}, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	var sets []rrset
+	if err := p.do(ctx, http.MethodGet, recordsPath(domain), nil, &sets); err != nil {
+		return nil, fmt.Errorf("gandiv5: list records: %w", err)
+	}
+
+	result := make([]dns.Record, 0, len(sets))
+	for _, set := range sets {
+		if !dns.MatchRecordSet(domain, filter, set.Name, set.Type) {
+			continue
+		}
+		result = append(result, dns.RecordFromSet(domain, set.Name, set.Type, set.TTL, set.Values))
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("gandiv5: create record: %w", err)
+	}
+
+	if err := p.do(ctx, http.MethodPost, recordsPath(domain), set, nil); err != nil {
+		return dns.Record{}, fmt.Errorf("gandiv5: create record: %w", err)
+	}
+
+	return dns.RecordFromSet(domain, set.Name, set.Type, set.TTL, set.Values), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("gandiv5: update record: %w", err)
+	}
+
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("gandiv5: update record: %w", err)
+	}
+
+	// PUT creates or replaces the target set; a renamed or retyped record
+	// additionally drops the set it was read from.
+	body := rrset{TTL: set.TTL, Values: set.Values}
+	if err := p.do(ctx, http.MethodPut, rrsetPath(domain, set.Name, set.Type), body, nil); err != nil {
+		return dns.Record{}, fmt.Errorf("gandiv5: update record: %w", err)
+	}
+	if dns.RecordSetRelativeName(name, domain) != set.Name || recordType != set.Type {
+		if err := p.do(ctx, http.MethodDelete, rrsetPath(domain, dns.RecordSetRelativeName(name, domain), recordType), nil, nil); err != nil {
+			return dns.Record{}, fmt.Errorf("gandiv5: update record: remove %s: %w", recordID, err)
+		}
+	}
+
+	return dns.RecordFromSet(domain, set.Name, set.Type, set.TTL, set.Values), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return fmt.Errorf("gandiv5: delete record: %w", err)
+	}
+
+	if err := p.do(ctx, http.MethodDelete, rrsetPath(domain, dns.RecordSetRelativeName(name, domain), recordType), nil, nil); err != nil {
+		return fmt.Errorf("gandiv5: delete record: %w", err)
+	}
+
+	return nil
+}
+
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *provider) do(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", p.authorization)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+			Cause   string `json:"cause"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s (status %d)", apiErr.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func toRRSet(domain string, input dns.RecordInput) (rrset, error) {
+	values, err := dns.RecordSetValues(input)
+	if err != nil {
+		return rrset{}, err
+	}
+
+	return rrset{
+		Name:   dns.RecordSetRelativeName(input.Name, domain),
+		Type:   strings.ToUpper(strings.TrimSpace(input.Type)),
+		TTL:    max(input.TTL, minTTL),
+		Values: values,
+	}, nil
+}
+
+func recordsPath(domain string) string {
+	return "/domains/" + url.PathEscape(strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")) + "/records"
+}
+
+func rrsetPath(domain, name, recordType string) string {
+	return recordsPath(domain) + "/" + url.PathEscape(name) + "/" + url.PathEscape(recordType)
+}
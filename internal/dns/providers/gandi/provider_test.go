@@ -0,0 +1,134 @@
+package gandi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string]rrset{
+		"@/A": {Name: "@", Type: "A", TTL: 10800, Values: []string{"192.0.2.1"}},
+	}
+
+	const base = "/domains/example.com/records"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization")) {
+			http.Error(w, "missing authorization", http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, base), "/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == base:
+			list := make([]rrset, 0, len(stored))
+			for _, set := range stored {
+				list = append(list, set)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			assert.NoError(t, json.NewEncoder(w).Encode(list))
+		case r.Method == http.MethodPost && r.URL.Path == base:
+			var set rrset
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&set)) {
+				return
+			}
+			if _, ok := stored[set.Name+"/"+set.Type]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 409, "message": "A record with that name / type pair already exists"})
+				return
+			}
+			stored[set.Name+"/"+set.Type] = set
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && key != "":
+			var set rrset
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&set)) {
+				return
+			}
+			name, recordType, _ := strings.Cut(key, "/")
+			set.Name, set.Type = name, recordType
+			stored[key] = set
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete && key != "":
+			delete(stored, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(&dns.Credential{
+		Values:     map[string]string{"GANDIV5_PERSONAL_ACCESS_TOKEN": "test-token"},
+		Additional: map[string]string{"GANDIV5_API_URL": server.URL},
+	})
+	require.NoError(t, err)
+
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "cname",
+		Name:    "www.example.com",
+		Content: "example.com.",
+		TTL:     60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, dns.Record{
+		ID:      "www/CNAME",
+		Type:    "CNAME",
+		Name:    "www",
+		Content: "example.com.",
+		TTL:     minTTL,
+	}, created)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "CNAME", Name: "www", Content: "example.org."})
+	require.ErrorContains(t, err, "already exists")
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{created}, records)
+
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", created.ID, dns.RecordInput{
+		Type:    "A",
+		Name:    "www",
+		Content: "192.0.2.10\n192.0.2.11",
+		TTL:     600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www/A", updated.ID)
+	require.Equal(t, "192.0.2.10\n192.0.2.11", updated.Content)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "A"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "CNAME"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", updated.ID))
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestNewProviderAuthorization(t *testing.T) {
+	t.Parallel()
+
+	created, err := newProvider(&dns.Credential{Values: map[string]string{"GANDIV5_API_KEY": "legacy"}})
+	require.NoError(t, err)
+	require.Equal(t, "Apikey legacy", created.(*provider).authorization)
+
+	_, err = newProvider(&dns.Credential{Values: map[string]string{}})
+	require.Error(t, err)
+}
@@ -0,0 +1,286 @@
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	defaultBaseURL = "https://api.hetzner.cloud/v1"
+	defaultTimeout = 10 * time.Second
+	defaultTTL     = 3600
+	minTTL         = 60
+	pageSize       = 100
+)
+
+// provider manages records through the DNS endpoints of the Hetzner Cloud
+// API, which replaced the standalone Hetzner DNS console API. Hetzner works
+// on record sets, which are exposed as one record each.
+type provider struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+type rrset struct {
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	TTL     *int          `json:"ttl"`
+	Records []rrsetRecord `json:"records"`
+}
+
+type rrsetRecord struct {
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
+func init() {
+	dns.RegisterProvider("hetzner", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	return newProviderWithHTTPClient(cred, &http.Client{Timeout: defaultTimeout})
+}
+
+func newProviderWithHTTPClient(cred *dns.Credential, httpClient *http.Client) (dns.Provider, error) {
+	token := strings.TrimSpace(cred.Values["HETZNER_API_TOKEN"])
+	if token == "" {
+		return nil, fmt.Errorf("hetzner: missing API token")
+	}
+
+	baseURL := strings.TrimSpace(cred.Additional["HETZNER_API_URL"])
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &provider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(pageSize))
+	if recordType := strings.ToUpper(strings.TrimSpace(filter.Type)); recordType != "" {
+		query.Set("type", recordType)
+	}
+	if name := strings.TrimSpace(filter.Name); name != "" {
+		query.Set("name", dns.RecordSetRelativeName(name, domain))
+	}
+
+	result := make([]dns.Record, 0)
+	for page := 1; page > 0; {
+		query.Set("page", strconv.Itoa(page))
+
+		var response struct {
+			RRSets []rrset `json:"rrsets"`
+			Meta   struct {
+				Pagination struct {
+					NextPage *int `json:"next_page"`
+				} `json:"pagination"`
+			} `json:"meta"`
+		}
+		if err := p.do(ctx, http.MethodGet, rrsetsPath(domain)+"?"+query.Encode(), nil, &response); err != nil {
+			return nil, fmt.Errorf("hetzner: list records: %w", err)
+		}
+
+		for _, set := range response.RRSets {
+			if !dns.MatchRecordSet(domain, filter, set.Name, set.Type) {
+				continue
+			}
+			result = append(result, toRecord(domain, set))
+		}
+
+		page = 0
+		if next := response.Meta.Pagination.NextPage; next != nil {
+			page = *next
+		}
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: create record: %w", err)
+	}
+
+	var response struct {
+		RRSet rrset `json:"rrset"`
+	}
+	if err := p.do(ctx, http.MethodPost, rrsetsPath(domain), set, &response); err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: create record: %w", err)
+	}
+
+	return toRecord(domain, response.RRSet), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: update record: %w", err)
+	}
+	name = dns.RecordSetRelativeName(name, domain)
+
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: update record: %w", err)
+	}
+
+	if name != set.Name || recordType != set.Type {
+		// Name and type identify a record set, so moving a record means
+		// creating the new set before dropping the old one.
+		record, err := p.CreateRecord(ctx, domain, input)
+		if err != nil {
+			return dns.Record{}, err
+		}
+		if err := p.DeleteRecord(ctx, domain, recordID); err != nil {
+			return dns.Record{}, err
+		}
+		return record, nil
+	}
+
+	path := rrsetPath(domain, name, recordType)
+	if err := p.do(ctx, http.MethodPost, path+"/actions/set_records", map[string]any{"records": set.Records}, nil); err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: update record: %w", err)
+	}
+	if err := p.do(ctx, http.MethodPost, path+"/actions/change_ttl", map[string]any{"ttl": set.TTL}, nil); err != nil {
+		return dns.Record{}, fmt.Errorf("hetzner: update record: %w", err)
+	}
+
+	return toRecord(domain, set), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return fmt.Errorf("hetzner: delete record: %w", err)
+	}
+
+	if err := p.do(ctx, http.MethodDelete, rrsetPath(domain, dns.RecordSetRelativeName(name, domain), recordType), nil, nil); err != nil {
+		return fmt.Errorf("hetzner: delete record: %w", err)
+	}
+
+	return nil
+}
+
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *provider) do(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s (%s, status %d)", apiErr.Error.Message, apiErr.Error.Code, resp.StatusCode)
+		}
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func toRRSet(domain string, input dns.RecordInput) (rrset, error) {
+	values, err := dns.RecordSetValues(input)
+	if err != nil {
+		return rrset{}, err
+	}
+
+	ttl := input.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	ttl = max(ttl, minTTL)
+
+	set := rrset{
+		Name:    dns.RecordSetRelativeName(input.Name, domain),
+		Type:    strings.ToUpper(strings.TrimSpace(input.Type)),
+		TTL:     &ttl,
+		Records: make([]rrsetRecord, 0, len(values)),
+	}
+	for _, value := range values {
+		set.Records = append(set.Records, rrsetRecord{Value: value, Comment: input.Comment})
+	}
+
+	return set, nil
+}
+
+func toRecord(domain string, set rrset) dns.Record {
+	values := make([]string, 0, len(set.Records))
+	for _, record := range set.Records {
+		values = append(values, record.Value)
+	}
+
+	// A null TTL means the set inherits the zone default.
+	ttl := 0
+	if set.TTL != nil {
+		ttl = *set.TTL
+	}
+
+	record := dns.RecordFromSet(domain, set.Name, set.Type, ttl, values)
+	if len(set.Records) > 0 {
+		record.Comment = set.Records[0].Comment
+	}
+	return record
+}
+
+func rrsetsPath(domain string) string {
+	return "/zones/" + url.PathEscape(strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")) + "/rrsets"
+}
+
+func rrsetPath(domain, name, recordType string) string {
+	return rrsetsPath(domain) + "/" + url.PathEscape(name) + "/" + url.PathEscape(recordType)
+}
@@ -0,0 +1,183 @@
+package hetzner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string]rrset{
+		"@/NS": {Name: "@", Type: "NS", Records: []rrsetRecord{
+			{Value: "hydrogen.ns.hetzner.com."},
+			{Value: "oxygen.ns.hetzner.com."},
+		}},
+	}
+
+	const base = "/zones/example.com/rrsets"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization")) {
+			http.Error(w, "missing authorization", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		key, action, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, base), "/"), "/actions/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == base:
+			// Serve one record set per page to exercise pagination.
+			keys := make([]string, 0, len(stored))
+			for key, set := range stored {
+				if recordType := r.URL.Query().Get("type"); recordType != "" && set.Type != recordType {
+					continue
+				}
+				if name := r.URL.Query().Get("name"); name != "" && set.Name != name {
+					continue
+				}
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			sets := make([]rrset, 0, 1)
+			if page >= 1 && page <= len(keys) {
+				sets = append(sets, stored[keys[page-1]])
+			}
+			var next *int
+			if page < len(keys) {
+				next = new(int)
+				*next = page + 1
+			}
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"rrsets": sets,
+				"meta":   map[string]any{"pagination": map[string]any{"page": page, "next_page": next}},
+			}))
+		case r.Method == http.MethodPost && r.URL.Path == base:
+			var set rrset
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&set)) {
+				return
+			}
+			if _, ok := stored[set.Name+"/"+set.Type]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"code": "uniqueness_error", "message": "rrset already exists"}})
+				return
+			}
+			stored[set.Name+"/"+set.Type] = set
+			w.WriteHeader(http.StatusCreated)
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"rrset": set}))
+		case r.Method == http.MethodPost && action == "set_records":
+			var body struct {
+				Records []rrsetRecord `json:"records"`
+			}
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+				return
+			}
+			set := stored[key]
+			set.Records = body.Records
+			stored[key] = set
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"action":{"id":1}}`))
+		case r.Method == http.MethodPost && action == "change_ttl":
+			var body struct {
+				TTL *int `json:"ttl"`
+			}
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+				return
+			}
+			set := stored[key]
+			set.TTL = body.TTL
+			stored[key] = set
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"action":{"id":2}}`))
+		case r.Method == http.MethodDelete && key != "":
+			delete(stored, key)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"action":{"id":3}}`))
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(&dns.Credential{
+		Values:     map[string]string{"HETZNER_API_TOKEN": "test-token"},
+		Additional: map[string]string{"HETZNER_API_URL": server.URL},
+	})
+	require.NoError(t, err)
+
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "A",
+		Name:    "www",
+		Content: "192.0.2.1",
+		TTL:     30,
+		Comment: "web",
+	})
+	require.NoError(t, err)
+	require.Equal(t, dns.Record{
+		ID:      "www/A",
+		Type:    "A",
+		Name:    "www",
+		Content: "192.0.2.1",
+		TTL:     minTTL,
+		Comment: "web",
+	}, created)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "A", Name: "www", Content: "192.0.2.9"})
+	require.ErrorContains(t, err, "rrset already exists")
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "hydrogen.ns.hetzner.com.\noxygen.ns.hetzner.com.", records[0].Content)
+	require.Zero(t, records[0].TTL)
+	require.Equal(t, created, records[1])
+
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", created.ID, dns.RecordInput{
+		Type:    "A",
+		Name:    "www",
+		Content: "192.0.2.2",
+		TTL:     600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www/A", updated.ID)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "a", Name: "www"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "192.0.2.2", records[0].Content)
+	require.Equal(t, 600, records[0].TTL)
+
+	moved, err := provider.UpdateRecord(t.Context(), "example.com", updated.ID, dns.RecordInput{
+		Type:    "AAAA",
+		Name:    "www",
+		Content: "2001:db8::1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www/AAAA", moved.ID)
+	require.Equal(t, defaultTTL, moved.TTL)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{moved}, records)
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", moved.ID))
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -38,6 +38,7 @@ type huaweiDNSClient interface {
 var (
 	_ dns.Provider           = (*provider)(nil)
 	_ dns.RecordLineProvider = (*provider)(nil)
+	_ dns.RecordSetProvider  = (*provider)(nil)
 )
 
 func init() {
@@ -334,6 +335,11 @@ func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID str
 	return nil
 }
 
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
 func (p *provider) showRecord(ctx context.Context, zoneID, recordID string) (*hwmodel.ShowRecordSetWithLineResponse, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("huaweicloud: show record: %w", err)
@@ -0,0 +1,282 @@
+package powerdns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	defaultServerName = "localhost"
+	defaultTimeout    = 10 * time.Second
+	defaultTTL        = 3600
+)
+
+// provider manages records through the PowerDNS Authoritative HTTP API.
+// PowerDNS works on record sets, which are exposed as one record each.
+type provider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type rrset struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	TTL        int            `json:"ttl,omitempty"`
+	ChangeType string         `json:"changetype,omitempty"`
+	Records    []rrsetRecord  `json:"records"`
+	Comments   []rrsetComment `json:"comments,omitempty"`
+}
+
+type rrsetRecord struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+type rrsetComment struct {
+	Content string `json:"content"`
+	Account string `json:"account"`
+}
+
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
+func init() {
+	dns.RegisterProvider("pdns", newProvider)
+	dns.RegisterProvider("powerdns", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	return newProviderWithHTTPClient(cred, &http.Client{Timeout: defaultTimeout})
+}
+
+func newProviderWithHTTPClient(cred *dns.Credential, httpClient *http.Client) (dns.Provider, error) {
+	apiKey := strings.TrimSpace(cred.Values["PDNS_API_KEY"])
+	apiURL := strings.TrimSuffix(strings.TrimSpace(cred.Values["PDNS_API_URL"]), "/")
+	if apiKey == "" || apiURL == "" {
+		return nil, fmt.Errorf("pdns: missing API key or API URL")
+	}
+	if _, err := url.ParseRequestURI(apiURL); err != nil {
+		return nil, fmt.Errorf("pdns: invalid API URL: %w", err)
+	}
+
+	serverName := strings.TrimSpace(cred.Additional["PDNS_SERVER_NAME"])
+	if serverName == "" {
+		serverName = defaultServerName
+	}
+
+	// API version 0 predates the /api/v1 prefix (PowerDNS 3.x).
+	prefix := "/api/v1"
+	if strings.TrimSpace(cred.Additional["PDNS_API_VERSION"]) == "0" {
+		prefix = ""
+	}
+
+	return &provider{
+		baseURL:    apiURL + prefix + "/servers/" + url.PathEscape(serverName),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	sets, err := p.listRRSets(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("pdns: list records: %w", err)
+	}
+
+	result := make([]dns.Record, 0, len(sets))
+	for _, set := range sets {
+		if !dns.MatchRecordSet(domain, filter, set.Name, set.Type) {
+			continue
+		}
+		result = append(result, toRecord(domain, set))
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: create record: %w", err)
+	}
+
+	sets, err := p.listRRSets(ctx, domain)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: create record: %w", err)
+	}
+	for _, existing := range sets {
+		if strings.EqualFold(existing.Name, set.Name) && existing.Type == set.Type {
+			return dns.Record{}, fmt.Errorf("pdns: create record: %s already exists", dns.RecordSetID(dns.RecordSetRelativeName(set.Name, domain), set.Type))
+		}
+	}
+
+	if err := p.patch(ctx, domain, set); err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: create record: %w", err)
+	}
+
+	return toRecord(domain, set), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: update record: %w", err)
+	}
+
+	set, err := toRRSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: update record: %w", err)
+	}
+
+	changes := make([]rrset, 0, 2)
+	if previous := dns.RecordSetFQDN(domain, name); !strings.EqualFold(previous, set.Name) || recordType != set.Type {
+		changes = append(changes, rrset{
+			Name:       previous,
+			Type:       recordType,
+			ChangeType: "DELETE",
+			Records:    []rrsetRecord{},
+		})
+	}
+	changes = append(changes, set)
+
+	if err := p.patch(ctx, domain, changes...); err != nil {
+		return dns.Record{}, fmt.Errorf("pdns: update record: %w", err)
+	}
+
+	return toRecord(domain, set), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return fmt.Errorf("pdns: delete record: %w", err)
+	}
+
+	if err := p.patch(ctx, domain, rrset{
+		Name:       dns.RecordSetFQDN(domain, name),
+		Type:       recordType,
+		ChangeType: "DELETE",
+		Records:    []rrsetRecord{},
+	}); err != nil {
+		return fmt.Errorf("pdns: delete record: %w", err)
+	}
+
+	return nil
+}
+
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *provider) listRRSets(ctx context.Context, domain string) ([]rrset, error) {
+	var zone struct {
+		RRSets []rrset `json:"rrsets"`
+	}
+	if err := p.do(ctx, http.MethodGet, zonePath(domain), nil, &zone); err != nil {
+		return nil, err
+	}
+	return zone.RRSets, nil
+}
+
+func (p *provider) patch(ctx context.Context, domain string, sets ...rrset) error {
+	return p.do(ctx, http.MethodPatch, zonePath(domain), map[string]any{"rrsets": sets}, nil)
+}
+
+func (p *provider) do(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", p.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s (status %d)", apiErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func toRRSet(domain string, input dns.RecordInput) (rrset, error) {
+	values, err := dns.RecordSetValues(input)
+	if err != nil {
+		return rrset{}, err
+	}
+
+	set := rrset{
+		Name:       dns.RecordSetFQDN(domain, input.Name),
+		Type:       strings.ToUpper(strings.TrimSpace(input.Type)),
+		TTL:        input.TTL,
+		ChangeType: "REPLACE",
+		Records:    make([]rrsetRecord, 0, len(values)),
+	}
+	if set.TTL <= 0 {
+		set.TTL = defaultTTL
+	}
+	for _, value := range values {
+		set.Records = append(set.Records, rrsetRecord{Content: value})
+	}
+	if input.Comment != "" {
+		set.Comments = []rrsetComment{{Content: input.Comment}}
+	}
+
+	return set, nil
+}
+
+func toRecord(domain string, set rrset) dns.Record {
+	values := make([]string, 0, len(set.Records))
+	for _, record := range set.Records {
+		values = append(values, record.Content)
+	}
+
+	record := dns.RecordFromSet(domain, set.Name, set.Type, set.TTL, values)
+	if len(set.Comments) > 0 {
+		record.Comment = set.Comments[0].Content
+	}
+	return record
+}
+
+func zonePath(domain string) string {
+	return "/zones/" + url.PathEscape(dns.RecordSetFQDN(domain, "@"))
+}
@@ -0,0 +1,151 @@
+package powerdns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := []rrset{{
+		Name:    "example.com.",
+		Type:    "SOA",
+		TTL:     3600,
+		Records: []rrsetRecord{{Content: "ns1.example.com. hostmaster.example.com. 1 10800 3600 604800 3600"}},
+	}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, "test-key", r.Header.Get("X-API-Key")) {
+			http.Error(w, "missing key", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/servers/ns1/zones/example.com." {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "Could not find domain"})
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"name": "example.com.", "rrsets": stored}))
+		case http.MethodPatch:
+			var payload struct {
+				RRSets []rrset `json:"rrsets"`
+			}
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload)) {
+				return
+			}
+			for _, change := range payload.RRSets {
+				kept := stored[:0]
+				for _, set := range stored {
+					if set.Name != change.Name || set.Type != change.Type {
+						kept = append(kept, set)
+					}
+				}
+				stored = kept
+				if change.ChangeType == "REPLACE" {
+					change.ChangeType = ""
+					stored = append(stored, change)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(&dns.Credential{
+		Values: map[string]string{
+			"PDNS_API_KEY": "test-key",
+			"PDNS_API_URL": server.URL + "/",
+		},
+		Additional: map[string]string{"PDNS_SERVER_NAME": "ns1"},
+	})
+	require.NoError(t, err)
+
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "txt",
+		Name:    "_acme-challenge",
+		Content: "token-1\ntoken-2",
+		TTL:     120,
+		Comment: "managed by nginx-ui",
+	})
+	require.NoError(t, err)
+	require.Equal(t, dns.Record{
+		ID:      "_acme-challenge/TXT",
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: "\"token-1\"\n\"token-2\"",
+		TTL:     120,
+		Comment: "managed by nginx-ui",
+	}, created)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: "token-3",
+	})
+	require.ErrorContains(t, err, "already exists")
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "TXT"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{created}, records)
+
+	priority := 10
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", created.ID, dns.RecordInput{
+		Type:     "MX",
+		Name:     "@",
+		Content:  "mx.example.com.",
+		Priority: &priority,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "@/MX", updated.ID)
+	require.Equal(t, "mx.example.com.", updated.Content)
+	require.Equal(t, 10, *updated.Priority)
+	require.Equal(t, defaultTTL, updated.TTL)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "_acme-challenge"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "@"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", updated.ID))
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "MX"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	_, err = provider.ListRecords(t.Context(), "example.org", dns.RecordFilter{})
+	require.ErrorContains(t, err, "Could not find domain")
+}
+
+func TestNewProviderValidatesCredential(t *testing.T) {
+	t.Parallel()
+
+	_, err := newProvider(&dns.Credential{Values: map[string]string{"PDNS_API_KEY": "key"}})
+	require.Error(t, err)
+
+	created, err := newProvider(&dns.Credential{
+		Values:     map[string]string{"PDNS_API_KEY": "key", "PDNS_API_URL": "http://pdns:8081"},
+		Additional: map[string]string{"PDNS_API_VERSION": "0"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "http://pdns:8081/servers/localhost", created.(*provider).baseURL)
+}
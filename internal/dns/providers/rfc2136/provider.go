@@ -0,0 +1,368 @@
+package rfc2136
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultTTL     = 3600
+	tsigFudge      = 300
+)
+
+// provider manages records with RFC 2136 dynamic updates. Records are read
+// with a zone transfer (AXFR), so the server must allow transfers to the
+// same client or TSIG key that may update the zone. DNS itself has no record
+// IDs; record sets are exposed as one record each.
+type provider struct {
+	nameserver    string
+	tsigKey       string
+	tsigSecret    string
+	tsigAlgorithm string
+	timeout       time.Duration
+}
+
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
+func init() {
+	// lego renamed its rfc2136 provider to dnsupdate and keeps the old name as
+	// an alias; credentials may use either.
+	dns.RegisterProvider("dnsupdate", newProvider)
+	dns.RegisterProvider("rfc2136", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	nameserver, err := parseNameserver(lookup(cred, "NAMESERVER"))
+	if err != nil {
+		return nil, fmt.Errorf("rfc2136: %w", err)
+	}
+
+	p := &provider{
+		nameserver: nameserver,
+		timeout:    defaultTimeout,
+	}
+
+	key := lookup(cred, "TSIG_KEY")
+	secret := lookup(cred, "TSIG_SECRET")
+	if key != "" && secret != "" {
+		p.tsigKey = mdns.CanonicalName(key)
+		p.tsigSecret = secret
+		p.tsigAlgorithm = mdns.HmacSHA1
+		if algorithm := lookup(cred, "TSIG_ALGORITHM"); algorithm != "" {
+			p.tsigAlgorithm = mdns.Fqdn(strings.ToLower(algorithm))
+		}
+		switch p.tsigAlgorithm {
+		case mdns.HmacSHA1, mdns.HmacSHA224, mdns.HmacSHA256, mdns.HmacSHA384, mdns.HmacSHA512:
+		default:
+			return nil, fmt.Errorf("rfc2136: unsupported TSIG algorithm %s", p.tsigAlgorithm)
+		}
+	}
+
+	if value := lookup(cred, "DNS_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("rfc2136: invalid DNS timeout %q", value)
+		}
+		p.timeout = time.Duration(seconds) * time.Second
+	}
+
+	return p, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	rrs, err := p.transfer(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("rfc2136: list records: %w", err)
+	}
+
+	type setKey struct {
+		name       string
+		recordType string
+	}
+	sets := make(map[setKey][]mdns.RR)
+	keys := make([]setKey, 0)
+	for _, rr := range rrs {
+		header := rr.Header()
+		switch header.Rrtype {
+		case mdns.TypeRRSIG, mdns.TypeNSEC, mdns.TypeNSEC3, mdns.TypeNSEC3PARAM:
+			// DNSSEC records are maintained by the server.
+			continue
+		}
+
+		key := setKey{name: strings.ToLower(header.Name), recordType: mdns.TypeToString[header.Rrtype]}
+		if !dns.MatchRecordSet(domain, filter, key.name, key.recordType) {
+			continue
+		}
+		if _, ok := sets[key]; !ok {
+			keys = append(keys, key)
+		}
+		sets[key] = append(sets[key], rr)
+	}
+
+	result := make([]dns.Record, 0, len(keys))
+	for _, key := range keys {
+		set := sets[key]
+		values := make([]string, 0, len(set))
+		for _, rr := range set {
+			values = append(values, rrValue(rr))
+		}
+		result = append(result, dns.RecordFromSet(domain, key.name, key.recordType, int(set[0].Header().Ttl), values))
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	rrs, err := toRRs(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("rfc2136: create record: %w", err)
+	}
+
+	msg := new(mdns.Msg)
+	msg.SetUpdate(mdns.Fqdn(domain))
+	msg.RRsetNotUsed(rrs[:1])
+	msg.Insert(rrs)
+
+	if err := p.update(ctx, msg); err != nil {
+		return dns.Record{}, fmt.Errorf("rfc2136: create record: %w", err)
+	}
+
+	return toRecord(domain, rrs), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	previous, err := setHeader(domain, recordID)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("rfc2136: update record: %w", err)
+	}
+
+	rrs, err := toRRs(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("rfc2136: update record: %w", err)
+	}
+
+	// The removal and the insertion are applied atomically, in order.
+	msg := new(mdns.Msg)
+	msg.SetUpdate(mdns.Fqdn(domain))
+	msg.RemoveRRset([]mdns.RR{previous})
+	msg.Insert(rrs)
+
+	if err := p.update(ctx, msg); err != nil {
+		return dns.Record{}, fmt.Errorf("rfc2136: update record: %w", err)
+	}
+
+	return toRecord(domain, rrs), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	previous, err := setHeader(domain, recordID)
+	if err != nil {
+		return fmt.Errorf("rfc2136: delete record: %w", err)
+	}
+
+	msg := new(mdns.Msg)
+	msg.SetUpdate(mdns.Fqdn(domain))
+	msg.RemoveRRset([]mdns.RR{previous})
+
+	if err := p.update(ctx, msg); err != nil {
+		return fmt.Errorf("rfc2136: delete record: %w", err)
+	}
+
+	return nil
+}
+
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *provider) update(ctx context.Context, msg *mdns.Msg) error {
+	client := p.client("udp")
+	p.sign(msg)
+
+	reply, _, err := client.ExchangeContext(ctx, msg, p.nameserver)
+	if err == nil && reply.Truncated {
+		client = p.client("tcp")
+		reply, _, err = client.ExchangeContext(ctx, msg, p.nameserver)
+	}
+	if err != nil {
+		return err
+	}
+	if reply.Rcode != mdns.RcodeSuccess {
+		return fmt.Errorf("server responded with %s", mdns.RcodeToString[reply.Rcode])
+	}
+
+	return nil
+}
+
+func (p *provider) transfer(ctx context.Context, domain string) ([]mdns.RR, error) {
+	msg := new(mdns.Msg)
+	msg.SetAxfr(mdns.Fqdn(domain))
+	p.sign(msg)
+
+	transfer := &mdns.Transfer{
+		DialTimeout:  p.timeout,
+		ReadTimeout:  p.timeout,
+		WriteTimeout: p.timeout,
+	}
+	if p.tsigKey != "" {
+		transfer.TsigSecret = map[string]string{p.tsigKey: p.tsigSecret}
+	}
+
+	envelopes, err := transfer.In(msg, p.nameserver)
+	if err != nil {
+		return nil, err
+	}
+
+	rrs := make([]mdns.RR, 0)
+	soaSeen := false
+	for {
+		select {
+		case <-ctx.Done():
+			// Drain the transfer so its reader goroutine can finish.
+			go func() {
+				for range envelopes {
+				}
+			}()
+			return nil, ctx.Err()
+		case envelope, ok := <-envelopes:
+			if !ok {
+				return rrs, nil
+			}
+			if envelope.Error != nil {
+				return nil, envelope.Error
+			}
+			for _, rr := range envelope.RR {
+				// A transfer opens and closes with the zone's SOA record.
+				if rr.Header().Rrtype == mdns.TypeSOA {
+					if soaSeen {
+						continue
+					}
+					soaSeen = true
+				}
+				rrs = append(rrs, rr)
+			}
+		}
+	}
+}
+
+func (p *provider) client(network string) *mdns.Client {
+	client := &mdns.Client{Net: network, Timeout: p.timeout}
+	if p.tsigKey != "" {
+		client.TsigSecret = map[string]string{p.tsigKey: p.tsigSecret}
+	}
+	return client
+}
+
+func (p *provider) sign(msg *mdns.Msg) {
+	if p.tsigKey != "" {
+		msg.SetTsig(p.tsigKey, p.tsigAlgorithm, tsigFudge, time.Now().Unix())
+	}
+}
+
+func toRRs(domain string, input dns.RecordInput) ([]mdns.RR, error) {
+	values, err := dns.RecordSetValues(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := input.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	fqdn := dns.RecordSetFQDN(domain, input.Name)
+	recordType := strings.ToUpper(strings.TrimSpace(input.Type))
+	rrs := make([]mdns.RR, 0, len(values))
+	for _, value := range values {
+		rr, err := mdns.NewRR(fmt.Sprintf("%s %d IN %s %s", fqdn, ttl, recordType, value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s record value %q: %w", recordType, value, err)
+		}
+		if rr == nil {
+			return nil, fmt.Errorf("invalid %s record value %q", recordType, value)
+		}
+		rrs = append(rrs, rr)
+	}
+
+	return rrs, nil
+}
+
+func toRecord(domain string, rrs []mdns.RR) dns.Record {
+	values := make([]string, 0, len(rrs))
+	for _, rr := range rrs {
+		values = append(values, rrValue(rr))
+	}
+
+	header := rrs[0].Header()
+	return dns.RecordFromSet(domain, header.Name, mdns.TypeToString[header.Rrtype], int(header.Ttl), values)
+}
+
+// setHeader returns an RR naming the record set recordID refers to, as used
+// by RemoveRRset.
+func setHeader(domain, recordID string) (mdns.RR, error) {
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	rrtype, ok := mdns.StringToType[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unknown record type %s", recordType)
+	}
+
+	return &mdns.ANY{Hdr: mdns.RR_Header{
+		Name:   dns.RecordSetFQDN(domain, name),
+		Rrtype: rrtype,
+		Class:  mdns.ClassINET,
+	}}, nil
+}
+
+// rrValue returns the zone-file representation of the data of rr.
+func rrValue(rr mdns.RR) string {
+	return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+func parseNameserver(nameserver string) (string, error) {
+	nameserver = strings.TrimSpace(nameserver)
+	if nameserver == "" {
+		return "", errors.New("missing nameserver")
+	}
+
+	if ip := net.ParseIP(strings.Trim(nameserver, "[]")); ip != nil {
+		return net.JoinHostPort(ip.String(), "53"), nil
+	}
+	if _, _, err := net.SplitHostPort(nameserver); err == nil {
+		return nameserver, nil
+	}
+	if strings.Contains(nameserver, ":") {
+		return "", fmt.Errorf("invalid nameserver %q", nameserver)
+	}
+
+	return net.JoinHostPort(nameserver, "53"), nil
+}
+
+func lookup(cred *dns.Credential, suffix string) string {
+	for _, prefix := range []string{"DNSUPDATE_", "RFC2136_"} {
+		if value := strings.TrimSpace(cred.Values[prefix+suffix]); value != "" {
+			return value
+		}
+		if value := strings.TrimSpace(cred.Additional[prefix+suffix]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+package rfc2136
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	testKey    = "nginx-ui."
+	testSecret = "c2VjcmV0LXNlY3JldC1zZWNyZXQ="
+)
+
+// testServer is a minimal authoritative server for example.com that answers
+// TSIG-signed AXFR and UPDATE requests.
+type testServer struct {
+	mu      sync.Mutex
+	records []mdns.RR
+	updates int
+}
+
+func (s *testServer) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
+	reply := new(mdns.Msg)
+	reply.SetReply(r)
+
+	if r.IsTsig() == nil || w.TsigStatus() != nil {
+		reply.Rcode = mdns.RcodeRefused
+		_ = w.WriteMsg(reply)
+		return
+	}
+	defer func() {
+		reply.SetTsig(testKey, mdns.HmacSHA256, tsigFudge, time.Now().Unix())
+		_ = w.WriteMsg(reply)
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Opcode == mdns.OpcodeQuery && len(r.Question) == 1 && r.Question[0].Qtype == mdns.TypeAXFR:
+		soa := s.records[0]
+		reply.Answer = append(append([]mdns.RR{}, s.records...), soa)
+	case r.Opcode == mdns.OpcodeUpdate:
+		s.updates++
+		reply.Rcode = s.apply(r)
+	default:
+		reply.Rcode = mdns.RcodeNotImplemented
+	}
+}
+
+func (s *testServer) apply(r *mdns.Msg) int {
+	for _, prerequisite := range r.Answer {
+		header := prerequisite.Header()
+		if header.Class == mdns.ClassNONE && s.hasSet(header.Name, header.Rrtype) {
+			return mdns.RcodeYXRrset
+		}
+	}
+
+	records := append([]mdns.RR{}, s.records...)
+	for _, change := range r.Ns {
+		header := change.Header()
+		switch header.Class {
+		case mdns.ClassANY:
+			kept := records[:0]
+			for _, rr := range records {
+				if !strings.EqualFold(rr.Header().Name, header.Name) || rr.Header().Rrtype != header.Rrtype {
+					kept = append(kept, rr)
+				}
+			}
+			records = kept
+		case mdns.ClassINET:
+			records = append(records, change)
+		default:
+			return mdns.RcodeFormatError
+		}
+	}
+	s.records = records
+
+	return mdns.RcodeSuccess
+}
+
+func (s *testServer) hasSet(name string, rrtype uint16) bool {
+	for _, rr := range s.records {
+		if strings.EqualFold(rr.Header().Name, name) && rr.Header().Rrtype == rrtype {
+			return true
+		}
+	}
+	return false
+}
+
+func startTestServer(t *testing.T) (*testServer, string) {
+	t.Helper()
+
+	handler := &testServer{records: []mdns.RR{
+		mustRR(t, "example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 3600"),
+		mustRR(t, "example.com. 3600 IN NS ns1.example.com."),
+		mustRR(t, "www.example.com. 300 IN A 192.0.2.1"),
+		mustRR(t, "www.example.com. 300 IN A 192.0.2.2"),
+	}}
+
+	secrets := map[string]string{testKey: testSecret}
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tcp, err := net.Listen("tcp", udp.LocalAddr().String())
+	require.NoError(t, err)
+
+	servers := []*mdns.Server{
+		{PacketConn: udp, Handler: handler, TsigSecret: secrets, MsgAcceptFunc: acceptUpdates},
+		{Listener: tcp, Handler: handler, TsigSecret: secrets, MsgAcceptFunc: acceptUpdates},
+	}
+	for _, server := range servers {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go func() {
+			_ = server.ActivateAndServe()
+		}()
+		<-started
+		t.Cleanup(func() {
+			_ = server.Shutdown()
+		})
+	}
+
+	return handler, udp.LocalAddr().String()
+}
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server, address := startTestServer(t)
+	provider, err := newProvider(&dns.Credential{
+		Values: map[string]string{"DNSUPDATE_NAMESERVER": address},
+		Additional: map[string]string{
+			"DNSUPDATE_TSIG_KEY":       "nginx-ui",
+			"DNSUPDATE_TSIG_SECRET":    testSecret,
+			"DNSUPDATE_TSIG_ALGORITHM": "hmac-sha256",
+		},
+	})
+	require.NoError(t, err)
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{{
+		ID:      "www/A",
+		Type:    "A",
+		Name:    "www",
+		Content: "192.0.2.1\n192.0.2.2",
+		TTL:     300,
+	}}, records)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, "@/SOA", records[0].ID)
+
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: "token value",
+		TTL:     120,
+	})
+	require.NoError(t, err)
+	require.Equal(t, dns.Record{
+		ID:      "_acme-challenge/TXT",
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: `"token value"`,
+		TTL:     120,
+	}, created)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "TXT", Name: "_acme-challenge", Content: "again"})
+	require.ErrorContains(t, err, "YXRRSET")
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "txt"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{created}, records)
+
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", "www/A", dns.RecordInput{
+		Type:    "AAAA",
+		Name:    "www",
+		Content: "2001:db8::1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www/AAAA", updated.ID)
+	require.Equal(t, defaultTTL, updated.TTL)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{updated}, records)
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", created.ID))
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "TXT"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "A", Name: "bad", Content: "not-an-ip"})
+	require.Error(t, err)
+
+	server.mu.Lock()
+	assert.Equal(t, 4, server.updates)
+	server.mu.Unlock()
+}
+
+func TestProviderRequiresValidTSIG(t *testing.T) {
+	t.Parallel()
+
+	_, address := startTestServer(t)
+	provider, err := newProvider(&dns.Credential{
+		Values: map[string]string{
+			"RFC2136_NAMESERVER":     address,
+			"RFC2136_TSIG_KEY":       testKey,
+			"RFC2136_TSIG_SECRET":    "d3Jvbmc=",
+			"RFC2136_TSIG_ALGORITHM": mdns.HmacSHA256,
+			"RFC2136_DNS_TIMEOUT":    "2",
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "A", Name: "www", Content: "192.0.2.3"})
+	require.Error(t, err)
+}
+
+func TestNewProviderConfiguration(t *testing.T) {
+	t.Parallel()
+
+	created, err := newProvider(&dns.Credential{Values: map[string]string{"DNSUPDATE_NAMESERVER": "ns1.example.com"}})
+	require.NoError(t, err)
+	require.Equal(t, "ns1.example.com:53", created.(*provider).nameserver)
+	require.Empty(t, created.(*provider).tsigKey)
+
+	created, err = newProvider(&dns.Credential{Values: map[string]string{"DNSUPDATE_NAMESERVER": "2001:db8::53"}})
+	require.NoError(t, err)
+	require.Equal(t, "[2001:db8::53]:53", created.(*provider).nameserver)
+
+	_, err = newProvider(&dns.Credential{Values: map[string]string{}})
+	require.Error(t, err)
+
+	_, err = newProvider(&dns.Credential{Values: map[string]string{
+		"DNSUPDATE_NAMESERVER":     "127.0.0.1",
+		"DNSUPDATE_TSIG_KEY":       testKey,
+		"DNSUPDATE_TSIG_SECRET":    testSecret,
+		"DNSUPDATE_TSIG_ALGORITHM": "hmac-md5",
+	}})
+	require.ErrorContains(t, err, "unsupported TSIG algorithm")
+}
+
+// acceptUpdates extends the default filter, which rejects UPDATE messages.
+func acceptUpdates(header mdns.Header) mdns.MsgAcceptAction {
+	if int(header.Bits>>11)&0xF == mdns.OpcodeUpdate {
+		return mdns.MsgAccept
+	}
+	return mdns.DefaultMsgAcceptFunc(header)
+}
+
+func mustRR(t *testing.T, value string) mdns.RR {
+	t.Helper()
+	rr, err := mdns.NewRR(value)
+	require.NoError(t, err)
+	return rr
+}
@@ -0,0 +1,352 @@
+package route53
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	awsroute53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+const (
+	// Route 53 is a global service, but the SDK still needs a region to sign
+	// requests with.
+	defaultRegion  = "us-east-1"
+	defaultTimeout = 10 * time.Second
+	defaultTTL     = 300
+)
+
+// provider manages Route 53 hosted zones. Route 53 works on record sets, which
+// are exposed as one record each. Alias records and record sets bound to a
+// routing policy (those with a set identifier) have no equivalent in
+// dns.Record and are left out of listings.
+type provider struct {
+	client       *awsroute53.Client
+	hostedZoneID string
+	privateZone  bool
+	zoneCache    sync.Map
+}
+
+var (
+	_ dns.Provider          = (*provider)(nil)
+	_ dns.RecordSetProvider = (*provider)(nil)
+)
+
+func init() {
+	dns.RegisterProvider("route53", newProvider)
+}
+
+func newProvider(cred *dns.Credential) (dns.Provider, error) {
+	region := firstNonEmpty(cred.Values["AWS_REGION"], defaultRegion)
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(region),
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(defaultTimeout)),
+	}
+
+	accessKeyID := strings.TrimSpace(cred.Values["AWS_ACCESS_KEY_ID"])
+	secretAccessKey := strings.TrimSpace(cred.Values["AWS_SECRET_ACCESS_KEY"])
+	switch {
+	case accessKeyID != "" && secretAccessKey != "":
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, strings.TrimSpace(cred.Values["AWS_SESSION_TOKEN"])),
+		))
+	case accessKeyID != "" || secretAccessKey != "":
+		return nil, fmt.Errorf("route53: both access key id and secret access key are required")
+	}
+
+	if profile := strings.TrimSpace(cred.Values["AWS_PROFILE"]); profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(profile))
+	}
+	if file := strings.TrimSpace(cred.Additional["AWS_SHARED_CREDENTIALS_FILE"]); file != "" {
+		opts = append(opts, awsconfig.WithSharedCredentialsFiles([]string{file}))
+	}
+	if value := strings.TrimSpace(cred.Additional["AWS_MAX_RETRIES"]); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("route53: invalid AWS_MAX_RETRIES %q", value)
+		}
+		opts = append(opts, awsconfig.WithRetryMaxAttempts(retries+1))
+	}
+	if endpoint := strings.TrimSpace(cred.Additional["AWS_ENDPOINT_URL"]); endpoint != "" {
+		opts = append(opts, awsconfig.WithBaseEndpoint(endpoint))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("route53: load config: %w", err)
+	}
+
+	if roleARN := strings.TrimSpace(cred.Values["AWS_ASSUME_ROLE_ARN"]); roleARN != "" {
+		externalID := strings.TrimSpace(cred.Values["AWS_EXTERNAL_ID"])
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleARN,
+			func(o *stscreds.AssumeRoleOptions) {
+				if externalID != "" {
+					o.ExternalID = aws.String(externalID)
+				}
+			},
+		))
+	}
+
+	return &provider{
+		client:       awsroute53.NewFromConfig(cfg),
+		hostedZoneID: strings.TrimSpace(cred.Values["AWS_HOSTED_ZONE_ID"]),
+		privateZone:  strings.EqualFold(strings.TrimSpace(cred.Additional["AWS_PRIVATE_ZONE"]), "true"),
+	}, nil
+}
+
+func (p *provider) ListRecords(ctx context.Context, domain string, filter dns.RecordFilter) ([]dns.Record, error) {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &awsroute53.ListResourceRecordSetsInput{HostedZoneId: aws.String(zoneID)}
+	if name := strings.TrimSpace(filter.Name); name != "" {
+		// Record sets are returned in name order, so start at the filtered name.
+		input.StartRecordName = aws.String(dns.RecordSetFQDN(domain, name))
+	}
+
+	result := make([]dns.Record, 0)
+	pager := awsroute53.NewListResourceRecordSetsPaginator(p.client, input)
+	for pager.HasMorePages() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("route53: list records: %w", err)
+		}
+
+		for _, set := range page.ResourceRecordSets {
+			name := unescapeName(aws.ToString(set.Name))
+			if input.StartRecordName != nil && !strings.EqualFold(name, aws.ToString(input.StartRecordName)) {
+				return result, nil
+			}
+			if set.AliasTarget != nil || set.SetIdentifier != nil {
+				continue
+			}
+			if !dns.MatchRecordSet(domain, filter, name, string(set.Type)) {
+				continue
+			}
+			result = append(result, toRecord(domain, set))
+		}
+	}
+
+	return result, nil
+}
+
+func (p *provider) CreateRecord(ctx context.Context, domain string, input dns.RecordInput) (dns.Record, error) {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return dns.Record{}, err
+	}
+
+	set, err := toRecordSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("route53: create record: %w", err)
+	}
+
+	// CREATE fails when the record set already exists, unlike UPSERT.
+	if err := p.change(ctx, zoneID, types.Change{Action: types.ChangeActionCreate, ResourceRecordSet: &set}); err != nil {
+		return dns.Record{}, fmt.Errorf("route53: create record: %w", err)
+	}
+
+	return toRecord(domain, set), nil
+}
+
+func (p *provider) UpdateRecord(ctx context.Context, domain string, recordID string, input dns.RecordInput) (dns.Record, error) {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return dns.Record{}, err
+	}
+
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("route53: update record: %w", err)
+	}
+
+	set, err := toRecordSet(domain, input)
+	if err != nil {
+		return dns.Record{}, fmt.Errorf("route53: update record: %w", err)
+	}
+
+	changes := []types.Change{{Action: types.ChangeActionUpsert, ResourceRecordSet: &set}}
+	if previous := dns.RecordSetFQDN(domain, name); !strings.EqualFold(previous, aws.ToString(set.Name)) || recordType != string(set.Type) {
+		existing, err := p.findRecordSet(ctx, zoneID, previous, recordType)
+		if err != nil {
+			return dns.Record{}, fmt.Errorf("route53: update record: %w", err)
+		}
+		changes = append(changes, types.Change{Action: types.ChangeActionDelete, ResourceRecordSet: existing})
+	}
+
+	if err := p.change(ctx, zoneID, changes...); err != nil {
+		return dns.Record{}, fmt.Errorf("route53: update record: %w", err)
+	}
+
+	return toRecord(domain, set), nil
+}
+
+func (p *provider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	name, recordType, err := dns.ParseRecordSetID(recordID)
+	if err != nil {
+		return fmt.Errorf("route53: delete record: %w", err)
+	}
+
+	// A deletion must repeat the record set exactly as it is stored.
+	existing, err := p.findRecordSet(ctx, zoneID, dns.RecordSetFQDN(domain, name), recordType)
+	if err != nil {
+		return fmt.Errorf("route53: delete record: %w", err)
+	}
+
+	if err := p.change(ctx, zoneID, types.Change{Action: types.ChangeActionDelete, ResourceRecordSet: existing}); err != nil {
+		return fmt.Errorf("route53: delete record: %w", err)
+	}
+
+	return nil
+}
+
+// ManagesRecordSets reports that each record is a whole record set.
+func (p *provider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *provider) change(ctx context.Context, zoneID string, changes ...types.Change) error {
+	_, err := p.client.ChangeResourceRecordSets(ctx, &awsroute53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+		ChangeBatch: &types.ChangeBatch{
+			Comment: aws.String("managed by nginx-ui"),
+			Changes: changes,
+		},
+	})
+	return err
+}
+
+func (p *provider) findRecordSet(ctx context.Context, zoneID, fqdn, recordType string) (*types.ResourceRecordSet, error) {
+	output, err := p.client.ListResourceRecordSets(ctx, &awsroute53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zoneID),
+		StartRecordName: aws.String(fqdn),
+		StartRecordType: types.RRType(recordType),
+		MaxItems:        aws.Int32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range output.ResourceRecordSets {
+		if strings.EqualFold(unescapeName(aws.ToString(set.Name)), fqdn) && string(set.Type) == recordType {
+			return &set, nil
+		}
+	}
+
+	return nil, fmt.Errorf("record set %s %s not found", fqdn, recordType)
+}
+
+func (p *provider) zoneID(ctx context.Context, domain string) (string, error) {
+	if p.hostedZoneID != "" {
+		return p.hostedZoneID, nil
+	}
+
+	fqdn := dns.RecordSetFQDN(domain, "@")
+	if zoneID, ok := p.zoneCache.Load(fqdn); ok {
+		return zoneID.(string), nil
+	}
+
+	output, err := p.client.ListHostedZonesByName(ctx, &awsroute53.ListHostedZonesByNameInput{
+		DNSName: aws.String(fqdn),
+	})
+	if err != nil {
+		return "", fmt.Errorf("route53: resolve hosted zone: %w", err)
+	}
+
+	for _, zone := range output.HostedZones {
+		if !strings.EqualFold(aws.ToString(zone.Name), fqdn) {
+			continue
+		}
+		if zone.Config != nil && zone.Config.PrivateZone != p.privateZone {
+			continue
+		}
+		zoneID := strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/")
+		p.zoneCache.Store(fqdn, zoneID)
+		return zoneID, nil
+	}
+
+	return "", errors.New("route53: resolve hosted zone: not found")
+}
+
+func toRecordSet(domain string, input dns.RecordInput) (types.ResourceRecordSet, error) {
+	values, err := dns.RecordSetValues(input)
+	if err != nil {
+		return types.ResourceRecordSet{}, err
+	}
+
+	ttl := int64(input.TTL)
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	set := types.ResourceRecordSet{
+		Name:            aws.String(dns.RecordSetFQDN(domain, input.Name)),
+		Type:            types.RRType(strings.ToUpper(strings.TrimSpace(input.Type))),
+		TTL:             aws.Int64(ttl),
+		ResourceRecords: make([]types.ResourceRecord, 0, len(values)),
+	}
+	for _, value := range values {
+		set.ResourceRecords = append(set.ResourceRecords, types.ResourceRecord{Value: aws.String(value)})
+	}
+
+	return set, nil
+}
+
+func toRecord(domain string, set types.ResourceRecordSet) dns.Record {
+	values := make([]string, 0, len(set.ResourceRecords))
+	for _, record := range set.ResourceRecords {
+		values = append(values, aws.ToString(record.Value))
+	}
+
+	return dns.RecordFromSet(domain, unescapeName(aws.ToString(set.Name)), string(set.Type), int(aws.ToInt64(set.TTL)), values)
+}
+
+// unescapeName reverts the octal escaping Route 53 applies to names, most
+// commonly "\052" for the wildcard label.
+func unescapeName(name string) string {
+	if !strings.Contains(name, `\`) {
+		return name
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+3 < len(name) {
+			if value, err := strconv.ParseUint(name[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(name[i])
+	}
+	return builder.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+)
+
+type fakeRecordSet struct {
+	Name            string   `xml:"Name"`
+	Type            string   `xml:"Type"`
+	SetIdentifier   string   `xml:"SetIdentifier,omitempty"`
+	TTL             int64    `xml:"TTL,omitempty"`
+	ResourceRecords []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+type fakeChange struct {
+	Action            string        `xml:"Action"`
+	ResourceRecordSet fakeRecordSet `xml:"ResourceRecordSet"`
+}
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string]fakeRecordSet{
+		"example.com./NS":     {Name: "example.com.", Type: "NS", TTL: 172800, ResourceRecords: []string{"ns-1.awsdns-00.com."}},
+		`\052.example.com./A`: {Name: `\052.example.com.`, Type: "A", TTL: 60, ResourceRecords: []string{"192.0.2.99"}},
+		"geo.example.com./A":  {Name: "geo.example.com.", Type: "A", SetIdentifier: "eu", TTL: 60, ResourceRecords: []string{"192.0.2.50"}},
+	}
+	zoneLookups := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Contains(t, r.Header.Get("Authorization"), "Credential=AKIDEXAMPLE/") {
+			http.Error(w, "unsigned request", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case r.Method == http.MethodGet && path == "/2013-04-01/hostedzonesbyname":
+			zoneLookups++
+			assert.Equal(t, "example.com.", r.URL.Query().Get("dnsname"))
+			fmt.Fprint(w, `<ListHostedZonesByNameResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><HostedZones>`+
+				`<HostedZone><Id>/hostedzone/ZPRIVATE</Id><Name>example.com.</Name><CallerReference>a</CallerReference><Config><PrivateZone>true</PrivateZone></Config></HostedZone>`+
+				`<HostedZone><Id>/hostedzone/ZPUBLIC</Id><Name>example.com.</Name><CallerReference>b</CallerReference><Config><PrivateZone>false</PrivateZone></Config></HostedZone>`+
+				`</HostedZones><IsTruncated>false</IsTruncated><MaxItems>100</MaxItems></ListHostedZonesByNameResponse>`)
+		case r.Method == http.MethodGet && path == "/2013-04-01/hostedzone/ZPUBLIC/rrset":
+			keys := make([]string, 0, len(stored))
+			for key := range stored {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			start := r.URL.Query().Get("name")
+			startType := r.URL.Query().Get("type")
+			sets := make([]fakeRecordSet, 0, len(keys))
+			for _, key := range keys {
+				set := stored[key]
+				if start != "" && set.Name < start {
+					continue
+				}
+				if startType != "" && set.Name == start && set.Type < startType {
+					continue
+				}
+				sets = append(sets, set)
+			}
+			if r.URL.Query().Get("maxitems") == "1" && len(sets) > 1 {
+				sets = sets[:1]
+			}
+
+			data, err := xml.Marshal(struct {
+				XMLName            xml.Name        `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ListResourceRecordSetsResponse"`
+				ResourceRecordSets []fakeRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+				IsTruncated        bool            `xml:"IsTruncated"`
+				MaxItems           string          `xml:"MaxItems"`
+			}{ResourceRecordSets: sets, MaxItems: "300"})
+			assert.NoError(t, err)
+			_, _ = w.Write(data)
+		case r.Method == http.MethodPost && path == "/2013-04-01/hostedzone/ZPUBLIC/rrset":
+			var request struct {
+				Changes []fakeChange `xml:"ChangeBatch>Changes>Change"`
+			}
+			if !assert.NoError(t, xml.NewDecoder(r.Body).Decode(&request)) {
+				return
+			}
+			for _, change := range request.Changes {
+				key := change.ResourceRecordSet.Name + "/" + change.ResourceRecordSet.Type
+				existing, exists := stored[key]
+				switch change.Action {
+				case "CREATE":
+					if exists {
+						w.WriteHeader(http.StatusBadRequest)
+						fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidChangeBatch</Code><Message>Tried to create resource record set but it already exists</Message></Error></ErrorResponse>`)
+						return
+					}
+					stored[key] = change.ResourceRecordSet
+				case "UPSERT":
+					stored[key] = change.ResourceRecordSet
+				case "DELETE":
+					if !assert.True(t, exists) || !assert.Equal(t, existing, change.ResourceRecordSet) {
+						http.Error(w, "delete mismatch", http.StatusBadRequest)
+						return
+					}
+					delete(stored, key)
+				}
+			}
+			fmt.Fprint(w, `<ChangeResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status><SubmittedAt>2026-01-01T00:00:00Z</SubmittedAt></ChangeInfo></ChangeResourceRecordSetsResponse>`)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := newProvider(&dns.Credential{
+		Values: map[string]string{
+			"AWS_ACCESS_KEY_ID":     "AKIDEXAMPLE",
+			"AWS_SECRET_ACCESS_KEY": "secret",
+		},
+		Additional: map[string]string{
+			"AWS_ENDPOINT_URL": server.URL,
+			"AWS_MAX_RETRIES":  "0",
+		},
+	})
+	require.NoError(t, err)
+
+	created, err := provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: "token",
+		TTL:     60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, dns.Record{
+		ID:      "_acme-challenge/TXT",
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: `"token"`,
+		TTL:     60,
+	}, created)
+
+	_, err = provider.CreateRecord(t.Context(), "example.com", dns.RecordInput{Type: "TXT", Name: "_acme-challenge", Content: "again"})
+	require.ErrorContains(t, err, "already exists")
+
+	records, err := provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, "*", records[0].Name)
+	require.Equal(t, created, records[1])
+	require.Equal(t, "@/NS", records[2].ID)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "_acme-challenge"})
+	require.NoError(t, err)
+	require.Equal(t, []dns.Record{created}, records)
+
+	updated, err := provider.UpdateRecord(t.Context(), "example.com", created.ID, dns.RecordInput{
+		Type:    "TXT",
+		Name:    "_acme-challenge",
+		Content: "token-2",
+		TTL:     120,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `"token-2"`, updated.Content)
+
+	moved, err := provider.UpdateRecord(t.Context(), "example.com", updated.ID, dns.RecordInput{
+		Type:    "CNAME",
+		Name:    "www",
+		Content: "example.com.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www/CNAME", moved.ID)
+	require.Equal(t, defaultTTL, moved.TTL)
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Type: "txt"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	require.NoError(t, provider.DeleteRecord(t.Context(), "example.com", moved.ID))
+	require.ErrorContains(t, provider.DeleteRecord(t.Context(), "example.com", moved.ID), "not found")
+
+	records, err = provider.ListRecords(t.Context(), "example.com", dns.RecordFilter{Name: "www"})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	mu.Lock()
+	require.Equal(t, 1, zoneLookups)
+	mu.Unlock()
+}
+
+func TestNewProviderRejectsPartialKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := newProvider(&dns.Credential{Values: map[string]string{"AWS_ACCESS_KEY_ID": "AKIDEXAMPLE"}})
+	require.Error(t, err)
+}
+
+func TestUnescapeName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "*.example.com.", unescapeName(`\052.example.com.`))
+	require.Equal(t, "www.example.com.", unescapeName("www.example.com."))
+	require.Equal(t, `bad\05`, unescapeName(`bad\05`))
+}
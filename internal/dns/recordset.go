@@ -0,0 +1,212 @@
+package dns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Several providers manage record sets (all values sharing a name and type)
+// instead of individual records. Such providers expose one Record per set:
+// the values are joined by newlines in Content and the set is identified by
+// its relative name and type.
+
+// RecordSetID returns the identifier of the record set with the given
+// relative name and type.
+func RecordSetID(name, recordType string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "@"
+	}
+	return name + "/" + strings.ToUpper(strings.TrimSpace(recordType))
+}
+
+// ParseRecordSetID splits an identifier built by RecordSetID.
+func ParseRecordSetID(id string) (name, recordType string, err error) {
+	index := strings.LastIndex(id, "/")
+	if index <= 0 || index == len(id)-1 {
+		return "", "", fmt.Errorf("invalid record set id %q", id)
+	}
+	return id[:index], strings.ToUpper(id[index+1:]), nil
+}
+
+// RecordSetFQDN returns the fully qualified, dot-terminated name of a record
+// relative to domain.
+func RecordSetFQDN(domain, name string) string {
+	zone := normalizeRecordSetName(domain)
+	relative := normalizeRecordSetName(name)
+	if relative == "" || relative == "@" || relative == zone {
+		return zone + "."
+	}
+	if strings.HasSuffix(relative, "."+zone) {
+		return relative + "."
+	}
+	return relative + "." + zone + "."
+}
+
+// RecordSetRelativeName returns name relative to domain, "@" for the apex.
+func RecordSetRelativeName(name, domain string) string {
+	recordName := normalizeRecordSetName(name)
+	zoneName := normalizeRecordSetName(domain)
+	if recordName == "" || recordName == "@" || recordName == zoneName {
+		return "@"
+	}
+	if zoneName != "" && strings.HasSuffix(recordName, "."+zoneName) {
+		return strings.TrimSuffix(recordName, "."+zoneName)
+	}
+	return recordName
+}
+
+// RecordSetValues converts the content of input into the zone-file values of
+// a record set. TXT values are quoted, and MX and SRV values are prefixed with
+// the priority and weight of input unless they already carry them.
+func RecordSetValues(input RecordInput) ([]string, error) {
+	values := splitRecordSetValues(input.Content)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("record value is required")
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(input.Type)) {
+	case "TXT":
+		for i, value := range values {
+			if !isQuotedRecordSetValue(value) {
+				values[i] = strconv.Quote(value)
+			}
+		}
+	case "MX":
+		for i, value := range values {
+			if hasNumericPrefixes(value, 1) {
+				continue
+			}
+			if input.Priority == nil {
+				return nil, fmt.Errorf("MX records require a priority")
+			}
+			values[i] = fmt.Sprintf("%d %s", *input.Priority, value)
+		}
+	case "SRV":
+		for i, value := range values {
+			if hasNumericPrefixes(value, 3) {
+				continue
+			}
+			if input.Priority == nil || input.Weight == nil {
+				return nil, fmt.Errorf("SRV records require priority and weight")
+			}
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("SRV record value must contain a port and target")
+			}
+			if _, err := strconv.Atoi(fields[0]); err != nil {
+				return nil, fmt.Errorf("SRV record value must start with a numeric port")
+			}
+			values[i] = fmt.Sprintf("%d %d %s", *input.Priority, *input.Weight, value)
+		}
+	}
+
+	return values, nil
+}
+
+// RecordFromSet builds the Record exposing a record set. The priority and
+// weight shared by all MX or SRV values are lifted into the record.
+func RecordFromSet(domain, name, recordType string, ttl int, values []string) Record {
+	recordType = strings.ToUpper(strings.TrimSpace(recordType))
+	relative := RecordSetRelativeName(name, domain)
+	record := Record{
+		ID:   RecordSetID(relative, recordType),
+		Type: recordType,
+		Name: relative,
+		TTL:  ttl,
+	}
+
+	trimmed := splitRecordSetValues(strings.Join(values, "\n"))
+	record.Content = strings.Join(trimmed, "\n")
+
+	switch recordType {
+	case "MX":
+		if content, prefixes, ok := stripCommonNumericPrefixes(trimmed, 1); ok {
+			record.Content = strings.Join(content, "\n")
+			record.Priority = &prefixes[0]
+		}
+	case "SRV":
+		if content, prefixes, ok := stripCommonNumericPrefixes(trimmed, 2); ok {
+			record.Content = strings.Join(content, "\n")
+			record.Priority = &prefixes[0]
+			record.Weight = &prefixes[1]
+		}
+	}
+
+	return record
+}
+
+// MatchRecordSet reports whether a record set passes filter.
+func MatchRecordSet(domain string, filter RecordFilter, name, recordType string) bool {
+	if filterType := strings.TrimSpace(filter.Type); filterType != "" && !strings.EqualFold(filterType, recordType) {
+		return false
+	}
+	if filterName := strings.TrimSpace(filter.Name); filterName != "" &&
+		RecordSetRelativeName(filterName, domain) != RecordSetRelativeName(name, domain) {
+		return false
+	}
+	return true
+}
+
+func splitRecordSetValues(content string) []string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	values := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+func stripCommonNumericPrefixes(values []string, count int) ([]string, []int, bool) {
+	if len(values) == 0 {
+		return nil, nil, false
+	}
+
+	prefixes := make([]int, count)
+	content := make([]string, 0, len(values))
+	for index, value := range values {
+		fields := strings.Fields(value)
+		if len(fields) <= count {
+			return nil, nil, false
+		}
+		for prefixIndex := 0; prefixIndex < count; prefixIndex++ {
+			parsed, err := strconv.Atoi(fields[prefixIndex])
+			if err != nil {
+				return nil, nil, false
+			}
+			if index == 0 {
+				prefixes[prefixIndex] = parsed
+			} else if prefixes[prefixIndex] != parsed {
+				return nil, nil, false
+			}
+		}
+		content = append(content, strings.Join(fields[count:], " "))
+	}
+
+	return content, prefixes, true
+}
+
+func hasNumericPrefixes(value string, count int) bool {
+	fields := strings.Fields(value)
+	if len(fields) <= count {
+		return false
+	}
+	for i := 0; i < count; i++ {
+		if _, err := strconv.Atoi(fields[i]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isQuotedRecordSetValue(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"'
+}
+
+func normalizeRecordSetName(value string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(value)), ".")
+}
@@ -0,0 +1,69 @@
+package dns_test
+
+import (
+	"testing"
+
+	dnsSvc "github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordSetValuesRoundTrip(t *testing.T) {
+	priority := 10
+	weight := 5
+
+	values, err := dnsSvc.RecordSetValues(dnsSvc.RecordInput{
+		Type:     "mx",
+		Content:  "mx1.example.com.\nmx2.example.com.",
+		Priority: &priority,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10 mx1.example.com.", "10 mx2.example.com."}, values)
+
+	record := dnsSvc.RecordFromSet("example.com", "example.com.", "MX", 300, values)
+	require.Equal(t, "@/MX", record.ID)
+	require.Equal(t, "@", record.Name)
+	require.Equal(t, "mx1.example.com.\nmx2.example.com.", record.Content)
+	require.Equal(t, 10, *record.Priority)
+
+	values, err = dnsSvc.RecordSetValues(dnsSvc.RecordInput{
+		Type:     "SRV",
+		Content:  "443 sip.example.com.",
+		Priority: &priority,
+		Weight:   &weight,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10 5 443 sip.example.com."}, values)
+
+	record = dnsSvc.RecordFromSet("example.com", "_sip._tcp.example.com", "srv", 60, values)
+	require.Equal(t, "_sip._tcp/SRV", record.ID)
+	require.Equal(t, "443 sip.example.com.", record.Content)
+	require.Equal(t, 5, *record.Weight)
+
+	values, err = dnsSvc.RecordSetValues(dnsSvc.RecordInput{Type: "TXT", Content: "v=spf1 -all\n\"already quoted\""})
+	require.NoError(t, err)
+	require.Equal(t, []string{`"v=spf1 -all"`, `"already quoted"`}, values)
+
+	_, err = dnsSvc.RecordSetValues(dnsSvc.RecordInput{Type: "MX", Content: "mx.example.com."})
+	require.Error(t, err)
+	_, err = dnsSvc.RecordSetValues(dnsSvc.RecordInput{Type: "A", Content: " \n "})
+	require.Error(t, err)
+}
+
+func TestRecordSetNames(t *testing.T) {
+	require.Equal(t, "www.example.com.", dnsSvc.RecordSetFQDN("Example.com.", "WWW"))
+	require.Equal(t, "example.com.", dnsSvc.RecordSetFQDN("example.com", "@"))
+	require.Equal(t, "www.example.com.", dnsSvc.RecordSetFQDN("example.com", "www.example.com"))
+	require.Equal(t, "www", dnsSvc.RecordSetRelativeName("www.example.com.", "example.com"))
+	require.Equal(t, "@", dnsSvc.RecordSetRelativeName("example.com.", "example.com"))
+
+	name, recordType, err := dnsSvc.ParseRecordSetID("_acme-challenge.www/txt")
+	require.NoError(t, err)
+	require.Equal(t, "_acme-challenge.www", name)
+	require.Equal(t, "TXT", recordType)
+
+	_, _, err = dnsSvc.ParseRecordSetID("www")
+	require.Error(t, err)
+
+	require.True(t, dnsSvc.MatchRecordSet("example.com", dnsSvc.RecordFilter{Type: "a", Name: "www"}, "www.example.com.", "A"))
+	require.False(t, dnsSvc.MatchRecordSet("example.com", dnsSvc.RecordFilter{Name: "@"}, "www.example.com.", "A"))
+}
@@ -14,6 +14,7 @@ import (
 	dnsSvc "github.com/0xJacky/Nginx-UI/internal/dns"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
 	"github.com/stretchr/testify/require"
 	"github.com/uozi-tech/cosy"
 	"gorm.io/driver/sqlite"
@@ -829,6 +830,148 @@ func TestRunDDNSUpdateTracksFamilyFailureTimestamps(t *testing.T) {
 	require.NotNil(t, loaded.DDNSConfig.IPv6FailedSince, "ipv6 failed, timestamp should be set")
 }
 
+func TestTriggerDDNSWebhookUpdatesRecords(t *testing.T) {
+	registerMockProvider()
+	setMockRecords([]dnsSvc.Record{
+		{ID: "a-record", Type: "A", Name: "home", Content: "198.51.100.10", TTL: 600},
+	})
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+
+	cred := createCredential(t, q)
+	domain, err := service.CreateDomain(ctx, dnsSvc.DomainInput{
+		Domain:          "example.com",
+		DnsCredentialID: cred.ID,
+	})
+	require.NoError(t, err)
+
+	input := dnsSvc.DDNSUpdateInput{
+		Enabled:         true,
+		IntervalSeconds: dnsSvc.DefaultDDNSInterval(),
+		IPVersion:       "ipv4",
+		RecordIDs:       []string{"a-record"},
+		Source:          &model.DDNSIPSource{Type: dnsSvc.DDNSSourceWebhook},
+		WebhookEnabled:  true,
+	}
+	result, err := service.UpdateDDNSConfigWithDetails(ctx, domain.ID, input)
+	require.NoError(t, err)
+	require.True(t, result.WebhookTokenIssued)
+	cfg := result.Config
+	require.NotEmpty(t, cfg.WebhookToken)
+
+	// Saving again keeps the token without handing it out.
+	result, err = service.UpdateDDNSConfigWithDetails(ctx, domain.ID, input)
+	require.NoError(t, err)
+	require.False(t, result.WebhookTokenIssued)
+	require.Equal(t, cfg.WebhookToken, result.Config.WebhookToken)
+
+	report := dnsSvc.DDNSWebhookReport{IPv4: "198.51.100.50"}
+	require.ErrorIs(t, service.TriggerDDNSWebhook(ctx, domain.ID, "wrong", report), dnsSvc.ErrDDNSWebhookUnauthorized)
+	require.ErrorIs(t, service.TriggerDDNSWebhook(ctx, domain.ID+1, cfg.WebhookToken, report), dnsSvc.ErrDDNSWebhookUnauthorized)
+	require.Empty(t, getMockUpdatedRecordIDs())
+
+	require.NoError(t, service.TriggerDDNSWebhook(ctx, domain.ID, cfg.WebhookToken, report))
+	require.Equal(t, []string{"a-record"}, getMockUpdatedRecordIDs())
+	require.Equal(t, "198.51.100.50", getMockUpdatedRecords()[0].Content)
+
+	var persisted model.DnsDomain
+	require.NoError(t, model.UseDB().WithContext(ctx).First(&persisted, domain.ID).Error)
+	require.Equal(t, "198.51.100.50", persisted.DDNSConfig.WebhookIPv4)
+	require.NotNil(t, persisted.DDNSConfig.WebhookAt)
+
+	rotated, err := service.RotateDDNSWebhookToken(ctx, domain.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, cfg.WebhookToken, rotated.WebhookToken)
+	require.ErrorIs(t, service.TriggerDDNSWebhook(ctx, domain.ID, cfg.WebhookToken, report), dnsSvc.ErrDDNSWebhookUnauthorized)
+}
+
+func TestRunDDNSUpdateUsesPerTargetSources(t *testing.T) {
+	registerMockProvider()
+	setMockRecords([]dnsSvc.Record{
+		{ID: "home-a", Type: "A", Name: "home", Content: "198.51.100.10", TTL: 600},
+		{ID: "office-a", Type: "A", Name: "office", Content: "198.51.100.10", TTL: 600},
+	})
+
+	ipv4Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("198.51.100.12"))
+	}))
+	defer ipv4Server.Close()
+	restore := dnsSvc.OverrideIPEndpointsForTest([]string{ipv4Server.URL}, []string{"http://127.0.0.1:1"})
+	defer restore()
+
+	officeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("wan2=203.0.113.99"))
+	}))
+	defer officeServer.Close()
+	previous := settings.DDNSSettings.AllowedURLs
+	settings.DDNSSettings.AllowedURLs = []string{officeServer.URL}
+	defer func() { settings.DDNSSettings.AllowedURLs = previous }()
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+
+	cred := createCredential(t, q)
+	domain, err := service.CreateDomain(ctx, dnsSvc.DomainInput{
+		Domain:          "example.com",
+		DnsCredentialID: cred.ID,
+	})
+	require.NoError(t, err)
+
+	cfg, err := service.UpdateDDNSConfig(ctx, domain.ID, dnsSvc.DDNSUpdateInput{
+		Enabled:         true,
+		IntervalSeconds: dnsSvc.DefaultDDNSInterval(),
+		IPVersion:       "ipv4",
+		RecordIDs:       []string{"home-a", "office-a"},
+		TargetSources: map[string]*model.DDNSIPSource{
+			"office-a": {Type: dnsSvc.DDNSSourceURL, URL: officeServer.URL, Regex: `wan2=(\S+)`},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, cfg.Targets, 2)
+
+	require.NoError(t, dnsSvc.RunDDNSUpdate(ctx, domain.ID))
+
+	contents := map[string]string{}
+	for _, record := range getMockUpdatedRecords() {
+		contents[record.Name] = record.Content
+	}
+	require.Equal(t, map[string]string{"home": "198.51.100.12", "office": "203.0.113.99"}, contents)
+
+	var persisted model.DnsDomain
+	require.NoError(t, model.UseDB().WithContext(ctx).First(&persisted, domain.ID).Error)
+	require.Equal(t, "198.51.100.12", persisted.DDNSConfig.LastIPv4, "last ip follows the domain source")
+}
+
+func TestUpdateDDNSConfigRejectsInvalidSource(t *testing.T) {
+	registerMockProvider()
+	setMockRecords([]dnsSvc.Record{
+		{ID: "a-record", Type: "A", Name: "home", Content: "198.51.100.10", TTL: 600},
+	})
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+
+	cred := createCredential(t, q)
+	domain, err := service.CreateDomain(ctx, dnsSvc.DomainInput{
+		Domain:          "example.com",
+		DnsCredentialID: cred.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = service.UpdateDDNSConfig(ctx, domain.ID, dnsSvc.DDNSUpdateInput{
+		Enabled:         true,
+		IntervalSeconds: dnsSvc.DefaultDDNSInterval(),
+		IPVersion:       "ipv4",
+		RecordIDs:       []string{"a-record"},
+		Source:          &model.DDNSIPSource{Type: dnsSvc.DDNSSourceWebhook},
+	})
+	require.ErrorContains(t, err, "webhook source requires the webhook")
+}
+
 func TestRunDDNSUpdateClearsFailedSinceOnRecovery(t *testing.T) {
 	registerMockProvider()
 	setMockRecords([]dnsSvc.Record{
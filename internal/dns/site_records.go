@@ -0,0 +1,354 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+// defaultSiteRecordTTL is used for records provisioned for a site.
+const defaultSiteRecordTTL = 600
+
+// SiteRecordAction names what provisioning does for one server name.
+type SiteRecordAction string
+
+const (
+	// SiteRecordCreate adds a missing record.
+	SiteRecordCreate SiteRecordAction = "create"
+	// SiteRecordRename moves a record provisioned for a server name the site
+	// no longer serves.
+	SiteRecordRename SiteRecordAction = "rename"
+	// SiteRecordExists links a record that already points at the target.
+	SiteRecordExists SiteRecordAction = "exists"
+	// SiteRecordConflict reports a record that points elsewhere and is left
+	// untouched.
+	SiteRecordConflict SiteRecordAction = "conflict"
+)
+
+// SiteRecordTarget is what records provisioned for a site point at.
+type SiteRecordTarget struct {
+	IPv4  string `json:"ipv4"`
+	IPv6  string `json:"ipv6"`
+	CNAME string `json:"cname"`
+}
+
+// SiteRecordChange is the planned outcome for one record of a server name.
+type SiteRecordChange struct {
+	Action   SiteRecordAction `json:"action"`
+	Host     string           `json:"host"`
+	DomainID uint64           `json:"domain_id"`
+	Domain   string           `json:"domain"`
+	Name     string           `json:"name"`
+	Type     string           `json:"type"`
+	Content  string           `json:"content"`
+	RecordID string           `json:"record_id,omitempty"`
+	// Previous is the name a renamed record currently has, or the content a
+	// conflicting record points at.
+	Previous string `json:"previous,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SiteRecordPlan lists the records provisioning would create or change for
+// the server names of a site. Unmatched holds names outside every managed
+// DNS domain.
+type SiteRecordPlan struct {
+	Changes   []SiteRecordChange `json:"changes"`
+	Unmatched []string           `json:"unmatched"`
+}
+
+// Pending reports whether applying the plan calls the provider.
+func (p *SiteRecordPlan) Pending() bool {
+	for _, change := range p.Changes {
+		if change.Action == SiteRecordCreate || change.Action == SiteRecordRename {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchDomain returns the managed domain with the longest suffix match for
+// host and the record name of host within it, or nil when none matches.
+func (s *Service) MatchDomain(ctx context.Context, host string) (*model.DnsDomain, string, error) {
+	domains, err := query.DnsDomain.WithContext(ctx).Find()
+	if err != nil {
+		return nil, "", err
+	}
+	domain := matchDomain(domains, host)
+	if domain == nil {
+		return nil, "", nil
+	}
+	return domain, RecordSetRelativeName(host, domain.Domain), nil
+}
+
+// PlanSiteRecords plans the records pointing the hosts of a site at target.
+// linked are the records already linked to the site; managed ones whose host
+// is no longer served are renamed instead of creating a new record.
+func (s *Service) PlanSiteRecords(ctx context.Context, hosts []string, linked []model.SiteDNSRecord, linkedDomainID uint64, target SiteRecordTarget) (*SiteRecordPlan, error) {
+	if err := validateSiteRecordTarget(target); err != nil {
+		return nil, err
+	}
+
+	domains, err := query.DnsDomain.WithContext(ctx).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SiteRecordPlan{Changes: []SiteRecordChange{}, Unmatched: []string{}}
+	served := make(map[siteRecordKey]struct{})
+	records := make(map[uint64][]Record)
+	for _, host := range NormalizeSiteHosts(hosts) {
+		domain := matchDomain(domains, host)
+		if domain == nil {
+			plan.Unmatched = append(plan.Unmatched, host)
+			continue
+		}
+		name := RecordSetRelativeName(host, domain.Domain)
+		served[newSiteRecordKey(domain.ID, name)] = struct{}{}
+
+		existing, ok := records[domain.ID]
+		if !ok {
+			existing, err = s.ListRecords(ctx, domain.ID, RecordListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			records[domain.ID] = existing
+		}
+
+		for _, input := range siteRecordInputs(name, target) {
+			plan.Changes = append(plan.Changes, planSiteRecord(host, domain, input, existing))
+		}
+	}
+
+	// A managed record whose host is gone follows the site to a new host of
+	// the same domain and type rather than leaving a stale record behind.
+	for _, record := range linked {
+		domainID := record.DomainID
+		if domainID == 0 {
+			domainID = linkedDomainID
+		}
+		if !record.Managed || domainID == 0 {
+			continue
+		}
+		if _, ok := served[newSiteRecordKey(domainID, record.Name)]; ok {
+			continue
+		}
+		for i := range plan.Changes {
+			change := &plan.Changes[i]
+			if change.Action == SiteRecordCreate && change.DomainID == domainID && strings.EqualFold(change.Type, record.Type) {
+				change.Action = SiteRecordRename
+				change.RecordID = record.ID
+				change.Previous = record.Name
+				break
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplySiteRecords executes the create and rename changes of plan and returns
+// the records to link to the site. Failed changes keep their error in plan.
+func (s *Service) ApplySiteRecords(ctx context.Context, plan *SiteRecordPlan) []model.SiteDNSRecord {
+	links := make([]model.SiteDNSRecord, 0, len(plan.Changes))
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		input := RecordInput{
+			Type:    change.Type,
+			Name:    change.Name,
+			Content: change.Content,
+			TTL:     defaultSiteRecordTTL,
+		}
+
+		var (
+			record Record
+			err    error
+		)
+		switch change.Action {
+		case SiteRecordCreate:
+			record, err = s.CreateRecord(ctx, change.DomainID, input)
+		case SiteRecordRename:
+			record, err = s.UpdateRecord(ctx, change.DomainID, change.RecordID, input)
+		case SiteRecordExists:
+			links = append(links, model.SiteDNSRecord{
+				ID:       change.RecordID,
+				Name:     change.Name,
+				Type:     change.Type,
+				Exists:   true,
+				DomainID: change.DomainID,
+			})
+			continue
+		default:
+			continue
+		}
+		if err != nil {
+			change.Error = err.Error()
+			continue
+		}
+
+		change.RecordID = record.ID
+		links = append(links, model.SiteDNSRecord{
+			ID:       record.ID,
+			Name:     change.Name,
+			Type:     change.Type,
+			Exists:   true,
+			DomainID: change.DomainID,
+			Managed:  true,
+		})
+	}
+	return links
+}
+
+// DeleteSiteRecords removes the managed records among linked and returns the
+// errors of the records that could not be removed.
+func (s *Service) DeleteSiteRecords(ctx context.Context, linked []model.SiteDNSRecord, linkedDomainID uint64) []error {
+	var errs []error
+	for _, record := range linked {
+		domainID := record.DomainID
+		if domainID == 0 {
+			domainID = linkedDomainID
+		}
+		if !record.Managed || domainID == 0 {
+			continue
+		}
+		if err := s.DeleteRecord(ctx, domainID, record.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// NormalizeSiteHosts turns server_name values into the host names records
+// can be provisioned for. Regular expressions, catch-all names, IP addresses
+// and suffix wildcards are dropped, and ".example.com" stands for the apex.
+func NormalizeSiteHosts(names []string) []string {
+	hosts := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		host := strings.Trim(strings.ToLower(strings.TrimSpace(name)), "\"'")
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "."), ".")
+		if host == "" || host == "_" || host == "localhost" || strings.HasPrefix(host, "~") ||
+			net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+			continue
+		}
+		// Only a leading wildcard label maps onto a DNS wildcard record.
+		if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			continue
+		}
+		if _, ok := seen[host]; ok {
+			continue
+		}
+		seen[host] = struct{}{}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func matchDomain(domains []*model.DnsDomain, host string) *model.DnsDomain {
+	host = strings.Trim(strings.ToLower(host), ".")
+
+	var best *model.DnsDomain
+	for _, domain := range domains {
+		zone := strings.Trim(strings.ToLower(domain.Domain), ".")
+		if host != zone && !strings.HasSuffix(host, "."+zone) {
+			continue
+		}
+		if best == nil || len(zone) > len(best.Domain) || (len(zone) == len(best.Domain) && domain.ID < best.ID) {
+			best = domain
+		}
+	}
+	return best
+}
+
+func validateSiteRecordTarget(target SiteRecordTarget) error {
+	if target.IPv4 == "" && target.IPv6 == "" && target.CNAME == "" {
+		return ErrSiteRecordTargetRequired
+	}
+	if target.IPv4 != "" && parseExpectedIP(target.IPv4, ipFamilyV4) == nil {
+		return cosy.WrapErrorWithParams(ErrInvalidSiteRecordTarget, target.IPv4)
+	}
+	if target.IPv6 != "" && parseExpectedIP(target.IPv6, ipFamilyV6) == nil {
+		return cosy.WrapErrorWithParams(ErrInvalidSiteRecordTarget, target.IPv6)
+	}
+	if target.CNAME != "" && !domainPattern.MatchString(strings.Trim(strings.ToLower(target.CNAME), ".")) {
+		return cosy.WrapErrorWithParams(ErrInvalidSiteRecordTarget, target.CNAME)
+	}
+	return nil
+}
+
+// siteRecordInputs returns the records name needs. A CNAME cannot live at the
+// zone apex, which falls back to the address targets.
+func siteRecordInputs(name string, target SiteRecordTarget) []RecordInput {
+	if target.CNAME != "" && name != "@" {
+		return []RecordInput{{Type: "CNAME", Name: name, Content: strings.Trim(strings.ToLower(target.CNAME), ".")}}
+	}
+
+	var inputs []RecordInput
+	if target.IPv4 != "" {
+		inputs = append(inputs, RecordInput{Type: "A", Name: name, Content: target.IPv4})
+	}
+	if target.IPv6 != "" {
+		inputs = append(inputs, RecordInput{Type: "AAAA", Name: name, Content: target.IPv6})
+	}
+	return inputs
+}
+
+func planSiteRecord(host string, domain *model.DnsDomain, input RecordInput, existing []Record) SiteRecordChange {
+	change := SiteRecordChange{
+		Action:   SiteRecordCreate,
+		Host:     host,
+		DomainID: domain.ID,
+		Domain:   domain.Domain,
+		Name:     input.Name,
+		Type:     input.Type,
+		Content:  input.Content,
+	}
+
+	var conflict *Record
+	for i := range existing {
+		record := &existing[i]
+		if RecordSetRelativeName(record.Name, domain.Domain) != input.Name {
+			continue
+		}
+		recordType := strings.ToUpper(record.Type)
+		if recordType == input.Type && siteRecordContains(record.Content, input.Content) {
+			change.Action = SiteRecordExists
+			change.RecordID = record.ID
+			return change
+		}
+		// A CNAME excludes every other record of the same name.
+		if conflict == nil && (recordType == input.Type || recordType == "CNAME" || input.Type == "CNAME") {
+			conflict = record
+		}
+	}
+
+	if conflict != nil {
+		change.Action = SiteRecordConflict
+		change.RecordID = conflict.ID
+		change.Previous = strings.ToUpper(conflict.Type) + " " + conflict.Content
+	}
+	return change
+}
+
+func siteRecordContains(content, value string) bool {
+	value = strings.Trim(strings.ToLower(value), ".")
+	for _, item := range splitRecordSetValues(content) {
+		if strings.Trim(strings.ToLower(item), ".") == value {
+			return true
+		}
+	}
+	return false
+}
+
+// siteRecordKey identifies a record name within a managed domain.
+type siteRecordKey struct {
+	domainID uint64
+	name     string
+}
+
+func newSiteRecordKey(domainID uint64, name string) siteRecordKey {
+	return siteRecordKey{domainID: domainID, name: strings.ToLower(name)}
+}
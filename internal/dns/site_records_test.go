@@ -0,0 +1,122 @@
+package dns_test
+
+import (
+	"context"
+	"testing"
+
+	dnsSvc "github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSiteHosts(t *testing.T) {
+	hosts := dnsSvc.NormalizeSiteHosts([]string{
+		"www.Example.com", "_", "localhost", "~^(?<sub>.+)\\.example\\.com$",
+		"192.0.2.1", "[::1]", "intranet", "*.example.com", "mail.*", ".example.org",
+		"www.example.com",
+	})
+	require.Equal(t, []string{"www.example.com", "*.example.com", "example.org"}, hosts)
+}
+
+func TestPlanSiteRecords(t *testing.T) {
+	registerRecordSetProvider()
+	recordSetStore.reset([]dnsSvc.Record{
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+		{ID: "api/CNAME", Type: "CNAME", Name: "api", Content: "lb.example.net", TTL: 300},
+	})
+
+	q := setupTestQuery(t)
+	resetDomains(t, q)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	cred := createRecordSetCredential(t, q)
+	parent := createZoneDomain(t, service, cred.ID)
+	child, err := service.CreateDomain(ctx, dnsSvc.DomainInput{Domain: "dev.example.com", DnsCredentialID: cred.ID})
+	require.NoError(t, err)
+
+	_, err = service.PlanSiteRecords(ctx, []string{"www.example.com"}, nil, 0, dnsSvc.SiteRecordTarget{})
+	require.ErrorIs(t, err, dnsSvc.ErrSiteRecordTargetRequired)
+
+	_, err = service.PlanSiteRecords(ctx, []string{"www.example.com"}, nil, 0, dnsSvc.SiteRecordTarget{IPv4: "2001:db8::1"})
+	require.ErrorContains(t, err, "Invalid DNS record target")
+
+	plan, err := service.PlanSiteRecords(ctx,
+		[]string{"www.example.com", "api.example.com", "app.dev.example.com", "example.com", "www.example.org"},
+		nil, 0, dnsSvc.SiteRecordTarget{IPv4: "192.0.2.1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"www.example.org"}, plan.Unmatched)
+	require.Len(t, plan.Changes, 4)
+
+	require.Equal(t, dnsSvc.SiteRecordExists, plan.Changes[0].Action)
+	require.Equal(t, "www/A", plan.Changes[0].RecordID)
+
+	require.Equal(t, dnsSvc.SiteRecordConflict, plan.Changes[1].Action)
+	require.Equal(t, "CNAME lb.example.net", plan.Changes[1].Previous)
+
+	// The longest suffix wins.
+	require.Equal(t, dnsSvc.SiteRecordCreate, plan.Changes[2].Action)
+	require.Equal(t, child.ID, plan.Changes[2].DomainID)
+	require.Equal(t, "app", plan.Changes[2].Name)
+
+	require.Equal(t, dnsSvc.SiteRecordCreate, plan.Changes[3].Action)
+	require.Equal(t, parent.ID, plan.Changes[3].DomainID)
+	require.Equal(t, "@", plan.Changes[3].Name)
+	require.True(t, plan.Pending())
+
+	// A CNAME target falls back to the addresses at the apex.
+	plan, err = service.PlanSiteRecords(ctx, []string{"example.com", "blog.example.com"}, nil, 0,
+		dnsSvc.SiteRecordTarget{IPv6: "2001:db8::1", CNAME: "edge.example.net."})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "AAAA", plan.Changes[0].Type)
+	require.Equal(t, "CNAME", plan.Changes[1].Type)
+	require.Equal(t, "edge.example.net", plan.Changes[1].Content)
+}
+
+func TestApplySiteRecordsRenamesManagedRecords(t *testing.T) {
+	registerRecordSetProvider()
+	recordSetStore.reset([]dnsSvc.Record{
+		{ID: "old/A", Type: "A", Name: "old", Content: "192.0.2.1", TTL: 600},
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+	})
+
+	q := setupTestQuery(t)
+	resetDomains(t, q)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createRecordSetCredential(t, q).ID)
+
+	linked := []model.SiteDNSRecord{{ID: "old/A", Name: "old", Type: "A", Managed: true}}
+	plan, err := service.PlanSiteRecords(ctx, []string{"new.example.com", "www.example.com"}, linked, domain.ID,
+		dnsSvc.SiteRecordTarget{IPv4: "192.0.2.1"})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, dnsSvc.SiteRecordRename, plan.Changes[0].Action)
+	require.Equal(t, "old", plan.Changes[0].Previous)
+
+	links := service.ApplySiteRecords(ctx, plan)
+	require.Equal(t, []model.SiteDNSRecord{
+		{ID: "new/A", Name: "new", Type: "A", Exists: true, DomainID: domain.ID, Managed: true},
+		{ID: "www/A", Name: "www", Type: "A", Exists: true, DomainID: domain.ID},
+	}, links)
+	require.ElementsMatch(t, []dnsSvc.Record{
+		{ID: "new/A", Type: "A", Name: "new", Content: "192.0.2.1", TTL: 600},
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+	}, recordSetStore.list())
+
+	// Only managed records are removed with the site.
+	require.Empty(t, service.DeleteSiteRecords(ctx, links, domain.ID))
+	require.Equal(t, []dnsSvc.Record{
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+	}, recordSetStore.list())
+}
+
+// resetDomains drops the domains earlier tests left in the shared database,
+// which would otherwise take part in suffix matching.
+func resetDomains(tb testing.TB, q *query.Query) {
+	tb.Helper()
+
+	_, err := q.DnsDomain.Unscoped().Where(q.DnsDomain.ID.Gt(0)).Delete()
+	require.NoError(tb, err)
+}
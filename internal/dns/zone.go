@@ -0,0 +1,750 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	mdns "github.com/miekg/dns"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+// defaultZoneTTL is written for records whose provider reports no TTL, which
+// usually means the record inherits the zone default.
+const defaultZoneTTL = 3600
+
+// txtChunkSize is the longest character string a TXT record can hold.
+const txtChunkSize = 255
+
+// ZoneChangeAction names what a zone import or copy does to a record.
+type ZoneChangeAction string
+
+const (
+	ZoneChangeAdd    ZoneChangeAction = "add"
+	ZoneChangeUpdate ZoneChangeAction = "update"
+	ZoneChangeDelete ZoneChangeAction = "delete"
+)
+
+// ZoneChange is a single provider call planned by a zone diff. Before is the
+// record as the provider holds it and After the input sent to replace it.
+type ZoneChange struct {
+	Action   ZoneChangeAction `json:"action"`
+	Name     string           `json:"name"`
+	Type     string           `json:"type"`
+	RecordID string           `json:"record_id,omitempty"`
+	Before   *Record          `json:"before,omitempty"`
+	After    *RecordInput     `json:"after,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// ZoneDiff lists the changes that bring a provider zone in line with a zone
+// file. Skipped holds records that could not be represented on either side.
+type ZoneDiff struct {
+	Changes   []ZoneChange `json:"changes"`
+	Unchanged int          `json:"unchanged"`
+	Skipped   []string     `json:"skipped"`
+}
+
+// ZoneSyncOptions controls zone imports and copies.
+type ZoneSyncOptions struct {
+	// DryRun computes the diff without calling the provider.
+	DryRun bool
+	// DeleteMissing removes record sets that exist at the provider but not in
+	// the source zone. Without it, such sets are left untouched.
+	DeleteMissing bool
+}
+
+// ZoneSyncResult reports the diff of a zone import or copy and, unless it was
+// a dry run, how many of its changes were applied.
+type ZoneSyncResult struct {
+	ZoneDiff
+	DomainID uint64 `json:"domain_id"`
+	DryRun   bool   `json:"dry_run"`
+	Applied  int    `json:"applied"`
+	Failed   int    `json:"failed"`
+}
+
+// ZoneFile is a domain exported in RFC 1035 master file format.
+type ZoneFile struct {
+	Domain  string `json:"domain"`
+	Content string `json:"content"`
+}
+
+// ExportZone renders the records of a managed domain as a BIND zone file.
+// Records the provider returns in a form that is not valid zone file syntax
+// are listed as comments at the end of the file.
+func (s *Service) ExportZone(ctx context.Context, domainID uint64) (*ZoneFile, error) {
+	domain, provider, err := s.prepareProvider(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := listZoneRecords(ctx, provider, domain.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	rrs := make([]mdns.RR, 0, len(records))
+	var skipped []string
+	for _, record := range records {
+		expanded, err := recordToRRs(domain.Domain, record)
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		rrs = append(rrs, expanded...)
+	}
+	sortRRs(rrs)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "; Zone %s exported by Nginx UI on %s\n", domain.Domain, time.Now().UTC().Format(time.RFC3339))
+	if domain.DnsCredential != nil {
+		fmt.Fprintf(&builder, "; Provider: %s (%s)\n", domain.DnsCredential.Provider, domain.DnsCredential.Name)
+	}
+	fmt.Fprintf(&builder, "$ORIGIN %s\n", mdns.Fqdn(domain.Domain))
+	for _, rr := range rrs {
+		builder.WriteString(rr.String())
+		builder.WriteByte('\n')
+	}
+	for _, reason := range skipped {
+		fmt.Fprintf(&builder, "; skipped %s\n", reason)
+	}
+
+	return &ZoneFile{Domain: domain.Domain, Content: builder.String()}, nil
+}
+
+// ImportZone diffs a BIND zone file against the records of a managed domain
+// and, unless opts.DryRun is set, applies the difference. Every record set
+// present in the file replaces the provider's set of the same name and type.
+// SOA records and the apex NS set belong to the provider and are ignored.
+func (s *Service) ImportZone(ctx context.Context, domainID uint64, content string, opts ZoneSyncOptions) (*ZoneSyncResult, error) {
+	domain, provider, err := s.prepareProvider(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := parseZoneFile(domain.Domain, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return syncZone(ctx, provider, domain.Domain, domain.ID, desired, nil, opts)
+}
+
+// CopyZone copies the records of a managed domain to the same domain under
+// another credential, for example when moving a zone between providers. The
+// target domain is created on demand when the copy is applied.
+func (s *Service) CopyZone(ctx context.Context, domainID uint64, targetCredentialID uint64, opts ZoneSyncOptions) (*ZoneSyncResult, error) {
+	source, sourceProvider, err := s.prepareProvider(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if source.DnsCredentialID == targetCredentialID {
+		return nil, ErrSameZoneCredential
+	}
+
+	targetCred, err := loadCredential(ctx, targetCredentialID)
+	if err != nil {
+		return nil, err
+	}
+	providerCred, err := toProviderCredential(targetCred)
+	if err != nil {
+		return nil, err
+	}
+	targetProvider, err := NewProvider(providerCred.Code, providerCred)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := listZoneRecords(ctx, sourceProvider, source.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		desired []mdns.RR
+		skipped []string
+	)
+	for _, record := range records {
+		expanded, err := recordToRRs(source.Domain, record)
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		desired = append(desired, expanded...)
+	}
+
+	dao := query.DnsDomain
+	target, err := dao.WithContext(ctx).
+		Where(dao.DnsCredentialID.Eq(targetCred.ID), dao.Domain.Eq(source.Domain)).
+		First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	targetID := uint64(0)
+	if target != nil {
+		targetID = target.ID
+	}
+	if target == nil && !opts.DryRun {
+		target = &model.DnsDomain{
+			Domain:          source.Domain,
+			Description:     source.Description,
+			DnsCredentialID: targetCred.ID,
+		}
+		if err := dao.WithContext(ctx).Create(target); err != nil {
+			return nil, err
+		}
+		targetID = target.ID
+	}
+
+	return syncZone(ctx, targetProvider, source.Domain, targetID, desired, skipped, opts)
+}
+
+// syncZone diffs desired against the records provider holds for domain and
+// applies the changes unless opts.DryRun is set.
+func syncZone(ctx context.Context, provider Provider, domain string, domainID uint64, desired []mdns.RR, skipped []string, opts ZoneSyncOptions) (*ZoneSyncResult, error) {
+	records, err := listZoneRecords(ctx, provider, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffZone(domain, records, desired, managesRecordSets(provider), opts.DeleteMissing)
+	diff.Skipped = append(diff.Skipped, skipped...)
+
+	result := &ZoneSyncResult{ZoneDiff: diff, DomainID: domainID, DryRun: opts.DryRun}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// Deleting first frees names for CNAME records and keeps providers that
+	// reject duplicate record sets from failing the adds.
+	for _, action := range []ZoneChangeAction{ZoneChangeDelete, ZoneChangeUpdate, ZoneChangeAdd} {
+		for i := range result.Changes {
+			change := &result.Changes[i]
+			if change.Action != action {
+				continue
+			}
+			if err := applyZoneChange(ctx, provider, domain, change); err != nil {
+				change.Error = err.Error()
+				result.Failed++
+				continue
+			}
+			result.Applied++
+		}
+	}
+
+	return result, nil
+}
+
+func applyZoneChange(ctx context.Context, provider Provider, domain string, change *ZoneChange) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+
+	switch change.Action {
+	case ZoneChangeAdd:
+		_, err := provider.CreateRecord(ctxWithTimeout, domain, *change.After)
+		return err
+	case ZoneChangeUpdate:
+		_, err := provider.UpdateRecord(ctxWithTimeout, domain, change.RecordID, *change.After)
+		return err
+	case ZoneChangeDelete:
+		return provider.DeleteRecord(ctxWithTimeout, domain, change.RecordID)
+	}
+	return fmt.Errorf("unknown zone change %q", change.Action)
+}
+
+func listZoneRecords(ctx context.Context, provider Provider, domain string) ([]Record, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+
+	return provider.ListRecords(ctxWithTimeout, domain, RecordFilter{})
+}
+
+func managesRecordSets(provider Provider) bool {
+	setProvider, ok := provider.(RecordSetProvider)
+	return ok && setProvider.ManagesRecordSets()
+}
+
+// parseZoneFile reads the records of a master file relative to domain.
+func parseZoneFile(domain, content string) ([]mdns.RR, error) {
+	origin := mdns.Fqdn(domain)
+	parser := mdns.NewZoneParser(strings.NewReader(content), origin, "")
+
+	var rrs []mdns.RR
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		if !mdns.IsSubDomain(origin, rr.Header().Name) {
+			return nil, cosy.WrapErrorWithParams(ErrZoneRecordOutOfZone, rr.Header().Name, domain)
+		}
+		rrs = append(rrs, rr)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidZoneFile, err.Error())
+	}
+
+	return rrs, nil
+}
+
+// zoneUnit is a provider record and the resource records it expands to.
+type zoneUnit struct {
+	record Record
+	rrs    []mdns.RR
+}
+
+// zoneSet groups the entries sharing an owner name and type.
+type zoneSet struct {
+	name       string
+	recordType string
+	desired    []mdns.RR
+	current    []zoneUnit
+}
+
+func diffZone(domain string, records []Record, desired []mdns.RR, recordSets bool, deleteMissing bool) ZoneDiff {
+	diff := ZoneDiff{Changes: []ZoneChange{}, Skipped: []string{}}
+	sets := map[string]*zoneSet{}
+	var keys []string
+
+	lookup := func(name, recordType string) *zoneSet {
+		key := strings.ToLower(name) + "/" + recordType
+		set, ok := sets[key]
+		if !ok {
+			set = &zoneSet{name: name, recordType: recordType}
+			sets[key] = set
+			keys = append(keys, key)
+		}
+		return set
+	}
+
+	for _, rr := range desired {
+		if isProviderManagedRR(domain, rr) {
+			continue
+		}
+		set := lookup(rr.Header().Name, mdns.TypeToString[rr.Header().Rrtype])
+		set.desired = append(set.desired, rr)
+	}
+
+	for _, record := range records {
+		rrs, err := recordToRRs(domain, record)
+		if err != nil {
+			// Records that cannot be read are never touched.
+			diff.Skipped = append(diff.Skipped, err.Error())
+			continue
+		}
+		if isProviderManagedRR(domain, rrs[0]) {
+			continue
+		}
+		set := lookup(rrs[0].Header().Name, mdns.TypeToString[rrs[0].Header().Rrtype])
+		set.current = append(set.current, zoneUnit{record: record, rrs: rrs})
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		set := sets[key]
+		name := RecordSetRelativeName(set.name, domain)
+
+		if len(set.desired) == 0 {
+			if !deleteMissing {
+				continue
+			}
+			for _, unit := range set.current {
+				diff.Changes = append(diff.Changes, deleteChange(name, set.recordType, unit.record))
+			}
+			continue
+		}
+
+		if recordSets {
+			diffRecordSet(&diff, domain, name, set)
+		} else {
+			diffRecords(&diff, domain, name, set)
+		}
+	}
+
+	return diff
+}
+
+// diffRecordSet compares a set as a whole, for providers exposing one record
+// per set.
+func diffRecordSet(diff *ZoneDiff, domain, name string, set *zoneSet) {
+	input := recordSetInput(domain, set.desired)
+	if len(set.current) == 0 {
+		diff.Changes = append(diff.Changes, ZoneChange{Action: ZoneChangeAdd, Name: name, Type: set.recordType, After: &input})
+		return
+	}
+
+	current := set.current[0]
+	if sameRRs(current.rrs, set.desired) {
+		diff.Unchanged++
+	} else {
+		preserveRecordSettings(&input, current.record)
+		before := current.record
+		diff.Changes = append(diff.Changes, ZoneChange{
+			Action:   ZoneChangeUpdate,
+			Name:     name,
+			Type:     set.recordType,
+			RecordID: current.record.ID,
+			Before:   &before,
+			After:    &input,
+		})
+	}
+
+	// A provider listing the same set twice is left with a single copy.
+	for _, unit := range set.current[1:] {
+		diff.Changes = append(diff.Changes, deleteChange(name, set.recordType, unit.record))
+	}
+}
+
+// diffRecords matches the values of a set one by one, for providers exposing
+// one record per value. Unmatched values are paired into updates so record
+// IDs referenced elsewhere, such as by DDNS, survive where possible.
+func diffRecords(diff *ZoneDiff, domain, name string, set *zoneSet) {
+	matched := make([]bool, len(set.current))
+	var pending []mdns.RR
+
+	for _, rr := range set.desired {
+		index := -1
+		for i, unit := range set.current {
+			if !matched[i] && len(unit.rrs) == 1 && rdataKey(unit.rrs[0]) == rdataKey(rr) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			pending = append(pending, rr)
+			continue
+		}
+
+		matched[index] = true
+		unit := set.current[index]
+		if unit.rrs[0].Header().Ttl == rr.Header().Ttl {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changes = append(diff.Changes, updateChange(domain, name, set.recordType, unit.record, rr))
+	}
+
+	for i, unit := range set.current {
+		if matched[i] {
+			continue
+		}
+		if len(pending) > 0 {
+			diff.Changes = append(diff.Changes, updateChange(domain, name, set.recordType, unit.record, pending[0]))
+			pending = pending[1:]
+			continue
+		}
+		diff.Changes = append(diff.Changes, deleteChange(name, set.recordType, unit.record))
+	}
+
+	for _, rr := range pending {
+		input := recordInputFromRR(domain, rr)
+		diff.Changes = append(diff.Changes, ZoneChange{Action: ZoneChangeAdd, Name: name, Type: set.recordType, After: &input})
+	}
+}
+
+func updateChange(domain, name, recordType string, record Record, rr mdns.RR) ZoneChange {
+	input := recordInputFromRR(domain, rr)
+	preserveRecordSettings(&input, record)
+	return ZoneChange{
+		Action:   ZoneChangeUpdate,
+		Name:     name,
+		Type:     recordType,
+		RecordID: record.ID,
+		Before:   &record,
+		After:    &input,
+	}
+}
+
+func deleteChange(name, recordType string, record Record) ZoneChange {
+	return ZoneChange{
+		Action:   ZoneChangeDelete,
+		Name:     name,
+		Type:     recordType,
+		RecordID: record.ID,
+		Before:   &record,
+	}
+}
+
+// preserveRecordSettings carries provider-specific settings that a zone file
+// cannot express over to the input replacing record.
+func preserveRecordSettings(input *RecordInput, record Record) {
+	if record.Line != "" {
+		line := record.Line
+		input.Line = &line
+	}
+	input.Proxied = record.Proxied
+	input.Comment = record.Comment
+}
+
+// isProviderManagedRR reports records that belong to the provider rather than
+// to the zone content: the SOA, the apex NS set and DNSSEC material.
+func isProviderManagedRR(domain string, rr mdns.RR) bool {
+	switch rr.Header().Rrtype {
+	case mdns.TypeSOA, mdns.TypeRRSIG, mdns.TypeNSEC, mdns.TypeNSEC3, mdns.TypeNSEC3PARAM, mdns.TypeDNSKEY:
+		return true
+	case mdns.TypeNS:
+		return strings.EqualFold(rr.Header().Name, mdns.Fqdn(domain))
+	}
+	return false
+}
+
+// recordToRRs expands a provider record into resource records, one per line
+// of its content.
+func recordToRRs(domain string, record Record) ([]mdns.RR, error) {
+	recordType := strings.ToUpper(strings.TrimSpace(record.Type))
+	owner := RecordSetFQDN(domain, record.Name)
+	ttl := record.TTL
+	if ttl <= 0 {
+		ttl = defaultZoneTTL
+	}
+
+	values := splitRecordSetValues(record.Content)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s: no value", RecordSetID(record.Name, recordType))
+	}
+
+	rrs := make([]mdns.RR, 0, len(values))
+	for _, value := range values {
+		rdata, err := recordValueToRdata(recordType, value, record.Priority, record.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", RecordSetID(record.Name, recordType), err)
+		}
+		rr, err := mdns.NewRR(fmt.Sprintf("%s %d IN %s %s", owner, ttl, recordType, rdata))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", RecordSetID(record.Name, recordType), err)
+		}
+		if rr == nil {
+			return nil, fmt.Errorf("%s: no value", RecordSetID(record.Name, recordType))
+		}
+		rrs = append(rrs, rr)
+	}
+
+	return rrs, nil
+}
+
+// recordValueToRdata turns a provider value into zone file rdata. Providers
+// report targets without the trailing dot and keep MX and SRV priorities in
+// separate fields, neither of which a master file allows.
+func recordValueToRdata(recordType, value string, priority, weight *int) (string, error) {
+	switch recordType {
+	case "TXT", "SPF":
+		if isQuotedRecordSetValue(value) {
+			return value, nil
+		}
+		return quoteTXT(value), nil
+	case "CNAME", "NS", "PTR", "DNAME", "ANAME", "ALIAS":
+		return mdns.Fqdn(value), nil
+	case "MX":
+		if !hasNumericPrefixes(value, 1) {
+			value = strconv.Itoa(intOrZero(priority)) + " " + value
+		}
+	case "SRV":
+		if !hasNumericPrefixes(value, 3) {
+			value = fmt.Sprintf("%d %d %s", intOrZero(priority), intOrZero(weight), value)
+		}
+	default:
+		return value, nil
+	}
+
+	fields := strings.Fields(value)
+	fields[len(fields)-1] = mdns.Fqdn(fields[len(fields)-1])
+	return strings.Join(fields, " "), nil
+}
+
+// recordInputFromRR builds the input creating rr at a provider exposing one
+// record per value.
+func recordInputFromRR(domain string, rr mdns.RR) RecordInput {
+	header := rr.Header()
+	input := RecordInput{
+		Type:    mdns.TypeToString[header.Rrtype],
+		Name:    RecordSetRelativeName(header.Name, domain),
+		Content: recordInputValue(rr),
+		TTL:     int(header.Ttl),
+	}
+
+	switch value := rr.(type) {
+	case *mdns.MX:
+		priority := int(value.Preference)
+		input.Priority = &priority
+		input.Content = strings.TrimSuffix(value.Mx, ".")
+	case *mdns.SRV:
+		priority, weight := int(value.Priority), int(value.Weight)
+		input.Priority, input.Weight = &priority, &weight
+		input.Content = fmt.Sprintf("%d %s", value.Port, strings.TrimSuffix(value.Target, "."))
+	}
+
+	return input
+}
+
+// recordSetInput builds the input creating a whole set at a provider exposing
+// one record per set. MX and SRV values keep their priorities inline, since
+// they may differ within the set.
+func recordSetInput(domain string, rrs []mdns.RR) RecordInput {
+	header := rrs[0].Header()
+	values := make([]string, 0, len(rrs))
+	for _, rr := range rrs {
+		switch rr.(type) {
+		case *mdns.MX, *mdns.SRV:
+			values = append(values, rdata(rr))
+		default:
+			values = append(values, recordInputValue(rr))
+		}
+	}
+
+	return RecordInput{
+		Type:    mdns.TypeToString[header.Rrtype],
+		Name:    RecordSetRelativeName(header.Name, domain),
+		Content: strings.Join(values, "\n"),
+		TTL:     int(header.Ttl),
+	}
+}
+
+// recordInputValue is the value of rr in the form providers accept in
+// RecordInput.Content: TXT strings joined and unquoted, targets without the
+// trailing dot.
+func recordInputValue(rr mdns.RR) string {
+	switch value := rr.(type) {
+	case *mdns.TXT:
+		return joinTXT(value.Txt)
+	case *mdns.SPF:
+		return joinTXT(value.Txt)
+	case *mdns.CNAME:
+		return strings.TrimSuffix(value.Target, ".")
+	case *mdns.NS:
+		return strings.TrimSuffix(value.Ns, ".")
+	case *mdns.PTR:
+		return strings.TrimSuffix(value.Ptr, ".")
+	case *mdns.DNAME:
+		return strings.TrimSuffix(value.Target, ".")
+	}
+	return rdata(rr)
+}
+
+func sameRRs(a, b []mdns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	left := make([]string, 0, len(a))
+	right := make([]string, 0, len(b))
+	for i := range a {
+		left = append(left, rdataKey(a[i])+"/"+strconv.FormatUint(uint64(a[i].Header().Ttl), 10))
+		right = append(right, rdataKey(b[i])+"/"+strconv.FormatUint(uint64(b[i].Header().Ttl), 10))
+	}
+	sort.Strings(left)
+	sort.Strings(right)
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rdataKey compares record data: TXT strings regardless of how they are
+// split into chunks, everything else case-insensitively.
+func rdataKey(rr mdns.RR) string {
+	switch value := rr.(type) {
+	case *mdns.TXT:
+		return joinTXT(value.Txt)
+	case *mdns.SPF:
+		return joinTXT(value.Txt)
+	}
+	return strings.ToLower(rdata(rr))
+}
+
+func rdata(rr mdns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+func sortRRs(rrs []mdns.RR) {
+	sort.SliceStable(rrs, func(i, j int) bool {
+		left, right := rrs[i].Header(), rrs[j].Header()
+		if !strings.EqualFold(left.Name, right.Name) {
+			return canonicalNameLess(left.Name, right.Name)
+		}
+		if left.Rrtype != right.Rrtype {
+			return left.Rrtype < right.Rrtype
+		}
+		return rdata(rrs[i]) < rdata(rrs[j])
+	})
+}
+
+// canonicalNameLess orders names so the apex comes first and each name is
+// followed by the names below it.
+func canonicalNameLess(a, b string) bool {
+	left := mdns.SplitDomainName(strings.ToLower(a))
+	right := mdns.SplitDomainName(strings.ToLower(b))
+	for i, j := len(left)-1, len(right)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if left[i] != right[j] {
+			return left[i] < right[j]
+		}
+	}
+	return len(left) < len(right)
+}
+
+// quoteTXT renders value as TXT character strings of at most 255 bytes.
+func quoteTXT(value string) string {
+	var chunks []string
+	for len(value) > txtChunkSize {
+		end := txtChunkSize
+		// Keep multi-byte runes in one chunk.
+		for end > 0 && value[end]&0xC0 == 0x80 {
+			end--
+		}
+		chunks = append(chunks, value[:end])
+		value = value[end:]
+	}
+	chunks = append(chunks, value)
+
+	quoted := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		chunk = strings.ReplaceAll(chunk, `\`, `\\`)
+		chunk = strings.ReplaceAll(chunk, `"`, `\"`)
+		quoted = append(quoted, `"`+chunk+`"`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// joinTXT concatenates the character strings of a TXT record, resolving the
+// escapes the zone parser keeps in them.
+func joinTXT(chunks []string) string {
+	var builder strings.Builder
+	for _, chunk := range chunks {
+		for i := 0; i < len(chunk); i++ {
+			if chunk[i] != '\\' || i+1 >= len(chunk) {
+				builder.WriteByte(chunk[i])
+				continue
+			}
+			if i+3 < len(chunk) && isDigits(chunk[i+1:i+4]) {
+				code, _ := strconv.Atoi(chunk[i+1 : i+4])
+				builder.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+			builder.WriteByte(chunk[i+1])
+			i++
+		}
+	}
+	return builder.String()
+}
+
+func isDigits(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,288 @@
+package dns_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	certdns "github.com/0xJacky/Nginx-UI/internal/cert/dns"
+	dnsSvc "github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/require"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 300
+@       IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+@       IN NS  ns1.example.com.
+www     IN A   192.0.2.1
+www     IN A   192.0.2.2
+@   600 IN MX  10 mail.example.com.
+note    IN TXT "hello \"zone\"" " world"
+`
+
+func TestExportZone(t *testing.T) {
+	registerMockProvider()
+	priority := 10
+	setMockRecords([]dnsSvc.Record{
+		{ID: "1", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+		{ID: "2", Type: "MX", Name: "@", Content: "mail.example.com", TTL: 600, Priority: &priority},
+		{ID: "3", Type: "TXT", Name: "@", Content: `v=spf1 include:"spf.example.net" -all`, TTL: 600},
+		{ID: "4", Type: "CNAME", Name: "blog", Content: "www.example.com", TTL: 0},
+		{ID: "5", Type: "mock", Name: "odd", Content: "value", TTL: 60},
+	})
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createCredential(t, q).ID)
+
+	zone, err := service.ExportZone(ctx, domain.ID)
+	require.NoError(t, err)
+	require.Equal(t, "example.com", zone.Domain)
+
+	lines := strings.Split(strings.TrimSpace(zone.Content), "\n")
+	require.Contains(t, lines, "$ORIGIN example.com.")
+	require.Contains(t, lines, "example.com.\t600\tIN\tMX\t10 mail.example.com.")
+	require.Contains(t, lines, "example.com.\t600\tIN\tTXT\t\"v=spf1 include:\\\"spf.example.net\\\" -all\"")
+	require.Contains(t, lines, "blog.example.com.\t3600\tIN\tCNAME\twww.example.com.")
+	require.Contains(t, lines, "www.example.com.\t300\tIN\tA\t192.0.2.1")
+	require.True(t, strings.HasPrefix(lines[len(lines)-1], "; skipped odd/MOCK"))
+
+	// The exported file imports back without changes.
+	result, err := service.ImportZone(ctx, domain.ID, zone.Content, dnsSvc.ZoneSyncOptions{DryRun: true, DeleteMissing: true})
+	require.NoError(t, err)
+	require.Empty(t, result.Changes)
+	require.Equal(t, 4, result.Unchanged)
+}
+
+func TestImportZoneDiff(t *testing.T) {
+	registerMockProvider()
+	records := []dnsSvc.Record{
+		{ID: "1", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+		{ID: "2", Type: "A", Name: "www", Content: "192.0.2.9", TTL: 300},
+		{ID: "3", Type: "A", Name: "old", Content: "192.0.2.5", TTL: 300},
+		{ID: "4", Type: "NS", Name: "@", Content: "ns.provider.net", TTL: 3600},
+	}
+	setMockRecords(records)
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createCredential(t, q).ID)
+
+	result, err := service.ImportZone(ctx, domain.ID, testZoneFile, dnsSvc.ZoneSyncOptions{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Equal(t, 1, result.Unchanged)
+	require.Len(t, result.Changes, 3)
+
+	changes := make(map[string]dnsSvc.ZoneChange)
+	for _, change := range result.Changes {
+		changes[string(change.Action)+" "+change.Name+"/"+change.Type] = change
+	}
+
+	update := changes["update www/A"]
+	require.Equal(t, "2", update.RecordID)
+	require.Equal(t, "192.0.2.2", update.After.Content)
+
+	mx := changes["add @/MX"]
+	require.Equal(t, "mail.example.com", mx.After.Content)
+	require.Equal(t, 10, *mx.After.Priority)
+	require.Equal(t, 600, mx.After.TTL)
+
+	txt := changes["add note/TXT"]
+	require.Equal(t, `hello "zone" world`, txt.After.Content)
+	require.Empty(t, getMockCreatedRecords())
+
+	setMockRecords(records)
+	result, err = service.ImportZone(ctx, domain.ID, testZoneFile, dnsSvc.ZoneSyncOptions{DeleteMissing: true})
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 4)
+	require.Equal(t, 4, result.Applied)
+	require.Zero(t, result.Failed)
+	require.Equal(t, []string{"3"}, getMockDeletedRecordIDs())
+	require.Equal(t, []string{"2"}, getMockUpdatedRecordIDs())
+	require.Len(t, getMockCreatedRecords(), 2)
+}
+
+func TestImportZoneRejectsInvalidFiles(t *testing.T) {
+	registerMockProvider()
+	setMockRecords(nil)
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	domain := createZoneDomain(t, service, createCredential(t, q).ID)
+
+	_, err := service.ImportZone(ctx, domain.ID, "www.example.org. 300 IN A 192.0.2.1\n", dnsSvc.ZoneSyncOptions{DryRun: true})
+	require.ErrorContains(t, err, "outside of example.com")
+
+	_, err = service.ImportZone(ctx, domain.ID, "www 300 IN A not-an-address\n", dnsSvc.ZoneSyncOptions{DryRun: true})
+	require.ErrorContains(t, err, "Invalid zone file")
+}
+
+func TestCopyZone(t *testing.T) {
+	registerMockProvider()
+	registerRecordSetProvider()
+	setMockRecords([]dnsSvc.Record{
+		{ID: "1", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+		{ID: "2", Type: "A", Name: "www", Content: "192.0.2.2", TTL: 300},
+		{ID: "3", Type: "TXT", Name: "@", Content: "hello", TTL: 300},
+	})
+	recordSetStore.reset([]dnsSvc.Record{
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1", TTL: 300},
+		{ID: "stale/A", Type: "A", Name: "stale", Content: "192.0.2.7", TTL: 300},
+	})
+
+	q := setupTestQuery(t)
+	ctx := context.Background()
+	service := dnsSvc.NewService()
+	source := createZoneDomain(t, service, createCredential(t, q).ID)
+	target := createRecordSetCredential(t, q)
+
+	_, err := service.CopyZone(ctx, source.ID, source.DnsCredentialID, dnsSvc.ZoneSyncOptions{DryRun: true})
+	require.ErrorIs(t, err, dnsSvc.ErrSameZoneCredential)
+
+	preview, err := service.CopyZone(ctx, source.ID, target.ID, dnsSvc.ZoneSyncOptions{DryRun: true, DeleteMissing: true})
+	require.NoError(t, err)
+	require.Zero(t, preview.DomainID)
+	require.Len(t, preview.Changes, 3)
+	require.Equal(t, dnsSvc.ZoneChangeAdd, preview.Changes[0].Action)
+	require.Equal(t, dnsSvc.ZoneChangeDelete, preview.Changes[1].Action)
+	require.Equal(t, "stale/A", preview.Changes[1].RecordID)
+	require.Equal(t, dnsSvc.ZoneChangeUpdate, preview.Changes[2].Action)
+	require.Equal(t, "www/A", preview.Changes[2].RecordID)
+	require.Equal(t, "192.0.2.1\n192.0.2.2", preview.Changes[2].After.Content)
+
+	count, err := query.DnsDomain.WithContext(ctx).Where(query.DnsDomain.DnsCredentialID.Eq(target.ID)).Count()
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	result, err := service.CopyZone(ctx, source.ID, target.ID, dnsSvc.ZoneSyncOptions{DeleteMissing: true})
+	require.NoError(t, err)
+	require.NotZero(t, result.DomainID)
+	require.Equal(t, 3, result.Applied)
+
+	copied, err := service.GetDomain(ctx, result.DomainID)
+	require.NoError(t, err)
+	require.Equal(t, "example.com", copied.Domain)
+	require.Equal(t, target.ID, copied.DnsCredentialID)
+
+	require.ElementsMatch(t, []dnsSvc.Record{
+		{ID: "www/A", Type: "A", Name: "www", Content: "192.0.2.1\n192.0.2.2", TTL: 300},
+		{ID: "@/TXT", Type: "TXT", Name: "@", Content: "hello", TTL: 300},
+	}, recordSetStore.list())
+}
+
+func createZoneDomain(tb testing.TB, service *dnsSvc.Service, credentialID uint64) *model.DnsDomain {
+	tb.Helper()
+
+	domain, err := service.CreateDomain(context.Background(), dnsSvc.DomainInput{
+		Domain:          "example.com",
+		DnsCredentialID: credentialID,
+	})
+	require.NoError(tb, err)
+	return domain
+}
+
+func createRecordSetCredential(tb testing.TB, q *query.Query) *model.DnsCredential {
+	tb.Helper()
+
+	cred := &model.DnsCredential{
+		Name:     "Record Set Credential",
+		Provider: "Record Set",
+		Config: &certdns.Config{
+			Code: "mock-recordset",
+			Configuration: &certdns.Configuration{
+				Credentials: map[string]string{"TOKEN": "bar"},
+			},
+		},
+	}
+	require.NoError(tb, q.DnsCredential.Create(cred))
+	return cred
+}
+
+var registerRecordSetOnce sync.Once
+
+func registerRecordSetProvider() {
+	registerRecordSetOnce.Do(func() {
+		dnsSvc.RegisterProvider("mock-recordset", func(*dnsSvc.Credential) (dnsSvc.Provider, error) {
+			return recordSetStore, nil
+		})
+	})
+}
+
+var recordSetStore = &recordSetProvider{}
+
+// recordSetProvider keeps one record per name and type, like the providers
+// implementing dns.RecordSetProvider.
+type recordSetProvider struct {
+	mu      sync.Mutex
+	records []dnsSvc.Record
+}
+
+func (p *recordSetProvider) reset(records []dnsSvc.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records = append([]dnsSvc.Record(nil), records...)
+}
+
+func (p *recordSetProvider) list() []dnsSvc.Record {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]dnsSvc.Record(nil), p.records...)
+}
+
+func (p *recordSetProvider) ManagesRecordSets() bool {
+	return true
+}
+
+func (p *recordSetProvider) ListRecords(ctx context.Context, domain string, filter dnsSvc.RecordFilter) ([]dnsSvc.Record, error) {
+	return p.list(), nil
+}
+
+func (p *recordSetProvider) CreateRecord(ctx context.Context, domain string, input dnsSvc.RecordInput) (dnsSvc.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	record := recordSetFromInput(input)
+	p.records = append(p.records, record)
+	return record, nil
+}
+
+func (p *recordSetProvider) UpdateRecord(ctx context.Context, domain string, recordID string, input dnsSvc.RecordInput) (dnsSvc.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	record := recordSetFromInput(input)
+	for i := range p.records {
+		if p.records[i].ID == recordID {
+			p.records[i] = record
+		}
+	}
+	return record, nil
+}
+
+func (p *recordSetProvider) DeleteRecord(ctx context.Context, domain string, recordID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	kept := p.records[:0]
+	for _, record := range p.records {
+		if record.ID != recordID {
+			kept = append(kept, record)
+		}
+	}
+	p.records = kept
+	return nil
+}
+
+func recordSetFromInput(input dnsSvc.RecordInput) dnsSvc.Record {
+	return dnsSvc.Record{
+		ID:      dnsSvc.RecordSetID(input.Name, input.Type),
+		Type:    input.Type,
+		Name:    input.Name,
+		Content: input.Content,
+		TTL:     input.TTL,
+	}
+}
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/docker/docker/api/types/container"
+	"github.com/uozi-tech/cosy"
+)
+
+// File is a file copied into a container.
+type File struct {
+	Name    string
+	Content []byte
+	Mode    int64
+	UID     int
+	GID     int
+}
+
+// CopyToContainer writes files into a directory of a container, which
+// defaults to the nginx container.
+func CopyToContainer(ctx context.Context, containerName, dir string, files []File) error {
+	if containerName == "" {
+		if !settings.NginxSettings.RunningInAnotherContainer() {
+			return ErrNginxNotRunningInAnotherContainer
+		}
+		containerName = settings.NginxSettings.ContainerName
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	now := time.Now()
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    file.Name,
+			Mode:    file.Mode,
+			Size:    int64(len(file.Content)),
+			Uid:     file.UID,
+			Gid:     file.GID,
+			ModTime: now,
+		}); err != nil {
+			return cosy.WrapErrorWithParams(ErrFailedToCopyToContainer, err.Error())
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return cosy.WrapErrorWithParams(ErrFailedToCopyToContainer, err.Error())
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return cosy.WrapErrorWithParams(ErrFailedToCopyToContainer, err.Error())
+	}
+
+	cli, err := initClient()
+	if err != nil {
+		return cosy.WrapErrorWithParams(ErrClientNotInitialized, err.Error())
+	}
+	defer cli.Close()
+
+	if err := cli.CopyToContainer(ctx, containerName, dir, &archive, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	}); err != nil {
+		return cosy.WrapErrorWithParams(ErrFailedToCopyToContainer, err.Error())
+	}
+	return nil
+}
@@ -19,4 +19,5 @@ var (
 	ErrFailedToStartTempContainer        = e.New(500013, "failed to start temp container: {0}")
 	ErrOldContainerNameNotFound          = e.New(500014, "could not find old container name")
 	ErrTempContainerNotFound             = e.New(500015, "could not find temp container")
+	ErrFailedToCopyToContainer           = e.New(500016, "failed to copy files to container: {0}")
 )
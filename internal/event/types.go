@@ -14,7 +14,11 @@ const (
 	TypeNginxLogIndexProgress Type = "nginx_log_index_progress"
 	TypeNginxLogIndexComplete Type = "nginx_log_index_complete"
 
+	TypeNginxLogExportProgress Type = "nginx_log_export_progress"
+
 	TypeNotification Type = "notification"
+
+	TypeSiteDeployment Type = "site_deployment"
 )
 
 // Event represents a generic event structure
@@ -63,3 +67,24 @@ type NginxLogIndexCompleteData struct {
 	IndexedSize int64  `json:"indexed_size"` // bytes
 	Error       string `json:"error,omitempty"`
 }
+
+// NginxLogExportProgressData represents the progress of a background log export
+type NginxLogExportProgressData struct {
+	ID       string  `json:"id"`
+	Status   string  `json:"status"` // "running", "completed", "failed", "cancelled"
+	Written  uint64  `json:"written"`
+	Total    uint64  `json:"total"`
+	Progress float64 `json:"progress"` // 0-100 percentage
+	Error    string  `json:"error,omitempty"`
+}
+
+// SiteDeploymentData represents the progress of a site deployment
+type SiteDeploymentData struct {
+	ID       uint64 `json:"id"`
+	SiteName string `json:"site_name"`
+	Status   string `json:"status"`
+	Step     int    `json:"step"`
+	Steps    int    `json:"steps"`
+	Weight   int    `json:"weight"` // percentage of traffic sent to the new target
+	Message  string `json:"message,omitempty"`
+}
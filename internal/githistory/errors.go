@@ -0,0 +1,14 @@
+package githistory
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                       = cosy.NewErrorScope("git_history")
+	ErrDisabled             = e.New(40001, "git history is disabled")
+	ErrInvalidRevision      = e.New(40002, "invalid revision: {0}")
+	ErrInvalidPath          = e.New(40003, "path is not under the nginx configuration directory: {0}")
+	ErrGitNotFound          = e.New(50001, "git executable not found")
+	ErrGitCommand           = e.New(50002, "git {0} failed: {1}")
+	ErrRollbackTestFailed   = e.New(50003, "nginx test failed, the rollback was reverted: {0}")
+	ErrRollbackReloadFailed = e.New(50004, "nginx reload failed, the rollback was reverted: {0}")
+)
@@ -0,0 +1,51 @@
+package githistory
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// excludePatterns keep key material out of the history, which everyone
+// allowed to read configs can browse. Certificates, their private keys and the
+// private certificate authorities all live below ssl/.
+const excludePatterns = "/ssl/\n*.key\n"
+
+// writeExcludes installs excludePatterns as info/exclude of the repository.
+// A repository created before the patterns existed stops tracking the files
+// they match, so they are not committed again.
+func writeExcludes() error {
+	excludePath := filepath.Join(repositoryPath(), "info", "exclude")
+	current, err := os.ReadFile(excludePath)
+	if err == nil && string(current) == excludePatterns {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(excludePath, []byte(excludePatterns), 0600); err != nil {
+		return err
+	}
+	if _, err := git("rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--",
+		":(glob)ssl/**", ":(glob)**/*.key"); err != nil {
+		return err
+	}
+
+	_, code, err := gitWithExitCode("diff", "--cached", "--quiet")
+	switch {
+	case code == 0:
+		return nil
+	case code != 1:
+		return err
+	}
+	_, err = git("commit", "--quiet", "--author="+SystemAuthor.String(), "--message=Stop tracking key material")
+	return err
+}
+
+// excluded reports whether a path relative to the work tree matches
+// excludePatterns.
+func excluded(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return rel == "ssl" || strings.HasPrefix(rel, "ssl/") || path.Ext(rel) == ".key"
+}
@@ -0,0 +1,75 @@
+package githistory
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy"
+	cSettings "github.com/uozi-tech/cosy/settings"
+)
+
+const defaultRepositoryName = "config-history.git"
+
+// repositoryPath returns the git directory. The repository uses the nginx
+// configuration directory as its work tree but keeps its metadata elsewhere,
+// so nginx never sees a .git directory among its includes.
+func repositoryPath() string {
+	if settings.GitHistorySettings.RepositoryPath != "" {
+		return settings.GitHistorySettings.RepositoryPath
+	}
+	if cSettings.ConfPath != "" {
+		return filepath.Join(filepath.Dir(cSettings.ConfPath), defaultRepositoryName)
+	}
+	return defaultRepositoryName
+}
+
+// git runs a git command against the history repository and returns its
+// standard output.
+func git(args ...string) (string, error) {
+	output, _, err := gitWithExitCode(args...)
+	return output, err
+}
+
+// gitWithExitCode is git for commands that report a result through their exit
+// code, such as diff --quiet.
+func gitWithExitCode(args ...string) (string, int, error) {
+	binary, err := exec.LookPath("git")
+	if err != nil {
+		return "", -1, ErrGitNotFound
+	}
+
+	fullArgs := append([]string{
+		"--git-dir", repositoryPath(),
+		"--work-tree", nginx.GetConfPath(),
+		"-c", "user.name=Nginx UI",
+		"-c", "user.email=nginx-ui@localhost",
+		"-c", "core.quotepath=off",
+	}, args...)
+
+	cmd := exec.Command(binary, fullArgs...)
+	cmd.Dir = nginx.GetConfPath()
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = exitErr.Error()
+		}
+		return stdout.String(), exitErr.ExitCode(), cosy.WrapErrorWithParams(ErrGitCommand, args[0], message)
+	}
+	if err != nil {
+		return stdout.String(), -1, cosy.WrapErrorWithParams(ErrGitCommand, args[0], err.Error())
+	}
+	return stdout.String(), 0, nil
+}
@@ -0,0 +1,376 @@
+package githistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// mutex serializes every git invocation, since the index of the repository
+// cannot be shared by concurrent commands.
+var mutex sync.Mutex
+
+// revisionPattern accepts commit hashes and HEAD~n only, which also keeps
+// user input from being read as a git option.
+var revisionPattern = regexp.MustCompile(`^([0-9a-fA-F]{4,64}|HEAD(~[0-9]+)?)$`)
+
+// Author is the identity recorded on a commit.
+type Author struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+var (
+	SystemAuthor      = Author{Name: "Nginx UI", Email: "nginx-ui@localhost"}
+	ClusterSyncAuthor = Author{Name: "Cluster Sync", Email: "cluster-sync@localhost"}
+	MCPAuthor         = Author{Name: "MCP", Email: "mcp@localhost"}
+)
+
+// UserAuthor returns the author recorded for changes made by u.
+func UserAuthor(u *model.User) Author {
+	if u == nil {
+		return SystemAuthor
+	}
+	return Author{Name: u.Name, Email: fmt.Sprintf("user-%d@nginx-ui", u.ID)}
+}
+
+func (a Author) String() string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+// Commit is one entry of the configuration history.
+type Commit struct {
+	Hash        string    `json:"hash"`
+	ShortHash   string    `json:"short_hash"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	Time        time.Time `json:"time"`
+	Message     string    `json:"message"`
+}
+
+// Enabled reports whether saves are recorded in git.
+func Enabled() bool {
+	return settings.GitHistorySettings.Enabled
+}
+
+// Init creates the repository and commits the current configuration when it
+// does not exist yet.
+func Init() error {
+	if !Enabled() {
+		return ErrDisabled
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return ensureRepository()
+}
+
+// Record commits the given files and logs instead of failing, so a broken
+// history never blocks a configuration save. It does nothing when the git
+// history is disabled.
+func Record(author Author, message string, paths ...string) {
+	if !Enabled() {
+		return
+	}
+	if err := CommitFiles(author, message, paths...); err != nil {
+		logger.Error("Failed to record configuration history:", err)
+	}
+}
+
+// CommitFiles commits the current content of paths, or of the whole
+// configuration directory when no path is given. Files that were deleted are
+// recorded as deletions.
+func CommitFiles(author Author, message string, paths ...string) error {
+	if !Enabled() {
+		return ErrDisabled
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := ensureRepository(); err != nil {
+		return err
+	}
+	return commitLocked(author, message, paths...)
+}
+
+// Log lists the commits touching path, or the whole tree when path is empty,
+// newest first.
+func Log(path string, limit, offset int) ([]Commit, error) {
+	if !Enabled() {
+		return nil, ErrDisabled
+	}
+
+	args := []string{"log", "--format=%H%x1f%h%x1f%an%x1f%ae%x1f%at%x1f%s%x1e"}
+	if limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		args = append(args, "--skip="+strconv.Itoa(offset))
+	}
+	if path != "" {
+		rel, err := relativePaths([]string{path})
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--")
+		args = append(args, rel...)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := ensureRepository(); err != nil {
+		return nil, err
+	}
+	output, err := git(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output), nil
+}
+
+// Show returns the unified diff a commit introduced, limited to path when it
+// is not empty.
+func Show(revision, path string) (string, error) {
+	if err := validateRevision(revision); err != nil {
+		return "", err
+	}
+	return run("show", []string{"--format=", "--patch", "--no-color", revision}, path)
+}
+
+// Diff returns the unified diff between two revisions, limited to path when
+// it is not empty. An empty to compares from against the files on disk.
+func Diff(from, to, path string) (string, error) {
+	if err := validateRevision(from); err != nil {
+		return "", err
+	}
+	args := []string{"--no-color", from}
+	if to != "" {
+		if err := validateRevision(to); err != nil {
+			return "", err
+		}
+		args = append(args, to)
+	}
+	return run("diff", args, path)
+}
+
+// Rollback restores the whole configuration directory to revision. Any
+// uncommitted change is committed first so it is never lost, and the tree is
+// put back when nginx rejects the restored configuration.
+func Rollback(revision string, author Author) (*Commit, error) {
+	if !Enabled() {
+		return nil, ErrDisabled
+	}
+	if err := validateRevision(revision); err != nil {
+		return nil, err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := ensureRepository(); err != nil {
+		return nil, err
+	}
+
+	target, err := git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidRevision, revision)
+	}
+	target = strings.TrimSpace(target)
+	short := target[:min(len(target), 7)]
+
+	if err := commitLocked(author, "Snapshot before rolling back to "+short); err != nil {
+		return nil, err
+	}
+	head, err := git("rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	head = strings.TrimSpace(head)
+
+	if _, err := git("read-tree", "-u", "--reset", target); err != nil {
+		return nil, err
+	}
+
+	revert := func() {
+		if _, err := git("read-tree", "-u", "--reset", head); err != nil {
+			logger.Error("Failed to revert configuration rollback:", err)
+		}
+	}
+
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		revert()
+		return nil, cosy.WrapErrorWithParams(ErrRollbackTestFailed, result.GetOutput())
+	}
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		revert()
+		nginx.Control(nginx.Reload)
+		return nil, cosy.WrapErrorWithParams(ErrRollbackReloadFailed, result.GetOutput())
+	}
+
+	subject, err := git("log", "-1", "--format=%s", target)
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("Roll back to %s: %s", short, strings.TrimSpace(subject))
+	if err := commitLocked(author, message); err != nil {
+		return nil, err
+	}
+
+	output, err := git("log", "-1", "--format=%H%x1f%h%x1f%an%x1f%ae%x1f%at%x1f%s%x1e")
+	if err != nil {
+		return nil, err
+	}
+	commits := parseLog(output)
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	return &commits[0], nil
+}
+
+func run(command string, args []string, path string) (string, error) {
+	if !Enabled() {
+		return "", ErrDisabled
+	}
+	args = append([]string{command}, args...)
+	if path != "" {
+		rel, err := relativePaths([]string{path})
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--")
+		args = append(args, rel...)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := ensureRepository(); err != nil {
+		return "", err
+	}
+	return git(args...)
+}
+
+// ensureRepository creates the repository on first use and records the
+// current configuration as its first commit.
+func ensureRepository() error {
+	if helper.FileExists(filepath.Join(repositoryPath(), "HEAD")) {
+		return writeExcludes()
+	}
+	if err := os.MkdirAll(repositoryPath(), 0700); err != nil {
+		return err
+	}
+	if _, err := git("init", "--quiet"); err != nil {
+		return err
+	}
+	if err := writeExcludes(); err != nil {
+		return err
+	}
+	if _, err := git("add", "--all"); err != nil {
+		return err
+	}
+	_, err := git("commit", "--quiet", "--allow-empty", "--author="+SystemAuthor.String(),
+		"--message=Initial configuration snapshot")
+	return err
+}
+
+func commitLocked(author Author, message string, paths ...string) error {
+	rel, err := relativePaths(paths)
+	if err != nil {
+		return err
+	}
+	// git refuses to add an excluded file that is named explicitly.
+	rel = slices.DeleteFunc(rel, func(pathspec string) bool {
+		return excluded(strings.TrimPrefix(pathspec, ":(literal)"))
+	})
+	if len(rel) == 0 {
+		return nil
+	}
+
+	if _, err := git(append([]string{"add", "--all", "--"}, rel...)...); err != nil {
+		return err
+	}
+
+	_, code, err := gitWithExitCode(append([]string{"diff", "--cached", "--quiet", "--"}, rel...)...)
+	switch {
+	case code == 0:
+		// Nothing changed since the last commit.
+		return nil
+	case code != 1:
+		return err
+	}
+
+	_, err = git(append([]string{"commit", "--quiet", "--author=" + author.String(), "--message=" + message, "--"}, rel...)...)
+	return err
+}
+
+// relativePaths converts paths, absolute or relative to the nginx
+// configuration directory, into pathspecs of the work tree.
+func relativePaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return []string{"."}, nil
+	}
+
+	confPath := nginx.GetConfPath()
+	rel := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(confPath, path)
+		}
+		path = filepath.Clean(path)
+		relPath, err := filepath.Rel(confPath, path)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return nil, cosy.WrapErrorWithParams(ErrInvalidPath, path)
+		}
+		// Pathspec magic such as :(glob) must not be interpreted.
+		rel = append(rel, ":(literal)"+filepath.ToSlash(relPath))
+	}
+	return rel, nil
+}
+
+func validateRevision(revision string) error {
+	if !revisionPattern.MatchString(revision) {
+		return cosy.WrapErrorWithParams(ErrInvalidRevision, revision)
+	}
+	return nil
+}
+
+func parseLog(output string) []Commit {
+	records := strings.Split(output, "\x1e")
+	commits := make([]Commit, 0, len(records))
+	for _, record := range records {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 6 {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			logger.Errorf("unexpected git log timestamp %q: %v", fields[4], err)
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:        fields[0],
+			ShortHash:   fields[1],
+			AuthorName:  fields[2],
+			AuthorEmail: fields[3],
+			Time:        time.Unix(timestamp, 0),
+			Message:     fields[5],
+		})
+	}
+	return commits
+}
@@ -0,0 +1,156 @@
+package githistory
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withHistory(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	originalHistory := *settings.GitHistorySettings
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+		*settings.GitHistorySettings = originalHistory
+	})
+
+	confDir := t.TempDir()
+	settings.NginxSettings.ConfigDir = confDir
+	settings.GitHistorySettings.Enabled = true
+	settings.GitHistorySettings.RepositoryPath = filepath.Join(t.TempDir(), "history.git")
+	return confDir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestCommitFilesRecordsAuthorAndDiff(t *testing.T) {
+	confDir := withHistory(t)
+	site := filepath.Join(confDir, "sites-available", "example.com")
+	writeFile(t, site, "listen 80;\n")
+
+	author := Author{Name: "alice", Email: "user-2@nginx-ui"}
+	require.NoError(t, CommitFiles(author, "Initial site"))
+
+	writeFile(t, site, "listen 8080;\n")
+	writeFile(t, filepath.Join(confDir, "unrelated.conf"), "# untouched\n")
+	require.NoError(t, CommitFiles(author, "Save site example.com", site))
+
+	commits, err := Log(site, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "Save site example.com", commits[0].Message)
+	assert.Equal(t, "alice", commits[0].AuthorName)
+	assert.Equal(t, "user-2@nginx-ui", commits[0].AuthorEmail)
+
+	diff, err := Show(commits[0].Hash, "")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "-listen 80;")
+	assert.Contains(t, diff, "+listen 8080;")
+	assert.NotContains(t, diff, "unrelated.conf", "only the saved file is committed")
+
+	diff, err = Diff(commits[1].Hash, "", "")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+listen 8080;")
+}
+
+func TestCommitFilesSkipsUnchangedFiles(t *testing.T) {
+	confDir := withHistory(t)
+	path := filepath.Join(confDir, "nginx.conf")
+	writeFile(t, path, "events {}\n")
+
+	require.NoError(t, CommitFiles(SystemAuthor, "first", path))
+	require.NoError(t, CommitFiles(SystemAuthor, "second", path))
+
+	commits, err := Log("", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "Initial configuration snapshot", commits[0].Message)
+}
+
+func TestRollbackRevertsWhenNginxTestFails(t *testing.T) {
+	confDir := withHistory(t)
+	path := filepath.Join(confDir, "nginx.conf")
+	writeFile(t, path, "events {}\n")
+	require.NoError(t, CommitFiles(SystemAuthor, "first"))
+	commits, err := Log("", 1, 0)
+	require.NoError(t, err)
+
+	writeFile(t, path, "events { worker_connections 512; }\n")
+	settings.NginxSettings.TestConfigCmd = "false"
+
+	_, err = Rollback(commits[0].Hash, SystemAuthor)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "events { worker_connections 512; }\n", string(content))
+}
+
+func TestRejectsInvalidInput(t *testing.T) {
+	withHistory(t)
+
+	_, err := Show("--output=/tmp/x", "")
+	assert.Error(t, err)
+	_, err = Diff("HEAD", "", "../outside")
+	assert.Error(t, err)
+
+	settings.GitHistorySettings.Enabled = false
+	_, err = Log("", 10, 0)
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestKeyMaterialIsNeverCommitted(t *testing.T) {
+	confDir := withHistory(t)
+	writeFile(t, filepath.Join(confDir, "nginx.conf"), "events {}\n")
+	writeFile(t, filepath.Join(confDir, "ssl", "example.com_P256", "private.key"), "secret\n")
+	writeFile(t, filepath.Join(confDir, "ssl", "private_ca", "1", "ca.pem"), "ca\n")
+	writeFile(t, filepath.Join(confDir, "conf.d", "client.key"), "secret\n")
+	require.NoError(t, Init())
+
+	keyPath := filepath.Join(confDir, "conf.d", "client.key")
+	writeFile(t, keyPath, "rotated\n")
+	require.NoError(t, CommitFiles(SystemAuthor, "Save key", keyPath))
+	require.NoError(t, CommitFiles(SystemAuthor, "Save everything"))
+
+	files, err := git("ls-files")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx.conf\n", files)
+}
+
+func TestExistingRepositoryStopsTrackingKeyMaterial(t *testing.T) {
+	confDir := withHistory(t)
+	writeFile(t, filepath.Join(confDir, "nginx.conf"), "events {}\n")
+	writeFile(t, filepath.Join(confDir, "ssl", "example.com_P256", "private.key"), "secret\n")
+
+	// A repository created before the exclusions existed tracks everything.
+	require.NoError(t, os.MkdirAll(settings.GitHistorySettings.RepositoryPath, 0700))
+	_, err := git("init", "--quiet")
+	require.NoError(t, err)
+	_, err = git("add", "--all")
+	require.NoError(t, err)
+	_, err = git("commit", "--quiet", "--message=Initial configuration snapshot")
+	require.NoError(t, err)
+
+	require.NoError(t, CommitFiles(SystemAuthor, "Save nginx.conf", filepath.Join(confDir, "nginx.conf")))
+
+	files, err := git("ls-files")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx.conf\n", files)
+	assert.FileExists(t, filepath.Join(confDir, "ssl", "example.com_P256", "private.key"))
+}
@@ -0,0 +1,17 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	diff, err := UnifiedDiff("example.conf", "listen 80;\n", "listen 8080;\n")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- a/example.conf")
+	assert.Contains(t, diff, "+++ b/example.conf")
+	assert.Contains(t, diff, "-listen 80;")
+	assert.Contains(t, diff, "+listen 8080;")
+}
@@ -1,6 +1,15 @@
 package helper
 
-import "github.com/go-acme/lego/v5/certcrypto"
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"strconv"
+	"strings"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+)
 
 const (
 	legacyEC256   certcrypto.KeyType = "P256"
@@ -73,3 +82,29 @@ func IsValidKeyType(keyType certcrypto.KeyType) bool {
 	}
 	return false
 }
+
+// PublicKeyType returns the key type of a public key, named like the
+// supported ones (RSA2048, EC256) even when it is not one of them.
+func PublicKeyType(publicKey crypto.PublicKey) certcrypto.KeyType {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return certcrypto.KeyType("RSA" + strconv.Itoa(key.N.BitLen()))
+	case *ecdsa.PublicKey:
+		return certcrypto.KeyType("EC" + strconv.Itoa(key.Curve.Params().BitSize))
+	case ed25519.PublicKey:
+		return "ED25519"
+	}
+	return ""
+}
+
+// IsWeakKeyType reports whether a key type returned by PublicKeyType is
+// weaker than the weakest supported RSA or EC key type.
+func IsWeakKeyType(keyType certcrypto.KeyType) bool {
+	name := string(keyType)
+	for prefix, minBits := range map[string]int{"RSA": 2048, "EC": 256} {
+		if bits, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); strings.HasPrefix(name, prefix) && err == nil {
+			return bits < minBits
+		}
+	}
+	return keyType == ""
+}
@@ -1,10 +1,15 @@
 package helper
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 
 	"github.com/go-acme/lego/v5/certcrypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetKeyTypeSupportsLegacyLegoV4Values(t *testing.T) {
@@ -37,3 +42,24 @@ func TestGetKeyTypeAliasesIncludesLegacyAndCurrentValues(t *testing.T) {
 	assert.ElementsMatch(t, []certcrypto.KeyType{certcrypto.RSA4096, "4096"}, GetKeyTypeAliases(certcrypto.RSA4096))
 	assert.ElementsMatch(t, []certcrypto.KeyType{certcrypto.RSA8192, "8192"}, GetKeyTypeAliases("8192"))
 }
+
+func TestPublicKeyType(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	weakECKey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, certcrypto.KeyType("RSA1024"), PublicKeyType(&rsaKey.PublicKey))
+	assert.Equal(t, certcrypto.EC256, PublicKeyType(&ecKey.PublicKey))
+	assert.Equal(t, certcrypto.KeyType("EC224"), PublicKeyType(&weakECKey.PublicKey))
+
+	assert.True(t, IsWeakKeyType("RSA1024"))
+	assert.True(t, IsWeakKeyType("EC224"))
+	assert.True(t, IsWeakKeyType(""))
+	assert.False(t, IsWeakKeyType(certcrypto.RSA2048))
+	assert.False(t, IsWeakKeyType(certcrypto.EC256))
+	assert.False(t, IsWeakKeyType("EC521"))
+	assert.False(t, IsWeakKeyType("ED25519"))
+}
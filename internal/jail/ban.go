@@ -0,0 +1,115 @@
+package jail
+
+import (
+	"errors"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+var jailNow = time.Now
+
+// ActiveBans limits a query to bans that have not expired yet.
+func ActiveBans(db *gorm.DB) *gorm.DB {
+	return db.Where("expires_at IS NULL OR expires_at > ?", jailNow())
+}
+
+// activeBan is the ActiveBans condition for the generated queries.
+func activeBan() gen.Condition {
+	b := query.JailBan
+	return b.Where(b.ExpiresAt.IsNull()).Or(b.ExpiresAt.Gt(jailNow()))
+}
+
+// Ban denies ip in the scope of the jail, keeping the matched lines as
+// evidence. A client that is already banned in that scope is left alone.
+func Ban(jail *model.Jail, ip string, hits []hit) (*model.JailBan, error) {
+	now := jailNow()
+	b := query.JailBan
+
+	existing, err := b.Where(activeBan(), b.IP.Eq(ip), b.SiteName.Eq(jail.SiteName)).Limit(1).Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return existing[0], nil
+	}
+
+	ban := &model.JailBan{
+		JailID:   jail.ID,
+		IP:       ip,
+		SiteName: jail.SiteName,
+		Hits:     len(hits),
+		Evidence: evidence(hits),
+	}
+	if jail.BanTimeSeconds > 0 {
+		expiresAt := now.Add(time.Duration(jail.BanTimeSeconds) * time.Second)
+		ban.ExpiresAt = &expiresAt
+	}
+	if err := b.Create(ban); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Jail %s banned %s after %d matches", jail.Name, ip, ban.Hits)
+	scheduleSync(jail.SiteName)
+	return ban, nil
+}
+
+// Unban lifts a ban and rewrites its deny list right away.
+func Unban(id uint64) error {
+	b := query.JailBan
+	ban, err := b.Where(b.ID.Eq(id)).First()
+	if err != nil {
+		return err
+	}
+	if _, err := b.Delete(ban); err != nil {
+		return err
+	}
+	return Sync(ban.SiteName)
+}
+
+// ExpireBans removes the bans whose ban time is over and rewrites the deny
+// lists they were part of.
+func ExpireBans() (int, error) {
+	b := query.JailBan
+	expired, err := b.Where(b.ExpiresAt.IsNotNull(), b.ExpiresAt.Lte(jailNow())).Find()
+	if err != nil {
+		return 0, err
+	}
+	return len(expired), deleteBans(expired)
+}
+
+// RemoveJailBans lifts all bans of a jail, used when the jail is deleted.
+func RemoveJailBans(jailID uint64) error {
+	b := query.JailBan
+	bans, err := b.Where(b.JailID.Eq(jailID)).Find()
+	if err != nil {
+		return err
+	}
+	return deleteBans(bans)
+}
+
+func deleteBans(bans []*model.JailBan) error {
+	if len(bans) == 0 {
+		return nil
+	}
+	if _, err := query.JailBan.Delete(bans...); err != nil {
+		return err
+	}
+
+	var errs []error
+	synced := map[string]bool{}
+	for _, ban := range bans {
+		if synced[ban.SiteName] {
+			continue
+		}
+		synced[ban.SiteName] = true
+		if err := Sync(ban.SiteName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,128 @@
+package jail
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+const denyListHeader = "# Managed by Nginx UI jails, changes will be overwritten.\n"
+
+var (
+	syncMutex sync.Mutex
+
+	// syncDelay batches the bans of a burst into a single reload.
+	syncDelay   = 2 * time.Second
+	pendingMu   sync.Mutex
+	pendingSync = map[string]bool{}
+)
+
+// DenyListPath returns the deny include of a scope. The global list lives in
+// conf.d, which the http block includes, so it applies without further
+// changes. A site list has to be included in the server block of the site.
+//
+// nginx only inherits access rules into blocks without allow or deny rules of
+// their own, so a server or location that has them needs the include as well.
+func DenyListPath(siteName string) string {
+	if siteName == "" {
+		return nginx.GetConfPath("conf.d", "nginx-ui-jail.conf")
+	}
+	return nginx.GetConfPath("jail", siteName+".conf")
+}
+
+// IncludeDirective returns the directive that applies the deny list of a
+// scope.
+func IncludeDirective(siteName string) string {
+	return fmt.Sprintf("include %s;", DenyListPath(siteName))
+}
+
+func renderDenyList(bans []*model.JailBan) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(denyListHeader)
+	seen := map[string]bool{}
+	for _, ban := range bans {
+		if seen[ban.IP] {
+			continue
+		}
+		seen[ban.IP] = true
+		fmt.Fprintf(&buf, "deny %s;\n", ban.IP)
+	}
+	return buf.Bytes()
+}
+
+// Sync rewrites the deny list of a scope from the active bans and reloads
+// nginx. When nginx rejects the new list the previous one is restored.
+func Sync(siteName string) error {
+	syncMutex.Lock()
+	defer syncMutex.Unlock()
+
+	b := query.JailBan
+	bans, err := b.Where(activeBan(), b.SiteName.Eq(siteName)).Order(b.IP).Find()
+	if err != nil {
+		return err
+	}
+
+	path := DenyListPath(siteName)
+	content := renderDenyList(bans)
+	previous, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if existed && bytes.Equal(previous, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		if existed {
+			err = os.WriteFile(path, previous, 0644)
+		} else {
+			err = os.Remove(path)
+		}
+		if err != nil {
+			logger.Errorf("Failed to restore jail deny list %s: %v", path, err)
+		}
+		return cosy.WrapErrorWithParams(ErrTestFailed, result.GetOutput())
+	}
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		return cosy.WrapErrorWithParams(ErrReloadFailed, result.GetOutput())
+	}
+	return nil
+}
+
+// scheduleSync syncs a scope after syncDelay, unless a sync of it is already
+// pending.
+func scheduleSync(siteName string) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	if pendingSync[siteName] {
+		return
+	}
+	pendingSync[siteName] = true
+
+	time.AfterFunc(syncDelay, func() {
+		pendingMu.Lock()
+		delete(pendingSync, siteName)
+		pendingMu.Unlock()
+
+		if err := Sync(siteName); err != nil {
+			logger.Errorf("Failed to sync jail deny list of %q: %v", siteName, err)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package jail
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                  = cosy.NewErrorScope("jail")
+	ErrEmptyFilter     = e.New(40001, "a jail needs at least one filter condition")
+	ErrInvalidPattern  = e.New(40002, "invalid {0}: {1}")
+	ErrInvalidLogPath  = e.New(40003, "log path is not under the whitelist: {0}")
+	ErrInvalidLogType  = e.New(40004, "unknown log type: {0}")
+	ErrAccessOnly      = e.New(40005, "{0} can only be matched in access logs")
+	ErrInvalidSiteName = e.New(40006, "invalid site name: {0}")
+	ErrInvalidIgnoreIP = e.New(40007, "invalid ignore IP: {0}")
+	ErrInvalidValue    = e.New(40008, "{0} must be positive")
+	ErrTestFailed      = e.New(50001, "nginx test failed, the deny list was restored: {0}")
+	ErrReloadFailed    = e.New(50002, "nginx reload failed: {0}")
+)
@@ -0,0 +1,103 @@
+package jail
+
+import (
+	"net/netip"
+	"regexp"
+	"slices"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+// errorLogClientRegexp extracts the client address nginx appends to error log
+// entries, e.g. `..., client: 203.0.113.7, server: example.com, ...`.
+var errorLogClientRegexp = regexp.MustCompile(`client: ([^,\s]+)`)
+
+var accessLogParser = parser.NewParser(&parser.Config{
+	TimeLayout:    parser.DefaultParserConfig().TimeLayout,
+	StrictMode:    true,
+	MaxLineLength: parser.DefaultParserConfig().MaxLineLength,
+}, nil, nil)
+
+// filter decides whether a log line counts as a failure of a client.
+type filter struct {
+	jail      *model.Jail
+	statuses  []int
+	path      *regexp.Regexp
+	userAgent *regexp.Regexp
+	line      *regexp.Regexp
+	ignore    []netip.Prefix
+}
+
+func newFilter(jail *model.Jail) (*filter, error) {
+	f := &filter{jail: jail, statuses: jail.StatusCodes}
+
+	var err error
+	for _, pattern := range []struct {
+		value string
+		re    **regexp.Regexp
+	}{
+		{jail.PathPattern, &f.path},
+		{jail.UserAgentPattern, &f.userAgent},
+		{jail.LinePattern, &f.line},
+	} {
+		if pattern.value == "" {
+			continue
+		}
+		if *pattern.re, err = regexp.Compile(pattern.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.ignore, err = parseIgnoreIPs(jail.IgnoreIPs); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// match returns the client address of a line when every condition of the
+// jail matches it. Loopback and ignored addresses never match.
+func (f *filter) match(line string) (string, bool) {
+	if f.line != nil && !f.line.MatchString(line) {
+		return "", false
+	}
+
+	var ip string
+	if f.jail.LogType == model.JailLogTypeError {
+		m := errorLogClientRegexp.FindStringSubmatch(line)
+		if m == nil {
+			return "", false
+		}
+		ip = m[1]
+	} else {
+		entry, err := accessLogParser.ParseLine(line)
+		if err != nil {
+			return "", false
+		}
+		if len(f.statuses) > 0 && !slices.Contains(f.statuses, entry.Status) {
+			return "", false
+		}
+		if f.path != nil && !f.path.MatchString(entry.Path) {
+			return "", false
+		}
+		if f.userAgent != nil && !f.userAgent.MatchString(entry.UserAgent) {
+			return "", false
+		}
+		ip = entry.IP
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", false
+	}
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsUnspecified() {
+		return "", false
+	}
+	for _, prefix := range f.ignore {
+		if prefix.Contains(addr) {
+			return "", false
+		}
+	}
+	return addr.String(), true
+}
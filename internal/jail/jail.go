@@ -0,0 +1,133 @@
+package jail
+
+import (
+	"net/netip"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+const (
+	defaultMaxRetry        = 5
+	defaultFindTimeSeconds = 600
+	defaultBanTimeSeconds  = 3600
+)
+
+// NormalizedColumns lists the columns Normalize may fill in, so a partial
+// update can persist the defaults along with the changed fields.
+var NormalizedColumns = []string{
+	"log_type",
+	"max_retry",
+	"find_time_seconds",
+	"ban_time_seconds",
+}
+
+// Normalize validates a jail and fills in the defaults of unset fields.
+func Normalize(jail *model.Jail) error {
+	switch jail.LogType {
+	case "":
+		jail.LogType = model.JailLogTypeAccess
+	case model.JailLogTypeAccess, model.JailLogTypeError:
+	default:
+		return cosy.WrapErrorWithParams(ErrInvalidLogType, string(jail.LogType))
+	}
+
+	if jail.LogType == model.JailLogTypeError {
+		switch {
+		case len(jail.StatusCodes) > 0:
+			return cosy.WrapErrorWithParams(ErrAccessOnly, "status_codes")
+		case jail.PathPattern != "":
+			return cosy.WrapErrorWithParams(ErrAccessOnly, "path_pattern")
+		case jail.UserAgentPattern != "":
+			return cosy.WrapErrorWithParams(ErrAccessOnly, "user_agent_pattern")
+		}
+	}
+
+	if len(jail.StatusCodes) == 0 && jail.PathPattern == "" &&
+		jail.UserAgentPattern == "" && jail.LinePattern == "" {
+		return ErrEmptyFilter
+	}
+
+	for _, pattern := range []struct {
+		name  string
+		value string
+	}{
+		{"path_pattern", jail.PathPattern},
+		{"user_agent_pattern", jail.UserAgentPattern},
+		{"line_pattern", jail.LinePattern},
+	} {
+		if _, err := regexp.Compile(pattern.value); err != nil {
+			return cosy.WrapErrorWithParams(ErrInvalidPattern, pattern.name, err.Error())
+		}
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"max_retry", &jail.MaxRetry, defaultMaxRetry},
+		{"find_time_seconds", &jail.FindTimeSeconds, defaultFindTimeSeconds},
+	} {
+		if *field.value < 0 {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, field.name)
+		}
+		if *field.value == 0 {
+			*field.value = field.def
+		}
+	}
+	// A negative ban time is a permanent ban.
+	if jail.BanTimeSeconds == 0 {
+		jail.BanTimeSeconds = defaultBanTimeSeconds
+	}
+
+	if jail.SiteName != "" && !isValidSiteName(jail.SiteName) {
+		return cosy.WrapErrorWithParams(ErrInvalidSiteName, jail.SiteName)
+	}
+
+	if _, err := parseIgnoreIPs(jail.IgnoreIPs); err != nil {
+		return err
+	}
+
+	if !utils.IsValidLogPath(jail.LogPath) {
+		return cosy.WrapErrorWithParams(ErrInvalidLogPath, jail.LogPath)
+	}
+	return nil
+}
+
+// isValidSiteName reports whether name can be used as the file name of a site
+// deny list.
+func isValidSiteName(name string) bool {
+	return name != "." && name != ".." && filepath.Base(name) == name &&
+		!strings.ContainsAny(name, `/\;"' `)
+}
+
+// parseIgnoreIPs accepts single addresses as well as CIDR ranges.
+func parseIgnoreIPs(ips []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(ips))
+	for _, ip := range ips {
+		ip = strings.TrimSpace(ip)
+		if prefix, err := netip.ParsePrefix(ip); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return nil, cosy.WrapErrorWithParams(ErrInvalidIgnoreIP, ip)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// GetEnabledJails returns the jails the watcher should run.
+func GetEnabledJails() (jails []*model.Jail, err error) {
+	j := query.Jail
+	return j.Where(j.Enabled.Is(true)).Find()
+}
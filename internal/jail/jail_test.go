@@ -0,0 +1,207 @@
+package jail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var testNow = time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+func accessLogLine(ip, path string, status int, userAgent string) string {
+	return fmt.Sprintf(`%s - - [01/Oct/2026:12:00:00 +0000] "GET %s HTTP/1.1" %d 153 "-" "%s"`, ip, path, status, userAgent)
+}
+
+func TestNormalizeFillsDefaultsAndRejectsInvalidJails(t *testing.T) {
+	jail := &model.Jail{StatusCodes: []int{401}, LogType: "syslog"}
+	requireErrorCode(t, Normalize(jail), ErrInvalidLogType)
+
+	jail = &model.Jail{}
+	requireErrorCode(t, Normalize(jail), ErrEmptyFilter)
+	assert.Equal(t, model.JailLogTypeAccess, jail.LogType)
+
+	jail = &model.Jail{LogType: model.JailLogTypeError, PathPattern: "^/wp-login"}
+	requireErrorCode(t, Normalize(jail), ErrAccessOnly)
+
+	jail = &model.Jail{UserAgentPattern: "(sqlmap"}
+	requireErrorCode(t, Normalize(jail), ErrInvalidPattern)
+
+	jail = &model.Jail{StatusCodes: []int{404}, MaxRetry: -1}
+	requireErrorCode(t, Normalize(jail), ErrInvalidValue)
+
+	jail = &model.Jail{StatusCodes: []int{404}, SiteName: "../nginx.conf"}
+	requireErrorCode(t, Normalize(jail), ErrInvalidSiteName)
+	assert.Equal(t, defaultMaxRetry, jail.MaxRetry)
+	assert.Equal(t, defaultFindTimeSeconds, jail.FindTimeSeconds)
+	assert.Equal(t, defaultBanTimeSeconds, jail.BanTimeSeconds)
+
+	jail = &model.Jail{StatusCodes: []int{404}, IgnoreIPs: []string{"10.0.0.0/8", "not-an-ip"}}
+	requireErrorCode(t, Normalize(jail), ErrInvalidIgnoreIP)
+}
+
+func TestFilterMatchesAccessLogLines(t *testing.T) {
+	f, err := newFilter(&model.Jail{
+		LogType:          model.JailLogTypeAccess,
+		StatusCodes:      []int{401, 404},
+		PathPattern:      `^/(wp-login\.php|\.env)`,
+		UserAgentPattern: `(?i)curl|sqlmap`,
+		IgnoreIPs:        []string{"198.51.100.0/24"},
+	})
+	require.NoError(t, err)
+
+	ip, ok := f.match(accessLogLine("203.0.113.7", "/.env", 404, "curl/8.0"))
+	assert.True(t, ok)
+	assert.Equal(t, "203.0.113.7", ip)
+
+	_, ok = f.match(accessLogLine("203.0.113.7", "/.env", 200, "curl/8.0"))
+	assert.False(t, ok, "status does not match")
+	_, ok = f.match(accessLogLine("203.0.113.7", "/index.html", 404, "curl/8.0"))
+	assert.False(t, ok, "path does not match")
+	_, ok = f.match(accessLogLine("203.0.113.7", "/.env", 404, "Mozilla/5.0"))
+	assert.False(t, ok, "user agent does not match")
+	_, ok = f.match(accessLogLine("198.51.100.20", "/.env", 404, "curl/8.0"))
+	assert.False(t, ok, "ignored range")
+	_, ok = f.match(accessLogLine("127.0.0.1", "/.env", 404, "curl/8.0"))
+	assert.False(t, ok, "loopback is never banned")
+	_, ok = f.match("not an access log line")
+	assert.False(t, ok)
+}
+
+func TestFilterMatchesErrorLogLines(t *testing.T) {
+	f, err := newFilter(&model.Jail{
+		LogType:     model.JailLogTypeError,
+		LinePattern: `no user/password was provided|user "\S+" was not found`,
+	})
+	require.NoError(t, err)
+
+	ip, ok := f.match(`2026/10/01 12:00:00 [error] 12#12: *3 no user/password was provided for basic authentication, client: 2001:db8::1, server: example.com, request: "GET / HTTP/1.1"`)
+	assert.True(t, ok)
+	assert.Equal(t, "2001:db8::1", ip)
+
+	_, ok = f.match(`2026/10/01 12:00:00 [error] 12#12: *4 open() "/srv/favicon.ico" failed, client: 203.0.113.7, server: example.com`)
+	assert.False(t, ok)
+}
+
+func TestTrackerBansWithinFindTime(t *testing.T) {
+	tr := newTracker(time.Minute, 3)
+
+	assert.Nil(t, tr.add("203.0.113.7", "1", testNow))
+	assert.Nil(t, tr.add("203.0.113.7", "2", testNow.Add(30*time.Second)))
+	// The first hit left the window, so this is only the second one.
+	assert.Nil(t, tr.add("203.0.113.7", "3", testNow.Add(61*time.Second)))
+	assert.Nil(t, tr.add("203.0.113.8", "other", testNow.Add(61*time.Second)))
+
+	hits := tr.add("203.0.113.7", "4", testNow.Add(62*time.Second))
+	assert.Equal(t, []string{"2", "3", "4"}, evidence(hits))
+	assert.Nil(t, tr.add("203.0.113.7", "5", testNow.Add(63*time.Second)), "a banned client starts over")
+}
+
+func TestBanWritesDenyListsPerScope(t *testing.T) {
+	confDir := setupBanTest(t)
+	global := &model.Jail{Model: model.Model{ID: 1}, Name: "scanners", BanTimeSeconds: 60}
+	site := &model.Jail{Model: model.Model{ID: 2}, Name: "wp-login", SiteName: "example.com", BanTimeSeconds: -1}
+
+	ban, err := Ban(global, "203.0.113.7", []hit{{at: testNow, line: "GET /.env"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /.env"}, ban.Evidence)
+	require.NotNil(t, ban.ExpiresAt)
+	assert.Equal(t, testNow.Add(time.Minute), ban.ExpiresAt.UTC())
+
+	again, err := Ban(global, "203.0.113.7", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ban.ID, again.ID, "an active ban is reused")
+
+	siteBan, err := Ban(site, "2001:db8::1", nil)
+	require.NoError(t, err)
+	assert.Nil(t, siteBan.ExpiresAt, "negative ban time bans permanently")
+
+	require.NoError(t, Sync(""))
+	require.NoError(t, Sync("example.com"))
+	assert.Equal(t, denyListHeader+"deny 203.0.113.7;\n", readFile(t, filepath.Join(confDir, "conf.d", "nginx-ui-jail.conf")))
+	assert.Equal(t, denyListHeader+"deny 2001:db8::1;\n", readFile(t, filepath.Join(confDir, "jail", "example.com.conf")))
+
+	jailNow = func() time.Time { return testNow.Add(2 * time.Minute) }
+	expired, err := ExpireBans()
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, denyListHeader, readFile(t, filepath.Join(confDir, "conf.d", "nginx-ui-jail.conf")))
+
+	require.NoError(t, Unban(siteBan.ID))
+	assert.Equal(t, denyListHeader, readFile(t, filepath.Join(confDir, "jail", "example.com.conf")))
+}
+
+func TestSyncRestoresDenyListWhenTestFails(t *testing.T) {
+	confDir := setupBanTest(t)
+	path := filepath.Join(confDir, "conf.d", "nginx-ui-jail.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(denyListHeader), 0644))
+
+	_, err := Ban(&model.Jail{Name: "scanners"}, "203.0.113.7", nil)
+	require.NoError(t, err)
+
+	settings.NginxSettings.TestConfigCmd = "false"
+	requireErrorCode(t, Sync(""), ErrTestFailed)
+	assert.Equal(t, denyListHeader, readFile(t, path))
+}
+
+func setupBanTest(t *testing.T) string {
+	t.Helper()
+
+	originalDB := model.UseDB()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	originalSyncDelay := syncDelay
+	t.Cleanup(func() {
+		model.Use(originalDB)
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+		syncDelay = originalSyncDelay
+		jailNow = time.Now
+	})
+
+	confDir := t.TempDir()
+	settings.NginxSettings.ConfigDir = confDir
+	settings.NginxSettings.ReloadCmd = "true"
+	settings.NginxSettings.RestartCmd = "true"
+	settings.NginxSettings.TestConfigCmd = "true"
+	// Scheduled syncs would race with the explicit ones of the tests.
+	syncDelay = time.Hour
+	jailNow = func() time.Time { return testNow }
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Jail{}, &model.JailBan{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	return confDir
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
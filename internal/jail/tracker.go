@@ -0,0 +1,78 @@
+package jail
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEvidence caps the log lines stored with a ban.
+const maxEvidence = 20
+
+type hit struct {
+	at   time.Time
+	line string
+}
+
+// tracker counts the failures of each client within the find time of a jail.
+type tracker struct {
+	mu       sync.Mutex
+	findTime time.Duration
+	maxRetry int
+	hits     map[string][]hit
+	adds     int
+}
+
+func newTracker(findTime time.Duration, maxRetry int) *tracker {
+	return &tracker{
+		findTime: findTime,
+		maxRetry: max(maxRetry, 1),
+		hits:     map[string][]hit{},
+	}
+}
+
+// add records a failure and returns the failures of the window once the
+// client reached the retry limit. The client starts over after that.
+func (t *tracker) add(ip, line string, now time.Time) []hit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hits := append(t.prune(t.hits[ip], now), hit{at: now, line: line})
+	if len(hits) >= t.maxRetry {
+		delete(t.hits, ip)
+		return hits
+	}
+	t.hits[ip] = hits
+
+	// Forget clients that stopped failing, so scanners rotating through many
+	// addresses do not grow the map forever.
+	t.adds++
+	if t.adds%1024 == 0 {
+		for ip, hits := range t.hits {
+			if hits = t.prune(hits, now); len(hits) == 0 {
+				delete(t.hits, ip)
+			} else {
+				t.hits[ip] = hits
+			}
+		}
+	}
+	return nil
+}
+
+func (t *tracker) prune(hits []hit, now time.Time) []hit {
+	cutoff := now.Add(-t.findTime)
+	i := 0
+	for i < len(hits) && !hits[i].at.After(cutoff) {
+		i++
+	}
+	return hits[i:]
+}
+
+// evidence returns the most recent lines of hits.
+func evidence(hits []hit) []string {
+	hits = hits[max(len(hits)-maxEvidence, 0):]
+	lines := make([]string, len(hits))
+	for i, h := range hits {
+		lines[i] = h.line
+	}
+	return lines
+}
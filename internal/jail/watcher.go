@@ -0,0 +1,124 @@
+package jail
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/nxadm/tail"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// watch is an enabled jail with its compiled filter and failure counts.
+type watch struct {
+	jail    *model.Jail
+	filter  *filter
+	tracker *tracker
+}
+
+var (
+	watcherMu     sync.Mutex
+	watcherCtx    context.Context
+	cancelWatcher context.CancelFunc
+)
+
+// Init starts watching the logs of the enabled jails until ctx is done.
+func Init(ctx context.Context) {
+	watcherMu.Lock()
+	watcherCtx = ctx
+	watcherMu.Unlock()
+
+	if err := Restart(); err != nil {
+		logger.Errorf("Failed to start jails: %v", err)
+	}
+}
+
+// Restart reloads the enabled jails, it is called whenever a jail changes.
+// Failure counts start over.
+func Restart() error {
+	watcherMu.Lock()
+	defer watcherMu.Unlock()
+
+	if watcherCtx == nil {
+		return nil
+	}
+	if cancelWatcher != nil {
+		cancelWatcher()
+	}
+	var ctx context.Context
+	ctx, cancelWatcher = context.WithCancel(watcherCtx)
+
+	jails, err := GetEnabledJails()
+	if err != nil {
+		return err
+	}
+
+	watches := map[string][]*watch{}
+	for _, jail := range jails {
+		f, err := newFilter(jail)
+		if err != nil {
+			logger.Errorf("Skipping jail %s: %v", jail.Name, err)
+			continue
+		}
+		watches[jail.LogPath] = append(watches[jail.LogPath], &watch{
+			jail:    jail,
+			filter:  f,
+			tracker: newTracker(time.Duration(jail.FindTimeSeconds)*time.Second, jail.MaxRetry),
+		})
+	}
+
+	for logPath, w := range watches {
+		go watchLog(ctx, logPath, w)
+	}
+	return nil
+}
+
+// watchLog follows a log from its end, across rotations, and feeds each new
+// line to the jails reading it.
+func watchLog(ctx context.Context, logPath string, watches []*watch) {
+	t, err := tail.TailFile(logPath, tail.Config{
+		Follow:   true,
+		ReOpen:   true,
+		Location: &tail.SeekInfo{Offset: 0, Whence: io.SeekEnd},
+		Logger:   tail.DiscardingLogger,
+	})
+	if err != nil {
+		logger.Errorf("Failed to tail %s for jails: %v", logPath, err)
+		return
+	}
+	defer t.Cleanup()
+	defer func() { _ = t.Stop() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-t.Lines:
+			if !ok {
+				return
+			}
+			if line == nil || line.Err != nil {
+				continue
+			}
+			handleLine(watches, line.Text, jailNow())
+		}
+	}
+}
+
+func handleLine(watches []*watch, line string, now time.Time) {
+	for _, w := range watches {
+		ip, ok := w.filter.match(line)
+		if !ok {
+			continue
+		}
+		hits := w.tracker.add(ip, line, now)
+		if hits == nil {
+			continue
+		}
+		if _, err := Ban(w.jail, ip, hits); err != nil {
+			logger.Errorf("Jail %s failed to ban %s: %v", w.jail.Name, ip, err)
+		}
+	}
+}
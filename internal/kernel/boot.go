@@ -21,11 +21,13 @@ import (
 	"github.com/0xJacky/Nginx-UI/internal/docker"
 	"github.com/0xJacky/Nginx-UI/internal/event"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/jail"
 	"github.com/0xJacky/Nginx-UI/internal/mcp"
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
 	"github.com/0xJacky/Nginx-UI/internal/passkey"
 	"github.com/0xJacky/Nginx-UI/internal/self_check"
+	"github.com/0xJacky/Nginx-UI/internal/sitedeploy"
 	"github.com/0xJacky/Nginx-UI/internal/sitecheck"
 	"github.com/0xJacky/Nginx-UI/internal/system"
 	"github.com/0xJacky/Nginx-UI/internal/user"
@@ -111,6 +113,8 @@ func InitAfterDatabase(ctx context.Context) {
 		passkey.Init,
 		mcp.Init,
 		nginx_log.InitializeServices,
+		jail.Init,
+		sitedeploy.Init,
 		user.InitTokenCache,
 	}
 
@@ -0,0 +1,153 @@
+package logexport
+
+import (
+	"io"
+	"sort"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/analytics"
+	"github.com/uozi-tech/cosy"
+)
+
+// Table is one named set of rows of an aggregate export
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// DashboardTables flattens dashboard analytics into exportable tables
+func DashboardTables(d *analytics.DashboardAnalytics) []Table {
+	summary := d.Summary
+	tables := []Table{
+		{
+			Name:    "summary",
+			Columns: []string{"metric", "value"},
+			Rows: [][]any{
+				{"total_uv", summary.TotalUV},
+				{"total_pv", summary.TotalPV},
+				{"total_traffic", summary.TotalTraffic},
+				{"avg_daily_uv", summary.AvgDailyUV},
+				{"avg_daily_pv", summary.AvgDailyPV},
+				{"peak_hour", summary.PeakHour},
+				{"peak_hour_traffic", summary.PeakHourTraffic},
+				{"avg_qps", summary.AvgQPS},
+				{"peak_qps", summary.PeakQPS},
+			},
+		},
+		{Name: "hourly_stats", Columns: []string{"timestamp", "hour", "uv", "pv"}},
+		{Name: "daily_stats", Columns: []string{"date", "timestamp", "uv", "pv"}},
+		{Name: "top_urls", Columns: []string{"url", "visits", "percent"}},
+		{Name: "browsers", Columns: []string{"browser", "count", "percent"}},
+		{Name: "operating_systems", Columns: []string{"os", "count", "percent"}},
+		{Name: "devices", Columns: []string{"device", "count", "percent"}},
+	}
+	for _, s := range d.HourlyStats {
+		tables[1].Rows = append(tables[1].Rows, []any{s.Timestamp, s.Hour, s.UV, s.PV})
+	}
+	for _, s := range d.DailyStats {
+		tables[2].Rows = append(tables[2].Rows, []any{s.Date, s.Timestamp, s.UV, s.PV})
+	}
+	for _, s := range d.TopURLs {
+		tables[3].Rows = append(tables[3].Rows, []any{s.URL, s.Visits, s.Percent})
+	}
+	for _, s := range d.Browsers {
+		tables[4].Rows = append(tables[4].Rows, []any{s.Browser, s.Count, s.Percent})
+	}
+	for _, s := range d.OperatingSystems {
+		tables[5].Rows = append(tables[5].Rows, []any{s.OS, s.Count, s.Percent})
+	}
+	for _, s := range d.Devices {
+		tables[6].Rows = append(tables[6].Rows, []any{s.Device, s.Count, s.Percent})
+	}
+	return tables
+}
+
+// GeoTables turns a geographic distribution into a table of countries sorted
+// by request count
+func GeoTables(g *analytics.GeoDistribution) []Table {
+	var total int
+	for _, requests := range g.Countries {
+		total += requests
+	}
+
+	countries := make([]string, 0, len(g.Countries))
+	for country := range g.Countries {
+		countries = append(countries, country)
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		a, b := g.Countries[countries[i]], g.Countries[countries[j]]
+		if a != b {
+			return a > b
+		}
+		return countries[i] < countries[j]
+	})
+
+	table := Table{Name: "countries", Columns: []string{"country", "requests", "percent"}}
+	for _, country := range countries {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(g.Countries[country]) / float64(total) * 100
+		}
+		table.Rows = append(table.Rows, []any{country, g.Countries[country], percent})
+	}
+	return []Table{table}
+}
+
+// SelectTable narrows tables to the one called name, an empty name keeps all
+func SelectTable(tables []Table, name string) ([]Table, error) {
+	if name == "" {
+		return tables, nil
+	}
+	for _, table := range tables {
+		if table.Name == name {
+			return []Table{table}, nil
+		}
+	}
+	return nil, cosy.WrapErrorWithParams(ErrUnknownTable, name)
+}
+
+// CheckTables reports whether tables can be written in format. A Parquet file
+// has a single schema, so it holds one table.
+func CheckTables(format Format, tables []Table) error {
+	if format == FormatParquet && len(tables) > 1 {
+		return ErrParquetSingleTable
+	}
+	return nil
+}
+
+// WriteTables writes tables to w. In NDJSON every row carries the name of its
+// table. A CSV export of several tables writes them one after another, each
+// headed by a "# name" line and separated by a blank line; select a single
+// table for a plain CSV file.
+func WriteTables(w io.Writer, format Format, tables []Table) error {
+	if err := CheckTables(format, tables); err != nil {
+		return err
+	}
+	for i, table := range tables {
+		columns, prefix := table.Columns, []any(nil)
+		switch {
+		case format == FormatNDJSON:
+			columns = append([]string{"table"}, table.Columns...)
+			prefix = []any{table.Name}
+		case len(tables) > 1:
+			separator := "# " + table.Name + "\n"
+			if i > 0 {
+				separator = "\n" + separator
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return err
+			}
+		}
+
+		writer := newRecordWriter(w, format, columns, columnKinds(len(table.Columns), table.Rows))
+		for _, row := range table.Rows {
+			if err := writer.Write(append(prefix, row...)); err != nil {
+				return err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package logexport
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+)
+
+// pageSize is the number of entries fetched per SearchAfter page
+const pageSize = 1000
+
+var entryColumns = []string{
+	"timestamp", "ip", "region_code", "province", "city", "method", "path", "protocol", "status",
+	"bytes_sent", "referer", "user_agent", "browser", "browser_version", "os", "os_version",
+	"device_type", "request_time", "upstream_time", "raw", "extra",
+}
+
+var entryKinds = columnKinds(len(entryColumns), [][]any{entryValues(&parser.AccessLogEntry{})})
+
+// ProgressFunc is called after each page with the number of entries written
+// so far and the number of entries matching the search.
+type ProgressFunc func(written, total uint64)
+
+// WriteEntries streams every access log entry matching req to w. The matches
+// are paged with a SearchAfter cursor, so only one page is held in memory
+// however large the export is. Offset and Limit of req are ignored.
+func WriteEntries(
+	ctx context.Context,
+	s searcher.SearcherInterface,
+	req searcher.SearchRequest,
+	format Format,
+	w io.Writer,
+	progress ProgressFunc,
+) (uint64, error) {
+	req.Limit = pageSize
+	req.Offset = 0
+	req.Fields = nil
+	req.UseCache = false
+	req.IncludeFacets = false
+	req.IncludeHighlighting = false
+	req.IncludeStats = false
+	if req.SortBy == "" {
+		req.SortBy = "timestamp"
+	}
+
+	writer := newRecordWriter(w, format, entryColumns, entryKinds)
+	var written uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		result, err := s.Search(ctx, &req)
+		if err != nil {
+			return written, err
+		}
+
+		for _, hit := range result.Hits {
+			if err := writer.Write(entryValues(EntryFromFields(hit.Fields))); err != nil {
+				return written, err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return written, err
+		}
+		written += uint64(len(result.Hits))
+		if progress != nil {
+			progress(written, max(result.TotalHits, written))
+		}
+
+		if len(result.Hits) < pageSize {
+			return written, writer.Close()
+		}
+		lastHit := result.Hits[len(result.Hits)-1]
+		if len(lastHit.Sort) == 0 {
+			return written, writer.Close()
+		}
+		req.SearchAfter = lastHit.Sort
+	}
+}
+
+// EntryFromFields rebuilds an access log entry from the stored fields of a
+// search hit.
+func EntryFromFields(fields map[string]any) *parser.AccessLogEntry {
+	entry := &parser.AccessLogEntry{
+		Timestamp:   int64(number(fields["timestamp"])),
+		IP:          text(fields["ip"]),
+		RegionCode:  text(fields["region_code"]),
+		Province:    text(fields["province"]),
+		City:        text(fields["city"]),
+		Method:      text(fields["method"]),
+		Path:        text(fields["path"]),
+		Protocol:    text(fields["protocol"]),
+		Status:      int(number(fields["status"])),
+		BytesSent:   int64(number(fields["bytes_sent"])),
+		Referer:     text(fields["referer"]),
+		UserAgent:   text(fields["user_agent"]),
+		Browser:     text(fields["browser"]),
+		BrowserVer:  text(fields["browser_version"]),
+		OS:          text(fields["os"]),
+		OSVersion:   text(fields["os_version"]),
+		DeviceType:  text(fields["device_type"]),
+		RequestTime: number(fields["request_time"]),
+		Raw:         text(fields["raw"]),
+	}
+	if value, ok := fields["upstream_time"].(float64); ok {
+		entry.UpstreamTime = &value
+	}
+	for field, value := range fields {
+		if name, ok := strings.CutPrefix(field, searcher.VarFieldPrefix); ok {
+			if entry.Extra == nil {
+				entry.Extra = make(map[string]string)
+			}
+			entry.Extra[name] = text(value)
+		}
+	}
+	return entry
+}
+
+func entryValues(entry *parser.AccessLogEntry) []any {
+	var extra any
+	if len(entry.Extra) > 0 {
+		extra = entry.Extra
+	}
+	return []any{
+		entry.Timestamp, entry.IP, entry.RegionCode, entry.Province, entry.City, entry.Method,
+		entry.Path, entry.Protocol, entry.Status, entry.BytesSent, entry.Referer, entry.UserAgent,
+		entry.Browser, entry.BrowserVer, entry.OS, entry.OSVersion, entry.DeviceType,
+		entry.RequestTime, entry.UpstreamTime, entry.Raw, extra,
+	}
+}
+
+func text(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+func number(value any) float64 {
+	f, _ := value.(float64)
+	return f
+}
@@ -0,0 +1,14 @@
+package logexport
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                      = cosy.NewErrorScope("log_export")
+	ErrUnsupportedFormat   = e.New(40001, "unsupported export format: {0}")
+	ErrUnknownTable        = e.New(40002, "unknown export table: {0}")
+	ErrJobNotFinished      = e.New(40003, "the export job has not finished")
+	ErrTooManyJobs         = e.New(40004, "too many export jobs are running, try again later")
+	ErrParquetSingleTable  = e.New(40005, "select a single table to export as Parquet")
+	ErrJobNotFound         = e.New(40401, "export job not found")
+	ErrSearcherUnavailable = e.New(50001, "the log searcher is not available")
+)
@@ -0,0 +1,178 @@
+package logexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/uozi-tech/cosy"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat validates a requested format, an empty one defaults to CSV.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatParquet:
+		return FormatParquet, nil
+	default:
+		return "", cosy.WrapErrorWithParams(ErrUnsupportedFormat, value)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Extension returns the file name extension of the format, without the dot.
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// recordWriter writes records with a fixed set of columns as CSV rows, as
+// NDJSON objects or as Parquet rows. Flush writes out what is buffered, Close
+// also completes the file.
+type recordWriter interface {
+	Write(values []any) error
+	Flush() error
+	Close() error
+}
+
+// newRecordWriter creates a writer for format. kinds holds the type of each
+// column, only Parquet uses it.
+func newRecordWriter(w io.Writer, format Format, columns []string, kinds []columnKind) recordWriter {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w), columns: columns}
+	case FormatParquet:
+		return newParquetWriter(w, columns, kinds)
+	default:
+		return &csvWriter{w: csv.NewWriter(w), columns: columns}
+	}
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	columns       []string
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(values []any) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = csvCell(value)
+	}
+	return c.w.Write(record)
+}
+
+// Flush also writes the header, so an export without rows still has one
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(c.columns)
+}
+
+type ndjsonWriter struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+func (n *ndjsonWriter) Write(values []any) error {
+	object := make(map[string]any, len(values))
+	for i, value := range values {
+		object[n.columns[i]] = value
+	}
+	return n.enc.Encode(object)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+// csvCell formats a value as a CSV cell. Strings come from request data such
+// as paths and user agents, so the ones a spreadsheet would evaluate as a
+// formula are prefixed with a quote.
+func csvCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if isFormula(v) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *float64:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// isFormula reports whether a spreadsheet would evaluate s. A lone "-", the
+// placeholder nginx logs for empty values, is left alone.
+func isFormula(s string) bool {
+	if s == "" {
+		return false
+	}
+	switch s[0] {
+	case '=', '+', '@', '\t', '\r':
+		return true
+	case '-':
+		return len(s) > 1
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package logexport
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/event"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// JobStatus is the state of a background export job
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+const (
+	// maxRunningJobs bounds the exports scanning the index at the same time
+	maxRunningJobs = 4
+	// jobTTL is how long a finished job and its file are kept for download
+	jobTTL = 24 * time.Hour
+)
+
+// Job is a background export of access log entries to a file. Jobs live in
+// memory, a restart drops them together with their files.
+type Job struct {
+	ID         string     `json:"id"`
+	UserID     uint64     `json:"user_id"`
+	Format     Format     `json:"format"`
+	Status     JobStatus  `json:"status"`
+	Written    uint64     `json:"written"`
+	Total      uint64     `json:"total"`
+	Size       int64      `json:"size"` // Bytes of the export file
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	path   string
+	cancel context.CancelFunc
+}
+
+// Progress returns the share of entries written, from 0 to 100
+func (j *Job) Progress() float64 {
+	if j.Status == JobCompleted {
+		return 100
+	}
+	if j.Total == 0 {
+		return 0
+	}
+	return float64(j.Written) / float64(j.Total) * 100
+}
+
+var (
+	jobsMutex sync.Mutex
+	jobs      = make(map[string]*Job)
+
+	getSearcher = func() searcher.SearcherInterface {
+		if s := nginx_log.GetSearcher(); s != nil && s.IsHealthy() {
+			return s
+		}
+		return nil
+	}
+
+	exportDir = filepath.Join(os.TempDir(), "nginx-ui-exports")
+)
+
+// StartJob exports the entries matching req to a file in the background and
+// reports its progress on the event bus.
+func StartJob(userID uint64, req *searcher.SearchRequest, format Format) (Job, error) {
+	s := getSearcher()
+	if s == nil {
+		return Job{}, ErrSearcherUnavailable
+	}
+
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	pruneJobs(time.Now())
+
+	running := 0
+	for _, job := range jobs {
+		if job.Status == JobRunning {
+			running++
+		}
+	}
+	if running >= maxRunningJobs {
+		return Job{}, ErrTooManyJobs
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	if err := os.MkdirAll(exportDir, 0700); err != nil {
+		return Job{}, err
+	}
+	file, err := os.CreateTemp(exportDir, "export-*."+format.Extension())
+	if err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:        id,
+		UserID:    userID,
+		Format:    format,
+		Status:    JobRunning,
+		CreatedAt: time.Now(),
+		path:      file.Name(),
+		cancel:    cancel,
+	}
+	jobs[id] = job
+	publishJob(job)
+
+	go runJob(ctx, s, job, *req, file)
+
+	return *job, nil
+}
+
+func runJob(ctx context.Context, s searcher.SearcherInterface, job *Job, req searcher.SearchRequest, file *os.File) {
+	defer job.cancel()
+
+	buffered := bufio.NewWriter(file)
+	_, err := WriteEntries(ctx, s, req, job.Format, buffered, func(written, total uint64) {
+		jobsMutex.Lock()
+		defer jobsMutex.Unlock()
+		job.Written, job.Total = written, total
+		publishJob(job)
+	})
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = JobCancelled
+	case err != nil:
+		job.Status = JobFailed
+		job.Error = err.Error()
+		logger.Errorf("Log export %s failed: %v", job.ID, err)
+	default:
+		job.Status = JobCompleted
+		if info, statErr := os.Stat(job.path); statErr == nil {
+			job.Size = info.Size()
+		}
+	}
+	// A job removed while running keeps no file, even if it got to complete
+	if removed := jobs[job.ID] != job; removed || job.Status != JobCompleted {
+		_ = os.Remove(job.path)
+	}
+	publishJob(job)
+}
+
+// ListJobs returns the export jobs of a user, newest first
+func ListJobs(userID uint64) []Job {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	pruneJobs(time.Now())
+
+	list := make([]Job, 0)
+	for _, job := range jobs {
+		if job.UserID == userID {
+			list = append(list, *job)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list
+}
+
+// GetJob returns an export job of a user
+func GetJob(id string, userID uint64) (Job, error) {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+
+	job, ok := jobs[id]
+	if !ok || job.UserID != userID {
+		return Job{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// OpenJobFile opens the file of a completed export job for download
+func OpenJobFile(id string, userID uint64) (*os.File, Job, error) {
+	job, err := GetJob(id, userID)
+	if err != nil {
+		return nil, job, err
+	}
+	if job.Status != JobCompleted {
+		return nil, job, ErrJobNotFinished
+	}
+	file, err := os.Open(job.path)
+	return file, job, err
+}
+
+// RemoveJob cancels a running export job or deletes the file of a finished one
+func RemoveJob(id string, userID uint64) error {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+
+	job, ok := jobs[id]
+	if !ok || job.UserID != userID {
+		return ErrJobNotFound
+	}
+	if job.Status == JobRunning {
+		// runJob removes the partial file once the export stops
+		job.cancel()
+	} else {
+		_ = os.Remove(job.path)
+	}
+	delete(jobs, id)
+	return nil
+}
+
+// pruneJobs drops jobs that finished more than jobTTL ago. The caller must
+// hold jobsMutex.
+func pruneJobs(now time.Time) {
+	for id, job := range jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > jobTTL {
+			_ = os.Remove(job.path)
+			delete(jobs, id)
+		}
+	}
+}
+
+// publishJob reports the state of a job on the event bus. The caller must
+// hold jobsMutex.
+func publishJob(job *Job) {
+	event.Publish(event.Event{
+		Type: event.TypeNginxLogExportProgress,
+		Data: event.NginxLogExportProgressData{
+			ID:       job.ID,
+			Status:   string(job.Status),
+			Written:  job.Written,
+			Total:    job.Total,
+			Progress: job.Progress(),
+			Error:    job.Error,
+		},
+	})
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
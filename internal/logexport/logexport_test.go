@@ -0,0 +1,219 @@
+package logexport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/analytics"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+)
+
+// fakeSearcher serves count entries, paged by a SearchAfter cursor holding
+// the index of the last returned entry.
+type fakeSearcher struct {
+	searcher.SearcherInterface
+	count    int
+	requests []searcher.SearchRequest
+}
+
+func (f *fakeSearcher) Search(_ context.Context, req *searcher.SearchRequest) (*searcher.SearchResult, error) {
+	f.requests = append(f.requests, *req)
+	start := 0
+	if len(req.SearchAfter) > 0 {
+		start, _ = strconv.Atoi(req.SearchAfter[0])
+	}
+
+	result := &searcher.SearchResult{TotalHits: uint64(f.count)}
+	for i := start; i < f.count && i < start+req.Limit; i++ {
+		result.Hits = append(result.Hits, &searcher.SearchHit{
+			Fields: map[string]any{
+				"timestamp":      float64(1_700_000_000 + i),
+				"ip":             "10.0.0." + strconv.Itoa(i%256),
+				"method":         "GET",
+				"path":           "/item/" + strconv.Itoa(i),
+				"status":         float64(200),
+				"bytes_sent":     float64(512),
+				"user_agent":     "=HYPERLINK(\"http://evil\")",
+				"referer":        "-",
+				"vars.tenant_id": "acme",
+			},
+			Sort: []string{strconv.Itoa(i + 1)},
+		})
+	}
+	return result, nil
+}
+
+func useSearcher(t *testing.T, s searcher.SearcherInterface) {
+	t.Helper()
+	original, originalDir := getSearcher, exportDir
+	getSearcher = func() searcher.SearcherInterface { return s }
+	exportDir = t.TempDir()
+	t.Cleanup(func() { getSearcher, exportDir = original, originalDir })
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
+
+func TestWriteEntriesStreamsCSVWithSearchAfter(t *testing.T) {
+	s := &fakeSearcher{count: 2*pageSize + 500}
+	var out bytes.Buffer
+	var lastWritten, lastTotal uint64
+
+	written, err := WriteEntries(context.Background(), s, searcher.SearchRequest{Limit: 10, Offset: 30, UseCache: true},
+		FormatCSV, &out, func(written, total uint64) {
+			assert.Greater(t, written, lastWritten)
+			lastWritten, lastTotal = written, total
+		})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(s.count), written)
+	assert.Equal(t, uint64(s.count), lastWritten)
+	assert.Equal(t, uint64(s.count), lastTotal)
+
+	require.Len(t, s.requests, 3)
+	for _, req := range s.requests {
+		assert.Equal(t, pageSize, req.Limit)
+		assert.Zero(t, req.Offset)
+		assert.False(t, req.UseCache)
+	}
+	assert.Equal(t, []string{strconv.Itoa(2 * pageSize)}, s.requests[2].SearchAfter)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, s.count+1)
+	assert.Equal(t, strings.Join(entryColumns, ","), lines[0])
+	assert.Equal(t, `1700000000,10.0.0.0,,,,GET,/item/0,,200,512,-,"'=HYPERLINK(""http://evil"")",,,,,,0,,,"{""tenant_id"":""acme""}"`, lines[1])
+}
+
+func TestWriteEntriesNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	written, err := WriteEntries(context.Background(), &fakeSearcher{count: 2}, searcher.SearchRequest{}, FormatNDJSON, &out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), written)
+
+	scanner := bufio.NewScanner(&out)
+	require.True(t, scanner.Scan())
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+	assert.Equal(t, "/item/0", entry["path"])
+	assert.Equal(t, `=HYPERLINK("http://evil")`, entry["user_agent"])
+	assert.Equal(t, map[string]any{"tenant_id": "acme"}, entry["extra"])
+	assert.Nil(t, entry["upstream_time"])
+}
+
+func TestWriteEntriesWithoutMatchesKeepsHeader(t *testing.T) {
+	var out bytes.Buffer
+	_, err := WriteEntries(context.Background(), &fakeSearcher{}, searcher.SearchRequest{}, FormatCSV, &out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(entryColumns, ",")+"\n", out.String())
+}
+
+func TestWriteTables(t *testing.T) {
+	tables := DashboardTables(&analytics.DashboardAnalytics{
+		Summary: analytics.DashboardSummary{TotalUV: 3, TotalPV: 10, AvgQPS: 0.5},
+		TopURLs: []analytics.URLAccessStats{{URL: "/", Visits: 7, Percent: 70}, {URL: "/about", Visits: 3, Percent: 30}},
+	})
+
+	selected, err := SelectTable(tables, "top_urls")
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, WriteTables(&out, FormatCSV, selected))
+	assert.Equal(t, "url,visits,percent\n/,7,70\n/about,3,30\n", out.String())
+
+	out.Reset()
+	require.NoError(t, WriteTables(&out, FormatCSV, tables))
+	assert.True(t, strings.HasPrefix(out.String(), "# summary\nmetric,value\ntotal_uv,3\n"))
+	assert.Contains(t, out.String(), "\n\n# devices\ndevice,count,percent\n")
+
+	out.Reset()
+	require.NoError(t, WriteTables(&out, FormatNDJSON, GeoTables(&analytics.GeoDistribution{
+		Countries: map[string]int{"US": 1, "CN": 3},
+	})))
+	assert.Equal(t, `{"country":"CN","percent":75,"requests":3,"table":"countries"}`+"\n"+
+		`{"country":"US","percent":25,"requests":1,"table":"countries"}`+"\n", out.String())
+
+	_, err = SelectTable(tables, "visitors")
+	requireErrorCode(t, err, ErrUnknownTable)
+	_, err = ParseFormat("xlsx")
+	requireErrorCode(t, err, ErrUnsupportedFormat)
+}
+
+func TestWriteParquet(t *testing.T) {
+	var out bytes.Buffer
+	written, err := WriteEntries(context.Background(), &fakeSearcher{count: pageSize + 1}, searcher.SearchRequest{}, FormatParquet, &out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(pageSize+1), written)
+
+	file := out.Bytes()
+	require.True(t, bytes.HasPrefix(file, parquetMagic))
+	require.True(t, bytes.HasSuffix(file, parquetMagic))
+	footerSize := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	require.Less(t, footerSize, len(file)-12)
+	footer := file[len(file)-8-footerSize : len(file)-8]
+	for _, column := range entryColumns {
+		assert.True(t, bytes.Contains(footer, []byte(column)), column)
+	}
+	assert.True(t, bytes.Contains(file, []byte("/item/1000")))
+
+	// Integers and floats of a column are stored as doubles
+	assert.Equal(t, []columnKind{kindString, kindDouble}, columnKinds(2, [][]any{{"a", 1}, {"b", 0.5}, {nil, nil}}))
+	assert.Equal(t, kindInt64, entryKinds[0])
+	assert.Equal(t, kindDouble, entryKinds[18])
+
+	tables := DashboardTables(&analytics.DashboardAnalytics{})
+	requireErrorCode(t, WriteTables(&out, FormatParquet, tables), ErrParquetSingleTable)
+	out.Reset()
+	require.NoError(t, WriteTables(&out, FormatParquet, tables[:1]))
+	assert.True(t, bytes.HasSuffix(out.Bytes(), parquetMagic))
+}
+
+func TestExportJob(t *testing.T) {
+	useSearcher(t, &fakeSearcher{count: 1500})
+
+	job, err := StartJob(1, &searcher.SearchRequest{}, FormatNDJSON)
+	require.NoError(t, err)
+	assert.Equal(t, JobRunning, job.Status)
+
+	_, err = GetJob(job.ID, 2)
+	requireErrorCode(t, err, ErrJobNotFound)
+
+	require.Eventually(t, func() bool {
+		job, err = GetJob(job.ID, 1)
+		return err == nil && job.Status != JobRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, JobCompleted, job.Status)
+	assert.Equal(t, uint64(1500), job.Written)
+	assert.Equal(t, float64(100), job.Progress())
+	list := ListJobs(1)
+	require.Len(t, list, 1)
+	assert.Equal(t, job.ID, list[0].ID)
+	assert.Empty(t, ListJobs(2))
+
+	file, _, err := OpenJobFile(job.ID, 1)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, 1500, bytes.Count(content, []byte("\n")))
+	assert.Equal(t, int64(len(content)), job.Size)
+
+	require.NoError(t, RemoveJob(job.ID, 1))
+	_, _, err = OpenJobFile(job.ID, 1)
+	requireErrorCode(t, err, ErrJobNotFound)
+	_, err = os.Stat(file.Name())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,441 @@
+package logexport
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// columnKind is the type a column is stored as in a Parquet file. CSV and
+// NDJSON keep the values as they are.
+type columnKind int
+
+const (
+	kindUnknown columnKind = iota
+	kindInt64
+	kindDouble
+	kindString
+)
+
+// Identifiers of parquet.thrift used by the writer
+const (
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetConvertedUTF8      = 0
+	parquetRepetitionOptional = 1
+	parquetEncodingPlain      = 0
+	parquetEncodingRLE        = 3
+	parquetCodecUncompressed  = 0
+	parquetPageTypeData       = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+// kindOf returns the column kind of a value, nil leaves it open
+func kindOf(value any) columnKind {
+	switch value.(type) {
+	case nil:
+		return kindUnknown
+	case int, int64, uint64:
+		return kindInt64
+	case float64, *float64:
+		return kindDouble
+	default:
+		return kindString
+	}
+}
+
+// columnKinds finds the kind of each column of rows. A column mixing integers
+// and floats is stored as doubles, one mixing numbers and text as strings.
+func columnKinds(columns int, rows [][]any) []columnKind {
+	kinds := make([]columnKind, columns)
+	for _, row := range rows {
+		for i, value := range row {
+			kinds[i] = widenKind(kinds[i], kindOf(value))
+		}
+	}
+	for i, kind := range kinds {
+		if kind == kindUnknown {
+			kinds[i] = kindString
+		}
+	}
+	return kinds
+}
+
+func widenKind(a, b columnKind) columnKind {
+	switch {
+	case b == kindUnknown || a == b:
+		return a
+	case a == kindUnknown:
+		return b
+	case a != kindString && b != kindString:
+		return kindDouble
+	default:
+		return kindString
+	}
+}
+
+// parquetColumn buffers the values of a column for the current row group
+type parquetColumn struct {
+	name    string
+	kind    columnKind
+	defined []bool // Whether each row has a value, false for nulls
+	values  []byte // PLAIN encoded values of the defined rows
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	switch c.kind {
+	case kindInt64:
+		return parquetTypeInt64
+	case kindDouble:
+		return parquetTypeDouble
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+func (c *parquetColumn) add(value any) {
+	if pointer, ok := value.(*float64); ok {
+		value = nil
+		if pointer != nil {
+			value = *pointer
+		}
+	}
+	if value == nil {
+		c.defined = append(c.defined, false)
+		return
+	}
+
+	c.defined = append(c.defined, true)
+	switch c.kind {
+	case kindInt64:
+		c.values = binary.LittleEndian.AppendUint64(c.values, uint64(toInt64(value)))
+	case kindDouble:
+		c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(toFloat64(value)))
+	default:
+		s, ok := value.(string)
+		if !ok {
+			encoded, _ := json.Marshal(value)
+			s = string(encoded)
+		}
+		c.values = binary.LittleEndian.AppendUint32(c.values, uint32(len(s)))
+		c.values = append(c.values, s...)
+	}
+}
+
+func (c *parquetColumn) reset() {
+	c.defined = c.defined[:0]
+	c.values = c.values[:0]
+}
+
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case uint64:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func toFloat64(value any) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// parquetChunk locates the values of a column in a row group
+type parquetChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+type parquetRowGroup struct {
+	numRows int64
+	size    int64
+	chunks  []parquetChunk
+}
+
+// parquetWriter writes records as a Parquet file of optional flat columns.
+// Values are PLAIN encoded and uncompressed, every Flush ends a row group
+// with one page per column, so memory stays bounded by the rows between two
+// flushes. The file is only complete once Close wrote the footer.
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []*parquetColumn
+	rows      int64
+	rowGroups []parquetRowGroup
+}
+
+func newParquetWriter(w io.Writer, columns []string, kinds []columnKind) *parquetWriter {
+	p := &parquetWriter{w: w}
+	for i, name := range columns {
+		p.columns = append(p.columns, &parquetColumn{name: name, kind: kinds[i]})
+	}
+	return p
+}
+
+func (p *parquetWriter) Write(values []any) error {
+	for i, value := range values {
+		p.columns[i].add(value)
+	}
+	p.rows++
+	return nil
+}
+
+// Flush writes the buffered rows as a row group
+func (p *parquetWriter) Flush() error {
+	if p.offset == 0 {
+		if err := p.write(parquetMagic); err != nil {
+			return err
+		}
+	}
+	if p.rows == 0 {
+		return nil
+	}
+
+	group := parquetRowGroup{numRows: p.rows}
+	for _, column := range p.columns {
+		chunk, err := p.writeChunk(column)
+		if err != nil {
+			return err
+		}
+		group.size += chunk.size
+		group.chunks = append(group.chunks, chunk)
+		column.reset()
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.rows = 0
+	return nil
+}
+
+// Close flushes the buffered rows and writes the footer
+func (p *parquetWriter) Close() error {
+	if err := p.Flush(); err != nil {
+		return err
+	}
+	footer := p.footer()
+	if err := p.write(footer); err != nil {
+		return err
+	}
+	if err := p.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return p.write(parquetMagic)
+}
+
+func (p *parquetWriter) write(b []byte) error {
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	return err
+}
+
+// writeChunk writes the buffered values of a column as a single data page
+func (p *parquetWriter) writeChunk(column *parquetColumn) (parquetChunk, error) {
+	levels := definitionLevels(column.defined)
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+	page = append(page, column.values...)
+
+	header := newCompactWriter()
+	header.i32(1, parquetPageTypeData)
+	header.i32(2, int32(len(page)))
+	header.i32(3, int32(len(page)))
+	header.structField(5)
+	header.i32(1, int32(len(column.defined)))
+	header.i32(2, parquetEncodingPlain)
+	header.i32(3, parquetEncodingRLE)
+	header.i32(4, parquetEncodingRLE)
+	header.endStruct()
+	header.endStruct()
+
+	chunk := parquetChunk{
+		offset:    p.offset,
+		size:      int64(len(header.buf) + len(page)),
+		numValues: int64(len(column.defined)),
+	}
+	if err := p.write(header.buf); err != nil {
+		return chunk, err
+	}
+	return chunk, p.write(page)
+}
+
+// footer encodes the FileMetaData of the file
+func (p *parquetWriter) footer() []byte {
+	var numRows int64
+	for _, group := range p.rowGroups {
+		numRows += group.numRows
+	}
+
+	m := newCompactWriter()
+	m.i32(1, 1)
+	m.listField(2, compactStruct, len(p.columns)+1)
+	m.beginStruct()
+	m.string(4, "schema")
+	m.i32(5, int32(len(p.columns)))
+	m.endStruct()
+	for _, column := range p.columns {
+		m.beginStruct()
+		m.i32(1, column.physicalType())
+		m.i32(3, parquetRepetitionOptional)
+		m.string(4, column.name)
+		if column.kind == kindString {
+			m.i32(6, parquetConvertedUTF8)
+		}
+		m.endStruct()
+	}
+	m.i64(3, numRows)
+	m.listField(4, compactStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		m.beginStruct()
+		m.listField(1, compactStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := p.columns[i]
+			m.beginStruct()
+			m.i64(2, chunk.offset)
+			m.structField(3)
+			m.i32(1, column.physicalType())
+			m.listField(2, compactI32, 2)
+			m.listI32(parquetEncodingPlain)
+			m.listI32(parquetEncodingRLE)
+			m.listField(3, compactBinary, 1)
+			m.listString(column.name)
+			m.i32(4, parquetCodecUncompressed)
+			m.i64(5, chunk.numValues)
+			m.i64(6, chunk.size)
+			m.i64(7, chunk.size)
+			m.i64(9, chunk.offset)
+			m.endStruct()
+			m.endStruct()
+		}
+		m.i64(2, group.size)
+		m.i64(3, group.numRows)
+		m.endStruct()
+	}
+	m.string(6, "Nginx UI")
+	m.endStruct()
+	return m.buf
+}
+
+// definitionLevels encodes whether each row has a value with the RLE hybrid
+// encoding at a bit width of 1, as runs of equal levels.
+func definitionLevels(defined []bool) []byte {
+	var out []byte
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		if defined[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// Type identifiers of the Thrift compact protocol
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the Thrift structs of the Parquet metadata with the
+// compact protocol. It starts inside the top level struct, which the last
+// endStruct closes.
+type compactWriter struct {
+	buf  []byte
+	last []int16 // The last field id of each open struct
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{last: []int16{0}}
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	top := len(w.last) - 1
+	if delta := id - w.last[top]; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.buf = binary.AppendUvarint(w.buf, zigzag(int64(id)))
+	}
+	w.last[top] = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.buf = binary.AppendUvarint(w.buf, zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.buf = binary.AppendUvarint(w.buf, zigzag(v))
+}
+
+func (w *compactWriter) string(id int16, s string) {
+	w.fieldHeader(id, compactBinary)
+	w.listString(s)
+}
+
+func (w *compactWriter) structField(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.beginStruct()
+}
+
+// listField starts a list, its elements follow with listI32, listString or
+// a struct each
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|elemType)
+	w.buf = binary.AppendUvarint(w.buf, uint64(size))
+}
+
+func (w *compactWriter) listI32(v int32) {
+	w.buf = binary.AppendUvarint(w.buf, zigzag(int64(v)))
+}
+
+func (w *compactWriter) listString(s string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *compactWriter) beginStruct() {
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf = append(w.buf, 0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func zigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
@@ -0,0 +1,374 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var applyMutex sync.Mutex
+
+// Apply converges the instance to the manifest. Records are changed in a single
+// database transaction, and the configuration files are written, tested and
+// reloaded before it commits. Any failure restores the files and rolls the
+// records back, so either the whole plan is applied or nothing is.
+func Apply(ctx context.Context, desired *Manifest, prune bool, author githistory.Author) (*Plan, error) {
+	applyMutex.Lock()
+	defer applyMutex.Unlock()
+
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	current, err := loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := diffState(current.manifest, desired, prune)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Empty() {
+		return plan, nil
+	}
+
+	a := &applier{current: current, desired: desired}
+	err = query.Q.Transaction(func(tx *query.Query) error {
+		return a.run(tx, plan)
+	})
+	if err != nil {
+		a.restore()
+		return nil, err
+	}
+
+	githistory.Record(author, "Apply manifest", a.paths()...)
+
+	return plan, nil
+}
+
+type applier struct {
+	current      *state
+	desired      *Manifest
+	namespaceIDs map[string]uint64
+	files        []fileSnapshot
+	reloaded     bool
+}
+
+func (a *applier) run(tx *query.Query, plan *Plan) error {
+	a.namespaceIDs = make(map[string]uint64, len(a.current.namespaces))
+	for name, ns := range a.current.namespaces {
+		a.namespaceIDs[name] = ns.ID
+	}
+
+	// Namespaces are deleted last, once no site or stream can still point at
+	// them.
+	var deletedNamespaces []Change
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Kind {
+		case KindNamespace:
+			if change.Action == ActionDelete {
+				deletedNamespaces = append(deletedNamespaces, change)
+				continue
+			}
+			err = a.applyNamespace(tx, change)
+		case KindCert:
+			err = a.applyCert(tx, change)
+		case KindUpstream:
+			err = a.applyUpstream(tx, change)
+		case KindSite:
+			err = a.applySite(tx, change)
+		case KindStream:
+			err = a.applyStream(tx, change)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, change := range deletedNamespaces {
+		n := tx.Namespace
+		if _, err := n.Where(n.ID.Eq(a.namespaceIDs[change.Name])).Delete(); err != nil {
+			return err
+		}
+	}
+
+	if len(a.files) == 0 {
+		return nil
+	}
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		return cosy.WrapErrorWithParams(ErrTestFailed, result.GetOutput())
+	}
+	a.reloaded = true
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		return cosy.WrapErrorWithParams(ErrReloadFailed, result.GetOutput())
+	}
+	return nil
+}
+
+func (a *applier) applyNamespace(tx *query.Query, change Change) error {
+	desired := find(a.desired.Namespaces, func(ns Namespace) bool { return ns.Name == change.Name }).model()
+	n := tx.Namespace
+	if change.Action == ActionCreate {
+		if err := n.Create(&desired); err != nil {
+			return err
+		}
+		a.namespaceIDs[desired.Name] = desired.ID
+		return nil
+	}
+	_, err := n.Where(n.ID.Eq(a.namespaceIDs[change.Name])).
+		Select(n.SyncNodeIds, n.PostSyncAction, n.UpstreamTestType, n.DeployMode, n.SyncStrategy, n.SyncIntervalMinutes).
+		Updates(&desired)
+	return err
+}
+
+func (a *applier) applyCert(tx *query.Query, change Change) error {
+	c := tx.Cert
+	if change.Action == ActionDelete {
+		_, err := c.Where(c.ID.Eq(a.current.certs[change.Name].ID)).Delete()
+		return err
+	}
+
+	desired := find(a.desired.Certs, func(cert Cert) bool { return cert.Name == change.Name })
+	record := &model.Cert{
+		Name:                  desired.Name,
+		Domains:               desired.Domains,
+		SSLCertificatePath:    desired.SSLCertificatePath,
+		SSLCertificateKeyPath: desired.SSLCertificateKeyPath,
+		KeyType:               certcrypto.KeyType(desired.KeyType),
+		ChallengeMethod:       desired.ChallengeMethod,
+		AutoCert:              desired.AutoCert,
+	}
+	if change.Action == ActionCreate {
+		return c.Create(record)
+	}
+	_, err := c.Where(c.ID.Eq(a.current.certs[change.Name].ID)).
+		Select(c.Domains, c.SSLCertificatePath, c.SSLCertificateKeyPath, c.KeyType, c.ChallengeMethod, c.AutoCert).
+		Updates(record)
+	return err
+}
+
+func (a *applier) applyUpstream(tx *query.Query, change Change) error {
+	u := tx.UpstreamConfig
+	switch change.Action {
+	case ActionCreate:
+		desired := find(a.desired.Upstreams, func(upstream Upstream) bool { return upstream.Socket == change.Name })
+		return u.Create(&model.UpstreamConfig{Socket: desired.Socket, Enabled: desired.Enabled})
+	case ActionUpdate:
+		desired := find(a.desired.Upstreams, func(upstream Upstream) bool { return upstream.Socket == change.Name })
+		_, err := u.Where(u.Socket.Eq(change.Name)).Select(u.Enabled).Updates(&model.UpstreamConfig{Enabled: desired.Enabled})
+		return err
+	default:
+		_, err := u.Where(u.Socket.Eq(change.Name)).Unscoped().Delete()
+		return err
+	}
+}
+
+func (a *applier) applySite(tx *query.Query, change Change) error {
+	paths, err := resolveConfigPaths(change.Name, site.ResolveAvailablePath, site.ResolveEnabledPath, site.MaintenanceSuffix)
+	if err != nil {
+		return err
+	}
+
+	s := tx.Site
+	if change.Action == ActionDelete {
+		if _, err := s.Where(s.Path.Eq(paths.available)).Unscoped().Delete(); err != nil {
+			return err
+		}
+		return a.removeConfig(paths)
+	}
+
+	desired := find(a.desired.Sites, func(cfg Config) bool { return cfg.Name == change.Name })
+	remote := a.isRemote(desired.Namespace)
+	if _, err := s.Where(s.Path.Eq(paths.available)).FirstOrCreate(); err != nil {
+		return err
+	}
+	_, err = s.Where(s.Path.Eq(paths.available)).
+		Select(s.NamespaceID, s.RemoteEnabled).
+		Updates(&model.Site{
+			NamespaceID:   a.namespaceIDs[desired.Namespace],
+			RemoteEnabled: remote && desired.Enabled,
+		})
+	if err != nil {
+		return err
+	}
+	return a.writeConfig(paths, desired, remote)
+}
+
+func (a *applier) applyStream(tx *query.Query, change Change) error {
+	paths, err := resolveConfigPaths(change.Name, stream.ResolveAvailablePath, stream.ResolveEnabledPath, "")
+	if err != nil {
+		return err
+	}
+
+	s := tx.Stream
+	if change.Action == ActionDelete {
+		if _, err := s.Where(s.Path.Eq(paths.available)).Unscoped().Delete(); err != nil {
+			return err
+		}
+		return a.removeConfig(paths)
+	}
+
+	desired := find(a.desired.Streams, func(cfg Config) bool { return cfg.Name == change.Name })
+	remote := a.isRemote(desired.Namespace)
+	if _, err := s.Where(s.Path.Eq(paths.available)).FirstOrCreate(); err != nil {
+		return err
+	}
+	_, err = s.Where(s.Path.Eq(paths.available)).
+		Select(s.NamespaceID, s.RemoteEnabled).
+		Updates(&model.Stream{
+			NamespaceID:   a.namespaceIDs[desired.Namespace],
+			RemoteEnabled: remote && desired.Enabled,
+		})
+	if err != nil {
+		return err
+	}
+	return a.writeConfig(paths, desired, remote)
+}
+
+func (a *applier) isRemote(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	return find(a.desired.Namespaces, func(ns Namespace) bool { return ns.Name == namespace }).DeployMode == model.DeployModeRemote
+}
+
+// writeConfig writes the configuration file and converges its enabled links.
+// Remote namespaces never keep a local link, their intent lives in the record.
+func (a *applier) writeConfig(paths configPaths, desired Config, remote bool) error {
+	if err := a.capture(paths.all()...); err != nil {
+		return err
+	}
+	if err := os.WriteFile(paths.available, []byte(desired.Content), 0644); err != nil {
+		return err
+	}
+
+	if remote || !desired.Enabled {
+		return removeLinks(paths.enabled, paths.maintenance)
+	}
+	// A site in maintenance counts as enabled, so its links are left alone.
+	if linkExists(paths.enabled) || linkExists(paths.maintenance) {
+		return nil
+	}
+	return os.Symlink(paths.available, paths.enabled)
+}
+
+func (a *applier) removeConfig(paths configPaths) error {
+	if err := a.capture(paths.all()...); err != nil {
+		return err
+	}
+	return removeLinks(paths.enabled, paths.maintenance, paths.available)
+}
+
+func (a *applier) capture(paths ...string) error {
+	for _, path := range paths {
+		snapshot, err := captureFile(path)
+		if err != nil {
+			return err
+		}
+		a.files = append(a.files, snapshot)
+	}
+	return nil
+}
+
+// restore puts every captured file back in reverse order and reloads nginx
+// when a reload was already attempted with the new files.
+func (a *applier) restore() {
+	for i := len(a.files) - 1; i >= 0; i-- {
+		if err := a.files[i].restore(); err != nil {
+			logger.Errorf("failed to restore %s after a failed manifest apply: %v", a.files[i].path, err)
+		}
+	}
+	if a.reloaded {
+		nginx.Control(nginx.Reload)
+	}
+}
+
+// paths returns the captured files that existed before or after the apply.
+func (a *applier) paths() []string {
+	paths := make([]string, 0, len(a.files))
+	for _, snapshot := range a.files {
+		if snapshot.exists || linkExists(snapshot.path) {
+			paths = append(paths, snapshot.path)
+		}
+	}
+	return paths
+}
+
+type configPaths struct {
+	available   string
+	enabled     string
+	maintenance string
+}
+
+func (p configPaths) all() []string {
+	paths := []string{p.available, p.enabled}
+	if p.maintenance != "" {
+		paths = append(paths, p.maintenance)
+	}
+	return paths
+}
+
+func resolveConfigPaths(
+	name string,
+	resolveAvailable func(string) (string, error),
+	resolveEnabled func(string) (string, error),
+	maintenanceSuffix string,
+) (paths configPaths, err error) {
+	if paths.available, err = resolveAvailable(name); err != nil {
+		return
+	}
+	if paths.enabled, err = resolveEnabled(name); err != nil {
+		return
+	}
+	paths.enabled = nginx.GetConfSymlinkPath(paths.enabled)
+	if maintenanceSuffix == "" {
+		return
+	}
+	if paths.maintenance, err = resolveEnabled(name + maintenanceSuffix); err != nil {
+		return
+	}
+	paths.maintenance = nginx.GetConfSymlinkPath(paths.maintenance)
+	return
+}
+
+func removeLinks(paths ...string) error {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func linkExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// find returns the entry matching the predicate. Plans are built from the
+// same manifest, so the entry always exists.
+func find[T any](entries []T, match func(T) bool) T {
+	for _, entry := range entries {
+		if match(entry) {
+			return entry
+		}
+	}
+	var zero T
+	return zero
+}
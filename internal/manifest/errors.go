@@ -0,0 +1,15 @@
+package manifest
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                     = cosy.NewErrorScope("manifest")
+	ErrInvalidManifest    = e.New(40001, "invalid manifest: {0}")
+	ErrUnsupportedVersion = e.New(40002, "unsupported manifest version: {0}")
+	ErrMissingName        = e.New(40003, "{0} entry is missing a name")
+	ErrDuplicateEntry     = e.New(40004, "{0} is declared more than once: {1}")
+	ErrUnknownNamespace   = e.New(40005, "{0} {1} references undeclared namespace {2}")
+	ErrInvalidValue       = e.New(40006, "{0} {1} has an invalid {2}")
+	ErrTestFailed         = e.New(50001, "nginx test failed, manifest was rolled back: {0}")
+	ErrReloadFailed       = e.New(50002, "nginx reload failed, manifest was rolled back: {0}")
+)
@@ -0,0 +1,155 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+)
+
+// state is the current instance state, both as a manifest and as the records
+// the manifest entries map to.
+type state struct {
+	manifest   *Manifest
+	namespaces map[string]*model.Namespace
+	sites      map[string]*model.Site
+	streams    map[string]*model.Stream
+	certs      map[string]*model.Cert
+	upstreams  map[string]*model.UpstreamConfig
+}
+
+// Export returns the current state of the instance as a manifest.
+func Export(ctx context.Context) (*Manifest, error) {
+	current, err := loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return current.manifest, nil
+}
+
+func loadState(ctx context.Context) (*state, error) {
+	current := &state{
+		manifest:   &Manifest{Version: Version},
+		namespaces: make(map[string]*model.Namespace),
+		sites:      make(map[string]*model.Site),
+		streams:    make(map[string]*model.Stream),
+		certs:      make(map[string]*model.Cert),
+		upstreams:  make(map[string]*model.UpstreamConfig),
+	}
+
+	namespaces, err := query.Namespace.Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		current.namespaces[ns.Name] = ns
+		current.manifest.Namespaces = append(current.manifest.Namespaces, Namespace{
+			Name:                ns.Name,
+			DeployMode:          ns.DeployMode,
+			SyncStrategy:        ns.SyncStrategy,
+			SyncIntervalMinutes: ns.SyncIntervalMinutes,
+			PostSyncAction:      ns.PostSyncAction,
+			UpstreamTestType:    ns.UpstreamTestType,
+			SyncNodeIDs:         ns.SyncNodeIds,
+		})
+	}
+
+	sites, err := query.Site.Preload(query.Site.Namespace).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, siteModel := range sites {
+		current.sites[filepath.Base(siteModel.Path)] = siteModel
+	}
+	siteConfigs, err := site.GetSiteConfigs(ctx, &site.ListOptions{}, sites)
+	if err != nil {
+		return nil, err
+	}
+	current.manifest.Sites, err = exportConfigs(siteConfigs, site.ResolveAvailablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := query.Stream.Preload(query.Stream.Namespace).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, streamModel := range streams {
+		current.streams[filepath.Base(streamModel.Path)] = streamModel
+	}
+	streamConfigs, err := stream.GetStreamConfigs(ctx, &stream.ListOptions{}, streams)
+	if err != nil {
+		return nil, err
+	}
+	current.manifest.Streams, err = exportConfigs(streamConfigs, stream.ResolveAvailablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := query.Cert.Order(query.Cert.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range certs {
+		// Names are not unique in the database, the oldest record wins.
+		if cert.Name == "" || current.certs[cert.Name] != nil {
+			continue
+		}
+		current.certs[cert.Name] = cert
+		current.manifest.Certs = append(current.manifest.Certs, Cert{
+			Name:                  cert.Name,
+			Domains:               cert.Domains,
+			SSLCertificatePath:    cert.SSLCertificatePath,
+			SSLCertificateKeyPath: cert.SSLCertificateKeyPath,
+			KeyType:               string(cert.KeyType),
+			ChallengeMethod:       cert.ChallengeMethod,
+			AutoCert:              cert.AutoCert,
+		})
+	}
+
+	upstreams, err := query.UpstreamConfig.Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, upstream := range upstreams {
+		current.upstreams[upstream.Socket] = upstream
+		current.manifest.Upstreams = append(current.manifest.Upstreams, Upstream{
+			Socket:  upstream.Socket,
+			Enabled: upstream.Enabled,
+		})
+	}
+
+	current.manifest.sortEntries()
+
+	return current, nil
+}
+
+func exportConfigs(configs []config.Config, resolve func(string) (string, error)) ([]Config, error) {
+	result := make([]Config, 0, len(configs))
+	for _, cfg := range configs {
+		path, err := resolve(cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		item := Config{
+			Name:    cfg.Name,
+			Enabled: cfg.Status != config.StatusDisabled,
+			Content: string(content),
+		}
+		if cfg.Namespace != nil {
+			item.Namespace = cfg.Namespace.Name
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,208 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/ghodss/yaml"
+	"github.com/uozi-tech/cosy"
+)
+
+// Version identifies the manifest format understood by this package.
+const Version = "nginx-ui/v1"
+
+// Manifest describes the desired state of an instance. Apply converges the
+// instance towards it, Export produces it from the current state.
+type Manifest struct {
+	Version    string      `json:"version"`
+	Namespaces []Namespace `json:"namespaces,omitempty"`
+	Sites      []Config    `json:"sites,omitempty"`
+	Streams    []Config    `json:"streams,omitempty"`
+	Certs      []Cert      `json:"certs,omitempty"`
+	Upstreams  []Upstream  `json:"upstreams,omitempty"`
+}
+
+// Namespace mirrors model.Namespace, keyed by name instead of ID so the same
+// manifest can be applied to several instances.
+type Namespace struct {
+	Name                string   `json:"name"`
+	DeployMode          string   `json:"deploy_mode,omitempty"`
+	SyncStrategy        string   `json:"sync_strategy,omitempty"`
+	SyncIntervalMinutes int      `json:"sync_interval_minutes,omitempty"`
+	PostSyncAction      string   `json:"post_sync_action,omitempty"`
+	UpstreamTestType    string   `json:"upstream_test_type,omitempty"`
+	SyncNodeIDs         []uint64 `json:"sync_node_ids,omitempty"`
+}
+
+// Config is a site or stream configuration file.
+type Config struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Enabled   bool   `json:"enabled"`
+	Content   string `json:"content"`
+}
+
+// Cert is a certificate record. Only the record is managed, the certificate
+// files must already exist on the target, and nothing is issued on apply.
+type Cert struct {
+	Name                  string   `json:"name"`
+	Domains               []string `json:"domains,omitempty"`
+	SSLCertificatePath    string   `json:"ssl_certificate_path"`
+	SSLCertificateKeyPath string   `json:"ssl_certificate_key_path"`
+	KeyType               string   `json:"key_type,omitempty"`
+	ChallengeMethod       string   `json:"challenge_method,omitempty"`
+	AutoCert              int      `json:"auto_cert,omitempty"`
+}
+
+// Upstream is a tracked upstream socket.
+type Upstream struct {
+	Socket  string `json:"socket"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Parse decodes a YAML or JSON manifest, fills in defaults and validates it.
+func Parse(data []byte) (*Manifest, error) {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidManifest, err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	m := &Manifest{}
+	if err := decoder.Decode(m); err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidManifest, err.Error())
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Marshal encodes the manifest as YAML, or as indented JSON when format is
+// "json".
+func (m *Manifest) Marshal(format string) ([]byte, error) {
+	if format == "json" {
+		out, err := json.MarshalIndent(m, "", "  ")
+		return append(out, '\n'), err
+	}
+	return yaml.Marshal(m)
+}
+
+// Validate fills in defaults and checks that every entry is named, unique and
+// only references declared namespaces.
+func (m *Manifest) Validate() error {
+	if m.Version == "" {
+		m.Version = Version
+	}
+	if m.Version != Version {
+		return cosy.WrapErrorWithParams(ErrUnsupportedVersion, m.Version)
+	}
+
+	namespaces := make(map[string]bool, len(m.Namespaces))
+	for i := range m.Namespaces {
+		ns := &m.Namespaces[i]
+		if err := checkName(KindNamespace, ns.Name, namespaces); err != nil {
+			return err
+		}
+		ns.applyDefaults()
+		if ns.DeployMode != model.DeployModeLocal && ns.DeployMode != model.DeployModeRemote {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, KindNamespace, ns.Name, "deploy_mode")
+		}
+		if ns.SyncStrategy != model.SyncStrategyManual && ns.SyncStrategy != model.SyncStrategyAuto {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, KindNamespace, ns.Name, "sync_strategy")
+		}
+		if ns.SyncIntervalMinutes < 0 {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, KindNamespace, ns.Name, "sync_interval_minutes")
+		}
+	}
+
+	for _, section := range []struct {
+		kind    string
+		configs []Config
+	}{{KindSite, m.Sites}, {KindStream, m.Streams}} {
+		seen := make(map[string]bool, len(section.configs))
+		for _, cfg := range section.configs {
+			if err := checkName(section.kind, cfg.Name, seen); err != nil {
+				return err
+			}
+			if cfg.Namespace != "" && !namespaces[cfg.Namespace] {
+				return cosy.WrapErrorWithParams(ErrUnknownNamespace, section.kind, cfg.Name, cfg.Namespace)
+			}
+		}
+	}
+
+	certs := make(map[string]bool, len(m.Certs))
+	for _, cert := range m.Certs {
+		if err := checkName(KindCert, cert.Name, certs); err != nil {
+			return err
+		}
+		if cert.SSLCertificatePath == "" || cert.SSLCertificateKeyPath == "" {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, KindCert, cert.Name, "certificate path")
+		}
+	}
+
+	upstreams := make(map[string]bool, len(m.Upstreams))
+	for _, upstream := range m.Upstreams {
+		if err := checkName(KindUpstream, upstream.Socket, upstreams); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkName(kind, name string, seen map[string]bool) error {
+	if strings.TrimSpace(name) == "" {
+		return cosy.WrapErrorWithParams(ErrMissingName, kind)
+	}
+	if seen[name] {
+		return cosy.WrapErrorWithParams(ErrDuplicateEntry, kind, name)
+	}
+	seen[name] = true
+	return nil
+}
+
+func (ns *Namespace) applyDefaults() {
+	if ns.DeployMode == "" {
+		ns.DeployMode = model.DeployModeLocal
+	}
+	if ns.SyncStrategy == "" {
+		ns.SyncStrategy = model.SyncStrategyManual
+	}
+	if ns.SyncIntervalMinutes == 0 {
+		ns.SyncIntervalMinutes = model.DefaultSyncIntervalMinutes
+	}
+	if ns.PostSyncAction == "" {
+		ns.PostSyncAction = model.PostSyncActionReloadNginx
+	}
+	if ns.UpstreamTestType == "" {
+		ns.UpstreamTestType = model.UpstreamTestLocal
+	}
+}
+
+// sortEntries orders every section by name so exports and plans are stable.
+func (m *Manifest) sortEntries() {
+	slices.SortFunc(m.Namespaces, func(a, b Namespace) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(m.Sites, func(a, b Config) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(m.Streams, func(a, b Config) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(m.Certs, func(a, b Cert) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(m.Upstreams, func(a, b Upstream) int { return strings.Compare(a.Socket, b.Socket) })
+}
+
+func (ns Namespace) model() model.Namespace {
+	return model.Namespace{
+		Name:                ns.Name,
+		SyncNodeIds:         ns.SyncNodeIDs,
+		PostSyncAction:      ns.PostSyncAction,
+		UpstreamTestType:    ns.UpstreamTestType,
+		DeployMode:          ns.DeployMode,
+		SyncStrategy:        ns.SyncStrategy,
+		SyncIntervalMinutes: ns.SyncIntervalMinutes,
+	}
+}
@@ -0,0 +1,195 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupManifestTest(t *testing.T) string {
+	t.Helper()
+
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+	})
+
+	confDir := t.TempDir()
+	for _, dir := range []string{"sites-available", "sites-enabled", "streams-available", "streams-enabled"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(confDir, dir), 0755))
+	}
+	settings.NginxSettings.ConfigDir = confDir
+	settings.NginxSettings.ReloadCmd = "true"
+	settings.NginxSettings.RestartCmd = "true"
+	settings.NginxSettings.TestConfigCmd = "true"
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Site{}, &model.Stream{}, &model.Cert{}, &model.UpstreamConfig{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	return confDir
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
+
+func TestParseYAMLFillsNamespaceDefaults(t *testing.T) {
+	m, err := Parse([]byte(`
+version: nginx-ui/v1
+namespaces:
+  - name: edge
+sites:
+  - name: example.com
+    namespace: edge
+    enabled: true
+    content: |
+      server {
+          listen 80;
+      }
+`))
+	require.NoError(t, err)
+	require.Len(t, m.Namespaces, 1)
+	assert.Equal(t, model.DeployModeLocal, m.Namespaces[0].DeployMode)
+	assert.Equal(t, model.SyncStrategyManual, m.Namespaces[0].SyncStrategy)
+	assert.Equal(t, model.DefaultSyncIntervalMinutes, m.Namespaces[0].SyncIntervalMinutes)
+	assert.Contains(t, m.Sites[0].Content, "listen 80;")
+}
+
+func TestParseRejectsInvalidManifests(t *testing.T) {
+	_, err := Parse([]byte("version: nginx-ui/v2\n"))
+	requireErrorCode(t, err, ErrUnsupportedVersion)
+
+	_, err = Parse([]byte("sites:\n  - name: a\n    unknown: true\n"))
+	requireErrorCode(t, err, ErrInvalidManifest)
+
+	_, err = Parse([]byte("sites:\n  - name: a\n    namespace: missing\n"))
+	requireErrorCode(t, err, ErrUnknownNamespace)
+
+	_, err = Parse([]byte("streams:\n  - name: a\n  - name: a\n"))
+	requireErrorCode(t, err, ErrDuplicateEntry)
+}
+
+func TestDiffStateOnlyDeletesWhenPruning(t *testing.T) {
+	setupManifestTest(t)
+	current := &Manifest{
+		Version:   Version,
+		Sites:     []Config{{Name: "old.example.com", Content: "server {}\n"}, {Name: "same.example.com", Content: "server {}\n"}},
+		Upstreams: []Upstream{{Socket: "127.0.0.1:8080", Enabled: true}},
+	}
+	desired := &Manifest{
+		Version:   Version,
+		Sites:     []Config{{Name: "same.example.com", Content: "server {}\n"}, {Name: "new.example.com", Content: "server {}\n"}},
+		Upstreams: []Upstream{{Socket: "127.0.0.1:8080", Enabled: false}},
+	}
+
+	plan, err := diffState(current, desired, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, Change{Kind: KindUpstream, Name: "127.0.0.1:8080", Action: ActionUpdate}, withoutDiff(plan.Changes[0]))
+	assert.Contains(t, plan.Changes[0].Diff, "+enabled: false")
+	assert.Equal(t, Change{Kind: KindSite, Name: "new.example.com", Action: ActionCreate}, withoutDiff(plan.Changes[1]))
+
+	plan, err = diffState(current, desired, true)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, Change{Kind: KindSite, Name: "old.example.com", Action: ActionDelete}, withoutDiff(plan.Changes[2]))
+	assert.Contains(t, plan.String(), "Plan: 1 to create, 1 to update, 1 to delete.")
+}
+
+func withoutDiff(change Change) Change {
+	change.Diff = ""
+	return change
+}
+
+func TestApplyThenExportRoundTrips(t *testing.T) {
+	confDir := setupManifestTest(t)
+	desired, err := Parse([]byte(`
+namespaces:
+  - name: edge
+sites:
+  - name: example.com
+    namespace: edge
+    enabled: true
+    content: "server { listen 80; }\n"
+streams:
+  - name: tcp
+    enabled: false
+    content: "server { listen 9000; }\n"
+upstreams:
+  - socket: 127.0.0.1:8080
+    enabled: true
+`))
+	require.NoError(t, err)
+
+	plan, err := Apply(context.Background(), desired, false, githistory.SystemAuthor)
+	require.NoError(t, err)
+	assert.Len(t, plan.Changes, 4)
+
+	link, err := os.Readlink(filepath.Join(confDir, "sites-enabled", "example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(confDir, "sites-available", "example.com"), link)
+	assert.NoFileExists(t, filepath.Join(confDir, "streams-enabled", "tcp"))
+
+	exported, err := Export(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, desired, exported)
+
+	plan, err = BuildPlan(context.Background(), exported, true)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
+
+func TestApplyRollsBackWhenNginxTestFails(t *testing.T) {
+	confDir := setupManifestTest(t)
+	available := filepath.Join(confDir, "sites-available", "example.com")
+	require.NoError(t, os.WriteFile(available, []byte("server { listen 80; }\n"), 0644))
+	settings.NginxSettings.TestConfigCmd = "false"
+
+	desired := &Manifest{
+		Namespaces: []Namespace{{Name: "edge"}},
+		Sites: []Config{
+			{Name: "example.com", Namespace: "edge", Enabled: true, Content: "server { listen 8080; }\n"},
+			{Name: "new.example.com", Content: "server {}\n"},
+		},
+	}
+	_, err := Apply(context.Background(), desired, false, githistory.SystemAuthor)
+	requireErrorCode(t, err, ErrTestFailed)
+
+	content, err := os.ReadFile(available)
+	require.NoError(t, err)
+	assert.Equal(t, "server { listen 80; }\n", string(content))
+	assert.NoFileExists(t, filepath.Join(confDir, "sites-available", "new.example.com"))
+	_, err = os.Lstat(filepath.Join(confDir, "sites-enabled", "example.com"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	count, err := query.Namespace.Count()
+	require.NoError(t, err)
+	assert.Zero(t, count, "the namespace is rolled back with the files")
+	count, err = query.Site.Count()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
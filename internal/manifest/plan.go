@@ -0,0 +1,205 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/ghodss/yaml"
+)
+
+// Entry kinds reported in a plan.
+const (
+	KindNamespace = "namespace"
+	KindSite      = "site"
+	KindStream    = "stream"
+	KindCert      = "cert"
+	KindUpstream  = "upstream"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+var actionSymbols = map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+
+// Change is a single entry of a plan. Diff is a unified diff of the entry
+// rendered as YAML.
+type Change struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// Plan lists the changes needed to converge the instance to a manifest.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the instance already matches the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan for terminal output.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes. The instance matches the manifest.\n"
+	}
+
+	var b strings.Builder
+	counts := make(map[Action]int, 3)
+	for _, change := range p.Changes {
+		counts[change.Action]++
+		fmt.Fprintf(&b, "%s %s %s\n", actionSymbols[change.Action], change.Kind, change.Name)
+		if change.Diff != "" {
+			for _, line := range strings.Split(strings.TrimRight(change.Diff, "\n"), "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+
+	return b.String()
+}
+
+// BuildPlan compares the manifest with the current state. Entries missing
+// from the manifest are only deleted when prune is set.
+func BuildPlan(ctx context.Context, desired *Manifest, prune bool) (*Plan, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	current, err := loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return diffState(current.manifest, desired, prune)
+}
+
+func diffState(current, desired *Manifest, prune bool) (*Plan, error) {
+	if err := validateConfigs(desired); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Changes: make([]Change, 0)}
+	sections := []section{
+		sectionOf(KindNamespace, current.Namespaces, desired.Namespaces, func(ns Namespace) string { return ns.Name }),
+		sectionOf(KindCert, current.Certs, desired.Certs, func(cert Cert) string { return cert.Name }),
+		sectionOf(KindUpstream, current.Upstreams, desired.Upstreams, func(upstream Upstream) string { return upstream.Socket }),
+		sectionOf(KindSite, current.Sites, desired.Sites, func(cfg Config) string { return cfg.Name }),
+		sectionOf(KindStream, current.Streams, desired.Streams, func(cfg Config) string { return cfg.Name }),
+	}
+
+	for _, group := range sections {
+		for _, name := range group.order {
+			before, exists := group.current[name]
+			after, wanted := group.desired[name]
+
+			var action Action
+			switch {
+			case wanted && !exists:
+				action = ActionCreate
+			case wanted && exists:
+				action = ActionUpdate
+			case exists && prune:
+				action = ActionDelete
+			default:
+				continue
+			}
+
+			beforeText, err := render(before)
+			if err != nil {
+				return nil, err
+			}
+			afterText, err := render(after)
+			if err != nil {
+				return nil, err
+			}
+			if action == ActionUpdate && beforeText == afterText {
+				continue
+			}
+
+			diff, err := helper.UnifiedDiff(group.kind+"/"+name, beforeText, afterText)
+			if err != nil {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, Change{
+				Kind:   group.kind,
+				Name:   name,
+				Action: action,
+				Diff:   diff,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// section holds both sides of a manifest section indexed by name. The order
+// lists desired entries first, then the entries only present on the instance.
+type section struct {
+	kind    string
+	current map[string]any
+	desired map[string]any
+	order   []string
+}
+
+func sectionOf[T any](kind string, current, desired []T, key func(T) string) section {
+	s := section{
+		kind:    kind,
+		current: make(map[string]any, len(current)),
+		desired: make(map[string]any, len(desired)),
+	}
+	for _, item := range desired {
+		s.desired[key(item)] = item
+		s.order = append(s.order, key(item))
+	}
+	for _, item := range current {
+		s.current[key(item)] = item
+		if _, ok := s.desired[key(item)]; !ok {
+			s.order = append(s.order, key(item))
+		}
+	}
+	return s
+}
+
+func render(entry any) (string, error) {
+	if entry == nil {
+		return "", nil
+	}
+	out, err := yaml.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// validateConfigs resolves and validates every site and stream before
+// anything is written, so a bad name or content fails the plan as a whole.
+func validateConfigs(m *Manifest) error {
+	for _, group := range []struct {
+		configs []Config
+		resolve func(string) (string, error)
+	}{{m.Sites, site.ResolveAvailablePath}, {m.Streams, stream.ResolveAvailablePath}} {
+		for _, cfg := range group.configs {
+			path, err := group.resolve(cfg.Name)
+			if err != nil {
+				return err
+			}
+			if err := config.ValidateConfigFile(path, cfg.Content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
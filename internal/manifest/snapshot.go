@@ -0,0 +1,55 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+)
+
+// fileSnapshot records a configuration file or enabled link as it was before
+// the apply touched it.
+type fileSnapshot struct {
+	path    string
+	exists  bool
+	link    string
+	content []byte
+	mode    os.FileMode
+}
+
+func captureFile(path string) (fileSnapshot, error) {
+	snapshot := fileSnapshot{path: path}
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return snapshot, err
+	}
+
+	snapshot.exists = true
+	snapshot.mode = info.Mode().Perm()
+	if info.Mode()&os.ModeSymlink != 0 {
+		snapshot.link, err = os.Readlink(path)
+		return snapshot, err
+	}
+	snapshot.content, err = os.ReadFile(path)
+	return snapshot, err
+}
+
+func (s fileSnapshot) restore() error {
+	if s.link != "" {
+		if err := removeLinks(s.path); err != nil {
+			return err
+		}
+		return os.Symlink(s.link, s.path)
+	}
+	if !s.exists {
+		return removeLinks(s.path)
+	}
+	// Replace a link created by the apply instead of writing through it.
+	if info, err := os.Lstat(s.path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(s.path); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(s.path, s.content, s.mode)
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/settings"
 	"gorm.io/gorm"
@@ -23,10 +24,11 @@ import (
 const ServiceTokenPrincipalKey = "ServiceTokenPrincipal"
 
 type ServiceTokenPrincipal struct {
-	PublicID  string
-	Name      string
-	Scopes    []string
-	CreatorID uint64
+	PublicID     string
+	Name         string
+	Scopes       []string
+	NamespaceIDs []uint64
+	CreatorID    uint64
 }
 
 func (principal *ServiceTokenPrincipal) HasScope(scope string) bool {
@@ -46,7 +48,29 @@ func (principal *ServiceTokenPrincipal) HasScope(scope string) bool {
 	}
 }
 
-func CreateServiceToken(name string, scopes []string, expiresAt *time.Time, creatorID uint64) (*model.MCPServiceToken, string, error) {
+// HasAPIScope reports whether the token may reach the management API with the
+// given api:* scope. Tokens limited to individual resources pass here and are
+// narrowed down by the per-route permission check.
+func (principal *ServiceTokenPrincipal) HasAPIScope(scope string) bool {
+	if principal.HasScope(scope) {
+		return true
+	}
+	if principal == nil {
+		return false
+	}
+	for _, granted := range principal.Scopes {
+		resource, action, ok := rbac.ParsePermission(granted)
+		if !ok || resource == rbac.ResourceMCP {
+			continue
+		}
+		if scope == model.APITokenScopeRead || action == rbac.ActionWrite {
+			return true
+		}
+	}
+	return false
+}
+
+func CreateServiceToken(name string, scopes []string, expiresAt *time.Time, creatorID uint64, namespaceIDs ...uint64) (*model.MCPServiceToken, string, error) {
 	if model.UseDB() == nil {
 		return nil, "", errors.New("database unavailable")
 	}
@@ -74,12 +98,13 @@ func CreateServiceToken(name string, scopes []string, expiresAt *time.Time, crea
 		return nil, "", err
 	}
 	record := &model.MCPServiceToken{
-		PublicID:  publicID,
-		Name:      name,
-		Verifier:  verifier,
-		Scopes:    normalizedScopes,
-		ExpiresAt: expiresAt,
-		CreatorID: creatorID,
+		PublicID:     publicID,
+		Name:         name,
+		Verifier:     verifier,
+		Scopes:       normalizedScopes,
+		NamespaceIDs: namespaceIDs,
+		ExpiresAt:    expiresAt,
+		CreatorID:    creatorID,
 	}
 	if err := model.UseDB().Create(record).Error; err != nil {
 		return nil, "", err
@@ -160,10 +185,11 @@ func VerifyServiceToken(rawToken string, now time.Time) (*ServiceTokenPrincipal,
 		return nil, err
 	}
 	return &ServiceTokenPrincipal{
-		PublicID:  record.PublicID,
-		Name:      record.Name,
-		Scopes:    append([]string(nil), record.Scopes...),
-		CreatorID: record.CreatorID,
+		PublicID:     record.PublicID,
+		Name:         record.Name,
+		Scopes:       append([]string(nil), record.Scopes...),
+		NamespaceIDs: append([]uint64(nil), record.NamespaceIDs...),
+		CreatorID:    record.CreatorID,
 	}, nil
 }
 
@@ -244,8 +270,7 @@ func normalizeServiceTokenScopes(scopes []string) ([]string, error) {
 	result := make([]string, 0, len(scopes))
 	for _, scope := range scopes {
 		scope = strings.TrimSpace(scope)
-		if scope != model.MCPTokenScopeRead && scope != model.MCPTokenScopeWrite &&
-			scope != model.APITokenScopeRead && scope != model.APITokenScopeWrite {
+		if !validServiceTokenScope(scope) {
 			return nil, fmt.Errorf("unsupported service token scope %q", scope)
 		}
 		if _, exists := seen[scope]; exists {
@@ -257,3 +282,15 @@ func normalizeServiceTokenScopes(scopes []string) ([]string, error) {
 	slices.Sort(result)
 	return result, nil
 }
+
+// validServiceTokenScope accepts the api:* and mcp:* scopes as well as any
+// single-resource permission such as "sites:write".
+func validServiceTokenScope(scope string) bool {
+	switch scope {
+	case model.MCPTokenScopeRead, model.MCPTokenScopeWrite,
+		model.APITokenScopeRead, model.APITokenScopeWrite:
+		return true
+	}
+	resource, _, ok := rbac.ParsePermission(scope)
+	return ok && resource != rbac.Wildcard
+}
@@ -0,0 +1,24 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+)
+
+type subjectContextKey struct{}
+
+// WithSubject stores the subject a tool call acts as in the request context,
+// which the SSE server hands on to the tool handlers.
+func WithSubject(ctx context.Context, subject *rbac.Subject) context.Context {
+	return context.WithValue(ctx, subjectContextKey{}, subject)
+}
+
+// SubjectFromContext returns the subject a tool call acts as. Without one it
+// returns an empty subject that is granted nothing.
+func SubjectFromContext(ctx context.Context) *rbac.Subject {
+	if subject, ok := ctx.Value(subjectContextKey{}).(*rbac.Subject); ok && subject != nil {
+		return subject
+	}
+	return &rbac.Subject{}
+}
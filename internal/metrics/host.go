@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/0xJacky/Nginx-UI/internal/analytic"
+	"github.com/0xJacky/Nginx-UI/internal/version"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+func collectBuild(_ context.Context, w *writer) error {
+	info := version.GetVersionInfo()
+	w.family("nginx_ui_build_info", typeGauge, "Nginx UI build information.")
+	w.sample("nginx_ui_build_info", 1, "version", info.Version, "hash", info.ShortHash)
+	return nil
+}
+
+// collectHost exposes the host statistics of the dashboard. Rates are left to
+// Prometheus, so CPU, network and disk activity are exported as counters.
+func collectHost(ctx context.Context, w *writer) error {
+	var errs []error
+
+	if times, err := cpu.TimesWithContext(ctx, false); err != nil {
+		errs = append(errs, err)
+	} else if len(times) > 0 {
+		w.family("nginx_ui_host_cpu_seconds_total", typeCounter, "CPU time spent in each mode.")
+		for _, mode := range []struct {
+			name  string
+			value float64
+		}{
+			{"user", times[0].User},
+			{"system", times[0].System},
+			{"idle", times[0].Idle},
+			{"iowait", times[0].Iowait},
+			{"steal", times[0].Steal},
+		} {
+			w.sample("nginx_ui_host_cpu_seconds_total", mode.value, "mode", mode.name)
+		}
+	}
+	if count, err := cpu.CountsWithContext(ctx, true); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.single("nginx_ui_host_cpus", typeGauge, "Number of logical CPUs.", float64(count))
+	}
+
+	if avg, err := load.AvgWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.single("nginx_ui_host_load1", typeGauge, "1 minute load average.", avg.Load1)
+		w.single("nginx_ui_host_load5", typeGauge, "5 minute load average.", avg.Load5)
+		w.single("nginx_ui_host_load15", typeGauge, "15 minute load average.", avg.Load15)
+	}
+
+	if uptime, err := host.UptimeWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.single("nginx_ui_host_uptime_seconds", typeGauge, "Host uptime.", float64(uptime))
+	}
+
+	if memory, err := mem.VirtualMemoryWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.family("nginx_ui_host_memory_bytes", typeGauge, "Host memory by state.")
+		w.sample("nginx_ui_host_memory_bytes", float64(memory.Total), "state", "total")
+		w.sample("nginx_ui_host_memory_bytes", float64(memory.Used), "state", "used")
+		w.sample("nginx_ui_host_memory_bytes", float64(memory.Available), "state", "available")
+		w.sample("nginx_ui_host_memory_bytes", float64(memory.Cached), "state", "cached")
+		w.sample("nginx_ui_host_memory_bytes", float64(memory.Free), "state", "free")
+	}
+	if swap, err := mem.SwapMemoryWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.family("nginx_ui_host_swap_bytes", typeGauge, "Host swap by state.")
+		w.sample("nginx_ui_host_swap_bytes", float64(swap.Total), "state", "total")
+		w.sample("nginx_ui_host_swap_bytes", float64(swap.Used), "state", "used")
+	}
+
+	if partitions, err := analytic.GetPartitionUsage(); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, family := range []struct {
+			name, help string
+			value      func(analytic.PartitionUsage) uint64
+		}{
+			{"nginx_ui_host_filesystem_size_bytes", "Filesystem size.", func(p analytic.PartitionUsage) uint64 { return p.Total }},
+			{"nginx_ui_host_filesystem_used_bytes", "Filesystem space in use.", func(p analytic.PartitionUsage) uint64 { return p.Used }},
+			{"nginx_ui_host_filesystem_free_bytes", "Filesystem space available.", func(p analytic.PartitionUsage) uint64 { return p.Free }},
+		} {
+			w.family(family.name, typeGauge, family.help)
+			for _, p := range partitions {
+				w.sample(family.name, float64(family.value(p)), "mountpoint", p.Mountpoint, "device", p.Device, "fstype", p.Fstype)
+			}
+		}
+	}
+
+	if network, err := analytic.GetNetworkStat(); err != nil {
+		errs = append(errs, err)
+	} else {
+		w.single("nginx_ui_host_network_receive_bytes_total", typeCounter,
+			"Bytes received on the counted network interfaces.", float64(network.BytesRecv))
+		w.single("nginx_ui_host_network_transmit_bytes_total", typeCounter,
+			"Bytes sent on the counted network interfaces.", float64(network.BytesSent))
+	}
+
+	if counters, err := disk.IOCountersWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, family := range []struct {
+			name, help string
+			value      func(disk.IOCountersStat) uint64
+		}{
+			{"nginx_ui_host_disk_reads_completed_total", "Disk reads completed.", func(c disk.IOCountersStat) uint64 { return c.ReadCount }},
+			{"nginx_ui_host_disk_writes_completed_total", "Disk writes completed.", func(c disk.IOCountersStat) uint64 { return c.WriteCount }},
+			{"nginx_ui_host_disk_read_bytes_total", "Bytes read from disk.", func(c disk.IOCountersStat) uint64 { return c.ReadBytes }},
+			{"nginx_ui_host_disk_written_bytes_total", "Bytes written to disk.", func(c disk.IOCountersStat) uint64 { return c.WriteBytes }},
+		} {
+			w.family(family.name, typeCounter, family.help)
+			for _, device := range slices.Sorted(maps.Keys(counters)) {
+				w.sample(family.name, float64(family.value(counters[device])), "device", device)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
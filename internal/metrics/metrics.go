@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// collector writes one group of families. A failing collector is reported
+// through nginx_ui_scrape_collector_success instead of failing the scrape.
+type collector struct {
+	name    string
+	collect func(ctx context.Context, w *writer) error
+}
+
+var collectors = []collector{
+	{"build", collectBuild},
+	{"host", collectHost},
+	{"nginx", collectNginx},
+	{"upstream", collectUpstreams},
+	{"site", collectSites},
+	{"certificate", collectCertificates},
+	{"log_index", collectLogIndex},
+}
+
+// Write collects every metric and writes them to out in the Prometheus text
+// exposition format.
+func Write(ctx context.Context, out io.Writer) error {
+	w := &writer{}
+	success := make([]bool, len(collectors))
+	durations := make([]time.Duration, len(collectors))
+
+	for i, c := range collectors {
+		start := time.Now()
+		err := c.collect(ctx, w)
+		durations[i] = time.Since(start)
+		success[i] = err == nil
+		if err != nil {
+			logger.Debugf("metrics: %s collector failed: %v", c.name, err)
+		}
+	}
+
+	w.family("nginx_ui_scrape_collector_success", typeGauge, "Whether a metrics collector succeeded.")
+	for i, c := range collectors {
+		w.sample("nginx_ui_scrape_collector_success", boolValue(success[i]), "collector", c.name)
+	}
+	w.family("nginx_ui_scrape_collector_duration_seconds", typeGauge, "Time spent in a metrics collector.")
+	for i, c := range collectors {
+		w.sample("nginx_ui_scrape_collector_duration_seconds", durations[i].Seconds(), "collector", c.name)
+	}
+
+	_, err := w.buf.WriteTo(out)
+	return err
+}
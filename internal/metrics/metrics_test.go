@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestWriterRendersTextFormat(t *testing.T) {
+	w := &writer{}
+	w.family("test_up", typeGauge, "Help with a \\ and a\nnewline.")
+	w.sample("test_up", 1, "name", `a "quoted"\name`, "url", "line\nbreak")
+	w.single("test_total", typeCounter, "A counter.", 1.5)
+	w.single("test_nan", typeGauge, "Not a number.", math.NaN())
+
+	assert.Equal(t, `# HELP test_up Help with a \\ and a\nnewline.
+# TYPE test_up gauge
+test_up{name="a \"quoted\"\\name",url="line\nbreak"} 1
+# HELP test_total A counter.
+# TYPE test_total counter
+test_total 1.5
+# HELP test_nan Not a number.
+# TYPE test_nan gauge
+test_nan NaN
+`, w.buf.String())
+}
+
+func TestWriteReportsEveryCollector(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:metrics?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Cert{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(context.Background(), &buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE nginx_ui_build_info gauge\n")
+	for _, c := range collectors {
+		assert.Contains(t, out, `nginx_ui_scrape_collector_success{collector="`+c.name+`"} `)
+	}
+
+	// Every family header appears once, so the output stays valid for scrapers.
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "# TYPE ") {
+			continue
+		}
+		assert.False(t, seen[line], "duplicate family %q", line)
+		seen[line] = true
+	}
+}
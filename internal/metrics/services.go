@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/0xJacky/Nginx-UI/internal/cert"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
+	"github.com/0xJacky/Nginx-UI/internal/performance"
+	"github.com/0xJacky/Nginx-UI/internal/sitecheck"
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/0xJacky/Nginx-UI/query"
+)
+
+func collectNginx(_ context.Context, w *writer) error {
+	w.single("nginx_ui_nginx_up", typeGauge, "Whether the nginx master process is running.", boolValue(nginx.IsRunning()))
+
+	enabled, status, err := performance.GetStubStatusData()
+	w.single("nginx_ui_nginx_stub_status_enabled", typeGauge, "Whether the stub_status module is reachable.", boolValue(enabled && err == nil))
+	if err != nil || !enabled {
+		return err
+	}
+
+	w.family("nginx_ui_nginx_connections", typeGauge, "Client connections by state, from stub_status.")
+	w.sample("nginx_ui_nginx_connections", float64(status.Active), "state", "active")
+	w.sample("nginx_ui_nginx_connections", float64(status.Reading), "state", "reading")
+	w.sample("nginx_ui_nginx_connections", float64(status.Writing), "state", "writing")
+	w.sample("nginx_ui_nginx_connections", float64(status.Waiting), "state", "waiting")
+	w.single("nginx_ui_nginx_connections_accepted_total", typeCounter, "Accepted client connections.", float64(status.Accepts))
+	w.single("nginx_ui_nginx_connections_handled_total", typeCounter, "Handled client connections.", float64(status.Handled))
+	w.single("nginx_ui_nginx_http_requests_total", typeCounter, "Client requests.", float64(status.Requests))
+	return nil
+}
+
+func collectUpstreams(_ context.Context, w *writer) error {
+	availability := upstream.GetUpstreamService().GetAvailabilityMap()
+	targets := slices.Sorted(maps.Keys(availability))
+
+	w.family("nginx_ui_upstream_target_up", typeGauge, "Whether an upstream target accepted the last availability probe.")
+	for _, target := range targets {
+		w.sample("nginx_ui_upstream_target_up", boolValue(availability[target].Online), "target", target)
+	}
+	w.family("nginx_ui_upstream_target_latency_seconds", typeGauge, "Connect latency of the last successful availability probe.")
+	for _, target := range targets {
+		if status := availability[target]; status.Online {
+			w.sample("nginx_ui_upstream_target_latency_seconds", float64(status.Latency)/1000, "target", target)
+		}
+	}
+	return nil
+}
+
+func collectSites(_ context.Context, w *writer) error {
+	service := sitecheck.GetService()
+	if service == nil {
+		return nil
+	}
+
+	sites := service.GetSites()
+	w.family("nginx_ui_site_up", typeGauge, "Whether the last site health check succeeded.")
+	for _, site := range sites {
+		if site.Status == sitecheck.StatusChecking {
+			continue
+		}
+		w.sample("nginx_ui_site_up", boolValue(site.Status == sitecheck.StatusOnline), "name", site.Name, "url", site.DisplayURL)
+	}
+	w.family("nginx_ui_site_http_status_code", typeGauge, "HTTP status code of the last site health check.")
+	for _, site := range sites {
+		if site.StatusCode > 0 {
+			w.sample("nginx_ui_site_http_status_code", float64(site.StatusCode), "name", site.Name, "url", site.DisplayURL)
+		}
+	}
+	w.family("nginx_ui_site_response_time_seconds", typeGauge, "Response time of the last site health check.")
+	for _, site := range sites {
+		if site.LastChecked > 0 {
+			w.sample("nginx_ui_site_response_time_seconds", float64(site.ResponseTime)/1000, "name", site.Name, "url", site.DisplayURL)
+		}
+	}
+	w.family("nginx_ui_site_last_check_timestamp_seconds", typeGauge, "Time of the last site health check.")
+	for _, site := range sites {
+		if site.LastChecked > 0 {
+			w.sample("nginx_ui_site_last_check_timestamp_seconds", float64(site.LastChecked), "name", site.Name, "url", site.DisplayURL)
+		}
+	}
+	return nil
+}
+
+func collectCertificates(_ context.Context, w *writer) error {
+	certs, err := query.Cert.Find()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	type expiry struct {
+		id, name            string
+		notBefore, notAfter float64
+	}
+	expiries := make([]expiry, 0, len(certs))
+	for _, certModel := range certs {
+		if certModel.SSLCertificatePath == "" {
+			continue
+		}
+		info, err := cert.GetCertInfo(certModel.SSLCertificatePath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		expiries = append(expiries, expiry{
+			id:        strconv.FormatUint(certModel.ID, 10),
+			name:      certModel.Name,
+			notBefore: float64(info.NotBefore.Unix()),
+			notAfter:  float64(info.NotAfter.Unix()),
+		})
+	}
+
+	w.family("nginx_ui_certificate_not_after_timestamp_seconds", typeGauge, "Expiry time of a managed certificate.")
+	for _, e := range expiries {
+		w.sample("nginx_ui_certificate_not_after_timestamp_seconds", e.notAfter, "id", e.id, "name", e.name)
+	}
+	w.family("nginx_ui_certificate_not_before_timestamp_seconds", typeGauge, "Start of the validity of a managed certificate.")
+	for _, e := range expiries {
+		w.sample("nginx_ui_certificate_not_before_timestamp_seconds", e.notBefore, "id", e.id, "name", e.name)
+	}
+	return errors.Join(errs...)
+}
+
+var logIndexStatuses = []string{
+	string(indexer.IndexStatusIndexed),
+	string(indexer.IndexStatusIndexing),
+	string(indexer.IndexStatusNotIndexed),
+	string(indexer.IndexStatusQueued),
+	string(indexer.IndexStatusError),
+}
+
+func collectLogIndex(_ context.Context, w *writer) error {
+	logs := nginx_log.GetAllLogsWithIndexGrouped()
+
+	w.family("nginx_ui_log_index_status", typeGauge, "Index status of a log file, one series per status.")
+	for _, log := range logs {
+		for _, status := range logIndexStatuses {
+			w.sample("nginx_ui_log_index_status", boolValue(log.IndexStatus == status), "path", log.Path, "type", log.Type, "status", status)
+		}
+	}
+	w.family("nginx_ui_log_index_documents", typeGauge, "Documents indexed from a log file.")
+	for _, log := range logs {
+		w.sample("nginx_ui_log_index_documents", float64(log.DocumentCount), "path", log.Path, "type", log.Type)
+	}
+	w.family("nginx_ui_log_index_last_indexed_timestamp_seconds", typeGauge, "Time a log file was last indexed.")
+	for _, log := range logs {
+		if log.LastIndexed > 0 {
+			w.sample("nginx_ui_log_index_last_indexed_timestamp_seconds", float64(log.LastIndexed), "path", log.Path, "type", log.Type)
+		}
+	}
+	w.family("nginx_ui_log_index_duration_seconds", typeGauge, "Duration of the last indexing run of a log file.")
+	for _, log := range logs {
+		if log.IndexDuration > 0 {
+			w.sample("nginx_ui_log_index_duration_seconds", float64(log.IndexDuration)/1000, "path", log.Path, "type", log.Type)
+		}
+	}
+	return nil
+}
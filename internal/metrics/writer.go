@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Prometheus text exposition format served by Write.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types of the text exposition format.
+const (
+	typeGauge   = "gauge"
+	typeCounter = "counter"
+)
+
+// writer renders families in the Prometheus text exposition format. Every
+// sample of a family has to be written right after its header.
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) family(name, kind, help string) {
+	w.buf.WriteString("# HELP ")
+	w.buf.WriteString(name)
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(escapeHelp(help))
+	w.buf.WriteString("\n# TYPE ")
+	w.buf.WriteString(name)
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(kind)
+	w.buf.WriteByte('\n')
+}
+
+// sample writes one value. labels holds name and value pairs.
+func (w *writer) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 1 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.buf.WriteString(labels[i])
+			w.buf.WriteString(`="`)
+			w.buf.WriteString(escapeLabel(labels[i+1]))
+			w.buf.WriteByte('"')
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(formatValue(value))
+	w.buf.WriteByte('\n')
+}
+
+// single writes a family holding one unlabelled sample.
+func (w *writer) single(name, kind, help string, value float64) {
+	w.family(name, kind, help)
+	w.sample(name, value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return helpEscaper.Replace(value)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/gin-gonic/gin"
+	"github.com/uozi-tech/cosy"
+)
+
+// RequireChangeSet blocks direct configuration saves while change set
+// approval is enforced, leaving the change set review as the only way to
+// write. Cluster nodes are exempt because they replay edits that were already
+// approved on the controller.
+func RequireChangeSet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !settings.ChangeSetSettings.RequireApproval {
+			c.Next()
+			return
+		}
+		if _, ok := c.Get(nodeauth.GinPrincipalKey); ok {
+			c.Next()
+			return
+		}
+
+		cosy.ErrHandler(c, ErrApprovalRequired)
+		c.Abort()
+	}
+}
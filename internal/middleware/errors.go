@@ -8,4 +8,5 @@ var (
 	ErrDecryptionFailed     = e.New(40001, "decryption failed")
 	ErrFormParseFailed      = e.New(40002, "form parse failed")
 	ErrDisabledInDemo       = e.New(40300, "this action is disabled in demo mode")
+	ErrApprovalRequired     = e.New(40301, "direct edits are disabled, submit a change set for approval instead")
 )
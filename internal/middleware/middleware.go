@@ -64,12 +64,41 @@ func authorizationToken(authorization string) string {
 // and interactive account or credential operations must never be delegated to
 // a service token through the cluster proxy.
 var serviceTokenWriteGETPaths = map[string]struct{}{
-	"/api/backup":            {},
-	"/api/certs/:id/revoke":  {},
-	"/api/domain/:name/cert": {},
-	"/api/geolite/download":  {},
-	"/api/system/restart":    {},
-	"/api/upgrade/perform":   {},
+	"/api/backup":                {},
+	"/api/certs/:id/revoke":      {},
+	"/api/domain/:name/cert":     {},
+	"/api/geolite/download":      {},
+	"/api/node/credentials":      {},
+	"/api/nodes/:id/credentials": {},
+	"/api/nodes/:id/secret":      {},
+	"/api/pty":                   {},
+	"/api/system/restart":        {},
+	"/api/upgrade/perform":       {},
+}
+
+// Some query APIs use POST only to carry a large filter body. They never
+// change state, so read access is enough to call them. Exports are left out:
+// they start background jobs writing files on the node.
+var readOnlyPOSTPaths = map[string]struct{}{
+	"/api/nginx_log":                                  {},
+	"/api/nginx_log/analytics":                        {},
+	"/api/nginx_log/dashboard":                        {},
+	"/api/nginx_log/errors/search":                    {},
+	"/api/nginx_log/errors/timeline":                  {},
+	"/api/nginx_log/errors/top_messages":              {},
+	"/api/nginx_log/geo/china":                        {},
+	"/api/nginx_log/geo/stats":                        {},
+	"/api/nginx_log/geo/world":                        {},
+	"/api/nginx_log/page":                             {},
+	"/api/nginx_log/saved_searches/:id/run":           {},
+	"/api/nginx_log/saved_searches/shared/:token/run": {},
+	"/api/nginx_log/search":                           {},
+	"/api/nginx_log/traffic_alerts/preview":           {},
+	"/api/manifest/plan":                              {},
+	"/api/ngx/build_config":                           {},
+	"/api/ngx/format_code":                            {},
+	"/api/ngx/tokenize_config":                        {},
+	"/api/templates/block/:name":                      {},
 }
 
 var serviceTokenInteractivePaths = map[string]struct{}{
@@ -119,6 +148,11 @@ func apiScopeForRequest(c *gin.Context) string {
 			return model.APITokenScopeRead
 		}
 		return model.APITokenScopeWrite
+	case http.MethodPost:
+		if _, readOnly := readOnlyPOSTPaths[c.FullPath()]; readOnly {
+			return model.APITokenScopeRead
+		}
+		return model.APITokenScopeWrite
 	default:
 		return model.APITokenScopeWrite
 	}
@@ -140,7 +174,7 @@ func authenticateServiceTokenRequest(c *gin.Context, token, requiredScope string
 	if err != nil {
 		return true, err
 	}
-	if !principal.HasScope(requiredScope) {
+	if !principal.HasAPIScope(requiredScope) {
 		return true, fmt.Errorf("service token scope is insufficient")
 	}
 	if serviceTokenRequiresInteractiveUser(c) {
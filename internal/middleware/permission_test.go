@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	internalmcp "github.com/0xJacky/Nginx-UI/internal/mcp"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePermissionUsesServiceTokenResourceScopes(t *testing.T) {
+	setupAPIServiceTokenTest(t)
+
+	_, sitesToken, err := internalmcp.CreateServiceToken("sites", []string{rbac.Permission(rbac.ResourceSites, rbac.ActionWrite)}, nil, 14)
+	require.NoError(t, err)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router := gin.New()
+	router.GET("/api/sites", AuthRequired(), RequirePermission(rbac.ResourceSites), ok)
+	router.POST("/api/sites/example", AuthRequired(), RequirePermission(rbac.ResourceSites), ok)
+	router.GET("/api/settings", AuthRequired(), RequirePermission(rbac.ResourceSettings), ok)
+
+	require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodGet, "/api/sites", sitesToken).Code)
+	require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodPost, "/api/sites/example", sitesToken).Code)
+	require.Equal(t, http.StatusForbidden, serviceTokenRequest(router, http.MethodGet, "/api/settings", sitesToken).Code)
+}
+
+func TestRequirePermissionUsesUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(user *model.User, method string) int {
+		router := gin.New()
+		handler := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+		router.Handle(method, "/api/sites", func(c *gin.Context) {
+			c.Set("user", user)
+		}, RequirePermission(rbac.ResourceSites), handler)
+		return serviceTokenRequest(router, method, "/api/sites", "").Code
+	}
+
+	viewer := &model.User{Model: model.Model{ID: 2}, Role: model.RoleViewer}
+	require.Equal(t, http.StatusNoContent, request(viewer, http.MethodGet))
+	require.Equal(t, http.StatusForbidden, request(viewer, http.MethodPost))
+
+	// The initial user keeps full access whatever role is stored.
+	initUser := &model.User{Model: model.Model{ID: 1}, Role: model.RoleViewer}
+	require.Equal(t, http.StatusNoContent, request(initUser, http.MethodPost))
+
+	legacy := &model.User{Model: model.Model{ID: 3}}
+	require.Equal(t, http.StatusNoContent, request(legacy, http.MethodPost))
+
+	unknown := &model.User{Model: model.Model{ID: 4}, Role: "missing"}
+	require.Equal(t, http.StatusForbidden, request(unknown, http.MethodGet))
+}
+
+func TestRequireWritePermissionIgnoresTheMethod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(user *model.User) int {
+		router := gin.New()
+		handler := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+		router.GET("/api/certs/:id/deploy_hooks", func(c *gin.Context) {
+			c.Set("user", user)
+		}, RequirePermission(rbac.ResourceCertificates), RequireWritePermission(rbac.ResourceTerminal), handler)
+		return serviceTokenRequest(router, http.MethodGet, "/api/certs/1/deploy_hooks", "").Code
+	}
+
+	operator := &model.User{Model: model.Model{ID: 2}, Role: model.RoleOperator}
+	require.Equal(t, http.StatusForbidden, request(operator), "operators have no terminal access")
+
+	admin := &model.User{Model: model.Model{ID: 4}, Role: model.RoleAdmin}
+	require.Equal(t, http.StatusNoContent, request(admin))
+}
+
+func TestRequirePermissionTreatsNodeSecretsAsWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(user *model.User) int {
+		router := gin.New()
+		handler := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+		router.GET("/api/nodes/:id/secret", func(c *gin.Context) {
+			c.Set("user", user)
+		}, RequirePermission(rbac.ResourceCluster), handler)
+		return serviceTokenRequest(router, http.MethodGet, "/api/nodes/1/secret", "").Code
+	}
+
+	viewer := &model.User{Model: model.Model{ID: 2}, Role: model.RoleViewer}
+	require.Equal(t, http.StatusForbidden, request(viewer))
+
+	admin := &model.User{Model: model.Model{ID: 4}, Role: model.RoleAdmin}
+	require.Equal(t, http.StatusNoContent, request(admin))
+}
+
+func TestWhenChecksOnlyMatchingRequests(t *testing.T) {
+	setupAPIServiceTokenTest(t)
+
+	_, sitesToken, err := internalmcp.CreateServiceToken("sites", []string{rbac.Permission(rbac.ResourceSites, rbac.ActionWrite)}, nil, 14)
+	require.NoError(t, err)
+	_, dnsToken, err := internalmcp.CreateServiceToken("sites and dns", []string{
+		rbac.Permission(rbac.ResourceSites, rbac.ActionWrite),
+		rbac.Permission(rbac.ResourceDNS, rbac.ActionWrite),
+	}, nil, 14)
+	require.NoError(t, err)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	deletesRecords := func(c *gin.Context) bool { return c.Query("delete_dns_records") == "true" }
+	router := gin.New()
+	router.DELETE("/api/sites/:name", AuthRequired(), RequirePermission(rbac.ResourceSites),
+		When(deletesRecords, RequireWritePermission(rbac.ResourceDNS)), ok)
+
+	require.Equal(t, http.StatusForbidden, serviceTokenRequest(router, http.MethodDelete, "/api/sites/example?delete_dns_records=true", sitesToken).Code)
+	require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodDelete, "/api/sites/example", sitesToken).Code)
+	require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodDelete, "/api/sites/example?delete_dns_records=true", dnsToken).Code)
+}
@@ -61,7 +61,7 @@ func RequireSecureSession() gin.HandlerFunc {
 		}
 		if token, ok := c.Get(internalmcp.ServiceTokenPrincipalKey); ok {
 			principal, valid := token.(*internalmcp.ServiceTokenPrincipal)
-			if !valid || !principal.HasScope(model.APITokenScopeWrite) {
+			if !valid || !principal.HasAPIScope(model.APITokenScopeWrite) {
 				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 					"message": "Service token write scope is required",
 				})
@@ -95,3 +95,17 @@ func TestAuthorizationTokenAcceptsBearerAndRejectsAmbientCredentials(t *testing.
 	context.Request.Header.Set("Authorization", "  Bearer token-value  ")
 	require.Equal(t, "token-value", getToken(context))
 }
+
+func TestAPIServiceTokenReadScopeCannotStartLogExports(t *testing.T) {
+	readToken, writeToken, _ := setupAPIServiceTokenTest(t)
+
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router := gin.New()
+	router.POST("/api/nginx_log/search", AuthRequired(), ok)
+	for _, path := range []string{"/api/nginx_log/export", "/api/nginx_log/export/dashboard", "/api/nginx_log/export/geo"} {
+		router.POST(path, AuthRequired(), ok)
+		require.Equal(t, http.StatusForbidden, serviceTokenRequest(router, http.MethodPost, path, readToken).Code, path)
+		require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodPost, path, writeToken).Code, path)
+	}
+	require.Equal(t, http.StatusNoContent, serviceTokenRequest(router, http.MethodPost, "/api/nginx_log/search", readToken).Code)
+}
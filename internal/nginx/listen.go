@@ -0,0 +1,32 @@
+package nginx
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LocalTLSAddress turns the parameters of a listen directive into a local
+// address to dial for a TLS handshake, reporting false for listeners that do
+// not terminate TLS over TCP. Wildcard addresses are reached over the loopback.
+func LocalTLSAddress(params string) (string, bool) {
+	fields := strings.Fields(params)
+	if len(fields) == 0 || !slices.Contains(fields[1:], "ssl") || strings.HasPrefix(fields[0], "unix:") {
+		return "", false
+	}
+
+	host, port := "", fields[0]
+	if h, p, err := net.SplitHostPort(fields[0]); err == nil {
+		host, port = h, p
+	} else if _, err := strconv.Atoi(fields[0]); err != nil {
+		host, port = fields[0], "443"
+	}
+	switch host {
+	case "", "*", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port), true
+}
@@ -0,0 +1,25 @@
+package nginx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalTLSAddress(t *testing.T) {
+	cases := map[string]string{
+		"443 ssl":                  "127.0.0.1:443",
+		"*:8443 ssl http2":         "127.0.0.1:8443",
+		"[::]:443 ssl":             "[::1]:443",
+		"10.0.0.1:443 ssl":         "10.0.0.1:443",
+		"example.com ssl":          "example.com:443",
+		"443 quic reuseport":       "",
+		"80":                       "",
+		"unix:/run/nginx.sock ssl": "",
+	}
+	for params, want := range cases {
+		address, ok := LocalTLSAddress(params)
+		assert.Equal(t, want != "", ok, params)
+		assert.Equal(t, want, address, params)
+	}
+}
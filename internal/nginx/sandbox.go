@@ -21,6 +21,13 @@ type NamespaceInfo struct {
 
 // SandboxTestConfigWithPaths tests nginx config in an isolated sandbox with provided paths.
 func SandboxTestConfigWithPaths(namespace *NamespaceInfo, sitePaths, streamPaths []string) TestConfigResult {
+	return SandboxTestConfigWithOverrides(namespace, sitePaths, streamPaths, nil)
+}
+
+// SandboxTestConfigWithOverrides tests nginx config in an isolated sandbox in
+// which the files keyed by their absolute path in overrides are replaced with
+// the given content. This validates pending edits before they reach the disk.
+func SandboxTestConfigWithOverrides(namespace *NamespaceInfo, sitePaths, streamPaths []string, overrides map[string]string) TestConfigResult {
 	// Remote namespaces have no local configuration to validate.
 	if namespace != nil && namespace.DeployMode == "remote" {
 		return TestConfigResult{
@@ -58,7 +65,7 @@ func SandboxTestConfigWithPaths(namespace *NamespaceInfo, sitePaths, streamPaths
 	}
 
 	// Create sandbox and test
-	sandbox, err := createSandbox(namespace, sitePaths, streamPaths)
+	sandbox, err := createSandboxWithOverrides(namespace, sitePaths, streamPaths, overrides)
 	if err != nil {
 		logger.Errorf("Failed to create sandbox: %v", err)
 		return NewSandboxBuildFailureResult(err)
@@ -87,6 +94,12 @@ type Sandbox struct {
 
 // createSandbox creates an isolated nginx configuration environment for testing
 func createSandbox(namespace *NamespaceInfo, sitePaths, streamPaths []string) (*Sandbox, error) {
+	return createSandboxWithOverrides(namespace, sitePaths, streamPaths, nil)
+}
+
+// createSandboxWithOverrides creates a sandbox in which the overridden files
+// carry their pending content instead of the content on disk
+func createSandboxWithOverrides(namespace *NamespaceInfo, sitePaths, streamPaths []string, overrides map[string]string) (*Sandbox, error) {
 	// Create temp directory for sandbox
 	tempDir, err := os.MkdirTemp("", "nginx-ui-sandbox-*")
 	if err != nil {
@@ -98,6 +111,7 @@ func createSandbox(namespace *NamespaceInfo, sitePaths, streamPaths []string) (*
 		Namespace: namespace,
 	}
 	builder := newSandboxBuilder(tempDir)
+	builder.overrides = overrides
 
 	// Copy full nginx conf directory to sandbox, excluding sites-* and streams-*
 	if err := copyConfigBaseExceptSitesStreams(tempDir); err != nil {
@@ -155,7 +169,7 @@ func (s *Sandbox) Cleanup() {
 func generateSandboxConfig(namespace *NamespaceInfo, siteFiles, streamFiles []string, builder *sandboxBuilder) (string, error) {
 	// Read the main nginx.conf to get basic structure
 	mainConfPath := GetConfEntryPath()
-	mainConf, err := os.ReadFile(mainConfPath)
+	mainConf, err := builder.readFile(mainConfPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read main nginx.conf: %w", err)
 	}
@@ -298,7 +312,7 @@ func collectAndCopyNamespaceEnabled(_ *NamespaceInfo, sitePaths, streamPaths []s
 		if !helper.FileExists(srcPath) {
 			srcPath = filepath.Join(availableRoot, relativePath)
 		}
-		content, rErr := os.ReadFile(srcPath)
+		content, rErr := builder.readFile(srcPath)
 		if rErr != nil {
 			return "", fmt.Errorf("read %s content %s: %w", kind, srcPath, rErr)
 		}
@@ -384,6 +398,7 @@ type sandboxBuilder struct {
 	sandboxDir string
 	confBase   string
 	mirrored   map[string]bool
+	overrides  map[string]string
 }
 
 func newSandboxBuilder(sandboxDir string) *sandboxBuilder {
@@ -394,6 +409,15 @@ func newSandboxBuilder(sandboxDir string) *sandboxBuilder {
 	}
 }
 
+// readFile returns the pending content of sourcePath if it is overridden and
+// the content on disk otherwise.
+func (b *sandboxBuilder) readFile(sourcePath string) ([]byte, error) {
+	if content, ok := b.overrides[filepath.Clean(sourcePath)]; ok {
+		return []byte(content), nil
+	}
+	return os.ReadFile(sourcePath)
+}
+
 func (b *sandboxBuilder) rewriteConfigContent(content string, sourcePath string) (string, error) {
 	tokens, err := tokenizeNginxConfig(content)
 	if err != nil {
@@ -539,7 +563,7 @@ func (b *sandboxBuilder) mirrorDependency(sourcePath string) error {
 	}
 	b.mirrored[sourcePath] = true
 
-	data, err := os.ReadFile(sourcePath)
+	data, err := b.readFile(sourcePath)
 	if err != nil {
 		return &SandboxBuildError{
 			Category: ErrorCategorySandboxBuildError,
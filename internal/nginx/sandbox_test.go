@@ -803,3 +803,35 @@ func TestNginxStringCacheSerializesColdLoad(t *testing.T) {
 		t.Fatalf("loader called %d times, want once", got)
 	}
 }
+
+func TestSandboxOverridesReplaceContentOnDisk(t *testing.T) {
+	withSandboxPaths(t, map[string]string{
+		"nginx.conf":    "events {}\nhttp { include conf.d/*.conf; }\n",
+		"conf.d/a.conf": "map $host $a { default old; }\n",
+	}, func(confDir string, _ string) {
+		dependencyPath := filepath.Join(confDir, "conf.d", "a.conf")
+		sandbox, err := createSandboxWithOverrides(&NamespaceInfo{Name: "pending"}, nil, nil, map[string]string{
+			dependencyPath: "map $host $a { default new; }\n",
+		})
+		if err != nil {
+			t.Fatalf("createSandboxWithOverrides() error = %v", err)
+		}
+		defer sandbox.Cleanup()
+
+		mirrored, err := os.ReadFile(filepath.Join(sandbox.Dir, "conf.d", "a.conf"))
+		if err != nil {
+			t.Fatalf("read mirrored dependency: %v", err)
+		}
+		if !strings.Contains(string(mirrored), "default new") {
+			t.Fatalf("mirrored dependency = %q, want overridden content", mirrored)
+		}
+
+		onDisk, err := os.ReadFile(dependencyPath)
+		if err != nil {
+			t.Fatalf("read dependency: %v", err)
+		}
+		if !strings.Contains(string(onDisk), "default old") {
+			t.Fatalf("dependency on disk = %q, want it untouched", onDisk)
+		}
+	})
+}
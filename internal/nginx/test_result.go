@@ -127,3 +127,8 @@ func DetectErrorCategory(message string, stdErr error) ErrorCategory {
 		return ErrorCategoryNginxRuntimeError
 	}
 }
+
+// IsError reports whether the test failed or the sandbox could not be built.
+func (r TestConfigResult) IsError() bool {
+	return r.Level > Warn || r.SandboxStatus == SandboxStatusFailed
+}
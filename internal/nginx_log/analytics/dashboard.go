@@ -10,7 +10,8 @@ import (
 	"github.com/uozi-tech/cosy/logger"
 )
 
-// GetDashboardAnalytics generates comprehensive dashboard analytics
+// GetDashboardAnalytics generates comprehensive dashboard analytics. Ranges
+// older than the raw retention of the log are read from its traffic rollups.
 func (s *service) GetDashboardAnalytics(ctx context.Context, req *DashboardQueryRequest) (*DashboardAnalytics, error) {
 	if req == nil {
 		return nil, fmt.Errorf("request cannot be nil")
@@ -20,6 +21,17 @@ func (s *service) GetDashboardAnalytics(ctx context.Context, req *DashboardQuery
 		return nil, fmt.Errorf("invalid time range: %w", err)
 	}
 
+	// Raw documents before the retention boundary are gone, their part of the
+	// range is served from the traffic rollups
+	if boundary := purgedBefore(req.LogPath); boundary > req.StartTime {
+		return s.getDashboardAnalyticsWithRollups(ctx, req, boundary)
+	}
+
+	return s.getRawDashboardAnalytics(ctx, req)
+}
+
+// getRawDashboardAnalytics generates dashboard analytics from the indexed documents
+func (s *service) getRawDashboardAnalytics(ctx context.Context, req *DashboardQueryRequest) (*DashboardAnalytics, error) {
 	searchReq := &searcher.SearchRequest{
 		StartTime:      &req.StartTime,
 		EndTime:        &req.EndTime,
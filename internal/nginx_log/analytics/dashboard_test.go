@@ -3,8 +3,11 @@ package analytics
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/rollup"
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -456,4 +459,69 @@ func TestService_calculateDashboardSummaryWithoutAggregates(t *testing.T) {
 	assert.Equal(t, int64(0), summary.TotalTraffic)
 	assert.InDelta(t, 0.0, summary.AvgQPS, 0.000001)
 	assert.InDelta(t, 0.0, summary.PeakQPS, 0.000001)
-}
\ No newline at end of file
+}
+func TestService_GetDashboardAnalytics_ReadsRollupsBeforeRetention(t *testing.T) {
+	mockSearcher := &MockSearcher{}
+	s := NewService(mockSearcher)
+
+	ctx := context.Background()
+	day1 := rollup.DayStart(time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local))
+	day2, day3 := day1.AddDate(0, 0, 1), day1.AddDate(0, 0, 2)
+	req := &DashboardQueryRequest{
+		StartTime: day1.Unix(),
+		EndTime:   day3.Add(12 * time.Hour).Unix(),
+		LogPath:   "/var/log/nginx/access.log",
+	}
+
+	rollups := []*model.NginxLogRollup{
+		{Granularity: model.RollupGranularityDay, BucketStart: day1.Unix(), Requests: 10, UniqueVisitors: 4, Bytes: 1000,
+			TopPaths: []model.RollupCount{{Key: "/a", Count: 6}, {Key: "/b", Count: 4}}},
+		{Granularity: model.RollupGranularityDay, BucketStart: day2.Unix(), Requests: 5, UniqueVisitors: 2, Bytes: 500,
+			TopPaths: []model.RollupCount{{Key: "/b", Count: 5}}},
+		{Granularity: model.RollupGranularityHour, BucketStart: day1.Add(time.Hour).Unix(), Requests: 10, UniqueVisitors: 4},
+	}
+	originalPurgedBefore, originalFindRollups := purgedBefore, findRollups
+	purgedBefore = func(logPath string) int64 {
+		assert.Equal(t, req.LogPath, logPath)
+		return day3.Unix()
+	}
+	findRollups = func(logPath, granularity string, start, end int64) ([]*model.NginxLogRollup, error) {
+		assert.Equal(t, day3.Unix(), end)
+		var found []*model.NginxLogRollup
+		for _, r := range rollups {
+			if r.Granularity == granularity && r.BucketStart >= start && r.BucketStart < end {
+				found = append(found, r)
+			}
+		}
+		return found, nil
+	}
+	t.Cleanup(func() { purgedBefore, findRollups = originalPurgedBefore, originalFindRollups })
+
+	// The raw part of the range, from the third day on, holds no documents
+	mockSearcher.On("Search", ctx, mock.MatchedBy(func(r *searcher.SearchRequest) bool {
+		return *r.StartTime >= day3.Unix()-12*3600
+	})).Return(&searcher.SearchResult{}, nil)
+
+	result, err := s.GetDashboardAnalytics(ctx, req)
+	assert.NoError(t, err)
+
+	assert.Len(t, result.DailyStats, 3)
+	assert.Equal(t, 10, result.DailyStats[0].PV)
+	assert.Equal(t, 4, result.DailyStats[0].UV)
+	assert.Equal(t, 5, result.DailyStats[1].PV)
+	assert.Equal(t, 0, result.DailyStats[2].PV)
+
+	assert.Equal(t, []URLAccessStats{{URL: "/b", Visits: 9, Percent: 60}, {URL: "/a", Visits: 6, Percent: 40}}, result.TopURLs)
+	assert.NotNil(t, result.Browsers)
+
+	summary := result.Summary
+	assert.Equal(t, 15, summary.TotalPV)
+	assert.Equal(t, 6, summary.TotalUV)
+	assert.Equal(t, int64(1500), summary.TotalTraffic)
+	assert.InDelta(t, 5.0, summary.AvgDailyPV, 0.01)
+	assert.Equal(t, 10, summary.PeakHourTraffic)
+	assert.InDelta(t, 10.0/3600, summary.PeakQPS, 0.000001)
+	assert.Equal(t, day3.Unix(), summary.RollupBefore)
+
+	mockSearcher.AssertExpectations(t)
+}
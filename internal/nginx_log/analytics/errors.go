@@ -0,0 +1,137 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+)
+
+// SetErrorSearcher sets the searcher over the error log index
+func (s *service) SetErrorSearcher(es *searcher.ErrorSearcher) {
+	s.errorSearcherMu.Lock()
+	defer s.errorSearcherMu.Unlock()
+	s.errorSearcher = es
+}
+
+// getErrorSearcher returns the error log searcher or an error if error logs
+// are not indexed
+func (s *service) getErrorSearcher() (*searcher.ErrorSearcher, error) {
+	s.errorSearcherMu.RLock()
+	defer s.errorSearcherMu.RUnlock()
+	if s.errorSearcher == nil {
+		return nil, fmt.Errorf("error log index is not available")
+	}
+	return s.errorSearcher, nil
+}
+
+// severeErrorLevels returns the levels from "error" up to "emerg"
+func severeErrorLevels() []string {
+	minSeverity := parser.ErrorLevelSeverity("error")
+	levels := make([]string, 0, len(parser.ErrorLogLevels))
+	for _, level := range parser.ErrorLogLevels {
+		if parser.ErrorLevelSeverity(level) >= minSeverity {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// GetErrorTimeline counts error log lines per interval, both in total and at
+// level error or above, and derives the per-minute rate of each bucket
+func (s *service) GetErrorTimeline(ctx context.Context, req *ErrorTimelineRequest) (*ErrorTimeline, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+	if req.StartTime <= 0 || req.EndTime <= 0 {
+		return nil, fmt.Errorf("start and end time are required")
+	}
+	if err := s.ValidateTimeRange(req.StartTime, req.EndTime); err != nil {
+		return nil, fmt.Errorf("invalid time range: %w", err)
+	}
+
+	es, err := s.getErrorSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := req.Interval
+	if interval <= 0 || (req.EndTime-req.StartTime)/interval > MaxTimelinePoints {
+		interval = searcher.AutoTimelineInterval(req.StartTime, req.EndTime)
+	}
+
+	searchReq := &searcher.ErrorSearchRequest{
+		LogPaths:         req.LogPaths,
+		StartTime:        &req.StartTime,
+		EndTime:          &req.EndTime,
+		Servers:          req.Servers,
+		Limit:            -1,
+		FacetFields:      []string{searcher.ErrorFieldLevel},
+		FacetSize:        len(parser.ErrorLogLevels),
+		TimelineInterval: interval,
+	}
+	totalResult, err := es.Search(ctx, searchReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search error logs for timeline: %w", err)
+	}
+
+	severeReq := *searchReq
+	severeReq.Levels = severeErrorLevels()
+	severeReq.FacetFields = nil
+	severeResult, err := es.Search(ctx, &severeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search severe error logs for timeline: %w", err)
+	}
+
+	timeline := &ErrorTimeline{
+		Interval: interval,
+		Total:    int(totalResult.TotalHits),
+		Severe:   int(severeResult.TotalHits),
+		Levels:   make(map[string]int),
+		Points:   make([]ErrorTimelinePoint, 0, len(totalResult.Timeline)),
+	}
+
+	if facet, ok := totalResult.Facets[searcher.ErrorFieldLevel]; ok {
+		for _, term := range facet.Terms {
+			timeline.Levels[term.Term] = term.Count
+		}
+	}
+
+	minutes := float64(interval) / 60
+	for i, bucket := range totalResult.Timeline {
+		point := ErrorTimelinePoint{
+			Timestamp: bucket.Start,
+			Total:     bucket.Count,
+			Rate:      float64(bucket.Count) / minutes,
+		}
+		if i < len(severeResult.Timeline) {
+			point.Severe = severeResult.Timeline[i].Count
+		}
+		if point.Rate > timeline.PeakRate {
+			timeline.PeakRate = point.Rate
+		}
+		timeline.Points = append(timeline.Points, point)
+	}
+
+	return timeline, nil
+}
+
+// GetTopErrorMessages returns the largest clusters of error messages that only
+// differ in ids, addresses or numbers
+func (s *service) GetTopErrorMessages(ctx context.Context, req *searcher.ErrorSearchRequest, size int) ([]*searcher.MessageCluster, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	es, err := s.getErrorSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := es.TopMessages(ctx, req, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cluster error messages: %w", err)
+	}
+	return clusters, nil
+}
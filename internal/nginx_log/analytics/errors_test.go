@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newErrorTestService(t *testing.T, base int64) Service {
+	t.Helper()
+
+	index, err := bleve.NewMemOnly(indexer.CreateErrorLogIndexMapping())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = index.Close() })
+
+	docs := []indexer.ErrorLogDocument{
+		{Timestamp: base, Level: "error", Message: "upstream timed out (110: Connection timed out) while reading upstream 10.0.0.1:80"},
+		{Timestamp: base + 30, Level: "error", Message: "upstream timed out (110: Connection timed out) while reading upstream 10.0.0.2:80"},
+		{Timestamp: base + 45, Level: "warn", Message: "an upstream response is buffered to a temporary file"},
+		{Timestamp: base + 300, Level: "crit", Message: "SSL_do_handshake() failed while SSL handshaking"},
+		{Timestamp: base + 320, Level: "notice", Message: "signal process started"},
+	}
+	for i, doc := range docs {
+		doc.MessagePattern = parser.MessagePattern(doc.Message)
+		doc.MainLogPath = "/var/log/nginx/error.log"
+		require.NoError(t, index.Index(fmt.Sprintf("doc%d", i), doc))
+	}
+
+	s := NewService(&MockSearcher{})
+	s.SetErrorSearcher(searcher.NewErrorSearcher(index, nil))
+	return s
+}
+
+func TestGetErrorTimeline(t *testing.T) {
+	const base = int64(1_699_999_800) // aligned to five minutes
+	s := newErrorTestService(t, base)
+
+	timeline, err := s.GetErrorTimeline(context.Background(), &ErrorTimelineRequest{
+		StartTime: base,
+		EndTime:   base + 599,
+		Interval:  300,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(300), timeline.Interval)
+	assert.Equal(t, 5, timeline.Total)
+	assert.Equal(t, 3, timeline.Severe)
+	assert.Equal(t, map[string]int{"error": 2, "warn": 1, "crit": 1, "notice": 1}, timeline.Levels)
+
+	require.Len(t, timeline.Points, 2)
+	assert.Equal(t, ErrorTimelinePoint{Timestamp: base, Total: 3, Severe: 2, Rate: 0.6}, timeline.Points[0])
+	assert.Equal(t, ErrorTimelinePoint{Timestamp: base + 300, Total: 2, Severe: 1, Rate: 0.4}, timeline.Points[1])
+	assert.InDelta(t, 0.6, timeline.PeakRate, 1e-9)
+}
+
+func TestGetErrorTimelineWithoutErrorIndex(t *testing.T) {
+	s := NewService(&MockSearcher{})
+
+	_, err := s.GetErrorTimeline(context.Background(), &ErrorTimelineRequest{StartTime: 1, EndTime: 2})
+	assert.Error(t, err)
+}
+
+func TestGetTopErrorMessages(t *testing.T) {
+	s := newErrorTestService(t, 1_699_999_800)
+
+	clusters, err := s.GetTopErrorMessages(context.Background(), &searcher.ErrorSearchRequest{Levels: []string{"error", "crit"}}, 5)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	assert.Equal(t, "upstream timed out (<n>: Connection timed out) while reading upstream <ip>", clusters[0].Pattern)
+	assert.Equal(t, 2, clusters[0].Count)
+}
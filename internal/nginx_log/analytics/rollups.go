@@ -0,0 +1,198 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/rollup"
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+// topURLLimit matches the facet size of the raw top URL query
+const topURLLimit = 100
+
+var (
+	purgedBefore = rollup.PurgedBefore
+	findRollups  = rollup.Find
+)
+
+// getDashboardAnalyticsWithRollups generates dashboard analytics for a range
+// starting before boundary, the time the raw documents of the log were purged
+// before. The part of the range before boundary is read from the rollups, the
+// rest from the index.
+func (s *service) getDashboardAnalyticsWithRollups(ctx context.Context, req *DashboardQueryRequest, boundary int64) (*DashboardAnalytics, error) {
+	raw := &DashboardAnalytics{}
+	if req.EndTime > boundary {
+		rawReq := *req
+		rawReq.StartTime = boundary
+		var err error
+		if raw, err = s.getRawDashboardAnalytics(ctx, &rawReq); err != nil {
+			return nil, err
+		}
+	}
+
+	// Hourly stats carry the same 12 hour timezone buffer as the raw ones
+	hourlyStart := time.Unix(req.StartTime, 0).UTC().Add(-12 * time.Hour)
+	hourlyEnd := time.Unix(req.EndTime, 0).UTC().Add(12 * time.Hour)
+
+	hourlyRollups, err := findRollups(req.LogPath, model.RollupGranularityHour, hourlyStart.Unix(), min(hourlyEnd.Unix(), boundary))
+	if err != nil {
+		return nil, err
+	}
+	dailyRollups, err := findRollups(req.LogPath, model.RollupGranularityDay,
+		rollup.DayStart(time.Unix(req.StartTime, 0)).Unix(), min(req.EndTime, boundary))
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &DashboardAnalytics{
+		HourlyStats:      mergeHourlyStats(raw.HourlyStats, hourlyRollups, hourlyStart, hourlyEnd, boundary),
+		DailyStats:       mergeDailyStats(raw.DailyStats, dailyRollups, req.StartTime, req.EndTime, boundary),
+		Browsers:         nonNil(raw.Browsers),
+		OperatingSystems: nonNil(raw.OperatingSystems),
+		Devices:          nonNil(raw.Devices),
+	}
+
+	// Range-wide figures of the rolled up part come from the daily rollups,
+	// which outlive the hourly ones
+	summary := raw.Summary
+	topURLs := make(map[string]int)
+	for _, stat := range raw.TopURLs {
+		topURLs[stat.URL] += stat.Visits
+	}
+	for _, r := range dailyRollups {
+		summary.TotalPV += int(r.Requests)
+		summary.TotalUV += int(r.UniqueVisitors)
+		summary.TotalTraffic += r.Bytes
+		for _, path := range r.TopPaths {
+			topURLs[path.Key] += int(path.Count)
+		}
+	}
+	for _, r := range hourlyRollups {
+		summary.PeakQPS = max(summary.PeakQPS, float64(r.Requests)/3600)
+	}
+
+	summary.AvgDailyUV, summary.AvgDailyPV = 0, 0
+	if days := len(analytics.DailyStats); days > 0 {
+		var sumPV int
+		for _, daily := range analytics.DailyStats {
+			sumPV += daily.PV
+		}
+		summary.AvgDailyUV = float64(summary.TotalUV) / float64(days)
+		summary.AvgDailyPV = float64(sumPV) / float64(days)
+	}
+
+	summary.PeakHour, summary.PeakHourTraffic = 0, 0
+	for _, hourly := range analytics.HourlyStats {
+		if hourly.PV > summary.PeakHourTraffic {
+			summary.PeakHour = hourly.Hour
+			summary.PeakHourTraffic = hourly.PV
+		}
+	}
+
+	summary.AvgQPS = 0
+	if rangeSeconds := req.EndTime - req.StartTime; rangeSeconds > 0 {
+		summary.AvgQPS = float64(summary.TotalPV) / float64(rangeSeconds)
+	}
+	summary.RollupBefore = boundary
+
+	analytics.TopURLs = mergeTopURLs(topURLs, summary.TotalPV)
+	analytics.Summary = summary
+
+	return analytics, nil
+}
+
+// mergeHourlyStats fills the hourly buckets of [start, end) from the rollups
+// before boundary and from the raw stats after it
+func mergeHourlyStats(raw []HourlyAccessStats, rollups []*model.NginxLogRollup, start, end time.Time, boundary int64) []HourlyAccessStats {
+	rawByHour := make(map[int64]HourlyAccessStats, len(raw))
+	for _, stat := range raw {
+		rawByHour[stat.Timestamp] = stat
+	}
+	rollupByHour := make(map[int64]*model.NginxLogRollup, len(rollups))
+	for _, r := range rollups {
+		rollupByHour[r.BucketStart] = r
+	}
+
+	stats := make([]HourlyAccessStats, 0)
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		timestamp := t.Unix()
+		stat := HourlyAccessStats{Hour: t.Hour(), Timestamp: timestamp}
+		if timestamp < boundary {
+			if r, ok := rollupByHour[timestamp]; ok {
+				stat.PV = int(r.Requests)
+				stat.UV = int(r.UniqueVisitors)
+			}
+		} else if rawStat, ok := rawByHour[timestamp]; ok {
+			stat = rawStat
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// mergeDailyStats fills the days of [start, end] from the rollups for days
+// starting before boundary and from the raw stats for the others
+func mergeDailyStats(raw []DailyAccessStats, rollups []*model.NginxLogRollup, start, end, boundary int64) []DailyAccessStats {
+	rawByDate := make(map[string]DailyAccessStats, len(raw))
+	for _, stat := range raw {
+		rawByDate[stat.Date] = stat
+	}
+	rollupByDate := make(map[string]*model.NginxLogRollup, len(rollups))
+	for _, r := range rollups {
+		rollupByDate[time.Unix(r.BucketStart, 0).Format("2006-01-02")] = r
+	}
+
+	stats := make([]DailyAccessStats, 0)
+	seen := make(map[string]bool)
+	endTime := time.Unix(end, 0)
+	for t := time.Unix(start, 0); !t.After(endTime); t = t.AddDate(0, 0, 1) {
+		date := t.Format("2006-01-02")
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+
+		stat := DailyAccessStats{Date: date, Timestamp: t.Unix()}
+		if rollup.DayStart(t).Unix() < boundary {
+			if r, ok := rollupByDate[date]; ok {
+				stat.PV = int(r.Requests)
+				stat.UV = int(r.UniqueVisitors)
+			}
+		} else if rawStat, ok := rawByDate[date]; ok {
+			stat = rawStat
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// mergeTopURLs sorts the visits per URL and keeps the most visited ones
+func mergeTopURLs(visits map[string]int, totalPV int) []URLAccessStats {
+	stats := make([]URLAccessStats, 0, len(visits))
+	for url, count := range visits {
+		percent := 0.0
+		if totalPV > 0 {
+			percent = float64(count) / float64(totalPV) * 100
+		}
+		stats = append(stats, URLAccessStats{URL: url, Visits: count, Percent: percent})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Visits != stats[j].Visits {
+			return stats[i].Visits > stats[j].Visits
+		}
+		return stats[i].URL < stats[j].URL
+	})
+	if len(stats) > topURLLimit {
+		stats = stats[:topURLLimit]
+	}
+	return stats
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -20,6 +20,10 @@ type Service interface {
 	GetGeoDistributionByCountry(ctx context.Context, req *GeoQueryRequest, countryCode string) (*GeoDistribution, error)
 	GetTopCountries(ctx context.Context, req *GeoQueryRequest) ([]CountryStats, error)
 
+	SetErrorSearcher(es *searcher.ErrorSearcher)
+	GetErrorTimeline(ctx context.Context, req *ErrorTimelineRequest) (*ErrorTimeline, error)
+	GetTopErrorMessages(ctx context.Context, req *searcher.ErrorSearchRequest, size int) ([]*searcher.MessageCluster, error)
+
 	ValidateLogPath(logPath string) error
 	ValidateTimeRange(startTime, endTime int64) error
 
@@ -30,6 +34,9 @@ type Service interface {
 type service struct {
 	searcher searcher.SearcherInterface
 
+	errorSearcherMu sync.RWMutex
+	errorSearcher   *searcher.ErrorSearcher
+
 	counterMu          sync.Mutex
 	cardinalityCounter *searcher.Counter
 	counterShards      []bleve.Index // Shards the counter was built from, to detect swaps
@@ -91,6 +91,10 @@ type DashboardSummary struct {
 	PeakHourTraffic int     `json:"peak_hour_traffic"`
 	AvgQPS          float64 `json:"avg_qps"`  // Total requests divided by the length of the queried range
 	PeakQPS         float64 `json:"peak_qps"` // Busiest minute in the range, expressed per second
+	// RollupBefore is set when the range starts before the raw retention of the
+	// log: figures before it come from daily rollups, so UV counts a visitor
+	// once per day and browsers, systems and devices only cover the raw part
+	RollupBefore int64 `json:"rollup_before,omitempty"`
 }
 
 // HourlyAccessStats represents hourly access statistics
@@ -194,6 +198,33 @@ type CityStats struct {
 	Percent     float64 `json:"percent"`
 }
 
+// ErrorTimelineRequest represents a request for the error log timeline
+type ErrorTimelineRequest struct {
+	LogPaths  []string `json:"log_paths"` // Main log paths of the error log groups
+	StartTime int64    `json:"start_time"`
+	EndTime   int64    `json:"end_time"`
+	Interval  int64    `json:"interval"` // Bucket size in seconds, 0 picks one from the range
+	Servers   []string `json:"servers"`
+}
+
+// ErrorTimeline represents error log volume over time
+type ErrorTimeline struct {
+	Interval int64                `json:"interval"`
+	Total    int                  `json:"total"`
+	Severe   int                  `json:"severe"` // Lines at level error or above
+	Levels   map[string]int       `json:"levels"`
+	PeakRate float64              `json:"peak_rate"` // Highest per-minute rate of a bucket
+	Points   []ErrorTimelinePoint `json:"points"`
+}
+
+// ErrorTimelinePoint represents the error log lines of one timeline bucket
+type ErrorTimelinePoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Total     int     `json:"total"`
+	Severe    int     `json:"severe"`
+	Rate      float64 `json:"rate"` // Lines per minute
+}
+
 // TimelineRequest represents a request for timeline data
 type TimelineRequest struct {
 	StartTime int64  `json:"start_time"` // Unix timestamp
@@ -372,4 +403,5 @@ const (
 	MaxLimit            = 1000
 	DefaultCacheTTL     = 5 * time.Minute
 	MinTimelineInterval = time.Hour
+	MaxTimelinePoints   = 500 // Buckets of an error timeline with an explicit interval
 )
@@ -0,0 +1,127 @@
+package nginx_log
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// Error log index instances, guarded by servicesMutex
+var (
+	globalErrorIndex    *indexer.ErrorIndex
+	globalErrorSearcher *searcher.ErrorSearcher
+)
+
+// openErrorIndexLocked opens the error log index next to the access log shards
+// and attaches it to the analytics service. Callers must hold servicesMutex.
+func openErrorIndexLocked(indexPath string) {
+	errorIndex, err := indexer.OpenErrorIndex(indexPath)
+	if err != nil {
+		logger.Errorf("Failed to open error log index, error logs will not be indexed: %v", err)
+		return
+	}
+
+	globalErrorIndex = errorIndex
+	globalErrorSearcher = searcher.NewErrorSearcher(errorIndex.Index(), searcher.DefaultSearcherConfig())
+	attachErrorSearcherLocked()
+}
+
+// attachErrorSearcherLocked hands the error searcher to the current analytics
+// service. Callers must hold servicesMutex.
+func attachErrorSearcherLocked() {
+	if globalAnalytics != nil && globalErrorSearcher != nil {
+		globalAnalytics.SetErrorSearcher(globalErrorSearcher)
+	}
+}
+
+// closeErrorIndexLocked closes the error log index. Callers must hold servicesMutex.
+func closeErrorIndexLocked() {
+	if globalErrorIndex != nil {
+		if err := globalErrorIndex.Close(); err != nil {
+			logger.Errorf("Failed to close error log index: %v", err)
+		}
+	}
+	globalErrorIndex = nil
+	globalErrorSearcher = nil
+}
+
+// GetErrorIndex returns the global error log index
+func GetErrorIndex() *indexer.ErrorIndex {
+	servicesMutex.RLock()
+	defer servicesMutex.RUnlock()
+
+	if !servicesInitialized {
+		return nil
+	}
+
+	return globalErrorIndex
+}
+
+// GetErrorSearcher returns the global error log searcher
+func GetErrorSearcher() *searcher.ErrorSearcher {
+	servicesMutex.RLock()
+	defer servicesMutex.RUnlock()
+
+	if !servicesInitialized {
+		return nil
+	}
+
+	return globalErrorSearcher
+}
+
+// IndexErrorLogGroup (re)indexes the error log group of mainLogPath and
+// records its index metadata and status. It returns the time range of the
+// indexed lines.
+func IndexErrorLogGroup(ctx context.Context, mainLogPath string) (*time.Time, *time.Time, error) {
+	errorIndex := GetErrorIndex()
+	if errorIndex == nil {
+		return nil, nil, fmt.Errorf("error log index is not available")
+	}
+
+	var persistence *indexer.PersistenceManager
+	logFileManager := GetLogFileManager()
+	if logFileManager != nil {
+		persistence = logFileManager.GetPersistence()
+	}
+
+	if persistence != nil {
+		if err := persistence.SetIndexStatus(mainLogPath, string(indexer.IndexStatusIndexing), 0, ""); err != nil {
+			logger.Errorf("Failed to set indexing status for %s: %v", mainLogPath, err)
+		}
+	}
+
+	startTime := time.Now()
+	docsCountMap, minTime, maxTime, err := errorIndex.IndexLogGroup(ctx, mainLogPath)
+	if err != nil {
+		if persistence != nil {
+			if statusErr := persistence.SetIndexStatus(mainLogPath, string(indexer.IndexStatusError), 0, err.Error()); statusErr != nil {
+				logger.Errorf("Failed to set error status for %s: %v", mainLogPath, statusErr)
+			}
+		}
+		return nil, nil, err
+	}
+
+	var totalDocs uint64
+	for _, count := range docsCountMap {
+		totalDocs += count
+	}
+
+	if logFileManager != nil {
+		if err := logFileManager.SaveErrorIndexMetadata(mainLogPath, totalDocs, startTime, time.Since(startTime), minTime, maxTime); err != nil {
+			logger.Errorf("Failed to save index metadata for %s: %v", mainLogPath, err)
+		}
+	}
+	if persistence != nil {
+		if err := persistence.SetIndexStatus(mainLogPath, string(indexer.IndexStatusIndexed), 0, ""); err != nil {
+			logger.Errorf("Failed to set indexed status for %s: %v", mainLogPath, err)
+		}
+	}
+
+	logger.Infof("Indexed error log group %s, Documents: %d", mainLogPath, totalDocs)
+
+	return minTime, maxTime, nil
+}
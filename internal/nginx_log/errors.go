@@ -29,4 +29,5 @@ var (
 	ErrModernSearcherNotAvailable          = e.New(50026, "modern searcher service not available")
 	ErrModernAnalyticsNotAvailable         = e.New(50027, "modern analytics service not available")
 	ErrModernIndexerNotAvailable           = e.New(50028, "modern indexer service not available")
+	ErrErrorLogIndexNotAvailable           = e.New(50029, "error log index not available")
 )
@@ -0,0 +1,379 @@
+package indexer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// ErrorIndexDirName is the directory below the index path that holds the
+// error log index. Error logs are far smaller than access logs, so all groups
+// share one unsharded index.
+const ErrorIndexDirName = "error_logs"
+
+// errorIndexBatchSize is the number of documents written per bleve batch.
+const errorIndexBatchSize = 1000
+
+// ErrorLogDocument represents an error log line in the error index
+type ErrorLogDocument struct {
+	Timestamp      int64  `json:"timestamp"`
+	Level          string `json:"level"`
+	PID            int    `json:"pid"`
+	TID            int    `json:"tid"`
+	ConnectionID   int64  `json:"connection_id"`
+	Message        string `json:"message"`
+	MessagePattern string `json:"message_pattern"`
+	Client         string `json:"client"`
+	Server         string `json:"server"`
+	Request        string `json:"request"`
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	Upstream       string `json:"upstream"`
+	Host           string `json:"host"`
+	Referrer       string `json:"referrer"`
+	Raw            string `json:"raw"`
+	FilePath       string `json:"file_path"`
+	MainLogPath    string `json:"main_log_path"`
+}
+
+// CreateErrorLogIndexMapping creates the index mapping for error log entries
+func CreateErrorLogIndexMapping() mapping.IndexMapping {
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultAnalyzer = "standard"
+	indexMapping.DefaultField = "raw"
+	indexMapping.IndexDynamic = false
+	indexMapping.StoreDynamic = false
+	indexMapping.DocValuesDynamic = false
+
+	docMapping := bleve.NewDocumentStaticMapping()
+
+	addTextField := func(name, analyzer string, docValues bool) {
+		fieldMapping := bleve.NewTextFieldMapping()
+		fieldMapping.Analyzer = analyzer
+		fieldMapping.Store = true
+		fieldMapping.Index = true
+		fieldMapping.IncludeInAll = false
+		fieldMapping.DocValues = docValues
+		docMapping.AddFieldMappingsAt(name, fieldMapping)
+	}
+	addNumericField := func(name string, docValues bool) {
+		fieldMapping := bleve.NewNumericFieldMapping()
+		fieldMapping.Store = true
+		fieldMapping.Index = true
+		fieldMapping.IncludeInAll = false
+		fieldMapping.DocValues = docValues
+		docMapping.AddFieldMappingsAt(name, fieldMapping)
+	}
+
+	// Doc values are kept on the fields used for sorting, facets and clustering.
+	addNumericField("timestamp", true)
+	addTextField("level", "keyword", true)
+	addNumericField("pid", false)
+	addNumericField("tid", false)
+	addNumericField("connection_id", false)
+	addTextField("message", "standard", false)
+	addTextField("message_pattern", "keyword", true)
+	addTextField("client", "keyword", true)
+	addTextField("server", "keyword", true)
+	addTextField("request", "standard", false)
+	addTextField("method", "keyword", false)
+	addTextField("path", "standard", false)
+	addTextField("upstream", "keyword", true)
+	addTextField("host", "keyword", true)
+	addTextField("referrer", "standard", false)
+	addTextField("raw", "standard", false)
+	addTextField("file_path", "keyword", false)
+	addTextField("main_log_path", "keyword", false)
+
+	indexMapping.AddDocumentMapping("_default", docMapping)
+
+	return indexMapping
+}
+
+// ErrorIndex stores parsed error log lines of all error log groups
+type ErrorIndex struct {
+	index bleve.Index
+	// indexMu serializes group (re)indexing so a group is never deleted and
+	// rewritten by two callers at once.
+	indexMu sync.Mutex
+}
+
+// OpenErrorIndex opens the error index below indexPath, creating it if needed
+func OpenErrorIndex(indexPath string) (*ErrorIndex, error) {
+	path := filepath.Join(indexPath, ErrorIndexDirName)
+
+	var idx bleve.Index
+	var err error
+	if _, statErr := os.Stat(filepath.Join(path, "index_meta.json")); os.IsNotExist(statErr) {
+		if err := os.MkdirAll(indexPath, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create index dir: %w", err)
+		}
+		idx, err = bleve.New(path, CreateErrorLogIndexMapping())
+		if err != nil {
+			return nil, fmt.Errorf("create error log index: %w", err)
+		}
+	} else {
+		idx, err = openExistingShard(path, existingShardLockTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("open error log index: %w", err)
+		}
+	}
+
+	return NewErrorIndex(idx), nil
+}
+
+// NewErrorIndex wraps an already opened bleve index
+func NewErrorIndex(idx bleve.Index) *ErrorIndex {
+	return &ErrorIndex{index: idx}
+}
+
+// Index returns the underlying bleve index for searching
+func (ei *ErrorIndex) Index() bleve.Index {
+	return ei.index
+}
+
+// Close closes the underlying bleve index
+func (ei *ErrorIndex) Close() error {
+	return ei.index.Close()
+}
+
+// IndexLogGroup replaces the indexed lines of the error log group of
+// mainLogPath with the current content of its files. It returns the number of
+// documents indexed per file together with the overall time range.
+func (ei *ErrorIndex) IndexLogGroup(ctx context.Context, mainLogPath string) (map[string]uint64, *time.Time, *time.Time, error) {
+	ei.indexMu.Lock()
+	defer ei.indexMu.Unlock()
+
+	files, err := findLogGroupFiles(mainLogPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := ei.deleteByField("main_log_path", mainLogPath); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(files) == 0 {
+		logger.Warnf("No actual error log file found for group: %s", mainLogPath)
+		return nil, nil, nil, nil
+	}
+
+	docsCountMap := make(map[string]uint64, len(files))
+	var overallMinTime, overallMaxTime *time.Time
+
+	for _, filePath := range files {
+		count, minTime, maxTime, err := ei.indexFile(ctx, filePath, mainLogPath)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return docsCountMap, overallMinTime, overallMaxTime, err
+			}
+			logger.Warnf("Failed to index error log '%s' in group '%s', skipping: %v", filePath, mainLogPath, err)
+			continue
+		}
+		docsCountMap[filePath] = count
+
+		if minTime != nil && (overallMinTime == nil || minTime.Before(*overallMinTime)) {
+			overallMinTime = minTime
+		}
+		if maxTime != nil && (overallMaxTime == nil || maxTime.After(*overallMaxTime)) {
+			overallMaxTime = maxTime
+		}
+	}
+
+	return docsCountMap, overallMinTime, overallMaxTime, nil
+}
+
+// indexFile parses and indexes one physical error log file
+func (ei *ErrorIndex) indexFile(ctx context.Context, filePath, mainLogPath string) (uint64, *time.Time, *time.Time, error) {
+	if !utils.IsValidLogPath(filePath) {
+		return 0, nil, nil, fmt.Errorf("invalid log path: %s", filePath)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to open log file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	reader, cleanup, err := createReaderForFile(file, filePath)
+	if err != nil {
+		reader = file
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	var docCount, failed uint64
+	var minTime, maxTime *time.Time
+	batch := ei.index.NewBatch()
+
+	flush := func() error {
+		if batch.Size() == 0 {
+			return nil
+		}
+		if err := ei.index.Batch(batch); err != nil {
+			return fmt.Errorf("failed to flush batch for %s: %w", filePath, err)
+		}
+		batch.Reset()
+		return nil
+	}
+
+	bufReader := bufio.NewReader(reader)
+	var lineNo int64
+	for {
+		line, readErr := bufReader.ReadString('\n')
+		if line != "" {
+			lineNo++
+			entry, err := parser.ParseErrorLine(line)
+			if err != nil {
+				failed++
+			} else {
+				ts := time.Unix(entry.Timestamp, 0)
+				if minTime == nil || ts.Before(*minTime) {
+					minTime = &ts
+				}
+				if maxTime == nil || ts.After(*maxTime) {
+					maxTime = &ts
+				}
+
+				doc := convertToErrorLogDocument(entry, filePath, mainLogPath)
+				if err := batch.Index(filePath+"-"+strconv.FormatInt(lineNo, 10), doc); err != nil {
+					return docCount, minTime, maxTime, fmt.Errorf("failed to add document for %s: %w", filePath, err)
+				}
+				docCount++
+
+				if batch.Size() >= errorIndexBatchSize {
+					if err := ctx.Err(); err != nil {
+						return docCount, minTime, maxTime, err
+					}
+					if err := flush(); err != nil {
+						return docCount, minTime, maxTime, err
+					}
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return docCount, minTime, maxTime, fmt.Errorf("failed to read %s: %w", filePath, readErr)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return docCount, minTime, maxTime, err
+	}
+
+	if failed > 0 {
+		logger.Debugf("Skipped %d unparsable lines in error log %s", failed, filePath)
+	}
+	logger.Infof("Indexed %d error log entries from %s", docCount, filePath)
+
+	return docCount, minTime, maxTime, nil
+}
+
+// convertToErrorLogDocument converts a parsed error log line to its document
+func convertToErrorLogDocument(entry *parser.ErrorLogEntry, filePath, mainLogPath string) *ErrorLogDocument {
+	return &ErrorLogDocument{
+		Timestamp:      entry.Timestamp,
+		Level:          entry.Level,
+		PID:            entry.PID,
+		TID:            entry.TID,
+		ConnectionID:   entry.ConnectionID,
+		Message:        entry.Message,
+		MessagePattern: parser.MessagePattern(entry.Message),
+		Client:         entry.Client,
+		Server:         entry.Server,
+		Request:        entry.Request,
+		Method:         entry.Method,
+		Path:           entry.Path,
+		Upstream:       entry.Upstream,
+		Host:           entry.Host,
+		Referrer:       entry.Referrer,
+		Raw:            entry.Raw,
+		FilePath:       filePath,
+		MainLogPath:    mainLogPath,
+	}
+}
+
+// DeleteLogGroup removes all indexed lines of the error log group of mainLogPath
+func (ei *ErrorIndex) DeleteLogGroup(mainLogPath string) error {
+	ei.indexMu.Lock()
+	defer ei.indexMu.Unlock()
+
+	return ei.deleteByField("main_log_path", mainLogPath)
+}
+
+// DeleteAll removes every indexed error log line
+func (ei *ErrorIndex) DeleteAll() error {
+	ei.indexMu.Lock()
+	defer ei.indexMu.Unlock()
+
+	return ei.deleteMatching(bleve.NewMatchAllQuery(), "all groups")
+}
+
+// deleteByField deletes every document whose keyword field equals value
+func (ei *ErrorIndex) deleteByField(field, value string) error {
+	q := bleve.NewTermQuery(value)
+	q.SetField(field)
+	return ei.deleteMatching(q, value)
+}
+
+// deleteMatching deletes every document matching q in batches
+func (ei *ErrorIndex) deleteMatching(q query.Query, label string) error {
+	searchRequest := bleve.NewSearchRequest(q)
+	searchRequest.Size = errorIndexBatchSize
+
+	for {
+		searchResult, err := ei.index.Search(searchRequest)
+		if err != nil {
+			return fmt.Errorf("failed to search error log documents of %s: %w", label, err)
+		}
+		if len(searchResult.Hits) == 0 {
+			return nil
+		}
+
+		batch := ei.index.NewBatch()
+		for _, hit := range searchResult.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err := ei.index.Batch(batch); err != nil {
+			return fmt.Errorf("failed to delete error log documents of %s: %w", label, err)
+		}
+
+		// Deleted documents no longer match, so keep searching from the start.
+		if len(searchResult.Hits) < searchRequest.Size {
+			return nil
+		}
+	}
+}
+
+// CountDocsByMainLogPath returns the number of indexed lines of a log group
+func (ei *ErrorIndex) CountDocsByMainLogPath(mainLogPath string) (uint64, error) {
+	query := bleve.NewTermQuery(mainLogPath)
+	query.SetField("main_log_path")
+
+	searchRequest := bleve.NewSearchRequest(query)
+	searchRequest.Size = 0
+
+	searchResult, err := ei.index.Search(searchRequest)
+	if err != nil {
+		return 0, err
+	}
+	return searchResult.Total, nil
+}
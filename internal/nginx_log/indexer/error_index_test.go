@@ -0,0 +1,69 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIndexIndexLogGroup(t *testing.T) {
+	logDir := t.TempDir()
+	previousWhiteList := settings.NginxSettings.LogDirWhiteList
+	settings.NginxSettings.LogDirWhiteList = []string{logDir}
+	t.Cleanup(func() { settings.NginxSettings.LogDirWhiteList = previousWhiteList })
+
+	mainLogPath := filepath.Join(logDir, "error.log")
+	require.NoError(t, os.WriteFile(mainLogPath, []byte(
+		`2024/01/15 10:30:45 [error] 12#12: *1 connect() failed (111: Connection refused) while connecting to upstream, client: 192.0.2.1, server: example.com, request: "GET / HTTP/1.1", upstream: "http://10.0.0.1:8080/", host: "example.com"`+"\n"+
+			`2024/01/15 10:31:00 [warn] 12#12: *2 an upstream response is buffered to a temporary file, client: 192.0.2.2, server: example.com`+"\n"+
+			"not an error log line\n"), 0o644))
+	require.NoError(t, os.WriteFile(mainLogPath+".1", []byte(
+		`2024/01/14 08:00:00 [error] 12#12: *7 connect() failed (111: Connection refused) while connecting to upstream, client: 192.0.2.9, server: example.com, upstream: "http://10.0.0.2:8080/"`+"\n"), 0o644))
+
+	errorIndex, err := OpenErrorIndex(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = errorIndex.Close() })
+
+	docsCountMap, minTime, maxTime, err := errorIndex.IndexLogGroup(context.Background(), mainLogPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{mainLogPath: 2, mainLogPath + ".1": 1}, docsCountMap)
+	require.NotNil(t, minTime)
+	require.NotNil(t, maxTime)
+	assert.Equal(t, "2024/01/14 08:00:00", minTime.Format("2006/01/02 15:04:05"))
+	assert.Equal(t, "2024/01/15 10:31:00", maxTime.Format("2006/01/02 15:04:05"))
+
+	count, err := errorIndex.CountDocsByMainLogPath(mainLogPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	// Lines differing only in addresses share a message pattern.
+	query := bleve.NewTermQuery("connect() failed (<n>: Connection refused) while connecting to upstream")
+	query.SetField("message_pattern")
+	result, err := errorIndex.Index().Search(bleve.NewSearchRequest(query))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), result.Total)
+
+	levelQuery := bleve.NewTermQuery("warn")
+	levelQuery.SetField("level")
+	result, err = errorIndex.Index().Search(bleve.NewSearchRequest(levelQuery))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), result.Total)
+
+	// Re-indexing replaces the group instead of duplicating it.
+	_, _, _, err = errorIndex.IndexLogGroup(context.Background(), mainLogPath)
+	require.NoError(t, err)
+	count, err = errorIndex.CountDocsByMainLogPath(mainLogPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	require.NoError(t, errorIndex.DeleteLogGroup(mainLogPath))
+	count, err = errorIndex.CountDocsByMainLogPath(mainLogPath)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -14,14 +14,22 @@ func TestCreateLogIndexMappingUsesOnlyRequiredIndexFeatures(t *testing.T) {
 	require.True(t, ok)
 
 	assert.Equal(t, "raw", indexMapping.DefaultField)
-	assert.False(t, indexMapping.IndexDynamic)
-	assert.False(t, indexMapping.StoreDynamic)
-	assert.False(t, indexMapping.DocValuesDynamic)
+	// Dynamic indexing only applies to the vars sub-document; every other
+	// field stays explicitly mapped.
+	assert.True(t, indexMapping.IndexDynamic)
+	assert.True(t, indexMapping.StoreDynamic)
+	assert.True(t, indexMapping.DocValuesDynamic)
+	assert.Equal(t, VarsDateTimeParser, indexMapping.DefaultDateTimeParser)
 
 	documentMapping := indexMapping.TypeMapping["_default"]
 	require.NotNil(t, documentMapping)
 	assert.False(t, documentMapping.Dynamic)
 
+	varsMapping := documentMapping.Properties[VarsFieldPrefix]
+	require.NotNil(t, varsMapping)
+	assert.True(t, varsMapping.Dynamic)
+	assert.Equal(t, "keyword", varsMapping.DefaultAnalyzer)
+
 	tests := []struct {
 		name               string
 		store              bool
@@ -145,6 +153,64 @@ func TestCreateLogIndexMappingPreservesSearchBehavior(t *testing.T) {
 	})
 }
 
+func TestCreateLogIndexMappingIndexesLogFormatVariables(t *testing.T) {
+	index, err := bleve.NewMemOnly(CreateLogIndexMapping())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, index.Close()) })
+
+	worker := &indexWorker{}
+	documents := map[string]*LogDocument{
+		"hit": {
+			Timestamp: 100, IP: "192.0.2.1", Method: "GET", Path: "/", Status: 200, Raw: "hit",
+			Vars: map[string]string{"upstream_cache_status": "HIT", "deploy_date": "2024-01-01", "host": "a.example.com"},
+		},
+		"miss": {
+			Timestamp: 200, IP: "192.0.2.2", Method: "GET", Path: "/", Status: 200, Raw: "miss",
+			Vars: map[string]string{"upstream_cache_status": "MISS", "host": "a.example.com"},
+		},
+		"plain": {Timestamp: 300, IP: "192.0.2.3", Method: "GET", Path: "/", Status: 200, Raw: "plain"},
+		"unmapped": {Timestamp: 400, IP: "192.0.2.4", Method: "GET", Path: "/", Status: 200, Raw: "unmapped"},
+	}
+	for id, document := range documents {
+		docMap := worker.logDocumentToMap(document)
+		if id == "unmapped" {
+			// Unknown top-level fields must not become searchable.
+			docMap["upstream_cache_status"] = "HIT"
+		}
+		require.NoError(t, index.Index(id, docMap))
+	}
+
+	search := func(field, value string) []string {
+		query := bleve.NewTermQuery(value)
+		query.SetField(field)
+		result, err := index.Search(bleve.NewSearchRequest(query))
+		require.NoError(t, err)
+		ids := make([]string, 0, len(result.Hits))
+		for _, hit := range result.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"hit"}, search("vars.upstream_cache_status", "HIT"))
+	assert.Equal(t, []string{"hit"}, search("vars.deploy_date", "2024-01-01"))
+	assert.Empty(t, search("upstream_cache_status", "HIT"))
+
+	t.Run("stored and facetable", func(t *testing.T) {
+		request := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{"hit", "miss"}))
+		request.Fields = []string{"*"}
+		request.AddFacet("cache", bleve.NewFacetRequest("vars.upstream_cache_status", 10))
+		result, err := index.Search(request)
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 2)
+		for _, hit := range result.Hits {
+			assert.Equal(t, "a.example.com", hit.Fields["vars.host"])
+		}
+		require.NotNil(t, result.Facets["cache"])
+		assert.Len(t, result.Facets["cache"].Terms.Terms(), 2)
+	})
+}
+
 func requireFieldMapping(t *testing.T, documentMapping *mapping.DocumentMapping, name string) *mapping.FieldMapping {
 	t.Helper()
 
@@ -46,6 +46,17 @@ type NginxLogWithIndex struct {
 	ErrorTime     int64  `json:"error_time,omitempty"`     // Unix timestamp when error occurred
 	RetryCount    int    `json:"retry_count,omitempty"`    // Number of retry attempts
 	QueuePosition int    `json:"queue_position,omitempty"` // Position in indexing queue
+	// Retention fields
+	RetentionDays int   `json:"retention_days"`          // Days raw documents are kept, 0 keeps them forever
+	PurgedBefore  int64 `json:"purged_before,omitempty"` // Unix timestamp before which raw documents were purged
+}
+
+// setRetention copies the retention policy of a log group from an index record
+func setRetention(log *NginxLogWithIndex, idx *model.NginxLogIndex) {
+	log.RetentionDays = idx.RetentionDays
+	if idx.PurgedBefore != nil {
+		log.PurgedBefore = idx.PurgedBefore.Unix()
+	}
 }
 
 // LogFileManager manages nginx log file discovery and index status
@@ -216,6 +227,7 @@ func (lm *LogFileManager) GetAllLogsWithIndexGrouped(filters ...func(*NginxLogWi
 			logWithIndex.IndexDuration = *idx.IndexDuration
 		}
 		logWithIndex.DocumentCount = idx.DocumentCount
+		setRetention(logWithIndex, idx)
 
 		// Set queue position if available
 		logWithIndex.QueuePosition = idx.QueuePosition
@@ -311,6 +323,8 @@ func (lm *LogFileManager) GetAllLogsWithIndexGrouped(filters ...func(*NginxLogWi
 
 				existing.DocumentCount += log.DocumentCount
 				existing.LastSize += log.LastSize
+				existing.RetentionDays = max(existing.RetentionDays, log.RetentionDays)
+				existing.PurgedBefore = max(existing.PurgedBefore, log.PurgedBefore)
 
 				// Update status with priority: indexing > queued > indexed > error > not_indexed
 				if log.IndexStatus == string(IndexStatusIndexing) {
@@ -401,6 +415,17 @@ func (lm *LogFileManager) GetAllLogsWithIndexGrouped(filters ...func(*NginxLogWi
 // SaveIndexMetadata saves the metadata for a log group after an indexing operation.
 // It creates a new record for the base log path.
 func (lm *LogFileManager) SaveIndexMetadata(basePath string, documentCount uint64, startTime time.Time, duration time.Duration, minTime *time.Time, maxTime *time.Time) error {
+	return lm.saveIndexMetadata(basePath, documentCount, startTime, duration, minTime, maxTime, true)
+}
+
+// SaveErrorIndexMetadata saves the metadata of an error log group. Error logs
+// live in the error index, so the provided document count is kept instead of
+// being recounted from the access log shards.
+func (lm *LogFileManager) SaveErrorIndexMetadata(basePath string, documentCount uint64, startTime time.Time, duration time.Duration, minTime *time.Time, maxTime *time.Time) error {
+	return lm.saveIndexMetadata(basePath, documentCount, startTime, duration, minTime, maxTime, false)
+}
+
+func (lm *LogFileManager) saveIndexMetadata(basePath string, documentCount uint64, startTime time.Time, duration time.Duration, minTime *time.Time, maxTime *time.Time, recount bool) error {
 	// We want to save the metadata against the base path (the "log group").
 	// We get or create a record for this specific path.
 	logIndex, err := lm.persistence.GetLogIndex(basePath)
@@ -420,7 +445,7 @@ func (lm *LogFileManager) SaveIndexMetadata(basePath string, documentCount uint6
 	}
 
 	// If indexer is available and healthy, query Bleve for exact document count
-	if lm.indexer != nil && lm.indexer.IsHealthy() {
+	if recount && lm.indexer != nil && lm.indexer.IsHealthy() {
 		// Decide whether this path is a main log path (group) or a specific file
 		mainPath := getMainLogPathFromFile(basePath)
 		if mainPath == basePath {
@@ -551,6 +576,7 @@ func (lm *LogFileManager) GetAllLogsWithIndex(filters ...func(*NginxLogWithIndex
 				logWithIndex.IndexDuration = *idx.IndexDuration
 			}
 			logWithIndex.DocumentCount = idx.DocumentCount
+			setRetention(logWithIndex, idx)
 
 			// Determine status
 			lm.indexingMutex.RLock()
@@ -450,6 +450,70 @@ func (pi *ParallelIndexer) DeleteIndexByLogGroup(basePath string, logFileManager
 	return nil
 }
 
+// DeleteDocumentsBefore deletes the documents of a log group whose timestamp
+// is earlier than before (Unix seconds) and returns how many were deleted.
+// Retention uses it to purge raw documents once they are rolled up.
+func (pi *ParallelIndexer) DeleteDocumentsBefore(mainLogPath string, before int64) (uint64, error) {
+	if !pi.IsHealthy() {
+		return 0, fmt.Errorf("indexer not healthy")
+	}
+
+	groupQuery := bleve.NewTermQuery(mainLogPath)
+	groupQuery.SetField("main_log_path")
+	end := float64(before)
+	inclusive := false
+	timeQuery := bleve.NewNumericRangeInclusiveQuery(nil, &end, nil, &inclusive)
+	timeQuery.SetField("timestamp")
+	q := bleve.NewConjunctionQuery(groupQuery, timeQuery)
+
+	var deleted uint64
+	var deleteErrors []error
+	for _, shard := range pi.shardManager.GetAllShards() {
+		if shard == nil {
+			continue
+		}
+
+		searchRequest := bleve.NewSearchRequest(q)
+		searchRequest.Size = 1000 // Process in batches
+
+		for {
+			searchResult, err := shard.Search(searchRequest)
+			if err != nil {
+				deleteErrors = append(deleteErrors, fmt.Errorf("failed to search for documents before %d: %w", before, err))
+				break
+			}
+
+			if len(searchResult.Hits) == 0 {
+				break
+			}
+
+			batch := shard.NewBatch()
+			for _, hit := range searchResult.Hits {
+				batch.Delete(hit.ID)
+			}
+
+			if err := shard.Batch(batch); err != nil {
+				deleteErrors = append(deleteErrors, fmt.Errorf("failed to delete batch of documents before %d: %w", before, err))
+				break
+			}
+			deleted += uint64(len(searchResult.Hits))
+
+			if len(searchResult.Hits) < searchRequest.Size {
+				break
+			}
+
+			// Deleted documents no longer match the query, so keep searching
+			// from the beginning.
+		}
+	}
+
+	if len(deleteErrors) > 0 {
+		return deleted, fmt.Errorf("encountered %d errors during deletion: %v", len(deleteErrors), deleteErrors[0])
+	}
+
+	return deleted, nil
+}
+
 // DestroyAllIndexes closes and deletes all index data from disk.
 func (pi *ParallelIndexer) DestroyAllIndexes(parentCtx context.Context) error {
 	// Stop all background routines before deleting files
@@ -661,6 +725,9 @@ func (w *indexWorker) logDocumentToMap(doc *LogDocument) map[string]interface{}
 	if doc.UpstreamTime != nil {
 		docMap["upstream_time"] = *doc.UpstreamTime
 	}
+	if len(doc.Vars) > 0 {
+		docMap[VarsFieldPrefix] = doc.Vars
+	}
 
 	return docMap
 }
@@ -746,41 +813,12 @@ func (pi *ParallelIndexer) IndexLogGroupWithProgress(basePath string, progressCo
 		progressTracker = NewProgressTracker(basePath, progressConfig)
 	}
 
-	// Find all files belonging to this log group by globbing
-	globPath := basePath + "*"
-	matches, err := filepath.Glob(globPath)
+	uniqueFiles, err := findLogGroupFiles(basePath)
 	if err != nil {
 		if progressTracker != nil {
 			progressTracker.Cancel(fmt.Sprintf("glob failed: %v", err))
 		}
-		return nil, nil, nil, fmt.Errorf("failed to glob for log files with base %s: %w", basePath, err)
-	}
-
-	// filepath.Glob might not match the base file itself if it has no extension,
-	// so we check for it explicitly and add it to the list.
-	// Validate log path before accessing it
-	if utils.IsValidLogPath(basePath) {
-		info, err := os.Stat(basePath)
-		if err == nil && info.Mode().IsRegular() {
-			matches = append(matches, basePath)
-		}
-	}
-
-	// Deduplicate file list
-	seen := make(map[string]struct{})
-	uniqueFiles := make([]string, 0)
-	for _, match := range matches {
-		if _, ok := seen[match]; !ok {
-			// Further check if it's a file, not a directory. Glob can match dirs.
-			// Validate log path before accessing it
-			if utils.IsValidLogPath(match) {
-				info, err := os.Stat(match)
-				if err == nil && info.Mode().IsRegular() {
-					seen[match] = struct{}{}
-					uniqueFiles = append(uniqueFiles, match)
-				}
-			}
-		}
+		return nil, nil, nil, err
 	}
 
 	if len(uniqueFiles) == 0 {
@@ -881,6 +919,46 @@ func (pi *ParallelIndexer) IndexLogGroupWithProgress(basePath string, progressCo
 	return docsCountMap, overallMinTime, overallMaxTime, nil
 }
 
+// findLogGroupFiles returns the regular files belonging to the log group of
+// basePath, i.e. the base file itself and its rotated siblings.
+func findLogGroupFiles(basePath string) ([]string, error) {
+	// Find all files belonging to this log group by globbing
+	globPath := basePath + "*"
+	matches, err := filepath.Glob(globPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob for log files with base %s: %w", basePath, err)
+	}
+
+	// filepath.Glob might not match the base file itself if it has no extension,
+	// so we check for it explicitly and add it to the list.
+	// Validate log path before accessing it
+	if utils.IsValidLogPath(basePath) {
+		info, err := os.Stat(basePath)
+		if err == nil && info.Mode().IsRegular() {
+			matches = append(matches, basePath)
+		}
+	}
+
+	// Deduplicate file list
+	seen := make(map[string]struct{})
+	uniqueFiles := make([]string, 0)
+	for _, match := range matches {
+		if _, ok := seen[match]; !ok {
+			// Further check if it's a file, not a directory. Glob can match dirs.
+			// Validate log path before accessing it
+			if utils.IsValidLogPath(match) {
+				info, err := os.Stat(match)
+				if err == nil && info.Mode().IsRegular() {
+					seen[match] = struct{}{}
+					uniqueFiles = append(uniqueFiles, match)
+				}
+			}
+		}
+	}
+
+	return uniqueFiles, nil
+}
+
 // indexSingleFileWithProgress indexes a single file with progress updates
 // Now uses the optimized implementation with full progress tracking integration
 func (pi *ParallelIndexer) indexSingleFileWithProgress(filePath string, progressTracker *ProgressTracker) (uint64, *time.Time, *time.Time, error) {
@@ -20,6 +20,14 @@ var (
 	parserInitOnce sync.Once
 )
 
+// Parsers for custom log_format definitions, keyed by LogFormat.Key and
+// derived from logParser so they share its enrichment services.
+var (
+	logFormatResolver func(logPath string) *parser.LogFormat
+	formatParsers     = make(map[string]*parser.Parser)
+	formatParsersMu   sync.Mutex
+)
+
 // geoIPOverride replaces the GeoLite-backed geo lookup when set.
 //
 // The slot defaults to nil, and only internal/demo ever fills it, so a
@@ -83,6 +91,45 @@ func InitLogParser() {
 	})
 }
 
+// SetLogFormatResolver installs the lookup from a main log path to the
+// log_format its access_log directive writes. Logs for which the resolver
+// returns nil are parsed as combined format.
+func SetLogFormatResolver(resolver func(logPath string) *parser.LogFormat) {
+	formatParsersMu.Lock()
+	defer formatParsersMu.Unlock()
+
+	logFormatResolver = resolver
+}
+
+// parserForLog returns the parser matching the log_format of a log group,
+// creating one per distinct format on first use.
+func parserForLog(mainLogPath string) *parser.Parser {
+	formatParsersMu.Lock()
+	resolver := logFormatResolver
+	formatParsersMu.Unlock()
+
+	if resolver == nil {
+		return logParser
+	}
+	format := resolver(mainLogPath)
+	if format == nil || format.IsCombined() {
+		return logParser
+	}
+
+	formatParsersMu.Lock()
+	defer formatParsersMu.Unlock()
+
+	key := format.Key()
+	if formatParser, ok := formatParsers[key]; ok {
+		return formatParser
+	}
+	formatParser := logParser.WithFormat(format)
+	formatParsers[key] = formatParser
+	logger.Debugf("Created parser for log_format %q", format.Name)
+
+	return formatParser
+}
+
 // IsLogParserInitialized returns true if the global parser singleton has been created.
 func IsLogParserInitialized() bool {
 	return logParser != nil
@@ -130,7 +177,7 @@ func ParseLogStreamBatches(ctx context.Context, reader io.Reader, filePath strin
 	// The main log path is constant for the whole file; compute it once
 	mainLogPath := getMainLogPathFromFile(filePath)
 
-	parseResult, err := logParser.StreamParseBatches(ctx, actualReader, func(entries []*parser.AccessLogEntry) error {
+	parseResult, err := parserForLog(mainLogPath).StreamParseBatches(ctx, actualReader, func(entries []*parser.AccessLogEntry) error {
 		docs := make([]*LogDocument, 0, len(entries))
 		for _, entry := range entries {
 			docs = append(docs, convertToLogDocument(entry, filePath, mainLogPath))
@@ -195,6 +242,7 @@ func convertToLogDocument(entry *parser.AccessLogEntry, filePath, mainLogPath st
 		Raw:         entry.Raw,
 		FilePath:    filePath,
 		MainLogPath: mainLogPath,
+		Vars:        entry.Extra,
 	}
 
 	if entry.UpstreamTime != nil {
@@ -0,0 +1,55 @@
+package indexer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogStreamBatchesUsesLogFormat(t *testing.T) {
+	format, err := parser.CompileLogFormat("cache", "",
+		`$remote_addr [$time_local] "$request" $status $body_bytes_sent cache=$upstream_cache_status`)
+	require.NoError(t, err)
+
+	const customLog = "/var/log/nginx/cache.access.log"
+	SetLogFormatResolver(func(logPath string) *parser.LogFormat {
+		if logPath == customLog {
+			return format
+		}
+		return nil
+	})
+	t.Cleanup(func() { SetLogFormatResolver(nil) })
+
+	parse := func(filePath, content string) []*LogDocument {
+		var docs []*LogDocument
+		_, _, err := ParseLogStreamBatches(context.Background(), strings.NewReader(content), filePath, func(batch []*LogDocument) error {
+			docs = append(docs, batch...)
+			return nil
+		})
+		require.NoError(t, err)
+		return docs
+	}
+
+	// Rotated files share the main log path and therefore the format.
+	docs := parse(customLog+".1",
+		`192.0.2.1 [25/Dec/2023:10:00:00 +0000] "GET /a HTTP/1.1" 200 10 cache=HIT`+"\n")
+	require.Len(t, docs, 1)
+	assert.Equal(t, "192.0.2.1", docs[0].IP)
+	assert.Equal(t, "/a", docs[0].Path)
+	assert.Equal(t, int64(1703498400), docs[0].Timestamp)
+	assert.Equal(t, map[string]string{"upstream_cache_status": "HIT"}, docs[0].Vars)
+
+	docs = parse("/var/log/nginx/access.log",
+		`192.0.2.2 - - [25/Dec/2023:10:00:00 +0000] "GET /b HTTP/1.1" 404 0 "-" "curl/8.0"`+"\n")
+	require.Len(t, docs, 1)
+	assert.Equal(t, "/b", docs[0].Path)
+	assert.Equal(t, 404, docs[0].Status)
+	assert.Nil(t, docs[0].Vars)
+
+	assert.Same(t, parserForLog(customLog), parserForLog(customLog))
+	assert.Same(t, logParser, parserForLog("/var/log/nginx/access.log"))
+}
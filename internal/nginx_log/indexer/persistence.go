@@ -9,6 +9,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
 	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gen"
 	"gorm.io/gen/field"
 	"gorm.io/gorm"
 )
@@ -75,9 +76,10 @@ func (pm *PersistenceManager) GetLogIndex(path string) (*model.NginxLogIndex, er
 	// Use FirstOrCreate to get existing record or create a new one
 	logIndex, err := q.Where(q.Path.Eq(path)).
 		Assign(field.Attrs(&model.NginxLogIndex{
-			Path:        path,
-			MainLogPath: mainLogPath,
-			Enabled:     true,
+			Path:          path,
+			MainLogPath:   mainLogPath,
+			Enabled:       true,
+			RetentionDays: groupRetention(mainLogPath),
 		})).
 		FirstOrCreate()
 
@@ -88,6 +90,46 @@ func (pm *PersistenceManager) GetLogIndex(path string) (*model.NginxLogIndex, er
 	return logIndex, nil
 }
 
+// keptRetention remembers the retention of log groups whose index records were
+// deleted, so the records a rebuild creates again keep it
+var (
+	keptRetentionMu sync.Mutex
+	keptRetention   = make(map[string]int)
+)
+
+// groupRetention returns the retention days of a log group, so a record added
+// for a newly rotated file follows the retention of its group
+func groupRetention(mainLogPath string) int {
+	q := query.NginxLogIndex
+	indexes, err := q.Where(q.MainLogPath.Eq(mainLogPath)).Order(q.RetentionDays.Desc()).Limit(1).Find()
+	if err == nil && len(indexes) > 0 {
+		return indexes[0].RetentionDays
+	}
+
+	keptRetentionMu.Lock()
+	defer keptRetentionMu.Unlock()
+	return keptRetention[mainLogPath]
+}
+
+// keepRetention remembers the retention of the log groups matched by conds
+// before their records are deleted
+func keepRetention(conds ...gen.Condition) {
+	q := query.NginxLogIndex
+	indexes, err := q.Select(q.MainLogPath, q.RetentionDays).Where(conds...).Find()
+	if err != nil {
+		logger.Warnf("Failed to read log retention before deleting index records: %v", err)
+		return
+	}
+
+	keptRetentionMu.Lock()
+	defer keptRetentionMu.Unlock()
+	for _, index := range indexes {
+		if index.RetentionDays > 0 {
+			keptRetention[index.MainLogPath] = max(keptRetention[index.MainLogPath], index.RetentionDays)
+		}
+	}
+}
+
 // SaveLogIndex saves or updates the index record with incremental indexing support
 func (pm *PersistenceManager) SaveLogIndex(logIndex *model.NginxLogIndex) error {
 	logIndex.Enabled = true
@@ -537,6 +579,7 @@ func (pm *PersistenceManager) DeleteAllLogIndexes() error {
 	if err != nil {
 		return err
 	}
+	keepRetention()
 	if err := db.Exec("DELETE FROM nginx_log_indices").Error; err != nil {
 		return fmt.Errorf("failed to delete all log indexes: %w", err)
 	}
@@ -574,6 +617,7 @@ func (pm *PersistenceManager) GetLogIndexesByGroup(mainLogPath string) ([]*model
 
 func (pm *PersistenceManager) DeleteLogIndexesByGroup(mainLogPath string) error {
 	q := query.NginxLogIndex
+	keepRetention(q.MainLogPath.Eq(mainLogPath))
 	result, err := q.Unscoped().Where(q.MainLogPath.Eq(mainLogPath)).Delete()
 	if err != nil {
 		return fmt.Errorf("failed to delete log indexes for group %s: %w", mainLogPath, err)
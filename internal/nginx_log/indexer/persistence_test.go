@@ -37,6 +37,39 @@ func TestDeleteAllLogIndexesUsesRegisteredQueryDatabase(t *testing.T) {
 	require.Zero(t, count)
 }
 
+func TestLogIndexRetentionSurvivesRebuild(t *testing.T) {
+	originalModelDB := model.UseDB()
+	originalQueryDB := query.Q.UnderlyingDB()
+	database, err := gorm.Open(sqlite.Open("file:retention-indexes?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(&model.NginxLogIndex{}))
+	model.Use(database)
+	query.SetDefault(database)
+	t.Cleanup(func() {
+		if originalModelDB != nil {
+			model.Use(originalModelDB)
+		}
+		if originalQueryDB != nil {
+			query.SetDefault(originalQueryDB)
+		}
+	})
+
+	pm := NewPersistenceManager(nil)
+	mainLogPath := "/var/log/nginx/retention.log"
+	require.NoError(t, database.Create(&model.NginxLogIndex{Path: mainLogPath, MainLogPath: mainLogPath, RetentionDays: 30}).Error)
+
+	// A newly rotated file follows the retention of its group
+	rotated, err := pm.GetLogIndex(mainLogPath + ".1")
+	require.NoError(t, err)
+	require.Equal(t, 30, rotated.RetentionDays)
+
+	// A full rebuild deletes every record, the recreated ones keep the retention
+	require.NoError(t, pm.DeleteAllLogIndexes())
+	recreated, err := pm.GetLogIndex(mainLogPath)
+	require.NoError(t, err)
+	require.Equal(t, 30, recreated.RetentionDays)
+}
+
 func TestPersistenceManager_Creation(t *testing.T) {
 	// Test default config
 	pm := NewPersistenceManager(nil)
@@ -11,7 +11,7 @@ import (
 
 const (
 	indexStorageVersionFile = ".nginx-ui-index-version"
-	indexStorageVersion     = "3"
+	indexStorageVersion     = "4"
 )
 
 // PrepareIndexStorage removes rebuildable shard data when the on-disk format
@@ -61,7 +61,7 @@ func isManagedIndexDirectory(entry os.DirEntry) bool {
 	if !entry.IsDir() {
 		return false
 	}
-	if strings.HasPrefix(entry.Name(), "shard_") {
+	if strings.HasPrefix(entry.Name(), "shard_") || entry.Name() == ErrorIndexDirName {
 		return true
 	}
 	_, err := uuid.Parse(entry.Name())
@@ -11,6 +11,7 @@ func TestPrepareIndexStorageRemovesOnlyManagedIndexes(t *testing.T) {
 	managedDirectories := []string{
 		"4a589aa8-79b9-4c45-b98f-2807af3b13f8",
 		"shard_0",
+		ErrorIndexDirName,
 	}
 	for _, name := range managedDirectories {
 		path := filepath.Join(indexPath, name)
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/datetime/flexible"
 	"github.com/blevesearch/bleve/v2/mapping"
 )
 
@@ -185,6 +187,12 @@ type Document struct {
 	Fields *LogDocument `json:"fields"`
 }
 
+// Dynamic fields for custom log_format variables
+const (
+	VarsFieldPrefix    = "vars"      // sub-document holding the variables
+	VarsDateTimeParser = "vars_text" // datetime parser that never matches
+)
+
 // LogDocument represents the structured data for a log entry
 type LogDocument struct {
 	Timestamp    int64    `json:"timestamp"`
@@ -210,6 +218,10 @@ type LogDocument struct {
 	FilePath     string   `json:"file_path"`     // Actual physical file path (e.g., /var/log/nginx/access.log.1.gz)
 	MainLogPath  string   `json:"main_log_path"` // Main log group path (e.g., /var/log/nginx/access.log)
 	Raw          string   `json:"raw"`
+
+	// Vars holds custom log_format variables without a dedicated field. They
+	// are indexed as dynamic keyword fields named "vars.<variable>".
+	Vars map[string]string `json:"vars,omitempty"`
 }
 
 // IndexJob represents a single indexing job
@@ -418,6 +430,26 @@ func CreateLogIndexMapping() mapping.IndexMapping {
 	addTextField("file_path", "keyword", storedAndIndexed)
 	addTextField("main_log_path", "keyword", storedAndIndexed)
 
+	// Custom log_format variables differ per log, so they live in a dynamic
+	// sub-document: every "vars.<variable>" field is indexed as a stored,
+	// facetable keyword. The top-level mapping stays static, and the
+	// layout-less datetime parser keeps values such as "2024-01-01" from being
+	// turned into datetime fields that term filters cannot match.
+	indexMapping.IndexDynamic = true
+	indexMapping.StoreDynamic = true
+	indexMapping.DocValuesDynamic = true
+	if err := indexMapping.AddCustomDateTimeParser(VarsDateTimeParser, map[string]interface{}{
+		"type":    flexible.Name,
+		"layouts": []interface{}{},
+	}); err == nil {
+		indexMapping.DefaultDateTimeParser = VarsDateTimeParser
+	}
+
+	varsMapping := bleve.NewDocumentMapping()
+	varsMapping.Dynamic = true
+	varsMapping.DefaultAnalyzer = keyword.Name
+	docMapping.AddSubDocumentMapping(VarsFieldPrefix, varsMapping)
+
 	indexMapping.AddDocumentMapping("_default", docMapping)
 
 	return indexMapping
@@ -0,0 +1,117 @@
+package nginx_log
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// logFormatRef records which log_format an access_log directive writes with
+type logFormatRef struct {
+	name       string
+	configFile string
+}
+
+var (
+	// logFormatDefinitions holds the log_format directives of each config file
+	logFormatDefinitions = make(map[string][]utils.LogFormatDefinition)
+	// logPathFormats maps an access log path to the format it is written with
+	logPathFormats = make(map[string]logFormatRef)
+	// compiledLogFormats caches compiled definitions by name, escape and
+	// pattern; a nil value marks a definition that failed to compile
+	compiledLogFormats = make(map[string]*parser.LogFormat)
+	logFormatMutex     sync.RWMutex
+)
+
+// updateLogFormats replaces the log_format definitions and access_log format
+// references that originated from configPath.
+func updateLogFormats(configPath string, content []byte, directives []utils.LogDirective) {
+	definitions := utils.ScanLogFormats(content)
+
+	logFormatMutex.Lock()
+	defer logFormatMutex.Unlock()
+
+	if len(definitions) > 0 {
+		logFormatDefinitions[configPath] = definitions
+	} else {
+		delete(logFormatDefinitions, configPath)
+	}
+
+	for path, ref := range logPathFormats {
+		if ref.configFile == configPath {
+			delete(logPathFormats, path)
+		}
+	}
+	for _, directive := range directives {
+		if directive.Type == "access" && directive.Format != "" {
+			logPathFormats[directive.Path] = logFormatRef{name: directive.Format, configFile: configPath}
+		}
+	}
+}
+
+// ResolveLogFormat returns the compiled log_format the access log at logPath
+// is written with, or nil when it uses the predefined combined format or its
+// format is unknown or cannot be parsed.
+func ResolveLogFormat(logPath string) *parser.LogFormat {
+	logFormatMutex.RLock()
+	ref, ok := logPathFormats[logPath]
+	if !ok || ref.name == utils.DefaultLogFormat {
+		logFormatMutex.RUnlock()
+		return nil
+	}
+	definition, found := findLogFormatDefinition(ref)
+	logFormatMutex.RUnlock()
+
+	if !found {
+		logger.Warnf("log_format %q used by %s is not defined, parsing it as combined", ref.name, logPath)
+		return nil
+	}
+
+	key := definition.Name + "\x00" + definition.Escape + "\x00" + definition.Pattern
+
+	logFormatMutex.Lock()
+	defer logFormatMutex.Unlock()
+
+	if format, ok := compiledLogFormats[key]; ok {
+		return format
+	}
+
+	format, err := parser.CompileLogFormat(definition.Name, definition.Escape, definition.Pattern)
+	if err != nil {
+		logger.Warnf("Cannot parse log_format %q used by %s, parsing it as combined: %v", ref.name, logPath, err)
+		format = nil
+	}
+	compiledLogFormats[key] = format
+
+	return format
+}
+
+// findLogFormatDefinition looks the referenced format up in the config file of
+// the access_log directive first, then in the other config files in path
+// order. Callers must hold logFormatMutex.
+func findLogFormatDefinition(ref logFormatRef) (utils.LogFormatDefinition, bool) {
+	for _, definition := range logFormatDefinitions[ref.configFile] {
+		if definition.Name == ref.name {
+			return definition, true
+		}
+	}
+
+	configFiles := make([]string, 0, len(logFormatDefinitions))
+	for configFile := range logFormatDefinitions {
+		configFiles = append(configFiles, configFile)
+	}
+	slices.Sort(configFiles)
+
+	for _, configFile := range configFiles {
+		for _, definition := range logFormatDefinitions[configFile] {
+			if definition.Name == ref.name {
+				return definition, true
+			}
+		}
+	}
+
+	return utils.LogFormatDefinition{}, false
+}
@@ -0,0 +1,48 @@
+package nginx_log
+
+import (
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveLogFormat(t *testing.T) {
+	t.Cleanup(func() {
+		for _, configPath := range []string{"/etc/nginx/nginx.conf", "/etc/nginx/sites-enabled/app.conf"} {
+			updateLogFormats(configPath, nil, nil)
+		}
+	})
+
+	scan := func(configPath, content string) {
+		updateLogFormats(configPath, []byte(content), utils.ScanLogDirectives("/etc/nginx", []byte(content)))
+	}
+
+	// The definition lives in nginx.conf, the access_log in a site config.
+	scan("/etc/nginx/nginx.conf", `http {
+    log_format json_log escape=json '{"ip":"$remote_addr","status":$status}';
+    log_format broken '$remote_addr$status';
+    access_log /var/log/nginx/access.log;
+}`)
+	scan("/etc/nginx/sites-enabled/app.conf", `server {
+    access_log /var/log/nginx/app.json.log json_log;
+    access_log /var/log/nginx/app.broken.log broken;
+    access_log /var/log/nginx/app.missing.log missing;
+}`)
+
+	format := ResolveLogFormat("/var/log/nginx/app.json.log")
+	require.NotNil(t, format)
+	assert.Equal(t, "json_log", format.Name)
+	assert.True(t, format.IsJSON())
+	assert.Same(t, format, ResolveLogFormat("/var/log/nginx/app.json.log"))
+
+	assert.Nil(t, ResolveLogFormat("/var/log/nginx/access.log"))
+	assert.Nil(t, ResolveLogFormat("/var/log/nginx/app.broken.log"))
+	assert.Nil(t, ResolveLogFormat("/var/log/nginx/app.missing.log"))
+	assert.Nil(t, ResolveLogFormat("/var/log/nginx/unknown.log"))
+
+	// Rescanning a config file replaces its references.
+	scan("/etc/nginx/sites-enabled/app.conf", `server { access_log /var/log/nginx/app.json.log; }`)
+	assert.Nil(t, ResolveLogFormat("/var/log/nginx/app.json.log"))
+}
@@ -86,6 +86,7 @@ func InitializeServices(ctx context.Context) {
 	globalAnalytics = analyticsInstance
 	globalIndexer = indexerInstance
 	globalLogFileManager = logFileManagerInstance
+	openErrorIndexLocked(indexerInstance.GetConfig().IndexPath)
 	shutdownCancel = cancel
 	servicesInitialized = true
 	servicesInitializing = false
@@ -526,6 +527,7 @@ func updateSearcherShardsLocked() {
 
 		// Create analytics service with the initial searcher
 		globalAnalytics = analytics.NewService(globalSearcher)
+		attachErrorSearcherLocked()
 
 		isHealthy := globalSearcher.IsHealthy()
 		isRunning := globalSearcher.IsRunning()
@@ -611,6 +613,8 @@ func StopServices() {
 		globalSearcher = nil
 	}
 
+	closeErrorIndexLocked()
+
 	// Reset state
 	globalLogFileManager = nil
 	servicesInitialized = false
@@ -630,6 +634,12 @@ func DestroyAllIndexes(ctx context.Context) error {
 		return fmt.Errorf("services not initialized")
 	}
 
+	if globalErrorIndex != nil {
+		if err := globalErrorIndex.DeleteAll(); err != nil {
+			logger.Errorf("Failed to clear error log index: %v", err)
+		}
+	}
+
 	return globalIndexer.DestroyAllIndexes(ctx)
 }
 
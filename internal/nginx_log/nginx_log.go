@@ -5,6 +5,7 @@ import (
 
 	"github.com/0xJacky/Nginx-UI/internal/cache"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
 	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
 )
 
@@ -12,6 +13,9 @@ import (
 func init() {
 	// Register the callback directly with the global registry
 	cache.RegisterCallback("nginx_log.scanForLogDirectives", scanForLogDirectives)
+
+	// Let the indexer parse each access log with its own log_format
+	indexer.SetLogFormatResolver(ResolveLogFormat)
 }
 
 // scanForLogDirectives scans and parses configuration files for log directives
@@ -22,11 +26,15 @@ func scanForLogDirectives(configPath string, content []byte) error {
 	RemoveLogPathsFromConfig(configPath)
 
 	// Extract, validate and register log directives from the config content
-	for _, directive := range utils.ScanLogDirectives(prefix, content) {
+	directives := utils.ScanLogDirectives(prefix, content)
+	for _, directive := range directives {
 		if utils.IsValidLogPath(directive.Path) {
 			AddLogPath(directive.Path, directive.Type, filepath.Base(directive.Path), configPath)
 		}
 	}
 
+	// Track log_format definitions and which format each access log uses
+	updateLogFormats(configPath, content, directives)
+
 	return nil
 }
@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errorLogTimeLayout is the timestamp layout of nginx error log lines, which
+// are written in the server's local time zone.
+const errorLogTimeLayout = "2006/01/02 15:04:05"
+
+// ErrorLogLevels lists the nginx error log levels from least to most severe.
+var ErrorLogLevels = []string{"debug", "info", "notice", "warn", "error", "crit", "alert", "emerg"}
+
+// errorLevelSeverity maps a level to its position in ErrorLogLevels.
+var errorLevelSeverity = func() map[string]int {
+	severity := make(map[string]int, len(ErrorLogLevels))
+	for i, level := range ErrorLogLevels {
+		severity[level] = i
+	}
+	return severity
+}()
+
+// ErrorLevelSeverity returns the severity of an error log level, higher being
+// more severe, or -1 for an unknown level.
+func ErrorLevelSeverity(level string) int {
+	if severity, ok := errorLevelSeverity[level]; ok {
+		return severity
+	}
+	return -1
+}
+
+// ParseErrorLine parses an nginx error log line of the form
+//
+//	2006/01/02 15:04:05 [level] pid#tid: *cid message, client: X, server: Y, request: "...", upstream: "...", host: "..."
+//
+// The connection id and the trailing context are optional.
+func ParseErrorLine(line string) (*ErrorLogEntry, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, ErrEmptyLogLine
+	}
+	if len(line) > DefaultParserConfig().MaxLineLength {
+		return nil, ErrLineTooLong
+	}
+	if len(line) < len(errorLogTimeLayout)+1 || line[len(errorLogTimeLayout)] != ' ' {
+		return nil, ErrInvalidErrorLogLine
+	}
+
+	ts, err := time.ParseInLocation(errorLogTimeLayout, line[:len(errorLogTimeLayout)], time.Local)
+	if err != nil {
+		return nil, ErrInvalidTimestamp
+	}
+
+	entry := &ErrorLogEntry{
+		Timestamp: ts.Unix(),
+		Raw:       line,
+	}
+
+	rest := line[len(errorLogTimeLayout)+1:]
+	if !strings.HasPrefix(rest, "[") {
+		return nil, ErrInvalidErrorLogLine
+	}
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return nil, ErrInvalidErrorLogLine
+	}
+	entry.Level = rest[1:end]
+	if ErrorLevelSeverity(entry.Level) < 0 {
+		return nil, ErrInvalidErrorLogLine
+	}
+	rest = strings.TrimPrefix(rest[end+1:], " ")
+
+	// pid#tid:
+	hash := strings.IndexByte(rest, '#')
+	colon := strings.IndexByte(rest, ':')
+	if hash <= 0 || colon <= hash {
+		return nil, ErrInvalidErrorLogLine
+	}
+	if entry.PID, err = strconv.Atoi(rest[:hash]); err != nil {
+		return nil, ErrInvalidErrorLogLine
+	}
+	if entry.TID, err = strconv.Atoi(rest[hash+1 : colon]); err != nil {
+		return nil, ErrInvalidErrorLogLine
+	}
+	rest = strings.TrimPrefix(rest[colon+1:], " ")
+
+	// *connection_id
+	if strings.HasPrefix(rest, "*") {
+		space := strings.IndexByte(rest, ' ')
+		if space < 0 {
+			space = len(rest)
+		}
+		if cid, err := strconv.ParseInt(rest[1:space], 10, 64); err == nil {
+			entry.ConnectionID = cid
+			rest = strings.TrimPrefix(rest[space:], " ")
+		}
+	}
+
+	entry.Message = rest
+	if idx := errorContextIndex(rest); idx >= 0 {
+		entry.Message = rest[:idx]
+		parseErrorContext(entry, rest[idx+2:])
+	}
+
+	if entry.Request != "" {
+		parts := strings.Fields(entry.Request)
+		if len(parts) >= 1 && ValidHTTPMethods[parts[0]] {
+			entry.Method = parts[0]
+		}
+		if len(parts) >= 2 {
+			entry.Path = parts[1]
+		}
+	}
+
+	return entry, nil
+}
+
+// errorContextIndex returns the position of the ", client: " or ", server: "
+// separator nginx appends the request context after, or -1.
+func errorContextIndex(s string) int {
+	if idx := strings.Index(s, ", client: "); idx >= 0 {
+		return idx
+	}
+	return strings.Index(s, ", server: ")
+}
+
+// parseErrorContext parses the `key: value, key: "quoted value"` pairs that
+// follow the message.
+func parseErrorContext(entry *ErrorLogEntry, s string) {
+	for s != "" {
+		sep := strings.Index(s, ": ")
+		if sep < 0 {
+			return
+		}
+		key := s[:sep]
+		s = s[sep+2:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			// nginx escapes quotes inside values, so the value ends at the
+			// first quote followed by the next pair or the end of the line.
+			end := strings.Index(s[1:], `", `)
+			if end < 0 {
+				value = strings.TrimSuffix(s[1:], `"`)
+				s = ""
+			} else {
+				value = s[1 : end+1]
+				s = s[end+4:]
+			}
+		} else {
+			end := strings.Index(s, ", ")
+			if end < 0 {
+				value = s
+				s = ""
+			} else {
+				value = s[:end]
+				s = s[end+2:]
+			}
+		}
+
+		switch key {
+		case "client":
+			entry.Client = value
+		case "server":
+			entry.Server = value
+		case "request":
+			entry.Request = value
+		case "upstream":
+			entry.Upstream = value
+		case "host":
+			entry.Host = value
+		case "referrer":
+			entry.Referrer = value
+		}
+	}
+}
+
+var (
+	uuidPattern       = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	ipv4Pattern       = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`)
+	ipv6Pattern       = regexp.MustCompile(`\[?[0-9a-fA-F]{0,4}(?::[0-9a-fA-F]{0,4}){2,7}\]?(?::\d+)?`)
+	hexPattern        = regexp.MustCompile(`\b(?:0x)?[0-9a-fA-F]*\d[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\b|\b(?:0x)?[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\d[0-9a-fA-F]*\b`)
+	numberPattern     = regexp.MustCompile(`\b\d+\b`)
+	multiSpacePattern = regexp.MustCompile(`\s+`)
+)
+
+// MessagePattern normalizes an error message so that messages differing only
+// in UUIDs, IP addresses, hexadecimal ids or numbers collapse to the same
+// pattern, e.g. `connect() failed (111: Connection refused) while connecting
+// to upstream 10.0.0.1:8080` becomes `connect() failed (<n>: Connection
+// refused) while connecting to upstream <ip>`.
+func MessagePattern(message string) string {
+	pattern := uuidPattern.ReplaceAllString(message, "<uuid>")
+	pattern = ipv4Pattern.ReplaceAllString(pattern, "<ip>")
+	pattern = ipv6Pattern.ReplaceAllStringFunc(pattern, func(match string) string {
+		// Require at least one hex digit so plain "::" stays untouched.
+		if strings.Count(match, ":") < 2 || strings.Trim(match, ":[]") == "" {
+			return match
+		}
+		return "<ip>"
+	})
+	pattern = hexPattern.ReplaceAllStringFunc(pattern, func(match string) string {
+		if len(strings.TrimPrefix(match, "0x")) < 6 {
+			return match
+		}
+		return "<hex>"
+	})
+	pattern = numberPattern.ReplaceAllString(pattern, "<n>")
+	return strings.TrimSpace(multiSpacePattern.ReplaceAllString(pattern, " "))
+}
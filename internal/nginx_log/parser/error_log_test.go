@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrorLine(t *testing.T) {
+	line := `2024/01/15 10:30:45 [error] 1234#5678: *91 connect() failed (111: Connection refused) while connecting to upstream, ` +
+		`client: 192.168.1.10, server: example.com, request: "GET /api/items?id=1 HTTP/1.1", ` +
+		`upstream: "http://127.0.0.1:8080/api/items?id=1", host: "example.com", referrer: "https://example.com/"`
+
+	entry, err := ParseErrorLine(line)
+	if err != nil {
+		t.Fatalf("ParseErrorLine() error = %v", err)
+	}
+
+	wantTime := time.Date(2024, 1, 15, 10, 30, 45, 0, time.Local).Unix()
+	if entry.Timestamp != wantTime {
+		t.Errorf("Timestamp = %d, want %d", entry.Timestamp, wantTime)
+	}
+	if entry.Level != "error" || entry.PID != 1234 || entry.TID != 5678 || entry.ConnectionID != 91 {
+		t.Errorf("unexpected header fields: level=%q pid=%d tid=%d cid=%d", entry.Level, entry.PID, entry.TID, entry.ConnectionID)
+	}
+	if entry.Message != "connect() failed (111: Connection refused) while connecting to upstream" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	if entry.Client != "192.168.1.10" || entry.Server != "example.com" || entry.Host != "example.com" {
+		t.Errorf("unexpected context: client=%q server=%q host=%q", entry.Client, entry.Server, entry.Host)
+	}
+	if entry.Request != "GET /api/items?id=1 HTTP/1.1" || entry.Method != "GET" || entry.Path != "/api/items?id=1" {
+		t.Errorf("unexpected request: %q method=%q path=%q", entry.Request, entry.Method, entry.Path)
+	}
+	if entry.Upstream != "http://127.0.0.1:8080/api/items?id=1" {
+		t.Errorf("Upstream = %q", entry.Upstream)
+	}
+	if entry.Referrer != "https://example.com/" {
+		t.Errorf("Referrer = %q", entry.Referrer)
+	}
+	if entry.Raw != line {
+		t.Error("Raw should hold the original line")
+	}
+}
+
+func TestParseErrorLineWithoutContext(t *testing.T) {
+	entry, err := ParseErrorLine("2024/01/15 10:30:45 [notice] 1#1: signal process started\n")
+	if err != nil {
+		t.Fatalf("ParseErrorLine() error = %v", err)
+	}
+	if entry.Level != "notice" || entry.ConnectionID != 0 || entry.Message != "signal process started" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Client != "" || entry.Server != "" {
+		t.Errorf("unexpected context: client=%q server=%q", entry.Client, entry.Server)
+	}
+}
+
+func TestParseErrorLineServerOnlyContext(t *testing.T) {
+	entry, err := ParseErrorLine(`2024/01/15 10:30:45 [crit] 7#7: *3 SSL_do_handshake() failed while SSL handshaking, server: 0.0.0.0:443`)
+	if err != nil {
+		t.Fatalf("ParseErrorLine() error = %v", err)
+	}
+	if entry.Message != "SSL_do_handshake() failed while SSL handshaking" || entry.Server != "0.0.0.0:443" {
+		t.Errorf("unexpected entry: message=%q server=%q", entry.Message, entry.Server)
+	}
+}
+
+func TestParseErrorLineInvalid(t *testing.T) {
+	lines := []string{
+		"",
+		`127.0.0.1 - - [25/Dec/2023:10:00:00 +0000] "GET / HTTP/1.1" 200 1`,
+		"2024/01/15 10:30:45 [fatal] 1#1: unknown level",
+		"2024/01/15 10:30:45 [error] no pid",
+		"2024-01-15 10:30:45 [error] 1#1: wrong date",
+	}
+
+	for _, line := range lines {
+		if _, err := ParseErrorLine(line); err == nil {
+			t.Errorf("ParseErrorLine(%q) expected error", line)
+		}
+	}
+}
+
+func TestMessagePattern(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{
+			message: "connect() failed (111: Connection refused) while connecting to upstream",
+			want:    "connect() failed (<n>: Connection refused) while connecting to upstream",
+		},
+		{
+			message: `upstream timed out (110: Connection timed out) while reading response header from upstream 10.0.0.1:8080`,
+			want:    `upstream timed out (<n>: Connection timed out) while reading response header from upstream <ip>`,
+		},
+		{
+			message: `limiting requests, excess: 20.520 by zone "api", client [2001:db8::1]:51234`,
+			want:    `limiting requests, excess: <n>.<n> by zone "api", client <ip>`,
+		},
+		{
+			message: `request 3f2a1c9e-5b7d-4e8f-9a0b-1c2d3e4f5a6b rejected, token 9f86d081884c7d65 expired`,
+			want:    `request <uuid> rejected, token <hex> expired`,
+		},
+		{
+			message: "signal process started",
+			want:    "signal process started",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := MessagePattern(tt.message); got != tt.want {
+			t.Errorf("MessagePattern(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+
+	// Messages differing only in ids and addresses share a pattern.
+	a := MessagePattern("client 192.0.2.1 closed keepalive connection *42")
+	b := MessagePattern("client 198.51.100.7 closed keepalive connection *9001")
+	if a != b {
+		t.Errorf("expected equal patterns, got %q and %q", a, b)
+	}
+}
+
+func TestErrorLevelSeverity(t *testing.T) {
+	if ErrorLevelSeverity("emerg") <= ErrorLevelSeverity("error") || ErrorLevelSeverity("error") <= ErrorLevelSeverity("warn") {
+		t.Error("expected emerg > error > warn")
+	}
+	if ErrorLevelSeverity("fatal") != -1 {
+		t.Error("expected -1 for an unknown level")
+	}
+}
@@ -8,4 +8,7 @@ var (
 	ErrLineTooLong          = e.New(50102, "log line exceeds maximum length")
 	ErrUnsupportedLogFormat = e.New(50103, "unsupported log format")
 	ErrInvalidTimestamp     = e.New(50104, "invalid timestamp format")
+	ErrInvalidLogFormat     = e.New(50105, "invalid log format {0}: {1}")
+	ErrLogFormatMismatch    = e.New(50106, "log line does not match log format")
+	ErrInvalidErrorLogLine  = e.New(50107, "invalid error log line")
 )
@@ -0,0 +1,393 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uozi-tech/cosy"
+)
+
+// CombinedLogFormat is the pattern of nginx's predefined "combined" format,
+// which the built-in parser handles without compiling a LogFormat.
+const CombinedLogFormat = `$remote_addr - $remote_user [$time_local] "$request" ` +
+	`$status $body_bytes_sent "$http_referer" "$http_user_agent"`
+
+// LogFormat is a compiled nginx log_format definition.
+type LogFormat struct {
+	Name      string
+	Escape    string   // "default", "json" or "none"
+	Pattern   string   // the format string as written in the configuration
+	Variables []string // variables referenced by the pattern, in order
+
+	segments  []formatSegment
+	jsonKeys  map[string]string // JSON key -> variable, for JSON formats
+	json      bool
+	combined  bool
+	variables map[string]bool
+}
+
+// formatSegment is either a literal or a variable of a text log format.
+type formatSegment struct {
+	literal  string
+	variable string
+}
+
+// jsonFormatFieldRegex matches `"key": $var` or `"key": "$var"` pairs in a JSON
+// log format, i.e. keys whose value is exactly one variable.
+var jsonFormatFieldRegex = regexp.MustCompile(`"([^"]+)"\s*:\s*"?\$\{?([A-Za-z0-9_]+)\}?"?\s*[,}]`)
+
+// CompileLogFormat compiles a log_format definition into a LogFormat. Formats
+// whose pattern is a JSON object are parsed as JSON, every other format is
+// matched literal by literal. An empty pattern is only accepted for the
+// predefined "combined" format.
+func CompileLogFormat(name, escape, pattern string) (*LogFormat, error) {
+	if escape == "" {
+		escape = "default"
+	}
+	switch escape {
+	case "default", "json", "none":
+	default:
+		return nil, cosy.WrapErrorWithParams(ErrInvalidLogFormat, name, "unknown escape "+escape)
+	}
+
+	if pattern == "" && name == "combined" {
+		pattern = CombinedLogFormat
+	}
+
+	format := &LogFormat{
+		Name:      name,
+		Escape:    escape,
+		Pattern:   pattern,
+		variables: make(map[string]bool),
+	}
+
+	format.segments = splitFormatSegments(pattern)
+	for _, segment := range format.segments {
+		if segment.variable != "" && !format.variables[segment.variable] {
+			format.variables[segment.variable] = true
+			format.Variables = append(format.Variables, segment.variable)
+		}
+	}
+	if len(format.Variables) == 0 {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidLogFormat, name, "no variables")
+	}
+
+	if strings.Join(strings.Fields(pattern), " ") == CombinedLogFormat {
+		format.combined = true
+		return format, nil
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(pattern), "{") {
+		format.json = true
+		format.jsonKeys = make(map[string]string)
+		for _, m := range jsonFormatFieldRegex.FindAllStringSubmatch(pattern, -1) {
+			format.jsonKeys[m[1]] = m[2]
+		}
+		return format, nil
+	}
+
+	for i := 1; i < len(format.segments); i++ {
+		if format.segments[i].variable != "" && format.segments[i-1].variable != "" {
+			return nil, cosy.WrapErrorWithParams(ErrInvalidLogFormat, name,
+				"variables $"+format.segments[i-1].variable+" and $"+format.segments[i].variable+" are not separated")
+		}
+	}
+
+	return format, nil
+}
+
+// IsCombined reports whether the format is equivalent to nginx's combined format.
+func (f *LogFormat) IsCombined() bool {
+	return f.combined
+}
+
+// IsJSON reports whether log lines of this format are JSON objects.
+func (f *LogFormat) IsJSON() bool {
+	return f.json
+}
+
+// Key identifies the definition, so parsers can be cached per distinct format.
+func (f *LogFormat) Key() string {
+	return f.Name + "\x00" + f.Escape + "\x00" + f.Pattern
+}
+
+// splitFormatSegments splits a pattern into literals and $variable / ${variable}
+// references. A '$' that does not start a variable name stays literal.
+func splitFormatSegments(pattern string) []formatSegment {
+	var segments []formatSegment
+	var literal strings.Builder
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, formatSegment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '$' {
+			literal.WriteByte(pattern[i])
+			continue
+		}
+
+		start, braced := i+1, false
+		if start < len(pattern) && pattern[start] == '{' {
+			start++
+			braced = true
+		}
+		end := start
+		for end < len(pattern) && isVariableChar(pattern[end]) {
+			end++
+		}
+		if end == start || (braced && (end >= len(pattern) || pattern[end] != '}')) {
+			literal.WriteByte(pattern[i])
+			continue
+		}
+
+		flushLiteral()
+		segments = append(segments, formatSegment{variable: pattern[start:end]})
+		i = end - 1
+		if braced {
+			i = end
+		}
+	}
+	flushLiteral()
+
+	return segments
+}
+
+func isVariableChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// WithFormat returns a parser that shares p's configuration and enrichment
+// services but parses lines according to format. A nil or combined format
+// returns p itself, since the built-in parser already handles it.
+func (p *Parser) WithFormat(format *LogFormat) *Parser {
+	if format == nil || format.combined {
+		return p
+	}
+
+	formatted := NewParser(p.config, p.uaParser, p.geoService)
+	formatted.format = format
+	return formatted
+}
+
+// Format returns the custom log format of the parser, or nil for the built-in
+// combined format parser.
+func (p *Parser) Format() *LogFormat {
+	return p.format
+}
+
+// parseLineWithFormat parses a line according to p.format and enriches the
+// resulting entry the same way the built-in parser does.
+func (p *Parser) parseLineWithFormat(line string, buf *parseBuffer) error {
+	assign := func(name, value string) {
+		p.assignVariable(buf, name, value)
+	}
+
+	var err error
+	if p.format.json {
+		err = p.format.parseJSON(line, assign)
+	} else {
+		err = p.format.parseText(line, assign)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.enrichGeo(buf.entry)
+	p.enrichUserAgent(buf.entry)
+
+	return nil
+}
+
+// parseText walks the format segments: literals must match in place, and each
+// variable extends up to the next occurrence of the literal that follows it.
+func (f *LogFormat) parseText(line string, assign func(name, value string)) error {
+	pos := 0
+
+	for i, segment := range f.segments {
+		if segment.variable == "" {
+			if !strings.HasPrefix(line[pos:], segment.literal) {
+				return ErrLogFormatMismatch
+			}
+			pos += len(segment.literal)
+			continue
+		}
+
+		end := len(line)
+		if i+1 < len(f.segments) {
+			idx := strings.Index(line[pos:], f.segments[i+1].literal)
+			if idx < 0 {
+				return ErrLogFormatMismatch
+			}
+			end = pos + idx
+		}
+
+		assign(segment.variable, line[pos:end])
+		pos = end
+	}
+
+	return nil
+}
+
+// parseJSON decodes a JSON log line. Keys that hold exactly one variable in
+// the format are assigned to that variable, other keys are kept under their
+// own (sanitized) name.
+func (f *LogFormat) parseJSON(line string, assign func(name, value string)) error {
+	data := []byte(line)
+	if f.Escape != "json" && strings.Contains(line, `\x`) {
+		// escape=default writes non-printable bytes and quotes as \xHH,
+		// which is not valid JSON; rewrite them as \u00HH.
+		data = bytes.ReplaceAll(data, []byte(`\x`), []byte(`\u00`))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return ErrLogFormatMismatch
+	}
+
+	for key, raw := range fields {
+		var value string
+		switch typed := raw.(type) {
+		case nil:
+			continue
+		case string:
+			value = typed
+		case json.Number:
+			value = typed.String()
+		case bool:
+			value = strconv.FormatBool(typed)
+		default:
+			encoded, err := json.Marshal(typed)
+			if err != nil {
+				continue
+			}
+			value = string(encoded)
+		}
+
+		name, ok := f.jsonKeys[key]
+		if !ok {
+			name = sanitizeFieldName(key)
+		}
+		assign(name, value)
+	}
+
+	return nil
+}
+
+// sanitizeFieldName maps a JSON key to a name usable as an index field.
+func sanitizeFieldName(key string) string {
+	b := []byte(key)
+	for i, c := range b {
+		if !isVariableChar(c) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// assignVariable maps a log_format variable onto the entry. Variables without
+// a dedicated AccessLogEntry field are kept in entry.Extra.
+func (p *Parser) assignVariable(buf *parseBuffer, name, value string) {
+	if value == "" || value == "-" {
+		return
+	}
+
+	entry := buf.entry
+	switch name {
+	case "remote_addr":
+		entry.IP = value
+	case "time_local":
+		if value == buf.lastTimeStr {
+			entry.Timestamp = buf.lastTimeUnix
+		} else if ts := p.parseTime(value); ts != 0 {
+			entry.Timestamp = ts
+			buf.lastTimeStr = value
+			buf.lastTimeUnix = ts
+		}
+	case "time_iso8601":
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			entry.Timestamp = t.Unix()
+		}
+	case "msec":
+		if sec, err := strconv.ParseFloat(value, 64); err == nil {
+			entry.Timestamp = int64(sec)
+		}
+	case "request":
+		parts := strings.Fields(value)
+		if len(parts) >= 1 && ValidHTTPMethods[parts[0]] {
+			entry.Method = parts[0]
+		}
+		if len(parts) >= 2 && entry.Path == "" {
+			entry.Path = parts[1]
+		}
+		if len(parts) >= 3 && entry.Protocol == "" {
+			entry.Protocol = parts[2]
+		}
+	case "request_method":
+		if ValidHTTPMethods[value] {
+			entry.Method = value
+		}
+	case "request_uri":
+		entry.Path = value
+	case "uri":
+		if !p.format.variables["request_uri"] && !p.format.variables["request"] {
+			entry.Path = value
+		}
+	case "server_protocol":
+		entry.Protocol = value
+	case "status":
+		if status, err := strconv.Atoi(value); err == nil && status >= 100 && status < 600 {
+			entry.Status = status
+		}
+	case "body_bytes_sent":
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil && size >= 0 {
+			entry.BytesSent = size
+		}
+	case "bytes_sent":
+		if p.format.variables["body_bytes_sent"] {
+			setExtra(entry, name, value)
+		} else if size, err := strconv.ParseInt(value, 10, 64); err == nil && size >= 0 {
+			entry.BytesSent = size
+		}
+	case "http_referer":
+		entry.Referer = value
+	case "http_user_agent":
+		entry.UserAgent = value
+	case "request_time":
+		if val, err := strconv.ParseFloat(value, 64); err == nil && val >= 0 {
+			entry.RequestTime = val
+		}
+	case "upstream_response_time":
+		// Multiple upstreams are logged as "0.010, 0.020 : 0.030"; the first
+		// value is the one the combined-format parser records.
+		end := 0
+		for end < len(value) && ((value[end] >= '0' && value[end] <= '9') || value[end] == '.') {
+			end++
+		}
+		if val, err := strconv.ParseFloat(value[:end], 64); err == nil && val >= 0 {
+			entry.UpstreamTime = &val
+		}
+		if end < len(value) {
+			setExtra(entry, name, value)
+		}
+	default:
+		setExtra(entry, name, value)
+	}
+}
+
+func setExtra(entry *AccessLogEntry, name, value string) {
+	if entry.Extra == nil {
+		entry.Extra = make(map[string]string)
+	}
+	entry.Extra[name] = value
+}
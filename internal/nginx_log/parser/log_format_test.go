@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"testing"
+)
+
+func newFormatTestParser(t *testing.T, name, escape, pattern string) *Parser {
+	t.Helper()
+
+	format, err := CompileLogFormat(name, escape, pattern)
+	if err != nil {
+		t.Fatalf("CompileLogFormat() error = %v", err)
+	}
+
+	config := DefaultParserConfig()
+	config.StrictMode = true
+
+	return NewParser(config, NewSimpleUserAgentParser(), &mockGeoIPService{}).WithFormat(format)
+}
+
+func TestCompileLogFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		escape   string
+		pattern  string
+		wantErr  bool
+		combined bool
+		json     bool
+		vars     []string
+	}{
+		{
+			name:     "predefined combined",
+			format:   "combined",
+			combined: true,
+			vars:     []string{"remote_addr", "remote_user", "time_local", "request", "status", "body_bytes_sent", "http_referer", "http_user_agent"},
+		},
+		{
+			name:     "combined spelled out over multiple strings",
+			format:   "main",
+			pattern:  `$remote_addr - $remote_user [$time_local]  "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"`,
+			combined: true,
+			vars:     []string{"remote_addr", "remote_user", "time_local", "request", "status", "body_bytes_sent", "http_referer", "http_user_agent"},
+		},
+		{
+			name:    "braced variables",
+			format:  "upstream",
+			pattern: `${remote_addr}|$status|${upstream_addr}`,
+			vars:    []string{"remote_addr", "status", "upstream_addr"},
+		},
+		{
+			name:    "json format",
+			format:  "json",
+			escape:  "json",
+			pattern: `{"ip":"$remote_addr","status":$status}`,
+			json:    true,
+			vars:    []string{"remote_addr", "status"},
+		},
+		{
+			name:    "unknown escape",
+			format:  "bad",
+			escape:  "html",
+			pattern: `$remote_addr`,
+			wantErr: true,
+		},
+		{
+			name:    "no variables",
+			format:  "static",
+			pattern: `static text`,
+			wantErr: true,
+		},
+		{
+			name:    "adjacent variables cannot be split",
+			format:  "adjacent",
+			pattern: `$remote_addr$status`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := CompileLogFormat(tt.format, tt.escape, tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if format.IsCombined() != tt.combined {
+				t.Errorf("IsCombined() = %v, want %v", format.IsCombined(), tt.combined)
+			}
+			if format.IsJSON() != tt.json {
+				t.Errorf("IsJSON() = %v, want %v", format.IsJSON(), tt.json)
+			}
+			if len(format.Variables) != len(tt.vars) {
+				t.Fatalf("Variables = %v, want %v", format.Variables, tt.vars)
+			}
+			for i := range tt.vars {
+				if format.Variables[i] != tt.vars[i] {
+					t.Errorf("Variables[%d] = %q, want %q", i, format.Variables[i], tt.vars[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParser_WithCombinedFormatUsesBuiltinParser(t *testing.T) {
+	format, err := CompileLogFormat("combined", "", "")
+	if err != nil {
+		t.Fatalf("CompileLogFormat() error = %v", err)
+	}
+
+	parser := NewParser(DefaultParserConfig(), nil, nil)
+	if parser.WithFormat(format) != parser {
+		t.Error("expected the combined format to reuse the built-in parser")
+	}
+}
+
+func TestParser_ParseLineWithTextFormat(t *testing.T) {
+	parser := newFormatTestParser(t, "upstream", "",
+		`$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent `+
+			`"$http_referer" "$http_user_agent" rt=$request_time uct="$upstream_connect_time" `+
+			`urt="$upstream_response_time" ua="$upstream_addr" host=$host`)
+
+	line := `127.0.0.1 - alice [25/Dec/2023:10:00:00 +0000] "GET /api/items?id=1 HTTP/2.0" 200 512 ` +
+		`"https://example.com/" "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0" rt=0.120 uct="0.001" ` +
+		`urt="0.050, 0.060" ua="10.0.0.1:8080, 10.0.0.2:8080" host=example.com`
+
+	entry, err := parser.ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine() error = %v", err)
+	}
+
+	if entry.IP != "127.0.0.1" || entry.City != "San Francisco" {
+		t.Errorf("unexpected client fields: ip=%q city=%q", entry.IP, entry.City)
+	}
+	if entry.Timestamp != 1703498400 {
+		t.Errorf("Timestamp = %d, want 1703498400", entry.Timestamp)
+	}
+	if entry.Method != "GET" || entry.Path != "/api/items?id=1" || entry.Protocol != "HTTP/2.0" {
+		t.Errorf("unexpected request fields: %s %s %s", entry.Method, entry.Path, entry.Protocol)
+	}
+	if entry.Status != 200 || entry.BytesSent != 512 || entry.RequestTime != 0.120 {
+		t.Errorf("unexpected response fields: status=%d bytes=%d rt=%v", entry.Status, entry.BytesSent, entry.RequestTime)
+	}
+	if entry.Browser != "Firefox" {
+		t.Errorf("Browser = %q, want Firefox", entry.Browser)
+	}
+	if entry.UpstreamTime == nil || *entry.UpstreamTime != 0.050 {
+		t.Errorf("UpstreamTime = %v, want 0.050", entry.UpstreamTime)
+	}
+
+	wantExtra := map[string]string{
+		"remote_user":            "alice",
+		"upstream_connect_time":  "0.001",
+		"upstream_response_time": "0.050, 0.060",
+		"upstream_addr":          "10.0.0.1:8080, 10.0.0.2:8080",
+		"host":                   "example.com",
+	}
+	if len(entry.Extra) != len(wantExtra) {
+		t.Errorf("Extra = %v, want %v", entry.Extra, wantExtra)
+	}
+	for name, want := range wantExtra {
+		if got := entry.Extra[name]; got != want {
+			t.Errorf("Extra[%q] = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParser_ParseLineWithTextFormatMismatch(t *testing.T) {
+	parser := newFormatTestParser(t, "pipe", "", `$remote_addr|$status|$request_time`)
+
+	if _, err := parser.ParseLine(`127.0.0.1 - - [25/Dec/2023:10:00:00 +0000] "GET / HTTP/1.1" 200 1`); err == nil {
+		t.Error("expected a mismatch error for a line in another format")
+	}
+}
+
+func TestParser_ParseLineWithJSONFormat(t *testing.T) {
+	parser := newFormatTestParser(t, "json_log", "json",
+		`{"time":"$time_iso8601","client":"$remote_addr","method":"$request_method",`+
+			`"uri":"$request_uri","status":$status,"bytes":$body_bytes_sent,"agent":"$http_user_agent",`+
+			`"cache":"$upstream_cache_status","trace":"$http_x_trace_id","label":"static-$host"}`)
+
+	line := `{"time":"2023-12-25T10:00:00+00:00","client":"::1","method":"POST","uri":"/login",` +
+		`"status":302,"bytes":0,"agent":"curl/8.0","cache":"HIT","trace":"","label":"static-example.com"}`
+
+	entry, err := parser.ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine() error = %v", err)
+	}
+
+	if entry.IP != "::1" || entry.Timestamp != 1703498400 {
+		t.Errorf("unexpected client fields: ip=%q ts=%d", entry.IP, entry.Timestamp)
+	}
+	if entry.Method != "POST" || entry.Path != "/login" || entry.Status != 302 {
+		t.Errorf("unexpected request fields: %s %s %d", entry.Method, entry.Path, entry.Status)
+	}
+	if entry.UserAgent != "curl/8.0" {
+		t.Errorf("UserAgent = %q, want curl/8.0", entry.UserAgent)
+	}
+	if entry.Extra["upstream_cache_status"] != "HIT" {
+		t.Errorf("Extra[upstream_cache_status] = %q, want HIT", entry.Extra["upstream_cache_status"])
+	}
+	if _, ok := entry.Extra["http_x_trace_id"]; ok {
+		t.Error("empty variables should not be kept")
+	}
+	if entry.Extra["label"] != "static-example.com" {
+		t.Errorf("Extra[label] = %q, want static-example.com", entry.Extra["label"])
+	}
+}
+
+func TestParser_ParseLineWithJSONFormatDefaultEscape(t *testing.T) {
+	parser := newFormatTestParser(t, "json_default", "", `{"ip":"$remote_addr","agent":"$http_user_agent"}`)
+
+	entry, err := parser.ParseLine(`{"ip":"127.0.0.1","agent":"say \x22hi\x22"}`)
+	if err != nil {
+		t.Fatalf("ParseLine() error = %v", err)
+	}
+	if entry.UserAgent != `say "hi"` {
+		t.Errorf("UserAgent = %q, want %q", entry.UserAgent, `say "hi"`)
+	}
+}
@@ -20,6 +20,10 @@ type Parser struct {
 	pool       *sync.Pool
 	stats      *ParseStats
 	mu         sync.RWMutex
+
+	// format is the custom log_format lines are parsed with; nil selects the
+	// built-in combined format parser.
+	format *LogFormat
 }
 
 // ParseStats tracks parsing performance metrics
@@ -87,10 +91,16 @@ func (p *Parser) ParseLine(line string) (*AccessLogEntry, error) {
 	buf.fields = buf.fields[:0]
 	*buf.entry = AccessLogEntry{}
 
-	// Zero-copy conversion to bytes
-	buf.lineBytes = stringToBytes(line)
+	var err error
+	if p.format != nil {
+		err = p.parseLineWithFormat(line, buf)
+	} else {
+		// Zero-copy conversion to bytes
+		buf.lineBytes = stringToBytes(line)
+		err = p.parseLineOptimized(buf.lineBytes, buf)
+	}
 
-	if err := p.parseLineOptimized(buf.lineBytes, buf); err != nil {
+	if err != nil {
 		if p.config.StrictMode {
 			return nil, err
 		}
@@ -406,23 +416,28 @@ func (p *Parser) parseIP(line []byte, pos int, entry *AccessLogEntry) int {
 	}
 	if pos > start {
 		entry.IP = bytesToString(line[start:pos])
+		p.enrichGeo(entry)
+	}
+	return pos
+}
 
-		// Populate geographic fields if enabled
-		if p.config.EnableGeoIP && p.geoService != nil && entry.IP != "-" {
-			if location, err := p.geoService.Search(entry.IP); err == nil && location != nil {
-				entry.Province = location.Province
-				entry.City = location.City
-				// Use the specific RegionCode (e.g., province code 'CA') if available,
-				// otherwise, fall back to the CountryCode (e.g., 'US').
-				if location.RegionCode != "" {
-					entry.RegionCode = location.RegionCode
-				} else {
-					entry.RegionCode = location.CountryCode
-				}
-			}
+// enrichGeo populates the geographic fields from entry.IP if enabled
+func (p *Parser) enrichGeo(entry *AccessLogEntry) {
+	if !p.config.EnableGeoIP || p.geoService == nil || entry.IP == "" || entry.IP == "-" {
+		return
+	}
+
+	if location, err := p.geoService.Search(entry.IP); err == nil && location != nil {
+		entry.Province = location.Province
+		entry.City = location.City
+		// Use the specific RegionCode (e.g., province code 'CA') if available,
+		// otherwise, fall back to the CountryCode (e.g., 'US').
+		if location.RegionCode != "" {
+			entry.RegionCode = location.RegionCode
+		} else {
+			entry.RegionCode = location.CountryCode
 		}
 	}
-	return pos
 }
 
 // fallbackTimeFormats are tried when the configured layout fails to parse.
@@ -454,21 +469,7 @@ func (p *Parser) parseTimestamp(line []byte, pos int, buf *parseBuffer) int {
 			// Same second as the previous line: reuse the cached value
 			entry.Timestamp = buf.lastTimeUnix
 		default:
-			if t, err := time.Parse(p.config.TimeLayout, timeStr); err == nil {
-				entry.Timestamp = t.Unix()
-			} else {
-				// Try alternative common nginx timestamp formats if the default fails
-				for _, format := range fallbackTimeFormats {
-					if format == p.config.TimeLayout {
-						continue // Already tried above
-					}
-					if t, err := time.Parse(format, timeStr); err == nil {
-						entry.Timestamp = t.Unix()
-						break
-					}
-				}
-				// If all parsing attempts fail, the timestamp stays 0
-			}
+			entry.Timestamp = p.parseTime(timeStr)
 
 			if entry.Timestamp != 0 {
 				// Copy the string: timeStr aliases the caller's line buffer
@@ -485,6 +486,26 @@ func (p *Parser) parseTimestamp(line []byte, pos int, buf *parseBuffer) int {
 	return pos
 }
 
+// parseTime parses a $time_local value with the configured layout, falling
+// back to other common nginx layouts. It returns 0 if no layout matches.
+func (p *Parser) parseTime(timeStr string) int64 {
+	if t, err := time.Parse(p.config.TimeLayout, timeStr); err == nil {
+		return t.Unix()
+	}
+
+	// Try alternative common nginx timestamp formats if the default fails
+	for _, format := range fallbackTimeFormats {
+		if format == p.config.TimeLayout {
+			continue // Already tried above
+		}
+		if t, err := time.Parse(format, timeStr); err == nil {
+			return t.Unix()
+		}
+	}
+
+	return 0
+}
+
 func (p *Parser) parseRequest(line []byte, pos int, entry *AccessLogEntry) int {
 	if pos >= len(line) || line[pos] != '"' {
 		return pos
@@ -590,23 +611,8 @@ func (p *Parser) parseUserAgent(line []byte, pos int, entry *AccessLogEntry) int
 	}
 
 	if pos > start {
-		userAgent := bytesToString(line[start:pos])
-		entry.UserAgent = userAgent
-
-		if p.config.EnableUA && p.uaParser != nil && userAgent != "-" {
-			parsed := p.uaParser.Parse(userAgent)
-			if parsed.Browser != "Unknown" && parsed.Browser != "" {
-				entry.Browser = parsed.Browser
-				entry.BrowserVer = parsed.BrowserVer
-			}
-			if parsed.OS != "Unknown" && parsed.OS != "" {
-				entry.OS = parsed.OS
-				entry.OSVersion = parsed.OSVersion
-			}
-			if parsed.DeviceType != "" {
-				entry.DeviceType = parsed.DeviceType
-			}
-		}
+		entry.UserAgent = bytesToString(line[start:pos])
+		p.enrichUserAgent(entry)
 	}
 
 	if pos < len(line) && line[pos] == '"' {
@@ -616,6 +622,26 @@ func (p *Parser) parseUserAgent(line []byte, pos int, entry *AccessLogEntry) int
 	return pos
 }
 
+// enrichUserAgent populates browser, OS and device fields from entry.UserAgent if enabled
+func (p *Parser) enrichUserAgent(entry *AccessLogEntry) {
+	if !p.config.EnableUA || p.uaParser == nil || entry.UserAgent == "" || entry.UserAgent == "-" {
+		return
+	}
+
+	parsed := p.uaParser.Parse(entry.UserAgent)
+	if parsed.Browser != "Unknown" && parsed.Browser != "" {
+		entry.Browser = parsed.Browser
+		entry.BrowserVer = parsed.BrowserVer
+	}
+	if parsed.OS != "Unknown" && parsed.OS != "" {
+		entry.OS = parsed.OS
+		entry.OSVersion = parsed.OSVersion
+	}
+	if parsed.DeviceType != "" {
+		entry.DeviceType = parsed.DeviceType
+	}
+}
+
 func (p *Parser) parseRequestTime(line []byte, pos int, entry *AccessLogEntry) int {
 	start := pos
 	for pos < len(line) && ((line[pos] >= '0' && line[pos] <= '9') || line[pos] == '.' || line[pos] == '-') {
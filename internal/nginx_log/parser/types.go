@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"regexp"
 	"time"
 )
 
@@ -27,13 +26,29 @@ type AccessLogEntry struct {
 	RequestTime  float64  `json:"request_time"`
 	UpstreamTime *float64 `json:"upstream_time,omitempty"`
 	Raw          string   `json:"raw"`
+
+	// Extra holds variables of custom log formats that have no dedicated
+	// field above, keyed by variable (or JSON key) name.
+	Extra map[string]string `json:"extra,omitempty"`
 }
 
-// LogFormat represents different nginx log format patterns
-type LogFormat struct {
-	Name    string
-	Pattern *regexp.Regexp
-	Fields  []string
+// ErrorLogEntry represents a parsed error log entry
+type ErrorLogEntry struct {
+	Timestamp    int64  `json:"timestamp"` // Unix timestamp
+	Level        string `json:"level"`
+	PID          int    `json:"pid"`
+	TID          int    `json:"tid"`
+	ConnectionID int64  `json:"connection_id,omitempty"`
+	Message      string `json:"message"`
+	Client       string `json:"client,omitempty"`
+	Server       string `json:"server,omitempty"`
+	Request      string `json:"request,omitempty"`
+	Method       string `json:"method,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Upstream     string `json:"upstream,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Referrer     string `json:"referrer,omitempty"`
+	Raw          string `json:"raw"`
 }
 
 // UserAgentParser interface for user agent parsing
@@ -0,0 +1,187 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+const (
+	// scanBatchSize is the number of documents fetched per SearchAfter page
+	scanBatchSize = 10000
+	// hourlyTopPaths and dailyTopPaths bound the paths kept per rollup
+	hourlyTopPaths = 25
+	dailyTopPaths  = 100
+)
+
+// bucket accumulates the documents of one rollup
+type bucket struct {
+	rollup    *model.NginxLogRollup
+	visitors  map[string]struct{}
+	paths     map[string]int64
+	countries map[string]int64
+}
+
+func newBucket(mainLogPath, granularity string, start int64) *bucket {
+	return &bucket{
+		rollup: &model.NginxLogRollup{
+			MainLogPath: mainLogPath,
+			Granularity: granularity,
+			BucketStart: start,
+		},
+		visitors:  make(map[string]struct{}),
+		paths:     make(map[string]int64),
+		countries: make(map[string]int64),
+	}
+}
+
+func (b *bucket) add(ip, path, country string, status int, bytesSent int64) {
+	r := b.rollup
+	r.Requests++
+	r.Bytes += bytesSent
+	switch status / 100 {
+	case 2:
+		r.Status2xx++
+	case 3:
+		r.Status3xx++
+	case 4:
+		r.Status4xx++
+	case 5:
+		r.Status5xx++
+	default:
+		r.StatusOther++
+	}
+	if ip != "" {
+		b.visitors[ip] = struct{}{}
+	}
+	if path != "" {
+		b.paths[path]++
+	}
+	if country != "" {
+		b.countries[country]++
+	}
+}
+
+// finish completes the rollup, keeping the topPaths most requested paths
+func (b *bucket) finish(topPaths int) *model.NginxLogRollup {
+	b.rollup.UniqueVisitors = int64(len(b.visitors))
+	b.rollup.TopPaths = topCounts(b.paths, topPaths)
+	b.rollup.Countries = topCounts(b.countries, 0)
+	return b.rollup
+}
+
+// topCounts sorts counts by count, then key, and keeps the first limit entries;
+// limit 0 keeps all of them
+func topCounts(counts map[string]int64, limit int) []model.RollupCount {
+	list := make([]model.RollupCount, 0, len(counts))
+	for key, count := range counts {
+		list = append(list, model.RollupCount{Key: key, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// DayStart returns the server-local midnight starting the day of t, the
+// boundary daily rollups and the dashboard's daily stats are aligned to
+func DayStart(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// BuildDay aggregates the raw documents of a log group over the server-local
+// day starting at day into its daily rollup and the rollups of the UTC hours
+// the day overlaps. Those hours are scanned in full even when the day starts
+// mid-hour, so an hourly rollup never depends on which day built it. The
+// daily rollup is returned even without requests, empty hours are left out.
+func BuildDay(ctx context.Context, s searcher.SearcherInterface, mainLogPath string, day time.Time) ([]*model.NginxLogRollup, error) {
+	dayStart := DayStart(day)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	scanStart := dayStart.UTC().Truncate(time.Hour).Unix()
+	scanEnd := dayEnd.UTC().Truncate(time.Hour)
+	if !scanEnd.Equal(dayEnd) {
+		scanEnd = scanEnd.Add(time.Hour)
+	}
+	scanEndUnix := scanEnd.Unix()
+
+	daily := newBucket(mainLogPath, model.RollupGranularityDay, dayStart.Unix())
+	hourly := make(map[int64]*bucket)
+
+	var searchAfter []string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := s.Search(ctx, &searcher.SearchRequest{
+			StartTime:      &scanStart,
+			EndTime:        &scanEndUnix,
+			LogPaths:       []string{mainLogPath},
+			UseMainLogPath: true,
+			Limit:          scanBatchSize,
+			SearchAfter:    searchAfter,
+			SortBy:         "timestamp",
+			SortOrder:      "asc",
+			Fields:         []string{"timestamp", "ip", "path", "status", "bytes_sent", "region_code"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s for rollup: %w", mainLogPath, err)
+		}
+
+		for _, hit := range result.Hits {
+			timestamp, ok := hit.Fields["timestamp"].(float64)
+			if !ok {
+				continue
+			}
+			ts := int64(timestamp)
+			ip, _ := hit.Fields["ip"].(string)
+			path, _ := hit.Fields["path"].(string)
+			country, _ := hit.Fields["region_code"].(string)
+			status, _ := hit.Fields["status"].(float64)
+			bytesSent, _ := hit.Fields["bytes_sent"].(float64)
+
+			hourStart := ts - ts%3600
+			hour, exists := hourly[hourStart]
+			if !exists {
+				hour = newBucket(mainLogPath, model.RollupGranularityHour, hourStart)
+				hourly[hourStart] = hour
+			}
+			hour.add(ip, path, country, int(status), int64(bytesSent))
+
+			if ts >= dayStart.Unix() && ts < dayEnd.Unix() {
+				daily.add(ip, path, country, int(status), int64(bytesSent))
+			}
+		}
+
+		if len(result.Hits) < scanBatchSize {
+			break
+		}
+		lastHit := result.Hits[len(result.Hits)-1]
+		if len(lastHit.Sort) == 0 {
+			return nil, fmt.Errorf("rollup scan of %s cannot continue without sort values", mainLogPath)
+		}
+		searchAfter = lastHit.Sort
+	}
+
+	rollups := make([]*model.NginxLogRollup, 0, len(hourly)+1)
+	rollups = append(rollups, daily.finish(dailyTopPaths))
+	for _, hour := range hourly {
+		rollups = append(rollups, hour.finish(hourlyTopPaths))
+	}
+	sort.Slice(rollups[1:], func(i, j int) bool {
+		return rollups[i+1].BucketStart < rollups[j+1].BucketStart
+	})
+	return rollups, nil
+}
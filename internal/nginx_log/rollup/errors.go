@@ -0,0 +1,9 @@
+package rollup
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                   = cosy.NewErrorScope("log_retention")
+	ErrInvalidRetention = e.New(40001, "retention days must not be negative")
+	ErrLogNotIndexed    = e.New(40401, "the log is not indexed: {0}")
+)
@@ -0,0 +1,185 @@
+package rollup
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// Purger deletes the raw documents of a log group older than a Unix time
+type Purger interface {
+	DeleteDocumentsBefore(mainLogPath string, before int64) (uint64, error)
+}
+
+// group is the retention state of a log group, folded from the index
+// records of its files
+type group struct {
+	mainLogPath    string
+	retentionDays  int
+	purgedBefore   *time.Time
+	timeRangeStart *time.Time
+	timeRangeEnd   *time.Time
+	busy           bool // A file of the group is queued or being indexed
+}
+
+func loadGroups() ([]*group, error) {
+	q := query.NginxLogIndex
+	indexes, err := q.Where(q.Enabled.Is(true)).Order(q.MainLogPath).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*group
+	byPath := make(map[string]*group)
+	for _, index := range indexes {
+		if index.MainLogPath == "" {
+			continue
+		}
+		g, exists := byPath[index.MainLogPath]
+		if !exists {
+			g = &group{mainLogPath: index.MainLogPath}
+			byPath[index.MainLogPath] = g
+			groups = append(groups, g)
+		}
+		g.retentionDays = max(g.retentionDays, index.RetentionDays)
+		g.purgedBefore = latest(g.purgedBefore, index.PurgedBefore)
+		g.timeRangeEnd = latest(g.timeRangeEnd, index.TimeRangeEnd)
+		if index.TimeRangeStart != nil && (g.timeRangeStart == nil || index.TimeRangeStart.Before(*g.timeRangeStart)) {
+			g.timeRangeStart = index.TimeRangeStart
+		}
+		if index.IndexStatus == string(indexer.IndexStatusQueued) || index.IndexStatus == string(indexer.IndexStatusIndexing) {
+			g.busy = true
+		}
+	}
+	return groups, nil
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// Run rolls up the complete days of every log group not rolled up yet, then
+// purges the raw documents older than each group's retention and the rollups
+// older than theirs. Raw documents are only purged once their days are rolled
+// up, so the dashboard never loses a range.
+func Run(ctx context.Context, s searcher.SearcherInterface, purger Purger) error {
+	groups, err := loadGroups()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, g := range groups {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if g.busy {
+			logger.Debugf("Skipping rollup of %s while it is being indexed", g.mainLogPath)
+			continue
+		}
+		if err := rollupGroup(ctx, s, g, now); err != nil {
+			logger.Errorf("Failed to roll up %s: %v", g.mainLogPath, err)
+			continue
+		}
+		if err := purgeGroup(purger, g, now); err != nil {
+			logger.Errorf("Failed to purge expired documents of %s: %v", g.mainLogPath, err)
+		}
+	}
+
+	pruned, err := prune(now, settings.NginxLogSettings.GetHourlyRollupRetention(),
+		settings.NginxLogSettings.GetDailyRollupRetention())
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		logger.Infof("Pruned %d expired traffic rollups", pruned)
+	}
+	return nil
+}
+
+// rollupGroup builds the rollups of the days after the latest rolled up day.
+// A day is complete once the group holds an entry logged after it ended.
+func rollupGroup(ctx context.Context, s searcher.SearcherInterface, g *group, now time.Time) error {
+	if g.timeRangeStart == nil || g.timeRangeEnd == nil {
+		return nil
+	}
+
+	last, err := lastDay(g.mainLogPath)
+	if err != nil {
+		return err
+	}
+
+	day := DayStart(*g.timeRangeStart)
+	if !last.IsZero() {
+		day = DayStart(last).AddDate(0, 0, 1)
+	}
+	// Days older than the daily rollups are kept would be pruned right away
+	if oldest := DayStart(now.Add(-settings.NginxLogSettings.GetDailyRollupRetention())); day.Before(oldest) {
+		day = oldest
+	}
+
+	for ; ; day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		if dayEnd.After(*g.timeRangeEnd) || dayEnd.After(now) {
+			return nil
+		}
+
+		rollups, err := BuildDay(ctx, s, g.mainLogPath, day)
+		if err != nil {
+			return err
+		}
+		if err := Save(rollups); err != nil {
+			return err
+		}
+		logger.Debugf("Rolled up %s for %s: %d requests", g.mainLogPath, day.Format("2006-01-02"), rollups[0].Requests)
+	}
+}
+
+// purgeGroup deletes the raw documents older than the retention of a group,
+// as far as they are rolled up
+func purgeGroup(purger Purger, g *group, now time.Time) error {
+	if g.retentionDays <= 0 {
+		return nil
+	}
+
+	last, err := lastDay(g.mainLogPath)
+	if err != nil || last.IsZero() {
+		return err
+	}
+
+	cutoff := DayStart(now.AddDate(0, 0, -g.retentionDays))
+	if rolledUntil := DayStart(last).AddDate(0, 0, 1); rolledUntil.Before(cutoff) {
+		cutoff = rolledUntil
+	}
+	// A rebuild may have indexed purged documents again, so the purge runs
+	// even when the boundary has not moved
+	if g.purgedBefore != nil && g.purgedBefore.After(cutoff) {
+		cutoff = *g.purgedBefore
+	}
+
+	deleted, err := purger.DeleteDocumentsBefore(g.mainLogPath, cutoff.Unix())
+	if err != nil {
+		return err
+	}
+
+	if g.purgedBefore == nil || cutoff.After(*g.purgedBefore) {
+		q := query.NginxLogIndex
+		_, err = q.Where(q.MainLogPath.Eq(g.mainLogPath)).Update(q.PurgedBefore, cutoff)
+		if err != nil {
+			return err
+		}
+	}
+
+	if deleted > 0 {
+		logger.Infof("Purged %d documents of %s logged before %s", deleted, g.mainLogPath, cutoff.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testLogPath = "/var/log/nginx/access.log"
+
+// fakeSearcher serves docs within the requested time range, paged by a
+// SearchAfter cursor holding the index of the last returned document
+type fakeSearcher struct {
+	searcher.SearcherInterface
+	docs     []map[string]any
+	searches int
+}
+
+func (f *fakeSearcher) Search(_ context.Context, req *searcher.SearchRequest) (*searcher.SearchResult, error) {
+	f.searches++
+	start := 0
+	if len(req.SearchAfter) > 0 {
+		start, _ = strconv.Atoi(req.SearchAfter[0])
+	}
+
+	result := &searcher.SearchResult{}
+	for i := start; i < len(f.docs) && len(result.Hits) < req.Limit; i++ {
+		ts := int64(f.docs[i]["timestamp"].(float64))
+		if ts < *req.StartTime || ts >= *req.EndTime {
+			continue
+		}
+		result.Hits = append(result.Hits, &searcher.SearchHit{Fields: f.docs[i], Sort: []string{strconv.Itoa(i + 1)}})
+	}
+	result.TotalHits = uint64(len(result.Hits))
+	return result, nil
+}
+
+type fakePurger struct {
+	cutoffs []int64
+}
+
+func (f *fakePurger) DeleteDocumentsBefore(mainLogPath string, before int64) (uint64, error) {
+	f.cutoffs = append(f.cutoffs, before)
+	return 1, nil
+}
+
+func doc(t time.Time, ip, path, country string, status int) map[string]any {
+	return map[string]any{
+		"timestamp":   float64(t.Unix()),
+		"ip":          ip,
+		"path":        path,
+		"region_code": country,
+		"status":      float64(status),
+		"bytes_sent":  float64(100),
+	}
+}
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	originalDB := model.UseDB()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.NginxLogIndex{}, &model.NginxLogRollup{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	t.Cleanup(func() { model.Use(originalDB) })
+}
+
+func TestBuildDay(t *testing.T) {
+	day := DayStart(time.Date(2025, 3, 10, 15, 0, 0, 0, time.Local))
+	s := &fakeSearcher{docs: []map[string]any{
+		doc(day.Add(-time.Minute), "10.0.0.9", "/before", "US", 200),
+		doc(day.Add(9*time.Hour), "10.0.0.1", "/", "US", 200),
+		doc(day.Add(9*time.Hour+time.Minute), "10.0.0.1", "/", "US", 304),
+		doc(day.Add(10*time.Hour), "10.0.0.2", "/login", "DE", 404),
+		doc(day.Add(23*time.Hour), "10.0.0.3", "/", "", 502),
+		doc(day.AddDate(0, 0, 1).Add(2*time.Hour), "10.0.0.4", "/after", "US", 200),
+	}}
+
+	rollups, err := BuildDay(context.Background(), s, testLogPath, day.Add(5*time.Hour))
+	require.NoError(t, err)
+
+	daily := rollups[0]
+	assert.Equal(t, model.RollupGranularityDay, daily.Granularity)
+	assert.Equal(t, day.Unix(), daily.BucketStart)
+	assert.Equal(t, int64(4), daily.Requests)
+	assert.Equal(t, int64(400), daily.Bytes)
+	assert.Equal(t, int64(1), daily.Status2xx)
+	assert.Equal(t, int64(1), daily.Status3xx)
+	assert.Equal(t, int64(1), daily.Status4xx)
+	assert.Equal(t, int64(1), daily.Status5xx)
+	assert.Equal(t, int64(3), daily.UniqueVisitors)
+	assert.Equal(t, []model.RollupCount{{Key: "/", Count: 3}, {Key: "/login", Count: 1}}, daily.TopPaths)
+	assert.Equal(t, []model.RollupCount{{Key: "US", Count: 2}, {Key: "DE", Count: 1}}, daily.Countries)
+
+	var hourlyRequests int64
+	for i, r := range rollups[1:] {
+		assert.Equal(t, model.RollupGranularityHour, r.Granularity)
+		assert.Zero(t, r.BucketStart%3600)
+		if i > 0 {
+			assert.Greater(t, r.BucketStart, rollups[i].BucketStart)
+		}
+		hourlyRequests += r.Requests
+	}
+	assert.Equal(t, daily.Requests, hourlyRequests)
+}
+
+func TestRunRollsUpBeforePurging(t *testing.T) {
+	setupDB(t)
+
+	now := time.Now()
+	today := DayStart(now)
+	s := &fakeSearcher{}
+	for days := 3; days >= 0; days-- {
+		noon := today.AddDate(0, 0, -days).Add(12 * time.Hour)
+		if noon.After(now) {
+			noon = today
+		}
+		s.docs = append(s.docs, doc(noon, "10.0.0."+strconv.Itoa(days), "/", "US", 200))
+	}
+	start, end := today.AddDate(0, 0, -3).Add(12*time.Hour), now
+	require.NoError(t, model.UseDB().Create(&model.NginxLogIndex{
+		Path:           testLogPath,
+		MainLogPath:    testLogPath,
+		Enabled:        true,
+		IndexStatus:    "indexed",
+		TimeRangeStart: &start,
+		TimeRangeEnd:   &end,
+	}).Error)
+	require.NoError(t, SetRetention(testLogPath, 1))
+
+	purger := &fakePurger{}
+	require.NoError(t, Run(context.Background(), s, purger))
+
+	daily, err := Find(testLogPath, model.RollupGranularityDay, 0, now.Unix())
+	require.NoError(t, err)
+	require.Len(t, daily, 3)
+	assert.Equal(t, today.AddDate(0, 0, -1).Unix(), daily[2].BucketStart)
+	for _, r := range daily {
+		assert.Equal(t, int64(1), r.Requests)
+	}
+
+	cutoff := DayStart(now.AddDate(0, 0, -1)).Unix()
+	assert.Equal(t, []int64{cutoff}, purger.cutoffs)
+	assert.Equal(t, cutoff, PurgedBefore(testLogPath))
+
+	// Rolled up days are not scanned again, the purge still runs
+	searches := s.searches
+	require.NoError(t, Run(context.Background(), s, purger))
+	assert.Equal(t, searches, s.searches)
+	assert.Equal(t, []int64{cutoff, cutoff}, purger.cutoffs)
+}
+
+func TestRunKeepsDocumentsWithoutRetention(t *testing.T) {
+	setupDB(t)
+
+	start, end := time.Now().AddDate(0, 0, -2), time.Now()
+	require.NoError(t, model.UseDB().Create(&model.NginxLogIndex{
+		Path:           testLogPath,
+		MainLogPath:    testLogPath,
+		Enabled:        true,
+		TimeRangeStart: &start,
+		TimeRangeEnd:   &end,
+	}).Error)
+
+	purger := &fakePurger{}
+	require.NoError(t, Run(context.Background(), &fakeSearcher{}, purger))
+	assert.Empty(t, purger.cutoffs)
+	assert.Zero(t, PurgedBefore(testLogPath))
+
+	var cErr *cosy.Error
+	require.ErrorAs(t, SetRetention(testLogPath, -1), &cErr)
+	assert.Equal(t, ErrInvalidRetention.(*cosy.Error).Code, cErr.Code)
+	require.ErrorAs(t, SetRetention("/var/log/nginx/other.log", 7), &cErr)
+	assert.Equal(t, ErrLogNotIndexed.(*cosy.Error).Code, cErr.Code)
+}
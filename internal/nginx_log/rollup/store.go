@@ -0,0 +1,103 @@
+package rollup
+
+import (
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm/clause"
+)
+
+// Save stores rollups, replacing those of the same log group and bucket
+func Save(rollups []*model.NginxLogRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	return query.NginxLogRollup.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "main_log_path"}, {Name: "granularity"}, {Name: "bucket_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"requests", "bytes", "status_2xx", "status_3xx", "status_4xx", "status_5xx", "status_other",
+			"unique_visitors", "top_paths", "countries", "updated_at",
+		}),
+	}).Create(rollups...)
+}
+
+// Find returns the rollups of a log group with a bucket starting in
+// [start, end), ordered by bucket start
+func Find(mainLogPath, granularity string, start, end int64) ([]*model.NginxLogRollup, error) {
+	if !query.Q.Available() {
+		return nil, nil
+	}
+
+	q := query.NginxLogRollup
+	return q.Where(
+		q.MainLogPath.Eq(mainLogPath),
+		q.Granularity.Eq(granularity),
+		q.BucketStart.Gte(start),
+		q.BucketStart.Lt(end),
+	).Order(q.BucketStart).Find()
+}
+
+// lastDay returns the start of the latest daily rollup of a log group, the
+// zero time if it has none
+func lastDay(mainLogPath string) (time.Time, error) {
+	q := query.NginxLogRollup
+	rollups, err := q.Where(q.MainLogPath.Eq(mainLogPath), q.Granularity.Eq(model.RollupGranularityDay)).
+		Order(q.BucketStart.Desc()).
+		Limit(1).
+		Find()
+	if err != nil || len(rollups) == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(rollups[0].BucketStart, 0), nil
+}
+
+// prune deletes the rollups older than their granularity's retention
+func prune(now time.Time, hourlyRetention, dailyRetention time.Duration) (int64, error) {
+	q := query.NginxLogRollup
+	hourly, err := q.Where(q.Granularity.Eq(model.RollupGranularityHour), q.BucketStart.Lt(now.Add(-hourlyRetention).Unix())).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+	daily, err := q.Where(q.Granularity.Eq(model.RollupGranularityDay), q.BucketStart.Lt(now.Add(-dailyRetention).Unix())).
+		Delete()
+	return hourly.RowsAffected + daily.RowsAffected, err
+}
+
+// PurgedBefore returns the Unix time before which the raw documents of a log
+// group were purged by its retention policy, 0 if none were
+func PurgedBefore(mainLogPath string) int64 {
+	if !query.Q.Available() || mainLogPath == "" {
+		return 0
+	}
+
+	q := query.NginxLogIndex
+	indexes, err := q.Where(q.MainLogPath.Eq(mainLogPath), q.PurgedBefore.IsNotNull()).
+		Order(q.PurgedBefore.Desc()).
+		Limit(1).
+		Find()
+	if err != nil || len(indexes) == 0 || indexes[0].PurgedBefore == nil {
+		return 0
+	}
+	return indexes[0].PurgedBefore.Unix()
+}
+
+// SetRetention sets how many days the raw documents of a log group are kept,
+// 0 keeps them forever
+func SetRetention(mainLogPath string, days int) error {
+	if days < 0 {
+		return ErrInvalidRetention
+	}
+
+	q := query.NginxLogIndex
+	result, err := q.Where(q.MainLogPath.Eq(mainLogPath)).Update(q.RetentionDays, days)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return cosy.WrapErrorWithParams(ErrLogNotIndexed, mainLogPath)
+	}
+	return nil
+}
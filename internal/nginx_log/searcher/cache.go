@@ -39,6 +39,7 @@ func NewCache(maxSize int64) *Cache {
 // CacheKeyData represents the normalized data used for cache key generation
 type CacheKeyData struct {
 	Query          string   `json:"query"`
+	Expression     string   `json:"expression"`
 	Limit          int      `json:"limit"`
 	Offset         int      `json:"offset"`
 	SearchAfter    []string `json:"search_after"`
@@ -68,6 +69,9 @@ type CacheKeyData struct {
 	FacetFields    []string `json:"facet_fields"`
 	FacetSize      int      `json:"facet_size"`
 	UseCache       bool     `json:"use_cache"`
+
+	// Custom log_format variable filters
+	Variables map[string][]string `json:"variables"`
 }
 
 // sortedUniqueStrings returns a sorted, deduplicated copy of a string slice
@@ -87,6 +91,19 @@ func sortedUniqueStrings(src []string) []string {
 	return res
 }
 
+// sortedVariables returns a copy of variable filters with sorted, deduplicated
+// values; JSON encoding already orders the map keys
+func sortedVariables(src map[string][]string) map[string][]string {
+	if len(src) == 0 {
+		return nil
+	}
+	res := make(map[string][]string, len(src))
+	for name, values := range src {
+		res[name] = sortedUniqueStrings(values)
+	}
+	return res
+}
+
 // sortedStrings returns a sorted copy of a string slice
 func sortedStrings(src []string) []string {
 	if len(src) == 0 {
@@ -113,6 +130,7 @@ func sortedInts(src []int) []int {
 func (c *Cache) GenerateKey(req *SearchRequest) string {
 	keyData := CacheKeyData{
 		Query:          req.Query,
+		Expression:     req.Expression,
 		Limit:          req.Limit,
 		Offset:         req.Offset,
 		SearchAfter:    req.SearchAfter,
@@ -142,6 +160,7 @@ func (c *Cache) GenerateKey(req *SearchRequest) string {
 		FacetFields:    sortedUniqueStrings(req.FacetFields),
 		FacetSize:      req.FacetSize,
 		UseCache:       req.UseCache,
+		Variables:      sortedVariables(req.Variables),
 	}
 
 	jsonData, err := json.Marshal(keyData)
@@ -155,9 +174,11 @@ func (c *Cache) GenerateKey(req *SearchRequest) string {
 
 // generateFallbackKey creates a basic cache key when JSON marshaling fails
 func (c *Cache) generateFallbackKey(req *SearchRequest) string {
-	keyBuf := make([]byte, 0, len(req.Query)+len(req.SortBy)+len(req.SortOrder)+32)
+	keyBuf := make([]byte, 0, len(req.Query)+len(req.Expression)+len(req.SortBy)+len(req.SortOrder)+32)
 	keyBuf = append(keyBuf, "q:"...)
 	keyBuf = append(keyBuf, req.Query...)
+	keyBuf = append(keyBuf, "|e:"...)
+	keyBuf = append(keyBuf, req.Expression...)
 	keyBuf = append(keyBuf, "|l:"...)
 	keyBuf = utils.AppendInt(keyBuf, req.Limit)
 	keyBuf = append(keyBuf, "|o:"...)
@@ -0,0 +1,317 @@
+package searcher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Error log index fields usable as facets
+const (
+	ErrorFieldLevel          = "level"
+	ErrorFieldClient         = "client"
+	ErrorFieldServer         = "server"
+	ErrorFieldUpstream       = "upstream"
+	ErrorFieldHost           = "host"
+	ErrorFieldMessagePattern = "message_pattern"
+)
+
+// DefaultTopMessagesSize is the number of message clusters returned by default
+const DefaultTopMessagesSize = 10
+
+// ErrorSearchRequest represents a search query on the error log index
+type ErrorSearchRequest struct {
+	// Query is matched against the message
+	Query string `json:"query,omitempty"`
+
+	// Filters; values of one filter are ORed. LogPaths are main log paths.
+	LogPaths        []string `json:"log_paths,omitempty"`
+	StartTime       *int64   `json:"start_time,omitempty"`
+	EndTime         *int64   `json:"end_time,omitempty"`
+	Levels          []string `json:"levels,omitempty"`
+	Clients         []string `json:"clients,omitempty"`
+	Servers         []string `json:"servers,omitempty"`
+	Upstreams       []string `json:"upstreams,omitempty"`
+	Hosts           []string `json:"hosts,omitempty"`
+	MessagePatterns []string `json:"message_patterns,omitempty"`
+
+	// Pagination and sorting by timestamp
+	Limit     int    `json:"limit"`
+	Offset    int    `json:"offset"`
+	SortOrder string `json:"sort_order,omitempty"`
+
+	// Facets on keyword fields such as level, client or upstream
+	FacetFields []string `json:"facet_fields,omitempty"`
+	FacetSize   int      `json:"facet_size,omitempty"`
+
+	// TimelineInterval, in seconds, additionally counts the matches between
+	// StartTime and EndTime per interval.
+	TimelineInterval int64 `json:"timeline_interval,omitempty"`
+}
+
+// ErrorSearchResult represents the result of an error log search
+type ErrorSearchResult struct {
+	*SearchResult
+	Timeline []*TimeBucket `json:"timeline,omitempty"`
+}
+
+// TimeBucket is the number of matches in [Start, Start+interval)
+type TimeBucket struct {
+	Start int64 `json:"start"`
+	Count int   `json:"count"`
+}
+
+// MessageCluster groups error lines whose messages only differ in ids,
+// addresses or numbers
+type MessageCluster struct {
+	Pattern   string `json:"pattern"`
+	Count     int    `json:"count"`
+	Sample    string `json:"sample"`
+	SampleRaw string `json:"sample_raw"`
+	Level     string `json:"level"`
+	LastSeen  int64  `json:"last_seen"`
+}
+
+// ErrorSearcher searches the error log index
+type ErrorSearcher struct {
+	index        bleve.Index
+	config       *Config
+	queryBuilder *QueryBuilder
+}
+
+// NewErrorSearcher creates a searcher over the error log index
+func NewErrorSearcher(index bleve.Index, config *Config) *ErrorSearcher {
+	if config == nil {
+		config = DefaultSearcherConfig()
+	}
+	return &ErrorSearcher{
+		index:        index,
+		config:       config,
+		queryBuilder: NewQueryBuilder(),
+	}
+}
+
+// BuildErrorQuery builds a Bleve query from an ErrorSearchRequest
+func (qb *QueryBuilder) BuildErrorQuery(req *ErrorSearchRequest) (query.Query, error) {
+	if req == nil {
+		return nil, fmt.Errorf("search request cannot be nil")
+	}
+
+	boolQuery := bleve.NewBooleanQuery()
+	if req.Query == "" {
+		boolQuery.AddMust(bleve.NewMatchAllQuery())
+	} else {
+		matchQuery := bleve.NewMatchQuery(req.Query)
+		matchQuery.SetField("message")
+		boolQuery.AddMust(matchQuery)
+	}
+
+	if timeQuery := qb.buildTimeRangeQuery(req.StartTime, req.EndTime); timeQuery != nil {
+		boolQuery.AddMust(timeQuery)
+	}
+
+	filters := []struct {
+		field  string
+		values []string
+	}{
+		{"main_log_path", req.LogPaths},
+		{ErrorFieldLevel, req.Levels},
+		{ErrorFieldClient, req.Clients},
+		{ErrorFieldServer, req.Servers},
+		{ErrorFieldUpstream, req.Upstreams},
+		{ErrorFieldHost, req.Hosts},
+		{ErrorFieldMessagePattern, req.MessagePatterns},
+	}
+	for _, filter := range filters {
+		if termsQuery := qb.buildTermsQuery(filter.field, filter.values); termsQuery != nil {
+			boolQuery.AddMust(termsQuery)
+		}
+	}
+
+	return boolQuery, nil
+}
+
+// Search runs an error log search
+func (es *ErrorSearcher) Search(ctx context.Context, req *ErrorSearchRequest) (*ErrorSearchResult, error) {
+	if es == nil || es.index == nil {
+		return nil, fmt.Errorf("error log index is not available")
+	}
+
+	q, err := es.queryBuilder.BuildErrorQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = es.config.DefaultLimit
+	}
+	if limit > es.config.MaxLimit {
+		limit = es.config.MaxLimit
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	searchReq := bleve.NewSearchRequestOptions(q, limit, req.Offset, false)
+	searchReq.Fields = []string{"*"}
+	if req.SortOrder == SortOrderAsc {
+		searchReq.SortBy([]string{"timestamp", "_id"})
+	} else {
+		searchReq.SortBy([]string{"-timestamp", "_id"})
+	}
+
+	facetSize := req.FacetSize
+	if facetSize <= 0 {
+		facetSize = DefaultFacetSize
+	}
+	for _, field := range req.FacetFields {
+		searchReq.AddFacet(field, bleve.NewFacetRequest(field, facetSize))
+	}
+
+	buckets := timelineBuckets(req.StartTime, req.EndTime, req.TimelineInterval)
+	if len(buckets) > 0 {
+		timelineFacet := bleve.NewFacetRequest("timestamp", len(buckets))
+		for _, start := range buckets {
+			min := float64(start)
+			max := float64(start + req.TimelineInterval)
+			timelineFacet.AddNumericRange(strconv.FormatInt(start, 10), &min, &max)
+		}
+		searchReq.AddFacet(timelineFacetName, timelineFacet)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, es.config.TimeoutDuration)
+	defer cancel()
+
+	bleveResult, err := es.index.SearchInContext(ctx, searchReq)
+	if err != nil {
+		return nil, fmt.Errorf("error log search failed: %w", err)
+	}
+
+	var timelineCounts map[string]int
+	if facet, ok := bleveResult.Facets[timelineFacetName]; ok {
+		timelineCounts = make(map[string]int, len(facet.NumericRanges))
+		for _, nr := range facet.NumericRanges {
+			timelineCounts[nr.Name] = nr.Count
+		}
+		delete(bleveResult.Facets, timelineFacetName)
+	}
+
+	result := &ErrorSearchResult{SearchResult: convertBleveSearchResult(bleveResult)}
+	result.Duration = bleveResult.Took
+	if len(buckets) > 0 {
+		result.Timeline = make([]*TimeBucket, 0, len(buckets))
+		for _, start := range buckets {
+			result.Timeline = append(result.Timeline, &TimeBucket{
+				Start: start,
+				Count: timelineCounts[strconv.FormatInt(start, 10)],
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// TopMessages clusters the matching error lines by message pattern and
+// returns the size largest clusters, each with its most recent line as sample.
+func (es *ErrorSearcher) TopMessages(ctx context.Context, req *ErrorSearchRequest, size int) ([]*MessageCluster, error) {
+	if size <= 0 {
+		size = DefaultTopMessagesSize
+	}
+
+	facetReq := *req
+	facetReq.Limit = -1
+	facetReq.Offset = 0
+	facetReq.FacetFields = []string{ErrorFieldMessagePattern}
+	facetReq.FacetSize = size
+	facetReq.TimelineInterval = 0
+
+	result, err := es.Search(ctx, &facetReq)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*MessageCluster, 0, size)
+	facet, ok := result.Facets[ErrorFieldMessagePattern]
+	if !ok {
+		return clusters, nil
+	}
+
+	for _, term := range facet.Terms {
+		cluster := &MessageCluster{Pattern: term.Term, Count: term.Count}
+
+		sampleReq := *req
+		sampleReq.MessagePatterns = []string{term.Term}
+		sampleReq.Limit = 1
+		sampleReq.Offset = 0
+		sampleReq.SortOrder = SortOrderDesc
+		sampleReq.FacetFields = nil
+		sampleReq.TimelineInterval = 0
+
+		sample, err := es.Search(ctx, &sampleReq)
+		if err != nil {
+			return nil, err
+		}
+		if len(sample.Hits) > 0 {
+			fields := sample.Hits[0].Fields
+			cluster.Sample, _ = fields["message"].(string)
+			cluster.SampleRaw, _ = fields["raw"].(string)
+			cluster.Level, _ = fields["level"].(string)
+			if ts, ok := fields["timestamp"].(float64); ok {
+				cluster.LastSeen = int64(ts)
+			}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+// timelineFacetName names the internal numeric range facet of the timeline
+const timelineFacetName = "__timeline"
+
+// maxTimelineBuckets bounds the number of numeric ranges of one timeline
+const maxTimelineBuckets = 1000
+
+// timelineBuckets returns the bucket starts covering [start, end], aligned to
+// the interval, or nil when no timeline was requested.
+func timelineBuckets(start, end *int64, interval int64) []int64 {
+	if start == nil || end == nil || interval <= 0 || *end < *start {
+		return nil
+	}
+	if (*end-*start)/interval+1 > maxTimelineBuckets {
+		return nil
+	}
+
+	first := *start - *start%interval
+	buckets := make([]int64, 0, (*end-first)/interval+1)
+	for bucket := first; bucket <= *end; bucket += interval {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// timelineIntervals are the bucket sizes picked by AutoTimelineInterval
+var timelineIntervals = []time.Duration{
+	time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// AutoTimelineInterval picks a bucket size, in seconds, yielding at most about
+// 120 buckets over the given range.
+func AutoTimelineInterval(start, end int64) int64 {
+	span := time.Duration(end-start) * time.Second
+	for _, interval := range timelineIntervals {
+		if span/interval <= 120 {
+			return int64(interval / time.Second)
+		}
+	}
+	// Beyond that, use whole days.
+	day := timelineIntervals[len(timelineIntervals)-1]
+	return int64((span/120).Truncate(day)/time.Second) + int64(day/time.Second)
+}
@@ -0,0 +1,108 @@
+package searcher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/indexer"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/parser"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newErrorTestSearcher indexes error log documents into an in-memory index
+// using the production error log mapping.
+func newErrorTestSearcher(t *testing.T) *ErrorSearcher {
+	t.Helper()
+
+	index, err := bleve.NewMemOnly(indexer.CreateErrorLogIndexMapping())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = index.Close() })
+
+	const base = int64(1_699_999_980) // aligned to the minute
+	docs := []indexer.ErrorLogDocument{
+		{Timestamp: base, Level: "error", Message: "connect() failed (111: Connection refused) while connecting to upstream", Client: "192.0.2.1", Upstream: "http://10.0.0.1:8080/"},
+		{Timestamp: base + 10, Level: "error", Message: "connect() failed (111: Connection refused) while connecting to upstream", Client: "192.0.2.2", Upstream: "http://10.0.0.2:8080/"},
+		{Timestamp: base + 70, Level: "error", Message: "connect() failed (111: Connection refused) while connecting to upstream", Client: "192.0.2.1", Upstream: "http://10.0.0.1:8080/"},
+		{Timestamp: base + 80, Level: "warn", Message: "an upstream response is buffered to a temporary file /var/cache/nginx/1/00/0000000001", Client: "192.0.2.3"},
+		{Timestamp: base + 130, Level: "crit", Message: "SSL_do_handshake() failed while SSL handshaking", Client: "192.0.2.4"},
+	}
+	for i, doc := range docs {
+		doc.MessagePattern = parser.MessagePattern(doc.Message)
+		doc.Raw = doc.Message
+		doc.MainLogPath = "/var/log/nginx/error.log"
+		doc.FilePath = doc.MainLogPath
+		require.NoError(t, index.Index(fmt.Sprintf("doc%d", i), doc))
+	}
+
+	return NewErrorSearcher(index, nil)
+}
+
+func TestErrorSearcherFilters(t *testing.T) {
+	es := newErrorTestSearcher(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  *ErrorSearchRequest
+		want uint64
+	}{
+		{"all", &ErrorSearchRequest{}, 5},
+		{"level", &ErrorSearchRequest{Levels: []string{"error"}}, 3},
+		{"levels are ORed", &ErrorSearchRequest{Levels: []string{"warn", "crit"}}, 2},
+		{"client", &ErrorSearchRequest{Clients: []string{"192.0.2.1"}}, 2},
+		{"upstream", &ErrorSearchRequest{Upstreams: []string{"http://10.0.0.2:8080/"}}, 1},
+		{"message query", &ErrorSearchRequest{Query: "handshaking"}, 1},
+		{"log path", &ErrorSearchRequest{LogPaths: []string{"/var/log/nginx/other.log"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := es.Search(ctx, tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.TotalHits)
+		})
+	}
+}
+
+func TestErrorSearcherTimeline(t *testing.T) {
+	es := newErrorTestSearcher(t)
+
+	start, end := int64(1_699_999_980), int64(1_699_999_980+179)
+	result, err := es.Search(context.Background(), &ErrorSearchRequest{
+		StartTime:        &start,
+		EndTime:          &end,
+		Limit:            -1,
+		TimelineInterval: 60,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Hits)
+	require.Len(t, result.Timeline, 3)
+	assert.Equal(t, []int{2, 2, 1}, []int{result.Timeline[0].Count, result.Timeline[1].Count, result.Timeline[2].Count})
+	assert.Equal(t, start+60, result.Timeline[1].Start)
+	assert.NotContains(t, result.Facets, timelineFacetName)
+}
+
+func TestErrorSearcherTopMessages(t *testing.T) {
+	es := newErrorTestSearcher(t)
+
+	clusters, err := es.TopMessages(context.Background(), &ErrorSearchRequest{}, 2)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, "connect() failed (<n>: Connection refused) while connecting to upstream", clusters[0].Pattern)
+	assert.Equal(t, 3, clusters[0].Count)
+	assert.Equal(t, "error", clusters[0].Level)
+	assert.Equal(t, int64(1_700_000_050), clusters[0].LastSeen)
+	assert.Contains(t, clusters[0].Sample, "Connection refused")
+	assert.Equal(t, 1, clusters[1].Count)
+}
+
+func TestAutoTimelineInterval(t *testing.T) {
+	assert.Equal(t, int64(60), AutoTimelineInterval(0, 3600))
+	assert.Equal(t, int64(900), AutoTimelineInterval(0, 24*3600))
+	assert.Equal(t, int64(6*3600), AutoTimelineInterval(0, 30*24*3600))
+	assert.Equal(t, int64(2*24*3600), AutoTimelineInterval(0, 200*24*3600))
+}
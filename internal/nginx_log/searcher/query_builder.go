@@ -2,11 +2,26 @@ package searcher
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/search/query"
 )
 
+// VarFieldPrefix prefixes the index fields of custom log_format variables
+const VarFieldPrefix = "vars."
+
+// variableNameRegex matches valid log_format variable names
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// VarField returns the index field of a custom log_format variable, usable
+// for filtering and as a facet field.
+func VarField(name string) string {
+	return VarFieldPrefix + name
+}
+
 // QueryBuilder provides high-level query building functionality
 type QueryBuilder struct {
 	defaultAnalyzer string
@@ -38,6 +53,15 @@ func (qb *QueryBuilder) BuildQuery(req *SearchRequest) (query.Query, error) {
 	boolQuery := bleve.NewBooleanQuery()
 	boolQuery.AddMust(mainQuery)
 
+	// Add the query language expression
+	if strings.TrimSpace(req.Expression) != "" {
+		expressionQuery, err := ParseQueryExpression(req.Expression)
+		if err != nil {
+			return nil, err
+		}
+		boolQuery.AddMust(expressionQuery)
+	}
+
 	// Add time range filters
 	if req.StartTime != nil || req.EndTime != nil {
 		if timeQuery := qb.buildTimeRangeQuery(req.StartTime, req.EndTime); timeQuery != nil {
@@ -128,6 +152,21 @@ func (qb *QueryBuilder) BuildQuery(req *SearchRequest) (query.Query, error) {
 		}
 	}
 
+	// Add custom log_format variable filters, in name order for stable queries
+	names := make([]string, 0, len(req.Variables))
+	for name := range req.Variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !variableNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name: %s", name)
+		}
+		if varQuery := qb.buildTermsQuery(VarField(name), req.Variables[name]); varQuery != nil {
+			boolQuery.AddMust(varQuery)
+		}
+	}
+
 	// Add bytes-sent range filter
 	if req.MinBytes != nil || req.MaxBytes != nil {
 		if bytesQuery := qb.buildNumericRangeQuery("bytes_sent", toFloatPtr(req.MinBytes), toFloatPtr(req.MaxBytes)); bytesQuery != nil {
@@ -289,5 +328,11 @@ func (qb *QueryBuilder) ValidateSearchRequest(req *SearchRequest) error {
 		return fmt.Errorf("invalid sort order: %s", req.SortOrder)
 	}
 
+	for name := range req.Variables {
+		if !variableNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid variable name: %s", name)
+		}
+	}
+
 	return nil
 }
@@ -89,3 +89,46 @@ func TestBuildQueryWithoutRangeFilters(t *testing.T) {
 		t.Error("request_time filter should not be present when no range is requested")
 	}
 }
+
+func TestBuildQueryVariableFilters(t *testing.T) {
+	qb := NewQueryBuilder()
+
+	q, err := qb.BuildQuery(&SearchRequest{
+		Variables: map[string][]string{
+			"upstream_cache_status": {"HIT"},
+			"host":                  {"a.example.com", "b.example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v", err)
+	}
+
+	fields := make(map[string]query.Query)
+	collectFieldQueries(t, q, fields)
+
+	cacheQuery, ok := fields["vars.upstream_cache_status"].(*query.TermQuery)
+	if !ok {
+		t.Fatal("expected a term query on vars.upstream_cache_status")
+	}
+	if cacheQuery.Term != "HIT" {
+		t.Errorf("vars.upstream_cache_status term = %q, want HIT", cacheQuery.Term)
+	}
+
+	// Multiple values of one variable are ORed in a nested boolean query,
+	// which collectFieldQueries does not descend into.
+	if _, exists := fields["vars.host"]; exists {
+		t.Error("expected vars.host values to be combined with OR")
+	}
+}
+
+func TestBuildQueryRejectsInvalidVariableName(t *testing.T) {
+	qb := NewQueryBuilder()
+
+	req := &SearchRequest{Variables: map[string][]string{"host OR *": {"x"}}}
+	if _, err := qb.BuildQuery(req); err == nil {
+		t.Error("expected BuildQuery() to reject an invalid variable name")
+	}
+	if err := qb.ValidateSearchRequest(req); err == nil {
+		t.Error("expected ValidateSearchRequest() to reject an invalid variable name")
+	}
+}
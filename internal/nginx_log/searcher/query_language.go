@@ -0,0 +1,585 @@
+package searcher
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Query expressions combine field clauses with AND, OR, NOT and parentheses,
+// for example:
+//
+//	status:>=500 AND path:/api/* AND NOT ip:10.0.0.0/8 AND request_time:>1.5
+//
+// Adjacent clauses without an operator are ANDed and a leading "-" negates a
+// clause. Numeric fields accept N, >N, >=N, <N, <=N and N..M, and status also
+// accepts classes such as 5xx. Unquoted values may use the * and ? wildcards,
+// quoted values are matched literally. ip accepts CIDR prefixes. A clause
+// without a field searches the raw log line.
+const (
+	maxQueryExpressionLength = 4096
+	maxQueryExpressionDepth  = 32
+)
+
+type queryFieldKind int
+
+const (
+	queryFieldKeyword queryFieldKind = iota
+	queryFieldText
+	queryFieldNumeric
+	queryFieldIP
+)
+
+type queryField struct {
+	name      string
+	kind      queryFieldKind
+	upperCase bool
+}
+
+// queryFields maps the field names of the query language, including aliases,
+// onto index fields
+var queryFields = map[string]queryField{
+	"ip":              {name: "ip", kind: queryFieldIP},
+	"status":          {name: "status", kind: queryFieldNumeric},
+	"method":          {name: "method", kind: queryFieldKeyword, upperCase: true},
+	"path":            {name: "path_exact", kind: queryFieldKeyword},
+	"referer":         {name: "referer", kind: queryFieldText},
+	"referrer":        {name: "referer", kind: queryFieldText},
+	"user_agent":      {name: "user_agent", kind: queryFieldText},
+	"ua":              {name: "user_agent", kind: queryFieldText},
+	"browser":         {name: "browser", kind: queryFieldKeyword},
+	"browser_version": {name: "browser_version", kind: queryFieldKeyword},
+	"os":              {name: "os", kind: queryFieldKeyword},
+	"os_version":      {name: "os_version", kind: queryFieldKeyword},
+	"device":          {name: "device_type", kind: queryFieldKeyword},
+	"device_type":     {name: "device_type", kind: queryFieldKeyword},
+	"country":         {name: "region_code", kind: queryFieldKeyword, upperCase: true},
+	"region_code":     {name: "region_code", kind: queryFieldKeyword, upperCase: true},
+	"province":        {name: "province", kind: queryFieldKeyword},
+	"city":            {name: "city", kind: queryFieldKeyword},
+	"bytes":           {name: "bytes_sent", kind: queryFieldNumeric},
+	"bytes_sent":      {name: "bytes_sent", kind: queryFieldNumeric},
+	"request_time":    {name: "request_time", kind: queryFieldNumeric},
+	"upstream_time":   {name: "upstream_time", kind: queryFieldNumeric},
+	"timestamp":       {name: "timestamp", kind: queryFieldNumeric},
+	"file":            {name: "file_path", kind: queryFieldKeyword},
+	"file_path":       {name: "file_path", kind: queryFieldKeyword},
+}
+
+// QuerySyntaxError reports an invalid query expression
+type QuerySyntaxError struct {
+	Pos     int // Byte offset in the expression
+	Message string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos+1)
+}
+
+type queryTokenKind int
+
+const (
+	tokenClause queryTokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenEOF
+)
+
+type queryToken struct {
+	kind   queryTokenKind
+	pos    int
+	field  string
+	value  string
+	quoted bool
+}
+
+// ParseQueryExpression compiles a query expression into a bleve query
+func ParseQueryExpression(expr string) (query.Query, error) {
+	if len(expr) > maxQueryExpressionLength {
+		return nil, &QuerySyntaxError{Pos: maxQueryExpressionLength, Message: "expression is too long"}
+	}
+
+	tokens, err := tokenizeQueryExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return bleve.NewMatchAllQuery(), nil
+	}
+
+	q, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &QuerySyntaxError{Pos: tok.pos, Message: "unexpected " + tok.describe()}
+	}
+	return q, nil
+}
+
+func (t queryToken) describe() string {
+	switch t.kind {
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	case tokenLParen:
+		return `"("`
+	case tokenRParen:
+		return `")"`
+	case tokenEOF:
+		return "end of expression"
+	}
+	return strconv.Quote(t.value)
+}
+
+// tokenizeQueryExpression splits an expression into operators, parentheses
+// and field:value clauses. A backslash escapes the next character of an
+// unquoted value.
+func tokenizeQueryExpression(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, pos: i})
+			i++
+		case c == '"':
+			value, next, err := readQuotedValue(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenClause, pos: i, value: value, quoted: true})
+			i = next
+		case c == '-' && i+1 < len(expr) && !isQueryDelimiter(expr[i+1]):
+			tokens = append(tokens, queryToken{kind: tokenNot, pos: i})
+			i++
+		default:
+			start := i
+			var word strings.Builder
+			colon := -1
+			for i < len(expr) && !isQueryDelimiter(expr[i]) {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					word.WriteByte(expr[i+1])
+					i += 2
+					continue
+				}
+				if expr[i] == ':' && colon < 0 {
+					colon = word.Len()
+				}
+				word.WriteByte(expr[i])
+				i++
+			}
+
+			text := word.String()
+			if colon < 0 {
+				switch text {
+				case "AND", "&&":
+					tokens = append(tokens, queryToken{kind: tokenAnd, pos: start})
+				case "OR", "||":
+					tokens = append(tokens, queryToken{kind: tokenOr, pos: start})
+				case "NOT":
+					tokens = append(tokens, queryToken{kind: tokenNot, pos: start})
+				default:
+					tokens = append(tokens, queryToken{kind: tokenClause, pos: start, value: text})
+				}
+				continue
+			}
+
+			tok := queryToken{kind: tokenClause, pos: start, field: text[:colon], value: text[colon+1:]}
+			if tok.value == "" && i < len(expr) && expr[i] == '"' {
+				value, next, err := readQuotedValue(expr, i)
+				if err != nil {
+					return nil, err
+				}
+				tok.value, tok.quoted = value, true
+				i = next
+			}
+			if tok.field == "" {
+				return nil, &QuerySyntaxError{Pos: start, Message: "missing field name"}
+			}
+			if tok.value == "" && !tok.quoted {
+				return nil, &QuerySyntaxError{Pos: start, Message: "missing value for field " + strconv.Quote(tok.field)}
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+func isQueryDelimiter(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')' || c == '"'
+}
+
+// readQuotedValue reads the double quoted string starting at expr[start]
+func readQuotedValue(expr string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 < len(expr) {
+				i++
+				value.WriteByte(expr[i])
+			}
+		case '"':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(expr[i])
+		}
+	}
+	return "", 0, &QuerySyntaxError{Pos: start, Message: "unterminated quoted string"}
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr(depth int) (query.Query, error) {
+	first, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []query.Query{first}
+	for p.peek().kind == tokenOr {
+		p.next()
+		q, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+
+	if len(queries) == 1 {
+		return first, nil
+	}
+	return bleve.NewDisjunctionQuery(queries...), nil
+}
+
+func (p *queryParser) parseAnd(depth int) (query.Query, error) {
+	first, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := []query.Query{first}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenClause, tokenNot, tokenLParen:
+			// Adjacent clauses are ANDed
+		default:
+			if len(queries) == 1 {
+				return first, nil
+			}
+			return bleve.NewConjunctionQuery(queries...), nil
+		}
+
+		q, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+}
+
+func (p *queryParser) parseUnary(depth int) (query.Query, error) {
+	if depth > maxQueryExpressionDepth {
+		return nil, &QuerySyntaxError{Pos: p.peek().pos, Message: "expression is nested too deeply"}
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokenNot:
+		q, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		// A boolean query with only a must-not clause matches nothing, so the
+		// negation is taken from all documents
+		notQuery := bleve.NewBooleanQuery()
+		notQuery.AddMust(bleve.NewMatchAllQuery())
+		notQuery.AddMustNot(q)
+		return notQuery, nil
+	case tokenLParen:
+		q, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &QuerySyntaxError{Pos: closing.pos, Message: `expected ")" but found ` + closing.describe()}
+		}
+		return q, nil
+	case tokenClause:
+		return compileQueryClause(tok)
+	}
+	return nil, &QuerySyntaxError{Pos: tok.pos, Message: "unexpected " + tok.describe()}
+}
+
+// compileQueryClause compiles a single field:value clause
+func compileQueryClause(tok queryToken) (query.Query, error) {
+	if tok.field == "" {
+		if !tok.quoted && hasWildcard(tok.value) {
+			wildcardQuery := bleve.NewWildcardQuery(strings.ToLower(tok.value))
+			wildcardQuery.SetField("raw")
+			return wildcardQuery, nil
+		}
+		if tok.quoted {
+			return bleve.NewMatchPhraseQuery(tok.value), nil
+		}
+		return bleve.NewMatchQuery(tok.value), nil
+	}
+
+	field, err := lookupQueryField(tok)
+	if err != nil {
+		return nil, err
+	}
+
+	value := tok.value
+	if field.upperCase {
+		value = strings.ToUpper(value)
+	}
+
+	switch field.kind {
+	case queryFieldNumeric:
+		return compileNumericClause(tok, field)
+	case queryFieldIP:
+		if strings.Contains(value, "/") {
+			q, err := cidrQuery(field.name, value)
+			if err != nil {
+				return nil, &QuerySyntaxError{Pos: tok.pos, Message: err.Error()}
+			}
+			return q, nil
+		}
+	case queryFieldText:
+		if !tok.quoted && hasWildcard(value) {
+			wildcardQuery := bleve.NewWildcardQuery(strings.ToLower(value))
+			wildcardQuery.SetField(field.name)
+			return wildcardQuery, nil
+		}
+		phraseQuery := bleve.NewMatchPhraseQuery(value)
+		phraseQuery.SetField(field.name)
+		return phraseQuery, nil
+	}
+
+	if !tok.quoted && isComparison(value) {
+		return nil, &QuerySyntaxError{Pos: tok.pos, Message: "field " + strconv.Quote(tok.field) + " does not support comparisons"}
+	}
+	if !tok.quoted && hasWildcard(value) {
+		wildcardQuery := bleve.NewWildcardQuery(value)
+		wildcardQuery.SetField(field.name)
+		return wildcardQuery, nil
+	}
+	termQuery := bleve.NewTermQuery(value)
+	termQuery.SetField(field.name)
+	return termQuery, nil
+}
+
+// lookupQueryField resolves a field name, including "vars.<name>" fields of
+// custom log_format variables
+func lookupQueryField(tok queryToken) (queryField, error) {
+	name := strings.ToLower(tok.field)
+	if field, ok := queryFields[name]; ok {
+		return field, nil
+	}
+
+	for _, prefix := range []string{VarFieldPrefix, "var."} {
+		if variable, ok := strings.CutPrefix(tok.field, prefix); ok {
+			if !variableNameRegex.MatchString(variable) {
+				return queryField{}, &QuerySyntaxError{Pos: tok.pos, Message: "invalid variable name " + strconv.Quote(variable)}
+			}
+			return queryField{name: VarField(variable), kind: queryFieldKeyword}, nil
+		}
+	}
+
+	return queryField{}, &QuerySyntaxError{Pos: tok.pos, Message: "unknown field " + strconv.Quote(tok.field)}
+}
+
+func hasWildcard(value string) bool {
+	return strings.ContainsAny(value, "*?")
+}
+
+func isComparison(value string) bool {
+	return strings.HasPrefix(value, ">") || strings.HasPrefix(value, "<") || strings.Contains(value, "..")
+}
+
+// compileNumericClause compiles N, >N, >=N, <N, <=N, N..M and, for status,
+// classes such as 5xx into an inclusive or exclusive numeric range
+func compileNumericClause(tok queryToken, field queryField) (query.Query, error) {
+	value := strings.TrimSpace(tok.value)
+	invalid := func() error {
+		return &QuerySyntaxError{Pos: tok.pos, Message: fmt.Sprintf("invalid number %q for field %q", tok.value, tok.field)}
+	}
+	parse := func(s string) (*float64, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, invalid()
+		}
+		return &f, nil
+	}
+
+	var (
+		min, max                   *float64
+		minInclusive, maxInclusive = true, true
+		err                        error
+	)
+
+	switch {
+	case strings.HasPrefix(value, ">="):
+		min, err = parse(value[2:])
+	case strings.HasPrefix(value, ">"):
+		min, err = parse(value[1:])
+		minInclusive = false
+	case strings.HasPrefix(value, "<="):
+		max, err = parse(value[2:])
+	case strings.HasPrefix(value, "<"):
+		max, err = parse(value[1:])
+		maxInclusive = false
+	case strings.Contains(value, ".."):
+		lower, upper, _ := strings.Cut(value, "..")
+		if min, err = parse(lower); err == nil {
+			max, err = parse(upper)
+		}
+	case field.name == "status" && len(value) == 3 && unicode.IsDigit(rune(value[0])) && strings.EqualFold(value[1:], "xx"):
+		lower := float64(value[0]-'0') * 100
+		upper := lower + 99
+		min, max = &lower, &upper
+	default:
+		min, err = parse(value)
+		max = min
+	}
+	if err != nil {
+		return nil, err
+	}
+	if min != nil && max != nil && *min > *max {
+		return nil, &QuerySyntaxError{Pos: tok.pos, Message: fmt.Sprintf("empty range %q for field %q", tok.value, tok.field)}
+	}
+
+	rangeQuery := bleve.NewNumericRangeInclusiveQuery(min, max, &minInclusive, &maxInclusive)
+	rangeQuery.SetField(field.name)
+	return rangeQuery, nil
+}
+
+// cidrQuery matches the keyword IP field against a CIDR prefix. IPs are
+// indexed as text, so an IPv4 prefix is expanded into prefix queries on the
+// dotted octets. An IPv6 prefix must end on a group boundary and must not
+// contain zero groups, which the textual form may compress.
+func cidrQuery(field, value string) (query.Query, error) {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q", value)
+	}
+	prefix = prefix.Masked()
+	addr, bits := prefix.Addr(), prefix.Bits()
+
+	if addr.Is4() {
+		return ipv4CIDRQuery(field, addr.As4(), bits), nil
+	}
+	return ipv6CIDRQuery(field, addr, bits, value)
+}
+
+func ipv4CIDRQuery(field string, octets [4]byte, bits int) query.Query {
+	if bits == 0 {
+		regexpQuery := bleve.NewRegexpQuery(`[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+`)
+		regexpQuery.SetField(field)
+		return regexpQuery
+	}
+
+	full, rem := bits/8, bits%8
+	var base strings.Builder
+	for i := 0; i < full; i++ {
+		base.WriteString(strconv.Itoa(int(octets[i])))
+		base.WriteByte('.')
+	}
+
+	// octetQuery matches the addresses starting with the given octets
+	octetQuery := func(prefix string, last bool) query.Query {
+		if last {
+			termQuery := bleve.NewTermQuery(prefix)
+			termQuery.SetField(field)
+			return termQuery
+		}
+		prefixQuery := bleve.NewPrefixQuery(prefix + ".")
+		prefixQuery.SetField(field)
+		return prefixQuery
+	}
+
+	if rem == 0 {
+		if full == 4 {
+			return octetQuery(strings.TrimSuffix(base.String(), "."), true)
+		}
+		prefixQuery := bleve.NewPrefixQuery(base.String())
+		prefixQuery.SetField(field)
+		return prefixQuery
+	}
+
+	start := int(octets[full])
+	count := 1 << (8 - rem)
+	queries := make([]query.Query, 0, count)
+	for v := start; v < start+count; v++ {
+		queries = append(queries, octetQuery(base.String()+strconv.Itoa(v), full == 3))
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+func ipv6CIDRQuery(field string, addr netip.Addr, bits int, value string) (query.Query, error) {
+	if bits == 128 {
+		termQuery := bleve.NewTermQuery(addr.String())
+		termQuery.SetField(field)
+		return termQuery, nil
+	}
+	if bits == 0 {
+		wildcardQuery := bleve.NewWildcardQuery("*:*")
+		wildcardQuery.SetField(field)
+		return wildcardQuery, nil
+	}
+	if bits%16 != 0 {
+		return nil, fmt.Errorf("IPv6 CIDR %q must have a prefix length that is a multiple of 16", value)
+	}
+
+	raw := addr.As16()
+	groups := make([]string, 0, bits/16)
+	for i := 0; i < bits/16; i++ {
+		group := uint16(raw[2*i])<<8 | uint16(raw[2*i+1])
+		if group == 0 {
+			return nil, fmt.Errorf("IPv6 CIDR %q must not contain zero groups", value)
+		}
+		groups = append(groups, strconv.FormatUint(uint64(group), 16))
+	}
+
+	prefixQuery := bleve.NewPrefixQuery(strings.Join(groups, ":") + ":")
+	prefixQuery.SetField(field)
+	return prefixQuery, nil
+}
@@ -0,0 +1,109 @@
+package searcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearcherQueryExpression(t *testing.T) {
+	s := newFilterTestSearcher(t)
+	defer func() { _ = s.Stop() }()
+
+	tests := []struct {
+		expression string
+		wantIDs    []string
+	}{
+		{"status:>=500", []string{"doc4"}},
+		{"status:4xx", []string{"doc3"}},
+		{"path:/api/*", []string{"doc1", "doc2"}},
+		{"NOT ip:10.0.0.0/8", []string{"doc1", "doc2"}},
+		{"ip:192.168.1.0/31", []string{"doc1"}},
+		{"ip:10.0.0.2/30", []string{"doc3", "doc4"}},
+		{"ip:192.168.1.2", []string{"doc2"}},
+		{"status:>=500 OR path:/api/*", []string{"doc1", "doc2", "doc4"}},
+		{"method:get browser:Chrome", []string{"doc1", "doc3"}},
+		{"bytes:500..1100", []string{"doc1", "doc3"}},
+		{"bytes:<512", []string{"doc4"}},
+		{"-status:200", []string{"doc3", "doc4"}},
+		{"(status:200 OR status:404) AND NOT os:Android", []string{"doc1", "doc2"}},
+		{`ua:"chromeua"`, []string{"doc1", "doc3"}},
+		{"doc2", []string{"doc2"}},
+		{"", []string{"doc1", "doc2", "doc3", "doc4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			result, err := s.Search(context.Background(), &SearchRequest{Expression: tt.expression, Limit: 100})
+			require.NoError(t, err)
+
+			gotIDs := make([]string, 0, len(result.Hits))
+			for _, hit := range result.Hits {
+				gotIDs = append(gotIDs, hit.ID)
+			}
+			assert.ElementsMatch(t, tt.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestSearcherQueryExpressionCombinesWithFilters(t *testing.T) {
+	s := newFilterTestSearcher(t)
+	defer func() { _ = s.Stop() }()
+
+	result, err := s.Search(context.Background(), &SearchRequest{
+		Expression: "NOT status:404",
+		Browsers:   []string{"Chrome"},
+		Limit:      100,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "doc1", result.Hits[0].ID)
+}
+
+func TestParseQueryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantPos    int
+	}{
+		{"status:abc", 0},
+		{"foo:bar", 0},
+		{"(status:200", 11},
+		{"status:200 AND", 14},
+		{"method:>1", 0},
+		{`ua:"chrome`, 3},
+		{"status:600..500", 0},
+		{"ip:fe80::/10", 0},
+		{"ip:2001:0:1::/48", 0},
+		{"status:200 )", 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ParseQueryExpression(tt.expression)
+			var syntaxErr *QuerySyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.wantPos, syntaxErr.Pos)
+		})
+	}
+}
+
+func TestCIDRQueryExpansion(t *testing.T) {
+	q, err := cidrQuery("ip", "172.16.0.0/12")
+	require.NoError(t, err)
+	disjunction, ok := q.(*query.DisjunctionQuery)
+	require.True(t, ok)
+	require.Len(t, disjunction.Disjuncts, 16)
+	assert.Equal(t, "172.16.", disjunction.Disjuncts[0].(*query.PrefixQuery).Prefix)
+	assert.Equal(t, "172.31.", disjunction.Disjuncts[15].(*query.PrefixQuery).Prefix)
+
+	q, err = cidrQuery("ip", "10.1.2.3/8")
+	require.NoError(t, err)
+	assert.Equal(t, "10.", q.(*query.PrefixQuery).Prefix)
+
+	q, err = cidrQuery("ip", "2001:db8::/32")
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8:", q.(*query.PrefixQuery).Prefix)
+}
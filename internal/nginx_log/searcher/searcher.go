@@ -261,6 +261,12 @@ func (s *Searcher) indexAliasForRequest(req *SearchRequest) (bleve.IndexAlias, f
 
 // convertBleveResult converts a Bleve SearchResult to our SearchResult format
 func (s *Searcher) convertBleveResult(bleveResult *bleve.SearchResult) *SearchResult {
+	return convertBleveSearchResult(bleveResult)
+}
+
+// convertBleveSearchResult converts a Bleve SearchResult to our SearchResult
+// format; it is shared by the access and error log searchers.
+func convertBleveSearchResult(bleveResult *bleve.SearchResult) *SearchResult {
 	result := &SearchResult{
 		Hits:      make([]*SearchHit, 0, len(bleveResult.Hits)),
 		TotalHits: bleveResult.Total,
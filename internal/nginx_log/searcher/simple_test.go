@@ -133,6 +133,22 @@ func TestCacheKeyIncludesLogPathsAndOrderInsensitive(t *testing.T) {
 	}
 }
 
+func TestCacheKeyIncludesVariables(t *testing.T) {
+	cache := NewCache(100)
+	defer cache.Close()
+
+	keyA := cache.GenerateKey(&SearchRequest{Variables: map[string][]string{"host": {"a", "b"}}})
+	keyB := cache.GenerateKey(&SearchRequest{Variables: map[string][]string{"host": {"b", "a"}}})
+	keyC := cache.GenerateKey(&SearchRequest{Variables: map[string][]string{"host": {"a"}}})
+
+	if keyA != keyB {
+		t.Fatalf("expected identical cache keys for reordered variable values, got A=%s B=%s", keyA, keyB)
+	}
+	if keyA == keyC {
+		t.Fatalf("expected different cache keys when variable filters differ, got A=%s C=%s", keyA, keyC)
+	}
+}
+
 func BenchmarkCacheOperations(b *testing.B) {
 	cache := NewCache(10000)
 	defer cache.Close()
@@ -41,6 +41,11 @@ type SearchRequest struct {
 	Query  string   `json:"query,omitempty"`
 	Fields []string `json:"fields,omitempty"`
 
+	// Expression is a query language expression such as
+	// `status:>=500 AND NOT ip:10.0.0.0/8`, ANDed with the other filters.
+	// See ParseQueryExpression.
+	Expression string `json:"expression,omitempty"`
+
 	// Filters
 	LogPaths       []string `json:"log_paths,omitempty"`
 	UseMainLogPath bool     `json:"use_main_log_path,omitempty"` // Use main_log_path field instead of file_path for log group queries
@@ -57,6 +62,10 @@ type SearchRequest struct {
 	OSs            []string `json:"operating_systems,omitempty"`
 	Devices        []string `json:"devices,omitempty"`
 
+	// Variables filters on custom log_format variables, indexed as
+	// "vars.<name>" keyword fields. Values of one variable are ORed.
+	Variables map[string][]string `json:"variables,omitempty"`
+
 	// Range filters
 	MinBytes   *int64   `json:"min_bytes,omitempty"`
 	MaxBytes   *int64   `json:"max_bytes,omitempty"`
@@ -9,13 +9,19 @@ import (
 // LogDirective represents a parsed access_log or error_log directive
 // found in an nginx configuration file.
 type LogDirective struct {
-	Type string // "access" or "error"
-	Path string // absolute log file path (relative paths resolved against the nginx prefix)
+	Type   string // "access" or "error"
+	Path   string // absolute log file path (relative paths resolved against the nginx prefix)
+	Format string // log_format name for access logs ("combined" when omitted), empty for error logs
 }
 
+// DefaultLogFormat is the predefined nginx format used by access_log
+// directives that do not name one.
+const DefaultLogFormat = "combined"
+
 // logDirectiveRegex matches access_log or error_log directives and captures
-// the directive name and its first parameter (the log target).
-var logDirectiveRegex = regexp.MustCompile(`(?m)(access_log|error_log)\s+([^\s;]+)(?:\s+[^;]+)?;`)
+// the directive name, its first parameter (the log target) and the optional
+// second parameter (the format name for access_log).
+var logDirectiveRegex = regexp.MustCompile(`(?m)(access_log|error_log)\s+([^\s;]+)(?:\s+([^\s;]+))?(?:\s+[^;]+)?;`)
 
 // ScanLogDirectives extracts access_log/error_log directives from nginx
 // configuration content. Commented directives and non-file targets
@@ -26,7 +32,7 @@ func ScanLogDirectives(prefix string, content []byte) []LogDirective {
 	directives := make([]LogDirective, 0, len(matches))
 
 	for _, m := range matches {
-		// m holds pair offsets: [full, full, group1, group1, group2, group2]
+		// m holds pair offsets: [full, full, group1, group1, group2, group2, group3, group3]
 		if isCommentedAt(content, m[0]) {
 			continue
 		}
@@ -43,13 +49,22 @@ func ScanLogDirectives(prefix string, content []byte) []LogDirective {
 		}
 
 		logType := "access"
+		format := DefaultLogFormat
 		if directiveType == "error_log" {
 			logType = "error"
+			format = ""
+		} else if m[6] >= 0 {
+			// Options such as buffer=32k or gzip may follow the path directly
+			// when the format is omitted; only a bare word names a format.
+			if param := string(content[m[6]:m[7]]); !strings.Contains(param, "=") && param != "gzip" {
+				format = param
+			}
 		}
 
 		directives = append(directives, LogDirective{
-			Type: logType,
-			Path: logPath,
+			Type:   logType,
+			Path:   logPath,
+			Format: format,
 		})
 	}
 
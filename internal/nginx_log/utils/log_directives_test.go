@@ -20,7 +20,7 @@ func TestScanLogDirectives(t *testing.T) {
     error_log /var/log/nginx/example.error.log;
 }`,
 			want: []LogDirective{
-				{Type: "access", Path: "/var/log/nginx/example.access.log"},
+				{Type: "access", Path: "/var/log/nginx/example.access.log", Format: "combined"},
 				{Type: "error", Path: "/var/log/nginx/example.error.log"},
 			},
 		},
@@ -29,7 +29,15 @@ func TestScanLogDirectives(t *testing.T) {
 			prefix:  "/etc/nginx",
 			content: `access_log /var/log/nginx/access.log main buffer=32k flush=5s;`,
 			want: []LogDirective{
-				{Type: "access", Path: "/var/log/nginx/access.log"},
+				{Type: "access", Path: "/var/log/nginx/access.log", Format: "main"},
+			},
+		},
+		{
+			name:    "options without format use combined",
+			prefix:  "/etc/nginx",
+			content: `access_log /var/log/nginx/access.log buffer=32k gzip;`,
+			want: []LogDirective{
+				{Type: "access", Path: "/var/log/nginx/access.log", Format: "combined"},
 			},
 		},
 		{
@@ -37,7 +45,7 @@ func TestScanLogDirectives(t *testing.T) {
 			prefix:  "/usr/local/nginx",
 			content: `access_log logs/access.log;`,
 			want: []LogDirective{
-				{Type: "access", Path: "/usr/local/nginx/logs/access.log"},
+				{Type: "access", Path: "/usr/local/nginx/logs/access.log", Format: "combined"},
 			},
 		},
 		{
@@ -49,7 +57,7 @@ func TestScanLogDirectives(t *testing.T) {
     access_log /var/log/nginx/real.log;
 }`,
 			want: []LogDirective{
-				{Type: "access", Path: "/var/log/nginx/real.log"},
+				{Type: "access", Path: "/var/log/nginx/real.log", Format: "combined"},
 			},
 		},
 		{
@@ -58,7 +66,7 @@ func TestScanLogDirectives(t *testing.T) {
 			content: `# access_log /var/log/nginx/same.log;
 access_log /var/log/nginx/same.log;`,
 			want: []LogDirective{
-				{Type: "access", Path: "/var/log/nginx/same.log"},
+				{Type: "access", Path: "/var/log/nginx/same.log", Format: "combined"},
 			},
 		},
 		{
@@ -90,8 +98,8 @@ server {
     error_log /var/log/nginx/b.error.log warn;
 }`,
 			want: []LogDirective{
-				{Type: "access", Path: "/var/log/nginx/a.access.log"},
-				{Type: "access", Path: "/var/log/nginx/b.access.log"},
+				{Type: "access", Path: "/var/log/nginx/a.access.log", Format: "combined"},
+				{Type: "access", Path: "/var/log/nginx/b.access.log", Format: "combined"},
 				{Type: "error", Path: "/var/log/nginx/b.error.log"},
 			},
 		},
@@ -107,6 +115,36 @@ server {
 	}
 }
 
+func TestScanLogFormats(t *testing.T) {
+	content := []byte(`http {
+    log_format main '$remote_addr - $remote_user [$time_local] "$request" '
+                    '$status $body_bytes_sent "$http_referer" '
+                    '"$http_user_agent" "$upstream_addr"';
+    # log_format disabled '$remote_addr';
+    log_format json_log escape=json '{"ip":"$remote_addr",'
+        '"status":$status,"note":"a;b"}';
+    log_format broken '$remote_addr'
+}`)
+
+	want := []LogFormatDefinition{
+		{
+			Name:    "main",
+			Escape:  "default",
+			Pattern: `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent" "$upstream_addr"`,
+		},
+		{
+			Name:    "json_log",
+			Escape:  "json",
+			Pattern: `{"ip":"$remote_addr","status":$status,"note":"a;b"}`,
+		},
+	}
+
+	got := ScanLogFormats(content)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanLogFormats() = %#v, want %#v", got, want)
+	}
+}
+
 func TestIsCommentedAt(t *testing.T) {
 	content := []byte("    # access_log /tmp/a.log;\naccess_log /tmp/b.log;")
 
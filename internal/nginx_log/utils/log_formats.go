@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LogFormatDefinition represents a parsed log_format directive found in an
+// nginx configuration file.
+type LogFormatDefinition struct {
+	Name    string // format name referenced by access_log
+	Escape  string // "default", "json" or "none"
+	Pattern string // format strings concatenated in declaration order
+}
+
+// logFormatRegex matches the start of a log_format directive; its arguments
+// are tokenized separately because quoted format strings may contain ';'.
+var logFormatRegex = regexp.MustCompile(`\blog_format\s+`)
+
+// ScanLogFormats extracts log_format definitions from nginx configuration
+// content. Commented and unterminated directives are skipped.
+func ScanLogFormats(content []byte) []LogFormatDefinition {
+	matches := logFormatRegex.FindAllIndex(content, -1)
+	definitions := make([]LogFormatDefinition, 0, len(matches))
+
+	for _, m := range matches {
+		if isCommentedAt(content, m[0]) {
+			continue
+		}
+
+		args, ok := scanDirectiveArgs(content, m[1])
+		if !ok || len(args) < 2 {
+			continue
+		}
+
+		definition := LogFormatDefinition{Name: args[0], Escape: "default"}
+		patterns := args[1:]
+		if escape, found := strings.CutPrefix(patterns[0], "escape="); found {
+			definition.Escape = escape
+			patterns = patterns[1:]
+		}
+		if len(patterns) == 0 {
+			continue
+		}
+		definition.Pattern = strings.Join(patterns, "")
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions
+}
+
+// scanDirectiveArgs tokenizes directive arguments starting at pos up to the
+// terminating ';'. Quoted arguments are unescaped the way nginx does it.
+// The second return value is false when the directive is not terminated.
+func scanDirectiveArgs(content []byte, pos int) ([]string, bool) {
+	var args []string
+
+	for pos < len(content) {
+		switch c := content[pos]; {
+		case c == ';':
+			return args, true
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			pos++
+		case c == '#':
+			for pos < len(content) && content[pos] != '\n' {
+				pos++
+			}
+		case c == '\'' || c == '"':
+			arg, next, ok := scanQuotedArg(content, pos+1, c)
+			if !ok {
+				return nil, false
+			}
+			args = append(args, arg)
+			pos = next
+		default:
+			start := pos
+			for pos < len(content) && !isArgDelimiter(content[pos]) {
+				pos++
+			}
+			args = append(args, string(content[start:pos]))
+		}
+	}
+
+	return nil, false
+}
+
+// scanQuotedArg reads a quoted argument starting after its opening quote and
+// returns the unescaped value and the position after the closing quote.
+func scanQuotedArg(content []byte, pos int, quote byte) (string, int, bool) {
+	var b strings.Builder
+
+	for pos < len(content) {
+		c := content[pos]
+		switch {
+		case c == quote:
+			return b.String(), pos + 1, true
+		case c == '\\' && pos+1 < len(content):
+			pos++
+			switch next := content[pos]; next {
+			case '"', '\'', '\\':
+				b.WriteByte(next)
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			}
+		default:
+			b.WriteByte(c)
+		}
+		pos++
+	}
+
+	return "", pos, false
+}
+
+func isArgDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ';':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package ocspcheck
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy/logger"
+	"golang.org/x/crypto/ocsp"
+)
+
+var (
+	mutex   sync.Mutex
+	ocspNow = time.Now
+)
+
+// Result summarizes a check of all certificates.
+type Result struct {
+	Checked         int `json:"checked"`
+	Revoked         int `json:"revoked"`
+	StaplingMissing int `json:"stapling_missing"`
+}
+
+// Check runs a check of all certificates from the background job.
+func Check() {
+	defer func() {
+		if err := recover(); err != nil {
+			buf := make([]byte, 1024)
+			runtime.Stack(buf, false)
+			logger.Errorf("%s\n%s", err, buf)
+		}
+	}()
+	if _, err := Run(context.Background()); err != nil {
+		logger.Error("OCSP check:", err)
+	}
+}
+
+// Run checks every deployed certificate that names an OCSP responder.
+func Run(ctx context.Context) (*Result, error) {
+	c := query.Cert
+	certs, err := c.Where(c.SSLCertificatePath.Neq("")).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, certModel := range certs {
+		err := CheckCert(ctx, certModel)
+		if errors.Is(err, ErrNoResponder) {
+			continue
+		}
+		if err != nil {
+			logger.Warnf("OCSP check: certificate %s: %v", certModel.Name, err)
+		}
+		result.Checked++
+		if certModel.OCSPStatus == model.CertOCSPStatusRevoked {
+			result.Revoked++
+		}
+		if certModel.OCSPStapling == model.CertOCSPStaplingMissing {
+			result.StaplingMissing++
+		}
+	}
+	return result, nil
+}
+
+// CheckCert asks the OCSP responder of a certificate for its status and
+// handshakes with the local listeners serving it to confirm they staple a
+// response. The outcome is stored on the certificate, and an alert is sent
+// when it becomes revoked or, for a must-staple certificate, unstapled.
+// A failed query keeps the last known status.
+func CheckCert(ctx context.Context, certModel *model.Cert) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	leaf, issuer, err := loadChain(ctx, client, certModel.SSLCertificatePath)
+	if err != nil {
+		return saveError(certModel, err)
+	}
+	if len(leaf.OCSPServer) == 0 {
+		// Self-signed and private CA certificates have no responder.
+		if certModel.OCSPStatus != "" || certModel.OCSPStapling != "" || certModel.OCSPError != "" {
+			if err := saveResult(certModel, map[string]any{
+				"ocsp_status":            "",
+				"ocsp_revoked_at":        nil,
+				"ocsp_revocation_reason": 0,
+				"ocsp_stapling":          "",
+				"ocsp_error":             "",
+			}); err != nil {
+				return err
+			}
+		}
+		return ErrNoResponder
+	}
+
+	previousStatus, previousStapling := certModel.OCSPStatus, certModel.OCSPStapling
+	now := ocspNow()
+	updates := map[string]any{
+		"ocsp_checked_at": now,
+		"ocsp_error":      "",
+	}
+
+	response, queryErr := queryResponder(ctx, client, leaf, issuer)
+	if queryErr != nil {
+		updates["ocsp_error"] = queryErr.Error()
+	} else {
+		updates["ocsp_status"], updates["ocsp_revoked_at"], updates["ocsp_revocation_reason"] = responseStatus(response)
+	}
+
+	stapling := ""
+	var missing *Listener
+	if listeners := Listeners(certModel.SSLCertificatePath); len(listeners) > 0 {
+		var stapled bool
+		stapled, missing = checkStapling(ctx, listeners, leaf, issuer)
+		switch {
+		case stapled:
+			stapling = model.CertOCSPStaplingOK
+		case missing != nil:
+			stapling = model.CertOCSPStaplingMissing
+		}
+	}
+	updates["ocsp_stapling"] = stapling
+
+	if err := saveResult(certModel, updates); err != nil {
+		return err
+	}
+
+	if certModel.OCSPStatus == model.CertOCSPStatusRevoked && previousStatus != model.CertOCSPStatusRevoked {
+		notification.Error("Certificate Revoked",
+			"Certificate %{name} has been revoked by its issuer", map[string]any{
+				"name":       certModel.Name,
+				"revoked_at": certModel.OCSPRevokedAt,
+			})
+	}
+	if certModel.MustStaple && stapling == model.CertOCSPStaplingMissing && previousStapling != model.CertOCSPStaplingMissing {
+		notification.Warning("OCSP Stapling Missing",
+			"Certificate %{name} requires OCSP stapling, but %{address} did not staple an OCSP response", map[string]any{
+				"name":        certModel.Name,
+				"address":     missing.Address,
+				"server_name": missing.ServerName,
+			})
+	}
+	return queryErr
+}
+
+// responseStatus maps an OCSP response to the stored status fields.
+func responseStatus(response *ocsp.Response) (status string, revokedAt *time.Time, reason int) {
+	switch response.Status {
+	case ocsp.Good:
+		return model.CertOCSPStatusGood, nil, 0
+	case ocsp.Revoked:
+		revoked := response.RevokedAt
+		return model.CertOCSPStatusRevoked, &revoked, response.RevocationReason
+	default:
+		return model.CertOCSPStatusUnknown, nil, 0
+	}
+}
+
+func saveError(certModel *model.Cert, err error) error {
+	if saveErr := saveResult(certModel, map[string]any{
+		"ocsp_checked_at": ocspNow(),
+		"ocsp_error":      err.Error(),
+	}); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// saveResult stores the OCSP fields and reloads them into certModel.
+func saveResult(certModel *model.Cert, updates map[string]any) error {
+	c := query.Cert
+	if _, err := c.Where(c.ID.Eq(certModel.ID)).Updates(updates); err != nil {
+		return err
+	}
+	reloaded, err := c.Where(c.ID.Eq(certModel.ID)).First()
+	if err != nil {
+		return err
+	}
+	*certModel = *reloaded
+	return nil
+}
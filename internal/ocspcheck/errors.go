@@ -0,0 +1,13 @@
+package ocspcheck
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                   = cosy.NewErrorScope("ocsp_check")
+	ErrNoResponder      = e.New(40001, "certificate has no OCSP responder")
+	ErrIssuerNotFound   = e.New(40002, "issuer certificate of {0} not found")
+	ErrReadCertificate  = e.New(50001, "read certificate error: {0}")
+	ErrResponderRequest = e.New(50002, "query OCSP responder error: {0}")
+	ErrResponderStatus  = e.New(50003, "OCSP responder responded with status {0}")
+	ErrParseResponse    = e.New(50004, "parse OCSP response error: {0}")
+)
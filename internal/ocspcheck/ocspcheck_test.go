@@ -0,0 +1,280 @@
+package ocspcheck
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestListeners(t *testing.T) {
+	setIndexedSite(t, "example.com", `server {
+    listen 443 ssl;
+    listen [::]:443 ssl;
+    listen 443 quic;
+    server_name *.example.com example.com;
+    ssl_certificate /etc/nginx/ssl/example.com/fullchain.cer;
+}
+server {
+    listen 8443 ssl;
+    server_name other.com;
+    ssl_certificate /etc/nginx/ssl/other.com/fullchain.cer;
+}`)
+
+	assert.Equal(t, []Listener{
+		{Address: "127.0.0.1:443", ServerName: "example.com"},
+		{Address: "[::1]:443", ServerName: "example.com"},
+	}, Listeners("/etc/nginx/ssl/example.com/fullchain.cer"))
+	assert.Empty(t, Listeners("/etc/nginx/ssl/missing/fullchain.cer"))
+}
+
+func TestCheckCertAlertsOnRevocationAndMissingStapling(t *testing.T) {
+	db := setupCheckTest(t)
+	pki := newTestPKI(t)
+
+	certPath := filepath.Join(t.TempDir(), "fullchain.cer")
+	require.NoError(t, os.WriteFile(certPath, append(pemCertificate(pki.leaf), pemCertificate(pki.ca)...), 0644))
+	address := pki.serveTLS(t)
+	setIndexedSite(t, "example.com", fmt.Sprintf(`server {
+    listen %s ssl;
+    server_name example.com;
+    ssl_certificate %s;
+}`, address, certPath))
+
+	certModel := &model.Cert{Name: "example.com", SSLCertificatePath: certPath, MustStaple: true}
+	require.NoError(t, db.Create(certModel).Error)
+
+	pki.setStatus(ocsp.Revoked)
+	require.NoError(t, CheckCert(t.Context(), certModel))
+	assert.Equal(t, model.CertOCSPStatusRevoked, certModel.OCSPStatus)
+	require.NotNil(t, certModel.OCSPRevokedAt)
+	assert.Equal(t, ocsp.KeyCompromise, certModel.OCSPRevocationReason)
+	assert.Equal(t, model.CertOCSPStaplingMissing, certModel.OCSPStapling)
+	assertNotificationCount(t, db, 2)
+
+	// Alerts are only sent when the state changes.
+	require.NoError(t, CheckCert(t.Context(), certModel))
+	assertNotificationCount(t, db, 2)
+
+	pki.setStatus(ocsp.Good)
+	pki.staple(t)
+	result, err := Run(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Checked: 1}, result)
+	certModel = &model.Cert{Model: model.Model{ID: certModel.ID}}
+	require.NoError(t, db.First(certModel).Error)
+	assert.Equal(t, model.CertOCSPStatusGood, certModel.OCSPStatus)
+	assert.Nil(t, certModel.OCSPRevokedAt)
+	assert.Equal(t, model.CertOCSPStaplingOK, certModel.OCSPStapling)
+	assert.Empty(t, certModel.OCSPError)
+}
+
+func TestCheckCertKeepsStatusWhenResponderFails(t *testing.T) {
+	db := setupCheckTest(t)
+	pki := newTestPKI(t)
+
+	certPath := filepath.Join(t.TempDir(), "fullchain.cer")
+	require.NoError(t, os.WriteFile(certPath, append(pemCertificate(pki.leaf), pemCertificate(pki.ca)...), 0644))
+	certModel := &model.Cert{Name: "example.com", SSLCertificatePath: certPath}
+	require.NoError(t, db.Create(certModel).Error)
+
+	pki.setStatus(ocsp.Good)
+	require.NoError(t, CheckCert(t.Context(), certModel))
+	assert.Empty(t, certModel.OCSPStapling, "no listener serves the certificate")
+
+	pki.responder.Close()
+	err := CheckCert(t.Context(), certModel)
+	require.Error(t, err)
+	assert.Equal(t, model.CertOCSPStatusGood, certModel.OCSPStatus)
+	assert.NotEmpty(t, certModel.OCSPError)
+	assertNotificationCount(t, db, 0)
+}
+
+type testPKI struct {
+	ca, leaf  *x509.Certificate
+	caKey     crypto.Signer
+	leafKey   crypto.Signer
+	responder *httptest.Server
+
+	mutex      sync.Mutex
+	status     int
+	ocspStaple []byte
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	p := &testPKI{}
+	p.responder = httptest.NewServer(http.HandlerFunc(p.respond))
+	t.Cleanup(p.responder.Close)
+
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	p.ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	p.caKey = caKey
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(0x1001),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 3, 0),
+		OCSPServer:   []string{p.responder.URL},
+	}, p.ca, &leafKey.PublicKey, caKey)
+	require.NoError(t, err)
+	p.leaf, err = x509.ParseCertificate(leafDER)
+	require.NoError(t, err)
+	p.leafKey = leafKey
+	return p
+}
+
+func (p *testPKI) setStatus(status int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.status = status
+}
+
+func (p *testPKI) response(t testing.TB) []byte {
+	p.mutex.Lock()
+	status := p.status
+	p.mutex.Unlock()
+
+	template := ocsp.Response{
+		Status:       status,
+		SerialNumber: p.leaf.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}
+	if status == ocsp.Revoked {
+		template.RevokedAt = time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		template.RevocationReason = ocsp.KeyCompromise
+	}
+	response, err := ocsp.CreateResponse(p.ca, p.ca, template, p.caKey)
+	if t != nil {
+		require.NoError(t, err)
+	}
+	return response
+}
+
+func (p *testPKI) respond(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if _, err := ocsp.ParseRequest(body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	_, _ = w.Write(p.response(nil))
+}
+
+// staple makes the TLS listener staple the current OCSP response.
+func (p *testPKI) staple(t *testing.T) {
+	staple := p.response(t)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.ocspStaple = staple
+}
+
+// serveTLS serves the leaf on a local listener and returns its address.
+func (p *testPKI) serveTLS(t *testing.T) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			return &tls.Certificate{
+				Certificate: [][]byte{p.leaf.Raw, p.ca.Raw},
+				PrivateKey:  p.leafKey,
+				OCSPStaple:  p.ocspStaple,
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+			}(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func pemCertificate(certificate *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+}
+
+func setIndexedSite(t *testing.T, name, content string) {
+	t.Helper()
+	site.IndexedSites[name] = &site.Index{Path: name, Content: content}
+	t.Cleanup(func() { delete(site.IndexedSites, name) })
+}
+
+func setupCheckTest(t *testing.T) *gorm.DB {
+	t.Helper()
+	originalRetryDelay := staplingRetryDelay
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Cert{}, &model.Notification{}, &model.ExternalNotify{}))
+	model.Use(db)
+	query.SetDefault(db)
+	staplingRetryDelay = 0
+
+	t.Cleanup(func() {
+		model.Use(nil)
+		staplingRetryDelay = originalRetryDelay
+	})
+	return db
+}
+
+func assertNotificationCount(t *testing.T, db *gorm.DB, want int64) {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&model.Notification{}).Count(&count).Error)
+	assert.Equal(t, want, count)
+}
@@ -0,0 +1,160 @@
+package ocspcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/transport"
+	"github.com/uozi-tech/cosy"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	responderTimeout = 15 * time.Second
+	// maxResponseSize bounds the OCSP responses and AIA issuer certificates read.
+	maxResponseSize = 1 << 20
+)
+
+// loadChain reads the leaf certificate at path and finds its issuer, either in
+// the same file or at the CA Issuers URL of the AIA extension.
+func loadChain(ctx context.Context, client *http.Client, path string) (leaf, issuer *x509.Certificate, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrReadCertificate, err.Error())
+	}
+	certs, err := parseCertificates(content)
+	if err != nil {
+		return nil, nil, cosy.WrapErrorWithParams(ErrReadCertificate, err.Error())
+	}
+	if len(certs) == 0 {
+		return nil, nil, cosy.WrapErrorWithParams(ErrReadCertificate, "no certificate found in "+path)
+	}
+
+	leaf = certs[0]
+	for _, candidate := range certs[1:] {
+		if leaf.CheckSignatureFrom(candidate) == nil {
+			return leaf, candidate, nil
+		}
+	}
+	for _, location := range leaf.IssuingCertificateURL {
+		candidate, err := fetchIssuer(ctx, client, location)
+		if err != nil {
+			continue
+		}
+		if leaf.CheckSignatureFrom(candidate) == nil {
+			return leaf, candidate, nil
+		}
+	}
+	return nil, nil, cosy.WrapErrorWithParams(ErrIssuerNotFound, leaf.Subject.CommonName)
+}
+
+// parseCertificates parses the certificates of a PEM file.
+func parseCertificates(content []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, certificate)
+	}
+}
+
+// fetchIssuer downloads an issuer certificate, which CAs publish either DER
+// or PEM encoded.
+func fetchIssuer(ctx context.Context, client *http.Client, location string) (*x509.Certificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, cosy.WrapErrorWithParams(ErrResponderStatus, strconv.Itoa(resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(body); block != nil {
+		body = block.Bytes
+	}
+	return x509.ParseCertificate(body)
+}
+
+// queryResponder asks the OCSP responders of the leaf for its status and
+// returns the first valid answer.
+func queryResponder(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, ErrNoResponder
+	}
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrResponderRequest, err.Error())
+	}
+
+	var lastErr error
+	for _, server := range leaf.OCSPServer {
+		response, err := postRequest(ctx, client, server, request)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		parsed, err := ocsp.ParseResponseForCert(response, leaf, issuer)
+		if err != nil {
+			lastErr = cosy.WrapErrorWithParams(ErrParseResponse, err.Error())
+			continue
+		}
+		return parsed, nil
+	}
+	return nil, lastErr
+}
+
+func postRequest(ctx context.Context, client *http.Client, server string, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(request))
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrResponderRequest, err.Error())
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrResponderRequest, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, cosy.WrapErrorWithParams(ErrResponderStatus, strconv.Itoa(resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrResponderRequest, err.Error())
+	}
+	return body, nil
+}
+
+func newClient() (*http.Client, error) {
+	t, err := transport.NewTransport()
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrResponderRequest, err.Error())
+	}
+	return &http.Client{Transport: t, Timeout: responderTimeout}, nil
+}
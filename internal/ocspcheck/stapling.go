@@ -0,0 +1,150 @@
+package ocspcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/uozi-tech/cosy/logger"
+	"golang.org/x/crypto/ocsp"
+)
+
+const handshakeTimeout = 5 * time.Second
+
+// staplingRetryDelay is how long to wait before handshaking again with a
+// listener that served no staple, since nginx only fetches the OCSP response
+// once the first handshake asks for it.
+var staplingRetryDelay = 2 * time.Second
+
+// Listener is a local nginx TLS listener serving a certificate.
+type Listener struct {
+	Address    string `json:"address"`
+	ServerName string `json:"server_name"`
+}
+
+// Listeners returns the TLS listeners of the indexed sites whose servers use
+// the certificate at certPath.
+func Listeners(certPath string) []Listener {
+	indexed := site.GetAllIndexedSites()
+	names := make([]string, 0, len(indexed))
+	for name := range indexed {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var listeners []Listener
+	for _, name := range names {
+		index := site.GetIndexedSite(name)
+		if index.Content == "" {
+			continue
+		}
+		ngxConfig, err := nginx.ParseNgxConfigByContent(index.Content)
+		if err != nil {
+			logger.Debugf("OCSP check: parse site %s error: %v", name, err)
+			continue
+		}
+		for _, server := range ngxConfig.Servers {
+			if !usesCertificate(server, certPath) {
+				continue
+			}
+			serverName := firstServerName(server)
+			for _, directive := range server.Directives {
+				if directive.Directive != "listen" {
+					continue
+				}
+				address, ok := nginx.LocalTLSAddress(directive.Params)
+				if !ok {
+					continue
+				}
+				listener := Listener{Address: address, ServerName: serverName}
+				if !slices.Contains(listeners, listener) {
+					listeners = append(listeners, listener)
+				}
+			}
+		}
+	}
+	return listeners
+}
+
+func usesCertificate(server *nginx.NgxServer, certPath string) bool {
+	for _, directive := range server.Directives {
+		if directive.Directive == "ssl_certificate" && strings.Trim(directive.Params, `"'`) == certPath {
+			return true
+		}
+	}
+	return false
+}
+
+// firstServerName returns a server name usable for SNI.
+func firstServerName(server *nginx.NgxServer) string {
+	for _, directive := range server.Directives {
+		if directive.Directive != "server_name" {
+			continue
+		}
+		for _, name := range strings.Fields(directive.Params) {
+			if name == "_" || strings.ContainsAny(name, "*~") {
+				continue
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// checkStapling reports whether every reachable listener staples a valid OCSP
+// response for the leaf, returning the first listener that does not. Listeners
+// that can not be reached are left out, so no listener yields an empty status.
+func checkStapling(ctx context.Context, listeners []Listener, leaf, issuer *x509.Certificate) (stapled bool, missing *Listener) {
+	reached := false
+	for i := range listeners {
+		ok, err := stapledResponse(ctx, listeners[i], leaf, issuer)
+		if err == nil && !ok && staplingRetryDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return false, nil
+			case <-time.After(staplingRetryDelay):
+			}
+			ok, err = stapledResponse(ctx, listeners[i], leaf, issuer)
+		}
+		if err != nil {
+			logger.Debugf("OCSP check: handshake with %s error: %v", listeners[i].Address, err)
+			continue
+		}
+		reached = true
+		if !ok {
+			return false, &listeners[i]
+		}
+	}
+	return reached, nil
+}
+
+// stapledResponse handshakes with a listener and reports whether it staples a
+// valid OCSP response for the leaf.
+func stapledResponse(ctx context.Context, listener Listener, leaf, issuer *x509.Certificate) (bool, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: handshakeTimeout},
+		// The chain is not verified: only the stapled response matters, and
+		// it is checked against the issuer below.
+		Config: &tls.Config{ServerName: listener.ServerName, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", listener.Address)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	staple := conn.(*tls.Conn).ConnectionState().OCSPResponse
+	if len(staple) == 0 {
+		return false, nil
+	}
+	if _, err := ocsp.ParseResponseForCert(staple, leaf, issuer); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	ngxConfig "github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/pkg/errors"
 	"github.com/tufanbarisyildirim/gonginx/config"
@@ -52,7 +53,7 @@ type PerfOpt struct {
 }
 
 // UpdatePerfOpt updates the Nginx performance optimization settings
-func UpdatePerfOpt(opt *PerfOpt) error {
+func UpdatePerfOpt(opt *PerfOpt, author githistory.Author) error {
 	confPath := nginx.GetConfEntryPath()
 	if confPath == "" {
 		return ErrNginxConfPathEmpty
@@ -77,7 +78,7 @@ func UpdatePerfOpt(opt *PerfOpt) error {
 	// Dump the updated configuration
 	updatedConf := dumper.DumpBlock(conf.Block, dumper.IndentedStyle)
 
-	return ngxConfig.Save(confPath, updatedConf, nil)
+	return ngxConfig.Save(confPath, updatedConf, nil, author)
 
 }
 
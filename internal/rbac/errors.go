@@ -0,0 +1,15 @@
+package rbac
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                       = cosy.NewErrorScope("rbac")
+	ErrPermissionDenied     = e.New(40301, "permission denied")
+	ErrNamespaceDenied      = e.New(40302, "namespace access denied")
+	ErrInvalidPermission    = e.New(40001, "invalid permission: {0}")
+	ErrRoleNotFound         = e.New(40401, "role not found: {0}")
+	ErrRoleNameConflict     = e.New(40002, "role name is reserved by a built-in role")
+	ErrRoleInUse            = e.New(40003, "role is assigned to users")
+	ErrCannotChangeInitUser = e.New(40004, "the initial user must keep the admin role")
+	ErrPermissionEscalation = e.New(40303, "cannot grant permissions you do not hold")
+)
@@ -0,0 +1,198 @@
+package rbac
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/uozi-tech/cosy"
+)
+
+// Resource is a group of management APIs sharing one permission.
+type Resource string
+
+// Action is what a request does with a resource.
+type Action string
+
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
+const (
+	ResourceAnalytic      Resource = "analytic"
+	ResourceBackup        Resource = "backup"
+	ResourceCertificates  Resource = "certificates"
+	ResourceChanges       Resource = "changes"
+	ResourceCluster       Resource = "cluster"
+	ResourceConfigs       Resource = "configs"
+	ResourceDNS           Resource = "dns"
+	ResourceJails         Resource = "jails"
+	ResourceLLM           Resource = "llm"
+	ResourceLogs          Resource = "logs"
+	ResourceMCP           Resource = "mcp"
+	ResourceMetrics       Resource = "metrics"
+	ResourceNginx         Resource = "nginx"
+	ResourceNotifications Resource = "notifications"
+	ResourceSettings      Resource = "settings"
+	ResourceSites         Resource = "sites"
+	ResourceStreams       Resource = "streams"
+	ResourceSystem        Resource = "system"
+	ResourceTemplates     Resource = "templates"
+	ResourceTerminal      Resource = "terminal"
+	ResourceUpstream      Resource = "upstream"
+	ResourceUsers         Resource = "users"
+)
+
+// Wildcard grants every action on every resource.
+const Wildcard = "*"
+
+// Resources lists every resource known to the permission model.
+var Resources = []Resource{
+	ResourceAnalytic,
+	ResourceBackup,
+	ResourceCertificates,
+	ResourceChanges,
+	ResourceCluster,
+	ResourceConfigs,
+	ResourceDNS,
+	ResourceJails,
+	ResourceLLM,
+	ResourceLogs,
+	ResourceMCP,
+	ResourceMetrics,
+	ResourceNginx,
+	ResourceNotifications,
+	ResourceSettings,
+	ResourceSites,
+	ResourceStreams,
+	ResourceSystem,
+	ResourceTemplates,
+	ResourceTerminal,
+	ResourceUpstream,
+	ResourceUsers,
+}
+
+var builtinRoles = map[string][]string{
+	model.RoleAdmin: {Wildcard},
+	model.RoleOperator: {
+		Permission(ResourceAnalytic, ActionRead),
+		Permission(ResourceCertificates, ActionWrite),
+		Permission(ResourceChanges, ActionWrite),
+		Permission(ResourceCluster, ActionRead),
+		Permission(ResourceConfigs, ActionWrite),
+		Permission(ResourceDNS, ActionWrite),
+		Permission(ResourceJails, ActionWrite),
+		Permission(ResourceLLM, ActionWrite),
+		Permission(ResourceLogs, ActionWrite),
+		Permission(ResourceMCP, ActionRead),
+		Permission(ResourceMetrics, ActionRead),
+		Permission(ResourceNginx, ActionWrite),
+		Permission(ResourceNotifications, ActionWrite),
+		Permission(ResourceSettings, ActionRead),
+		Permission(ResourceSites, ActionWrite),
+		Permission(ResourceStreams, ActionWrite),
+		Permission(ResourceSystem, ActionRead),
+		Permission(ResourceTemplates, ActionRead),
+		Permission(ResourceUpstream, ActionWrite),
+	},
+	model.RoleViewer: {
+		Permission(ResourceAnalytic, ActionRead),
+		Permission(ResourceCertificates, ActionRead),
+		Permission(ResourceChanges, ActionRead),
+		Permission(ResourceCluster, ActionRead),
+		// No configs: raw config paths reach the certificates and their keys.
+		Permission(ResourceDNS, ActionRead),
+		Permission(ResourceJails, ActionRead),
+		Permission(ResourceLogs, ActionRead),
+		Permission(ResourceMetrics, ActionRead),
+		Permission(ResourceNginx, ActionRead),
+		Permission(ResourceNotifications, ActionRead),
+		Permission(ResourceSites, ActionRead),
+		Permission(ResourceStreams, ActionRead),
+		Permission(ResourceSystem, ActionRead),
+		Permission(ResourceTemplates, ActionRead),
+		Permission(ResourceUpstream, ActionRead),
+	},
+}
+
+// BuiltinRoleNames returns the names of the roles defined in code.
+func BuiltinRoleNames() []string {
+	return []string{model.RoleAdmin, model.RoleOperator, model.RoleViewer}
+}
+
+// IsBuiltinRole reports whether name refers to a role defined in code.
+func IsBuiltinRole(name string) bool {
+	_, ok := builtinRoles[name]
+	return ok
+}
+
+// BuiltinRolePermissions returns a copy of a built-in role's permissions.
+func BuiltinRolePermissions(name string) ([]string, bool) {
+	permissions, ok := builtinRoles[name]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(permissions), true
+}
+
+// Permission formats a resource and action as "<resource>:<action>".
+func Permission(resource Resource, action Action) string {
+	return string(resource) + ":" + string(action)
+}
+
+// ParsePermission splits a permission into its resource and action. The
+// resource may be "*" to apply the action to every resource.
+func ParsePermission(permission string) (Resource, Action, bool) {
+	if permission == Wildcard {
+		return Wildcard, Wildcard, true
+	}
+	resource, action, ok := strings.Cut(permission, ":")
+	if !ok {
+		return "", "", false
+	}
+	if Action(action) != ActionRead && Action(action) != ActionWrite {
+		return "", "", false
+	}
+	if resource != Wildcard && !slices.Contains(Resources, Resource(resource)) {
+		return "", "", false
+	}
+	return Resource(resource), Action(action), true
+}
+
+// NormalizePermissions validates, deduplicates and sorts permissions.
+func NormalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(permissions))
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if _, _, ok := ParsePermission(permission); !ok {
+			return nil, cosy.WrapErrorWithParams(ErrInvalidPermission, permission)
+		}
+		if _, exists := seen[permission]; exists {
+			continue
+		}
+		seen[permission] = struct{}{}
+		result = append(result, permission)
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+// Grants reports whether a permission list allows the action on resource.
+// A write permission implies read access to the same resource.
+func Grants(permissions []string, resource Resource, action Action) bool {
+	for _, permission := range permissions {
+		granted, grantedAction, ok := ParsePermission(permission)
+		if !ok {
+			continue
+		}
+		if granted != Wildcard && granted != resource {
+			continue
+		}
+		if grantedAction == Wildcard || grantedAction == action || grantedAction == ActionWrite {
+			return true
+		}
+	}
+	return false
+}
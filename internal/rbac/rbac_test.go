@@ -0,0 +1,75 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantsWriteImpliesRead(t *testing.T) {
+	permissions := []string{Permission(ResourceSites, ActionWrite), Permission(ResourceLogs, ActionRead)}
+
+	assert.True(t, Grants(permissions, ResourceSites, ActionRead))
+	assert.True(t, Grants(permissions, ResourceSites, ActionWrite))
+	assert.True(t, Grants(permissions, ResourceLogs, ActionRead))
+	assert.False(t, Grants(permissions, ResourceLogs, ActionWrite))
+	assert.False(t, Grants(permissions, ResourceUsers, ActionRead))
+	assert.True(t, Grants([]string{"*:read"}, ResourceUsers, ActionRead))
+	assert.False(t, Grants([]string{"*:read"}, ResourceUsers, ActionWrite))
+	assert.True(t, Grants([]string{Wildcard}, ResourceUsers, ActionWrite))
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	permissions, err := NormalizePermissions([]string{" sites:write", "logs:read", "sites:write"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs:read", "sites:write"}, permissions)
+
+	for _, invalid := range []string{"sites", "sites:delete", "unknown:read", ""} {
+		_, err = NormalizePermissions([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSubjectCovers(t *testing.T) {
+	operator := ForUser(&model.User{Model: model.Model{ID: 2}, Role: model.RoleOperator})
+
+	assert.True(t, operator.Covers([]string{"sites:write", "settings:read"}))
+	assert.False(t, operator.Covers([]string{"settings:write"}))
+	assert.False(t, operator.Covers([]string{"*:read"}))
+	assert.False(t, operator.Covers([]string{Wildcard}))
+
+	assert.True(t, ForScopes([]string{model.APITokenScopeWrite}, nil).Covers([]string{"*:read", "users:write"}))
+	assert.True(t, Admin().Covers([]string{Wildcard}))
+}
+
+func TestForScopesIgnoresWildcardAndUnknownScopes(t *testing.T) {
+	subject := ForScopes([]string{Wildcard, "bogus", "sites:read", model.MCPTokenScopeRead}, nil)
+
+	assert.Equal(t, []string{"sites:read", model.MCPTokenScopeRead}, subject.Permissions)
+	assert.False(t, subject.Can(ResourceUsers, ActionRead))
+	assert.True(t, subject.Can(ResourceMCP, ActionRead))
+}
+
+func TestNamespaceScope(t *testing.T) {
+	scoped := &Subject{Permissions: []string{Wildcard}, NamespaceIDs: []uint64{3}}
+	assert.True(t, scoped.CanAccessNamespace(3))
+	assert.False(t, scoped.CanAccessNamespace(4))
+	assert.False(t, scoped.CanAccessNamespace(0))
+	assert.True(t, Admin().CanAccessNamespace(0))
+}
+
+func TestValidateAssignment(t *testing.T) {
+	scoped := &Subject{Permissions: []string{Wildcard}, NamespaceIDs: []uint64{3}}
+
+	assert.ErrorIs(t, ValidateAssignment(initUserID, model.RoleViewer, nil, Admin()), ErrCannotChangeInitUser)
+	assert.ErrorIs(t, ValidateAssignment(initUserID, model.RoleAdmin, []uint64{3}, Admin()), ErrCannotChangeInitUser)
+	assert.NoError(t, ValidateAssignment(2, model.RoleViewer, []uint64{3}, scoped))
+	assert.ErrorIs(t, ValidateAssignment(2, model.RoleViewer, []uint64{4}, scoped), ErrNamespaceDenied)
+	assert.ErrorIs(t, ValidateAssignment(2, model.RoleViewer, nil, scoped), ErrNamespaceDenied)
+
+	viewer := ForUser(&model.User{Model: model.Model{ID: 2}, Role: model.RoleViewer})
+	assert.ErrorIs(t, ValidateAssignment(3, model.RoleOperator, nil, viewer), ErrPermissionEscalation)
+	assert.False(t, viewer.Can(ResourceConfigs, ActionRead))
+}
@@ -0,0 +1,140 @@
+package rbac
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/gorm"
+)
+
+// RoleInfo describes a built-in or custom role for the API.
+type RoleInfo struct {
+	ID          uint64   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Builtin     bool     `json:"builtin"`
+}
+
+var builtinRoleDescriptions = map[string]string{
+	model.RoleAdmin:    "Full access to every resource",
+	model.RoleOperator: "Manage sites, streams, configs and certificates without access to users, settings, backups or the terminal",
+	model.RoleViewer:   "Read-only access",
+}
+
+// RolePermissions returns the permissions of a built-in or custom role.
+func RolePermissions(name string) ([]string, error) {
+	if permissions, ok := BuiltinRolePermissions(name); ok {
+		return permissions, nil
+	}
+	if model.UseDB() == nil {
+		return nil, cosy.WrapErrorWithParams(ErrRoleNotFound, name)
+	}
+	r := query.Role
+	role, err := r.Where(r.Name.Eq(name)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cosy.WrapErrorWithParams(ErrRoleNotFound, name)
+		}
+		return nil, err
+	}
+	return role.Permissions, nil
+}
+
+// RoleExists reports whether name refers to a built-in or custom role.
+func RoleExists(name string) bool {
+	_, err := RolePermissions(name)
+	return err == nil
+}
+
+// ListRoles returns the built-in roles followed by the custom roles.
+func ListRoles() ([]RoleInfo, error) {
+	result := make([]RoleInfo, 0, len(builtinRoles))
+	for _, name := range BuiltinRoleNames() {
+		permissions, _ := BuiltinRolePermissions(name)
+		result = append(result, RoleInfo{
+			Name:        name,
+			Description: builtinRoleDescriptions[name],
+			Permissions: permissions,
+			Builtin:     true,
+		})
+	}
+
+	r := query.Role
+	roles, err := r.Order(r.Name).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		result = append(result, RoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	return result, nil
+}
+
+// ValidateRole normalizes a custom role before it is stored. The grantor
+// must already hold every permission it hands out.
+func ValidateRole(role *model.Role, grantor *Subject) error {
+	role.Name = strings.TrimSpace(role.Name)
+	if IsBuiltinRole(role.Name) {
+		return ErrRoleNameConflict
+	}
+	permissions, err := NormalizePermissions(role.Permissions)
+	if err != nil {
+		return err
+	}
+	if !grantor.Covers(permissions) {
+		return ErrPermissionEscalation
+	}
+	role.Permissions = permissions
+	return nil
+}
+
+// EnsureRoleUnused prevents deleting or renaming a role users still hold.
+func EnsureRoleUnused(name string) error {
+	u := query.User
+	count, err := u.Where(u.Role.Eq(name)).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	return nil
+}
+
+// ValidateAssignment checks that grantor may give the role and namespace
+// scope to the user identified by userID.
+func ValidateAssignment(userID uint64, role string, namespaceIDs []uint64, grantor *Subject) error {
+	if userID == initUserID && role != "" && role != model.RoleAdmin {
+		return ErrCannotChangeInitUser
+	}
+	if userID == initUserID && len(namespaceIDs) > 0 {
+		return ErrCannotChangeInitUser
+	}
+	if role != "" {
+		permissions, err := RolePermissions(role)
+		if err != nil {
+			return err
+		}
+		if !grantor.Covers(permissions) {
+			return ErrPermissionEscalation
+		}
+	}
+	for _, namespaceID := range namespaceIDs {
+		if !grantor.CanAccessNamespace(namespaceID) {
+			return ErrNamespaceDenied
+		}
+	}
+	if len(namespaceIDs) == 0 && grantor.IsNamespaceScoped() {
+		return ErrNamespaceDenied
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package rbac
+
+import (
+	"slices"
+
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+// initUserID is the account created during installation. It always keeps
+// full access so a misconfigured role can never lock everyone out.
+const initUserID = 1
+
+// Subject is the authorization view of whoever issued a request: a user, a
+// service token or a cluster node.
+type Subject struct {
+	Role         string   `json:"role,omitempty"`
+	Permissions  []string `json:"permissions"`
+	NamespaceIDs []uint64 `json:"namespace_ids,omitempty"`
+}
+
+// Admin returns a subject with unrestricted access.
+func Admin() *Subject {
+	return &Subject{Role: model.RoleAdmin, Permissions: []string{Wildcard}}
+}
+
+// ForUser resolves the role assigned to u. Unknown roles grant nothing.
+func ForUser(u *model.User) *Subject {
+	if u == nil {
+		return &Subject{}
+	}
+	if u.ID == initUserID {
+		return Admin()
+	}
+	role := u.Role
+	if role == "" {
+		role = model.RoleAdmin
+	}
+	permissions, _ := RolePermissions(role)
+	return &Subject{
+		Role:         role,
+		Permissions:  permissions,
+		NamespaceIDs: slices.Clone(u.NamespaceIDs),
+	}
+}
+
+// ForScopes builds a subject from service token scopes. The legacy api:read
+// and api:write scopes apply to every resource; MCP scopes grant no API access.
+func ForScopes(scopes []string, namespaceIDs []uint64) *Subject {
+	permissions := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		switch scope {
+		case model.APITokenScopeRead:
+			permissions = append(permissions, Wildcard+":"+string(ActionRead))
+		case model.APITokenScopeWrite:
+			permissions = append(permissions, Wildcard+":"+string(ActionWrite))
+		default:
+			if _, _, ok := ParsePermission(scope); ok && scope != Wildcard {
+				permissions = append(permissions, scope)
+			}
+		}
+	}
+	return &Subject{Permissions: permissions, NamespaceIDs: slices.Clone(namespaceIDs)}
+}
+
+// Can reports whether the subject may perform action on resource.
+func (s *Subject) Can(resource Resource, action Action) bool {
+	if s == nil {
+		return false
+	}
+	return Grants(s.Permissions, resource, action)
+}
+
+// IsNamespaceScoped reports whether the subject is limited to some namespaces.
+func (s *Subject) IsNamespaceScoped() bool {
+	return s != nil && len(s.NamespaceIDs) > 0
+}
+
+// CanAccessNamespace reports whether the subject may touch content in the
+// namespace. Scoped subjects cannot access content outside any namespace.
+func (s *Subject) CanAccessNamespace(namespaceID uint64) bool {
+	if !s.IsNamespaceScoped() {
+		return true
+	}
+	return slices.Contains(s.NamespaceIDs, namespaceID)
+}
+
+// Covers reports whether the subject holds every permission in permissions,
+// which is required before handing them to a role or a service token.
+func (s *Subject) Covers(permissions []string) bool {
+	for _, permission := range permissions {
+		resource, action, ok := ParsePermission(permission)
+		if !ok {
+			return false
+		}
+		if resource == Wildcard {
+			if !slices.Contains(s.Permissions, Wildcard) &&
+				!slices.Contains(s.Permissions, permission) &&
+				!(action == ActionRead && slices.Contains(s.Permissions, Wildcard+":"+string(ActionWrite))) {
+				return false
+			}
+			continue
+		}
+		if !s.Can(resource, action) {
+			return false
+		}
+	}
+	return true
+}
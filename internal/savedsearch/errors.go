@@ -0,0 +1,12 @@
+package savedsearch
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                      = cosy.NewErrorScope("saved_search")
+	ErrInvalidExpression   = e.New(40001, "invalid query expression: {0}")
+	ErrInvalidLogPath      = e.New(40002, "log path is not under the whitelist: {0}")
+	ErrInvalidTimeRange    = e.New(40003, "time range must not be negative")
+	ErrInvalidSortOrder    = e.New(40004, "sort order must be asc or desc")
+	ErrSearcherUnavailable = e.New(50001, "the log searcher is not available")
+)
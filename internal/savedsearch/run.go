@@ -0,0 +1,72 @@
+package savedsearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+// RunOptions narrows a run of a saved search. An explicit start or end time
+// overrides the time range stored with the search.
+type RunOptions struct {
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+	Limit     int   `json:"limit"`
+	Offset    int   `json:"offset"`
+}
+
+var getSearcher = func() searcher.SearcherInterface {
+	if s := nginx_log.GetSearcher(); s != nil && s.IsHealthy() {
+		return s
+	}
+	return nil
+}
+
+// BuildRequest turns a saved search into a searcher request evaluated at now.
+func BuildRequest(search *model.NginxLogSavedSearch, opts RunOptions, now time.Time) *searcher.SearchRequest {
+	req := &searcher.SearchRequest{
+		Query:      search.Query,
+		Expression: search.Expression,
+		Limit:      opts.Limit,
+		Offset:     opts.Offset,
+		SortBy:     search.SortBy,
+		SortOrder:  search.SortOrder,
+		UseCache:   true,
+	}
+	if req.SortBy == "" {
+		req.SortBy = "timestamp"
+		req.SortOrder = searcher.SortOrderDesc
+	}
+	if search.LogPath != "" {
+		req.LogPaths = []string{search.LogPath}
+		req.UseMainLogPath = true
+	}
+
+	startTime, endTime := opts.StartTime, opts.EndTime
+	if startTime == 0 && endTime == 0 && search.TimeRange > 0 {
+		endTime = now.Unix()
+		startTime = endTime - search.TimeRange
+	}
+	if startTime > 0 {
+		req.StartTime = &startTime
+	}
+	if endTime > 0 {
+		req.EndTime = &endTime
+	}
+	return req
+}
+
+// Run executes a saved search against the log index.
+func Run(ctx context.Context, search *model.NginxLogSavedSearch, opts RunOptions) (*searcher.SearchResult, error) {
+	s := getSearcher()
+	if s == nil {
+		return nil, ErrSearcherUnavailable
+	}
+	if err := ValidateExpression(search.Expression); err != nil {
+		return nil, err
+	}
+	return s.Search(ctx, BuildRequest(search, opts, time.Now()))
+}
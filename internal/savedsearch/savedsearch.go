@@ -0,0 +1,84 @@
+package savedsearch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+// shareTokenBytes is the entropy of a share token, hex encoded into the link
+const shareTokenBytes = 24
+
+// Normalize validates a saved search before it is stored.
+func Normalize(search *model.NginxLogSavedSearch) error {
+	if err := ValidateExpression(search.Expression); err != nil {
+		return err
+	}
+	if search.LogPath != "" && !utils.IsValidLogPath(search.LogPath) {
+		return cosy.WrapErrorWithParams(ErrInvalidLogPath, search.LogPath)
+	}
+	if search.TimeRange < 0 {
+		return ErrInvalidTimeRange
+	}
+	switch search.SortOrder {
+	case "", searcher.SortOrderAsc, searcher.SortOrderDesc:
+	default:
+		return ErrInvalidSortOrder
+	}
+	return nil
+}
+
+// ValidateExpression reports where a query language expression is invalid.
+func ValidateExpression(expression string) error {
+	if _, err := searcher.ParseQueryExpression(expression); err != nil {
+		var syntaxErr *searcher.QuerySyntaxError
+		if errors.As(err, &syntaxErr) {
+			return cosy.WrapErrorWithParams(ErrInvalidExpression, syntaxErr.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// Get loads a saved search of a user.
+func Get(id, userID uint64) (*model.NginxLogSavedSearch, error) {
+	q := query.NginxLogSavedSearch
+	return q.Where(q.ID.Eq(id), q.UserID.Eq(userID)).First()
+}
+
+// GetShared loads a saved search by the token of its share link.
+func GetShared(token string) (*model.NginxLogSavedSearch, error) {
+	q := query.NginxLogSavedSearch
+	return q.Where(q.ShareToken.Eq(token), q.ShareToken.Neq("")).First()
+}
+
+// Share issues a share token for a saved search, keeping an existing one so
+// links already handed out stay valid.
+func Share(search *model.NginxLogSavedSearch) error {
+	if search.ShareToken != "" {
+		return nil
+	}
+
+	token := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	search.ShareToken = hex.EncodeToString(token)
+	q := query.NginxLogSavedSearch
+	_, err := q.Where(q.ID.Eq(search.ID)).Update(q.ShareToken, search.ShareToken)
+	return err
+}
+
+// Unshare revokes the share link of a saved search.
+func Unshare(search *model.NginxLogSavedSearch) error {
+	search.ShareToken = ""
+	q := query.NginxLogSavedSearch
+	_, err := q.Where(q.ID.Eq(search.ID)).Update(q.ShareToken, "")
+	return err
+}
@@ -0,0 +1,98 @@
+package savedsearch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	originalDB := model.UseDB()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.NginxLogSavedSearch{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	t.Cleanup(func() { model.Use(originalDB) })
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
+
+func TestNormalizeRejectsInvalidSearches(t *testing.T) {
+	require.NoError(t, Normalize(&model.NginxLogSavedSearch{Expression: "status:>=500 AND NOT ip:10.0.0.0/8"}))
+
+	err := Normalize(&model.NginxLogSavedSearch{Expression: "status:>=500 AND"})
+	requireErrorCode(t, err, ErrInvalidExpression)
+	assert.Contains(t, err.Error(), "position 17")
+
+	requireErrorCode(t, Normalize(&model.NginxLogSavedSearch{TimeRange: -1}), ErrInvalidTimeRange)
+	requireErrorCode(t, Normalize(&model.NginxLogSavedSearch{SortOrder: "up"}), ErrInvalidSortOrder)
+	requireErrorCode(t, Normalize(&model.NginxLogSavedSearch{LogPath: "/etc/passwd"}), ErrInvalidLogPath)
+}
+
+func TestBuildRequestAppliesTimeRange(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	search := &model.NginxLogSavedSearch{
+		LogPath:    "/var/log/nginx/access.log",
+		Expression: "status:5xx",
+		TimeRange:  3600,
+	}
+
+	req := BuildRequest(search, RunOptions{Limit: 20}, now)
+	assert.Equal(t, "status:5xx", req.Expression)
+	assert.Equal(t, []string{"/var/log/nginx/access.log"}, req.LogPaths)
+	assert.True(t, req.UseMainLogPath)
+	assert.Equal(t, "timestamp", req.SortBy)
+	assert.Equal(t, int64(1_700_000_000-3600), *req.StartTime)
+	assert.Equal(t, int64(1_700_000_000), *req.EndTime)
+	assert.Equal(t, 20, req.Limit)
+
+	// An explicit window overrides the stored time range
+	req = BuildRequest(search, RunOptions{StartTime: 100}, now)
+	assert.Equal(t, int64(100), *req.StartTime)
+	assert.Nil(t, req.EndTime)
+}
+
+func TestShareAndUnshare(t *testing.T) {
+	setupDB(t)
+
+	search := &model.NginxLogSavedSearch{UserID: 1, Name: "5xx", Expression: "status:5xx"}
+	require.NoError(t, model.UseDB().Create(search).Error)
+
+	_, err := Get(search.ID, 2)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, Share(search))
+	token := search.ShareToken
+	assert.Len(t, token, 2*shareTokenBytes)
+
+	// Sharing again keeps the link
+	require.NoError(t, Share(search))
+	assert.Equal(t, token, search.ShareToken)
+
+	shared, err := GetShared(token)
+	require.NoError(t, err)
+	assert.Equal(t, search.ID, shared.ID)
+
+	require.NoError(t, Unshare(search))
+	_, err = GetShared(token)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = GetShared("")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
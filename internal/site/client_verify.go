@@ -0,0 +1,119 @@
+package site
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/cert"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+)
+
+// clientVerifyDirectives are the directives SetClientVerify manages.
+var clientVerifyDirectives = []string{
+	"ssl_verify_client",
+	"ssl_client_certificate",
+	"ssl_crl",
+	"ssl_verify_depth",
+}
+
+// SetClientVerify makes every TLS server of a site verify client certificates
+// against a private CA, using its chain and CRL. mode is the value of
+// ssl_verify_client, a zero caID removes the verification again.
+func SetClientVerify(name string, caID uint64, mode string, author githistory.Author) error {
+	path, err := ResolveAvailablePath(name)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrSiteNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var directives []*nginx.NgxDirective
+	if caID != 0 {
+		ca, err := cert.GetCertAuthority(caID)
+		if err != nil {
+			return err
+		}
+		chain, err := cert.CertAuthorityChain(ca)
+		if err != nil {
+			return err
+		}
+		paths := cert.GetCertAuthorityPaths(ca.ID)
+		directives = []*nginx.NgxDirective{
+			{Directive: "ssl_verify_client", Params: mode},
+			{Directive: "ssl_client_certificate", Params: paths.Chain},
+			{Directive: "ssl_crl", Params: paths.CRL},
+			{Directive: "ssl_verify_depth", Params: strconv.Itoa(len(chain))},
+		}
+	}
+
+	newContent, err := applyClientVerify(string(content), directives)
+	if err != nil {
+		return err
+	}
+
+	var namespaceID uint64
+	var syncNodeIDs []uint64
+	s := query.Site
+	if siteModel, err := s.Where(s.Path.Eq(path)).First(); err == nil {
+		namespaceID = siteModel.NamespaceID
+		syncNodeIDs = siteModel.SyncNodeIDs
+	}
+	return Save(name, newContent, true, namespaceID, syncNodeIDs, model.PostSyncActionReloadNginx, author)
+}
+
+// applyClientVerify replaces the client verification directives of every TLS
+// server in a site configuration with the given ones.
+func applyClientVerify(content string, directives []*nginx.NgxDirective) (string, error) {
+	ngxConfig, err := nginx.ParseNgxConfigByContent(content)
+	if err != nil {
+		return "", err
+	}
+	tlsServers := 0
+	for _, server := range ngxConfig.Servers {
+		if !isTLSServer(server) {
+			continue
+		}
+		tlsServers++
+		server.Directives = slices.DeleteFunc(server.Directives, func(d *nginx.NgxDirective) bool {
+			return slices.Contains(clientVerifyDirectives, d.Directive)
+		})
+		for _, directive := range directives {
+			server.Directives = append(server.Directives, &nginx.NgxDirective{
+				Directive: directive.Directive,
+				Params:    directive.Params,
+			})
+		}
+	}
+	if tlsServers == 0 {
+		return "", ErrNoTLSServer
+	}
+	return ngxConfig.BuildConfig()
+}
+
+// isTLSServer reports whether a server block terminates TLS.
+func isTLSServer(server *nginx.NgxServer) bool {
+	for _, directive := range server.Directives {
+		switch directive.Directive {
+		case "ssl_certificate":
+			return true
+		case "listen":
+			for _, param := range strings.Fields(directive.Params) {
+				if param == "ssl" || param == "quic" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
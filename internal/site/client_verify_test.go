@@ -0,0 +1,55 @@
+package site
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+)
+
+func TestApplyClientVerifyOnlyChangesTLSServers(t *testing.T) {
+	content := `server {
+    listen 80;
+    server_name example.com;
+    return 301 https://$host$request_uri;
+}
+server {
+    listen 443 ssl;
+    server_name example.com;
+    ssl_certificate /etc/nginx/ssl/example.com/fullchain.cer;
+    ssl_certificate_key /etc/nginx/ssl/example.com/private.key;
+    ssl_verify_client optional;
+    location / {
+        proxy_pass http://127.0.0.1:8080;
+    }
+}
+`
+	got, err := applyClientVerify(content, []*nginx.NgxDirective{
+		{Directive: "ssl_verify_client", Params: "on"},
+		{Directive: "ssl_client_certificate", Params: "/etc/nginx/ssl/private_ca/1/chain.crt"},
+	})
+	if err != nil {
+		t.Fatalf("applyClientVerify() error = %v", err)
+	}
+	if strings.Count(got, "ssl_verify_client") != 1 || !strings.Contains(got, "ssl_verify_client on;") {
+		t.Fatalf("expected a single ssl_verify_client on, got:\n%s", got)
+	}
+	if strings.Count(got, "ssl_client_certificate") != 1 {
+		t.Fatalf("expected ssl_client_certificate in the TLS server only, got:\n%s", got)
+	}
+	if !strings.Contains(got, "proxy_pass http://127.0.0.1:8080;") {
+		t.Fatalf("locations must be kept, got:\n%s", got)
+	}
+
+	got, err = applyClientVerify(got, nil)
+	if err != nil {
+		t.Fatalf("applyClientVerify() error = %v", err)
+	}
+	if strings.Contains(got, "ssl_verify_client") || strings.Contains(got, "ssl_client_certificate") {
+		t.Fatalf("expected client verification to be removed, got:\n%s", got)
+	}
+
+	if _, err = applyClientVerify("server {\n    listen 80;\n}\n", nil); err != ErrNoTLSServer {
+		t.Fatalf("applyClientVerify() error = %v, want %v", err, ErrNoTLSServer)
+	}
+}
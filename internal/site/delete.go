@@ -1,11 +1,13 @@
 package site
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"runtime"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
 	"github.com/0xJacky/Nginx-UI/internal/notification"
@@ -15,7 +17,17 @@ import (
 )
 
 // Delete deletes a site by removing the file in sites-available
-func Delete(name string) (err error) {
+func Delete(name string, author githistory.Author) (err error) {
+	return deleteSite(name, false, author)
+}
+
+// DeleteWithDNSRecords deletes a site together with the DNS records that were
+// provisioned for it, regardless of the namespace policy.
+func DeleteWithDNSRecords(name string, author githistory.Author) (err error) {
+	return deleteSite(name, true, author)
+}
+
+func deleteSite(name string, deleteDNS bool, author githistory.Author) (err error) {
 	availablePath, err := ResolveAvailablePath(name)
 	if err != nil {
 		return err
@@ -34,6 +46,9 @@ func Delete(name string) (err error) {
 	syncDelete(name)
 
 	s := query.Site
+	// The DNS links go away with the record, so they are read beforehand.
+	siteModel, _ := s.Where(s.Path.Eq(availablePath)).First()
+
 	_, err = s.Where(s.Path.Eq(availablePath)).Unscoped().Delete(&model.Site{})
 	if err != nil {
 		return
@@ -68,6 +83,11 @@ func Delete(name string) (err error) {
 	if err != nil {
 		return
 	}
+	githistory.Record(author, "Delete site "+name, availablePath)
+
+	if siteModel != nil && (deleteDNS || ResolveNamespaceByID(siteModel.NamespaceID).DeletesDNSWithSite()) {
+		deleteDNSRecords(context.Background(), siteModel)
+	}
 
 	return
 }
@@ -0,0 +1,288 @@
+package site
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/dns"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gorm"
+)
+
+// autoProvisionTimeout bounds the provider calls made while a site is saved.
+const autoProvisionTimeout = 30 * time.Second
+
+var serverNamePattern = regexp.MustCompile(`(?m)^[ \t]*server_name\s+([^;#]+);`)
+
+// ServerNames returns the server_name values of a site configuration.
+func ServerNames(content string) []string {
+	var names []string
+	for _, match := range serverNamePattern.FindAllStringSubmatch(content, -1) {
+		names = append(names, strings.Fields(match[1])...)
+	}
+	return names
+}
+
+// DNSTarget returns the DNS record target configured on a namespace.
+func DNSTarget(namespace *model.Namespace) dns.SiteRecordTarget {
+	if namespace == nil {
+		return dns.SiteRecordTarget{}
+	}
+	return dns.SiteRecordTarget{
+		IPv4:  namespace.DNSTargetIPv4,
+		IPv6:  namespace.DNSTargetIPv6,
+		CNAME: namespace.DNSTargetCNAME,
+	}
+}
+
+// PlanDNSRecords proposes the DNS records the server names of a site need.
+// A nil target falls back to the target of the site's namespace.
+func PlanDNSRecords(ctx context.Context, name string, target *dns.SiteRecordTarget) (*dns.SiteRecordPlan, error) {
+	siteModel, content, err := loadSiteForDNS(name)
+	if err != nil {
+		return nil, err
+	}
+	return planDNSRecords(ctx, siteModel, content, target)
+}
+
+// ProvisionDNSRecords creates and renames the records planned for a site and
+// links them to it. The returned plan carries the outcome of every change.
+func ProvisionDNSRecords(ctx context.Context, name string, target *dns.SiteRecordTarget) (*dns.SiteRecordPlan, error) {
+	siteModel, content, err := loadSiteForDNS(name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planDNSRecords(ctx, siteModel, content, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyDNSRecords(ctx, siteModel, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// AutoProvisionDNSRecords provisions the records of a saved site when its
+// namespace does so without confirmation. Failures are logged only, the site
+// itself has been saved already.
+func AutoProvisionDNSRecords(ctx context.Context, name string) {
+	ctx, cancel := context.WithTimeout(ctx, autoProvisionTimeout)
+	defer cancel()
+
+	siteModel, content, err := loadSiteForDNS(name)
+	if err != nil {
+		logger.Warn("Failed to load site for DNS provisioning:", err)
+		return
+	}
+
+	namespace := ResolveNamespaceByID(siteModel.NamespaceID)
+	if namespace.EffectiveDNSProvisioning() != model.DNSProvisioningAuto {
+		return
+	}
+
+	plan, err := planDNSRecords(ctx, siteModel, content, nil)
+	if err != nil {
+		logger.Warn("Failed to plan DNS records for site", name, err)
+		return
+	}
+	if !plan.Pending() {
+		return
+	}
+	if err := applyDNSRecords(ctx, siteModel, plan); err != nil {
+		logger.Warn("Failed to provision DNS records for site", name, err)
+		return
+	}
+	for _, change := range plan.Changes {
+		if change.Error != "" {
+			logger.Warn("Failed to provision DNS record", change.Host, change.Type, change.Error)
+		}
+	}
+}
+
+// renameDNSRecords moves the managed record named after a renamed site to
+// the new name, for sites following the convention of being named after
+// their primary host. It only runs for namespaces provisioning automatically.
+func renameDNSRecords(ctx context.Context, siteModel *model.Site, oldName, newName string) {
+	namespace := ResolveNamespaceByID(siteModel.NamespaceID)
+	if namespace.EffectiveDNSProvisioning() != model.DNSProvisioningAuto {
+		return
+	}
+
+	oldHosts := dns.NormalizeSiteHosts([]string{oldName})
+	newHosts := dns.NormalizeSiteHosts([]string{newName})
+	if len(oldHosts) == 0 || len(newHosts) == 0 {
+		return
+	}
+
+	svc := dns.NewService()
+	oldDomain, oldRecordName, err := svc.MatchDomain(ctx, oldHosts[0])
+	if err != nil || oldDomain == nil {
+		return
+	}
+	newDomain, newRecordName, err := svc.MatchDomain(ctx, newHosts[0])
+	if err != nil || newDomain == nil || newDomain.ID != oldDomain.ID {
+		return
+	}
+
+	changed := false
+	for i, record := range siteModel.DNSRecords {
+		if !record.Managed || linkDomainID(siteModel, record) != oldDomain.ID || record.Name != oldRecordName {
+			continue
+		}
+
+		current, err := findRecord(ctx, svc, oldDomain.ID, record.ID)
+		if err != nil {
+			logger.Warn("Failed to load DNS record", record.ID, err)
+			continue
+		}
+		updated, err := svc.UpdateRecord(ctx, oldDomain.ID, record.ID, dns.RecordInput{
+			Type:    current.Type,
+			Name:    newRecordName,
+			Content: current.Content,
+			TTL:     current.TTL,
+			Proxied: current.Proxied,
+			Comment: current.Comment,
+		})
+		if err != nil {
+			logger.Warn("Failed to rename DNS record", record.ID, err)
+			continue
+		}
+
+		siteModel.DNSRecords[i].ID = updated.ID
+		siteModel.DNSRecords[i].Name = newRecordName
+		changed = true
+	}
+
+	if changed {
+		if err := query.Site.Save(siteModel); err != nil {
+			logger.Warn("Failed to save renamed DNS records:", err)
+		}
+	}
+}
+
+// deleteDNSRecords removes the managed records of a deleted site.
+func deleteDNSRecords(ctx context.Context, siteModel *model.Site) {
+	var domainID uint64
+	if siteModel.DNSDomainID != nil {
+		domainID = uint64(*siteModel.DNSDomainID)
+	}
+	for _, err := range dns.NewService().DeleteSiteRecords(ctx, siteModel.DNSRecords, domainID) {
+		logger.Warn("Failed to delete DNS record of site:", err)
+	}
+}
+
+func loadSiteForDNS(name string) (*model.Site, string, error) {
+	path, err := ResolveAvailablePath(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", ErrSiteNotFound
+		}
+		return nil, "", err
+	}
+
+	s := query.Site
+	siteModel, err := s.Where(s.Path.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		siteModel = &model.Site{Path: path}
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return siteModel, string(content), nil
+}
+
+func planDNSRecords(ctx context.Context, siteModel *model.Site, content string, target *dns.SiteRecordTarget) (*dns.SiteRecordPlan, error) {
+	if target == nil {
+		namespaceTarget := DNSTarget(ResolveNamespaceByID(siteModel.NamespaceID))
+		target = &namespaceTarget
+	}
+
+	var domainID uint64
+	if siteModel.DNSDomainID != nil {
+		domainID = uint64(*siteModel.DNSDomainID)
+	}
+	return dns.NewService().PlanSiteRecords(ctx, ServerNames(content), siteModel.DNSRecords, domainID, *target)
+}
+
+// applyDNSRecords executes plan and merges the resulting records into the
+// links of the site. Renamed records replace their previous link.
+func applyDNSRecords(ctx context.Context, siteModel *model.Site, plan *dns.SiteRecordPlan) error {
+	replaced := make(map[string]struct{})
+	for _, change := range plan.Changes {
+		if change.Action == dns.SiteRecordRename {
+			replaced[change.RecordID] = struct{}{}
+		}
+	}
+
+	applied := dns.NewService().ApplySiteRecords(ctx, plan)
+	for _, change := range plan.Changes {
+		if change.Action == dns.SiteRecordRename && change.Error != "" {
+			delete(replaced, change.RecordID)
+		}
+	}
+
+	links := make([]model.SiteDNSRecord, 0, len(siteModel.DNSRecords)+len(applied))
+	seen := make(map[string]struct{})
+	for _, record := range siteModel.DNSRecords {
+		if _, ok := replaced[record.ID]; ok {
+			continue
+		}
+		// Once links may span several domains, each carries its own.
+		if record.DomainID == 0 && siteModel.DNSDomainID != nil {
+			record.DomainID = uint64(*siteModel.DNSDomainID)
+		}
+		seen[record.ID] = struct{}{}
+		links = append(links, record)
+	}
+	for _, record := range applied {
+		if _, ok := seen[record.ID]; ok {
+			continue
+		}
+		seen[record.ID] = struct{}{}
+		links = append(links, record)
+	}
+
+	siteModel.DNSRecords = links
+	if siteModel.DNSDomainID == nil && len(links) > 0 {
+		domainID := int(links[0].DomainID)
+		siteModel.DNSDomainID = &domainID
+	}
+
+	return query.Site.Save(siteModel)
+}
+
+func linkDomainID(siteModel *model.Site, record model.SiteDNSRecord) uint64 {
+	if record.DomainID != 0 {
+		return record.DomainID
+	}
+	if siteModel.DNSDomainID != nil {
+		return uint64(*siteModel.DNSDomainID)
+	}
+	return 0
+}
+
+func findRecord(ctx context.Context, svc *dns.Service, domainID uint64, recordID string) (*dns.Record, error) {
+	records, err := svc.ListRecords(ctx, domainID, dns.RecordListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].ID == recordID {
+			return &records[i], nil
+		}
+	}
+	return nil, cosy.WrapErrorWithParams(dns.ErrRecordNotFound, recordID)
+}
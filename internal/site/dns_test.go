@@ -0,0 +1,21 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerNames(t *testing.T) {
+	content := `server {
+    listen 80;
+    server_name example.com www.example.com; # primary
+    # server_name commented.example.com;
+}
+server {
+	server_name
+		api.example.com;
+}`
+
+	require.Equal(t, []string{"example.com", "www.example.com", "api.example.com"}, ServerNames(content))
+}
@@ -4,11 +4,13 @@ import (
 	"os"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 )
 
 // Duplicate duplicates a site by copying the file
-func Duplicate(src, dst string) (err error) {
+func Duplicate(src, dst string, author githistory.Author) (err error) {
+	name := dst
 	src, err = ResolveAvailablePath(src)
 	if err != nil {
 		return err
@@ -38,5 +40,7 @@ func Duplicate(src, dst string) (err error) {
 		return
 	}
 
+	githistory.Record(author, "Duplicate site as "+name, dst)
+
 	return
 }
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"sync"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -15,7 +16,7 @@ import (
 )
 
 // Enable enables a site by creating a symlink in sites-enabled
-func Enable(name string) (err error) {
+func Enable(name string, author githistory.Author) (err error) {
 	configFilePath, err := ResolveAvailablePath(name)
 	if err != nil {
 		return err
@@ -67,6 +68,8 @@ func Enable(name string) (err error) {
 		})
 	}
 
+	githistory.Record(author, "Enable site "+name, enabledConfigFilePath)
+
 	go syncEnable(name)
 
 	return
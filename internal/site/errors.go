@@ -11,4 +11,5 @@ var (
 	ErrNginxTestFailed     = e.New(50004, "nginx test failed: {0}")
 	ErrNginxReloadFailed   = e.New(50005, "nginx reload failed: {0}")
 	ErrReadDirFailed       = e.New(50006, "read dir failed: {0}")
+	ErrNoTLSServer         = e.New(50007, "site has no server with TLS enabled")
 )
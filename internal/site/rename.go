@@ -1,6 +1,7 @@
 package site
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -16,7 +18,7 @@ import (
 	"github.com/uozi-tech/cosy/logger"
 )
 
-func Rename(oldName string, newName string) (err error) {
+func Rename(oldName string, newName string, author githistory.Author) (err error) {
 	oldPath, err := ResolveAvailablePath(oldName)
 	if err != nil {
 		return err
@@ -49,12 +51,17 @@ func Rename(oldName string, newName string) (err error) {
 		return
 	}
 
+	if siteModel, err := s.Where(s.Path.Eq(newPath)).First(); err == nil && len(siteModel.DNSRecords) > 0 {
+		renameDNSRecords(context.Background(), siteModel, oldName, newName)
+	}
+
 	// recreate a soft link
 	oldEnabledConfigFilePath, err := ResolveEnabledPath(oldName)
 	if err != nil {
 		return err
 	}
 
+	historyPaths := []string{oldPath, newPath}
 	relinked := false
 	if helper.SymbolLinkExists(oldEnabledConfigFilePath) {
 		_ = os.Remove(oldEnabledConfigFilePath)
@@ -63,6 +70,7 @@ func Rename(oldName string, newName string) (err error) {
 		if err != nil {
 			return err
 		}
+		historyPaths = append(historyPaths, oldEnabledConfigFilePath, newEnabledConfigFilePath)
 
 		err = os.Symlink(newPath, newEnabledConfigFilePath)
 		if err != nil {
@@ -100,6 +108,8 @@ func Rename(oldName string, newName string) (err error) {
 		"name":     newName,
 	})
 
+	githistory.Record(author, "Rename site "+oldName+" to "+newName, historyPaths...)
+
 	go syncRename(oldName, newName)
 
 	return
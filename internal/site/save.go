@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -16,8 +17,9 @@ import (
 	"github.com/uozi-tech/cosy/logger"
 )
 
-// Save saves a site configuration file
-func Save(name string, content string, overwrite bool, namespaceId uint64, syncNodeIds []uint64, postAction string) (err error) {
+// Save saves a site configuration file and records it in the git history as
+// author.
+func Save(name string, content string, overwrite bool, namespaceId uint64, syncNodeIds []uint64, postAction string, author githistory.Author) (err error) {
 	path, err := ResolveAvailablePath(name)
 	if err != nil {
 		return err
@@ -114,12 +116,15 @@ func Save(name string, content string, overwrite bool, namespaceId uint64, syncN
 		}
 	}
 
-	go syncSave(name, content)
+	githistory.Record(author, "Save site "+name, path)
+
+	go SyncSave(name, content)
 
 	return
 }
 
-func syncSave(name string, content string) {
+// SyncSave pushes the content of a site to the nodes it is synced to.
+func SyncSave(name string, content string) {
 	nodes, postSyncAction := getSyncData(name)
 
 	wg := &sync.WaitGroup{}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
 	appsettings "github.com/0xJacky/Nginx-UI/settings"
@@ -91,7 +92,7 @@ func setupSiteMutationTest(t *testing.T) (string, func()) {
 func TestSaveAllowsManagedSiteHostname(t *testing.T) {
 	confDir, waitForSyncQuery := setupSiteMutationTest(t)
 
-	err := Save("example.com", "server {\n    listen 80;\n}\n", true, 0, nil, "")
+	err := Save("example.com", "server {\n    listen 80;\n}\n", true, 0, nil, "", githistory.SystemAuthor)
 	if err != nil {
 		t.Fatalf("Save returned error: %v", err)
 	}
@@ -105,7 +106,7 @@ func TestSaveAllowsManagedSiteHostname(t *testing.T) {
 func TestSaveRejectsDangerousSiteExtension(t *testing.T) {
 	setupSiteMutationTest(t)
 
-	err := Save("evil.pl", "server {\n}\n", true, 0, nil, "")
+	err := Save("evil.pl", "server {\n}\n", true, 0, nil, "", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Save expected validation error")
 	}
@@ -122,7 +123,7 @@ func TestRenameAllowsManagedSiteHostname(t *testing.T) {
 		t.Fatalf("failed to seed site config: %v", err)
 	}
 
-	err := Rename("old.example.com", "new.example.com")
+	err := Rename("old.example.com", "new.example.com", githistory.SystemAuthor)
 	if err != nil {
 		t.Fatalf("Rename returned error: %v", err)
 	}
@@ -140,7 +141,7 @@ func TestRenameRejectsDangerousSiteExtension(t *testing.T) {
 		t.Fatalf("failed to seed site config: %v", err)
 	}
 
-	err := Rename("old.example.com", "evil.pl")
+	err := Rename("old.example.com", "evil.pl", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Rename expected validation error")
 	}
@@ -157,7 +158,7 @@ func TestDuplicateRejectsDangerousSiteExtension(t *testing.T) {
 		t.Fatalf("failed to seed site config: %v", err)
 	}
 
-	err := Duplicate("source.example.com", "copy.pl")
+	err := Duplicate("source.example.com", "copy.pl", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Duplicate expected validation error")
 	}
@@ -174,7 +175,7 @@ func TestDuplicateRejectsBinarySiteContent(t *testing.T) {
 		t.Fatalf("failed to seed site config: %v", err)
 	}
 
-	err := Duplicate("source.example.com", "copy.example.com")
+	err := Duplicate("source.example.com", "copy.example.com", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Duplicate expected validation error")
 	}
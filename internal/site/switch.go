@@ -0,0 +1,37 @@
+package site
+
+import (
+	"os"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+)
+
+// ExitMaintenance turns the maintenance mode of a site off when it is on, so
+// the site can then be enabled or disabled normally.
+func ExitMaintenance(name string) error {
+	maintenanceConfigPath, err := ResolveEnabledPath(name + MaintenanceSuffix)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(maintenanceConfigPath); err == nil {
+		return DisableMaintenance(name)
+	}
+	return nil
+}
+
+// EnterMaintenance disables a site when it is enabled and serves its
+// maintenance page instead.
+func EnterMaintenance(name string, author githistory.Author) error {
+	enabledConfigPath, err := ResolveEnabledPath(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(enabledConfigPath); err == nil {
+		if err := Disable(name, author); err != nil {
+			return err
+		}
+	}
+	return EnableMaintenance(name)
+}
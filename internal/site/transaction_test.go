@@ -3,9 +3,11 @@ package site
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/model"
 	appsettings "github.com/0xJacky/Nginx-UI/settings"
 	"github.com/stretchr/testify/assert"
@@ -35,7 +37,7 @@ func TestEnableRollsBackLinkWhenConfigTestFails(t *testing.T) {
 	require.NoError(t, os.WriteFile(availablePath, []byte("server {}\n"), 0o644))
 	appsettings.NginxSettings.TestConfigCmd = "false"
 
-	err := Enable("example.com")
+	err := Enable("example.com", githistory.SystemAuthor)
 
 	require.Error(t, err)
 	_, statErr := os.Lstat(enabledPath)
@@ -52,7 +54,7 @@ func TestEnableRestoresDisabledStateWhenReloadFails(t *testing.T) {
 	appsettings.NginxSettings.ReloadCmd = fmt.Sprintf(
 		"if [ ! -e %q ]; then touch %q; exit 1; fi", reloadMarker, reloadMarker)
 
-	err := Enable("example.com")
+	err := Enable("example.com", githistory.SystemAuthor)
 
 	require.Error(t, err)
 	_, statErr := os.Lstat(enabledPath)
@@ -70,7 +72,7 @@ func TestSaveRestoresEnabledConfigWhenTestFails(t *testing.T) {
 	require.NoError(t, os.Symlink(availablePath, enabledPath))
 	appsettings.NginxSettings.TestConfigCmd = "false"
 
-	err := Save("example.com", "server { listen 81; }\n", true, 0, nil, "")
+	err := Save("example.com", "server { listen 81; }\n", true, 0, nil, "", githistory.SystemAuthor)
 
 	require.Error(t, err)
 	content, readErr := os.ReadFile(availablePath)
@@ -93,7 +95,7 @@ func TestSaveRestoresAndReloadsPreviousConfigWhenReloadFails(t *testing.T) {
 	appsettings.NginxSettings.ReloadCmd = fmt.Sprintf(
 		"if [ ! -e %q ]; then touch %q; exit 1; fi", reloadMarker, reloadMarker)
 
-	err := Save("example.com", "server { listen 81; }\n", true, 0, nil, model.PostSyncActionReloadNginx)
+	err := Save("example.com", "server { listen 81; }\n", true, 0, nil, model.PostSyncActionReloadNginx, githistory.SystemAuthor)
 
 	require.Error(t, err)
 	content, readErr := os.ReadFile(availablePath)
@@ -102,3 +104,31 @@ func TestSaveRestoresAndReloadsPreviousConfigWhenReloadFails(t *testing.T) {
 	_, markerErr := os.Stat(reloadMarker)
 	assert.NoError(t, markerErr)
 }
+
+func TestSaveAndRenameRecordGitHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	confDir, waitForSyncQuery := setupSiteMutationTest(t)
+	originalHistory := *appsettings.GitHistorySettings
+	t.Cleanup(func() { *appsettings.GitHistorySettings = originalHistory })
+	appsettings.GitHistorySettings.Enabled = true
+	appsettings.GitHistorySettings.RepositoryPath = filepath.Join(t.TempDir(), "history.git")
+	require.NoError(t, githistory.Init())
+
+	author := githistory.Author{Name: "alice", Email: "user-2@nginx-ui"}
+	require.NoError(t, Save("example.com", "server { listen 80; }\n", true, 0, nil, "", author))
+	waitForSyncQuery()
+	require.NoError(t, Rename("example.com", "www.example.com", author))
+	waitForSyncQuery()
+
+	commits, err := githistory.Log("", 0, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(commits), 2)
+	assert.Equal(t, "Rename site example.com to www.example.com", commits[0].Message)
+	assert.Equal(t, "Save site example.com", commits[1].Message)
+	assert.Equal(t, "alice", commits[0].AuthorName)
+
+	_, err = os.Stat(filepath.Join(confDir, "sites-available", "www.example.com"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package sitedeploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xJacky/Nginx-UI/internal/sitecheck"
+	"github.com/0xJacky/Nginx-UI/internal/trafficalert"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+)
+
+var (
+	measure         = trafficalert.Measure
+	checkSiteHealth = checkSiteURLs
+)
+
+// evaluate checks the step that just ended. The error ratio and latency are
+// measured over the step on the site's access log, so they cover the traffic
+// of both targets. A step with fewer requests than MinRequests passes.
+func evaluate(ctx context.Context, d *model.SiteDeployment) model.SiteDeploymentCheck {
+	now := deployNow()
+	check := model.SiteDeploymentCheck{
+		Step:      d.CurrentStep,
+		Weight:    d.Weight,
+		CheckedAt: now,
+		Passed:    true,
+	}
+
+	for _, threshold := range []struct {
+		metric model.TrafficAlertMetric
+		limit  float64
+		value  **float64
+		label  string
+	}{
+		{model.TrafficAlertMetricErrorRatio, d.MaxErrorRatio, &check.ErrorRatio, "5xx ratio %.2f%% is above %.2f%%"},
+		{model.TrafficAlertMetricLatency, d.MaxLatency, &check.Latency, "p95 request time %.3fs is above %.3fs"},
+	} {
+		if threshold.limit <= 0 {
+			continue
+		}
+		m, err := measure(ctx, &model.TrafficAlertRule{
+			LogPath:       d.LogPath,
+			Metric:        threshold.metric,
+			Percentile:    95,
+			WindowSeconds: d.StepIntervalSeconds,
+			MinRequests:   d.MinRequests,
+		}, now)
+		if err != nil {
+			// Without the numbers the step cannot be verified.
+			check.Passed = false
+			check.Message = err.Error()
+			return check
+		}
+		check.Requests = m.Requests
+		if m.NoData {
+			continue
+		}
+		value := m.Value
+		*threshold.value = &value
+		if value > threshold.limit {
+			check.Passed = false
+			check.Message = fmt.Sprintf(threshold.label, value, threshold.limit)
+			return check
+		}
+	}
+
+	if d.HealthCheck {
+		healthy := true
+		if err := checkSiteHealth(ctx, d.SiteName); err != nil {
+			healthy = false
+			check.Passed = false
+			check.Message = err.Error()
+		}
+		check.Healthy = &healthy
+	}
+	return check
+}
+
+// checkSiteURLs probes the health checked URLs of a site right away instead
+// of waiting for the next round of the site checker.
+func checkSiteURLs(ctx context.Context, siteName string) error {
+	sc := query.SiteConfig
+	configs, err := sc.Where(sc.SiteName.Eq(siteName), sc.HealthCheckEnabled.Is(true)).Find()
+	if err != nil {
+		return err
+	}
+
+	options := sitecheck.DefaultCheckOptions()
+	options.CheckFavicon = false
+	checker := sitecheck.NewSiteChecker(options)
+	for _, config := range configs {
+		info, err := checker.CheckSite(ctx, config.GetURL())
+		if err != nil {
+			return err
+		}
+		if info.Status != sitecheck.StatusOnline {
+			return fmt.Errorf("health check of %s is %s: %s", config.GetURL(), info.Status, info.Error)
+		}
+	}
+	return nil
+}
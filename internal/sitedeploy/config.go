@@ -0,0 +1,162 @@
+package sitedeploy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+const splitHeader = "# Managed by Nginx UI deployments, changes will be overwritten.\n"
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// splitVariable is the variable split_clients sets to the target of a request.
+func splitVariable(siteName string) string {
+	return "$nginx_ui_deploy_" + nonIdentifierRegexp.ReplaceAllString(siteName, "_")
+}
+
+// SplitPath returns the include holding the split_clients block of a site. It
+// lives in conf.d, which the http block includes.
+func SplitPath(siteName string) string {
+	return nginx.GetConfPath("conf.d", "nginx-ui-deploy-"+nonIdentifierRegexp.ReplaceAllString(siteName, "_")+".conf")
+}
+
+// renderSplit weights the traffic between both targets by client address, so
+// a client keeps hitting the same target while the weight stays the same.
+func renderSplit(siteName, oldTarget, newTarget string, weight int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(splitHeader)
+	fmt.Fprintf(&buf, "split_clients \"${remote_addr}\" %s {\n", splitVariable(siteName))
+	switch {
+	case weight >= 100:
+		fmt.Fprintf(&buf, "    * %s;\n", newTarget)
+	case weight <= 0:
+		fmt.Fprintf(&buf, "    * %s;\n", oldTarget)
+	default:
+		fmt.Fprintf(&buf, "    %d%% %s;\n", weight, newTarget)
+		fmt.Fprintf(&buf, "    * %s;\n", oldTarget)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// proxyPassRegexp matches the proxy_pass directives pointing at target. Only
+// directives without a URI are matched, since nginx passes the request URI
+// differently once proxy_pass contains a variable.
+func proxyPassRegexp(target string) *regexp.Regexp {
+	return regexp.MustCompile(`(\bproxy_pass\s+)(https?://)` + regexp.QuoteMeta(target) + `(\s*;)`)
+}
+
+// replaceProxyPass points the proxy_pass directives at from to to and reports
+// how many were replaced.
+func replaceProxyPass(content, from, to string) (string, int) {
+	count := 0
+	re := proxyPassRegexp(from)
+	replaced := re.ReplaceAllStringFunc(content, func(match string) string {
+		count++
+		m := re.FindStringSubmatch(match)
+		return m[1] + m[2] + to + m[3]
+	})
+	return replaced, count
+}
+
+type fileSnapshot struct {
+	path    string
+	exists  bool
+	content []byte
+}
+
+func captureFile(path string) (fileSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fileSnapshot{path: path}, nil
+	}
+	if err != nil {
+		return fileSnapshot{}, err
+	}
+	return fileSnapshot{path: path, exists: true, content: content}, nil
+}
+
+func (s fileSnapshot) restore() error {
+	if !s.exists {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(s.path, s.content, 0644)
+}
+
+// fileChange is the new content of a file, nil content removes it. Changes of
+// the site itself are recorded in the git history, the split include is
+// regenerated on every step and is not.
+type fileChange struct {
+	path    string
+	content []byte
+	history bool
+}
+
+// applyChanges writes the files, then tests and reloads nginx. When nginx
+// rejects the result all files are restored, otherwise the changes with
+// history are recorded as author with message.
+func applyChanges(author githistory.Author, message string, changes ...fileChange) error {
+	snapshots := make([]fileSnapshot, 0, len(changes))
+	restore := func() {
+		for _, snapshot := range snapshots {
+			if err := snapshot.restore(); err != nil {
+				logger.Errorf("Failed to restore %s: %v", snapshot.path, err)
+			}
+		}
+	}
+
+	for _, change := range changes {
+		snapshot, err := captureFile(change.path)
+		if err != nil {
+			restore()
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+
+		if change.content == nil {
+			err = os.Remove(change.path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = os.WriteFile(change.path, change.content, 0644)
+		}
+		if err != nil {
+			restore()
+			return err
+		}
+	}
+
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		restore()
+		return cosy.WrapErrorWithParams(ErrNginxTestFailed, result.GetOutput())
+	}
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		restore()
+		if retry := nginx.Control(nginx.Reload); retry.IsError() {
+			logger.Errorf("Failed to reload the restored configuration: %s", retry.GetOutput())
+		}
+		return cosy.WrapErrorWithParams(ErrNginxReloadFailed, result.GetOutput())
+	}
+
+	var paths []string
+	for _, change := range changes {
+		if change.history {
+			paths = append(paths, change.path)
+		}
+	}
+	if len(paths) > 0 {
+		githistory.Record(author, message, paths...)
+	}
+	return nil
+}
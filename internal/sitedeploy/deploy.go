@@ -0,0 +1,347 @@
+package sitedeploy
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/event"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var (
+	deployNow = time.Now
+
+	// mutex serializes the state changes of deployments, between the runners
+	// and manual rollbacks.
+	mutex   sync.Mutex
+	baseCtx = context.Background()
+	runners = map[uint64]context.CancelFunc{}
+)
+
+// Init resumes the deployments that were running when Nginx UI stopped. Each
+// one waits a full step interval before its next check.
+func Init(ctx context.Context) {
+	mutex.Lock()
+	baseCtx = ctx
+	mutex.Unlock()
+
+	deployments, err := getRunningDeployments()
+	if err != nil {
+		logger.Errorf("Failed to load running deployments: %v", err)
+		return
+	}
+	for _, d := range deployments {
+		mutex.Lock()
+		startRunner(d)
+		mutex.Unlock()
+	}
+}
+
+// Start validates a deployment, points the proxy_pass of the site at the
+// split between both targets with the weight of the first step and starts
+// ramping it up. author is recorded on the change of the site.
+func Start(d *model.SiteDeployment, author githistory.Author) error {
+	if site.IsRemoteDeploy(d.SiteName) {
+		return cosy.WrapErrorWithParams(ErrRemoteDeploy, d.SiteName)
+	}
+	sitePath, err := site.ResolveAvailablePath(d.SiteName)
+	if err != nil {
+		return err
+	}
+	enabledPath, err := site.ResolveEnabledPath(d.SiteName)
+	if err != nil {
+		return err
+	}
+	if !helper.FileExists(enabledPath) {
+		return cosy.WrapErrorWithParams(ErrSiteNotEnabled, d.SiteName)
+	}
+
+	if d.LogPath == "" && (d.MaxErrorRatio > 0 || d.MaxLatency > 0) {
+		d.LogPath = siteAccessLog(d.SiteName)
+	}
+	if err := Normalize(d); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	q := query.SiteDeployment
+	running, err := q.Where(q.SiteName.Eq(d.SiteName), q.Status.Eq(string(model.SiteDeploymentRunning))).Count()
+	if err != nil {
+		return err
+	}
+	if running > 0 {
+		return cosy.WrapErrorWithParams(ErrDeploymentRunning, d.SiteName)
+	}
+
+	original, err := os.ReadFile(sitePath)
+	if err != nil {
+		return err
+	}
+	content, count := replaceProxyPass(string(original), d.OldTarget, splitVariable(d.SiteName))
+	if count == 0 {
+		return cosy.WrapErrorWithParams(ErrTargetNotFound, d.OldTarget)
+	}
+
+	now := deployNow()
+	d.Status = model.SiteDeploymentRunning
+	d.OriginalConfig = string(original)
+	d.CurrentStep = 0
+	d.Weight = d.Steps[0]
+	d.StepStartedAt = &now
+	d.Checks = nil
+
+	err = applyChanges(author, "Start deployment of site "+d.SiteName+" to "+d.NewTarget,
+		fileChange{path: SplitPath(d.SiteName), content: renderSplit(d.SiteName, d.OldTarget, d.NewTarget, d.Weight)},
+		fileChange{path: sitePath, content: []byte(content), history: true},
+	)
+	if err != nil {
+		return err
+	}
+	if err := q.Create(d); err != nil {
+		// Without a record nothing would ever finish the deployment.
+		restoreErr := applyChanges(author, "Restore site "+d.SiteName,
+			fileChange{path: sitePath, content: original, history: true},
+			fileChange{path: SplitPath(d.SiteName)},
+		)
+		if restoreErr != nil {
+			logger.Errorf("Failed to restore site %s: %v", d.SiteName, restoreErr)
+		}
+		return err
+	}
+
+	publish(d, "")
+	startRunner(d)
+	return nil
+}
+
+// siteAccessLog returns the access log of a site, preferring one it declares
+// itself.
+func siteAccessLog(siteName string) string {
+	logs, err := site.GetLogs(siteName)
+	if err != nil {
+		return ""
+	}
+	path := ""
+	for _, entry := range logs {
+		if entry.Type != "access" || !entry.Valid {
+			continue
+		}
+		if !entry.Inherited {
+			return entry.Path
+		}
+		path = entry.Path
+	}
+	return path
+}
+
+// startRunner must be called with mutex held.
+func startRunner(d *model.SiteDeployment) {
+	if _, ok := runners[d.ID]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(baseCtx)
+	runners[d.ID] = cancel
+	go run(ctx, d.ID, time.Duration(d.StepIntervalSeconds)*time.Second)
+}
+
+// stopRunner must be called with mutex held.
+func stopRunner(id uint64) {
+	if cancel, ok := runners[id]; ok {
+		cancel()
+		delete(runners, id)
+	}
+}
+
+func run(ctx context.Context, id uint64, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if done := step(ctx, id); done {
+			return
+		}
+	}
+}
+
+// step checks the current step of a deployment and moves on to the next one,
+// finishes it or rolls it back. It reports whether the deployment is over.
+func step(ctx context.Context, id uint64) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	d, err := GetDeployment(id)
+	if err != nil || d.Status != model.SiteDeploymentRunning {
+		return true
+	}
+	// The checks can take a while, so they run without holding the mutex and
+	// the outcome is dropped when the deployment changed meanwhile.
+	check := evaluate(ctx, d)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	current, err := GetDeployment(id)
+	if err != nil {
+		logger.Errorf("Failed to load deployment %d: %v", id, err)
+		stopRunner(id)
+		return true
+	}
+	if current.Status != model.SiteDeploymentRunning || current.CurrentStep != d.CurrentStep {
+		return current.Status != model.SiteDeploymentRunning
+	}
+	d = current
+
+	d.Checks = append(d.Checks, check)
+	if !check.Passed {
+		rollback(d, check.Message, githistory.SystemAuthor)
+		return true
+	}
+
+	if d.CurrentStep == len(d.Steps)-1 {
+		finish(d)
+		return true
+	}
+
+	d.CurrentStep++
+	d.Weight = d.Steps[d.CurrentStep]
+	now := deployNow()
+	d.StepStartedAt = &now
+	err = applyChanges(githistory.SystemAuthor, "Shift traffic of site "+d.SiteName, fileChange{
+		path:    SplitPath(d.SiteName),
+		content: renderSplit(d.SiteName, d.OldTarget, d.NewTarget, d.Weight),
+	})
+	if err != nil {
+		rollback(d, err.Error(), githistory.SystemAuthor)
+		return true
+	}
+	save(d)
+	publish(d, check.Message)
+	return false
+}
+
+// finish points the site at the new target for good.
+func finish(d *model.SiteDeployment) {
+	stopRunner(d.ID)
+
+	sitePath, err := site.ResolveAvailablePath(d.SiteName)
+	if err == nil {
+		var content []byte
+		content, err = os.ReadFile(sitePath)
+		if err == nil {
+			final, _ := replaceProxyPass(string(content), splitVariable(d.SiteName), d.NewTarget)
+			err = applyChanges(githistory.SystemAuthor, "Finish deployment of site "+d.SiteName+" to "+d.NewTarget,
+				fileChange{path: sitePath, content: []byte(final), history: true},
+				fileChange{path: SplitPath(d.SiteName)},
+			)
+		}
+	}
+
+	now := deployNow()
+	d.FinishedAt = &now
+	if err != nil {
+		// The split still sends all traffic to the new target, so the site
+		// keeps working until someone looks at it.
+		d.Status = model.SiteDeploymentFailed
+		d.Reason = err.Error()
+		save(d)
+		publish(d, d.Reason)
+		notification.Error("Deployment Failed", "Deployment of %{site} to %{target} could not be finalized: %{reason}", details(d))
+		return
+	}
+
+	d.Status = model.SiteDeploymentSucceeded
+	save(d)
+	publish(d, "")
+	notification.Success("Deployment Completed", "All traffic of %{site} is now served by %{target}", details(d))
+}
+
+// rollback restores the site configuration from before the deployment.
+func rollback(d *model.SiteDeployment, reason string, author githistory.Author) {
+	stopRunner(d.ID)
+
+	sitePath, err := site.ResolveAvailablePath(d.SiteName)
+	if err == nil {
+		err = applyChanges(author, "Roll back deployment of site "+d.SiteName,
+			fileChange{path: sitePath, content: []byte(d.OriginalConfig), history: true},
+			fileChange{path: SplitPath(d.SiteName)},
+		)
+	}
+
+	now := deployNow()
+	d.FinishedAt = &now
+	d.Weight = 0
+	d.Reason = reason
+	if err != nil {
+		d.Status = model.SiteDeploymentFailed
+		d.Reason = strings.Join([]string{reason, err.Error()}, ": ")
+		save(d)
+		publish(d, d.Reason)
+		notification.Error("Deployment Failed", "Deployment of %{site} to %{target} could not be rolled back: %{reason}", details(d))
+		return
+	}
+
+	d.Status = model.SiteDeploymentRolledBack
+	save(d)
+	publish(d, reason)
+	notification.Warning("Deployment Rolled Back", "Deployment of %{site} to %{target} was rolled back: %{reason}", details(d))
+}
+
+// Rollback stops a running deployment and restores the site as author.
+func Rollback(id uint64, reason string, author githistory.Author) (*model.SiteDeployment, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	d, err := GetDeployment(id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != model.SiteDeploymentRunning {
+		return nil, ErrNotRunning
+	}
+	rollback(d, reason, author)
+	return d, nil
+}
+
+func save(d *model.SiteDeployment) {
+	if err := query.SiteDeployment.Save(d); err != nil {
+		logger.Errorf("Failed to save deployment %d: %v", d.ID, err)
+	}
+}
+
+func details(d *model.SiteDeployment) map[string]any {
+	return map[string]any{
+		"site":   d.SiteName,
+		"target": d.NewTarget,
+		"reason": d.Reason,
+	}
+}
+
+func publish(d *model.SiteDeployment, message string) {
+	event.Publish(event.Event{
+		Type: event.TypeSiteDeployment,
+		Data: event.SiteDeploymentData{
+			ID:       d.ID,
+			SiteName: d.SiteName,
+			Status:   string(d.Status),
+			Step:     d.CurrentStep,
+			Steps:    len(d.Steps),
+			Weight:   d.Weight,
+			Message:  message,
+		},
+	})
+}
@@ -0,0 +1,87 @@
+package sitedeploy
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+const defaultStepIntervalSeconds = 300
+
+var defaultCanarySteps = []int{10, 25, 50, 100}
+
+// targetRegexp accepts what proxy_pass can resolve without a resolver: the
+// name of an upstream block or an address with port.
+var targetRegexp = regexp.MustCompile(`^(\[[0-9A-Fa-f:.]+\]|[A-Za-z0-9_.-]+)(:\d{1,5})?$`)
+
+// Normalize validates a deployment request and fills in the defaults of unset
+// fields.
+func Normalize(d *model.SiteDeployment) error {
+	switch d.Strategy {
+	case "":
+		d.Strategy = model.SiteDeploymentCanary
+	case model.SiteDeploymentCanary, model.SiteDeploymentBlueGreen:
+	default:
+		return cosy.WrapErrorWithParams(ErrUnknownStrategy, string(d.Strategy))
+	}
+
+	for _, target := range []string{d.OldTarget, d.NewTarget} {
+		if !targetRegexp.MatchString(target) {
+			return cosy.WrapErrorWithParams(ErrInvalidTarget, target)
+		}
+	}
+	if d.OldTarget == d.NewTarget {
+		return ErrSameTarget
+	}
+
+	if d.Strategy == model.SiteDeploymentBlueGreen {
+		d.Steps = []int{100}
+	} else if len(d.Steps) == 0 {
+		d.Steps = slices.Clone(defaultCanarySteps)
+	}
+	for i, weight := range d.Steps {
+		if weight <= 0 || weight > 100 || (i > 0 && weight <= d.Steps[i-1]) {
+			return ErrInvalidSteps
+		}
+	}
+	if d.Steps[len(d.Steps)-1] != 100 {
+		return ErrInvalidSteps
+	}
+
+	for _, field := range []struct {
+		name  string
+		value float64
+	}{
+		{"step_interval_seconds", float64(d.StepIntervalSeconds)},
+		{"max_error_ratio", d.MaxErrorRatio},
+		{"max_latency", d.MaxLatency},
+		{"min_requests", float64(d.MinRequests)},
+	} {
+		if field.value < 0 {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, field.name)
+		}
+	}
+	if d.StepIntervalSeconds == 0 {
+		d.StepIntervalSeconds = defaultStepIntervalSeconds
+	}
+
+	if (d.MaxErrorRatio > 0 || d.MaxLatency > 0) && !utils.IsValidLogPath(d.LogPath) {
+		return cosy.WrapErrorWithParams(ErrInvalidLogPath, d.LogPath)
+	}
+	return nil
+}
+
+// GetDeployment loads a deployment by id.
+func GetDeployment(id uint64) (*model.SiteDeployment, error) {
+	q := query.SiteDeployment
+	return q.Where(q.ID.Eq(id)).First()
+}
+
+func getRunningDeployments() (deployments []*model.SiteDeployment, err error) {
+	q := query.SiteDeployment
+	return q.Where(q.Status.Eq(string(model.SiteDeploymentRunning))).Find()
+}
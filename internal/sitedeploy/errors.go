@@ -0,0 +1,20 @@
+package sitedeploy
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                    = cosy.NewErrorScope("site_deploy")
+	ErrDeploymentRunning = e.New(40001, "site {0} already has a running deployment")
+	ErrTargetNotFound    = e.New(40002, "no proxy_pass to {0} without a URI was found in the site")
+	ErrInvalidTarget     = e.New(40003, "invalid target: {0}, use an upstream name or an address with port")
+	ErrSameTarget        = e.New(40004, "the new target must differ from the old one")
+	ErrInvalidSteps      = e.New(40005, "steps must be increasing percentages ending at 100")
+	ErrInvalidValue      = e.New(40006, "{0} must not be negative")
+	ErrInvalidLogPath    = e.New(40007, "log path is not under the whitelist: {0}")
+	ErrRemoteDeploy      = e.New(40008, "site {0} is deployed to remote nodes only")
+	ErrSiteNotEnabled    = e.New(40009, "site {0} is not enabled")
+	ErrNotRunning        = e.New(40010, "the deployment is not running")
+	ErrUnknownStrategy   = e.New(40011, "unknown deployment strategy: {0}")
+	ErrNginxTestFailed   = e.New(50001, "nginx test failed, the configuration was restored: {0}")
+	ErrNginxReloadFailed = e.New(50002, "nginx reload failed, the configuration was restored: {0}")
+)
@@ -0,0 +1,259 @@
+package sitedeploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/trafficalert"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const siteConfig = `server {
+    listen 80;
+    server_name example.com;
+    location / {
+        proxy_pass http://app_v1;
+    }
+    location /api/ {
+        proxy_pass http://app_v1/api/;
+    }
+}
+`
+
+func TestRenderSplit(t *testing.T) {
+	assert.Equal(t, splitHeader+`split_clients "${remote_addr}" $nginx_ui_deploy_example_com {
+    10% app_v2;
+    * app_v1;
+}
+`, string(renderSplit("example.com", "app_v1", "app_v2", 10)))
+
+	assert.Equal(t, splitHeader+`split_clients "${remote_addr}" $nginx_ui_deploy_example_com {
+    * 127.0.0.1:8081;
+}
+`, string(renderSplit("example.com", "127.0.0.1:8080", "127.0.0.1:8081", 100)))
+}
+
+func TestReplaceProxyPassSkipsDirectivesWithURI(t *testing.T) {
+	content, count := replaceProxyPass(siteConfig, "app_v1", "$nginx_ui_deploy_example_com")
+	assert.Equal(t, 1, count)
+	assert.Contains(t, content, "proxy_pass http://$nginx_ui_deploy_example_com;")
+	assert.Contains(t, content, "proxy_pass http://app_v1/api/;")
+
+	_, count = replaceProxyPass(siteConfig, "app", "app_v2")
+	assert.Equal(t, 0, count, "targets match as a whole")
+}
+
+func TestNormalizeFillsDefaultsAndRejectsInvalidDeployments(t *testing.T) {
+	d := &model.SiteDeployment{OldTarget: "app_v1", NewTarget: "app_v2"}
+	require.NoError(t, Normalize(d))
+	assert.Equal(t, model.SiteDeploymentCanary, d.Strategy)
+	assert.Equal(t, defaultCanarySteps, d.Steps)
+	assert.Equal(t, defaultStepIntervalSeconds, d.StepIntervalSeconds)
+
+	d = &model.SiteDeployment{Strategy: model.SiteDeploymentBlueGreen, OldTarget: "[::1]:8080", NewTarget: "[::1]:8081", Steps: []int{50}}
+	require.NoError(t, Normalize(d))
+	assert.Equal(t, []int{100}, d.Steps)
+
+	d = &model.SiteDeployment{OldTarget: "http://app_v1", NewTarget: "app_v2"}
+	requireErrorCode(t, Normalize(d), ErrInvalidTarget)
+
+	d = &model.SiteDeployment{OldTarget: "app_v1", NewTarget: "app_v1"}
+	requireErrorCode(t, Normalize(d), ErrSameTarget)
+
+	for _, steps := range [][]int{{10, 50}, {50, 10, 100}, {0, 100}} {
+		d = &model.SiteDeployment{OldTarget: "app_v1", NewTarget: "app_v2", Steps: steps}
+		requireErrorCode(t, Normalize(d), ErrInvalidSteps)
+	}
+
+	d = &model.SiteDeployment{OldTarget: "app_v1", NewTarget: "app_v2", MaxLatency: -1}
+	requireErrorCode(t, Normalize(d), ErrInvalidValue)
+}
+
+func TestCanaryRampsUpAndFinishes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	confDir, _ := setupDeployTest(t)
+	originalHistory := *settings.GitHistorySettings
+	t.Cleanup(func() { *settings.GitHistorySettings = originalHistory })
+	settings.GitHistorySettings.Enabled = true
+	settings.GitHistorySettings.RepositoryPath = filepath.Join(t.TempDir(), "history.git")
+	require.NoError(t, githistory.Init())
+	useMeasurement(t, &trafficalert.Measurement{Value: 1, Requests: 100})
+
+	d := &model.SiteDeployment{
+		SiteName:      "example.com",
+		OldTarget:     "app_v1",
+		NewTarget:     "app_v2",
+		Steps:         []int{20, 100},
+		LogPath:       filepath.Join(confDir, "access.log"),
+		MaxErrorRatio: 5,
+	}
+	require.NoError(t, Start(d, githistory.SystemAuthor))
+	assert.Contains(t, readFile(t, filepath.Join(confDir, "sites-available", "example.com")), "proxy_pass http://$nginx_ui_deploy_example_com;")
+	assert.Contains(t, readFile(t, SplitPath("example.com")), "20% app_v2;")
+
+	requireErrorCode(t, Start(&model.SiteDeployment{SiteName: "example.com", OldTarget: "app_v1", NewTarget: "app_v3"}, githistory.SystemAuthor), ErrDeploymentRunning)
+
+	assert.False(t, step(context.Background(), d.ID))
+	d, err := GetDeployment(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, d.Weight)
+	assert.Contains(t, readFile(t, SplitPath("example.com")), "* app_v2;")
+
+	assert.True(t, step(context.Background(), d.ID))
+	d, err = GetDeployment(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.SiteDeploymentSucceeded, d.Status)
+	assert.Len(t, d.Checks, 2)
+	assert.Equal(t, 1.0, *d.Checks[0].ErrorRatio)
+
+	content := readFile(t, filepath.Join(confDir, "sites-available", "example.com"))
+	assert.Contains(t, content, "proxy_pass http://app_v2;")
+	assert.Contains(t, content, "proxy_pass http://app_v1/api/;")
+	assert.NoFileExists(t, SplitPath("example.com"))
+
+	commits, err := githistory.Log("", 0, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(commits), 2, "both site rewrites are in the history")
+	assert.Equal(t, "Finish deployment of site example.com to app_v2", commits[0].Message)
+	assert.Equal(t, "Start deployment of site example.com to app_v2", commits[1].Message)
+}
+
+func TestRollbackWhenThresholdIsBreached(t *testing.T) {
+	confDir, db := setupDeployTest(t)
+	useMeasurement(t, &trafficalert.Measurement{Value: 12.5, Requests: 100})
+
+	d := &model.SiteDeployment{
+		SiteName:      "example.com",
+		OldTarget:     "app_v1",
+		NewTarget:     "app_v2",
+		LogPath:       filepath.Join(confDir, "access.log"),
+		MaxErrorRatio: 5,
+	}
+	require.NoError(t, Start(d, githistory.SystemAuthor))
+
+	assert.True(t, step(context.Background(), d.ID))
+	d, err := GetDeployment(d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.SiteDeploymentRolledBack, d.Status)
+	assert.Equal(t, "5xx ratio 12.50% is above 5.00%", d.Reason)
+	assert.Equal(t, siteConfig, readFile(t, filepath.Join(confDir, "sites-available", "example.com")))
+	assert.NoFileExists(t, SplitPath("example.com"))
+
+	var notifications []model.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, model.NotificationWarning, notifications[0].Type)
+}
+
+func TestManualRollback(t *testing.T) {
+	confDir, _ := setupDeployTest(t)
+
+	d := &model.SiteDeployment{SiteName: "example.com", OldTarget: "app_v1", NewTarget: "app_v2"}
+	require.NoError(t, Start(d, githistory.SystemAuthor))
+
+	d, err := Rollback(d.ID, "manual", githistory.SystemAuthor)
+	require.NoError(t, err)
+	assert.Equal(t, model.SiteDeploymentRolledBack, d.Status)
+	assert.Equal(t, siteConfig, readFile(t, filepath.Join(confDir, "sites-available", "example.com")))
+
+	_, err = Rollback(d.ID, "manual", githistory.SystemAuthor)
+	requireErrorCode(t, err, ErrNotRunning)
+}
+
+func TestStartRestoresSiteWhenNginxRejectsIt(t *testing.T) {
+	confDir, _ := setupDeployTest(t)
+	settings.NginxSettings.TestConfigCmd = "false"
+
+	err := Start(&model.SiteDeployment{SiteName: "example.com", OldTarget: "app_v1", NewTarget: "app_v2"}, githistory.SystemAuthor)
+	requireErrorCode(t, err, ErrNginxTestFailed)
+	assert.Equal(t, siteConfig, readFile(t, filepath.Join(confDir, "sites-available", "example.com")))
+	assert.NoFileExists(t, SplitPath("example.com"))
+}
+
+func setupDeployTest(t *testing.T) (string, *gorm.DB) {
+	t.Helper()
+
+	originalDB := model.UseDB()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	originalWhiteList := settings.NginxSettings.LogDirWhiteList
+	ctx, cancel := context.WithCancel(context.Background())
+	baseCtx = ctx
+	t.Cleanup(func() {
+		cancel()
+		mutex.Lock()
+		runners = map[uint64]context.CancelFunc{}
+		baseCtx = context.Background()
+		mutex.Unlock()
+		model.Use(originalDB)
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+		settings.NginxSettings.LogDirWhiteList = originalWhiteList
+	})
+
+	confDir := t.TempDir()
+	for _, dir := range []string{"sites-available", "sites-enabled", "conf.d"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(confDir, dir), 0755))
+	}
+	sitePath := filepath.Join(confDir, "sites-available", "example.com")
+	require.NoError(t, os.WriteFile(sitePath, []byte(siteConfig), 0644))
+	require.NoError(t, os.Symlink(sitePath, filepath.Join(confDir, "sites-enabled", "example.com")))
+
+	settings.NginxSettings.ConfigDir = confDir
+	settings.NginxSettings.ReloadCmd = "true"
+	settings.NginxSettings.RestartCmd = "true"
+	settings.NginxSettings.TestConfigCmd = "true"
+	settings.NginxSettings.LogDirWhiteList = []string{confDir}
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Site{}, &model.ConfigBackup{},
+		&model.Notification{}, &model.SiteConfig{}, &model.SiteDeployment{}))
+	model.Use(db)
+	query.SetDefault(db)
+	query.SetDefault(db)
+
+	return confDir, db
+}
+
+func useMeasurement(t *testing.T, m *trafficalert.Measurement) {
+	t.Helper()
+	original := measure
+	measure = func(context.Context, *model.TrafficAlertRule, time.Time) (*trafficalert.Measurement, error) {
+		return m, nil
+	}
+	t.Cleanup(func() { measure = original })
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
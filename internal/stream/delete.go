@@ -6,6 +6,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
 	"github.com/0xJacky/Nginx-UI/internal/notification"
@@ -15,7 +16,7 @@ import (
 )
 
 // Delete deletes a site by removing the file in sites-available
-func Delete(name string) (err error) {
+func Delete(name string, author githistory.Author) (err error) {
 	availablePath, err := ResolveAvailablePath(name)
 	if err != nil {
 		return err
@@ -61,6 +62,8 @@ func Delete(name string) (err error) {
 		return
 	}
 
+	githistory.Record(author, "Delete stream "+name, availablePath)
+
 	return
 }
 
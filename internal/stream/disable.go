@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"sync"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -16,7 +17,7 @@ import (
 )
 
 // Disable disables a site by removing the symlink in sites-enabled
-func Disable(name string) (err error) {
+func Disable(name string, author githistory.Author) (err error) {
 	enabledConfigFilePath, err := resolveEnabledSymlinkPath(name)
 	if err != nil {
 		return err
@@ -57,6 +58,8 @@ func Disable(name string) (err error) {
 		return res.GetError()
 	}
 
+	githistory.Record(author, "Disable stream "+name, enabledConfigFilePath)
+
 	go syncDisable(name)
 
 	return
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
@@ -86,7 +87,7 @@ func GetStreamInfo(name string) (*Info, error) {
 }
 
 // SaveStreamConfig saves stream configuration with database update
-func SaveStreamConfig(name, content string, namespaceID uint64, syncNodeIDs []uint64, overwrite bool, postAction string) error {
+func SaveStreamConfig(name, content string, namespaceID uint64, syncNodeIDs []uint64, overwrite bool, postAction string, author githistory.Author) error {
 	// Get stream from database or create if not exists
 	path, err := ResolveAvailablePath(name)
 	if err != nil {
@@ -121,5 +122,5 @@ func SaveStreamConfig(name, content string, namespaceID uint64, syncNodeIDs []ui
 	}
 
 	// Save the stream configuration file
-	return Save(name, content, overwrite, syncNodeIDs, postAction)
+	return Save(name, content, overwrite, syncNodeIDs, postAction, author)
 }
@@ -26,6 +26,15 @@ func GetIndexedStream(path string) *Index {
 	return &Index{}
 }
 
+// GetAllIndexedStreams returns a snapshot copy of all indexed streams.
+func GetAllIndexedStreams() map[string]*Index {
+	result := make(map[string]*Index, len(IndexedStreams))
+	for k, v := range IndexedStreams {
+		result[k] = v
+	}
+	return result
+}
+
 func init() {
 	cache.RegisterCallback("stream.scanForStream", scanForStream)
 }
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -16,7 +17,7 @@ import (
 	"github.com/uozi-tech/cosy/logger"
 )
 
-func Rename(oldName string, newName string) (err error) {
+func Rename(oldName string, newName string, author githistory.Author) (err error) {
 	oldPath, err := ResolveAvailablePath(oldName)
 	if err != nil {
 		return err
@@ -55,6 +56,7 @@ func Rename(oldName string, newName string) (err error) {
 		return err
 	}
 
+	historyPaths := []string{oldPath, newPath}
 	relinked := false
 	if helper.SymbolLinkExists(oldEnabledConfigFilePath) {
 		_ = os.Remove(oldEnabledConfigFilePath)
@@ -63,6 +65,7 @@ func Rename(oldName string, newName string) (err error) {
 		if err != nil {
 			return err
 		}
+		historyPaths = append(historyPaths, oldEnabledConfigFilePath, newEnabledConfigFilePath)
 
 		err = os.Symlink(newPath, newEnabledConfigFilePath)
 		if err != nil {
@@ -100,6 +103,8 @@ func Rename(oldName string, newName string) (err error) {
 		"name":     newName,
 	})
 
+	githistory.Record(author, "Rename stream "+oldName+" to "+newName, historyPaths...)
+
 	go syncRename(oldName, newName)
 
 	return
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/nginx"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
@@ -17,8 +18,9 @@ import (
 	"github.com/uozi-tech/cosy/logger"
 )
 
-// Save saves a site configuration file
-func Save(name string, content string, overwrite bool, syncNodeIds []uint64, postAction string) (err error) {
+// Save saves a stream configuration file and records it in the git history
+// as author.
+func Save(name string, content string, overwrite bool, syncNodeIds []uint64, postAction string, author githistory.Author) (err error) {
 	path, err := ResolveAvailablePath(name)
 	if err != nil {
 		return err
@@ -89,12 +91,15 @@ func Save(name string, content string, overwrite bool, syncNodeIds []uint64, pos
 		}
 	}
 
-	go syncSave(name, content)
+	githistory.Record(author, "Save stream "+name, path)
+
+	go SyncSave(name, content)
 
 	return
 }
 
-func syncSave(name string, content string) {
+// SyncSave pushes the content of a stream to the nodes it is synced to.
+func SyncSave(name string, content string) {
 	nodes, postSyncAction := getSyncData(name)
 
 	wg := &sync.WaitGroup{}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/0xJacky/Nginx-UI/query"
 	appsettings "github.com/0xJacky/Nginx-UI/settings"
@@ -84,7 +85,7 @@ func setupStreamMutationTest(t *testing.T) (string, func()) {
 func TestSaveAllowsManagedStreamName(t *testing.T) {
 	confDir, waitForSyncQuery := setupStreamMutationTest(t)
 
-	err := Save("tcp_proxy", "server {\n    listen 8080;\n}\n", true, nil, "")
+	err := Save("tcp_proxy", "server {\n    listen 8080;\n}\n", true, nil, "", githistory.SystemAuthor)
 	if err != nil {
 		t.Fatalf("Save returned error: %v", err)
 	}
@@ -98,7 +99,7 @@ func TestSaveAllowsManagedStreamName(t *testing.T) {
 func TestSaveRejectsDangerousStreamExtension(t *testing.T) {
 	setupStreamMutationTest(t)
 
-	err := Save("evil.sh", "server {\n}\n", true, nil, "")
+	err := Save("evil.sh", "server {\n}\n", true, nil, "", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Save expected validation error")
 	}
@@ -115,7 +116,7 @@ func TestRenameAllowsManagedStreamName(t *testing.T) {
 		t.Fatalf("failed to seed stream config: %v", err)
 	}
 
-	err := Rename("tcp_proxy", "tcp_proxy_new")
+	err := Rename("tcp_proxy", "tcp_proxy_new", githistory.SystemAuthor)
 	if err != nil {
 		t.Fatalf("Rename returned error: %v", err)
 	}
@@ -133,7 +134,7 @@ func TestRenameRejectsDangerousStreamExtension(t *testing.T) {
 		t.Fatalf("failed to seed stream config: %v", err)
 	}
 
-	err := Rename("tcp_proxy", "evil.sh")
+	err := Rename("tcp_proxy", "evil.sh", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Rename expected validation error")
 	}
@@ -150,7 +151,7 @@ func TestDuplicateRejectsDangerousStreamExtension(t *testing.T) {
 		t.Fatalf("failed to seed stream config: %v", err)
 	}
 
-	err := Duplicate("tcp_proxy", "copy.sh")
+	err := Duplicate("tcp_proxy", "copy.sh", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Duplicate expected validation error")
 	}
@@ -167,7 +168,7 @@ func TestDuplicateRejectsBinaryStreamContent(t *testing.T) {
 		t.Fatalf("failed to seed stream config: %v", err)
 	}
 
-	err := Duplicate("tcp_proxy", "copy_proxy")
+	err := Duplicate("tcp_proxy", "copy_proxy", githistory.SystemAuthor)
 	if err == nil {
 		t.Fatal("Duplicate expected validation error")
 	}
@@ -0,0 +1,108 @@
+package tlsscan
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+const (
+	EndpointTypeSite   = "site"
+	EndpointTypeStream = "stream"
+)
+
+// Endpoint is a TLS listener of a server in the nginx configuration, probed
+// with one of its server names.
+type Endpoint struct {
+	Type       string `json:"type"`
+	Config     string `json:"config"`
+	Address    string `json:"address"`
+	ServerName string `json:"server_name"`
+	// Certificates are the configured ssl_certificate files, several when a
+	// server offers both an RSA and an EC certificate.
+	Certificates []string `json:"certificates"`
+}
+
+// Endpoints enumerates the TLS servers of the indexed sites and streams, one
+// endpoint per listen address and server name.
+func Endpoints() []Endpoint {
+	var endpoints []Endpoint
+	sites := site.GetAllIndexedSites()
+	for _, name := range slices.Sorted(maps.Keys(sites)) {
+		endpoints = appendEndpoints(endpoints, EndpointTypeSite, name, sites[name].Content)
+	}
+	streams := stream.GetAllIndexedStreams()
+	for _, name := range slices.Sorted(maps.Keys(streams)) {
+		endpoints = appendEndpoints(endpoints, EndpointTypeStream, name, streams[name].Content)
+	}
+	return endpoints
+}
+
+func appendEndpoints(endpoints []Endpoint, endpointType, config, content string) []Endpoint {
+	if content == "" {
+		return endpoints
+	}
+	ngxConfig, err := nginx.ParseNgxConfigByContent(content)
+	if err != nil {
+		logger.Debugf("TLS scan: parse %s %s error: %v", endpointType, config, err)
+		return endpoints
+	}
+
+	for _, server := range ngxConfig.Servers {
+		var addresses, serverNames, certificates []string
+		for _, directive := range server.Directives {
+			switch directive.Directive {
+			case "listen":
+				if address, ok := nginx.LocalTLSAddress(directive.Params); ok {
+					addresses = append(addresses, address)
+				}
+			case "server_name":
+				for _, name := range strings.Fields(directive.Params) {
+					// Wildcard and regex names can not be sent as SNI.
+					if name != "_" && !strings.ContainsAny(name, "*~") && !slices.Contains(serverNames, name) {
+						serverNames = append(serverNames, name)
+					}
+				}
+			case "ssl_certificate":
+				certificates = append(certificates, certificatePath(directive.Params))
+			}
+		}
+		if len(serverNames) == 0 {
+			serverNames = []string{""}
+		}
+
+		for _, address := range addresses {
+			for _, serverName := range serverNames {
+				endpoint := Endpoint{
+					Type:         endpointType,
+					Config:       config,
+					Address:      address,
+					ServerName:   serverName,
+					Certificates: certificates,
+				}
+				if !slices.ContainsFunc(endpoints, func(e Endpoint) bool {
+					return e.Address == endpoint.Address && e.ServerName == endpoint.ServerName
+				}) {
+					endpoints = append(endpoints, endpoint)
+				}
+			}
+		}
+	}
+	return endpoints
+}
+
+// certificatePath resolves an ssl_certificate value the way nginx does,
+// relative to its configuration directory.
+func certificatePath(value string) string {
+	value = strings.Trim(value, `"'`)
+	if value == "" || filepath.IsAbs(value) || strings.HasPrefix(value, "data:") || strings.Contains(value, "$") {
+		return value
+	}
+	return nginx.GetConfPath(value)
+}
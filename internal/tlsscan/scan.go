@@ -0,0 +1,256 @@
+package tlsscan
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/go-acme/lego/v5/certcrypto"
+)
+
+// Issue kinds reported for an endpoint.
+const (
+	IssueHandshakeFailed     = "handshake_failed"
+	IssueExpired             = "expired"
+	IssueIntermediateExpired = "intermediate_expired"
+	IssueCertificateMismatch = "certificate_mismatch"
+	IssueWeakKey             = "weak_key"
+	IssueNameMismatch        = "name_mismatch"
+)
+
+const (
+	handshakeTimeout = 5 * time.Second
+	scanConcurrency  = 8
+)
+
+var (
+	scanMutex   sync.Mutex
+	reportMutex sync.RWMutex
+	lastReport  *Report
+	scanNow     = time.Now
+)
+
+// Certificate describes a certificate of a served chain.
+type Certificate struct {
+	Subject      string             `json:"subject"`
+	Issuer       string             `json:"issuer"`
+	SerialNumber string             `json:"serial_number"`
+	DNSNames     []string           `json:"dns_names"`
+	NotBefore    time.Time          `json:"not_before"`
+	NotAfter     time.Time          `json:"not_after"`
+	KeyType      certcrypto.KeyType `json:"key_type"`
+	Fingerprint  string             `json:"fingerprint"`
+}
+
+// Issue is a problem found on an endpoint.
+type Issue struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// EndpointResult is the chain an endpoint served and what is wrong with it.
+type EndpointResult struct {
+	Endpoint
+	Chain  []*Certificate `json:"chain"`
+	Issues []Issue        `json:"issues"`
+}
+
+// InventoryCertificate is a served leaf certificate and the number of
+// endpoints serving it.
+type InventoryCertificate struct {
+	*Certificate
+	Endpoints int `json:"endpoints"`
+}
+
+// Summary counts the endpoints and their issues by kind.
+type Summary struct {
+	Endpoints int            `json:"endpoints"`
+	Healthy   int            `json:"healthy"`
+	Issues    map[string]int `json:"issues"`
+}
+
+// Report is the outcome of a scan.
+type Report struct {
+	ScannedAt    time.Time               `json:"scanned_at"`
+	Summary      Summary                 `json:"summary"`
+	Certificates []*InventoryCertificate `json:"certificates"`
+	Endpoints    []*EndpointResult       `json:"endpoints"`
+}
+
+// LastReport returns the report of the last scan, or nil before the first.
+func LastReport() *Report {
+	reportMutex.RLock()
+	defer reportMutex.RUnlock()
+	return lastReport
+}
+
+// Scan handshakes with every TLS endpoint of the configuration and records
+// the chains actually served.
+func Scan(ctx context.Context) *Report {
+	scanMutex.Lock()
+	defer scanMutex.Unlock()
+
+	now := scanNow()
+	endpoints := Endpoints()
+	results := make([]*EndpointResult, len(endpoints))
+	semaphore := make(chan struct{}, scanConcurrency)
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = scanEndpoint(ctx, endpoint, now)
+		}()
+	}
+	wg.Wait()
+
+	report := newReport(now, results)
+	reportMutex.Lock()
+	lastReport = report
+	reportMutex.Unlock()
+	return report
+}
+
+func scanEndpoint(ctx context.Context, endpoint Endpoint, now time.Time) *EndpointResult {
+	result := &EndpointResult{Endpoint: endpoint, Chain: []*Certificate{}, Issues: []Issue{}}
+	chain, err := handshake(ctx, endpoint)
+	if err != nil {
+		result.addIssue(IssueHandshakeFailed, err.Error())
+		return result
+	}
+	for _, certificate := range chain {
+		result.Chain = append(result.Chain, newCertificate(certificate))
+	}
+
+	leaf := chain[0]
+	if now.After(leaf.NotAfter) {
+		result.addIssue(IssueExpired, fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format(time.DateOnly)))
+	}
+	for _, intermediate := range chain[1:] {
+		if now.After(intermediate.NotAfter) {
+			result.addIssue(IssueIntermediateExpired, fmt.Sprintf("%s expired on %s",
+				intermediate.Subject.CommonName, intermediate.NotAfter.Format(time.DateOnly)))
+		}
+	}
+	if keyType := result.Chain[0].KeyType; helper.IsWeakKeyType(keyType) {
+		result.addIssue(IssueWeakKey, fmt.Sprintf("weak %s key", keyType))
+	}
+	if endpoint.ServerName != "" && leaf.VerifyHostname(endpoint.ServerName) != nil {
+		result.addIssue(IssueNameMismatch, fmt.Sprintf("%s is not covered by %s",
+			endpoint.ServerName, strings.Join(leaf.DNSNames, ", ")))
+	}
+	if message := compareConfigured(endpoint.Certificates, leaf); message != "" {
+		result.addIssue(IssueCertificateMismatch, message)
+	}
+	return result
+}
+
+// handshake returns the chain an endpoint serves for its server name.
+func handshake(ctx context.Context, endpoint Endpoint) ([]*x509.Certificate, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: handshakeTimeout},
+		// The served chain is inspected, not trusted.
+		Config: &tls.Config{ServerName: endpoint.ServerName, InsecureSkipVerify: true},
+	}
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	chain := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate served")
+	}
+	return chain, nil
+}
+
+// compareConfigured returns why the served leaf is none of the configured
+// certificates, or an empty string when it is one of them or the
+// configuration can not tell, like with variables.
+func compareConfigured(paths []string, leaf *x509.Certificate) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	for _, path := range paths {
+		if strings.Contains(path, "$") || strings.HasPrefix(path, "data:") {
+			return ""
+		}
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Sprintf("configured certificate %s can not be read: %v", path, err)
+		}
+		block, _ := pem.Decode(content)
+		if block != nil && slices.Equal(block.Bytes, leaf.Raw) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("served certificate differs from the configured %s", strings.Join(paths, ", "))
+}
+
+func newCertificate(certificate *x509.Certificate) *Certificate {
+	sum := sha256.Sum256(certificate.Raw)
+	return &Certificate{
+		Subject:      certificate.Subject.String(),
+		Issuer:       certificate.Issuer.String(),
+		SerialNumber: certificate.SerialNumber.Text(16),
+		DNSNames:     certificate.DNSNames,
+		NotBefore:    certificate.NotBefore,
+		NotAfter:     certificate.NotAfter,
+		KeyType:      helper.PublicKeyType(certificate.PublicKey),
+		Fingerprint:  hex.EncodeToString(sum[:]),
+	}
+}
+
+func newReport(now time.Time, results []*EndpointResult) *Report {
+	report := &Report{
+		ScannedAt:    now,
+		Summary:      Summary{Endpoints: len(results), Issues: map[string]int{}},
+		Certificates: []*InventoryCertificate{},
+		Endpoints:    results,
+	}
+	inventory := map[string]*InventoryCertificate{}
+	for _, result := range results {
+		if len(result.Issues) == 0 {
+			report.Summary.Healthy++
+		}
+		for _, issue := range result.Issues {
+			report.Summary.Issues[issue.Kind]++
+		}
+		if len(result.Chain) == 0 {
+			continue
+		}
+		leaf := result.Chain[0]
+		if certificate, ok := inventory[leaf.Fingerprint]; ok {
+			certificate.Endpoints++
+			continue
+		}
+		inventory[leaf.Fingerprint] = &InventoryCertificate{Certificate: leaf, Endpoints: 1}
+		report.Certificates = append(report.Certificates, inventory[leaf.Fingerprint])
+	}
+	slices.SortStableFunc(report.Certificates, func(a, b *InventoryCertificate) int {
+		return a.NotAfter.Compare(b.NotAfter)
+	})
+	return report
+}
+
+func (r *EndpointResult) addIssue(kind, message string) {
+	r.Issues = append(r.Issues, Issue{Kind: kind, Message: message})
+}
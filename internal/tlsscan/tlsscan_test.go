@@ -0,0 +1,218 @@
+package tlsscan
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Now()
+
+func TestEndpoints(t *testing.T) {
+	setupScanTest(t)
+	setIndexedSite(t, "example.com", `server {
+    listen 443 ssl;
+    listen [::]:443 ssl;
+    listen 80;
+    server_name example.com www.example.com *.example.com;
+    ssl_certificate ssl/example.com/fullchain.cer;
+}
+server {
+    listen 80;
+    server_name plain.example.com;
+}`)
+	setIndexedStream(t, "mqtt.conf", `server {
+    listen 8883 ssl;
+    ssl_certificate /etc/ssl/mqtt.pem;
+    proxy_pass 127.0.0.1:1883;
+}`)
+
+	certificate := filepath.Join(settings.NginxSettings.ConfigDir, "ssl", "example.com", "fullchain.cer")
+	assert.Equal(t, []Endpoint{
+		{Type: EndpointTypeSite, Config: "example.com", Address: "127.0.0.1:443", ServerName: "example.com", Certificates: []string{certificate}},
+		{Type: EndpointTypeSite, Config: "example.com", Address: "127.0.0.1:443", ServerName: "www.example.com", Certificates: []string{certificate}},
+		{Type: EndpointTypeSite, Config: "example.com", Address: "[::1]:443", ServerName: "example.com", Certificates: []string{certificate}},
+		{Type: EndpointTypeSite, Config: "example.com", Address: "[::1]:443", ServerName: "www.example.com", Certificates: []string{certificate}},
+		{Type: EndpointTypeStream, Config: "mqtt.conf", Address: "127.0.0.1:8883", Certificates: []string{"/etc/ssl/mqtt.pem"}},
+	}, Endpoints())
+}
+
+func TestScanReportsMismatches(t *testing.T) {
+	setupScanTest(t)
+	dir := t.TempDir()
+
+	root, rootKey := newTestCertificate(t, "Root", true, nil, nil, testNow.AddDate(5, 0, 0), ecKey(t))
+	expiredIntermediate, intermediateKey := newTestCertificate(t, "Expired Intermediate", true, root, rootKey, testNow.AddDate(0, 0, -1), ecKey(t))
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	weakLeaf, _ := newTestCertificate(t, "example.com", false, expiredIntermediate, intermediateKey, testNow.AddDate(0, 3, 0), weakKey)
+	weakAddress := serveTLS(t, []*x509.Certificate{weakLeaf, expiredIntermediate}, weakKey)
+
+	healthyKey := ecKey(t)
+	healthyLeaf, _ := newTestCertificate(t, "example.org", false, root, rootKey, testNow.AddDate(0, 3, 0), healthyKey)
+	healthyAddress := serveTLS(t, []*x509.Certificate{healthyLeaf}, healthyKey)
+	healthyPath := writeCertificate(t, filepath.Join(dir, "example.org.pem"), healthyLeaf)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	setIndexedSite(t, "sites", fmt.Sprintf(`server {
+    listen %s ssl;
+    server_name www.example.net;
+    ssl_certificate %s;
+}
+server {
+    listen %s ssl;
+    server_name example.org;
+    ssl_certificate %s;
+}
+server {
+    listen %s ssl;
+    server_name down.example.org;
+}`, weakAddress, healthyPath, healthyAddress, healthyPath, closedAddress))
+
+	report := Scan(t.Context())
+	assert.Same(t, report, LastReport())
+	require.Len(t, report.Endpoints, 3)
+
+	assert.ElementsMatch(t, []string{IssueIntermediateExpired, IssueWeakKey, IssueNameMismatch, IssueCertificateMismatch},
+		issueKinds(report.Endpoints[0]))
+	require.Len(t, report.Endpoints[0].Chain, 2)
+	assert.Equal(t, "RSA1024", string(report.Endpoints[0].Chain[0].KeyType))
+
+	assert.Empty(t, report.Endpoints[1].Issues)
+	assert.Equal(t, []string{IssueHandshakeFailed}, issueKinds(report.Endpoints[2]))
+
+	assert.Equal(t, Summary{
+		Endpoints: 3,
+		Healthy:   1,
+		Issues: map[string]int{
+			IssueIntermediateExpired: 1,
+			IssueWeakKey:             1,
+			IssueNameMismatch:        1,
+			IssueCertificateMismatch: 1,
+			IssueHandshakeFailed:     1,
+		},
+	}, report.Summary)
+	require.Len(t, report.Certificates, 2)
+	assert.Equal(t, "CN=example.com", report.Certificates[0].Subject, "the certificate expiring first comes first")
+}
+
+func issueKinds(result *EndpointResult) []string {
+	var kinds []string
+	for _, issue := range result.Issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	return kinds
+}
+
+func ecKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// newTestCertificate issues a certificate for key, self-signed when parent is
+// nil.
+func newTestCertificate(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey crypto.Signer,
+	notAfter time.Time, key crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    testNow.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.DNSNames = []string{name}
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func writeCertificate(t *testing.T, path string, certificate *x509.Certificate) string {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}), 0644))
+	return path
+}
+
+func serveTLS(t *testing.T, chain []*x509.Certificate, key crypto.Signer) string {
+	t.Helper()
+	certificate := tls.Certificate{PrivateKey: key}
+	for _, c := range chain {
+		certificate.Certificate = append(certificate.Certificate, c.Raw)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{certificate}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func setIndexedSite(t *testing.T, name, content string) {
+	t.Helper()
+	site.IndexedSites[name] = &site.Index{Path: name, Content: content}
+	t.Cleanup(func() { delete(site.IndexedSites, name) })
+}
+
+func setIndexedStream(t *testing.T, name, content string) {
+	t.Helper()
+	stream.IndexedStreams[name] = &stream.Index{Path: name, Content: content}
+	t.Cleanup(func() { delete(stream.IndexedStreams, name) })
+}
+
+func setupScanTest(t *testing.T) {
+	t.Helper()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	settings.NginxSettings.ConfigDir = t.TempDir()
+	t.Cleanup(func() {
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		reportMutex.Lock()
+		lastReport = nil
+		reportMutex.Unlock()
+	})
+}
@@ -0,0 +1,13 @@
+package trafficalert
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                      = cosy.NewErrorScope("traffic_alert")
+	ErrUnknownMetric       = e.New(40001, "unknown traffic alert metric: {0}")
+	ErrInvalidLogPath      = e.New(40002, "log path is not under the whitelist: {0}")
+	ErrInvalidThreshold    = e.New(40003, "the recovery threshold must not exceed the threshold")
+	ErrInvalidPercentile   = e.New(40004, "percentile must be between 0 and 100")
+	ErrInvalidDuration     = e.New(40005, "{0} must not be negative")
+	ErrSearcherUnavailable = e.New(50001, "the log searcher is not available")
+)
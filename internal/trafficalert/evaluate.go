@@ -0,0 +1,165 @@
+package trafficalert
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// Rule states reported in TrafficAlertState.LastStatus.
+const (
+	StatusOK       = "ok"
+	StatusAlerting = "alerting"
+	StatusNoData   = "no_data"
+)
+
+var trafficAlertNow = time.Now
+var trafficAlertLocks sync.Map
+
+func lockRule(ruleID uint64) func() {
+	lockValue, _ := trafficAlertLocks.LoadOrStore(ruleID, &sync.Mutex{})
+	lock := lockValue.(*sync.Mutex)
+	lock.Lock()
+	return lock.Unlock
+}
+
+// Evaluate measures a rule and notifies on alert transitions. The alert fires
+// once the value stayed above the threshold for FailureThreshold evaluations
+// and recovers only when it drops to the recovery threshold. A window without
+// enough data neither fires nor recovers.
+func Evaluate(ctx context.Context, rule *model.TrafficAlertRule) error {
+	unlock := lockRule(rule.ID)
+	defer unlock()
+
+	now := trafficAlertNow()
+	m, err := Measure(ctx, rule, now)
+	if err != nil {
+		return err
+	}
+
+	state, err := GetState(rule.ID)
+	if err != nil {
+		return err
+	}
+	state.LastEvaluatedAt = &now
+	state.LastValue = m.Value
+	state.LastRequests = m.Requests
+
+	switch {
+	case m.NoData:
+		state.ConsecutiveFailures = 0
+		state.LastStatus = StatusNoData
+	case m.Value > rule.Threshold:
+		state.ConsecutiveFailures++
+		threshold := max(rule.FailureThreshold, 1)
+
+		shouldNotify := state.ConsecutiveFailures >= threshold && !state.FailureNotified
+		if state.FailureNotified && rule.CooldownSeconds > 0 && state.LastNotifiedAt != nil {
+			shouldNotify = now.Sub(*state.LastNotifiedAt) >= time.Duration(rule.CooldownSeconds)*time.Second
+		}
+		if shouldNotify {
+			notification.WarningTo(
+				"Traffic Alert Triggered",
+				"Traffic alert %{rule} on node %{node}: %{metric} of %{log_path} is %{value}, above %{threshold}",
+				alertDetails(rule, m),
+				rule.ExternalNotifyIDs,
+			)
+			state.FailureNotified = true
+			state.LastNotifiedAt = &now
+		}
+		if state.FailureNotified {
+			state.LastStatus = StatusAlerting
+		} else {
+			state.LastStatus = StatusOK
+		}
+	case m.Value <= rule.RecoveryThreshold:
+		if state.FailureNotified && rule.RecoveryEnabled {
+			notification.SuccessTo(
+				"Traffic Alert Recovered",
+				"Traffic alert %{rule} on node %{node} recovered: %{metric} of %{log_path} is %{value}",
+				alertDetails(rule, m),
+				rule.ExternalNotifyIDs,
+			)
+		}
+		state.ConsecutiveFailures = 0
+		state.FailureNotified = false
+		state.LastNotifiedAt = nil
+		state.LastStatus = StatusOK
+	default:
+		// Between the recovery threshold and the threshold a pending breach is
+		// reset, but a fired alert stays active until it recovers.
+		state.ConsecutiveFailures = 0
+		if !state.FailureNotified {
+			state.LastStatus = StatusOK
+		}
+	}
+
+	if state.ID == 0 {
+		err = query.TrafficAlertState.Create(state)
+	} else {
+		err = query.TrafficAlertState.Save(state)
+	}
+	if err != nil {
+		logger.Errorf("Failed to persist traffic alert state for rule %d: %v", rule.ID, err)
+	}
+	return err
+}
+
+// EvaluateByID loads a rule and evaluates it. Disabled rules are skipped.
+func EvaluateByID(ctx context.Context, ruleID uint64) error {
+	rule, err := GetRule(ruleID)
+	if err != nil {
+		return err
+	}
+	if !rule.Enabled {
+		return nil
+	}
+	return Evaluate(ctx, rule)
+}
+
+func alertDetails(rule *model.TrafficAlertRule, m *Measurement) map[string]any {
+	return map[string]any{
+		"node":      settings.NodeSettings.Name,
+		"rule":      rule.Name,
+		"log_path":  rule.LogPath,
+		"metric":    metricLabel(rule, m),
+		"value":     formatValue(rule.Metric, m.Value),
+		"threshold": formatValue(rule.Metric, rule.Threshold),
+		"subject":   m.Subject,
+		"requests":  m.Requests,
+	}
+}
+
+func metricLabel(rule *model.TrafficAlertRule, m *Measurement) string {
+	switch rule.Metric {
+	case model.TrafficAlertMetricErrorRatio:
+		return "5xx ratio"
+	case model.TrafficAlertMetricLatency:
+		return "p" + strconv.FormatFloat(rule.Percentile, 'f', -1, 64) + " request time"
+	case model.TrafficAlertMetricRateSpike:
+		return "request rate versus baseline"
+	case model.TrafficAlertMetricIPRate:
+		return "requests per minute of " + m.Subject
+	}
+	return string(rule.Metric)
+}
+
+func formatValue(metric model.TrafficAlertMetric, value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	switch metric {
+	case model.TrafficAlertMetricErrorRatio:
+		return formatted + "%"
+	case model.TrafficAlertMetricLatency:
+		return formatted + "s"
+	case model.TrafficAlertMetricRateSpike:
+		return formatted + "x"
+	}
+	return formatted
+}
@@ -0,0 +1,186 @@
+package trafficalert
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log"
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/spf13/cast"
+)
+
+// percentilePageSize bounds the hits fetched per page while walking down to
+// the percentile rank.
+const percentilePageSize = 1000
+
+// Measurement is the value of a rule over one evaluation window.
+type Measurement struct {
+	Value float64 `json:"value"`
+	// Requests is the number of requests in the window.
+	Requests uint64 `json:"requests"`
+	// Subject names what the value belongs to, such as the client IP of an
+	// ip_rate rule.
+	Subject string `json:"subject,omitempty"`
+	// NoData is set when the window holds fewer requests than the rule
+	// requires, or when a rate spike has no baseline to compare against.
+	NoData bool `json:"no_data"`
+}
+
+var getSearcher = func() searcher.SearcherInterface {
+	if s := nginx_log.GetSearcher(); s != nil && s.IsHealthy() {
+		return s
+	}
+	return nil
+}
+
+// Measure computes the value of a rule over the window ending at now.
+func Measure(ctx context.Context, rule *model.TrafficAlertRule, now time.Time) (*Measurement, error) {
+	s := getSearcher()
+	if s == nil {
+		return nil, ErrSearcherUnavailable
+	}
+
+	window := time.Duration(rule.WindowSeconds) * time.Second
+	req := windowRequest(rule.LogPath, now.Add(-window), now)
+	total, err := count(ctx, s, req)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Measurement{Requests: total}
+	if total == 0 || total < uint64(rule.MinRequests) {
+		m.NoData = true
+		return m, nil
+	}
+
+	switch rule.Metric {
+	case model.TrafficAlertMetricErrorRatio:
+		serverErrors, err := countServerErrors(ctx, s, req)
+		if err != nil {
+			return nil, err
+		}
+		m.Value = float64(serverErrors) * 100 / float64(total)
+	case model.TrafficAlertMetricLatency:
+		m.Value, err = requestTimePercentile(ctx, s, req, total, rule.Percentile)
+		if err != nil {
+			return nil, err
+		}
+	case model.TrafficAlertMetricRateSpike:
+		baseline := time.Duration(rule.BaselineSeconds) * time.Second
+		start := now.Add(-window)
+		baselineTotal, err := count(ctx, s, windowRequest(rule.LogPath, start.Add(-baseline), start))
+		if err != nil {
+			return nil, err
+		}
+		if baselineTotal == 0 {
+			m.NoData = true
+			return m, nil
+		}
+		m.Value = (float64(total) / window.Seconds()) / (float64(baselineTotal) / baseline.Seconds())
+	case model.TrafficAlertMetricIPRate:
+		ip, requests, err := busiestIP(ctx, s, req)
+		if err != nil {
+			return nil, err
+		}
+		m.Subject = ip
+		m.Value = float64(requests) / window.Minutes()
+	default:
+		return nil, ErrUnknownMetric
+	}
+	return m, nil
+}
+
+// windowRequest matches the requests of a log group, including its rotated
+// files, with a timestamp in [start, end).
+func windowRequest(logPath string, start, end time.Time) searcher.SearchRequest {
+	startUnix, endUnix := start.Unix(), end.Unix()
+	return searcher.SearchRequest{
+		LogPaths:       []string{logPath},
+		UseMainLogPath: true,
+		StartTime:      &startUnix,
+		EndTime:        &endUnix,
+		Limit:          -1,
+	}
+}
+
+func count(ctx context.Context, s searcher.SearcherInterface, req searcher.SearchRequest) (uint64, error) {
+	result, err := s.Search(ctx, &req)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalHits, nil
+}
+
+func countServerErrors(ctx context.Context, s searcher.SearcherInterface, req searcher.SearchRequest) (uint64, error) {
+	req.IncludeFacets = true
+	req.FacetFields = []string{"status"}
+	req.FacetSize = 500
+	result, err := s.Search(ctx, &req)
+	if err != nil {
+		return 0, err
+	}
+
+	var serverErrors uint64
+	if facet, ok := result.Facets["status"]; ok {
+		for _, term := range facet.Terms {
+			if code, err := strconv.Atoi(term.Term); err == nil && code >= 500 {
+				serverErrors += uint64(term.Count)
+			}
+		}
+	}
+	return serverErrors, nil
+}
+
+// requestTimePercentile returns the nearest-rank percentile of request_time.
+// The ranks are counted from the slowest request, so only the tail above the
+// percentile is read.
+func requestTimePercentile(
+	ctx context.Context,
+	s searcher.SearcherInterface,
+	req searcher.SearchRequest,
+	total uint64,
+	percentile float64,
+) (float64, error) {
+	rank := total - uint64(math.Ceil(percentile/100*float64(total))) + 1
+
+	req.SortBy = "request_time"
+	req.SortOrder = searcher.SortOrderDesc
+	req.Fields = []string{"request_time"}
+
+	var seen uint64
+	for {
+		req.Limit = int(min(percentilePageSize, rank-seen))
+		result, err := s.Search(ctx, &req)
+		if err != nil {
+			return 0, err
+		}
+		if len(result.Hits) == 0 {
+			return 0, nil
+		}
+		seen += uint64(len(result.Hits))
+		last := result.Hits[len(result.Hits)-1]
+		if seen >= rank {
+			return cast.ToFloat64(last.Fields["request_time"]), nil
+		}
+		req.SearchAfter = last.Sort
+	}
+}
+
+func busiestIP(ctx context.Context, s searcher.SearcherInterface, req searcher.SearchRequest) (string, uint64, error) {
+	req.IncludeFacets = true
+	req.FacetFields = []string{"ip"}
+	req.FacetSize = 1
+	result, err := s.Search(ctx, &req)
+	if err != nil {
+		return "", 0, err
+	}
+
+	facet, ok := result.Facets["ip"]
+	if !ok || len(facet.Terms) == 0 {
+		return "", 0, nil
+	}
+	return facet.Terms[0].Term, uint64(facet.Terms[0].Count), nil
+}
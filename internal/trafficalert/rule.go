@@ -0,0 +1,105 @@
+package trafficalert
+
+import (
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/utils"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+const (
+	defaultWindowSeconds   = 300
+	defaultBaselineSeconds = 3600
+	defaultIntervalSeconds = 60
+	defaultPercentile      = 95
+)
+
+// NormalizedColumns lists the columns Normalize may fill in, so a partial
+// update can persist the defaults along with the changed fields.
+var NormalizedColumns = []string{
+	"recovery_threshold",
+	"percentile",
+	"window_seconds",
+	"baseline_seconds",
+	"interval_seconds",
+	"failure_threshold",
+}
+
+// Normalize validates a rule and fills in the defaults of unset fields. A zero
+// recovery threshold means the alert recovers as soon as the value is back at
+// the threshold.
+func Normalize(rule *model.TrafficAlertRule) error {
+	switch rule.Metric {
+	case model.TrafficAlertMetricErrorRatio, model.TrafficAlertMetricLatency,
+		model.TrafficAlertMetricRateSpike, model.TrafficAlertMetricIPRate:
+	default:
+		return cosy.WrapErrorWithParams(ErrUnknownMetric, string(rule.Metric))
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"window_seconds", &rule.WindowSeconds, defaultWindowSeconds},
+		{"baseline_seconds", &rule.BaselineSeconds, defaultBaselineSeconds},
+		{"interval_seconds", &rule.IntervalSeconds, defaultIntervalSeconds},
+		{"failure_threshold", &rule.FailureThreshold, 1},
+		{"min_requests", &rule.MinRequests, 0},
+		{"cooldown_seconds", &rule.CooldownSeconds, 0},
+	} {
+		if *field.value < 0 {
+			return cosy.WrapErrorWithParams(ErrInvalidDuration, field.name)
+		}
+		if *field.value == 0 {
+			*field.value = field.def
+		}
+	}
+
+	if rule.Metric == model.TrafficAlertMetricLatency {
+		if rule.Percentile == 0 {
+			rule.Percentile = defaultPercentile
+		}
+		if rule.Percentile < 0 || rule.Percentile >= 100 {
+			return ErrInvalidPercentile
+		}
+	}
+
+	if rule.RecoveryThreshold == 0 {
+		rule.RecoveryThreshold = rule.Threshold
+	}
+	if rule.RecoveryThreshold > rule.Threshold {
+		return ErrInvalidThreshold
+	}
+
+	if !utils.IsValidLogPath(rule.LogPath) {
+		return cosy.WrapErrorWithParams(ErrInvalidLogPath, rule.LogPath)
+	}
+	return nil
+}
+
+// GetEnabledRules returns the rules that should be scheduled.
+func GetEnabledRules() (rules []*model.TrafficAlertRule, err error) {
+	r := query.TrafficAlertRule
+	return r.Where(r.Enabled.Is(true)).Find()
+}
+
+// GetRule loads a rule by id.
+func GetRule(id uint64) (*model.TrafficAlertRule, error) {
+	r := query.TrafficAlertRule
+	return r.Where(r.ID.Eq(id)).First()
+}
+
+// GetState returns the persisted state of a rule, or an empty state when the
+// rule has not been evaluated yet.
+func GetState(ruleID uint64) (*model.TrafficAlertState, error) {
+	s := query.TrafficAlertState
+	states, err := s.Where(s.RuleID.Eq(ruleID)).Limit(1).Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(states) > 0 {
+		return states[0], nil
+	}
+	return &model.TrafficAlertState{RuleID: ruleID}, nil
+}
@@ -0,0 +1,267 @@
+package trafficalert
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/nginx_log/searcher"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type entry struct {
+	timestamp   int64
+	status      int
+	ip          string
+	requestTime float64
+}
+
+// fakeSearcher answers the requests Measure sends from an in-memory log.
+type fakeSearcher struct {
+	searcher.SearcherInterface
+	entries []entry
+}
+
+func (f *fakeSearcher) Search(_ context.Context, req *searcher.SearchRequest) (*searcher.SearchResult, error) {
+	var matched []entry
+	for _, e := range f.entries {
+		if e.timestamp >= *req.StartTime && e.timestamp < *req.EndTime {
+			matched = append(matched, e)
+		}
+	}
+	result := &searcher.SearchResult{TotalHits: uint64(len(matched)), Facets: map[string]*searcher.Facet{}}
+
+	for _, field := range req.FacetFields {
+		counts := map[string]int{}
+		for _, e := range matched {
+			if field == "status" {
+				counts[strconv.Itoa(e.status)]++
+			} else {
+				counts[e.ip]++
+			}
+		}
+		facet := &searcher.Facet{Field: field}
+		for term, count := range counts {
+			facet.Terms = append(facet.Terms, &searcher.FacetTerm{Term: term, Count: count})
+		}
+		slices.SortFunc(facet.Terms, func(a, b *searcher.FacetTerm) int { return b.Count - a.Count })
+		facet.Terms = facet.Terms[:min(len(facet.Terms), req.FacetSize)]
+		result.Facets[field] = facet
+	}
+
+	if req.Limit > 0 {
+		slices.SortFunc(matched, func(a, b entry) int {
+			if a.requestTime > b.requestTime {
+				return -1
+			}
+			return 1
+		})
+		offset := 0
+		if len(req.SearchAfter) > 0 {
+			offset, _ = strconv.Atoi(req.SearchAfter[0])
+		}
+		for i := offset; i < len(matched) && i < offset+req.Limit; i++ {
+			result.Hits = append(result.Hits, &searcher.SearchHit{
+				Fields: map[string]any{"request_time": matched[i].requestTime},
+				Sort:   []string{strconv.Itoa(i + 1)},
+			})
+		}
+	}
+	return result, nil
+}
+
+func useSearcher(t *testing.T, entries []entry) {
+	t.Helper()
+	original := getSearcher
+	getSearcher = func() searcher.SearcherInterface { return &fakeSearcher{entries: entries} }
+	t.Cleanup(func() { getSearcher = original })
+}
+
+var testNow = time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+// requests returns n entries spread over the seconds before end.
+func requests(n int, end time.Time, status int, ip string) []entry {
+	entries := make([]entry, n)
+	for i := range entries {
+		entries[i] = entry{timestamp: end.Unix() - 1 - int64(i%60), status: status, ip: ip, requestTime: 0.1}
+	}
+	return entries
+}
+
+func TestNormalizeFillsDefaultsAndRejectsInvalidRules(t *testing.T) {
+	rule := &model.TrafficAlertRule{Metric: "unknown"}
+	requireErrorCode(t, Normalize(rule), ErrUnknownMetric)
+
+	rule = &model.TrafficAlertRule{Metric: model.TrafficAlertMetricLatency, Threshold: 1, RecoveryThreshold: 2}
+	requireErrorCode(t, Normalize(rule), ErrInvalidThreshold)
+	assert.Equal(t, float64(defaultPercentile), rule.Percentile)
+	assert.Equal(t, defaultWindowSeconds, rule.WindowSeconds)
+	assert.Equal(t, 1, rule.FailureThreshold)
+
+	rule = &model.TrafficAlertRule{Metric: model.TrafficAlertMetricLatency, Percentile: 100}
+	requireErrorCode(t, Normalize(rule), ErrInvalidPercentile)
+
+	rule = &model.TrafficAlertRule{Metric: model.TrafficAlertMetricIPRate, CooldownSeconds: -1}
+	requireErrorCode(t, Normalize(rule), ErrInvalidDuration)
+}
+
+func TestMeasureMetrics(t *testing.T) {
+	entries := append(requests(90, testNow, 200, "10.0.0.1"), requests(10, testNow, 502, "10.0.0.2")...)
+	for i := range entries {
+		entries[i].requestTime = float64(i+1) / 100
+	}
+	// Outside of the window, only the rate spike baseline sees them.
+	entries = append(entries, requests(120, testNow.Add(-5*time.Minute), 200, "10.0.0.3")...)
+	useSearcher(t, entries)
+
+	rule := &model.TrafficAlertRule{WindowSeconds: 300, BaselineSeconds: 600}
+
+	rule.Metric = model.TrafficAlertMetricErrorRatio
+	m, err := Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, &Measurement{Value: 10, Requests: 100}, m)
+
+	rule.Metric = model.TrafficAlertMetricLatency
+	rule.Percentile = 95
+	m, err = Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.95, m.Value, 1e-9)
+
+	rule.Metric = model.TrafficAlertMetricRateSpike
+	m, err = Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.InDelta(t, (100.0/300)/(120.0/600), m.Value, 1e-9)
+
+	rule.Metric = model.TrafficAlertMetricIPRate
+	m, err = Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", m.Subject)
+	assert.InDelta(t, 90.0/5, m.Value, 1e-9)
+
+	rule.MinRequests = 101
+	m, err = Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.True(t, m.NoData)
+}
+
+func TestRequestTimePercentilePagesThroughTheTail(t *testing.T) {
+	entries := make([]entry, 3000)
+	for i := range entries {
+		entries[i] = entry{timestamp: testNow.Unix() - 1, requestTime: float64(i + 1)}
+	}
+	useSearcher(t, entries)
+
+	rule := &model.TrafficAlertRule{Metric: model.TrafficAlertMetricLatency, Percentile: 50, WindowSeconds: 60}
+	m, err := Measure(context.Background(), rule, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1500), m.Value)
+}
+
+func TestEvaluateAppliesHysteresis(t *testing.T) {
+	db := setupEvaluateTest(t)
+	rule := &model.TrafficAlertRule{
+		Model:             model.Model{ID: 1},
+		Name:              "errors",
+		LogPath:           "/var/log/nginx/access.log",
+		Metric:            model.TrafficAlertMetricErrorRatio,
+		Threshold:         20,
+		RecoveryThreshold: 5,
+		WindowSeconds:     300,
+		FailureThreshold:  2,
+		RecoveryEnabled:   true,
+	}
+	evaluate := func(errorRatio int) *model.TrafficAlertState {
+		t.Helper()
+		useSearcher(t, append(requests(100-errorRatio, testNow, 200, "10.0.0.1"), requests(errorRatio, testNow, 500, "10.0.0.1")...))
+		require.NoError(t, Evaluate(context.Background(), rule))
+		state, err := GetState(rule.ID)
+		require.NoError(t, err)
+		return state
+	}
+
+	state := evaluate(30)
+	assert.Equal(t, StatusOK, state.LastStatus, "a single breach stays pending")
+	assertNotificationCount(t, db, 0)
+
+	state = evaluate(30)
+	assert.Equal(t, StatusAlerting, state.LastStatus)
+	assertNotificationCount(t, db, 1)
+
+	evaluate(30)
+	assertNotificationCount(t, db, 1)
+
+	state = evaluate(10)
+	assert.Equal(t, StatusAlerting, state.LastStatus, "above the recovery threshold the alert stays active")
+	assertNotificationCount(t, db, 1)
+
+	evaluate(30)
+	assertNotificationCount(t, db, 1)
+
+	useSearcher(t, nil)
+	require.NoError(t, Evaluate(context.Background(), rule))
+	state, err := GetState(rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusNoData, state.LastStatus)
+	assert.True(t, state.FailureNotified, "a window without data does not recover")
+
+	state = evaluate(2)
+	assert.Equal(t, StatusOK, state.LastStatus)
+	assert.False(t, state.FailureNotified)
+	assertNotificationCount(t, db, 2)
+
+	var notifications []model.Notification
+	require.NoError(t, db.Order("id asc").Find(&notifications).Error)
+	assert.Equal(t, model.NotificationWarning, notifications[0].Type)
+	assert.Equal(t, model.NotificationSuccess, notifications[1].Type)
+}
+
+func setupEvaluateTest(t *testing.T) *gorm.DB {
+	t.Helper()
+	originalDB := model.UseDB()
+	originalNotification := query.Notification
+	originalExternalNotify := query.ExternalNotify
+	originalState := query.TrafficAlertState
+	originalNow := trafficAlertNow
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Notification{}, &model.ExternalNotify{}, &model.TrafficAlertState{}))
+	model.Use(db)
+	testQuery := query.Use(db)
+	query.Notification = &testQuery.Notification
+	query.ExternalNotify = &testQuery.ExternalNotify
+	query.TrafficAlertState = &testQuery.TrafficAlertState
+	trafficAlertNow = func() time.Time { return testNow }
+
+	t.Cleanup(func() {
+		model.Use(originalDB)
+		query.Notification = originalNotification
+		query.ExternalNotify = originalExternalNotify
+		query.TrafficAlertState = originalState
+		trafficAlertNow = originalNow
+	})
+	return db
+}
+
+func assertNotificationCount(t *testing.T, db *gorm.DB, want int64) {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&model.Notification{}).Count(&count).Error)
+	assert.Equal(t, want, count)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
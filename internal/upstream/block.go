@@ -0,0 +1,530 @@
+package upstream
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Load balancing methods of an upstream block, round robin is the default and
+// has no directive.
+const (
+	MethodRoundRobin = ""
+	MethodLeastConn  = "least_conn"
+	MethodIPHash     = "ip_hash"
+	MethodHash       = "hash"
+	MethodRandom     = "random"
+)
+
+// Block is the structured form of an `upstream {}` block. Parsing a block and
+// rendering it again keeps every directive, those without a field of their
+// own are kept verbatim in Directives.
+type Block struct {
+	Name string `json:"name"`
+
+	Method         string `json:"method"`
+	HashKey        string `json:"hash_key,omitempty"`
+	HashConsistent bool   `json:"hash_consistent,omitempty"`
+	RandomTwo      bool   `json:"random_two,omitempty"`
+	RandomMethod   string `json:"random_method,omitempty"`
+
+	// Zone is the shared memory zone as "name [size]", servers using resolve
+	// require it.
+	Zone string `json:"zone,omitempty"`
+
+	Keepalive         int    `json:"keepalive,omitempty"`
+	KeepaliveRequests int    `json:"keepalive_requests,omitempty"`
+	KeepaliveTime     string `json:"keepalive_time,omitempty"`
+	KeepaliveTimeout  string `json:"keepalive_timeout,omitempty"`
+
+	Servers    []*Server    `json:"servers"`
+	Directives []*Directive `json:"directives,omitempty"`
+	Comments   []string     `json:"comments,omitempty"`
+
+	// start and end are the byte offsets of the block in the parsed content.
+	start, end int
+}
+
+// Server is a `server` directive of an upstream block.
+type Server struct {
+	Address     string `json:"address"`
+	Weight      int    `json:"weight,omitempty"`
+	MaxFails    *int   `json:"max_fails,omitempty"`
+	FailTimeout string `json:"fail_timeout,omitempty"`
+	MaxConns    int    `json:"max_conns,omitempty"`
+	Backup      bool   `json:"backup,omitempty"`
+	Down        bool   `json:"down,omitempty"`
+	Resolve     bool   `json:"resolve,omitempty"`
+	// Params keeps the parameters without a field of their own, such as
+	// slow_start or service.
+	Params   []string `json:"params,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+}
+
+// Directive is a directive of an upstream block kept verbatim.
+type Directive struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+}
+
+// ErrBlockNotFound is returned when a configuration has no upstream block of
+// the requested name.
+var ErrBlockNotFound = errors.New("upstream block not found")
+
+var (
+	blockNameRegexp     = regexp.MustCompile(`^[^\s;{}#'"]+$`)
+	directiveNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+	timeRegexp      = regexp.MustCompile(`^(\d+(ms|s|m|h|d|w|M|y)?)+$`)
+)
+
+// ParseBlocks returns the upstream blocks of a configuration in the order
+// they appear.
+func ParseBlocks(content string) ([]*Block, error) {
+	tokens, err := tokenize(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []*Block
+	statementStart := true
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.comment {
+			continue
+		}
+		// Other blocks like map may have an entry named upstream.
+		if statementStart && token.value == "upstream" && !token.quoted &&
+			i+2 < len(tokens) && tokens[i+2].value == "{" && !tokens[i+2].quoted {
+			block, next, err := parseBlock(tokens, i)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+			i = next
+			statementStart = true
+			continue
+		}
+		statementStart = !token.quoted && (token.value == ";" || token.value == "{" || token.value == "}")
+	}
+	return blocks, nil
+}
+
+// FindBlock returns the upstream block with the given name.
+func FindBlock(content, name string) (*Block, error) {
+	blocks, err := ParseBlocks(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		if block.Name == name {
+			return block, nil
+		}
+	}
+	return nil, ErrBlockNotFound
+}
+
+// ReplaceBlock renders block in place of the upstream block of the same name,
+// keeping the rest of the content as it is.
+func ReplaceBlock(content string, block *Block) (string, error) {
+	current, err := FindBlock(content, block.Name)
+	if err != nil {
+		return "", err
+	}
+
+	lineStart := strings.LastIndexByte(content[:current.start], '\n') + 1
+	indent := content[lineStart:current.start]
+	if strings.TrimSpace(indent) != "" {
+		indent = ""
+	}
+	rendered, err := block.Render(indent)
+	if err != nil {
+		return "", err
+	}
+	rendered = strings.TrimSuffix(rendered, "\n")
+	return content[:current.start] + strings.TrimPrefix(rendered, indent) + content[current.end:], nil
+}
+
+func parseBlock(tokens []token, i int) (*Block, int, error) {
+	block := &Block{Name: tokens[i+1].value, start: tokens[i].start}
+	var comments []string
+	var statement []token
+
+	for i += 3; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.comment:
+			comments = append(comments, t.value)
+		case t.value == "}" && !t.quoted:
+			if len(statement) > 0 {
+				return nil, 0, fmt.Errorf("missing ';' in upstream %s", block.Name)
+			}
+			block.Comments = append(block.Comments, comments...)
+			block.end = t.end
+			return block, i, nil
+		case t.value == "{" && !t.quoted:
+			return nil, 0, fmt.Errorf("unexpected block in upstream %s", block.Name)
+		case t.value == ";" && !t.quoted:
+			if len(statement) == 0 {
+				continue
+			}
+			args := make([]string, len(statement)-1)
+			for j, arg := range statement[1:] {
+				args[j] = arg.value
+			}
+			if err := block.apply(statement[0].value, args, comments); err != nil {
+				return nil, 0, err
+			}
+			statement = statement[:0]
+			comments = nil
+		default:
+			statement = append(statement, t)
+		}
+	}
+	return nil, 0, fmt.Errorf("unterminated upstream %s", block.Name)
+}
+
+// apply sets the field of a directive, comments in front of a server stay
+// with it and all others move to the top of the block.
+func (b *Block) apply(name string, args []string, comments []string) error {
+	if name == "server" {
+		server, err := parseServer(args)
+		if err != nil {
+			return fmt.Errorf("upstream %s: %w", b.Name, err)
+		}
+		server.Comments = comments
+		b.Servers = append(b.Servers, server)
+		return nil
+	}
+	b.Comments = append(b.Comments, comments...)
+
+	var err error
+	switch {
+	case name == MethodLeastConn && len(args) == 0, name == MethodIPHash && len(args) == 0:
+		b.Method = name
+	case name == MethodHash && (len(args) == 1 || len(args) == 2 && args[1] == "consistent"):
+		b.Method = name
+		b.HashKey = args[0]
+		b.HashConsistent = len(args) == 2
+	case name == MethodRandom && len(args) == 0:
+		b.Method = name
+	case name == MethodRandom && args[0] == "two" && len(args) <= 2:
+		b.Method = name
+		b.RandomTwo = true
+		if len(args) == 2 {
+			b.RandomMethod = args[1]
+		}
+	case name == "zone" && len(args) > 0:
+		b.Zone = strings.Join(args, " ")
+	case name == "keepalive" && len(args) == 1:
+		b.Keepalive, err = strconv.Atoi(args[0])
+	case name == "keepalive_requests" && len(args) == 1:
+		b.KeepaliveRequests, err = strconv.Atoi(args[0])
+	case name == "keepalive_time" && len(args) == 1:
+		b.KeepaliveTime = args[0]
+	case name == "keepalive_timeout" && len(args) == 1:
+		b.KeepaliveTimeout = args[0]
+	default:
+		b.Directives = append(b.Directives, &Directive{Name: name, Args: args})
+	}
+	if err != nil {
+		return fmt.Errorf("upstream %s: invalid %s: %w", b.Name, name, err)
+	}
+	return nil
+}
+
+func parseServer(args []string) (*Server, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("server without address")
+	}
+	server := &Server{Address: args[0]}
+	for _, arg := range args[1:] {
+		key, value, hasValue := strings.Cut(arg, "=")
+		var err error
+		switch {
+		case key == "weight" && hasValue:
+			server.Weight, err = strconv.Atoi(value)
+		case key == "max_fails" && hasValue:
+			var maxFails int
+			maxFails, err = strconv.Atoi(value)
+			server.MaxFails = &maxFails
+		case key == "fail_timeout" && hasValue:
+			server.FailTimeout = value
+		case key == "max_conns" && hasValue:
+			server.MaxConns, err = strconv.Atoi(value)
+		case arg == "backup":
+			server.Backup = true
+		case arg == "down":
+			server.Down = true
+		case arg == "resolve":
+			server.Resolve = true
+		default:
+			server.Params = append(server.Params, arg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("server %s: invalid %s", server.Address, key)
+		}
+	}
+	return server, nil
+}
+
+// Validate checks the block against the rules nginx enforces when loading it.
+func (b *Block) Validate() error {
+	if !blockNameRegexp.MatchString(b.Name) {
+		return fmt.Errorf("invalid upstream name %q", b.Name)
+	}
+
+	switch b.Method {
+	case MethodRoundRobin, MethodLeastConn, MethodIPHash:
+	case MethodHash:
+		if b.HashKey == "" {
+			return fmt.Errorf("hash requires a key")
+		}
+	case MethodRandom:
+		if b.RandomMethod != "" && (!b.RandomTwo || !slices.Contains([]string{MethodLeastConn, "least_time=header", "least_time=last_byte"}, b.RandomMethod)) {
+			return fmt.Errorf("invalid random method %q", b.RandomMethod)
+		}
+	default:
+		return fmt.Errorf("unknown balancing method %q", b.Method)
+	}
+
+	if b.Keepalive < 0 || b.KeepaliveRequests < 0 {
+		return fmt.Errorf("keepalive settings must not be negative")
+	}
+	for _, value := range []string{b.KeepaliveTime, b.KeepaliveTimeout} {
+		if value != "" && !timeRegexp.MatchString(value) {
+			return fmt.Errorf("invalid time %q", value)
+		}
+	}
+
+	if len(b.Servers) == 0 {
+		return fmt.Errorf("upstream %s has no servers", b.Name)
+	}
+	for _, server := range b.Servers {
+		if !blockNameRegexp.MatchString(server.Address) {
+			return fmt.Errorf("invalid server address %q", server.Address)
+		}
+		if server.Weight < 0 || server.MaxConns < 0 || (server.MaxFails != nil && *server.MaxFails < 0) {
+			return fmt.Errorf("server %s: weight, max_fails and max_conns must not be negative", server.Address)
+		}
+		if server.FailTimeout != "" && !timeRegexp.MatchString(server.FailTimeout) {
+			return fmt.Errorf("server %s: invalid fail_timeout %q", server.Address, server.FailTimeout)
+		}
+		if server.Backup && (b.Method == MethodHash || b.Method == MethodIPHash || b.Method == MethodRandom) {
+			return fmt.Errorf("server %s: backup cannot be used with %s", server.Address, b.Method)
+		}
+		if server.Resolve && b.Zone == "" {
+			return fmt.Errorf("server %s: resolve requires a zone", server.Address)
+		}
+	}
+	return nil
+}
+
+// Server returns the server with the given address.
+func (b *Block) Server(address string) *Server {
+	for _, server := range b.Servers {
+		if server.Address == address {
+			return server
+		}
+	}
+	return nil
+}
+
+// Render formats the block, indenting it by indent. Comments and the names of
+// the directives kept verbatim are written as they are, so they are checked
+// first, and the result has to parse back into the same block.
+func (b *Block) Render(indent string) (string, error) {
+	if err := b.checkVerbatim(); err != nil {
+		return "", err
+	}
+
+	rendered := b.render(indent)
+	reparsed, err := ParseBlocks(rendered)
+	if err != nil {
+		return "", fmt.Errorf("upstream %s renders invalid configuration: %w", b.Name, err)
+	}
+	if len(reparsed) != 1 || reparsed[0].render(indent) != rendered {
+		return "", fmt.Errorf("upstream %s does not render to the same block", b.Name)
+	}
+	return rendered, nil
+}
+
+// checkVerbatim checks the parts of the block Render writes without quoting.
+func (b *Block) checkVerbatim() error {
+	comments := slices.Clone(b.Comments)
+	for _, server := range b.Servers {
+		comments = append(comments, server.Comments...)
+	}
+	for _, c := range comments {
+		if !strings.HasPrefix(c, "#") || strings.ContainsAny(c, "\r\n") {
+			return fmt.Errorf("upstream %s: invalid comment %q", b.Name, c)
+		}
+	}
+	for _, directive := range b.Directives {
+		if !directiveNameRegexp.MatchString(directive.Name) {
+			return fmt.Errorf("upstream %s: invalid directive name %q", b.Name, directive.Name)
+		}
+	}
+	return nil
+}
+
+func (b *Block) render(indent string) string {
+	var buf strings.Builder
+	inner := indent + "    "
+	line := func(name string, args ...string) {
+		buf.WriteString(inner + name)
+		for _, arg := range args {
+			buf.WriteString(" " + quote(arg))
+		}
+		buf.WriteString(";\n")
+	}
+	comment := func(comments []string) {
+		for _, c := range comments {
+			buf.WriteString(inner + c + "\n")
+		}
+	}
+
+	buf.WriteString(indent + "upstream " + b.Name + " {\n")
+	comment(b.Comments)
+	if b.Zone != "" {
+		line("zone", strings.Fields(b.Zone)...)
+	}
+
+	// The balancing method has to come before keepalive.
+	switch b.Method {
+	case MethodLeastConn, MethodIPHash:
+		line(b.Method)
+	case MethodHash:
+		if b.HashConsistent {
+			line(b.Method, b.HashKey, "consistent")
+		} else {
+			line(b.Method, b.HashKey)
+		}
+	case MethodRandom:
+		args := []string{}
+		if b.RandomTwo {
+			args = append(args, "two")
+			if b.RandomMethod != "" {
+				args = append(args, b.RandomMethod)
+			}
+		}
+		line(b.Method, args...)
+	}
+
+	for _, server := range b.Servers {
+		comment(server.Comments)
+		line("server", server.args()...)
+	}
+
+	if b.Keepalive > 0 {
+		line("keepalive", strconv.Itoa(b.Keepalive))
+	}
+	if b.KeepaliveRequests > 0 {
+		line("keepalive_requests", strconv.Itoa(b.KeepaliveRequests))
+	}
+	if b.KeepaliveTime != "" {
+		line("keepalive_time", b.KeepaliveTime)
+	}
+	if b.KeepaliveTimeout != "" {
+		line("keepalive_timeout", b.KeepaliveTimeout)
+	}
+	for _, directive := range b.Directives {
+		line(directive.Name, directive.Args...)
+	}
+	buf.WriteString(indent + "}\n")
+	return buf.String()
+}
+
+func (s *Server) args() []string {
+	args := []string{s.Address}
+	if s.Weight > 0 {
+		args = append(args, "weight="+strconv.Itoa(s.Weight))
+	}
+	if s.MaxFails != nil {
+		args = append(args, "max_fails="+strconv.Itoa(*s.MaxFails))
+	}
+	if s.FailTimeout != "" {
+		args = append(args, "fail_timeout="+s.FailTimeout)
+	}
+	if s.MaxConns > 0 {
+		args = append(args, "max_conns="+strconv.Itoa(s.MaxConns))
+	}
+	args = append(args, s.Params...)
+	if s.Backup {
+		args = append(args, "backup")
+	}
+	if s.Down {
+		args = append(args, "down")
+	}
+	if s.Resolve {
+		args = append(args, "resolve")
+	}
+	return args
+}
+
+func quote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\r\n;{}#'\"\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+type token struct {
+	value      string
+	start, end int
+	quoted     bool
+	comment    bool
+}
+
+// tokenize splits a configuration into words, quoted strings, comments and
+// the ';', '{' and '}' separators. Like nginx, '#' only starts a comment at
+// the beginning of a word.
+func tokenize(content string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(content); {
+		switch c := content[i]; c {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '#':
+			start := i
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, token{value: strings.TrimRight(content[start:i], " \t\r"), start: start, end: i, comment: true})
+		case '{', '}', ';':
+			tokens = append(tokens, token{value: string(c), start: i, end: i + 1})
+			i++
+		case '\'', '"':
+			start := i
+			var value strings.Builder
+			for i++; ; i++ {
+				if i >= len(content) {
+					return nil, fmt.Errorf("unterminated quoted string at offset %d", start)
+				}
+				if content[i] == '\\' && i+1 < len(content) {
+					i++
+					value.WriteByte(content[i])
+					continue
+				}
+				if content[i] == c {
+					i++
+					break
+				}
+				value.WriteByte(content[i])
+			}
+			tokens = append(tokens, token{value: value.String(), start: start, end: i, quoted: true})
+		default:
+			start := i
+			for i < len(content) && !strings.ContainsRune(" \t\r\n{};", rune(content[i])) {
+				if content[i] == '\\' && i+1 < len(content) {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, token{value: content[start:i], start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
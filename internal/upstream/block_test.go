@@ -0,0 +1,178 @@
+package upstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const blockConfig = `http {
+    # backends
+    upstream backend {
+        # spread by connections
+        zone backend 64k;
+        least_conn;
+        server 10.0.0.1:8080 weight=5 max_fails=0 fail_timeout=10s slow_start=30s;
+        # standby
+        server app.internal:8080 backup resolve;
+        keepalive 32;
+        keepalive_timeout 60s;
+        ntlm;
+    }
+
+    upstream "legacy" {
+        hash $request_uri consistent;
+        server unix:/run/legacy.sock down;
+    }
+
+    map $host $pool {
+        upstream backend;
+    }
+
+    server {
+        location / {
+            proxy_pass http://backend;
+        }
+    }
+}
+`
+
+func TestParseBlocks(t *testing.T) {
+	blocks, err := ParseBlocks(blockConfig)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+
+	backend := blocks[0]
+	assert.Equal(t, "backend", backend.Name)
+	assert.Equal(t, MethodLeastConn, backend.Method)
+	assert.Equal(t, "backend 64k", backend.Zone)
+	assert.Equal(t, 32, backend.Keepalive)
+	assert.Equal(t, "60s", backend.KeepaliveTimeout)
+	assert.Equal(t, []*Directive{{Name: "ntlm", Args: []string{}}}, backend.Directives)
+	assert.Equal(t, []string{"# spread by connections"}, backend.Comments)
+
+	require.Len(t, backend.Servers, 2)
+	primary := backend.Servers[0]
+	assert.Equal(t, 5, primary.Weight)
+	require.NotNil(t, primary.MaxFails)
+	assert.Equal(t, 0, *primary.MaxFails)
+	assert.Equal(t, "10s", primary.FailTimeout)
+	assert.Equal(t, []string{"slow_start=30s"}, primary.Params)
+	standby := backend.Servers[1]
+	assert.True(t, standby.Backup)
+	assert.True(t, standby.Resolve)
+	assert.Equal(t, []string{"# standby"}, standby.Comments)
+
+	legacy := blocks[1]
+	assert.Equal(t, "legacy", legacy.Name)
+	assert.Equal(t, MethodHash, legacy.Method)
+	assert.Equal(t, "$request_uri", legacy.HashKey)
+	assert.True(t, legacy.HashConsistent)
+	assert.True(t, legacy.Servers[0].Down)
+}
+
+func TestRenderRoundTrips(t *testing.T) {
+	blocks, err := ParseBlocks(blockConfig)
+	require.NoError(t, err)
+
+	for _, block := range blocks {
+		require.NoError(t, block.Validate())
+		rendered, err := block.Render("")
+		require.NoError(t, err)
+		reparsed, err := ParseBlocks(rendered)
+		require.NoError(t, err)
+		require.Len(t, reparsed, 1)
+		rerendered, err := reparsed[0].Render("")
+		require.NoError(t, err)
+		assert.Equal(t, rendered, rerendered)
+	}
+
+	rendered, err := blocks[1].Render("")
+	require.NoError(t, err)
+	assert.Equal(t, `upstream legacy {
+    hash $request_uri consistent;
+    server unix:/run/legacy.sock down;
+}
+`, rendered)
+}
+
+func TestRenderRejectsInjectedDirectives(t *testing.T) {
+	newBlock := func() *Block {
+		return &Block{Name: "backend", Method: MethodRoundRobin, Servers: []*Server{{Address: "10.0.0.1:8080"}}}
+	}
+
+	for name, edit := range map[string]func(b *Block){
+		"comment without #":    func(b *Block) { b.Comments = []string{"keepalive 8;"} },
+		"comment with newline": func(b *Block) { b.Comments = []string{"# note\n}\nserver { listen 81; }"} },
+		"server comment":       func(b *Block) { b.Servers[0].Comments = []string{"# a\rb"} },
+		"directive name":       func(b *Block) { b.Directives = []*Directive{{Name: "ntlm; }\nserver {"}} },
+		"uppercase directive":  func(b *Block) { b.Directives = []*Directive{{Name: "NTLM"}} },
+	} {
+		block := newBlock()
+		edit(block)
+		_, err := block.Render("")
+		assert.Error(t, err, name)
+	}
+
+	block := newBlock()
+	block.Comments = []string{"# note"}
+	block.Directives = []*Directive{{Name: "ntlm"}}
+	_, err := block.Render("")
+	assert.NoError(t, err)
+}
+
+func TestReplaceBlockKeepsTheRestOfTheFile(t *testing.T) {
+	block, err := FindBlock(blockConfig, "backend")
+	require.NoError(t, err)
+	block.Servers[0].Down = true
+	block.Keepalive = 0
+
+	content, err := ReplaceBlock(blockConfig, block)
+	require.NoError(t, err)
+	assert.Contains(t, content, `    # backends
+    upstream backend {
+        # spread by connections
+        zone backend 64k;
+        least_conn;
+        server 10.0.0.1:8080 weight=5 max_fails=0 fail_timeout=10s slow_start=30s down;
+`)
+	assert.NotContains(t, content, "keepalive 32;")
+	assert.Contains(t, content, "    upstream \"legacy\" {\n")
+	assert.Contains(t, content, "proxy_pass http://backend;")
+
+	_, err = FindBlock(content, "missing")
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+}
+
+func TestValidateRejectsWhatNginxRejects(t *testing.T) {
+	valid := func() *Block {
+		return &Block{Name: "backend", Servers: []*Server{{Address: "10.0.0.1:8080"}}}
+	}
+	require.NoError(t, valid().Validate())
+
+	b := valid()
+	b.Servers = nil
+	assert.Error(t, b.Validate())
+
+	b = valid()
+	b.Method = MethodIPHash
+	b.Servers[0].Backup = true
+	assert.Error(t, b.Validate(), "backup cannot be used with ip_hash")
+
+	b = valid()
+	b.Servers[0].Resolve = true
+	assert.Error(t, b.Validate(), "resolve requires a zone")
+
+	b = valid()
+	b.Method = MethodHash
+	assert.Error(t, b.Validate(), "hash requires a key")
+
+	b = valid()
+	b.Servers[0].FailTimeout = "ten seconds"
+	assert.Error(t, b.Validate())
+
+	b = valid()
+	b.Servers[0].Address = "10.0.0.1:8080; evil"
+	assert.Error(t, b.Validate())
+}
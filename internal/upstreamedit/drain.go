@@ -0,0 +1,204 @@
+package upstreamedit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/uozi-tech/cosy"
+)
+
+const defaultDrainTimeout = 5 * time.Minute
+
+// DrainStatus is the progress of draining a server of an upstream.
+type DrainStatus struct {
+	Upstream    string    `json:"upstream"`
+	Address     string    `json:"address"`
+	StartedAt   time.Time `json:"started_at"`
+	Deadline    time.Time `json:"deadline"`
+	Connections int       `json:"connections"`
+	Done        bool      `json:"done"`
+	TimedOut    bool      `json:"timed_out"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type drain struct {
+	status DrainStatus
+	cancel context.CancelFunc
+}
+
+var (
+	drainMutex sync.Mutex
+	// drains holds the drains by upstream and server address.
+	drains = map[string]*drain{}
+
+	drainPollInterval = time.Second
+	countConnections  = activeConnections
+)
+
+func drainKey(name, address string) string {
+	return name + "/" + address
+}
+
+// Drain marks a server down so nginx stops sending it new requests, then
+// waits in the background until its open connections are finished or the
+// timeout passes. Either outcome is reported as a notification.
+func Drain(name, address string, timeout time.Duration) (*DrainStatus, error) {
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	if _, err := SetServerDown(name, address, true); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	d := &drain{cancel: cancel}
+	d.status = DrainStatus{
+		Upstream:  name,
+		Address:   address,
+		StartedAt: time.Now(),
+		Deadline:  time.Now().Add(timeout),
+	}
+
+	drainMutex.Lock()
+	if previous, ok := drains[drainKey(name, address)]; ok {
+		previous.cancel()
+	}
+	drains[drainKey(name, address)] = d
+	status := d.status
+	drainMutex.Unlock()
+
+	go d.wait(ctx)
+	return &status, nil
+}
+
+// Undrain stops waiting for a drain and brings the server back.
+func Undrain(name, address string) (*Result, error) {
+	drainMutex.Lock()
+	if d, ok := drains[drainKey(name, address)]; ok {
+		d.cancel()
+		delete(drains, drainKey(name, address))
+	}
+	drainMutex.Unlock()
+
+	return SetServerDown(name, address, false)
+}
+
+// GetDrains returns the drains of an upstream, including the finished ones
+// until the server is brought back.
+func GetDrains(name string) []DrainStatus {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	statuses := make([]DrainStatus, 0)
+	for _, d := range drains {
+		if d.status.Upstream == name {
+			statuses = append(statuses, d.status)
+		}
+	}
+	return statuses
+}
+
+func (d *drain) wait(ctx context.Context) {
+	defer d.cancel()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		count, err := countConnections(d.status.Address)
+
+		drainMutex.Lock()
+		d.status.Connections = count
+		if err != nil {
+			d.status.Error = err.Error()
+		}
+		d.status.Done = err != nil || count == 0
+		status := d.status
+		drainMutex.Unlock()
+
+		if status.Done {
+			notifyDrained(status)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return
+			}
+			drainMutex.Lock()
+			d.status.Done = true
+			d.status.TimedOut = true
+			status = d.status
+			drainMutex.Unlock()
+			notifyDrained(status)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func notifyDrained(status DrainStatus) {
+	details := map[string]any{
+		"upstream":    status.Upstream,
+		"address":     status.Address,
+		"connections": status.Connections,
+	}
+	switch {
+	case status.Error != "":
+		notification.Warning("Upstream Server Drain Unverified",
+			"Server %{address} of upstream %{upstream} is down, but its connections could not be counted", details)
+	case status.TimedOut:
+		notification.Warning("Upstream Server Drain Timed Out",
+			"Server %{address} of upstream %{upstream} still has %{connections} open connections", details)
+	default:
+		notification.Success("Upstream Server Drained",
+			"Server %{address} of upstream %{upstream} has no open connections left", details)
+	}
+}
+
+// activeConnections counts the established TCP connections of this host to a
+// server. The count includes connections of other processes than nginx.
+func activeConnections(address string) (int, error) {
+	if strings.HasPrefix(address, "unix:") {
+		return 0, cosy.WrapErrorWithParams(ErrCannotCount, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = strings.Trim(address, "[]"), "80"
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return 0, cosy.WrapErrorWithParams(ErrCannotCount, address)
+	}
+	ips, err := net.LookupHost(host)
+	if err != nil {
+		return 0, cosy.WrapErrorWithParams(ErrCannotCount, address)
+	}
+
+	connections, err := psnet.Connections("tcp")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, connection := range connections {
+		if connection.Status != "ESTABLISHED" || connection.Raddr.Port != uint32(portNumber) {
+			continue
+		}
+		for _, ip := range ips {
+			if net.ParseIP(ip).Equal(net.ParseIP(connection.Raddr.IP)) {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
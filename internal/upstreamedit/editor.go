@@ -0,0 +1,157 @@
+package upstreamedit
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/helper"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/uozi-tech/cosy"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+// Result is an upstream block along with the file defining it.
+type Result struct {
+	Block      *upstream.Block `json:"block"`
+	ConfigPath string          `json:"config_path"`
+}
+
+// mutex serializes the edits, so two of them never rewrite the same file at
+// once.
+var mutex sync.Mutex
+
+// configPath returns the file defining an upstream, as found by the config
+// scanner.
+func configPath(name string) (string, error) {
+	definition, ok := upstream.GetUpstreamService().GetUpstreamDefinition(name)
+	if !ok {
+		return "", cosy.WrapErrorWithParams(ErrUpstreamNotFound, name)
+	}
+	if !helper.IsUnderDirectory(definition.ConfigPath, nginx.GetConfPath()) {
+		return "", ErrNotUnderConfDir
+	}
+	return definition.ConfigPath, nil
+}
+
+func load(name string) (path, content string, block *upstream.Block, err error) {
+	path, err = configPath(name)
+	if err != nil {
+		return
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	content = string(raw)
+
+	block, err = upstream.FindBlock(content, name)
+	if errors.Is(err, upstream.ErrBlockNotFound) {
+		err = cosy.WrapErrorWithParams(ErrUpstreamNotFound, name)
+	} else if err != nil {
+		err = cosy.WrapErrorWithParams(ErrParseFailed, path, err.Error())
+	}
+	return
+}
+
+// Get returns the structured form of an upstream block.
+func Get(name string) (*Result, error) {
+	path, _, block, err := load(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Block: block, ConfigPath: path}, nil
+}
+
+// Save replaces an upstream block with block, leaving the rest of its file
+// untouched, and records the file in the git history as author.
+func Save(name string, block *upstream.Block, author githistory.Author) (*Result, error) {
+	if block.Name == "" {
+		block.Name = name
+	}
+	if block.Name != name {
+		return nil, ErrNameMismatch
+	}
+	return update(name, author, "Save upstream "+name, func(current *upstream.Block) error {
+		*current = *block
+		return nil
+	})
+}
+
+// SetServerDown marks a server of an upstream as down, or brings it back.
+func SetServerDown(name, address string, down bool) (*Result, error) {
+	return SetServersDown(name, map[string]bool{address: down})
+}
+
+// SetServersDown marks several servers of an upstream as down or up by
+// address, with a single reload.
+func SetServersDown(name string, down map[string]bool) (*Result, error) {
+	return update(name, githistory.SystemAuthor, "Update server states of upstream "+name, func(block *upstream.Block) error {
+		for address, value := range down {
+			server := block.Server(address)
+			if server == nil {
+				return cosy.WrapErrorWithParams(ErrServerNotFound, address)
+			}
+			server.Down = value
+		}
+		return nil
+	})
+}
+
+// update applies change to an upstream block and writes it back, then tests
+// and reloads nginx. When nginx rejects the result the file is restored,
+// otherwise it is synced to the cluster like a direct save.
+func update(name string, author githistory.Author, message string, change func(*upstream.Block) error) (*Result, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	path, content, block, err := load(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(block); err != nil {
+		return nil, err
+	}
+	if err := block.Validate(); err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrInvalidBlock, err.Error())
+	}
+
+	updated, err := upstream.ReplaceBlock(content, block)
+	if err != nil {
+		return nil, cosy.WrapErrorWithParams(ErrParseFailed, path, err.Error())
+	}
+	if updated != content {
+		if err := apply(path, content, updated); err != nil {
+			return nil, err
+		}
+		githistory.Record(author, message, path)
+		ownerOf(path).sync(updated)
+	}
+	return &Result{Block: block, ConfigPath: path}, nil
+}
+
+func apply(path, original, updated string) error {
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return err
+	}
+
+	restore := func() {
+		if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+			logger.Errorf("Failed to restore %s: %v", path, err)
+		}
+	}
+	if result := nginx.Control(nginx.TestConfig); result.IsError() {
+		restore()
+		return cosy.WrapErrorWithParams(ErrNginxTestFailed, result.GetOutput())
+	}
+	if result := nginx.Control(nginx.Reload); result.IsError() {
+		restore()
+		if retry := nginx.Control(nginx.Reload); retry.IsError() {
+			logger.Errorf("Failed to reload the restored configuration: %s", retry.GetOutput())
+		}
+		return cosy.WrapErrorWithParams(ErrNginxReloadFailed, result.GetOutput())
+	}
+	return nil
+}
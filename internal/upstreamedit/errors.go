@@ -0,0 +1,16 @@
+package upstreamedit
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                    = cosy.NewErrorScope("upstream_edit")
+	ErrInvalidBlock      = e.New(40001, "invalid upstream block: {0}")
+	ErrNameMismatch      = e.New(40002, "the upstream cannot be renamed")
+	ErrNotUnderConfDir   = e.New(40003, "the upstream is not defined under the nginx configuration directory")
+	ErrCannotCount       = e.New(40004, "connections to {0} cannot be counted")
+	ErrUpstreamNotFound  = e.New(40401, "upstream {0} not found")
+	ErrServerNotFound    = e.New(40402, "server {0} not found in the upstream")
+	ErrParseFailed       = e.New(50001, "failed to parse {0}: {1}")
+	ErrNginxTestFailed   = e.New(50002, "nginx test failed, the configuration was restored: {0}")
+	ErrNginxReloadFailed = e.New(50003, "nginx reload failed, the configuration was restored: {0}")
+)
@@ -0,0 +1,120 @@
+package upstreamedit
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/nginx"
+	"github.com/0xJacky/Nginx-UI/internal/site"
+	"github.com/0xJacky/Nginx-UI/internal/stream"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy/logger"
+	"gorm.io/gorm"
+)
+
+type ownerKind int
+
+const (
+	ownerConfig ownerKind = iota
+	ownerSite
+	ownerStream
+)
+
+// owner is the site or stream whose file defines an upstream. Any other file
+// is a plain config.
+type owner struct {
+	kind ownerKind
+	name string
+	path string
+}
+
+func ownerOf(path string) owner {
+	dirs := []struct {
+		kind ownerKind
+		dir  string
+	}{
+		{ownerSite, "sites-available"},
+		{ownerSite, "sites-enabled"},
+		{ownerStream, "streams-available"},
+		{ownerStream, "streams-enabled"},
+	}
+	for _, d := range dirs {
+		if filepath.Dir(path) == nginx.GetConfPath(d.dir) {
+			return owner{kind: d.kind, name: filepath.Base(path), path: path}
+		}
+	}
+	return owner{kind: ownerConfig, path: path}
+}
+
+// Namespace returns the namespace of the site or stream defining an upstream.
+// ok is false when the upstream lives in a file outside of any site or stream,
+// such as nginx.conf, which only unscoped subjects may change.
+func Namespace(name string) (namespaceID uint64, ok bool, err error) {
+	path, err := configPath(name)
+	if err != nil {
+		return 0, false, err
+	}
+
+	o := ownerOf(path)
+	switch o.kind {
+	case ownerSite:
+		available, err := site.ResolveAvailablePath(o.name)
+		if err != nil {
+			return 0, false, err
+		}
+		s := query.Site
+		record, err := s.Where(s.Path.Eq(available)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, true, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return record.NamespaceID, true, nil
+	case ownerStream:
+		available, err := stream.ResolveAvailablePath(o.name)
+		if err != nil {
+			return 0, false, err
+		}
+		s := query.Stream
+		record, err := s.Where(s.Path.Eq(available)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, true, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return record.NamespaceID, true, nil
+	default:
+		return 0, false, nil
+	}
+}
+
+// sync pushes a rewritten file to the nodes it is synced to, the way saving
+// the site, stream or config does.
+func (o owner) sync(content string) {
+	switch o.kind {
+	case ownerSite:
+		go site.SyncSave(o.name, content)
+	case ownerStream:
+		go stream.SyncSave(o.name, content)
+	default:
+		go syncConfig(o.path)
+	}
+}
+
+func syncConfig(path string) {
+	c := query.Config
+	cfg, err := c.Where(c.Filepath.Eq(path)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to load the config record of %s: %v", path, err)
+		return
+	}
+	if err := config.SyncToRemoteServer(cfg); err != nil {
+		logger.Errorf("Failed to sync %s: %v", path, err)
+	}
+}
@@ -0,0 +1,233 @@
+package upstreamedit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const upstreamConfig = `upstream backend {
+    least_conn;
+    server 10.0.0.1:8080 weight=2;
+    server 10.0.0.2:8080;
+    keepalive 16;
+}
+
+server {
+    listen 80;
+    location / {
+        proxy_pass http://backend;
+    }
+}
+`
+
+func TestSaveRewritesTheBlockAndRecordsHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	path, _ := setupEditTest(t)
+	originalHistory := *settings.GitHistorySettings
+	t.Cleanup(func() { *settings.GitHistorySettings = originalHistory })
+	settings.GitHistorySettings.Enabled = true
+	settings.GitHistorySettings.RepositoryPath = filepath.Join(t.TempDir(), "history.git")
+	require.NoError(t, githistory.Init())
+
+	result, err := Get("backend")
+	require.NoError(t, err)
+	assert.Equal(t, path, result.ConfigPath)
+
+	block := result.Block
+	block.Method = upstream.MethodIPHash
+	block.Servers[0].Weight = 0
+	block.Servers = append(block.Servers, &upstream.Server{Address: "10.0.0.3:8080", Backup: true})
+	_, err = Save("backend", block, githistory.SystemAuthor)
+	requireErrorCode(t, err, ErrInvalidBlock)
+
+	block.Servers[2].Backup = false
+	_, err = Save("backend", block, githistory.Author{Name: "alice", Email: "user-2@nginx-ui"})
+	require.NoError(t, err)
+	assert.Equal(t, `upstream backend {
+    ip_hash;
+    server 10.0.0.1:8080;
+    server 10.0.0.2:8080;
+    server 10.0.0.3:8080;
+    keepalive 16;
+}
+
+server {
+    listen 80;
+    location / {
+        proxy_pass http://backend;
+    }
+}
+`, readFile(t, path))
+
+	commits, err := githistory.Log("", 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, commits)
+	assert.Equal(t, "Save upstream backend", commits[0].Message)
+	assert.Equal(t, "alice", commits[0].AuthorName)
+
+	block.Name = "renamed"
+	_, err = Save("backend", block, githistory.SystemAuthor)
+	requireErrorCode(t, err, ErrNameMismatch)
+}
+
+func TestSetServerDownRestoresTheFileWhenNginxRejectsIt(t *testing.T) {
+	path, _ := setupEditTest(t)
+
+	_, err := SetServerDown("backend", "10.0.0.9:8080", true)
+	requireErrorCode(t, err, ErrServerNotFound)
+	_, err = SetServerDown("missing", "10.0.0.1:8080", true)
+	requireErrorCode(t, err, ErrUpstreamNotFound)
+
+	settings.NginxSettings.TestConfigCmd = "false"
+	_, err = SetServerDown("backend", "10.0.0.1:8080", true)
+	requireErrorCode(t, err, ErrNginxTestFailed)
+	assert.Equal(t, upstreamConfig, readFile(t, path))
+}
+
+func TestDrainWaitsForConnectionsToFinish(t *testing.T) {
+	path, db := setupEditTest(t)
+	connections := make(chan int, 3)
+	connections <- 2
+	connections <- 1
+	connections <- 0
+	countConnections = func(string) (int, error) { return <-connections, nil }
+
+	status, err := Drain("backend", "10.0.0.2:8080", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, status.Done)
+	assert.Contains(t, readFile(t, path), "server 10.0.0.2:8080 down;")
+
+	require.Eventually(t, func() bool {
+		drains := GetDrains("backend")
+		return len(drains) == 1 && drains[0].Done
+	}, 5*time.Second, 10*time.Millisecond)
+	drained := GetDrains("backend")[0]
+	assert.False(t, drained.TimedOut)
+	assert.Equal(t, 0, drained.Connections)
+
+	var notifications []model.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, model.NotificationSuccess, notifications[0].Type)
+
+	_, err = Undrain("backend", "10.0.0.2:8080")
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, path), "server 10.0.0.2:8080;")
+	assert.Empty(t, GetDrains("backend"))
+}
+
+func TestDrainTimesOut(t *testing.T) {
+	_, db := setupEditTest(t)
+	countConnections = func(string) (int, error) { return 3, nil }
+
+	_, err := Drain("backend", "10.0.0.1:8080", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		drains := GetDrains("backend")
+		return len(drains) == 1 && drains[0].TimedOut
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3, GetDrains("backend")[0].Connections)
+
+	var notifications []model.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, model.NotificationWarning, notifications[0].Type)
+}
+
+func setupEditTest(t *testing.T) (string, *gorm.DB) {
+	t.Helper()
+
+	originalDB := model.UseDB()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	originalPollInterval := drainPollInterval
+	t.Cleanup(func() {
+		drainMutex.Lock()
+		for _, d := range drains {
+			d.cancel()
+		}
+		drains = map[string]*drain{}
+		drainMutex.Unlock()
+		model.Use(originalDB)
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+		drainPollInterval = originalPollInterval
+		countConnections = activeConnections
+	})
+
+	confDir := t.TempDir()
+	path := filepath.Join(confDir, "conf.d", "backend.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(upstreamConfig), 0644))
+	upstream.GetUpstreamService().UpdateUpstreamDefinition("backend", nil, path)
+
+	settings.NginxSettings.ConfigDir = confDir
+	settings.NginxSettings.ReloadCmd = "true"
+	settings.NginxSettings.RestartCmd = "true"
+	settings.NginxSettings.TestConfigCmd = "true"
+	drainPollInterval = 10 * time.Millisecond
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.ConfigBackup{}, &model.Notification{}, &model.ExternalNotify{},
+		&model.Config{}, &model.Site{}, &model.Stream{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	return path, db
+}
+
+func TestNamespaceFollowsTheDefiningSite(t *testing.T) {
+	path, db := setupEditTest(t)
+
+	_, ok, err := Namespace("backend")
+	require.NoError(t, err)
+	assert.False(t, ok, "an upstream in conf.d belongs to no namespace")
+
+	sitePath := filepath.Join(filepath.Dir(filepath.Dir(path)), "sites-available", "example.com")
+	require.NoError(t, os.MkdirAll(filepath.Dir(sitePath), 0755))
+	require.NoError(t, os.WriteFile(sitePath, []byte(upstreamConfig), 0644))
+	require.NoError(t, db.Create(&model.Site{Path: sitePath, NamespaceID: 3}).Error)
+	upstream.GetUpstreamService().UpdateUpstreamDefinition("backend", nil, sitePath)
+
+	namespaceID, ok, err := Namespace("backend")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), namespaceID)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
@@ -0,0 +1,148 @@
+package upstreamhealth
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/0xJacky/Nginx-UI/internal/upstreamedit"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy"
+)
+
+const (
+	defaultIntervalSeconds = 10
+	defaultTimeoutSeconds  = 5
+	defaultRise            = 2
+	defaultFall            = 3
+)
+
+// NormalizedColumns lists the columns Normalize may fill in, so a partial
+// update can persist the defaults along with the changed fields.
+var NormalizedColumns = []string{
+	"scheme",
+	"path",
+	"interval_seconds",
+	"timeout_seconds",
+	"rise",
+	"fall",
+}
+
+// Normalize validates a check and fills in the defaults of unset fields.
+func Normalize(check *model.UpstreamHealthCheck) error {
+	if check.Upstream == "" || strings.ContainsAny(check.Upstream, " \t\r\n;{}#'\"") {
+		return cosy.WrapErrorWithParams(ErrInvalidUpstream, check.Upstream)
+	}
+
+	if check.Scheme == "" {
+		check.Scheme = "http"
+	}
+	if check.Scheme != "http" && check.Scheme != "https" {
+		return cosy.WrapErrorWithParams(ErrInvalidScheme, check.Scheme)
+	}
+	if check.Path == "" {
+		check.Path = "/"
+	}
+	if !strings.HasPrefix(check.Path, "/") {
+		return ErrInvalidPath
+	}
+
+	for _, status := range check.ExpectedStatus {
+		if status < 100 || status > 599 {
+			return cosy.WrapErrorWithParams(ErrInvalidStatus, strconv.Itoa(status))
+		}
+	}
+	if check.BodyPattern != "" {
+		if _, err := regexp.Compile(check.BodyPattern); err != nil {
+			return cosy.WrapErrorWithParams(ErrInvalidPattern, err.Error())
+		}
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"interval_seconds", &check.IntervalSeconds, defaultIntervalSeconds},
+		{"timeout_seconds", &check.TimeoutSeconds, defaultTimeoutSeconds},
+		{"rise", &check.Rise, defaultRise},
+		{"fall", &check.Fall, defaultFall},
+	} {
+		if *field.value < 0 {
+			return cosy.WrapErrorWithParams(ErrInvalidValue, field.name)
+		}
+		if *field.value == 0 {
+			*field.value = field.def
+		}
+	}
+	return nil
+}
+
+// GetEnabledChecks returns the checks that should be scheduled.
+func GetEnabledChecks() (checks []*model.UpstreamHealthCheck, err error) {
+	q := query.UpstreamHealthCheck
+	return q.Where(q.Enabled.Is(true)).Find()
+}
+
+// GetCheck loads a check by id.
+func GetCheck(id uint64) (*model.UpstreamHealthCheck, error) {
+	q := query.UpstreamHealthCheck
+	return q.Where(q.ID.Eq(id)).First()
+}
+
+// GetServerHealth returns the health of the servers probed by a check.
+func GetServerHealth(checkID uint64) (states []*model.UpstreamServerHealth, err error) {
+	s := query.UpstreamServerHealth
+	return s.Where(s.CheckID.Eq(checkID)).Order(s.Address).Find()
+}
+
+// RemoveCheckState brings back the servers a check marked down, then deletes
+// the server health and events of the check. Left down, those servers would
+// look taken down by hand to any later check.
+func RemoveCheckState(check *model.UpstreamHealthCheck) error {
+	s := query.UpstreamServerHealth
+	markedDown, err := s.Where(s.CheckID.Eq(check.ID), s.MarkedDown.Is(true)).Find()
+	if err != nil {
+		return err
+	}
+	if err := restoreServers(check.Upstream, markedDown); err != nil {
+		return err
+	}
+
+	if _, err := s.Where(s.CheckID.Eq(check.ID)).Delete(); err != nil {
+		return err
+	}
+	e := query.UpstreamHealthEvent
+	_, err = e.Where(e.CheckID.Eq(check.ID)).Delete()
+	return err
+}
+
+// restoreServers brings back the servers of states that are still down in the
+// upstream. Nothing is left to restore when the upstream was removed.
+func restoreServers(name string, states []*model.UpstreamServerHealth) error {
+	if len(states) == 0 {
+		return nil
+	}
+	result, err := upstreamedit.Get(name)
+	if err != nil {
+		var cErr *cosy.Error
+		if errors.As(err, &cErr) && cErr.Code == upstreamedit.ErrUpstreamNotFound.(*cosy.Error).Code {
+			return nil
+		}
+		return err
+	}
+
+	up := map[string]bool{}
+	for _, state := range states {
+		if server := result.Block.Server(state.Address); server != nil && server.Down {
+			up[state.Address] = false
+		}
+	}
+	if len(up) == 0 {
+		return nil
+	}
+	_, err = upstreamedit.SetServersDown(name, up)
+	return err
+}
@@ -0,0 +1,14 @@
+package upstreamhealth
+
+import "github.com/uozi-tech/cosy"
+
+var (
+	e                  = cosy.NewErrorScope("upstream_health")
+	ErrInvalidScheme   = e.New(40001, "unsupported scheme: {0}, use http or https")
+	ErrInvalidPath     = e.New(40002, "the path must start with /")
+	ErrInvalidPattern  = e.New(40003, "invalid body pattern: {0}")
+	ErrInvalidStatus   = e.New(40004, "invalid status code: {0}")
+	ErrInvalidValue    = e.New(40005, "{0} must not be negative")
+	ErrInvalidUpstream = e.New(40006, "invalid upstream name: {0}")
+	ErrLastServerKept  = e.New(40901, "no other server of the upstream is in rotation, it was kept up")
+)
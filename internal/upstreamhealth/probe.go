@@ -0,0 +1,95 @@
+package upstreamhealth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/model"
+)
+
+// maxBodySize limits how much of a response is matched against the body
+// pattern.
+const maxBodySize = 1 << 20
+
+// probeResult is the outcome of probing one server.
+type probeResult struct {
+	statusCode int
+	err        error
+}
+
+// probe requests the check path from a server directly, bypassing nginx.
+// Redirects are not followed, a 3xx status is the answer of the server.
+func probe(ctx context.Context, check *model.UpstreamHealthCheck, address string) probeResult {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(check.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	transport := &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: !check.VerifySSL},
+	}
+	host := address
+	if socket, ok := strings.CutPrefix(address, "unix:"); ok {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		host = "localhost"
+	}
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.Scheme+"://"+host+check.Path, nil)
+	if err != nil {
+		return probeResult{err: err}
+	}
+	if check.Host != "" {
+		req.Host = check.Host
+	}
+	req.Header.Set("User-Agent", "Nginx-UI-Health-Check")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeResult{err: err}
+	}
+	defer resp.Body.Close()
+
+	result := probeResult{statusCode: resp.StatusCode}
+	if len(check.ExpectedStatus) > 0 {
+		if !slices.Contains(check.ExpectedStatus, resp.StatusCode) {
+			result.err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+			return result
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		result.err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		return result
+	}
+
+	if check.BodyPattern != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			result.err = err
+			return result
+		}
+		pattern, err := regexp.Compile(check.BodyPattern)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		if !pattern.Match(body) {
+			result.err = fmt.Errorf("body does not match %s", check.BodyPattern)
+		}
+	}
+	return result
+}
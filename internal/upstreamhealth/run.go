@@ -0,0 +1,231 @@
+package upstreamhealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xJacky/Nginx-UI/internal/notification"
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/0xJacky/Nginx-UI/internal/upstreamedit"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/uozi-tech/cosy/logger"
+)
+
+var healthNow = time.Now
+
+// target is a server probed by a run along with its health.
+type target struct {
+	server *upstream.Server
+	state  *model.UpstreamServerHealth
+	result probeResult
+}
+
+// RunByID runs a check by id, it is the entry point of the scheduled jobs.
+func RunByID(ctx context.Context, id uint64) error {
+	check, err := GetCheck(id)
+	if err != nil {
+		return err
+	}
+	return Run(ctx, check)
+}
+
+// Run probes every server of the upstream once. Servers that turned unhealthy
+// are marked down and servers that recovered are brought back with a single
+// reload. The last servers in rotation are never marked down.
+func Run(ctx context.Context, check *model.UpstreamHealthCheck) error {
+	result, err := upstreamedit.Get(check.Upstream)
+	if err != nil {
+		return err
+	}
+
+	s := query.UpstreamServerHealth
+	stored, err := s.Where(s.CheckID.Eq(check.ID)).Find()
+	if err != nil {
+		return err
+	}
+	states := make(map[string]*model.UpstreamServerHealth, len(stored))
+	for _, state := range stored {
+		states[state.Address] = state
+	}
+
+	var targets []*target
+	for _, server := range result.Block.Servers {
+		state, ok := states[server.Address]
+		if !ok {
+			state = &model.UpstreamServerHealth{CheckID: check.ID, Address: server.Address, Healthy: true}
+		}
+		delete(states, server.Address)
+
+		if !server.Down {
+			state.MarkedDown = false
+		}
+		// Servers taken down by hand, or being drained, are left alone.
+		if server.Down && !state.MarkedDown {
+			continue
+		}
+		targets = append(targets, &target{server: server, state: state})
+	}
+	for _, state := range states {
+		if _, err := s.Delete(state); err != nil {
+			logger.Warnf("Failed to remove the health of %s: %v", state.Address, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.result = probe(ctx, check, t.server.Address)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := healthNow()
+	changes := map[string]bool{}
+	var changed []*target
+	for _, t := range targets {
+		if observe(check, t.state, t.result, now) {
+			changed = append(changed, t)
+		}
+		// Unhealthy servers kept in rotation are marked down once another
+		// server is back.
+		switch {
+		case !t.state.Healthy && !t.server.Down:
+			changes[t.server.Address] = true
+		case t.state.Healthy && t.state.MarkedDown:
+			changes[t.server.Address] = false
+		}
+	}
+
+	kept := keepLastServers(result.Block.Servers, changes)
+
+	var applyErr error
+	if len(changes) > 0 {
+		_, applyErr = upstreamedit.SetServersDown(check.Upstream, changes)
+	}
+	for _, t := range targets {
+		if _, wanted := changes[t.server.Address]; wanted && applyErr == nil {
+			t.state.MarkedDown = !t.state.Healthy
+		}
+	}
+	for _, t := range changed {
+		_, wanted := changes[t.server.Address]
+		if kept[t.server.Address] {
+			record(check, t, true, ErrLastServerKept)
+			continue
+		}
+		record(check, t, wanted, applyErr)
+	}
+
+	for _, t := range targets {
+		if err := s.Save(t.state); err != nil {
+			return err
+		}
+	}
+	return applyErr
+}
+
+// keepLastServers drops the mark downs that would leave the upstream without
+// a server in rotation and returns the servers it kept up. A wrong path or
+// host fails every probe, taking all servers out would turn that into an
+// outage.
+func keepLastServers(servers []*upstream.Server, changes map[string]bool) map[string]bool {
+	up := 0
+	for _, server := range servers {
+		if !server.Down {
+			up++
+		}
+	}
+	for _, down := range changes {
+		if down {
+			up--
+		} else {
+			up++
+		}
+	}
+	if up > 0 {
+		return nil
+	}
+
+	kept := map[string]bool{}
+	for address, down := range changes {
+		if down {
+			kept[address] = true
+			delete(changes, address)
+		}
+	}
+	return kept
+}
+
+// observe counts a probe and reports whether the server changed its health.
+func observe(check *model.UpstreamHealthCheck, state *model.UpstreamServerHealth, result probeResult, now time.Time) bool {
+	state.LastCheckedAt = &now
+	state.LastStatusCode = result.statusCode
+
+	if result.err == nil {
+		state.LastError = ""
+		state.Successes++
+		state.Failures = 0
+		if !state.Healthy && state.Successes >= check.Rise {
+			state.Healthy = true
+			return true
+		}
+		return false
+	}
+
+	state.LastError = result.err.Error()
+	state.Failures++
+	state.Successes = 0
+	if state.Healthy && state.Failures >= check.Fall {
+		state.Healthy = false
+		return true
+	}
+	return false
+}
+
+// record stores a health change as an event and notifies about it. wanted
+// tells whether the change had to be applied to the configuration.
+func record(check *model.UpstreamHealthCheck, t *target, wanted bool, applyErr error) {
+	event := &model.UpstreamHealthEvent{
+		CheckID:  check.ID,
+		Upstream: check.Upstream,
+		Address:  t.server.Address,
+		Healthy:  t.state.Healthy,
+		Applied:  wanted && applyErr == nil,
+		Reason:   t.state.LastError,
+	}
+	if wanted && applyErr != nil {
+		event.Reason = applyErr.Error()
+	}
+	if err := query.UpstreamHealthEvent.Create(event); err != nil {
+		logger.Errorf("Failed to record the health event of %s: %v", t.server.Address, err)
+	}
+
+	details := map[string]any{
+		"upstream": check.Upstream,
+		"address":  t.server.Address,
+		"reason":   event.Reason,
+	}
+	switch {
+	case wanted && applyErr != nil:
+		notification.WarningTo("Upstream Server Health Not Applied",
+			"The health of server %{address} of upstream %{upstream} changed, but the configuration could not be updated: %{reason}",
+			details, check.ExternalNotifyIDs)
+	case t.state.Healthy:
+		details["count"] = check.Rise
+		notification.SuccessTo("Upstream Server Recovered",
+			"Server %{address} of upstream %{upstream} passed %{count} health checks in a row and is back in rotation",
+			details, check.ExternalNotifyIDs)
+	default:
+		details["count"] = check.Fall
+		notification.WarningTo("Upstream Server Unhealthy",
+			"Server %{address} of upstream %{upstream} failed %{count} health checks in a row and was taken out of rotation: %{reason}",
+			details, check.ExternalNotifyIDs)
+	}
+}
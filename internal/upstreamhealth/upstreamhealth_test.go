@@ -0,0 +1,306 @@
+package upstreamhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/0xJacky/Nginx-UI/internal/upstream"
+	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
+	"github.com/0xJacky/Nginx-UI/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uozi-tech/cosy"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeFillsDefaultsAndRejectsInvalidChecks(t *testing.T) {
+	check := &model.UpstreamHealthCheck{Upstream: "backend"}
+	require.NoError(t, Normalize(check))
+	assert.Equal(t, "http", check.Scheme)
+	assert.Equal(t, "/", check.Path)
+	assert.Equal(t, defaultIntervalSeconds, check.IntervalSeconds)
+	assert.Equal(t, defaultRise, check.Rise)
+	assert.Equal(t, defaultFall, check.Fall)
+
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend; evil"}), ErrInvalidUpstream)
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend", Scheme: "ftp"}), ErrInvalidScheme)
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend", Path: "health"}), ErrInvalidPath)
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend", ExpectedStatus: []int{42}}), ErrInvalidStatus)
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend", BodyPattern: "(ok"}), ErrInvalidPattern)
+	requireErrorCode(t, Normalize(&model.UpstreamHealthCheck{Upstream: "backend", Fall: -1}), ErrInvalidValue)
+}
+
+func TestProbeChecksStatusAndBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, "status: ok, host: "+r.Host)
+		case "/moved":
+			http.Redirect(w, r, "/health", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	check := &model.UpstreamHealthCheck{Upstream: "backend", Path: "/health", BodyPattern: `status: ok`}
+	require.NoError(t, Normalize(check))
+	result := probe(context.Background(), check, address)
+	require.NoError(t, result.err)
+	assert.Equal(t, http.StatusOK, result.statusCode)
+
+	check.Host = "example.com"
+	check.BodyPattern = `host: example\.com$`
+	assert.NoError(t, probe(context.Background(), check, address).err)
+
+	check.BodyPattern = `status: degraded`
+	assert.Error(t, probe(context.Background(), check, address).err)
+
+	check.BodyPattern = ""
+	check.Path = "/missing"
+	result = probe(context.Background(), check, address)
+	assert.Error(t, result.err)
+	assert.Equal(t, http.StatusNotFound, result.statusCode)
+
+	check.ExpectedStatus = []int{http.StatusNotFound}
+	assert.NoError(t, probe(context.Background(), check, address).err)
+
+	check.ExpectedStatus = nil
+	check.Path = "/moved"
+	result = probe(context.Background(), check, address)
+	assert.NoError(t, result.err, "a redirect is a healthy answer")
+	assert.Equal(t, http.StatusFound, result.statusCode)
+}
+
+func TestRunMarksFailingServersDownAndBringsThemBack(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(flaky.Close)
+	stable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(stable.Close)
+
+	flakyAddress := strings.TrimPrefix(flaky.URL, "http://")
+	stableAddress := strings.TrimPrefix(stable.URL, "http://")
+	path, db := setupRunTest(t, fmt.Sprintf(`upstream backend {
+    server %s;
+    server %s;
+    server 192.0.2.1:8080 down;
+}
+`, flakyAddress, stableAddress))
+
+	check := &model.UpstreamHealthCheck{Model: model.Model{ID: 1}, Upstream: "backend", Rise: 2, Fall: 2}
+	require.NoError(t, Normalize(check))
+
+	require.NoError(t, Run(context.Background(), check))
+	assert.NotContains(t, readFile(t, path), flakyAddress+" down;", "a single failure is not enough")
+
+	require.NoError(t, Run(context.Background(), check))
+	assert.Contains(t, readFile(t, path), "server "+flakyAddress+" down;")
+
+	states, err := GetServerHealth(check.ID)
+	require.NoError(t, err)
+	require.Len(t, states, 2, "the server taken down by hand is not probed")
+	for _, state := range states {
+		if state.Address == flakyAddress {
+			assert.False(t, state.Healthy)
+			assert.True(t, state.MarkedDown)
+			assert.Equal(t, http.StatusBadGateway, state.LastStatusCode)
+		} else {
+			assert.True(t, state.Healthy)
+		}
+	}
+
+	failing.Store(false)
+	require.NoError(t, Run(context.Background(), check))
+	assert.Contains(t, readFile(t, path), "server "+flakyAddress+" down;")
+	require.NoError(t, Run(context.Background(), check))
+	content := readFile(t, path)
+	assert.Contains(t, content, "server "+flakyAddress+";")
+	assert.Contains(t, content, "server 192.0.2.1:8080 down;")
+
+	var events []model.UpstreamHealthEvent
+	require.NoError(t, db.Order("id").Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.False(t, events[0].Healthy)
+	assert.True(t, events[0].Applied)
+	assert.Equal(t, "unexpected status 502", events[0].Reason)
+	assert.True(t, events[1].Healthy)
+
+	var notifications []model.Notification
+	require.NoError(t, db.Order("id").Find(&notifications).Error)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, model.NotificationWarning, notifications[0].Type)
+	assert.Equal(t, model.NotificationSuccess, notifications[1].Type)
+}
+
+func TestRunKeepsTheServerWhenNginxRejectsTheChange(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(broken.Close)
+	stable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(stable.Close)
+	address := strings.TrimPrefix(broken.URL, "http://")
+	config := fmt.Sprintf("upstream backend {\n    server %s;\n    server %s;\n}\n",
+		address, strings.TrimPrefix(stable.URL, "http://"))
+	path, db := setupRunTest(t, config)
+	settings.NginxSettings.TestConfigCmd = "false"
+
+	check := &model.UpstreamHealthCheck{Model: model.Model{ID: 1}, Upstream: "backend", Fall: 1}
+	require.NoError(t, Normalize(check))
+	assert.Error(t, Run(context.Background(), check))
+	assert.Equal(t, config, readFile(t, path))
+
+	states, err := GetServerHealth(check.ID)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	for _, state := range states {
+		if state.Address == address {
+			assert.False(t, state.Healthy)
+			assert.False(t, state.MarkedDown)
+		}
+	}
+
+	var event model.UpstreamHealthEvent
+	require.NoError(t, db.First(&event).Error)
+	assert.False(t, event.Applied)
+	assert.Contains(t, event.Reason, "nginx test failed")
+}
+
+func TestRunNeverTakesOutTheLastServers(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	first := httptest.NewServer(handler)
+	t.Cleanup(first.Close)
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(second.Close)
+
+	firstAddress := strings.TrimPrefix(first.URL, "http://")
+	secondAddress := strings.TrimPrefix(second.URL, "http://")
+	config := fmt.Sprintf("upstream backend {\n    server %s;\n    server %s;\n}\n", firstAddress, secondAddress)
+	path, db := setupRunTest(t, config)
+
+	check := &model.UpstreamHealthCheck{Model: model.Model{ID: 1}, Upstream: "backend", Rise: 1, Fall: 1}
+	require.NoError(t, Normalize(check))
+	require.NoError(t, Run(context.Background(), check))
+	assert.Equal(t, config, readFile(t, path), "a check failing everywhere must not empty the upstream")
+
+	var events []model.UpstreamHealthEvent
+	require.NoError(t, db.Order("id").Find(&events).Error)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.False(t, event.Applied)
+		assert.Equal(t, ErrLastServerKept.Error(), event.Reason)
+	}
+
+	// Once a server is back the one still failing is taken out
+	failing.Store(false)
+	require.NoError(t, Run(context.Background(), check))
+	content := readFile(t, path)
+	assert.Contains(t, content, "server "+firstAddress+";")
+	assert.Contains(t, content, "server "+secondAddress+" down;")
+}
+
+func TestRemoveCheckStateBringsBackMarkedDownServers(t *testing.T) {
+	path, db := setupRunTest(t, `upstream backend {
+    server 192.0.2.1:8080 down;
+    server 192.0.2.2:8080 down;
+    server 192.0.2.3:8080;
+}
+`)
+	check := &model.UpstreamHealthCheck{Model: model.Model{ID: 1}, Upstream: "backend"}
+	require.NoError(t, db.Create(&[]model.UpstreamServerHealth{
+		{CheckID: check.ID, Address: "192.0.2.1:8080", MarkedDown: true},
+		{CheckID: check.ID, Address: "192.0.2.3:8080", Healthy: true},
+	}).Error)
+
+	require.NoError(t, RemoveCheckState(check))
+	content := readFile(t, path)
+	assert.Contains(t, content, "server 192.0.2.1:8080;")
+	assert.Contains(t, content, "server 192.0.2.2:8080 down;", "servers taken down by hand stay down")
+
+	states, err := GetServerHealth(check.ID)
+	require.NoError(t, err)
+	assert.Empty(t, states)
+}
+
+func setupRunTest(t *testing.T, config string) (string, *gorm.DB) {
+	t.Helper()
+
+	originalDB := model.UseDB()
+	originalConfigDir := settings.NginxSettings.ConfigDir
+	originalReloadCmd := settings.NginxSettings.ReloadCmd
+	originalRestartCmd := settings.NginxSettings.RestartCmd
+	originalTestConfigCmd := settings.NginxSettings.TestConfigCmd
+	t.Cleanup(func() {
+		model.Use(originalDB)
+		settings.NginxSettings.ConfigDir = originalConfigDir
+		settings.NginxSettings.ReloadCmd = originalReloadCmd
+		settings.NginxSettings.RestartCmd = originalRestartCmd
+		settings.NginxSettings.TestConfigCmd = originalTestConfigCmd
+	})
+
+	confDir := t.TempDir()
+	path := filepath.Join(confDir, "conf.d", "backend.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(config), 0644))
+	upstream.GetUpstreamService().UpdateUpstreamDefinition("backend", nil, path)
+
+	settings.NginxSettings.ConfigDir = confDir
+	settings.NginxSettings.ReloadCmd = "true"
+	settings.NginxSettings.RestartCmd = "true"
+	settings.NginxSettings.TestConfigCmd = "true"
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.ConfigBackup{}, &model.Notification{}, &model.ExternalNotify{},
+		&model.UpstreamServerHealth{}, &model.UpstreamHealthEvent{}))
+	model.Use(db)
+	query.SetDefault(db)
+
+	return path, db
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func requireErrorCode(t *testing.T, err error, want error) {
+	t.Helper()
+	var cErr *cosy.Error
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, want.(*cosy.Error).Code, cErr.Code)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xJacky/Nginx-UI/settings"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrApprovalRequired is returned by the tools that write configuration while
+// change set approval is enforced.
+var ErrApprovalRequired = errors.New("direct edits are disabled, submit a change set for approval instead")
+
+// requireChangeSet wraps a tool that writes configuration so it is refused
+// while change set approval is enforced, like the matching HTTP endpoints.
+func requireChangeSet(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		if settings.ChangeSetSettings.RequireApproval {
+			return nil, ErrApprovalRequired
+		}
+		return handler(ctx, request)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
 	"github.com/0xJacky/Nginx-UI/internal/mcp"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/query"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 )
@@ -43,6 +44,10 @@ func handleNginxConfigGet(ctx context.Context, request mcpgo.CallToolRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	subject := mcp.SubjectFromContext(ctx)
+	if !subject.Can(rbac.ResourceCertificates, rbac.ActionWrite) && config.IsKeyMaterial(absPath, content) {
+		return nil, config.ErrKeyMaterialDenied
+	}
 
 	q := query.Config
 	cfg, err := q.Where(q.Filepath.Eq(absPath)).FirstOrInit()
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/0xJacky/Nginx-UI/internal/config"
+	"github.com/0xJacky/Nginx-UI/internal/githistory"
 	"github.com/0xJacky/Nginx-UI/internal/helper"
 	"github.com/0xJacky/Nginx-UI/internal/mcp"
 	"github.com/0xJacky/Nginx-UI/model"
@@ -89,7 +90,7 @@ func handleNginxConfigModify(ctx context.Context, request mcpgo.CallToolRequest)
 	cfg.SyncNodeIds = syncNodeIds
 	cfg.SyncOverwrite = syncOverwrite
 
-	err = config.Save(absPath, content, cfg)
+	err = config.Save(absPath, content, cfg, githistory.MCPAuthor)
 	if err != nil {
 		return nil, err
 	}
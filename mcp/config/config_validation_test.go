@@ -32,6 +32,30 @@ func TestNginxConfigAddRejectsRestrictedDirectiveContent(t *testing.T) {
 	}
 }
 
+func TestNginxConfigAddRequiresChangeSetWhileApprovalIsEnforced(t *testing.T) {
+	confDir := setupMCPConfigValidationTest(t)
+	original := appsettings.ChangeSetSettings.RequireApproval
+	appsettings.ChangeSetSettings.RequireApproval = true
+	t.Cleanup(func() { appsettings.ChangeSetSettings.RequireApproval = original })
+
+	_, err := requireChangeSet(handleNginxConfigAdd)(context.Background(), mcpgo.CallToolRequest{
+		Params: mcpgo.CallToolParams{
+			Arguments: map[string]any{
+				"name":    "app.conf",
+				"content": "server { listen 80; }",
+			},
+		},
+	})
+	if !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("expected ErrApprovalRequired, got %v", err)
+	}
+
+	path := filepath.Join(confDir, "app.conf")
+	if _, statErr := os.Stat(path); !errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("expected refused MCP add to leave %q absent, stat error: %v", path, statErr)
+	}
+}
+
 func TestNginxConfigModifyRejectsStatementSeparatedRestrictedDirectiveContent(t *testing.T) {
 	confDir := setupMCPConfigValidationTest(t)
 	path := filepath.Join(confDir, "app.conf")
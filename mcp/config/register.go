@@ -5,13 +5,13 @@ import (
 )
 
 func Init() {
-	mcp.AddTool(nginxConfigAddTool, handleNginxConfigAdd)
+	mcp.AddTool(nginxConfigAddTool, requireChangeSet(handleNginxConfigAdd))
 	mcp.AddTool(nginxConfigBasePathTool, handleNginxConfigBasePath)
-	mcp.AddTool(nginxConfigEnableTool, handleNginxConfigEnable)
+	mcp.AddTool(nginxConfigEnableTool, requireChangeSet(handleNginxConfigEnable))
 	mcp.AddTool(nginxConfigGetTool, handleNginxConfigGet)
 	mcp.AddTool(nginxConfigHistoryTool, handleNginxConfigHistory)
 	mcp.AddTool(nginxConfigListTool, handleNginxConfigList)
 	mcp.AddTool(nginxConfigMkdirTool, handleNginxConfigMkdir)
-	mcp.AddTool(nginxConfigModifyTool, handleNginxConfigModify)
-	mcp.AddTool(nginxConfigRenameTool, handleNginxConfigRename)
+	mcp.AddTool(nginxConfigModifyTool, requireChangeSet(handleNginxConfigModify))
+	mcp.AddTool(nginxConfigRenameTool, requireChangeSet(handleNginxConfigRename))
 }
@@ -6,14 +6,17 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	internalmcp "github.com/0xJacky/Nginx-UI/internal/mcp"
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
 	"github.com/0xJacky/Nginx-UI/internal/nodeauth"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/internal/user"
 	"github.com/0xJacky/Nginx-UI/model"
+	"github.com/0xJacky/Nginx-UI/query"
 	"github.com/0xJacky/Nginx-UI/settings"
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +31,32 @@ var sensitiveMCPTools = map[string]struct{}{
 	"restart_nginx":       {},
 }
 
+type mcpToolPermission struct {
+	resource rbac.Resource
+	action   rbac.Action
+}
+
+// mcpToolPermissions maps every tool to the permission it needs on top of the
+// "mcp" one. Tools missing here cannot be called.
+var mcpToolPermissions = map[string]mcpToolPermission{
+	"nginx_config_add":       {rbac.ResourceConfigs, rbac.ActionWrite},
+	"nginx_config_base_path": {rbac.ResourceConfigs, rbac.ActionRead},
+	"nginx_config_enable":    {rbac.ResourceConfigs, rbac.ActionWrite},
+	"nginx_config_get":       {rbac.ResourceConfigs, rbac.ActionRead},
+	"nginx_config_history":   {rbac.ResourceConfigs, rbac.ActionRead},
+	"nginx_config_list":      {rbac.ResourceConfigs, rbac.ActionRead},
+	"nginx_config_mkdir":     {rbac.ResourceConfigs, rbac.ActionWrite},
+	"nginx_config_modify":    {rbac.ResourceConfigs, rbac.ActionWrite},
+	"nginx_config_rename":    {rbac.ResourceConfigs, rbac.ActionWrite},
+	"nginx_status":           {rbac.ResourceNginx, rbac.ActionRead},
+	"reload_nginx":           {rbac.ResourceNginx, rbac.ActionWrite},
+	"restart_nginx":          {rbac.ResourceNginx, rbac.ActionWrite},
+}
+
+// Config tools read and write raw files that cannot be narrowed to the
+// namespaces of a scoped subject.
+const mcpConfigToolPrefix = "nginx_config_"
+
 type mcpToolCallProbe struct {
 	Method string `json:"method"`
 	Params struct {
@@ -122,6 +151,21 @@ func authorizeMCPToolRequest() gin.HandlerFunc {
 			c.Request.Body = io.NopCloser(bytes.NewReader(body))
 		}
 
+		if mcpRequestCallsConfigTool(body) && middleware.CurrentSubject(c).IsNamespaceScoped() {
+			middleware.AbortWithNamespaceDenied(c)
+			return
+		}
+
+		subject := mcpToolSubject(c)
+		for _, name := range mcpRequestToolNames(body) {
+			permission, ok := mcpToolPermissions[name]
+			if !ok || !subject.Can(permission.resource, permission.action) {
+				c.AbortWithStatusJSON(http.StatusForbidden, rbac.ErrPermissionDenied)
+				return
+			}
+		}
+		c.Request = c.Request.WithContext(internalmcp.WithSubject(c.Request.Context(), subject))
+
 		requiredScope := model.MCPTokenScopeRead
 		if mcpRequestNeedsSecureSession(body) {
 			requiredScope = model.MCPTokenScopeWrite
@@ -136,6 +180,17 @@ func authorizeMCPToolRequest() gin.HandlerFunc {
 			return
 		}
 
+		// The MCP scopes double as the "mcp" resource permissions, so users
+		// are held to the same rule through their role.
+		action := rbac.ActionRead
+		if requiredScope == model.MCPTokenScopeWrite {
+			action = rbac.ActionWrite
+		}
+		if !middleware.CurrentSubject(c).Can(rbac.ResourceMCP, action) {
+			c.AbortWithStatusJSON(http.StatusForbidden, rbac.ErrPermissionDenied)
+			return
+		}
+
 		if requiredScope == model.MCPTokenScopeWrite {
 			requireSecureSession(c)
 			return
@@ -144,39 +199,71 @@ func authorizeMCPToolRequest() gin.HandlerFunc {
 	}
 }
 
+// mcpToolSubject returns the subject the tools of a request run as. A service
+// token only carries the "mcp" scopes, so its tools are held to the role of
+// the user who created it.
+func mcpToolSubject(c *gin.Context) *rbac.Subject {
+	value, ok := c.Get(internalmcp.ServiceTokenPrincipalKey)
+	if !ok {
+		return middleware.CurrentSubject(c)
+	}
+	principal, valid := value.(*internalmcp.ServiceTokenPrincipal)
+	if !valid {
+		return &rbac.Subject{}
+	}
+
+	u := query.User
+	creator, err := u.Where(u.ID.Eq(principal.CreatorID), u.Status.Is(true)).First()
+	if err != nil {
+		return &rbac.Subject{}
+	}
+	return rbac.ForUser(creator)
+}
+
 func mcpRequestNeedsSecureSession(body []byte) bool {
+	return mcpRequestCallsTool(body, func(name string) bool {
+		_, ok := sensitiveMCPTools[name]
+		return ok
+	})
+}
+
+func mcpRequestCallsConfigTool(body []byte) bool {
+	return mcpRequestCallsTool(body, func(name string) bool {
+		return strings.HasPrefix(name, mcpConfigToolPrefix)
+	})
+}
+
+// mcpRequestCallsTool reports whether a message, or any message of a batch,
+// calls a tool matched by match.
+func mcpRequestCallsTool(body []byte, match func(name string) bool) bool {
+	return slices.ContainsFunc(mcpRequestToolNames(body), match)
+}
+
+// mcpRequestToolNames returns the tools called by a message or a batch.
+func mcpRequestToolNames(body []byte) []string {
 	body = bytes.TrimSpace(body)
 	if len(body) == 0 {
-		return false
+		return nil
 	}
 
+	var messages []mcpToolCallProbe
 	if body[0] == '[' {
-		var messages []mcpToolCallProbe
 		if err := json.Unmarshal(body, &messages); err != nil {
-			return false
+			return nil
 		}
-
-		for _, message := range messages {
-			if mcpMessageNeedsSecureSession(message) {
-				return true
-			}
+	} else {
+		var message mcpToolCallProbe
+		if err := json.Unmarshal(body, &message); err != nil {
+			return nil
 		}
-		return false
-	}
-
-	var message mcpToolCallProbe
-	if err := json.Unmarshal(body, &message); err != nil {
-		return false
+		messages = append(messages, message)
 	}
 
-	return mcpMessageNeedsSecureSession(message)
-}
-
-func mcpMessageNeedsSecureSession(message mcpToolCallProbe) bool {
-	if message.Method != "tools/call" {
-		return false
+	var names []string
+	for _, message := range messages {
+		if message.Method == "tools/call" {
+			names = append(names, message.Params.Name)
+		}
 	}
-
-	_, ok := sensitiveMCPTools[message.Params.Name]
-	return ok
+	return names
 }
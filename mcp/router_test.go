@@ -270,3 +270,53 @@ func TestMCPRequestNeedsSecureSession(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPConfigToolsRejectNamespaceScopedTokens(t *testing.T) {
+	router, _, userID := setupMCPSecurityRouter(t)
+	_, scopedToken, err := internalmcp.CreateServiceToken("scoped", []string{model.MCPTokenScopeRead}, nil, userID, 1)
+	require.NoError(t, err)
+
+	call := func(tool string) int {
+		request := httptest.NewRequest(http.MethodPost, "/mcp_message", bytes.NewBufferString(fmt.Sprintf(`{
+			"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q}
+		}`, tool)))
+		request.Header.Set("Authorization", "Bearer "+scopedToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, call("nginx_config_get"))
+	assert.Equal(t, http.StatusForbidden, call("nginx_config_list"))
+	assert.NotEqual(t, http.StatusForbidden, call("nginx_status"))
+}
+
+// TestMCPToolsRequireThePermissionTheyUse covers the per tool permissions: a
+// service token is held to the role of its creator, and unknown tools are
+// refused.
+func TestMCPToolsRequireThePermissionTheyUse(t *testing.T) {
+	router, _, userID := setupMCPSecurityRouter(t)
+	viewer := &model.User{Name: "viewer", Status: true, Language: "en", Role: model.RoleViewer}
+	require.NoError(t, query.User.Create(viewer))
+
+	_, viewerToken, err := internalmcp.CreateServiceToken("viewer", []string{model.MCPTokenScopeWrite}, nil, viewer.ID)
+	require.NoError(t, err)
+	_, adminToken, err := internalmcp.CreateServiceToken("admin", []string{model.MCPTokenScopeWrite}, nil, userID)
+	require.NoError(t, err)
+
+	call := func(token, tool string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp_message", bytes.NewBufferString(fmt.Sprintf(`{
+			"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q}
+		}`, tool)))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, call(viewerToken, "nginx_config_get"))
+	assert.Equal(t, http.StatusForbidden, call(viewerToken, "restart_nginx"))
+	assert.NotEqual(t, http.StatusForbidden, call(viewerToken, "nginx_status"))
+	assert.NotEqual(t, http.StatusForbidden, call(adminToken, "nginx_config_get"))
+	assert.Equal(t, http.StatusForbidden, call(adminToken, "unknown_tool"))
+}
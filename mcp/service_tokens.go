@@ -8,6 +8,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/api/audit"
 	internalmcp "github.com/0xJacky/Nginx-UI/internal/mcp"
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/model"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -48,9 +49,30 @@ func initServiceTokenManagementRouter(group *gin.RouterGroup) {
 	mutations.DELETE("/:id", RevokeServiceToken)
 }
 
+// visibleServiceTokens limits users who cannot manage other accounts to the
+// tokens they created themselves.
+func visibleServiceTokens(c *gin.Context) *gorm.DB {
+	db := model.UseDB().Model(&model.MCPServiceToken{})
+	if middleware.CurrentSubject(c).Can(rbac.ResourceUsers, rbac.ActionWrite) {
+		return db
+	}
+	return db.Where("creator_id = ?", currentMCPUserID(c))
+}
+
+func ensureServiceTokenVisible(c *gin.Context, publicID string) error {
+	var count int64
+	if err := visibleServiceTokens(c).Where("public_id = ?", publicID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func ListServiceTokens(c *gin.Context) {
 	var records []model.MCPServiceToken
-	if err := model.UseDB().Order("created_at DESC").Find(&records).Error; err != nil {
+	if err := visibleServiceTokens(c).Order("created_at DESC").Find(&records).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
@@ -67,11 +89,19 @@ func CreateServiceToken(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
+	// A token can never carry more than its creator holds, including the
+	// namespace restriction.
+	subject := middleware.CurrentSubject(c)
+	if !subject.Covers(rbac.ForScopes(request.Scopes, nil).Permissions) {
+		c.JSON(http.StatusForbidden, rbac.ErrPermissionEscalation)
+		return
+	}
 	record, rawToken, err := internalmcp.CreateServiceToken(
 		request.Name,
 		request.Scopes,
 		request.ExpiresAt,
 		currentMCPUserID(c),
+		subject.NamespaceIDs...,
 	)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
@@ -82,7 +112,14 @@ func CreateServiceToken(c *gin.Context) {
 }
 
 func RotateServiceToken(c *gin.Context) {
-	record, rawToken, err := internalmcp.RotateServiceToken(c.Param("id"))
+	err := ensureServiceTokenVisible(c, c.Param("id"))
+	var (
+		record   *model.MCPServiceToken
+		rawToken string
+	)
+	if err == nil {
+		record, rawToken, err = internalmcp.RotateServiceToken(c.Param("id"))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -96,7 +133,10 @@ func RotateServiceToken(c *gin.Context) {
 }
 
 func RevokeServiceToken(c *gin.Context) {
-	err := internalmcp.RevokeServiceToken(c.Param("id"))
+	err := ensureServiceTokenVisible(c, c.Param("id"))
+	if err == nil {
+		err = internalmcp.RevokeServiceToken(c.Param("id"))
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -16,6 +16,7 @@ const (
 	AutoCertEnabled           = 1
 	AutoCertDisabled          = -1
 	AutoCertSelfSigned        = 3
+	AutoCertPrivateCA         = 4
 	CertChallengeMethodHTTP01 = "http01"
 	CertChallengeMethodDNS01  = "dns01"
 
@@ -24,6 +25,29 @@ const (
 	CertStatusPending = "pending"
 	CertStatusSuccess = "success"
 	CertStatusFailure = "failure"
+
+	// CertOCSPStatus values mirror the certificate status reported by the
+	// OCSP responder of the issuer.
+	CertOCSPStatusGood    = "good"
+	CertOCSPStatusRevoked = "revoked"
+	CertOCSPStatusUnknown = "unknown"
+
+	// CertOCSPStapling values tell whether the local nginx listeners serving
+	// the certificate staple an OCSP response.
+	CertOCSPStaplingOK      = "ok"
+	CertOCSPStaplingMissing = "missing"
+
+	// CertDeployHook types.
+	CertDeployHookScript = "script"
+	CertDeployHookCopy   = "copy"
+	CertDeployHookBundle = "bundle"
+	CertDeployHookDocker = "docker"
+
+	CertDeployHookStagePre  = "pre"
+	CertDeployHookStagePost = "post"
+
+	CertBundleFormatPEM    = "pem"
+	CertBundleFormatPKCS12 = "pkcs12"
 )
 
 type CertDomains []string
@@ -44,6 +68,31 @@ type SelfSignedCertConfig struct {
 	ValidityDays int      `json:"validity_days"`
 }
 
+// CertDeployHook distributes a certificate once it has been issued or
+// renewed. Script hooks may run before the issuance instead, with Stage set
+// to pre. Hooks hold commands and bundle passwords, so they are left out of
+// the certificate JSON and only served by the deploy hooks API.
+type CertDeployHook struct {
+	Type    string `json:"type" binding:"required,oneof=script copy bundle docker"`
+	Stage   string `json:"stage,omitempty" binding:"omitempty,oneof=pre post"`
+	Command string `json:"command,omitempty"`
+	// Timeout of a script in seconds
+	Timeout int `json:"timeout,omitempty" binding:"omitempty,min=1,max=3600"`
+	// Destination is the directory of copy and docker hooks and the file of
+	// bundle hooks.
+	Destination string `json:"destination,omitempty"`
+	// Owner is a user, optionally followed by :group, given by name or id
+	Owner string `json:"owner,omitempty"`
+	// Mode is the octal permission of the written files
+	Mode string `json:"mode,omitempty"`
+	// KeyMode is the octal permission of the key written by copy and docker
+	// hooks, 0600 unless set. Mode does not apply to it.
+	KeyMode   string `json:"key_mode,omitempty"`
+	Format    string `json:"format,omitempty" binding:"omitempty,oneof=pem pkcs12"`
+	Password  string `json:"password,omitempty"`
+	Container string `json:"container,omitempty"`
+}
+
 type Cert struct {
 	Model
 	Name                         string                `json:"name"`
@@ -68,6 +117,15 @@ type Cert struct {
 	EnableCommonName             bool                  `json:"enable_common_name"`
 	RevokeOld                    bool                  `json:"revoke_old"`
 	SelfSignedConfig             *SelfSignedCertConfig `json:"self_signed_config,omitempty" gorm:"serializer:json"`
+	PrivateCAConfig              *PrivateCACertConfig  `json:"private_ca_config,omitempty" gorm:"serializer:json"`
+	CTUnexpectedIssuers          []string              `json:"ct_unexpected_issuers,omitempty" gorm:"serializer:json"`
+	OCSPStatus                   string                `json:"ocsp_status"`
+	OCSPRevokedAt                *time.Time            `json:"ocsp_revoked_at,omitempty"`
+	OCSPRevocationReason         int                   `json:"ocsp_revocation_reason,omitempty"`
+	OCSPStapling                 string                `json:"ocsp_stapling"`
+	OCSPError                    string                `json:"ocsp_error,omitempty"`
+	OCSPCheckedAt                *time.Time            `json:"ocsp_checked_at,omitempty"`
+	DeployHooks                  []CertDeployHook      `json:"-" gorm:"serializer:json[aes]"`
 	LastAutoRenewAt              *time.Time            `json:"-"`
 	LastAutoRenewError           string                `json:"-"`
 	NextAutoRenewAt              *time.Time            `json:"-"`
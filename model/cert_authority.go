@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+)
+
+// PrivateCACertUsage selects the extended key usage of a certificate issued
+// by a private CA.
+type PrivateCACertUsage string
+
+const (
+	PrivateCACertUsageServer PrivateCACertUsage = "server"
+	PrivateCACertUsageClient PrivateCACertUsage = "client"
+)
+
+// PrivateCACertConfig stores the parameters a certificate was issued with by
+// a private CA, so the auto-renewal job can issue it again.
+type PrivateCACertConfig struct {
+	CertAuthorityID uint64             `json:"cert_authority_id"`
+	Usage           PrivateCACertUsage `json:"usage"`
+	IPAddresses     []string           `json:"ip_addresses"`
+	EmailAddresses  []string           `json:"email_addresses"`
+	ValidityDays    int                `json:"validity_days"`
+}
+
+// CertAuthority is a root or intermediate CA managed by Nginx UI. Its
+// certificate, the chain up to the root and its CRL are published under the
+// nginx configuration directory, so sites can verify client certificates
+// against it.
+type CertAuthority struct {
+	Model
+	Name string `json:"name" gorm:"not null"`
+	// ParentID is the CA that signed this one, it is zero for a root.
+	ParentID     uint64             `json:"parent_id" gorm:"index"`
+	CommonName   string             `json:"common_name"`
+	Organization string             `json:"organization"`
+	KeyType      certcrypto.KeyType `json:"key_type"`
+	ValidityDays int                `json:"validity_days"`
+	Certificate  string             `json:"certificate"`
+	PrivateKey   string             `json:"-" gorm:"serializer:json[aes]"`
+	SerialNumber string             `json:"serial_number" gorm:"index"`
+	NotBefore    time.Time          `json:"not_before"`
+	NotAfter     time.Time          `json:"not_after"`
+	// CRL is the latest revocation list signed by this CA, in PEM.
+	CRL             string     `json:"-"`
+	CRLNumber       int64      `json:"crl_number"`
+	CRLValidityDays int        `json:"crl_validity_days" gorm:"default:7"`
+	CRLUpdatedAt    *time.Time `json:"crl_updated_at"`
+	CRLNextUpdate   *time.Time `json:"crl_next_update"`
+}
+
+// CertIssuance is a certificate signed by a private CA. Every renewal adds
+// one, so revoking a Cert covers the versions it replaced as well.
+type CertIssuance struct {
+	Model
+	CertAuthorityID uint64    `json:"cert_authority_id" gorm:"index"`
+	CertID          uint64    `json:"cert_id" gorm:"index"`
+	SerialNumber    string    `json:"serial_number"`
+	NotAfter        time.Time `json:"not_after"`
+}
+
+// CertRevocation is a certificate revoked by a private CA, it is listed in the
+// CRL of the CA.
+type CertRevocation struct {
+	Model
+	CertAuthorityID uint64    `json:"cert_authority_id" gorm:"index"`
+	CertID          uint64    `json:"cert_id" gorm:"index"`
+	SerialNumber    string    `json:"serial_number"`
+	Reason          int       `json:"reason"`
+	RevokedAt       time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+// ChangeSetStatus represents where a change set is in the review workflow
+type ChangeSetStatus string
+
+const (
+	ChangeSetStatusPending  ChangeSetStatus = "pending"
+	ChangeSetStatusApplied  ChangeSetStatus = "applied"
+	ChangeSetStatusRejected ChangeSetStatus = "rejected"
+	ChangeSetStatusExpired  ChangeSetStatus = "expired"
+	ChangeSetStatusFailed   ChangeSetStatus = "failed"
+)
+
+// ChangeSetItemKind identifies which save path applies an item
+type ChangeSetItemKind string
+
+const (
+	ChangeSetItemSite   ChangeSetItemKind = "site"
+	ChangeSetItemStream ChangeSetItemKind = "stream"
+	ChangeSetItemConfig ChangeSetItemKind = "config"
+)
+
+// ChangeSetItemAction is what an item does to its file
+type ChangeSetItemAction string
+
+const (
+	// ChangeSetActionWrite replaces the content of the file, it is the
+	// action of an item unless another one is given.
+	ChangeSetActionWrite        ChangeSetItemAction = ""
+	ChangeSetActionRename       ChangeSetItemAction = "rename"
+	ChangeSetActionEnable       ChangeSetItemAction = "enable"
+	ChangeSetActionDisable      ChangeSetItemAction = "disable"
+	ChangeSetActionDelete       ChangeSetItemAction = "delete"
+	ChangeSetActionDuplicate    ChangeSetItemAction = "duplicate"
+	ChangeSetActionMaintenance  ChangeSetItemAction = "maintenance"
+	ChangeSetActionClientVerify ChangeSetItemAction = "client_verify"
+)
+
+// ChangeSetItem is a single file edit inside a change set
+type ChangeSetItem struct {
+	Kind   ChangeSetItemKind   `json:"kind"`
+	Action ChangeSetItemAction `json:"action,omitempty"`
+	// Name is the site or stream name, or the config path relative to the
+	// nginx configuration directory.
+	Name            string `json:"name"`
+	Path            string `json:"path"`
+	NamespaceID     uint64 `json:"namespace_id"`
+	Content         string `json:"content"`
+	OriginalContent string `json:"original_content"`
+	// NewFile is set when the file did not exist when the item was staged.
+	NewFile bool `json:"new_file"`
+	// Diff is the unified diff of a write, or a summary of any other action.
+	Diff string `json:"diff"`
+	// NewName is the name a rename or duplicate leads to.
+	NewName string `json:"new_name,omitempty"`
+	// CertAuthorityID and VerifyMode configure client certificate
+	// verification of a site.
+	CertAuthorityID uint64 `json:"cert_authority_id,omitempty"`
+	VerifyMode      string `json:"verify_mode,omitempty"`
+}
+
+// ChangeSet collects configuration edits that are tested in a sandbox and
+// only written to disk once a second user approves them.
+type ChangeSet struct {
+	Model
+	Title       string          `json:"title" gorm:"not null"`
+	Description string          `json:"description"`
+	Status      ChangeSetStatus `json:"status" gorm:"index;default:'pending'"`
+	Items       []ChangeSetItem `json:"items" gorm:"serializer:json"`
+	AuthorID    uint64          `json:"author_id" gorm:"index"`
+	Author      *User           `json:"author,omitempty"`
+	ReviewerID  uint64          `json:"reviewer_id,omitempty"`
+	Reviewer    *User           `json:"reviewer,omitempty"`
+	// ReviewComment is the reason given when approving or rejecting.
+	ReviewComment string     `json:"review_comment"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	// TestPassed and TestOutput hold the nginx -t result of the staged
+	// content, refreshed when the change set is approved.
+	TestPassed bool   `json:"test_passed"`
+	TestOutput string `json:"test_output"`
+	// ApplyError records why applying an approved change set failed.
+	ApplyError string `json:"apply_error,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// CTLogEntry is a certificate for a monitored domain that was found in the
+// Certificate Transparency logs. Known entries match a certificate Nginx UI
+// deployed, all others were issued somewhere else.
+type CTLogEntry struct {
+	Model
+	Domain string `json:"domain" gorm:"index"`
+	// SourceID is the id of the entry at the CT log source.
+	SourceID     int64     `json:"source_id"`
+	IssuerName   string    `json:"issuer_name" gorm:"uniqueIndex:idx_ct_log_entry_certificate"`
+	CommonName   string    `json:"common_name"`
+	Names        []string  `json:"names" gorm:"serializer:json"`
+	SerialNumber string    `json:"serial_number" gorm:"uniqueIndex:idx_ct_log_entry_certificate"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after" gorm:"index"`
+	Known        bool      `json:"known" gorm:"index"`
+	// CertID is the managed certificate with the same serial number.
+	CertID uint64 `json:"cert_id"`
+	// UnexpectedIssuer is set when the issuer never issued a certificate
+	// deployed by Nginx UI.
+	UnexpectedIssuer bool `json:"unexpected_issuer"`
+	Acknowledged     bool `json:"acknowledged"`
+}
+
+// CertSerial records the serial number of every certificate Nginx UI has
+// deployed, so certificates replaced by a renewal are still recognized in the
+// CT logs.
+type CertSerial struct {
+	Model
+	CertID       uint64 `json:"cert_id" gorm:"index"`
+	SerialNumber string `json:"serial_number" gorm:"uniqueIndex"`
+	IssuerName   string `json:"issuer_name"`
+}
+
+// CTMonitorTarget records a watched domain whose CT logs have been searched.
+// Only the certificates found by the first search are taken as the baseline
+// without a notification.
+type CTMonitorTarget struct {
+	Model
+	Domain    string    `json:"domain" gorm:"uniqueIndex"`
+	CheckedAt time.Time `json:"checked_at"`
+}
@@ -7,6 +7,25 @@ type DDNSRecordTarget struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// Source overrides the IP source of the domain for this record.
+	Source *DDNSIPSource `json:"source,omitempty"`
+}
+
+// DDNSIPSource describes where DDNS reads the address written to a record.
+// An empty Type means the public IP echo services.
+type DDNSIPSource struct {
+	Type string `json:"type"`
+	// Interface, IPv6Scope and Prefix select an address of a local interface.
+	Interface string `json:"interface,omitempty"`
+	IPv6Scope string `json:"ipv6_scope,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	// Command is run through the shell and its output searched for an address.
+	Command string `json:"command,omitempty"`
+	// URL is fetched and its body searched for an address.
+	URL string `json:"url,omitempty"`
+	// Regex narrows the command output or URL body down to the address, taken
+	// from the first capture group when there is one.
+	Regex string `json:"regex,omitempty"`
 }
 
 // DDNSConfig stores per-domain DDNS configuration and runtime status.
@@ -22,4 +41,12 @@ type DDNSConfig struct {
 	LastError                 string             `json:"last_error,omitempty"`
 	IPv4FailedSince           *time.Time         `json:"ipv4_failed_since,omitempty"`
 	IPv6FailedSince           *time.Time         `json:"ipv6_failed_since,omitempty"`
+	Source                    *DDNSIPSource      `json:"source,omitempty"`
+	WebhookToken              string             `json:"webhook_token,omitempty"`
+	WebhookIPv4               string             `json:"webhook_ipv4,omitempty"`
+	WebhookIPv6               string             `json:"webhook_ipv6,omitempty"`
+	WebhookAt                 *time.Time         `json:"webhook_at,omitempty"`
+	// WaitPropagation holds a run until every authoritative nameserver serves
+	// the updated addresses.
+	WaitPropagation bool `json:"wait_propagation,omitempty"`
 }
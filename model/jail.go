@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// JailLogType selects how the lines of a jail's log are read.
+type JailLogType string
+
+const (
+	JailLogTypeAccess JailLogType = "access"
+	JailLogTypeError  JailLogType = "error"
+)
+
+// Jail watches a log and bans client IPs whose lines match its filter more
+// than MaxRetry times within FindTimeSeconds. Bans of a jail with a SiteName
+// only apply to that site, all others apply to every server.
+type Jail struct {
+	Model
+	Name    string      `json:"name" gorm:"not null"`
+	LogPath string      `json:"log_path" gorm:"index;not null"`
+	LogType JailLogType `json:"log_type" gorm:"default:'access'"`
+
+	// Filter, every condition that is set must match. Status codes, paths and
+	// user agents are only available for access logs.
+	StatusCodes      []int  `json:"status_codes" gorm:"serializer:json"`
+	PathPattern      string `json:"path_pattern"`
+	UserAgentPattern string `json:"user_agent_pattern"`
+	LinePattern      string `json:"line_pattern"`
+
+	MaxRetry        int `json:"max_retry" gorm:"default:5"`
+	FindTimeSeconds int `json:"find_time_seconds" gorm:"default:600"`
+	// BanTimeSeconds is how long a ban lasts. A negative value bans permanently.
+	BanTimeSeconds int      `json:"ban_time_seconds" gorm:"default:3600"`
+	SiteName       string   `json:"site_name"`
+	IgnoreIPs      []string `json:"ignore_ips" gorm:"serializer:json"`
+	Enabled        bool     `json:"enabled" gorm:"index;default:true"`
+}
+
+// JailBan is a client IP denied by a jail, along with the log lines that
+// triggered it.
+type JailBan struct {
+	Model
+	JailID    uint64     `json:"jail_id" gorm:"index"`
+	Jail      *Jail      `json:"jail,omitempty"`
+	IP        string     `json:"ip" gorm:"index;not null"`
+	SiteName  string     `json:"site_name" gorm:"index"`
+	Hits      int        `json:"hits"`
+	Evidence  []string   `json:"evidence" gorm:"serializer:json"`
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index"`
+}
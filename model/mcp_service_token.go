@@ -19,4 +19,7 @@ type MCPServiceToken struct {
 	RevokedAt  *time.Time `json:"revoked_at,omitempty" gorm:"index"`
 	CreatorID  uint64     `json:"creator_id" gorm:"index;not null"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// NamespaceIDs is copied from the creator so a token never reaches beyond
+	// the namespaces its creator could access.
+	NamespaceIDs []uint64 `json:"namespace_ids" gorm:"serializer:json"`
 }
@@ -36,6 +36,7 @@ func GenerateAllModel() []any {
 	return []any{
 		ConfigBackup{},
 		User{},
+		Role{},
 		AuthToken{},
 		Cert{},
 		LLMSession{},
@@ -59,6 +60,23 @@ func GenerateAllModel() []any {
 		SiteHealthAlertState{},
 		NginxLogIndex{},
 		UpstreamConfig{},
+		ChangeSet{},
+		TrafficAlertRule{},
+		TrafficAlertState{},
+		Jail{},
+		JailBan{},
+		SiteDeployment{},
+		UpstreamHealthCheck{},
+		UpstreamServerHealth{},
+		UpstreamHealthEvent{},
+		CertAuthority{},
+		CertIssuance{},
+		CertRevocation{},
+		CTLogEntry{},
+		CertSerial{},
+		CTMonitorTarget{},
+		NginxLogSavedSearch{},
+		NginxLogRollup{},
 	}
 }
 
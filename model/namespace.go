@@ -34,6 +34,16 @@ const (
 	SyncStrategyAuto = "auto"
 )
 
+// DNSProvisioning defines how missing DNS records of a site's server names are handled
+const (
+	// DNSProvisioningManual proposes the records and waits for confirmation
+	DNSProvisioningManual = "manual"
+	// DNSProvisioningAuto creates and renames the records without asking
+	DNSProvisioningAuto = "auto"
+	// DNSProvisioningOff never proposes records
+	DNSProvisioningOff = "off"
+)
+
 // DefaultSyncIntervalMinutes is used when a namespace enables automatic sync
 // without providing an interval.
 const DefaultSyncIntervalMinutes = 30
@@ -49,6 +59,13 @@ type Namespace struct {
 	DeployMode          string   `json:"deploy_mode" gorm:"default:'local'"`
 	SyncStrategy        string   `json:"sync_strategy" gorm:"default:'manual'"`
 	SyncIntervalMinutes int      `json:"sync_interval_minutes" gorm:"default:30"`
+	DNSProvisioning     string   `json:"dns_provisioning" gorm:"default:'manual'"`
+	// DNSTargetIPv4, DNSTargetIPv6 and DNSTargetCNAME are what provisioned
+	// records point at. A CNAME target takes precedence below the zone apex.
+	DNSTargetIPv4     string `json:"dns_target_ipv4"`
+	DNSTargetIPv6     string `json:"dns_target_ipv6"`
+	DNSTargetCNAME    string `json:"dns_target_cname"`
+	DNSDeleteWithSite bool   `json:"dns_delete_with_site"`
 }
 
 // IsRemoteDeploy reports whether the namespace content is only deployed to the
@@ -71,3 +88,18 @@ func (n *Namespace) EffectiveSyncInterval() int {
 	}
 	return n.SyncIntervalMinutes
 }
+
+// EffectiveDNSProvisioning returns the DNS provisioning policy. Sites outside
+// a namespace get their records proposed.
+func (n *Namespace) EffectiveDNSProvisioning() string {
+	if n == nil || n.DNSProvisioning == "" {
+		return DNSProvisioningManual
+	}
+	return n.DNSProvisioning
+}
+
+// DeletesDNSWithSite reports whether the DNS records provisioned for a site
+// are removed when the site is deleted.
+func (n *Namespace) DeletesDNSWithSite() bool {
+	return n != nil && n.DNSDeleteWithSite
+}
@@ -21,6 +21,10 @@ type NginxLogIndex struct {
 	Enabled        bool       `gorm:"default:true" json:"enabled"`               // Whether indexing is enabled for this file
 	HasTimeRange   bool       `gorm:"-" json:"has_timerange"`                    // Whether a time range is available (not persisted)
 
+	// Retention fields, kept on every record of a log group
+	RetentionDays int        `gorm:"default:0" json:"retention_days"` // Days raw documents are kept, 0 keeps them forever
+	PurgedBefore  *time.Time `json:"purged_before,omitempty"`         // Raw documents before this time were purged, rollups cover them
+
 	// Extended status fields
 	IndexStatus   string     `gorm:"default:'not_indexed';size:50" json:"index_status"` // Current index status
 	ErrorMessage  string     `gorm:"type:text" json:"error_message,omitempty"`          // Last error message
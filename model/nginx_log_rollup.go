@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Rollup granularities
+const (
+	RollupGranularityHour = "hour"
+	RollupGranularityDay  = "day"
+)
+
+// NginxLogRollup is the downsampled traffic of a log group over one UTC hour
+// or one server-local day. Rollups outlive the raw index documents, so the
+// dashboard can still cover ranges purged by the retention policy.
+type NginxLogRollup struct {
+	ID          uint64 `gorm:"primary_key" json:"id"`
+	MainLogPath string `gorm:"uniqueIndex:idx_nginx_log_rollup_bucket;size:500;not null" json:"main_log_path"`
+	Granularity string `gorm:"uniqueIndex:idx_nginx_log_rollup_bucket;size:10;not null" json:"granularity"`
+	BucketStart int64  `gorm:"uniqueIndex:idx_nginx_log_rollup_bucket;not null" json:"bucket_start"` // Unix seconds
+
+	Requests       int64 `json:"requests"`
+	Bytes          int64 `json:"bytes"`
+	Status2xx      int64 `gorm:"column:status_2xx" json:"status_2xx"`
+	Status3xx      int64 `gorm:"column:status_3xx" json:"status_3xx"`
+	Status4xx      int64 `gorm:"column:status_4xx" json:"status_4xx"`
+	Status5xx      int64 `gorm:"column:status_5xx" json:"status_5xx"`
+	StatusOther    int64 `json:"status_other"`
+	UniqueVisitors int64 `json:"unique_visitors"`
+
+	// TopPaths and Countries keep the most requested values of the bucket only
+	TopPaths  []RollupCount `json:"top_paths" gorm:"serializer:json"`
+	Countries []RollupCount `json:"countries" gorm:"serializer:json"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RollupCount is the number of requests of one path or country in a rollup
+type RollupCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
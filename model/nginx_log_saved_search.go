@@ -0,0 +1,22 @@
+package model
+
+// NginxLogSavedSearch is a named access log search owned by a user. Sharing
+// it issues a ShareToken, which lets any user allowed to read logs open the
+// search by link until it is unshared.
+type NginxLogSavedSearch struct {
+	Model
+	UserID      uint64 `json:"user_id" gorm:"index;not null"`
+	Name        string `json:"name" gorm:"not null"`
+	Description string `json:"description"`
+	LogPath     string `json:"log_path"`
+	// Query is matched as free text, Expression is a query language
+	// expression such as `status:>=500 AND NOT ip:10.0.0.0/8`
+	Query      string `json:"query"`
+	Expression string `json:"expression"`
+	// TimeRange is the look-back window in seconds the search covers when it
+	// runs, 0 searches all indexed logs
+	TimeRange  int64  `json:"time_range"`
+	SortBy     string `json:"sort_by"`
+	SortOrder  string `json:"sort_order"`
+	ShareToken string `json:"share_token,omitempty" gorm:"index"`
+}
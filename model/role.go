@@ -0,0 +1,18 @@
+package model
+
+// Built-in role names. Their permissions are defined in internal/rbac and
+// cannot be edited; the roles table only stores custom roles.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+// Role is a custom set of permissions that can be assigned to users. A
+// permission has the form "<resource>:<action>", e.g. "sites:write", or "*".
+type Role struct {
+	Model
+	Name        string   `json:"name" gorm:"uniqueIndex" cosy:"add:required,max=32;update:omitempty,max=32;list:fussy"`
+	Description string   `json:"description" cosy:"all:omitempty"`
+	Permissions []string `json:"permissions" gorm:"serializer:json" cosy:"add:required;update:omitempty"`
+}
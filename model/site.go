@@ -5,6 +5,12 @@ type SiteDNSRecord struct {
 	Name   string `json:"name"`
 	Type   string `json:"type"`
 	Exists bool   `json:"exists"`
+	// DomainID is the DNS domain of the record when it differs from the
+	// domain linked to the site, which happens once server names span zones.
+	DomainID uint64 `json:"domain_id,omitempty"`
+	// Managed marks records provisioned for the site. Only those follow a
+	// rename or are removed together with the site.
+	Managed bool `json:"managed,omitempty"`
 }
 
 type Site struct {
@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+type SiteDeploymentStrategy string
+
+const (
+	// SiteDeploymentCanary shifts traffic to the new target in steps.
+	SiteDeploymentCanary SiteDeploymentStrategy = "canary"
+	// SiteDeploymentBlueGreen switches all traffic at once and keeps the old
+	// target around until the new one passed a bake period.
+	SiteDeploymentBlueGreen SiteDeploymentStrategy = "blue_green"
+)
+
+type SiteDeploymentStatus string
+
+const (
+	SiteDeploymentRunning    SiteDeploymentStatus = "running"
+	SiteDeploymentSucceeded  SiteDeploymentStatus = "succeeded"
+	SiteDeploymentRolledBack SiteDeploymentStatus = "rolled_back"
+	// SiteDeploymentFailed means nginx rejected the final or the restored
+	// configuration, so the site needs manual attention.
+	SiteDeploymentFailed SiteDeploymentStatus = "failed"
+)
+
+// SiteDeploymentCheck is the outcome of the checks run at the end of a step.
+type SiteDeploymentCheck struct {
+	Step       int       `json:"step"`
+	Weight     int       `json:"weight"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Requests   uint64    `json:"requests"`
+	ErrorRatio *float64  `json:"error_ratio,omitempty"`
+	Latency    *float64  `json:"latency,omitempty"`
+	Healthy    *bool     `json:"healthy,omitempty"`
+	Passed     bool      `json:"passed"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// SiteDeployment moves the proxy_pass of a site from OldTarget to NewTarget,
+// weighting the traffic between both with split_clients.
+type SiteDeployment struct {
+	Model
+	SiteName  string                 `json:"site_name" gorm:"index;not null"`
+	Strategy  SiteDeploymentStrategy `json:"strategy" gorm:"default:'canary'"`
+	OldTarget string                 `json:"old_target"`
+	NewTarget string                 `json:"new_target"`
+	// Steps are the percentages of traffic sent to the new target, the last
+	// step is always 100.
+	Steps               []int `json:"steps" gorm:"serializer:json"`
+	StepIntervalSeconds int   `json:"step_interval_seconds" gorm:"default:300"`
+
+	// Thresholds checked between steps, zero disables a check.
+	LogPath       string  `json:"log_path"`
+	MaxErrorRatio float64 `json:"max_error_ratio"`
+	MaxLatency    float64 `json:"max_latency"`
+	MinRequests   int     `json:"min_requests"`
+	HealthCheck   bool    `json:"health_check"`
+
+	Status      SiteDeploymentStatus  `json:"status" gorm:"index"`
+	CurrentStep int                   `json:"current_step"`
+	Weight      int                   `json:"weight"`
+	Reason      string                `json:"reason"`
+	Checks      []SiteDeploymentCheck `json:"checks" gorm:"serializer:json"`
+	// OriginalConfig is the site configuration before the deployment started,
+	// restored on rollback.
+	OriginalConfig string     `json:"-"`
+	StepStartedAt  *time.Time `json:"step_started_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+}
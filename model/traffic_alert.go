@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// TrafficAlertMetric selects what a traffic alert rule measures over its window.
+type TrafficAlertMetric string
+
+const (
+	// TrafficAlertMetricErrorRatio is the share of 5xx responses, in percent.
+	TrafficAlertMetricErrorRatio TrafficAlertMetric = "error_ratio"
+	// TrafficAlertMetricLatency is a request_time percentile, in seconds.
+	TrafficAlertMetricLatency TrafficAlertMetric = "latency"
+	// TrafficAlertMetricRateSpike is the request rate of the window divided by
+	// the rate of the baseline period before it.
+	TrafficAlertMetricRateSpike TrafficAlertMetric = "rate_spike"
+	// TrafficAlertMetricIPRate is the request rate of the busiest client IP, in
+	// requests per minute.
+	TrafficAlertMetricIPRate TrafficAlertMetric = "ip_rate"
+)
+
+// TrafficAlertRule evaluates a metric of an indexed access log on a schedule
+// and notifies when it stays above Threshold. The alert only recovers once the
+// value drops to RecoveryThreshold, so a value hovering around the threshold
+// does not flap.
+type TrafficAlertRule struct {
+	Model
+	Name              string             `json:"name" gorm:"not null"`
+	LogPath           string             `json:"log_path" gorm:"index;not null"`
+	Metric            TrafficAlertMetric `json:"metric" gorm:"not null"`
+	Threshold         float64            `json:"threshold"`
+	RecoveryThreshold float64            `json:"recovery_threshold"`
+	Percentile        float64            `json:"percentile"`
+	WindowSeconds     int                `json:"window_seconds" gorm:"default:300"`
+	BaselineSeconds   int                `json:"baseline_seconds" gorm:"default:3600"`
+	MinRequests       int                `json:"min_requests"`
+	IntervalSeconds   int                `json:"interval_seconds" gorm:"default:60"`
+	FailureThreshold  int                `json:"failure_threshold" gorm:"default:1"`
+	CooldownSeconds   int                `json:"cooldown_seconds"`
+	RecoveryEnabled   bool               `json:"recovery_enabled"`
+	ExternalNotifyIDs []uint64           `json:"external_notify_ids" gorm:"serializer:json"`
+	Enabled           bool               `json:"enabled" gorm:"index;default:true"`
+}
+
+// TrafficAlertState stores the transition state of a rule so repeated
+// evaluations and process restarts do not generate duplicate notifications.
+type TrafficAlertState struct {
+	Model
+	RuleID              uint64     `gorm:"uniqueIndex" json:"rule_id"`
+	LastStatus          string     `json:"last_status"`
+	LastValue           float64    `json:"last_value"`
+	LastRequests        uint64     `json:"last_requests"`
+	LastEvaluatedAt     *time.Time `json:"last_evaluated_at"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailureNotified     bool       `json:"failure_notified"`
+	LastNotifiedAt      *time.Time `json:"last_notified_at"`
+}
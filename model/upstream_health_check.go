@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// UpstreamHealthCheck probes every server of an upstream over HTTP. A server
+// failing Fall probes in a row is marked down in the configuration and nginx
+// is reloaded, it is brought back after Rise successful probes. The last
+// servers in rotation are never marked down.
+type UpstreamHealthCheck struct {
+	Model
+	Upstream string `json:"upstream" gorm:"uniqueIndex;not null"`
+	Scheme   string `json:"scheme" gorm:"default:'http'"`
+	Path     string `json:"path" gorm:"default:'/'"`
+	// Host overrides the Host header, by default it is the server address.
+	Host string `json:"host"`
+	// ExpectedStatus lists the accepted status codes, any 2xx or 3xx status is
+	// accepted when it is empty.
+	ExpectedStatus    []int    `json:"expected_status" gorm:"serializer:json"`
+	BodyPattern       string   `json:"body_pattern"`
+	VerifySSL         bool     `json:"verify_ssl"`
+	IntervalSeconds   int      `json:"interval_seconds" gorm:"default:10"`
+	TimeoutSeconds    int      `json:"timeout_seconds" gorm:"default:5"`
+	Rise              int      `json:"rise" gorm:"default:2"`
+	Fall              int      `json:"fall" gorm:"default:3"`
+	ExternalNotifyIDs []uint64 `json:"external_notify_ids" gorm:"serializer:json"`
+	Enabled           bool     `json:"enabled" gorm:"index;default:true"`
+}
+
+// UpstreamServerHealth is the health of a server as seen by a check.
+type UpstreamServerHealth struct {
+	Model
+	CheckID   uint64 `json:"check_id" gorm:"uniqueIndex:idx_upstream_server_health"`
+	Address   string `json:"address" gorm:"uniqueIndex:idx_upstream_server_health"`
+	Healthy   bool   `json:"healthy"`
+	Successes int    `json:"successes"`
+	Failures  int    `json:"failures"`
+	// MarkedDown is set while the check keeps the server down. Servers taken
+	// down by hand are not probed and never brought back by the check.
+	MarkedDown     bool       `json:"marked_down"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	LastCheckedAt  *time.Time `json:"last_checked_at"`
+}
+
+// UpstreamHealthEvent records a server turning unhealthy or recovering.
+type UpstreamHealthEvent struct {
+	Model
+	CheckID  uint64 `json:"check_id" gorm:"index"`
+	Upstream string `json:"upstream" gorm:"index"`
+	Address  string `json:"address"`
+	Healthy  bool   `json:"healthy"`
+	// Applied tells whether the server was marked down or up in the
+	// configuration, Reason holds the probe or reload error otherwise.
+	Applied bool   `json:"applied"`
+	Reason  string `json:"reason"`
+}
@@ -34,6 +34,12 @@ type User struct {
 	RecoveryCodes RecoveryCodes `json:"-" gorm:"serializer:json[aes]"`
 	EnabledTwoFA  bool          `json:"enabled_2fa" gorm:"-"`
 	Language      string        `json:"language" gorm:"default:en"`
+	// Role is either a built-in role name or the name of a custom Role.
+	// Accounts created before roles existed default to admin.
+	Role string `json:"role" gorm:"default:admin" cosy:"all:omitempty"`
+	// NamespaceIDs restricts the sites and streams the user may access.
+	// An empty list means the user is not scoped to any namespace.
+	NamespaceIDs []uint64 `json:"namespace_ids" gorm:"serializer:json" cosy:"all:omitempty"`
 }
 
 type AuthToken struct {
@@ -52,6 +52,15 @@ func newCert(db *gorm.DB, opts ...gen.DOOption) cert {
 	_cert.EnableCommonName = field.NewBool(tableName, "enable_common_name")
 	_cert.RevokeOld = field.NewBool(tableName, "revoke_old")
 	_cert.SelfSignedConfig = field.NewField(tableName, "self_signed_config")
+	_cert.PrivateCAConfig = field.NewField(tableName, "private_ca_config")
+	_cert.CTUnexpectedIssuers = field.NewField(tableName, "ct_unexpected_issuers")
+	_cert.OCSPStatus = field.NewString(tableName, "ocsp_status")
+	_cert.OCSPRevokedAt = field.NewTime(tableName, "ocsp_revoked_at")
+	_cert.OCSPRevocationReason = field.NewInt(tableName, "ocsp_revocation_reason")
+	_cert.OCSPStapling = field.NewString(tableName, "ocsp_stapling")
+	_cert.OCSPError = field.NewString(tableName, "ocsp_error")
+	_cert.OCSPCheckedAt = field.NewTime(tableName, "ocsp_checked_at")
+	_cert.DeployHooks = field.NewField(tableName, "deploy_hooks")
 	_cert.LastAutoRenewAt = field.NewTime(tableName, "last_auto_renew_at")
 	_cert.LastAutoRenewError = field.NewString(tableName, "last_auto_renew_error")
 	_cert.NextAutoRenewAt = field.NewTime(tableName, "next_auto_renew_at")
@@ -108,6 +117,15 @@ type cert struct {
 	EnableCommonName             field.Bool
 	RevokeOld                    field.Bool
 	SelfSignedConfig             field.Field
+	PrivateCAConfig              field.Field
+	CTUnexpectedIssuers          field.Field
+	OCSPStatus                   field.String
+	OCSPRevokedAt                field.Time
+	OCSPRevocationReason         field.Int
+	OCSPStapling                 field.String
+	OCSPError                    field.String
+	OCSPCheckedAt                field.Time
+	DeployHooks                  field.Field
 	LastAutoRenewAt              field.Time
 	LastAutoRenewError           field.String
 	NextAutoRenewAt              field.Time
@@ -162,6 +180,15 @@ func (c *cert) updateTableName(table string) *cert {
 	c.EnableCommonName = field.NewBool(table, "enable_common_name")
 	c.RevokeOld = field.NewBool(table, "revoke_old")
 	c.SelfSignedConfig = field.NewField(table, "self_signed_config")
+	c.PrivateCAConfig = field.NewField(table, "private_ca_config")
+	c.CTUnexpectedIssuers = field.NewField(table, "ct_unexpected_issuers")
+	c.OCSPStatus = field.NewString(table, "ocsp_status")
+	c.OCSPRevokedAt = field.NewTime(table, "ocsp_revoked_at")
+	c.OCSPRevocationReason = field.NewInt(table, "ocsp_revocation_reason")
+	c.OCSPStapling = field.NewString(table, "ocsp_stapling")
+	c.OCSPError = field.NewString(table, "ocsp_error")
+	c.OCSPCheckedAt = field.NewTime(table, "ocsp_checked_at")
+	c.DeployHooks = field.NewField(table, "deploy_hooks")
 	c.LastAutoRenewAt = field.NewTime(table, "last_auto_renew_at")
 	c.LastAutoRenewError = field.NewString(table, "last_auto_renew_error")
 	c.NextAutoRenewAt = field.NewTime(table, "next_auto_renew_at")
@@ -189,7 +216,7 @@ func (c *cert) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (c *cert) fillFieldMap() {
-	c.fieldMap = make(map[string]field.Expr, 37)
+	c.fieldMap = make(map[string]field.Expr, 46)
 	c.fieldMap["id"] = c.ID
 	c.fieldMap["created_at"] = c.CreatedAt
 	c.fieldMap["updated_at"] = c.UpdatedAt
@@ -214,6 +241,15 @@ func (c *cert) fillFieldMap() {
 	c.fieldMap["enable_common_name"] = c.EnableCommonName
 	c.fieldMap["revoke_old"] = c.RevokeOld
 	c.fieldMap["self_signed_config"] = c.SelfSignedConfig
+	c.fieldMap["private_ca_config"] = c.PrivateCAConfig
+	c.fieldMap["ct_unexpected_issuers"] = c.CTUnexpectedIssuers
+	c.fieldMap["ocsp_status"] = c.OCSPStatus
+	c.fieldMap["ocsp_revoked_at"] = c.OCSPRevokedAt
+	c.fieldMap["ocsp_revocation_reason"] = c.OCSPRevocationReason
+	c.fieldMap["ocsp_stapling"] = c.OCSPStapling
+	c.fieldMap["ocsp_error"] = c.OCSPError
+	c.fieldMap["ocsp_checked_at"] = c.OCSPCheckedAt
+	c.fieldMap["deploy_hooks"] = c.DeployHooks
 	c.fieldMap["last_auto_renew_at"] = c.LastAutoRenewAt
 	c.fieldMap["last_auto_renew_error"] = c.LastAutoRenewError
 	c.fieldMap["next_auto_renew_at"] = c.NextAutoRenewAt
@@ -21,26 +21,44 @@ var (
 	AuthToken                *authToken
 	AutoBackup               *autoBackup
 	BanIP                    *banIP
+	CTLogEntry               *cTLogEntry
+	CTMonitorTarget          *cTMonitorTarget
 	Cert                     *cert
+	CertAuthority            *certAuthority
+	CertIssuance             *certIssuance
+	CertRevocation           *certRevocation
+	CertSerial               *certSerial
+	ChangeSet                *changeSet
 	Config                   *config
 	ConfigBackup             *configBackup
 	DnsCredential            *dnsCredential
 	DnsDomain                *dnsDomain
 	ExternalNotify           *externalNotify
+	Jail                     *jail
+	JailBan                  *jailBan
 	LLMSession               *lLMSession
 	MCPServiceToken          *mCPServiceToken
 	Namespace                *namespace
 	NginxLogIndex            *nginxLogIndex
+	NginxLogRollup           *nginxLogRollup
+	NginxLogSavedSearch      *nginxLogSavedSearch
 	Node                     *node
 	NodeControllerCredential *nodeControllerCredential
 	NodeCredential           *nodeCredential
 	Notification             *notification
 	Passkey                  *passkey
+	Role                     *role
 	Site                     *site
 	SiteConfig               *siteConfig
+	SiteDeployment           *siteDeployment
 	SiteHealthAlertState     *siteHealthAlertState
 	Stream                   *stream
+	TrafficAlertRule         *trafficAlertRule
+	TrafficAlertState        *trafficAlertState
 	UpstreamConfig           *upstreamConfig
+	UpstreamHealthCheck      *upstreamHealthCheck
+	UpstreamHealthEvent      *upstreamHealthEvent
+	UpstreamServerHealth     *upstreamServerHealth
 	User                     *user
 )
 
@@ -50,26 +68,44 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	AuthToken = &Q.AuthToken
 	AutoBackup = &Q.AutoBackup
 	BanIP = &Q.BanIP
+	CTLogEntry = &Q.CTLogEntry
+	CTMonitorTarget = &Q.CTMonitorTarget
 	Cert = &Q.Cert
+	CertAuthority = &Q.CertAuthority
+	CertIssuance = &Q.CertIssuance
+	CertRevocation = &Q.CertRevocation
+	CertSerial = &Q.CertSerial
+	ChangeSet = &Q.ChangeSet
 	Config = &Q.Config
 	ConfigBackup = &Q.ConfigBackup
 	DnsCredential = &Q.DnsCredential
 	DnsDomain = &Q.DnsDomain
 	ExternalNotify = &Q.ExternalNotify
+	Jail = &Q.Jail
+	JailBan = &Q.JailBan
 	LLMSession = &Q.LLMSession
 	MCPServiceToken = &Q.MCPServiceToken
 	Namespace = &Q.Namespace
 	NginxLogIndex = &Q.NginxLogIndex
+	NginxLogRollup = &Q.NginxLogRollup
+	NginxLogSavedSearch = &Q.NginxLogSavedSearch
 	Node = &Q.Node
 	NodeControllerCredential = &Q.NodeControllerCredential
 	NodeCredential = &Q.NodeCredential
 	Notification = &Q.Notification
 	Passkey = &Q.Passkey
+	Role = &Q.Role
 	Site = &Q.Site
 	SiteConfig = &Q.SiteConfig
+	SiteDeployment = &Q.SiteDeployment
 	SiteHealthAlertState = &Q.SiteHealthAlertState
 	Stream = &Q.Stream
+	TrafficAlertRule = &Q.TrafficAlertRule
+	TrafficAlertState = &Q.TrafficAlertState
 	UpstreamConfig = &Q.UpstreamConfig
+	UpstreamHealthCheck = &Q.UpstreamHealthCheck
+	UpstreamHealthEvent = &Q.UpstreamHealthEvent
+	UpstreamServerHealth = &Q.UpstreamServerHealth
 	User = &Q.User
 }
 
@@ -80,26 +116,44 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 		AuthToken:                newAuthToken(db, opts...),
 		AutoBackup:               newAutoBackup(db, opts...),
 		BanIP:                    newBanIP(db, opts...),
+		CTLogEntry:               newCTLogEntry(db, opts...),
+		CTMonitorTarget:          newCTMonitorTarget(db, opts...),
 		Cert:                     newCert(db, opts...),
+		CertAuthority:            newCertAuthority(db, opts...),
+		CertIssuance:             newCertIssuance(db, opts...),
+		CertRevocation:           newCertRevocation(db, opts...),
+		CertSerial:               newCertSerial(db, opts...),
+		ChangeSet:                newChangeSet(db, opts...),
 		Config:                   newConfig(db, opts...),
 		ConfigBackup:             newConfigBackup(db, opts...),
 		DnsCredential:            newDnsCredential(db, opts...),
 		DnsDomain:                newDnsDomain(db, opts...),
 		ExternalNotify:           newExternalNotify(db, opts...),
+		Jail:                     newJail(db, opts...),
+		JailBan:                  newJailBan(db, opts...),
 		LLMSession:               newLLMSession(db, opts...),
 		MCPServiceToken:          newMCPServiceToken(db, opts...),
 		Namespace:                newNamespace(db, opts...),
 		NginxLogIndex:            newNginxLogIndex(db, opts...),
+		NginxLogRollup:           newNginxLogRollup(db, opts...),
+		NginxLogSavedSearch:      newNginxLogSavedSearch(db, opts...),
 		Node:                     newNode(db, opts...),
 		NodeControllerCredential: newNodeControllerCredential(db, opts...),
 		NodeCredential:           newNodeCredential(db, opts...),
 		Notification:             newNotification(db, opts...),
 		Passkey:                  newPasskey(db, opts...),
+		Role:                     newRole(db, opts...),
 		Site:                     newSite(db, opts...),
 		SiteConfig:               newSiteConfig(db, opts...),
+		SiteDeployment:           newSiteDeployment(db, opts...),
 		SiteHealthAlertState:     newSiteHealthAlertState(db, opts...),
 		Stream:                   newStream(db, opts...),
+		TrafficAlertRule:         newTrafficAlertRule(db, opts...),
+		TrafficAlertState:        newTrafficAlertState(db, opts...),
 		UpstreamConfig:           newUpstreamConfig(db, opts...),
+		UpstreamHealthCheck:      newUpstreamHealthCheck(db, opts...),
+		UpstreamHealthEvent:      newUpstreamHealthEvent(db, opts...),
+		UpstreamServerHealth:     newUpstreamServerHealth(db, opts...),
 		User:                     newUser(db, opts...),
 	}
 }
@@ -111,26 +165,44 @@ type Query struct {
 	AuthToken                authToken
 	AutoBackup               autoBackup
 	BanIP                    banIP
+	CTLogEntry               cTLogEntry
+	CTMonitorTarget          cTMonitorTarget
 	Cert                     cert
+	CertAuthority            certAuthority
+	CertIssuance             certIssuance
+	CertRevocation           certRevocation
+	CertSerial               certSerial
+	ChangeSet                changeSet
 	Config                   config
 	ConfigBackup             configBackup
 	DnsCredential            dnsCredential
 	DnsDomain                dnsDomain
 	ExternalNotify           externalNotify
+	Jail                     jail
+	JailBan                  jailBan
 	LLMSession               lLMSession
 	MCPServiceToken          mCPServiceToken
 	Namespace                namespace
 	NginxLogIndex            nginxLogIndex
+	NginxLogRollup           nginxLogRollup
+	NginxLogSavedSearch      nginxLogSavedSearch
 	Node                     node
 	NodeControllerCredential nodeControllerCredential
 	NodeCredential           nodeCredential
 	Notification             notification
 	Passkey                  passkey
+	Role                     role
 	Site                     site
 	SiteConfig               siteConfig
+	SiteDeployment           siteDeployment
 	SiteHealthAlertState     siteHealthAlertState
 	Stream                   stream
+	TrafficAlertRule         trafficAlertRule
+	TrafficAlertState        trafficAlertState
 	UpstreamConfig           upstreamConfig
+	UpstreamHealthCheck      upstreamHealthCheck
+	UpstreamHealthEvent      upstreamHealthEvent
+	UpstreamServerHealth     upstreamServerHealth
 	User                     user
 }
 
@@ -145,26 +217,44 @@ func (q *Query) clone(db *gorm.DB) *Query {
 		AuthToken:                q.AuthToken.clone(db),
 		AutoBackup:               q.AutoBackup.clone(db),
 		BanIP:                    q.BanIP.clone(db),
+		CTLogEntry:               q.CTLogEntry.clone(db),
+		CTMonitorTarget:          q.CTMonitorTarget.clone(db),
 		Cert:                     q.Cert.clone(db),
+		CertAuthority:            q.CertAuthority.clone(db),
+		CertIssuance:             q.CertIssuance.clone(db),
+		CertRevocation:           q.CertRevocation.clone(db),
+		CertSerial:               q.CertSerial.clone(db),
+		ChangeSet:                q.ChangeSet.clone(db),
 		Config:                   q.Config.clone(db),
 		ConfigBackup:             q.ConfigBackup.clone(db),
 		DnsCredential:            q.DnsCredential.clone(db),
 		DnsDomain:                q.DnsDomain.clone(db),
 		ExternalNotify:           q.ExternalNotify.clone(db),
+		Jail:                     q.Jail.clone(db),
+		JailBan:                  q.JailBan.clone(db),
 		LLMSession:               q.LLMSession.clone(db),
 		MCPServiceToken:          q.MCPServiceToken.clone(db),
 		Namespace:                q.Namespace.clone(db),
 		NginxLogIndex:            q.NginxLogIndex.clone(db),
+		NginxLogRollup:           q.NginxLogRollup.clone(db),
+		NginxLogSavedSearch:      q.NginxLogSavedSearch.clone(db),
 		Node:                     q.Node.clone(db),
 		NodeControllerCredential: q.NodeControllerCredential.clone(db),
 		NodeCredential:           q.NodeCredential.clone(db),
 		Notification:             q.Notification.clone(db),
 		Passkey:                  q.Passkey.clone(db),
+		Role:                     q.Role.clone(db),
 		Site:                     q.Site.clone(db),
 		SiteConfig:               q.SiteConfig.clone(db),
+		SiteDeployment:           q.SiteDeployment.clone(db),
 		SiteHealthAlertState:     q.SiteHealthAlertState.clone(db),
 		Stream:                   q.Stream.clone(db),
+		TrafficAlertRule:         q.TrafficAlertRule.clone(db),
+		TrafficAlertState:        q.TrafficAlertState.clone(db),
 		UpstreamConfig:           q.UpstreamConfig.clone(db),
+		UpstreamHealthCheck:      q.UpstreamHealthCheck.clone(db),
+		UpstreamHealthEvent:      q.UpstreamHealthEvent.clone(db),
+		UpstreamServerHealth:     q.UpstreamServerHealth.clone(db),
 		User:                     q.User.clone(db),
 	}
 }
@@ -184,26 +274,44 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 		AuthToken:                q.AuthToken.replaceDB(db),
 		AutoBackup:               q.AutoBackup.replaceDB(db),
 		BanIP:                    q.BanIP.replaceDB(db),
+		CTLogEntry:               q.CTLogEntry.replaceDB(db),
+		CTMonitorTarget:          q.CTMonitorTarget.replaceDB(db),
 		Cert:                     q.Cert.replaceDB(db),
+		CertAuthority:            q.CertAuthority.replaceDB(db),
+		CertIssuance:             q.CertIssuance.replaceDB(db),
+		CertRevocation:           q.CertRevocation.replaceDB(db),
+		CertSerial:               q.CertSerial.replaceDB(db),
+		ChangeSet:                q.ChangeSet.replaceDB(db),
 		Config:                   q.Config.replaceDB(db),
 		ConfigBackup:             q.ConfigBackup.replaceDB(db),
 		DnsCredential:            q.DnsCredential.replaceDB(db),
 		DnsDomain:                q.DnsDomain.replaceDB(db),
 		ExternalNotify:           q.ExternalNotify.replaceDB(db),
+		Jail:                     q.Jail.replaceDB(db),
+		JailBan:                  q.JailBan.replaceDB(db),
 		LLMSession:               q.LLMSession.replaceDB(db),
 		MCPServiceToken:          q.MCPServiceToken.replaceDB(db),
 		Namespace:                q.Namespace.replaceDB(db),
 		NginxLogIndex:            q.NginxLogIndex.replaceDB(db),
+		NginxLogRollup:           q.NginxLogRollup.replaceDB(db),
+		NginxLogSavedSearch:      q.NginxLogSavedSearch.replaceDB(db),
 		Node:                     q.Node.replaceDB(db),
 		NodeControllerCredential: q.NodeControllerCredential.replaceDB(db),
 		NodeCredential:           q.NodeCredential.replaceDB(db),
 		Notification:             q.Notification.replaceDB(db),
 		Passkey:                  q.Passkey.replaceDB(db),
+		Role:                     q.Role.replaceDB(db),
 		Site:                     q.Site.replaceDB(db),
 		SiteConfig:               q.SiteConfig.replaceDB(db),
+		SiteDeployment:           q.SiteDeployment.replaceDB(db),
 		SiteHealthAlertState:     q.SiteHealthAlertState.replaceDB(db),
 		Stream:                   q.Stream.replaceDB(db),
+		TrafficAlertRule:         q.TrafficAlertRule.replaceDB(db),
+		TrafficAlertState:        q.TrafficAlertState.replaceDB(db),
 		UpstreamConfig:           q.UpstreamConfig.replaceDB(db),
+		UpstreamHealthCheck:      q.UpstreamHealthCheck.replaceDB(db),
+		UpstreamHealthEvent:      q.UpstreamHealthEvent.replaceDB(db),
+		UpstreamServerHealth:     q.UpstreamServerHealth.replaceDB(db),
 		User:                     q.User.replaceDB(db),
 	}
 }
@@ -213,26 +321,44 @@ type queryCtx struct {
 	AuthToken                *authTokenDo
 	AutoBackup               *autoBackupDo
 	BanIP                    *banIPDo
+	CTLogEntry               *cTLogEntryDo
+	CTMonitorTarget          *cTMonitorTargetDo
 	Cert                     *certDo
+	CertAuthority            *certAuthorityDo
+	CertIssuance             *certIssuanceDo
+	CertRevocation           *certRevocationDo
+	CertSerial               *certSerialDo
+	ChangeSet                *changeSetDo
 	Config                   *configDo
 	ConfigBackup             *configBackupDo
 	DnsCredential            *dnsCredentialDo
 	DnsDomain                *dnsDomainDo
 	ExternalNotify           *externalNotifyDo
+	Jail                     *jailDo
+	JailBan                  *jailBanDo
 	LLMSession               *lLMSessionDo
 	MCPServiceToken          *mCPServiceTokenDo
 	Namespace                *namespaceDo
 	NginxLogIndex            *nginxLogIndexDo
+	NginxLogRollup           *nginxLogRollupDo
+	NginxLogSavedSearch      *nginxLogSavedSearchDo
 	Node                     *nodeDo
 	NodeControllerCredential *nodeControllerCredentialDo
 	NodeCredential           *nodeCredentialDo
 	Notification             *notificationDo
 	Passkey                  *passkeyDo
+	Role                     *roleDo
 	Site                     *siteDo
 	SiteConfig               *siteConfigDo
+	SiteDeployment           *siteDeploymentDo
 	SiteHealthAlertState     *siteHealthAlertStateDo
 	Stream                   *streamDo
+	TrafficAlertRule         *trafficAlertRuleDo
+	TrafficAlertState        *trafficAlertStateDo
 	UpstreamConfig           *upstreamConfigDo
+	UpstreamHealthCheck      *upstreamHealthCheckDo
+	UpstreamHealthEvent      *upstreamHealthEventDo
+	UpstreamServerHealth     *upstreamServerHealthDo
 	User                     *userDo
 }
 
@@ -242,26 +368,44 @@ func (q *Query) WithContext(ctx context.Context) *queryCtx {
 		AuthToken:                q.AuthToken.WithContext(ctx),
 		AutoBackup:               q.AutoBackup.WithContext(ctx),
 		BanIP:                    q.BanIP.WithContext(ctx),
+		CTLogEntry:               q.CTLogEntry.WithContext(ctx),
+		CTMonitorTarget:          q.CTMonitorTarget.WithContext(ctx),
 		Cert:                     q.Cert.WithContext(ctx),
+		CertAuthority:            q.CertAuthority.WithContext(ctx),
+		CertIssuance:             q.CertIssuance.WithContext(ctx),
+		CertRevocation:           q.CertRevocation.WithContext(ctx),
+		CertSerial:               q.CertSerial.WithContext(ctx),
+		ChangeSet:                q.ChangeSet.WithContext(ctx),
 		Config:                   q.Config.WithContext(ctx),
 		ConfigBackup:             q.ConfigBackup.WithContext(ctx),
 		DnsCredential:            q.DnsCredential.WithContext(ctx),
 		DnsDomain:                q.DnsDomain.WithContext(ctx),
 		ExternalNotify:           q.ExternalNotify.WithContext(ctx),
+		Jail:                     q.Jail.WithContext(ctx),
+		JailBan:                  q.JailBan.WithContext(ctx),
 		LLMSession:               q.LLMSession.WithContext(ctx),
 		MCPServiceToken:          q.MCPServiceToken.WithContext(ctx),
 		Namespace:                q.Namespace.WithContext(ctx),
 		NginxLogIndex:            q.NginxLogIndex.WithContext(ctx),
+		NginxLogRollup:           q.NginxLogRollup.WithContext(ctx),
+		NginxLogSavedSearch:      q.NginxLogSavedSearch.WithContext(ctx),
 		Node:                     q.Node.WithContext(ctx),
 		NodeControllerCredential: q.NodeControllerCredential.WithContext(ctx),
 		NodeCredential:           q.NodeCredential.WithContext(ctx),
 		Notification:             q.Notification.WithContext(ctx),
 		Passkey:                  q.Passkey.WithContext(ctx),
+		Role:                     q.Role.WithContext(ctx),
 		Site:                     q.Site.WithContext(ctx),
 		SiteConfig:               q.SiteConfig.WithContext(ctx),
+		SiteDeployment:           q.SiteDeployment.WithContext(ctx),
 		SiteHealthAlertState:     q.SiteHealthAlertState.WithContext(ctx),
 		Stream:                   q.Stream.WithContext(ctx),
+		TrafficAlertRule:         q.TrafficAlertRule.WithContext(ctx),
+		TrafficAlertState:        q.TrafficAlertState.WithContext(ctx),
 		UpstreamConfig:           q.UpstreamConfig.WithContext(ctx),
+		UpstreamHealthCheck:      q.UpstreamHealthCheck.WithContext(ctx),
+		UpstreamHealthEvent:      q.UpstreamHealthEvent.WithContext(ctx),
+		UpstreamServerHealth:     q.UpstreamServerHealth.WithContext(ctx),
 		User:                     q.User.WithContext(ctx),
 	}
 }
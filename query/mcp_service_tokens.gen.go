@@ -40,6 +40,7 @@ func newMCPServiceToken(db *gorm.DB, opts ...gen.DOOption) mCPServiceToken {
 	_mCPServiceToken.RevokedAt = field.NewTime(tableName, "revoked_at")
 	_mCPServiceToken.CreatorID = field.NewUint64(tableName, "creator_id")
 	_mCPServiceToken.LastUsedAt = field.NewTime(tableName, "last_used_at")
+	_mCPServiceToken.NamespaceIDs = field.NewField(tableName, "namespace_ids")
 
 	_mCPServiceToken.fillFieldMap()
 
@@ -49,19 +50,20 @@ func newMCPServiceToken(db *gorm.DB, opts ...gen.DOOption) mCPServiceToken {
 type mCPServiceToken struct {
 	mCPServiceTokenDo
 
-	ALL        field.Asterisk
-	ID         field.Uint64
-	CreatedAt  field.Time
-	UpdatedAt  field.Time
-	DeletedAt  field.Field
-	PublicID   field.String
-	Name       field.String
-	Verifier   field.Bytes
-	Scopes_    field.Field
-	ExpiresAt  field.Time
-	RevokedAt  field.Time
-	CreatorID  field.Uint64
-	LastUsedAt field.Time
+	ALL          field.Asterisk
+	ID           field.Uint64
+	CreatedAt    field.Time
+	UpdatedAt    field.Time
+	DeletedAt    field.Field
+	PublicID     field.String
+	Name         field.String
+	Verifier     field.Bytes
+	Scopes_      field.Field
+	ExpiresAt    field.Time
+	RevokedAt    field.Time
+	CreatorID    field.Uint64
+	LastUsedAt   field.Time
+	NamespaceIDs field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -90,6 +92,7 @@ func (m *mCPServiceToken) updateTableName(table string) *mCPServiceToken {
 	m.RevokedAt = field.NewTime(table, "revoked_at")
 	m.CreatorID = field.NewUint64(table, "creator_id")
 	m.LastUsedAt = field.NewTime(table, "last_used_at")
+	m.NamespaceIDs = field.NewField(table, "namespace_ids")
 
 	m.fillFieldMap()
 
@@ -106,7 +109,7 @@ func (m *mCPServiceToken) GetFieldByName(fieldName string) (field.OrderExpr, boo
 }
 
 func (m *mCPServiceToken) fillFieldMap() {
-	m.fieldMap = make(map[string]field.Expr, 12)
+	m.fieldMap = make(map[string]field.Expr, 13)
 	m.fieldMap["id"] = m.ID
 	m.fieldMap["created_at"] = m.CreatedAt
 	m.fieldMap["updated_at"] = m.UpdatedAt
@@ -119,6 +122,7 @@ func (m *mCPServiceToken) fillFieldMap() {
 	m.fieldMap["revoked_at"] = m.RevokedAt
 	m.fieldMap["creator_id"] = m.CreatorID
 	m.fieldMap["last_used_at"] = m.LastUsedAt
+	m.fieldMap["namespace_ids"] = m.NamespaceIDs
 }
 
 func (m mCPServiceToken) clone(db *gorm.DB) mCPServiceToken {
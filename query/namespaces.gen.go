@@ -40,6 +40,11 @@ func newNamespace(db *gorm.DB, opts ...gen.DOOption) namespace {
 	_namespace.DeployMode = field.NewString(tableName, "deploy_mode")
 	_namespace.SyncStrategy = field.NewString(tableName, "sync_strategy")
 	_namespace.SyncIntervalMinutes = field.NewInt(tableName, "sync_interval_minutes")
+	_namespace.DNSProvisioning = field.NewString(tableName, "dns_provisioning")
+	_namespace.DNSTargetIPv4 = field.NewString(tableName, "dns_target_ipv4")
+	_namespace.DNSTargetIPv6 = field.NewString(tableName, "dns_target_ipv6")
+	_namespace.DNSTargetCNAME = field.NewString(tableName, "dns_target_cname")
+	_namespace.DNSDeleteWithSite = field.NewBool(tableName, "dns_delete_with_site")
 
 	_namespace.fillFieldMap()
 
@@ -62,6 +67,11 @@ type namespace struct {
 	DeployMode          field.String
 	SyncStrategy        field.String
 	SyncIntervalMinutes field.Int
+	DNSProvisioning     field.String
+	DNSTargetIPv4       field.String
+	DNSTargetIPv6       field.String
+	DNSTargetCNAME      field.String
+	DNSDeleteWithSite   field.Bool
 
 	fieldMap map[string]field.Expr
 }
@@ -90,6 +100,11 @@ func (n *namespace) updateTableName(table string) *namespace {
 	n.DeployMode = field.NewString(table, "deploy_mode")
 	n.SyncStrategy = field.NewString(table, "sync_strategy")
 	n.SyncIntervalMinutes = field.NewInt(table, "sync_interval_minutes")
+	n.DNSProvisioning = field.NewString(table, "dns_provisioning")
+	n.DNSTargetIPv4 = field.NewString(table, "dns_target_ipv4")
+	n.DNSTargetIPv6 = field.NewString(table, "dns_target_ipv6")
+	n.DNSTargetCNAME = field.NewString(table, "dns_target_cname")
+	n.DNSDeleteWithSite = field.NewBool(table, "dns_delete_with_site")
 
 	n.fillFieldMap()
 
@@ -106,7 +121,7 @@ func (n *namespace) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (n *namespace) fillFieldMap() {
-	n.fieldMap = make(map[string]field.Expr, 12)
+	n.fieldMap = make(map[string]field.Expr, 17)
 	n.fieldMap["id"] = n.ID
 	n.fieldMap["created_at"] = n.CreatedAt
 	n.fieldMap["updated_at"] = n.UpdatedAt
@@ -119,6 +134,11 @@ func (n *namespace) fillFieldMap() {
 	n.fieldMap["deploy_mode"] = n.DeployMode
 	n.fieldMap["sync_strategy"] = n.SyncStrategy
 	n.fieldMap["sync_interval_minutes"] = n.SyncIntervalMinutes
+	n.fieldMap["dns_provisioning"] = n.DNSProvisioning
+	n.fieldMap["dns_target_ipv4"] = n.DNSTargetIPv4
+	n.fieldMap["dns_target_ipv6"] = n.DNSTargetIPv6
+	n.fieldMap["dns_target_cname"] = n.DNSTargetCNAME
+	n.fieldMap["dns_delete_with_site"] = n.DNSDeleteWithSite
 }
 
 func (n namespace) clone(db *gorm.DB) namespace {
@@ -43,6 +43,8 @@ func newNginxLogIndex(db *gorm.DB, opts ...gen.DOOption) nginxLogIndex {
 	_nginxLogIndex.TimeRangeEnd = field.NewTime(tableName, "time_range_end")
 	_nginxLogIndex.DocumentCount = field.NewUint64(tableName, "document_count")
 	_nginxLogIndex.Enabled = field.NewBool(tableName, "enabled")
+	_nginxLogIndex.RetentionDays = field.NewInt(tableName, "retention_days")
+	_nginxLogIndex.PurgedBefore = field.NewTime(tableName, "purged_before")
 	_nginxLogIndex.IndexStatus = field.NewString(tableName, "index_status")
 	_nginxLogIndex.ErrorMessage = field.NewString(tableName, "error_message")
 	_nginxLogIndex.ErrorTime = field.NewTime(tableName, "error_time")
@@ -73,6 +75,8 @@ type nginxLogIndex struct {
 	TimeRangeEnd   field.Time
 	DocumentCount  field.Uint64
 	Enabled        field.Bool
+	RetentionDays  field.Int
+	PurgedBefore   field.Time
 	IndexStatus    field.String
 	ErrorMessage   field.String
 	ErrorTime      field.Time
@@ -109,6 +113,8 @@ func (n *nginxLogIndex) updateTableName(table string) *nginxLogIndex {
 	n.TimeRangeEnd = field.NewTime(table, "time_range_end")
 	n.DocumentCount = field.NewUint64(table, "document_count")
 	n.Enabled = field.NewBool(table, "enabled")
+	n.RetentionDays = field.NewInt(table, "retention_days")
+	n.PurgedBefore = field.NewTime(table, "purged_before")
 	n.IndexStatus = field.NewString(table, "index_status")
 	n.ErrorMessage = field.NewString(table, "error_message")
 	n.ErrorTime = field.NewTime(table, "error_time")
@@ -130,7 +136,7 @@ func (n *nginxLogIndex) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (n *nginxLogIndex) fillFieldMap() {
-	n.fieldMap = make(map[string]field.Expr, 20)
+	n.fieldMap = make(map[string]field.Expr, 22)
 	n.fieldMap["id"] = n.ID
 	n.fieldMap["created_at"] = n.CreatedAt
 	n.fieldMap["updated_at"] = n.UpdatedAt
@@ -146,6 +152,8 @@ func (n *nginxLogIndex) fillFieldMap() {
 	n.fieldMap["time_range_end"] = n.TimeRangeEnd
 	n.fieldMap["document_count"] = n.DocumentCount
 	n.fieldMap["enabled"] = n.Enabled
+	n.fieldMap["retention_days"] = n.RetentionDays
+	n.fieldMap["purged_before"] = n.PurgedBefore
 	n.fieldMap["index_status"] = n.IndexStatus
 	n.fieldMap["error_message"] = n.ErrorMessage
 	n.fieldMap["error_time"] = n.ErrorTime
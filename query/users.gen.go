@@ -38,6 +38,8 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.OTPSecret = field.NewBytes(tableName, "otp_secret")
 	_user.RecoveryCodes = field.NewField(tableName, "recovery_codes")
 	_user.Language = field.NewString(tableName, "language")
+	_user.Role = field.NewString(tableName, "role")
+	_user.NamespaceIDs = field.NewField(tableName, "namespace_ids")
 
 	_user.fillFieldMap()
 
@@ -58,6 +60,8 @@ type user struct {
 	OTPSecret     field.Bytes
 	RecoveryCodes field.Field
 	Language      field.String
+	Role          field.String
+	NamespaceIDs  field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -84,6 +88,8 @@ func (u *user) updateTableName(table string) *user {
 	u.OTPSecret = field.NewBytes(table, "otp_secret")
 	u.RecoveryCodes = field.NewField(table, "recovery_codes")
 	u.Language = field.NewString(table, "language")
+	u.Role = field.NewString(table, "role")
+	u.NamespaceIDs = field.NewField(table, "namespace_ids")
 
 	u.fillFieldMap()
 
@@ -100,7 +106,7 @@ func (u *user) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *user) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 10)
+	u.fieldMap = make(map[string]field.Expr, 12)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
@@ -111,6 +117,8 @@ func (u *user) fillFieldMap() {
 	u.fieldMap["otp_secret"] = u.OTPSecret
 	u.fieldMap["recovery_codes"] = u.RecoveryCodes
 	u.fieldMap["language"] = u.Language
+	u.fieldMap["role"] = u.Role
+	u.fieldMap["namespace_ids"] = u.NamespaceIDs
 }
 
 func (u user) clone(db *gorm.DB) user {
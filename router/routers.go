@@ -7,6 +7,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/api/audit"
 	"github.com/0xJacky/Nginx-UI/api/backup"
 	"github.com/0xJacky/Nginx-UI/api/certificate"
+	"github.com/0xJacky/Nginx-UI/api/change_set"
 	"github.com/0xJacky/Nginx-UI/api/cluster"
 	"github.com/0xJacky/Nginx-UI/api/config"
 	"github.com/0xJacky/Nginx-UI/api/crypto"
@@ -14,8 +15,11 @@ import (
 	"github.com/0xJacky/Nginx-UI/api/event"
 	"github.com/0xJacky/Nginx-UI/api/external_notify"
 	"github.com/0xJacky/Nginx-UI/api/geolite"
+	"github.com/0xJacky/Nginx-UI/api/jails"
 	"github.com/0xJacky/Nginx-UI/api/license"
 	"github.com/0xJacky/Nginx-UI/api/llm"
+	"github.com/0xJacky/Nginx-UI/api/manifest"
+	"github.com/0xJacky/Nginx-UI/api/metrics"
 	"github.com/0xJacky/Nginx-UI/api/nginx"
 	nginxLog "github.com/0xJacky/Nginx-UI/api/nginx_log"
 	"github.com/0xJacky/Nginx-UI/api/notification"
@@ -30,6 +34,7 @@ import (
 	"github.com/0xJacky/Nginx-UI/api/upstream"
 	"github.com/0xJacky/Nginx-UI/api/user"
 	"github.com/0xJacky/Nginx-UI/internal/middleware"
+	"github.com/0xJacky/Nginx-UI/internal/rbac"
 	"github.com/0xJacky/Nginx-UI/mcp"
 	"github.com/gin-gonic/gin"
 	"github.com/uozi-tech/cosy"
@@ -60,10 +65,19 @@ func InitRouter() {
 
 	mcp.InitRouter(r)
 
+	// The scrape target lives outside /api at the path Prometheus expects. It
+	// is served by this node only and never proxied.
+	metrics.InitRouter(r.Group("",
+		middleware.IPWhiteList(),
+		middleware.AuthRequired(),
+		middleware.RequirePermission(rbac.ResourceMetrics),
+	))
+
 	root := r.Group("/api", middleware.IPWhiteList())
 	{
 		public.InitRouter(root)
 		crypto.InitPublicRouter(root)
+		dnsapi.InitPublicRouter(root)
 		user.InitAuthRouter(root)
 		license.InitRouter(root)
 
@@ -81,55 +95,87 @@ func InitRouter() {
 		// browser, even while the UI is managing a selected remote node.
 		local := root.Group("/", middleware.AuthRequired())
 		{
-			llm.InitLocalRouter(local)
+			llm.InitLocalRouter(local.Group("", middleware.RequirePermission(rbac.ResourceLLM)))
 			user.InitTokenRouter(local)
 			user.InitUserRouter(local)
+			user.InitPermissionRouter(local)
 		}
 
-		// Authorization required and not websocket request
-		g := root.Group("/", middleware.AuthRequired(), middleware.Proxy())
+		// Authorization required and not websocket request. Permissions are
+		// checked before Proxy so a remote node is only reached when allowed.
+		g := root.Group("/", middleware.AuthRequired())
 		{
-			debug.InitRouter(g)
-			analytic.InitRouter(g)
-			user.InitManageUserRouter(g)
-			nginx.InitRouter(g)
-			sites.InitRouter(g)
-			streams.InitRouter(g)
-			config.InitRouter(g)
-			template.InitRouter(g)
-			certificate.InitCertificateRouter(g)
-			certificate.InitDNSCredentialRouter(g)
-			certificate.InitAcmeUserRouter(g)
-			dnsapi.InitRouter(g)
-			system.InitPrivateRouter(g)
-			settings.InitRouter(g)
-			llm.InitRouter(g)
-			mcp.InitManagementRouter(g)
-			cluster.InitRouter(g)
-			notification.InitRouter(g)
-			external_notify.InitRouter(g)
-			backup.InitAutoBackupRouter(g)
-			nginxLog.InitRouter(g)
-			upstream.InitHTTPRouter(g)
-			g.GET("/geolite/status", geolite.GetStatus)
+			// Packages whose routes need more than the permission of their
+			// group take the guarded group and add Proxy themselves.
+			guarded := func(resource rbac.Resource) *gin.RouterGroup {
+				return g.Group("", middleware.RequirePermission(resource))
+			}
+			proxied := func(resource rbac.Resource) *gin.RouterGroup {
+				return guarded(resource).Group("", middleware.Proxy())
+			}
+			debug.InitRouter(proxied(rbac.ResourceSystem))
+			analytic.InitRouter(proxied(rbac.ResourceAnalytic))
+			user.InitManageUserRouter(proxied(rbac.ResourceUsers))
+			user.InitRoleRouter(proxied(rbac.ResourceUsers))
+			nginx.InitRouter(proxied(rbac.ResourceNginx))
+			sites.InitRouter(guarded(rbac.ResourceSites))
+			streams.InitRouter(proxied(rbac.ResourceStreams))
+			// Raw config paths cannot be mapped onto namespaces, scoped
+			// subjects manage their content through sites and streams.
+			config.InitRouter(g.Group("",
+				middleware.RequireUnscopedSubject(),
+				middleware.RequirePermission(rbac.ResourceConfigs),
+				middleware.Proxy(),
+			))
+			change_set.InitRouter(proxied(rbac.ResourceChanges))
+			// A manifest spans namespaces, sites, streams, certificates and
+			// upstreams, so every one of those permissions is required.
+			manifest.InitRouter(g.Group("",
+				middleware.RequireUnscopedSubject(),
+				middleware.RequirePermission(rbac.ResourceCluster),
+				middleware.RequirePermission(rbac.ResourceSites),
+				middleware.RequirePermission(rbac.ResourceStreams),
+				middleware.RequirePermission(rbac.ResourceCertificates),
+				middleware.RequirePermission(rbac.ResourceUpstream),
+				middleware.Proxy(),
+			))
+			template.InitRouter(proxied(rbac.ResourceTemplates))
+			certificate.InitCertificateRouter(guarded(rbac.ResourceCertificates))
+			certificate.InitDNSCredentialRouter(proxied(rbac.ResourceCertificates))
+			certificate.InitAcmeUserRouter(proxied(rbac.ResourceCertificates))
+			certificate.InitCertAuthorityRouter(proxied(rbac.ResourceCertificates))
+			dnsapi.InitRouter(proxied(rbac.ResourceDNS))
+			system.InitPrivateRouter(proxied(rbac.ResourceSystem))
+			settings.InitRouter(proxied(rbac.ResourceSettings))
+			llm.InitRouter(proxied(rbac.ResourceLLM))
+			// Any user may mint service tokens; scopes are capped by their own role.
+			mcp.InitManagementRouter(g.Group("", middleware.Proxy()))
+			cluster.InitRouter(guarded(rbac.ResourceCluster))
+			notification.InitRouter(proxied(rbac.ResourceNotifications))
+			external_notify.InitRouter(proxied(rbac.ResourceNotifications))
+			backup.InitAutoBackupRouter(proxied(rbac.ResourceBackup))
+			nginxLog.InitRouter(proxied(rbac.ResourceLogs))
+			jails.InitRouter(proxied(rbac.ResourceJails))
+			upstream.InitHTTPRouter(proxied(rbac.ResourceUpstream))
+			proxied(rbac.ResourceSystem).GET("/geolite/status", geolite.GetStatus)
 		}
 
 		// Authorization required and websocket request (no cookie fallback to prevent CSWSH)
-		w := root.Group("/", middleware.AuthRequiredWS(), middleware.ProxyWs())
+		w := root.Group("/", middleware.AuthRequiredWS())
 		{
-			analytic.InitWebSocketRouter(w)
-			certificate.InitCertificateWebSocketRouter(w)
-			event.InitRouter(w)
-			o := w.Group("", middleware.RequireInteractiveUser(), middleware.RequireSecureSession())
-			{
-				terminal.InitRouter(o)
+			proxiedWS := func(resource rbac.Resource) *gin.RouterGroup {
+				return w.Group("", middleware.RequirePermission(resource), middleware.ProxyWs())
 			}
-			nginxLog.InitWebSocketRouter(w)
-			upstream.InitWebSocketRouter(w)
-			system.InitWebSocketRouter(w)
-			nginx.InitWebSocketRouter(w)
-			cluster.InitWebSocketRouter(w)
-			w.GET("/geolite/download", geolite.DownloadGeoLiteDB)
+			analytic.InitWebSocketRouter(proxiedWS(rbac.ResourceAnalytic))
+			certificate.InitCertificateWebSocketRouter(proxiedWS(rbac.ResourceCertificates))
+			event.InitRouter(proxiedWS(rbac.ResourceNotifications))
+			terminal.InitRouter(w.Group("", middleware.RequirePermission(rbac.ResourceTerminal)))
+			nginxLog.InitWebSocketRouter(proxiedWS(rbac.ResourceLogs))
+			upstream.InitWebSocketRouter(proxiedWS(rbac.ResourceUpstream))
+			system.InitWebSocketRouter(proxiedWS(rbac.ResourceSystem))
+			nginx.InitWebSocketRouter(proxiedWS(rbac.ResourceNginx))
+			cluster.InitWebSocketRouter(proxiedWS(rbac.ResourceCluster))
+			proxiedWS(rbac.ResourceSystem).GET("/geolite/download", geolite.DownloadGeoLiteDB)
 		}
 	}
 }
@@ -8,6 +8,14 @@ type Cert struct {
 	RenewalInterval      int      `json:"renewal_interval" binding:"min=1,max=90"`
 	RecursiveNameservers []string `json:"recursive_nameservers" binding:"omitempty,dive,hostname_port"`
 	HTTPChallengePort    string   `json:"http_challenge_port"`
+	// CTLogSource is the http(s) URL of a crt.sh-compatible API.
+	CTLogSource string `json:"ct_log_source" binding:"omitempty,http_url"`
+	// CTMonitorInterval is the number of hours between two CT log checks,
+	// zero turns the monitor off.
+	CTMonitorInterval int `json:"ct_monitor_interval" binding:"min=0,max=168"`
+	// DNSPropagationCheck makes DNS-01 issuance wait until every
+	// authoritative nameserver serves the challenge record.
+	DNSPropagationCheck bool `json:"dns_propagation_check"`
 }
 
 var CertSettings = &Cert{
@@ -16,6 +24,8 @@ var CertSettings = &Cert{
 	RenewalInterval:      30,
 	RecursiveNameservers: []string{},
 	HTTPChallengePort:    "9180",
+	CTLogSource:          "https://crt.sh",
+	CTMonitorInterval:    6,
 }
 
 func (s *Cert) GetCADir() string {
@@ -0,0 +1,27 @@
+package settings
+
+import "time"
+
+const defaultChangeSetExpireHours = 72
+
+type ChangeSet struct {
+	// RequireApproval turns off direct saves of sites, streams and configs;
+	// edits must then go through a change set approved by a second user.
+	// Upstream health checks still mark failing servers down on their own,
+	// since a failover cannot wait for a review.
+	RequireApproval bool `json:"require_approval"`
+	ExpireHours     int  `json:"expire_hours" binding:"omitempty,min=1"`
+}
+
+var ChangeSetSettings = &ChangeSet{
+	ExpireHours: defaultChangeSetExpireHours,
+}
+
+// GetExpiry returns how long a change set waits for review before it expires.
+func (s *ChangeSet) GetExpiry() time.Duration {
+	hours := s.ExpireHours
+	if hours < 1 {
+		hours = defaultChangeSetExpireHours
+	}
+	return time.Duration(hours) * time.Hour
+}
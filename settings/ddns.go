@@ -0,0 +1,10 @@
+package settings
+
+// DDNS lists the command and URL address sources DDNS may use. Both run as
+// the Nginx UI process, so they can only be allowed from the settings file.
+type DDNS struct {
+	AllowedCommands []string `json:"allowed_commands" ini:",,allowshadow" protected:"true"`
+	AllowedURLs     []string `json:"allowed_urls" ini:",,allowshadow" protected:"true"`
+}
+
+var DDNSSettings = &DDNS{}
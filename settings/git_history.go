@@ -0,0 +1,13 @@
+package settings
+
+type GitHistory struct {
+	// Enabled commits every configuration save to a git repository so
+	// changes can be diffed and the whole tree rolled back.
+	Enabled bool `json:"enabled"`
+	// RepositoryPath is the git directory. It is kept outside the nginx
+	// configuration directory and defaults to config-history.git next to
+	// app.ini.
+	RepositoryPath string `json:"repository_path" protected:"true"`
+}
+
+var GitHistorySettings = &GitHistory{}
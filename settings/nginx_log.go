@@ -8,6 +8,11 @@ type NginxLog struct {
 	// IncrementalIndexInterval controls how often the incremental indexing job runs, in minutes.
 	// When set to 0 or a negative value, a conservative default will be used.
 	IncrementalIndexInterval int `json:"incremental_index_interval"`
+	// HourlyRollupRetention and DailyRollupRetention control how many days the
+	// hourly and daily traffic rollups are kept. When set to 0 or a negative
+	// value, the defaults are used.
+	HourlyRollupRetention int `json:"hourly_rollup_retention"`
+	DailyRollupRetention  int `json:"daily_rollup_retention"`
 }
 
 var NginxLogSettings = &NginxLog{}
@@ -20,3 +25,21 @@ func (n *NginxLog) GetIncrementalIndexInterval() time.Duration {
 	}
 	return time.Duration(n.IncrementalIndexInterval) * time.Minute
 }
+
+// GetHourlyRollupRetention returns how long hourly traffic rollups are kept.
+// Defaults to 90 days when not configured or configured with an invalid value.
+func (n *NginxLog) GetHourlyRollupRetention() time.Duration {
+	if n == nil || n.HourlyRollupRetention <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return time.Duration(n.HourlyRollupRetention) * 24 * time.Hour
+}
+
+// GetDailyRollupRetention returns how long daily traffic rollups are kept.
+// Defaults to 730 days when not configured or configured with an invalid value.
+func (n *NginxLog) GetDailyRollupRetention() time.Duration {
+	if n == nil || n.DailyRollupRetention <= 0 {
+		return 730 * 24 * time.Hour
+	}
+	return time.Duration(n.DailyRollupRetention) * 24 * time.Hour
+}
@@ -33,8 +33,11 @@ var envPrefixMap = map[string]interface{}{
 	"AUTH":           AuthSettings,
 	"CASDOOR":        CasdoorSettings,
 	"CERT":           CertSettings,
+	"CHANGE_SET":     ChangeSetSettings,
 	"CLUSTER":        ClusterSettings,
 	"CRYPTO":         CryptoSettings,
+	"DDNS":           DDNSSettings,
+	"GIT_HISTORY":    GitHistorySettings,
 	"HTTP":           HTTPSettings,
 	"LOGROTATE":      LogrotateSettings,
 	"NGINX":          NginxSettings,
@@ -59,8 +62,11 @@ func init() {
 	sections.Set("casdoor", CasdoorSettings)
 	sections.Set("oidc", OIDCSettings)
 	sections.Set("cert", CertSettings)
+	sections.Set("change_set", ChangeSetSettings)
 	sections.Set("cluster", ClusterSettings)
 	sections.Set("crypto", CryptoSettings)
+	sections.Set("ddns", DDNSSettings)
+	sections.Set("git_history", GitHistorySettings)
 	sections.Set("http", HTTPSettings)
 	sections.Set("logrotate", LogrotateSettings)
 	sections.Set("nginx", NginxSettings)